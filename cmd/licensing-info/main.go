@@ -10,6 +10,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"time"
 
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -35,17 +36,58 @@ import (
 //    "enterprise_resource_units": "1"
 //  }
 //
+// The consumption recorded by the operator over a period of time, for example a billing period, can be reported with the
+// from and to flags. Dates are given in the RFC 3339 or YYYY-MM-DD formats, in UTC:
+//
+//  > go run cmd/licensing-info/main.go -operator-namespace <operator-namespace> -from 2026-09-01 -to 2026-10-01
+//  {
+//    "from": "2026-09-01T00:00:00Z",
+//    "to": "2026-10-01T00:00:00Z",
+//    "covered_duration": "720h0m0s",
+//    "peak": {"timestamp": "2026-09-14T09:12:00Z", "enterprise_resource_units": 2, "total_memory_bytes": 137438953472},
+//    "time_weighted_enterprise_resource_units": 1.4,
+//    "daily_peaks": {...},
+//    "usage": {"my-namespace": {"elasticsearch": {...}, "kibana": {...}}}
+//  }
+//
 
 func main() {
 	var operatorNamespace string
 	flag.StringVar(&operatorNamespace, "operator-namespace", "elastic-system", "indicates the namespace where the operator is deployed")
+	var from, to string
+	flag.StringVar(&from, "from", "", "start of the period to report the recorded consumption for, defaults to the start of the current month if to is set")
+	flag.StringVar(&to, "to", "", "end of the period to report the recorded consumption for, defaults to now if from is set")
 	flag.Parse()
-	licensingInfo, err := license.NewResourceReporter(newK8sClient(), operatorNamespace, nil).Get(context.Background())
-	if err != nil {
-		log.Fatal(err, "Failed to get licensing info")
+	reporter := license.NewResourceReporter(newK8sClient(), operatorNamespace, 0, nil)
+
+	var output any
+	if from == "" && to == "" {
+		licensingInfo, err := reporter.Get(context.Background())
+		if err != nil {
+			log.Fatal(err, "Failed to get licensing info")
+		}
+		output = licensingInfo
+	} else {
+		now := time.Now().UTC()
+		fromTime := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		toTime := now
+		if from != "" {
+			fromTime = parseTime(from)
+		}
+		if to != "" {
+			toTime = parseTime(to)
+		}
+		if !fromTime.Before(toTime) {
+			log.Fatalf("Invalid period: %s is not before %s", fromTime.Format(time.RFC3339), toTime.Format(time.RFC3339))
+		}
+		report, err := reporter.GetUsageReport(context.Background(), fromTime, toTime)
+		if err != nil {
+			log.Fatal(err, "Failed to get usage report")
+		}
+		output = report
 	}
 
-	bytes, err := json.Marshal(licensingInfo)
+	bytes, err := json.Marshal(output)
 	if err != nil {
 		log.Fatal(err, "Failed to marshal licensing info")
 	}
@@ -53,6 +95,17 @@ func main() {
 	fmt.Print(string(bytes))
 }
 
+// parseTime parses a time in the RFC 3339 or YYYY-MM-DD formats.
+func parseTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	log.Fatalf("Invalid time %q, expected RFC 3339 or YYYY-MM-DD format", value)
+	return time.Time{}
+}
+
 func newK8sClient() client.Client {
 	cfg, err := config.GetConfig()
 	if err != nil {
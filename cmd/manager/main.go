@@ -23,6 +23,8 @@ import (
 	"github.com/spf13/viper"
 	"go.elastic.co/apm/v2"
 	"go.uber.org/automaxprocs/maxprocs"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -56,6 +58,7 @@ import (
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1beta1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/maps/v1alpha1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
 	eprv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/packageregistry/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/stackconfigpolicy/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/agent"
@@ -67,6 +70,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/autoscaling"
 	esavalidation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/autoscaling/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat"
+	beatvalidation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/validation"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	commonesclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/esclient"
@@ -75,13 +79,17 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/password"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
 	controllerscheme "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/sharding"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing/apmclientgo"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	commonwebhook "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/admission"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/conversion"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch"
 	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/diagnostics"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/settings"
 	esvalidation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/enterprisesearch"
@@ -91,9 +99,11 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/logstash"
 	lsvalidation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/logstash/validation"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/maps"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/otel"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/packageregistry"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/remotecluster"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/stackconfigpolicy"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/storageversion"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/webhook"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/dev"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/dev/portforward"
@@ -239,11 +249,24 @@ func Command() *cobra.Command {
 		false, // Set to false for backward compatibility
 		"Restrict cross-namespace resource association through RBAC (eg. referencing Elasticsearch from Kibana)",
 	)
+	cmd.Flags().Bool(
+		operator.EnableConversionWebhookFlag,
+		false,
+		fmt.Sprintf(
+			"Enables the conversion webhook of the resources served in several API versions, and configures their CRDs to use it. Requires %s and %s to be true. When disabled, the CRDs are reset to the None conversion strategy if the operator is still allowed to update them.",
+			operator.EnableWebhookFlag, operator.ManageWebhookCertsFlag,
+		),
+	)
 	cmd.Flags().Bool(
 		operator.EnableLeaderElection,
 		true,
 		"Enable leader election. Enabling this will ensure there is only one active operator.",
 	)
+	cmd.Flags().Bool(
+		operator.EnableStorageVersionMigrationFlag,
+		false,
+		"Rewrites the stored objects of the operator resources in the storage version of their CRD, then prunes the previous versions from the CRD status.",
+	)
 	cmd.Flags().Bool(
 		operator.EnableTracingFlag,
 		false,
@@ -266,6 +289,21 @@ func Command() *cobra.Command {
 			operator.MaxConcurrentReconcilesFlag,
 		),
 	)
+	cmd.Flags().String(
+		operator.ImageCatalogFlag,
+		"",
+		"Path to a YAML file pinning Elastic Stack container images to digests, with per-namespace container registry and image pull secrets overrides. Versions missing from the catalog are rejected for the images it contains.",
+	)
+	cmd.Flags().StringSlice(
+		operator.LicenseExpiryWarningsFlag,
+		[]string{"720h", "168h", "24h"},
+		"Comma separated list of durations before the expiry of the license of an Elasticsearch cluster at which a warning event is emitted and the LicenseExpiring condition of the cluster is set. Set to an empty string to disable warnings.",
+	)
+	cmd.Flags().Int(
+		operator.LicensingLedgerRetentionFlag,
+		13,
+		"Number of months, including the current one, for which the monthly licensing ledgers are kept in the operator namespace. Older ledgers are deleted. Set to 0 to keep all the ledgers.",
+	)
 	cmd.Flags().String(
 		operator.IPFamilyFlag,
 		"",
@@ -333,6 +371,11 @@ func Command() *cobra.Command {
 		1*time.Hour,
 		"Interval between ECK telemetry data updates",
 	)
+	cmd.Flags().String(
+		operator.TelemetrySinksFlag,
+		"",
+		"Path to a YAML file configuring an Elasticsearch data stream and/or an OTLP/HTTP endpoint to export ECK telemetry data to, in addition to Kibana. Credentials are read from Secrets in the operator namespace. Sinks remain active when Kibana telemetry is disabled.",
+	)
 	cmd.Flags().Bool(
 		operator.UBIOnlyFlag,
 		false,
@@ -354,6 +397,15 @@ func Command() *cobra.Command {
 		"",
 		fmt.Sprintf("Kubernetes secret mounted into the path designated by %s to be used for webhook certificates", operator.WebhookCertDirFlag),
 	)
+	cmd.Flags().String(
+		operator.WebhookReferenceValidationFlag,
+		string(commonwebhook.ReferenceValidationWarn),
+		fmt.Sprintf(
+			"Validation at admission of the references to other resources: existence, version compatibility and, if %s is true, access. One of %s, %s or %s. Only used when enable-webhook is true.",
+			operator.EnforceRBACOnRefsFlag,
+			commonwebhook.ReferenceValidationDisabled, commonwebhook.ReferenceValidationWarn, commonwebhook.ReferenceValidationDeny,
+		),
+	)
 	cmd.Flags().String(
 		operator.WebhookNameFlag,
 		DefaultWebhookName,
@@ -364,6 +416,16 @@ func Command() *cobra.Command {
 		WebhookPort,
 		"Port is the port that the webhook server serves at.",
 	)
+	cmd.Flags().Int(
+		operator.ShardCountFlag,
+		0,
+		"Number of shards the resources are partitioned into to be reconciled by several operator replicas. Cluster-wide tasks remain on the elected replica. 0 disables sharding.",
+	)
+	cmd.Flags().String(
+		operator.ShardKeyFlag,
+		string(sharding.NamespaceKey),
+		fmt.Sprintf("How resources are assigned to shards when %s is greater than 0: %s or %s.", operator.ShardCountFlag, sharding.NamespaceKey, sharding.ResourceKey),
+	)
 	cmd.Flags().String(
 		operator.SetDefaultSecurityContextFlag,
 		"auto-detect",
@@ -543,6 +605,17 @@ func startOperator(ctx context.Context) error {
 		version.GlobalMinStackVersion = version.From(7, 10, 0)
 	}
 
+	// pin container images to digests and override registries per namespace if requested
+	if imageCatalogFile := viper.GetString(operator.ImageCatalogFlag); imageCatalogFile != "" {
+		imageCatalog, err := container.LoadImageCatalog(imageCatalogFile)
+		if err != nil {
+			log.Error(err, "Failed to load image catalog", "file", imageCatalogFile)
+			return err
+		}
+		log.Info("Setting image catalog", "file", imageCatalogFile, "images", len(imageCatalog.Images), "namespaces", len(imageCatalog.Namespaces))
+		container.SetImageCatalog(imageCatalog)
+	}
+
 	// Get a config to talk to the apiserver
 	cfg, err := ctrl.GetConfig()
 	if err != nil {
@@ -611,6 +684,11 @@ func startOperator(ctx context.Context) error {
 	for _, ns := range managedNamespaces {
 		opts.Cache.DefaultNamespaces[ns] = cache.Config{}
 	}
+	// Events are only read when collecting diagnostics, do not cache them
+	if opts.Client.Cache == nil {
+		opts.Client.Cache = &client.CacheOptions{}
+	}
+	opts.Client.Cache.DisableFor = append(opts.Client.Cache.DisableFor, &corev1.Event{})
 
 	// only expose prometheus metrics if provided a non-zero port
 	metricsPort := viper.GetInt(operator.MetricsPortFlag)
@@ -680,6 +758,12 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	crdClientset, err := apiextensionsclient.NewForConfig(cfg)
+	if err != nil {
+		log.Error(err, "Failed to create Kubernetes API extensions client")
+		return err
+	}
+
 	distributionChannel := viper.GetString(operator.DistributionChannelFlag)
 	operatorInfo, err := about.GetOperatorInfo(clientset, operatorNamespace, distributionChannel)
 	if err != nil {
@@ -695,6 +779,12 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	licenseExpiryWarnings, err := license.ParseExpiryWarnings(viper.GetStringSlice(operator.LicenseExpiryWarningsFlag))
+	if err != nil {
+		log.Error(err, "Failed to parse license expiry warnings")
+		return err
+	}
+
 	setDefaultSecurityContext, err := determineSetDefaultSecurityContext(viper.GetString(operator.SetDefaultSecurityContextFlag), clientset)
 	if err != nil {
 		log.Error(err, "Failed to determine how to set default security context")
@@ -722,11 +812,25 @@ func startOperator(ctx context.Context) error {
 		return err
 	}
 
+	var shardManager *sharding.Manager
+	if shards := viper.GetInt(operator.ShardCountFlag); shards > 0 {
+		shardManager, err = newShardManager(clientset, operatorNamespace, shards)
+		if err != nil {
+			log.Error(err, "Invalid sharding parameters")
+			return err
+		}
+		if err := mgr.Add(shardManager); err != nil {
+			log.Error(err, "Failed to add the shard manager")
+			return err
+		}
+	}
+
 	params := operator.Parameters{
 		Dialer:                           dialer,
 		ElasticsearchObservationInterval: viper.GetDuration(operator.ElasticsearchObservationIntervalFlag),
 		ExposedNodeLabels:                exposedNodeLabels,
 		IPFamily:                         ipFamily,
+		LicenseExpiryWarnings:            licenseExpiryWarnings,
 		OperatorNamespace:                operatorNamespace,
 		OperatorInfo:                     operatorInfo,
 		GlobalCA:                         ca,
@@ -744,10 +848,7 @@ func startOperator(ctx context.Context) error {
 		SetDefaultSecurityContext: setDefaultSecurityContext,
 		ValidateStorageClass:      viper.GetBool(operator.ValidateStorageClassFlag),
 		Tracer:                    tracer,
-	}
-
-	if viper.GetBool(operator.EnableWebhookFlag) {
-		setupWebhook(ctx, mgr, params, webhookCertDir, clientset, exposedNodeLabels, managedNamespaces, tracer)
+		Sharding:                  shardManager,
 	}
 
 	enforceRbacOnRefs := viper.GetBool(operator.EnforceRBACOnRefsFlag)
@@ -759,13 +860,41 @@ func startOperator(ctx context.Context) error {
 		accessReviewer = rbac.NewPermissiveAccessReviewer()
 	}
 
+	if viper.GetBool(operator.EnableWebhookFlag) {
+		referenceValidationMode, err := commonwebhook.ParseReferenceValidationMode(viper.GetString(operator.WebhookReferenceValidationFlag))
+		if err != nil {
+			log.Error(err, "Invalid webhook reference validation parameter")
+			return err
+		}
+		referenceValidator := commonwebhook.NewReferenceValidator(mgr.GetClient(), accessReviewer, referenceValidationMode)
+		setupWebhook(ctx, mgr, params, webhookCertDir, clientset, crdClientset, exposedNodeLabels, referenceValidator, managedNamespaces, tracer)
+	}
+
 	if err := registerControllers(mgr, params, accessReviewer); err != nil {
 		return err
 	}
 
 	disableTelemetry := viper.GetBool(operator.DisableTelemetryFlag)
 	telemetryInterval := viper.GetDuration(operator.TelemetryIntervalFlag)
-	go asyncTasks(ctx, mgr, cfg, managedNamespaces, operatorNamespace, operatorInfo, disableTelemetry, telemetryInterval, tracer, dialer)
+	var telemetrySinks []telemetry.Sink
+	if telemetrySinksFile := viper.GetString(operator.TelemetrySinksFlag); telemetrySinksFile != "" {
+		telemetrySinks, err = telemetry.LoadSinks(mgr.GetClient(), operatorNamespace, telemetrySinksFile)
+		if err != nil {
+			log.Error(err, "Failed to load telemetry sinks", "file", telemetrySinksFile)
+			return err
+		}
+		log.Info("Exporting telemetry data to sinks", "file", telemetrySinksFile, "sinks", len(telemetrySinks))
+	}
+	var storageVersionMigrator *storageversion.Migrator
+	if viper.GetBool(operator.EnableStorageVersionMigrationFlag) {
+		if len(managedNamespaces) > 0 {
+			log.Info("Storage version migration is ignored as it requires the operator to manage all namespaces")
+		} else {
+			storageVersionMigrator = storageversion.NewMigrator(mgr.GetClient(), crdClientset)
+		}
+	}
+	licensingLedgerRetention := viper.GetInt(operator.LicensingLedgerRetentionFlag)
+	go asyncTasks(ctx, mgr, cfg, managedNamespaces, operatorNamespace, operatorInfo, disableTelemetry, telemetryInterval, telemetrySinks, licensingLedgerRetention, storageVersionMigrator, tracer, dialer)
 
 	log.Info("Starting the manager", "uuid", operatorInfo.OperatorUUID,
 		"namespace", operatorNamespace, "version", operatorInfo.BuildInfo.Version,
@@ -806,6 +935,28 @@ func startOperator(ctx context.Context) error {
 	}
 }
 
+// newShardManager returns a shard manager assigning the shards of resources to the operator replicas.
+func newShardManager(clientset kubernetes.Interface, operatorNamespace string, shards int) (*sharding.Manager, error) {
+	key, err := sharding.ParseKey(viper.GetString(operator.ShardKeyFlag))
+	if err != nil {
+		return nil, err
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	if !viper.GetBool(operator.EnableLeaderElection) {
+		log.Info("Leader election is disabled, cluster-wide tasks run on every operator replica")
+	}
+	log.Info("Sharding reconciliations across operator replicas", "shards", shards, "key", key, "identity", identity)
+	return sharding.NewManager(clientset, sharding.Params{
+		Shards:    shards,
+		Key:       key,
+		Namespace: operatorNamespace,
+		Identity:  identity,
+	}), nil
+}
+
 func readOptionalCA(caDir string) (*certificates.CA, error) {
 	if caDir == "" {
 		return nil, nil
@@ -823,6 +974,9 @@ func asyncTasks(
 	operatorInfo about.OperatorInfo,
 	disableTelemetry bool,
 	telemetryInterval time.Duration,
+	telemetrySinks []telemetry.Sink,
+	licensingLedgerRetention int,
+	storageVersionMigrator *storageversion.Migrator,
 	tracer *apm.Tracer,
 	dialer net.Dialer,
 ) {
@@ -836,14 +990,14 @@ func asyncTasks(
 
 	// Start the resource reporter
 	go func() {
-		r := licensing.NewResourceReporter(mgr.GetClient(), operatorNamespace, tracer)
+		r := licensing.NewResourceReporter(mgr.GetClient(), operatorNamespace, licensingLedgerRetention, tracer)
 		r.Start(ctx, licensing.ResourceReporterFrequency)
 	}()
 
-	if !disableTelemetry {
+	if !disableTelemetry || len(telemetrySinks) > 0 {
 		// Start the telemetry reporter
 		go func() {
-			tr := telemetry.NewReporter(operatorInfo, mgr.GetClient(), operatorNamespace, managedNamespaces, telemetryInterval, tracer)
+			tr := telemetry.NewReporter(operatorInfo, mgr.GetClient(), operatorNamespace, managedNamespaces, telemetryInterval, tracer, !disableTelemetry, telemetrySinks)
 			tr.Start(ctx)
 		}()
 	}
@@ -864,6 +1018,16 @@ func asyncTasks(
 		log.Error(err, "AutoOps garbage collection failed, will be attempted again at next operator restart")
 	}
 	tracing.EndContextTransaction(gcCtx)
+
+	// Rewrite the objects still stored in a previous version of their CRD
+	if storageVersionMigrator != nil {
+		migrationCtx := tracing.NewContextTransaction(ctx, tracer, tracing.RunOnceTxType, "storage-version-migration", nil)
+		migrationCtx = logconf.AddToContext(migrationCtx, logf.Log.WithName("storage-version-migration"))
+		if err := storageVersionMigrator.Migrate(migrationCtx); err != nil {
+			log.Error(err, "Storage version migration failed, will be attempted again at next operator restart")
+		}
+		tracing.EndContextTransaction(migrationCtx)
+	}
 }
 
 // determineSetDefaultSecurityContext determines what settings we need to use for security context by using the following rules:
@@ -921,6 +1085,7 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 	}{
 		{name: "APMServer", registerFunc: apmserver.Add},
 		{name: "Elasticsearch", registerFunc: elasticsearch.Add},
+		{name: "ElasticsearchDiagnostics", registerFunc: diagnostics.Add},
 		{name: "ElasticsearchAutoscaling", registerFunc: autoscaling.Add},
 		{name: "Kibana", registerFunc: kibana.Add},
 		{name: "EnterpriseSearch", registerFunc: enterprisesearch.Add},
@@ -932,6 +1097,8 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "PackageRegistry", registerFunc: packageregistry.Add},
 		{name: "StackConfigPolicy", registerFunc: stackconfigpolicy.Add},
 		{name: "Logstash", registerFunc: logstash.Add},
+		{name: "OTelCollector", registerFunc: otel.Add},
+		{name: "StackMonitoringSharedCollector", registerFunc: stackmon.AddSharedCollector},
 	}
 
 	for _, c := range controllers {
@@ -962,6 +1129,8 @@ func registerControllers(mgr manager.Manager, params operator.Parameters, access
 		{name: "AGENT-FS", registerFunc: associationctl.AddAgentFleetServer},
 		{name: "EMS-ES", registerFunc: associationctl.AddMapsES},
 		{name: "LOGSTASH-ES", registerFunc: associationctl.AddLogstashES},
+		{name: "OTEL-ES", registerFunc: associationctl.AddOTelCollectorES},
+		{name: "OTEL-APM", registerFunc: associationctl.AddOTelCollectorApm},
 		{name: "ES-MONITORING", registerFunc: associationctl.AddEsMonitoring},
 		{name: "KB-MONITORING", registerFunc: associationctl.AddKbMonitoring},
 		{name: "BEAT-MONITORING", registerFunc: associationctl.AddBeatMonitoring},
@@ -994,6 +1163,7 @@ func garbageCollectUsers(ctx context.Context, cfg *rest.Config, managedNamespace
 		For(&agentv1alpha1.AgentList{}, associationctl.AgentAssociationLabelNamespace, associationctl.AgentAssociationLabelName).
 		For(&emsv1alpha1.ElasticMapsServerList{}, associationctl.MapsESAssociationLabelNamespace, associationctl.MapsESAssociationLabelName).
 		For(&logstashv1alpha1.LogstashList{}, associationctl.LogstashAssociationLabelNamespace, associationctl.LogstashAssociationLabelName).
+		For(&otelv1alpha1.OTelCollectorList{}, associationctl.OTelCollectorAssociationLabelNamespace, associationctl.OTelCollectorAssociationLabelName).
 		DoGarbageCollection(ctx)
 	if err != nil {
 		return fmt.Errorf("user garbage collector failed: %w", err)
@@ -1017,6 +1187,7 @@ func garbageCollectSoftOwnedSecrets(ctx context.Context, k8sClient k8s.Client) {
 		policyv1alpha1.Kind:   &policyv1alpha1.StackConfigPolicy{},
 		autoopsv1alpha1.Kind:  &autoopsv1alpha1.AutoOpsAgentPolicy{},
 		logstashv1alpha1.Kind: &logstashv1alpha1.Logstash{},
+		otelv1alpha1.Kind:     &otelv1alpha1.OTelCollector{},
 	}); err != nil {
 		log.Error(err, "Orphan secrets garbage collection failed, will be attempted again at next operator restart.")
 		return
@@ -1041,13 +1212,31 @@ func setupWebhook(
 	params operator.Parameters,
 	webhookCertDir string,
 	clientset kubernetes.Interface,
+	crdClientset apiextensionsclient.Interface,
 	exposedNodeLabels esvalidation.NodeLabels,
+	referenceValidator *commonwebhook.ReferenceValidator,
 	managedNamespaces []string,
 	tracer *apm.Tracer,
 ) {
 	manageWebhookCerts := viper.GetBool(operator.ManageWebhookCertsFlag)
+	conversionWebhook := viper.GetBool(operator.EnableConversionWebhookFlag)
+	if conversionWebhook {
+		if err := conversion.RegisterWebhook(mgr); err != nil {
+			log.Error(err, "unable to setup the conversion webhook")
+			os.Exit(1)
+		}
+		if !manageWebhookCerts {
+			log.Info("The conversion webhook must be configured in the CRDs as webhook certificates are not managed by the operator")
+		}
+	}
 	if manageWebhookCerts {
-		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation, clientset, tracer); err != nil {
+		// the CRDs are configured to use the conversion webhook if enabled, reset otherwise
+		conversionCRDs, err := conversionWebhookCRDs(mgr)
+		if err != nil {
+			log.Error(err, "unable to list the CRDs served by the conversion webhook")
+			os.Exit(1)
+		}
+		if err := reconcileWebhookCertsAndAddController(ctx, mgr, params.CertRotation, clientset, crdClientset, conversionCRDs, conversionWebhook, tracer); err != nil {
 			log.Error(err, "unable to setup the webhook certificates")
 			os.Exit(1)
 		}
@@ -1063,7 +1252,6 @@ func setupWebhook(
 		&agentv1alpha1.Agent{},
 		&apmv1.ApmServer{},
 		&apmv1beta1.ApmServer{},
-		&beatv1beta1.Beat{},
 		&entv1.EnterpriseSearch{},
 		&entv1beta1.EnterpriseSearch{},
 		&esv1beta1.Elasticsearch{},
@@ -1072,22 +1260,25 @@ func setupWebhook(
 		&emsv1alpha1.ElasticMapsServer{},
 		&eprv1alpha1.PackageRegistry{},
 		&policyv1alpha1.StackConfigPolicy{},
+		&otelv1alpha1.OTelCollector{},
 	}
 	for _, obj := range webhookObjects {
 		commonwebhook.SetupValidatingWebhookWithConfig(&commonwebhook.Config{
-			Manager:          mgr,
-			WebhookPath:      obj.WebhookPath(),
-			ManagedNamespace: managedNamespaces,
-			Validator:        obj,
-			LicenseChecker:   checker,
+			Manager:            mgr,
+			WebhookPath:        obj.WebhookPath(),
+			ManagedNamespace:   managedNamespaces,
+			Validator:          obj,
+			LicenseChecker:     checker,
+			ReferenceValidator: referenceValidator,
 		})
 	}
 
-	// Logstash, Elasticsearch, ElasticsearchAutoscaling, and AutoOps validating webhooks are wired up
-	// differently in order to access the k8s client or license checker directly.
-	esvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, exposedNodeLabels, checker, managedNamespaces)
+	// Beat, Logstash, Elasticsearch, ElasticsearchAutoscaling, and AutoOps validating webhooks are wired up
+	// differently in order to access the k8s client, license checker or Beat type registry directly.
+	beatvalidation.RegisterWebhook(mgr, checker, referenceValidator, managedNamespaces)
+	esvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, exposedNodeLabels, checker, referenceValidator, managedNamespaces)
 	esavalidation.RegisterWebhook(mgr, params.ValidateStorageClass, checker, managedNamespaces)
-	lsvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, managedNamespaces)
+	lsvalidation.RegisterWebhook(mgr, params.ValidateStorageClass, referenceValidator, managedNamespaces)
 	autoopsvalidation.RegisterWebhook(mgr, checker, managedNamespaces)
 
 	// wait for the secret to be populated in the local filesystem before returning
@@ -1116,16 +1307,44 @@ func setupWebhook(
 	}
 }
 
-func reconcileWebhookCertsAndAddController(ctx context.Context, mgr manager.Manager, certRotation certificates.RotationParams, clientset kubernetes.Interface, tracer *apm.Tracer) error {
+// conversionWebhookCRDs returns the names of the CRDs of the resources converted by the conversion webhook.
+func conversionWebhookCRDs(mgr manager.Manager) ([]string, error) {
+	groupKinds, err := conversion.ConvertedGroupKinds(mgr.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	crds := make([]string, 0, len(groupKinds))
+	for _, gk := range groupKinds {
+		mapping, err := mgr.GetRESTMapper().RESTMapping(gk)
+		if err != nil {
+			return nil, err
+		}
+		crds = append(crds, mapping.Resource.GroupResource().String())
+	}
+	return crds, nil
+}
+
+func reconcileWebhookCertsAndAddController(
+	ctx context.Context,
+	mgr manager.Manager,
+	certRotation certificates.RotationParams,
+	clientset kubernetes.Interface,
+	crdClientset apiextensionsclient.Interface,
+	conversionCRDs []string,
+	conversionWebhook bool,
+	tracer *apm.Tracer,
+) error {
 	ctx = tracing.NewContextTransaction(ctx, tracer, tracing.ReconciliationTxType, webhook.ControllerName, nil)
 	defer tracing.EndContextTransaction(ctx)
 	log.Info("Automatic management of the webhook certificates enabled")
 	// Ensure that all the certificates needed by the webhook server are already created
 	webhookParams := webhook.Params{
-		Name:       viper.GetString(operator.WebhookNameFlag),
-		Namespace:  viper.GetString(operator.OperatorNamespaceFlag),
-		SecretName: viper.GetString(operator.WebhookSecretFlag),
-		Rotation:   certRotation,
+		Name:              viper.GetString(operator.WebhookNameFlag),
+		Namespace:         viper.GetString(operator.OperatorNamespaceFlag),
+		SecretName:        viper.GetString(operator.WebhookSecretFlag),
+		Rotation:          certRotation,
+		ConversionCRDs:    conversionCRDs,
+		ConversionWebhook: conversionWebhook,
 	}
 
 	// retrieve the current webhook configuration interface
@@ -1138,8 +1357,11 @@ func reconcileWebhookCertsAndAddController(ctx context.Context, mgr manager.Mana
 	if err := webhookParams.ReconcileResources(ctx, clientset, wh); err != nil {
 		return err
 	}
+	if err := webhookParams.ReconcileCRDConversion(ctx, crdClientset, wh); err != nil {
+		return err
+	}
 
-	return webhook.Add(mgr, webhookParams, clientset, wh, tracer)
+	return webhook.Add(mgr, webhookParams, clientset, crdClientset, wh, tracer)
 }
 
 func fipsLog() {
@@ -222,6 +222,18 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - update
   - patch
   - delete
+- apiGroups:
+  - autoscaling
+  resources:
+  - horizontalpodautoscalers
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
 - apiGroups:
   - elasticsearch.k8s.elastic.co
   resources:
@@ -313,6 +325,7 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - create
   - update
   - patch
+  - delete
 - apiGroups:
   - maps.k8s.elastic.co
   resources:
@@ -380,6 +393,21 @@ updating docs/operating-eck/eck-permissions.asciidoc file.
   - patch
   - delete
   - deletecollection
+- apiGroups:
+  - otel.k8s.elastic.co
+  resources:
+  - otelcollectors
+  - otelcollectors/status
+  - otelcollectors/finalizers # needed for ownerReferences with blockOwnerDeletion on OCP
+  verbs:
+  - get
+  - list
+  - watch
+  - create
+  - update
+  - patch
+  - delete
+  - deletecollection
 {{- end -}}
 
 {{/*
@@ -401,6 +429,15 @@ RBAC permissions on non-namespaced resources
   - get
   - list
   - watch
+- apiGroups: [""]
+  resources:
+  - persistentvolumes
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
 - apiGroups:
   - admissionregistration.k8s.io
   resources:
@@ -413,6 +450,37 @@ RBAC permissions on non-namespaced resources
   - update
   - patch
   - delete
+{{- if and .Values.webhook.enabled .Values.webhook.manageCerts .Values.webhook.conversion }}
+{{- /* the CRDs served in several versions, to configure their conversion webhook */}}
+- apiGroups:
+  - apiextensions.k8s.io
+  resources:
+  - customresourcedefinitions
+  resourceNames:
+  - apmservers.apm.k8s.elastic.co
+  - elasticsearches.elasticsearch.k8s.elastic.co
+  - enterprisesearches.enterprisesearch.k8s.elastic.co
+  - kibanas.kibana.k8s.elastic.co
+  verbs:
+  - get
+  - update
+{{- end }}
+{{- if .Values.config.storageVersionMigration }}
+- apiGroups:
+  - apiextensions.k8s.io
+  resources:
+  - customresourcedefinitions
+  verbs:
+  - get
+  - list
+  - update
+- apiGroups:
+  - apiextensions.k8s.io
+  resources:
+  - customresourcedefinitions/status
+  verbs:
+  - update
+{{- end }}
 {{- end -}}
 
 {{/*
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 )
 
@@ -22,6 +23,7 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
 		checkAtMostOneDeploymentOption,
 		checkAtMostOneDefaultESRef,
 		checkESRefsNamed,
@@ -62,6 +64,15 @@ func checkSupportedVersion(a *Agent) field.ErrorList {
 	return commonv1.CheckSupportedStackVersion(a.Spec.Version, version.SupportedAgentVersions)
 }
 
+func checkImageCatalogVersion(a *Agent) field.ErrorList {
+	v, err := version.Parse(a.Spec.Version)
+	if err != nil {
+		// already reported by checkSupportedVersion
+		return nil
+	}
+	return commonv1.CheckImageCatalogVersion(a.Spec.Version, container.AgentImageFor(v), a.Spec.Image)
+}
+
 func checkIfVersionDeprecated(a *Agent) (string, field.ErrorList) {
 	return commonv1.CheckDeprecatedStackVersion(a.Spec.Version)
 }
@@ -6,13 +6,16 @@ package v1alpha1
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 )
 
 func Test_checkSupportedVersion(t *testing.T) {
@@ -832,3 +835,31 @@ func Test_checkNoDowngrade(t *testing.T) {
 		})
 	}
 }
+
+func Test_checkImageCatalogVersion(t *testing.T) {
+	catalog, err := container.ParseImageCatalog([]byte(`
+images:
+- image: elastic-agent/elastic-agent
+  version: 9.1.0
+  digest: sha256:` + strings.Repeat("a", 64)))
+	require.NoError(t, err)
+	container.SetImageCatalog(catalog)
+	defer container.SetImageCatalog(nil)
+
+	for _, tt := range []struct {
+		name    string
+		version string
+		image   string
+		wantErr bool
+	}{
+		{name: "version in the catalog", version: "9.1.0"},
+		{name: "version missing from the catalog", version: "9.2.0", wantErr: true},
+		{name: "image of another major version not in the catalog", version: "8.19.0"},
+		{name: "custom image", version: "9.2.0", image: "my.registry/elastic-agent:9.2.0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := checkImageCatalogVersion(&Agent{Spec: AgentSpec{Version: tt.version, Image: tt.image}})
+			assert.Equal(t, tt.wantErr, len(errs) > 0, errs)
+		})
+	}
+}
@@ -26,12 +26,17 @@ type ApmServerSpec struct {
 	// Version of the APM Server.
 	Version string `json:"version"`
 
-	// Image is the APM Server Docker image to deploy.
+	// Image is the APM Server Docker image to deploy. In `integration` mode, it is the Elastic Agent Docker image.
 	Image string `json:"image,omitempty"`
 
 	// Count of APM Server instances to deploy.
 	Count int32 `json:"count,omitempty"`
 
+	// Autoscaling defines a HorizontalPodAutoscaler managed by the operator to scale the APM Server Deployment.
+	// When set, Count is ignored and the number of instances is left to the HorizontalPodAutoscaler.
+	// +kubebuilder:validation:Optional
+	Autoscaling *commonv1.HorizontalPodAutoscalerSpec `json:"autoscaling,omitempty"`
+
 	// Config holds the APM Server configuration. See: https://www.elastic.co/guide/en/apm/server/current/configuring-howto-apm-server.html
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Config *commonv1.Config `json:"config,omitempty"`
@@ -61,8 +66,33 @@ type ApmServerSpec struct {
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Mode specifies how APM Server runs. In `standalone` mode, the APM Server binary is deployed with the configuration
+	// specified through `config`. In `integration` mode, a Fleet-managed Elastic Agent running the APM integration is
+	// deployed, and its Fleet policy is managed through Kibana. The secret token and the Service are kept, so that
+	// instrumented applications are not affected when switching modes. `integration` mode requires `kibanaRef` and
+	// `fleetServerRef`, and does not support `config`, `secureSettings` and `autoscaling`.
+	// Defaults to `standalone` mode.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=standalone;integration
+	Mode ApmServerMode `json:"mode,omitempty"`
+
+	// FleetServerRef is a reference to the Fleet Server the Elastic Agent running the APM integration enrolls with.
+	// Don't set unless `mode` is set to `integration`.
+	// +kubebuilder:validation:Optional
+	FleetServerRef commonv1.ObjectSelector `json:"fleetServerRef,omitempty"`
 }
 
+// ApmServerMode is the runtime mode of an APM Server.
+type ApmServerMode string
+
+const (
+	// ApmServerStandaloneMode denotes running the standalone APM Server binary.
+	ApmServerStandaloneMode ApmServerMode = "standalone"
+	// ApmServerIntegrationMode denotes running the APM integration in a Fleet-managed Elastic Agent.
+	ApmServerIntegrationMode ApmServerMode = "integration"
+)
+
 // ApmServerStatus defines the observed state of ApmServer
 type ApmServerStatus struct {
 	commonv1.DeploymentStatus `json:",inline"`
@@ -79,6 +109,10 @@ type ApmServerStatus struct {
 	// KibanaAssociationStatus is the status of any auto-linking to Kibana.
 	KibanaAssociationStatus commonv1.AssociationStatus `json:"kibanaAssociationStatus,omitempty"`
 
+	// Integration is the status of the APM integration, when running in `integration` mode.
+	// +kubebuilder:validation:Optional
+	Integration *IntegrationStatus `json:"integration,omitempty"`
+
 	// ObservedGeneration represents the .metadata.generation that the status is based upon.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the APM Server
@@ -87,6 +121,18 @@ type ApmServerStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// IntegrationStatus is the status of the APM integration running in a Fleet-managed Elastic Agent.
+type IntegrationStatus struct {
+	// Agent is the name of the Elastic Agent running the APM integration.
+	Agent string `json:"agent,omitempty"`
+	// AgentPolicyID is the ID of the Fleet agent policy the Elastic Agent is enrolled in.
+	AgentPolicyID string `json:"agentPolicyID,omitempty"`
+	// PackagePolicyID is the ID of the Fleet package policy configuring the APM integration.
+	PackagePolicyID string `json:"packagePolicyID,omitempty"`
+	// PackagePolicyHash is a hash of the APM integration settings last applied to the package policy.
+	PackagePolicyHash string `json:"packagePolicyHash,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // ApmServer represents an APM Server resource in a Kubernetes cluster.
@@ -121,6 +167,11 @@ func init() {
 	SchemeBuilder.Register(&ApmServer{}, &ApmServerList{})
 }
 
+// IsIntegrationMode returns true if the APM Server runs as the APM integration in a Fleet-managed Elastic Agent.
+func (as *ApmServer) IsIntegrationMode() bool {
+	return as.Spec.Mode == ApmServerIntegrationMode
+}
+
 // IsMarkedForDeletion returns true if the APM is going to be deleted
 func (as *ApmServer) IsMarkedForDeletion() bool {
 	return !as.DeletionTimestamp.IsZero()
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/admission"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
@@ -31,12 +32,19 @@ var (
 	// ApmAgentConfigurationMinVersion is the minimum required version to establish an association with Kibana
 	ApmAgentConfigurationMinVersion = version.MustParse("7.5.1")
 
+	// IntegrationModeMinVersion is the minimum required version to run APM Server in integration mode, from which
+	// the Fleet API can be used without superuser privileges.
+	IntegrationModeMinVersion = version.MustParse("8.1.0")
+
 	defaultChecks = []func(*ApmServer) field.ErrorList{
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
 		checkAgentConfigurationMinVersion,
 		checkAssociations,
+		checkAutoscaling,
+		checkIntegrationMode,
 	}
 
 	updateChecks = []func(old, curr *ApmServer) field.ErrorList{
@@ -128,6 +136,10 @@ func checkSupportedVersion(as *ApmServer) field.ErrorList {
 	return commonv1.CheckSupportedStackVersion(as.Spec.Version, version.SupportedAPMServerVersions)
 }
 
+func checkImageCatalogVersion(as *ApmServer) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(as.Spec.Version, container.APMServerImage, as.Spec.Image)
+}
+
 func checkIfVersionDeprecated(as *ApmServer) (string, field.ErrorList) {
 	return commonv1.CheckDeprecatedStackVersion(as.Spec.Version)
 }
@@ -164,5 +176,51 @@ func checkAgentConfigurationMinVersion(as *ApmServer) field.ErrorList {
 func checkAssociations(as *ApmServer) field.ErrorList {
 	err1 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), as.Spec.ElasticsearchRef)
 	err2 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("kibanaRef"), as.Spec.KibanaRef)
-	return append(err1, err2...)
+	err3 := commonv1.CheckAssociationRefs(field.NewPath("spec").Child("fleetServerRef"), as.Spec.FleetServerRef)
+	return append(append(err1, err2...), err3...)
+}
+
+func checkAutoscaling(as *ApmServer) field.ErrorList {
+	return commonv1.CheckHorizontalPodAutoscaler(field.NewPath("spec").Child("autoscaling"), as.Spec.Autoscaling)
+}
+
+func checkIntegrationMode(as *ApmServer) field.ErrorList {
+	specPath := field.NewPath("spec")
+	if !as.IsIntegrationMode() {
+		if as.Spec.FleetServerRef.IsSet() {
+			return field.ErrorList{field.Forbidden(specPath.Child("fleetServerRef"), "fleetServerRef can only be used in integration mode")}
+		}
+		return nil
+	}
+
+	var errs field.ErrorList
+	apmVersion, err := commonv1.ParseVersion(as.EffectiveVersion())
+	if err != nil {
+		return err
+	}
+	if !apmVersion.GTE(IntegrationModeMinVersion) {
+		errs = append(errs, field.Forbidden(
+			specPath.Child("mode"),
+			fmt.Sprintf("minimum required version for integration mode is %s but desired version is %s", IntegrationModeMinVersion, apmVersion),
+		))
+	}
+	if !as.Spec.KibanaRef.IsSet() {
+		errs = append(errs, field.Required(specPath.Child("kibanaRef"), "kibanaRef is required in integration mode to manage the Fleet policy"))
+	}
+	if !as.Spec.FleetServerRef.IsSet() {
+		errs = append(errs, field.Required(specPath.Child("fleetServerRef"), "fleetServerRef is required in integration mode to enroll the Elastic Agent"))
+	}
+	if as.Spec.FleetServerRef.IsExternal() {
+		errs = append(errs, field.Forbidden(specPath.Child("fleetServerRef"), "references to Fleet Server running outside the Kubernetes cluster are not supported"))
+	}
+	if as.Spec.Config != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("config"), "config cannot be used in integration mode, where the configuration is managed through Fleet"))
+	}
+	if len(as.Spec.SecureSettings) > 0 {
+		errs = append(errs, field.Forbidden(specPath.Child("secureSettings"), "secureSettings cannot be used in integration mode"))
+	}
+	if as.Spec.Autoscaling != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("autoscaling"), "autoscaling cannot be used in integration mode"))
+	}
+	return errs
 }
@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
@@ -232,6 +233,92 @@ func TestWebhook(t *testing.T) {
 				`spec.elasticsearchRef: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "autoscaling",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](2), MaxReplicas: 5}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "autoscaling-max-lower-than-min",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](3), MaxReplicas: 2}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.autoscaling.maxReplicas: Invalid value: 2: maxReplicas cannot be lower than minReplicas`,
+			),
+		},
+		{
+			Name:      "integration-mode",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.Version = "8.17.0"
+				apm.Spec.Mode = apmv1.ApmServerIntegrationMode
+				apm.Spec.KibanaRef = commonv1.ObjectSelector{Name: "kb"}
+				apm.Spec.FleetServerRef = commonv1.ObjectSelector{Name: "fleet-server"}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "integration-mode-missing-refs",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.Version = "8.17.0"
+				apm.Spec.Mode = apmv1.ApmServerIntegrationMode
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.kibanaRef: Required value: kibanaRef is required in integration mode to manage the Fleet policy`,
+				`spec.fleetServerRef: Required value: fleetServerRef is required in integration mode to enroll the Elastic Agent`,
+			),
+		},
+		{
+			Name:      "integration-mode-unsupported-fields",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.Version = "8.0.0"
+				apm.Spec.Mode = apmv1.ApmServerIntegrationMode
+				apm.Spec.KibanaRef = commonv1.ObjectSelector{Name: "kb"}
+				apm.Spec.FleetServerRef = commonv1.ObjectSelector{Name: "fleet-server"}
+				apm.Spec.Config = &commonv1.Config{Data: map[string]any{"apm-server.rum.enabled": true}}
+				apm.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MaxReplicas: 2}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.mode: Forbidden: minimum required version for integration mode is 8.1.0 but desired version is 8.0.0`,
+				`spec.config: Forbidden: config cannot be used in integration mode, where the configuration is managed through Fleet`,
+				`spec.autoscaling: Forbidden: autoscaling cannot be used in integration mode`,
+			),
+		},
+		{
+			Name:      "fleet-server-ref-in-standalone-mode",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				apm := mkApmServer(uid)
+				apm.Spec.FleetServerRef = commonv1.ObjectSelector{Name: "fleet-server"}
+				return serialize(t, apm)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.fleetServerRef: Forbidden: fleetServerRef can only be used in integration mode`,
+			),
+		},
 	}
 
 	validator := &apmv1.ApmServer{}
@@ -59,7 +59,7 @@ func (in *ApmServer) DeepCopyInto(out *ApmServer) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.esAssocConf != nil {
 		in, out := &in.esAssocConf, &out.esAssocConf
 		*out = new(commonv1.AssociationConf)
@@ -125,6 +125,11 @@ func (in *ApmServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApmServerSpec) DeepCopyInto(out *ApmServerSpec) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(commonv1.HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -145,6 +150,7 @@ func (in *ApmServerSpec) DeepCopyInto(out *ApmServerSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.FleetServerRef = in.FleetServerRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmServerSpec.
@@ -161,6 +167,11 @@ func (in *ApmServerSpec) DeepCopy() *ApmServerSpec {
 func (in *ApmServerStatus) DeepCopyInto(out *ApmServerStatus) {
 	*out = *in
 	out.DeploymentStatus = in.DeploymentStatus
+	if in.Integration != nil {
+		in, out := &in.Integration, &out.Integration
+		*out = new(IntegrationStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApmServerStatus.
@@ -172,3 +183,18 @@ func (in *ApmServerStatus) DeepCopy() *ApmServerStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntegrationStatus) DeepCopyInto(out *IntegrationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntegrationStatus.
+func (in *IntegrationStatus) DeepCopy() *IntegrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IntegrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -78,6 +78,45 @@ type AutoOpsAgentPolicySpec struct {
 	// in the target namespaces.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Mode defines how AutoOps agents are deployed for the selected Elasticsearch clusters.
+	// PerCluster, the default, runs one agent Deployment per Elasticsearch cluster.
+	// Shared runs a fixed number of agent Deployments, or shards, among which the selected clusters are distributed.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=PerCluster;Shared
+	Mode AgentMode `json:"mode,omitempty"`
+
+	// Shards is the number of agent Deployments among which the selected Elasticsearch clusters are distributed
+	// in Shared mode. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Shards *int32 `json:"shards,omitempty"`
+}
+
+// AgentMode defines how AutoOps agents are deployed for the selected Elasticsearch clusters.
+type AgentMode string
+
+const (
+	// PerClusterMode runs one agent Deployment per Elasticsearch cluster.
+	PerClusterMode AgentMode = "PerCluster"
+	// SharedMode runs a fixed number of agent Deployments that each monitor a subset of the Elasticsearch clusters.
+	SharedMode AgentMode = "Shared"
+)
+
+// IsShared returns true if the agents are shared between the selected Elasticsearch clusters.
+func (s AutoOpsAgentPolicySpec) IsShared() bool {
+	return s.Mode == SharedMode
+}
+
+// ShardCount returns the number of agent Deployments in Shared mode, or 0 otherwise.
+func (s AutoOpsAgentPolicySpec) ShardCount() int {
+	if !s.IsShared() {
+		return 0
+	}
+	if s.Shards == nil || *s.Shards < 1 {
+		return 1
+	}
+	return int(*s.Shards)
 }
 
 // AutoOpsRef defines a reference to a secret containing connection details for AutoOps via Cloud Connect.
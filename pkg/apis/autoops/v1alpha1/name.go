@@ -5,6 +5,8 @@
 package v1alpha1
 
 import (
+	"strconv"
+
 	"k8s.io/apimachinery/pkg/types"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
@@ -22,6 +24,8 @@ const (
 	configSuffix       = "config"
 	caSecretSuffix     = "ca"
 	apiKeySecretSuffix = "apikey"
+	shardSuffix        = "shard"
+	sharedConfigSuffix = "shared-config"
 )
 
 // Deployment returns the name of the deployment for the given policy and ES instance.
@@ -47,3 +51,13 @@ func APIKeySecret(policyName string, es types.NamespacedName) string {
 	hash := hash.HashObject(es.Namespace + es.Name)
 	return AutoOpsNamer.Suffix(policyName, apiKeySecretSuffix, hash)
 }
+
+// SharedDeployment returns the name of the deployment of the given shard for the given policy in Shared mode.
+func SharedDeployment(policyName string, shard int) string {
+	return AutoOpsNamer.Suffix(policyName, deploymentSuffix, shardSuffix, strconv.Itoa(shard))
+}
+
+// SharedConfig returns the name of the Secret which holds the configuration of the given shard for the given policy in Shared mode.
+func SharedConfig(policyName string, shard int) string {
+	return AutoOpsNamer.Suffix(policyName, sharedConfigSuffix, strconv.Itoa(shard))
+}
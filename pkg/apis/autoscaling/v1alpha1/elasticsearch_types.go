@@ -46,6 +46,11 @@ type ElasticsearchAutoscalerSpec struct {
 	// +kubebuilder:validation:Optional
 	// PollingPeriod is the period at which to synchronize with the Elasticsearch autoscaling API.
 	PollingPeriod *metav1.Duration `json:"pollingPeriod,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// Schedules raise the minimum resources of some autoscaling policies during time windows, for example to prepare
+	// for a predictable peak of activity.
+	Schedules []AutoscalingSchedule `json:"schedules,omitempty"`
 }
 
 func (esa *ElasticsearchAutoscaler) GetAutoscalingPolicySpecs() (v1alpha1.AutoscalingPolicySpecs, error) {
@@ -0,0 +1,253 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+	// Embed the IANA time zone database, the operator image does not ship one.
+	_ "time/tzdata"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ScheduleFrequency is the frequency at which a scheduled window repeats.
+// +kubebuilder:validation:Enum=Daily;Weekly;Monthly
+type ScheduleFrequency string
+
+const (
+	DailySchedule   ScheduleFrequency = "Daily"
+	WeeklySchedule  ScheduleFrequency = "Weekly"
+	MonthlySchedule ScheduleFrequency = "Monthly"
+)
+
+// Period returns the shortest period of the frequency, which is also the longest allowed window duration.
+func (f ScheduleFrequency) Period() time.Duration {
+	switch f {
+	case DailySchedule:
+		return 24 * time.Hour
+	case WeeklySchedule:
+		return 7 * 24 * time.Hour
+	case MonthlySchedule:
+		// shortest month
+		return 28 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// Weekday is a day of the week.
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type Weekday string
+
+// AutoscalingSchedule raises the minimum resources of some autoscaling policies during one-off or recurring time
+// windows. Once a window is over the regular minimum resources apply again, and the resources are scaled down
+// according to the Elasticsearch autoscaling deciders.
+type AutoscalingSchedule struct {
+	// Name identifies the schedule in the autoscaler specification.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Policies are the names of the autoscaling policies to which the schedule applies.
+	// +kubebuilder:validation:MinItems=1
+	Policies []string `json:"policies"`
+
+	// Start is the beginning of a one-off window. It must be specified along with End, and cannot be used with Recurrence.
+	// +kubebuilder:validation:Optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the end of a one-off window.
+	// +kubebuilder:validation:Optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Recurrence defines a window repeated daily, weekly or monthly. It cannot be used with Start and End.
+	// +kubebuilder:validation:Optional
+	Recurrence *ScheduleRecurrence `json:"recurrence,omitempty"`
+
+	// Min holds the minimum resources of the policies while a window is active. The minimum resources of a policy are
+	// only raised: a value lower than the one in the policy has no effect. The maximum resources of the policy are raised
+	// as well if they are lower than the scheduled minimum.
+	Min ScheduledResources `json:"min"`
+}
+
+// ScheduleRecurrence defines a recurring time window.
+type ScheduleRecurrence struct {
+	// Frequency is the frequency at which the window repeats.
+	Frequency ScheduleFrequency `json:"frequency"`
+
+	// StartTime is the time of the day at which the window starts, in the HH:MM format.
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+
+	// Duration of the window. It cannot be longer than the period of the recurrence, using 28 days for a month.
+	Duration metav1.Duration `json:"duration"`
+
+	// DaysOfWeek are the days on which a weekly window starts.
+	// +kubebuilder:validation:Optional
+	DaysOfWeek []Weekday `json:"daysOfWeek,omitempty"`
+
+	// DaysOfMonth are the days on which a monthly window starts. Negative values count from the end of the month,
+	// -1 being the last day of the month. A day which does not exist in a given month is skipped.
+	// +kubebuilder:validation:Optional
+	DaysOfMonth []int32 `json:"daysOfMonth,omitempty"`
+
+	// TimeZone is the IANA name of the time zone of StartTime, for example "Europe/Paris". Defaults to UTC.
+	// +kubebuilder:validation:Optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ScheduledResources holds the minimum resources applied to autoscaling policies during a scheduled window.
+type ScheduledResources struct {
+	// NodeCount is the minimum number of nodes of the policy.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	NodeCount *int32 `json:"nodeCount,omitempty"`
+	// CPU is the minimum CPU of the nodes managed by the policy.
+	// +kubebuilder:validation:Optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Memory is the minimum memory of the nodes managed by the policy.
+	// +kubebuilder:validation:Optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// Storage is the minimum storage capacity of the nodes managed by the policy.
+	// +kubebuilder:validation:Optional
+	Storage *resource.Quantity `json:"storage,omitempty"`
+}
+
+// IsEmpty returns true if no resource is set.
+func (sr ScheduledResources) IsEmpty() bool {
+	return sr.NodeCount == nil && sr.CPU == nil && sr.Memory == nil && sr.Storage == nil
+}
+
+// ScheduleWindow is an occurrence of a scheduled window.
+// +kubebuilder:object:generate=false
+type ScheduleWindow struct {
+	Start, End time.Time
+}
+
+// Contains returns true if t is within the window, the end of the window being excluded.
+func (w ScheduleWindow) Contains(t time.Time) bool {
+	return !t.Before(w.Start) && t.Before(w.End)
+}
+
+// ActiveWindow returns the window of the schedule containing the given time, if any.
+// Invalid schedules, which are rejected by the validation, are never active.
+func (s AutoscalingSchedule) ActiveWindow(now time.Time) (ScheduleWindow, bool) {
+	var active ScheduleWindow
+	var found bool
+	for _, w := range s.windows(now.Add(-s.maxDuration()), now) {
+		// windows of a same schedule may overlap, retain the one ending last
+		if w.Contains(now) && (!found || w.End.After(active.End)) {
+			active, found = w, true
+		}
+	}
+	return active, found
+}
+
+// NextStart returns the start of the first window starting after the given time, if any.
+func (s AutoscalingSchedule) NextStart(now time.Time) (time.Time, bool) {
+	if s.Recurrence == nil {
+		if s.Start != nil && s.Start.Time.After(now) {
+			return s.Start.Time, true
+		}
+		return time.Time{}, false
+	}
+	// a month is at most 3 days longer than the shortest one
+	for _, w := range s.Recurrence.windows(now, now.Add(s.Recurrence.Frequency.Period()+3*24*time.Hour)) {
+		if w.Start.After(now) {
+			return w.Start, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s AutoscalingSchedule) maxDuration() time.Duration {
+	if s.Recurrence != nil {
+		return s.Recurrence.Duration.Duration
+	}
+	if s.Start != nil && s.End != nil {
+		return s.End.Sub(s.Start.Time)
+	}
+	return 0
+}
+
+// windows returns the windows of the schedule starting between from and to, sorted by start time.
+func (s AutoscalingSchedule) windows(from, to time.Time) []ScheduleWindow {
+	if s.Recurrence == nil {
+		if s.Start == nil || s.End == nil || !s.End.After(s.Start.Time) {
+			return nil
+		}
+		if s.Start.Time.Before(from) || s.Start.Time.After(to) {
+			return nil
+		}
+		return []ScheduleWindow{{Start: s.Start.Time, End: s.End.Time}}
+	}
+	return s.Recurrence.windows(from, to)
+}
+
+func (r ScheduleRecurrence) windows(from, to time.Time) []ScheduleWindow {
+	loc, err := r.Location()
+	if err != nil {
+		return nil
+	}
+	hour, minute, err := r.ParseStartTime()
+	if err != nil || r.Duration.Duration <= 0 {
+		return nil
+	}
+	var windows []ScheduleWindow
+	from, to = from.In(loc), to.In(loc)
+	// iterate over the days, starting the day before to account for time zone transitions
+	for day := time.Date(from.Year(), from.Month(), from.Day()-1, 0, 0, 0, 0, loc); !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !r.startsOn(day) {
+			continue
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+		if start.Before(from) || start.After(to) {
+			continue
+		}
+		windows = append(windows, ScheduleWindow{Start: start, End: start.Add(r.Duration.Duration)})
+	}
+	return windows
+}
+
+// startsOn returns true if a window starts on the given day.
+func (r ScheduleRecurrence) startsOn(day time.Time) bool {
+	switch r.Frequency {
+	case DailySchedule:
+		return true
+	case WeeklySchedule:
+		for _, d := range r.DaysOfWeek {
+			if string(d) == day.Weekday().String() {
+				return true
+			}
+		}
+	case MonthlySchedule:
+		// day 0 of the next month is the last day of the current one
+		lastDay := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+		for _, d := range r.DaysOfMonth {
+			if int(d) == day.Day() || (d < 0 && lastDay+int(d)+1 == day.Day()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Location returns the time zone of the recurrence.
+func (r ScheduleRecurrence) Location() (*time.Location, error) {
+	if r.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(r.TimeZone)
+}
+
+// ParseStartTime returns the hour and the minute at which the window starts.
+func (r ScheduleRecurrence) ParseStartTime() (int, int, error) {
+	t, err := time.Parse("15:04", r.StartTime)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start time %q, expected HH:MM: %w", r.StartTime, err)
+	}
+	return t.Hour(), t.Minute(), nil
+}
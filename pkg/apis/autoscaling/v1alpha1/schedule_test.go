@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestAutoscalingSchedule_ActiveWindow(t *testing.T) {
+	oneOffStart := metav1.NewTime(mustParseTime(t, "2026-11-27T00:00:00Z"))
+	oneOffEnd := metav1.NewTime(mustParseTime(t, "2026-11-30T00:00:00Z"))
+	tests := []struct {
+		name      string
+		schedule  AutoscalingSchedule
+		now       string
+		wantStart string
+		wantEnd   string
+	}{
+		{
+			name:      "one-off window in progress",
+			schedule:  AutoscalingSchedule{Start: &oneOffStart, End: &oneOffEnd},
+			now:       "2026-11-28T12:00:00Z",
+			wantStart: "2026-11-27T00:00:00Z",
+			wantEnd:   "2026-11-30T00:00:00Z",
+		},
+		{
+			name:     "one-off window over, end is excluded",
+			schedule: AutoscalingSchedule{Start: &oneOffStart, End: &oneOffEnd},
+			now:      "2026-11-30T00:00:00Z",
+		},
+		{
+			name: "daily window spanning midnight, started the day before",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: DailySchedule, StartTime: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour},
+			}},
+			now:       "2026-10-20T01:00:00Z",
+			wantStart: "2026-10-19T22:00:00Z",
+			wantEnd:   "2026-10-20T02:00:00Z",
+		},
+		{
+			name: "daily window in another time zone",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: DailySchedule, StartTime: "01:30", Duration: metav1.Duration{Duration: 3 * time.Hour}, TimeZone: "Europe/Paris",
+			}},
+			// 01:30 in Paris is 23:30 UTC during summer time
+			now:       "2026-06-30T23:45:00Z",
+			wantStart: "2026-06-30T23:30:00Z",
+			wantEnd:   "2026-07-01T02:30:00Z",
+		},
+		{
+			name: "weekly window on another day",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: WeeklySchedule, StartTime: "08:00", Duration: metav1.Duration{Duration: 10 * time.Hour}, DaysOfWeek: []Weekday{"Monday"},
+			}},
+			// a Tuesday
+			now: "2026-10-20T09:00:00Z",
+		},
+		{
+			name: "weekly window",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: WeeklySchedule, StartTime: "08:00", Duration: metav1.Duration{Duration: 10 * time.Hour}, DaysOfWeek: []Weekday{"Monday", "Tuesday"},
+			}},
+			now:       "2026-10-20T09:00:00Z",
+			wantStart: "2026-10-20T08:00:00Z",
+			wantEnd:   "2026-10-20T18:00:00Z",
+		},
+		{
+			name: "monthly window on the last day of the month",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: MonthlySchedule, StartTime: "00:00", Duration: metav1.Duration{Duration: 48 * time.Hour}, DaysOfMonth: []int32{-1},
+			}},
+			now:       "2027-03-01T12:00:00Z",
+			wantStart: "2027-02-28T00:00:00Z",
+			wantEnd:   "2027-03-02T00:00:00Z",
+		},
+		{
+			name: "monthly window skips missing days",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: MonthlySchedule, StartTime: "00:00", Duration: metav1.Duration{Duration: time.Hour}, DaysOfMonth: []int32{31},
+			}},
+			now: "2026-11-30T00:30:00Z",
+		},
+		{
+			name: "invalid time zone is never active",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: DailySchedule, StartTime: "00:00", Duration: metav1.Duration{Duration: 24 * time.Hour}, TimeZone: "Nowhere",
+			}},
+			now: "2026-10-20T09:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, active := tt.schedule.ActiveWindow(mustParseTime(t, tt.now))
+			if tt.wantStart == "" {
+				assert.False(t, active)
+				return
+			}
+			assert.True(t, active)
+			assert.True(t, mustParseTime(t, tt.wantStart).Equal(window.Start), "start: %s", window.Start)
+			assert.True(t, mustParseTime(t, tt.wantEnd).Equal(window.End), "end: %s", window.End)
+		})
+	}
+}
+
+func TestAutoscalingSchedule_NextStart(t *testing.T) {
+	oneOffStart := metav1.NewTime(mustParseTime(t, "2027-11-26T00:00:00Z"))
+	oneOffEnd := metav1.NewTime(mustParseTime(t, "2027-11-29T00:00:00Z"))
+	tests := []struct {
+		name     string
+		schedule AutoscalingSchedule
+		now      string
+		want     string
+	}{
+		{
+			name:     "one-off window in more than a month",
+			schedule: AutoscalingSchedule{Start: &oneOffStart, End: &oneOffEnd},
+			now:      "2026-10-20T00:00:00Z",
+			want:     "2027-11-26T00:00:00Z",
+		},
+		{
+			name:     "one-off window already started",
+			schedule: AutoscalingSchedule{Start: &oneOffStart, End: &oneOffEnd},
+			now:      "2027-11-27T00:00:00Z",
+		},
+		{
+			name: "daily window later today",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: DailySchedule, StartTime: "22:00", Duration: metav1.Duration{Duration: time.Hour},
+			}},
+			now:  "2026-10-20T21:00:00Z",
+			want: "2026-10-20T22:00:00Z",
+		},
+		{
+			name: "monthly window next month",
+			schedule: AutoscalingSchedule{Recurrence: &ScheduleRecurrence{
+				Frequency: MonthlySchedule, StartTime: "00:00", Duration: metav1.Duration{Duration: time.Hour}, DaysOfMonth: []int32{1},
+			}},
+			now:  "2026-10-01T00:30:00Z",
+			want: "2026-11-01T00:00:00Z",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, ok := tt.schedule.NextStart(mustParseTime(t, tt.now))
+			if tt.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.True(t, mustParseTime(t, tt.want).Equal(next), "next: %s", next)
+		})
+	}
+}
@@ -14,6 +14,40 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingSchedule) DeepCopyInto(out *AutoscalingSchedule) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Start != nil {
+		in, out := &in.Start, &out.Start
+		*out = (*in).DeepCopy()
+	}
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	if in.Recurrence != nil {
+		in, out := &in.Recurrence, &out.Recurrence
+		*out = new(ScheduleRecurrence)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Min.DeepCopyInto(&out.Min)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingSchedule.
+func (in *AutoscalingSchedule) DeepCopy() *AutoscalingSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticsearchAutoscaler) DeepCopyInto(out *ElasticsearchAutoscaler) {
 	*out = *in
@@ -89,6 +123,13 @@ func (in *ElasticsearchAutoscalerSpec) DeepCopyInto(out *ElasticsearchAutoscaler
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.Schedules != nil {
+		in, out := &in.Schedules, &out.Schedules
+		*out = make([]AutoscalingSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAutoscalerSpec.
@@ -115,3 +156,64 @@ func (in *ElasticsearchRef) DeepCopy() *ElasticsearchRef {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleRecurrence) DeepCopyInto(out *ScheduleRecurrence) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.DaysOfWeek != nil {
+		in, out := &in.DaysOfWeek, &out.DaysOfWeek
+		*out = make([]Weekday, len(*in))
+		copy(*out, *in)
+	}
+	if in.DaysOfMonth != nil {
+		in, out := &in.DaysOfMonth, &out.DaysOfMonth
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleRecurrence.
+func (in *ScheduleRecurrence) DeepCopy() *ScheduleRecurrence {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleRecurrence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledResources) DeepCopyInto(out *ScheduledResources) {
+	*out = *in
+	if in.NodeCount != nil {
+		in, out := &in.NodeCount, &out.NodeCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledResources.
+func (in *ScheduledResources) DeepCopy() *ScheduledResources {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledResources)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -19,6 +19,11 @@ const (
 	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
 	// we duplicate it as a constant here for practical purposes.
 	Kind = "Beat"
+
+	// DefaultConfigAnnotation opts a Beat of a well-known type into the default configuration of its type, along with
+	// the host mounts, environment variables and security context it requires, when neither config nor configRef is
+	// specified.
+	DefaultConfigAnnotation = "eck.k8s.elastic.co/default-config"
 )
 
 var (
@@ -29,7 +34,8 @@ var (
 type BeatSpec struct {
 	// Type is the type of the Beat to deploy (filebeat, metricbeat, heartbeat, auditbeat, journalbeat, packetbeat, and so on).
 	// Any string can be used, but well-known types will have the image field defaulted and have the appropriate
-	// Elasticsearch roles created automatically. It also allows for dashboard setup when combined with a `KibanaRef`.
+	// Elasticsearch roles created automatically. It also allows for dashboard setup when combined with a `KibanaRef`,
+	// and for a default configuration to be used when opted into with the `eck.k8s.elastic.co/default-config` annotation.
 	// +kubebuilder:validation:MaxLength=20
 	// +kubebuilder:validation:Pattern=[a-zA-Z0-9-]+
 	Type string `json:"type"`
@@ -50,6 +56,8 @@ type BeatSpec struct {
 	Image string `json:"image,omitempty"`
 
 	// Config holds the Beat configuration. At most one of [`Config`, `ConfigRef`] can be specified.
+	// If neither is specified and the `eck.k8s.elastic.co/default-config` annotation is set to `true`, well-known types
+	// use a default configuration, along with the host mounts, environment variables and security context it requires.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Config *commonv1.Config `json:"config,omitempty"`
@@ -457,6 +465,10 @@ func (b *Beat) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return b.Spec.Monitoring.Logs.ElasticsearchRefs
 }
 
+func (b *Beat) GetMonitoringMode() commonv1.MonitoringMode {
+	return b.Spec.Monitoring.GetMode()
+}
+
 func (b *Beat) MonitoringAssociation(esRef commonv1.ObjectSelector) commonv1.Association {
 	return &BeatMonitoringAssociation{
 		Beat: b,
@@ -125,5 +125,11 @@ func checkAssociations(b *Beat) field.ErrorList {
 }
 
 func checkMonitoring(b *Beat) field.ErrorList {
-	return validations.Validate(b, b.Spec.Version, validations.MinStackVersion)
+	errs := validations.Validate(b, b.Spec.Version, validations.MinStackVersion)
+	// Beats expose their metrics on a Unix socket which cannot be reached from the shared collector
+	if b.Spec.Monitoring.GetMode() == commonv1.SharedMonitoringMode {
+		errs = append(errs, field.NotSupported(field.NewPath("spec").Child("monitoring").Child("mode"), b.Spec.Monitoring.Mode,
+			[]commonv1.MonitoringMode{commonv1.SidecarsMonitoringMode, commonv1.CollectorMonitoringMode}))
+	}
+	return errs
 }
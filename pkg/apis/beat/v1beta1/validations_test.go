@@ -53,6 +53,38 @@ func Test_checkBeatType(t *testing.T) {
 	}
 }
 
+func Test_checkImageIfTypeUnknown(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		spec    BeatSpec
+		wantErr string
+	}{
+		{
+			name: "known type without image",
+			spec: BeatSpec{Type: "filebeat"},
+		},
+		{
+			name: "unknown type with image",
+			spec: BeatSpec{Type: "apachebeat", Image: "apachebeat:1.0.0"},
+		},
+		{
+			name:    "unknown type without image",
+			spec:    BeatSpec{Type: "apachebeat"},
+			wantErr: "Image is required if Beat type is not one of [filebeat, metricbeat, heartbeat, auditbeat, journalbeat, packetbeat]",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkImageIfTypeUnknown(&Beat{Spec: tt.spec})
+			if tt.wantErr == "" {
+				require.Empty(t, got)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Equal(t, tt.wantErr, got[0].Detail)
+		})
+	}
+}
+
 func Test_checkSpec(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -343,6 +375,35 @@ func Test_checkMonitoring(t *testing.T) {
 			},
 			want: nil,
 		},
+		{
+			name: "stack monitoring in the shared mode is not supported",
+			beat: &Beat{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "testbeat",
+					Namespace: "test",
+				},
+				Spec: BeatSpec{
+					Type:      "filebeat",
+					Version:   "9.1.0",
+					DaemonSet: &DaemonSetSpec{},
+					Monitoring: commonv1.Monitoring{
+						Mode: commonv1.SharedMonitoringMode,
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{
+								{
+									Name:      "es",
+									Namespace: "test",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: field.ErrorList{
+				field.NotSupported(field.NewPath("spec").Child("monitoring").Child("mode"), commonv1.SharedMonitoringMode,
+					[]commonv1.MonitoringMode{commonv1.SidecarsMonitoringMode, commonv1.CollectorMonitoringMode}),
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -116,6 +116,9 @@ const (
 	EPRConfigAnnotationNameBase    = "association.k8s.elastic.co/epr-conf"
 	PackageRegistryAssociationType = "package-registry"
 
+	ApmServerConfigAnnotationNameBase = "association.k8s.elastic.co/apm-conf"
+	ApmServerAssociationType          = "apm"
+
 	AssociationUnknown     AssociationStatus = ""
 	AssociationPending     AssociationStatus = "Pending"
 	AssociationEstablished AssociationStatus = "Established"
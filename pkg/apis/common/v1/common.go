@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"reflect"
 
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	v1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -451,6 +452,29 @@ func (p *PodDisruptionBudgetTemplate) IsSpecified() bool {
 		p.Spec.UnhealthyPodEvictionPolicy != nil)
 }
 
+// DefaultAutoscalingCPUUtilization is the default average CPU utilization targeted by a HorizontalPodAutoscaler.
+const DefaultAutoscalingCPUUtilization int32 = 80
+
+// HorizontalPodAutoscalerSpec defines a HorizontalPodAutoscaler managed by the operator to scale a Deployment.
+type HorizontalPodAutoscalerSpec struct {
+	// MinReplicas is the lower limit for the number of replicas. Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// MaxReplicas is the upper limit for the number of replicas. It cannot be lower than MinReplicas.
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Metrics used to compute the desired number of replicas. Defaults to an average CPU utilization of 80%.
+	// +kubebuilder:validation:Optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Behavior configures the scaling behavior in both up and down directions.
+	// +kubebuilder:validation:Optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
 // NamespacedSecretSource defines a data source based on a Kubernetes Secret in a given namespace.
 type NamespacedSecretSource struct {
 	// Namespace is the namespace of the secret.
@@ -4,6 +4,19 @@
 
 package v1
 
+// MonitoringMode defines how monitoring data is collected from an Elastic Stack application.
+type MonitoringMode string
+
+const (
+	// SidecarsMonitoringMode collects metrics with a Metricbeat sidecar and logs with a Filebeat sidecar.
+	SidecarsMonitoringMode MonitoringMode = "Sidecars"
+	// CollectorMonitoringMode collects both metrics and logs with a single EDOT collector sidecar.
+	CollectorMonitoringMode MonitoringMode = "Collector"
+	// SharedMonitoringMode collects metrics with a collector Deployment shared by all the monitored resources of the
+	// namespace, which scrapes each resource through its Service. Logs cannot be collected in this mode.
+	SharedMonitoringMode MonitoringMode = "Shared"
+)
+
 // Monitoring holds references to both the metrics, and logs Elasticsearch clusters for
 // configuring stack monitoring.
 type Monitoring struct {
@@ -13,6 +26,20 @@ type Monitoring struct {
 	// Logs holds references to Elasticsearch clusters which receive log data from an associated resource.
 	// +kubebuilder:validation:Optional
 	Logs LogsMonitoring `json:"logs,omitempty"`
+	// Mode defines how monitoring data is collected: with Metricbeat and Filebeat sidecars (Sidecars), with a single
+	// EDOT collector sidecar (Collector), or with a collector Deployment shared by all the monitored resources of the
+	// namespace (Shared). Defaults to Sidecars.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Sidecars;Collector;Shared
+	Mode MonitoringMode `json:"mode,omitempty"`
+}
+
+// GetMode returns the monitoring mode, defaulting to Sidecars.
+func (m Monitoring) GetMode() MonitoringMode {
+	if m.Mode == "" {
+		return SidecarsMonitoringMode
+	}
+	return m.Mode
 }
 
 // MetricsMonitoring holds a list of Elasticsearch clusters which receive monitoring data from
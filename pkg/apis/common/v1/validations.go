@@ -15,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	common_name "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/name"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 )
@@ -76,6 +77,24 @@ func CheckSupportedStackVersion(ver string, supported version.MinMaxVersion) fie
 	return nil
 }
 
+// CheckImageCatalogVersion checks that the given version is in the image catalog of the operator, if the image catalog
+// pins the given image to digests. A custom image is not resolved through the catalog and is not restricted.
+func CheckImageCatalogVersion(ver string, img container.Image, customImage string) field.ErrorList {
+	if customImage != "" {
+		return nil
+	}
+	v, err := ParseVersion(ver)
+	if err != nil {
+		return err
+	}
+
+	if err := container.CheckCatalogVersion(img, *v); err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), ver, fmt.Sprintf("Unsupported version: %v", err))}
+	}
+
+	return nil
+}
+
 // CheckDeprecatedStackVersion checks that the given version is not deprecated.
 func CheckDeprecatedStackVersion(ver string) (string, field.ErrorList) {
 	v, err := ParseVersion(ver)
@@ -139,6 +158,24 @@ func CheckLocalAssociationRefs(path *field.Path, refs ...LocalObjectSelector) fi
 	return nil
 }
 
+// CheckHorizontalPodAutoscaler checks that the given HorizontalPodAutoscaler spec defines a valid range of replicas.
+func CheckHorizontalPodAutoscaler(path *field.Path, spec *HorizontalPodAutoscalerSpec) field.ErrorList {
+	if spec == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	if spec.MinReplicas != nil && *spec.MinReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("minReplicas"), *spec.MinReplicas, "minReplicas must be at least 1"))
+	}
+	if spec.MaxReplicas < 1 {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), spec.MaxReplicas, "maxReplicas must be at least 1"))
+	}
+	if spec.MinReplicas != nil && spec.MaxReplicas < *spec.MinReplicas {
+		errs = append(errs, field.Invalid(path.Child("maxReplicas"), spec.MaxReplicas, "maxReplicas cannot be lower than minReplicas"))
+	}
+	return errs
+}
+
 func ParseVersion(ver string) (*version.Version, field.ErrorList) {
 	v, err := version.Parse(ver)
 	if err != nil {
@@ -67,6 +67,108 @@ type AutoscalingPolicySpec struct {
 	NamedAutoscalingPolicy `json:",inline"`
 
 	AutoscalingResources `json:"resources"`
+
+	// Recommender selects how the resources managed by the policy are calculated. The capacity recommender, used by
+	// default, relies on the Elasticsearch autoscaling capacity API. The metrics recommender adjusts the number of nodes
+	// according to the node statistics collected by the operator, it does not require the Elasticsearch autoscaling API.
+	// +kubebuilder:validation:Optional
+	Recommender RecommenderType `json:"recommender,omitempty"`
+
+	// Metrics holds the settings of the metrics recommender.
+	// +kubebuilder:validation:Optional
+	Metrics *MetricsRecommenderSettings `json:"metrics,omitempty"`
+}
+
+// RecommenderType is the type of recommender used to calculate the resources of an autoscaling policy.
+// +kubebuilder:validation:Enum=capacity;metrics
+type RecommenderType string
+
+const (
+	// CapacityRecommender calculates resources from the Elasticsearch autoscaling capacity API.
+	CapacityRecommender RecommenderType = "capacity"
+	// MetricsRecommender calculates the number of nodes from the Elasticsearch node statistics.
+	MetricsRecommender RecommenderType = "metrics"
+)
+
+const (
+	DefaultTargetCPUPercent  int32 = 70
+	DefaultTargetHeapPercent int32 = 75
+	DefaultTargetDiskPercent int32 = 80
+	DefaultTargetQueueSize   int32 = 10
+)
+
+// MetricsRecommenderSettings holds the target utilization of the nodes managed by an autoscaling policy using the
+// metrics recommender. Nodes are added as soon as a metric, averaged over the nodes of the policy, exceeds its target.
+// Nodes are removed one at a time, as long as all the metrics remain below their target without this node.
+type MetricsRecommenderSettings struct {
+	// TargetCPUPercent is the target CPU usage of the nodes, in percent. Defaults to 70.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetCPUPercent *int32 `json:"targetCPUPercent,omitempty"`
+	// TargetHeapPercent is the target JVM heap usage of the nodes, in percent. Defaults to 75.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetHeapPercent *int32 `json:"targetHeapPercent,omitempty"`
+	// TargetDiskPercent is the target disk usage of the nodes, in percent. Defaults to 80.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	TargetDiskPercent *int32 `json:"targetDiskPercent,omitempty"`
+	// TargetSearchQueue is the target number of queued search requests per node. Defaults to 10.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TargetSearchQueue *int32 `json:"targetSearchQueue,omitempty"`
+	// TargetWriteQueue is the target number of queued write requests per node. Defaults to 10.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	TargetWriteQueue *int32 `json:"targetWriteQueue,omitempty"`
+}
+
+// UsesMetricsRecommender returns true if the resources of the policy are calculated by the metrics recommender.
+func (aps AutoscalingPolicySpec) UsesMetricsRecommender() bool {
+	return aps.Recommender == MetricsRecommender
+}
+
+// CPUPercent returns the target CPU usage, or the default one.
+func (s *MetricsRecommenderSettings) CPUPercent() int32 {
+	if s == nil || s.TargetCPUPercent == nil {
+		return DefaultTargetCPUPercent
+	}
+	return *s.TargetCPUPercent
+}
+
+// HeapPercent returns the target JVM heap usage, or the default one.
+func (s *MetricsRecommenderSettings) HeapPercent() int32 {
+	if s == nil || s.TargetHeapPercent == nil {
+		return DefaultTargetHeapPercent
+	}
+	return *s.TargetHeapPercent
+}
+
+// DiskPercent returns the target disk usage, or the default one.
+func (s *MetricsRecommenderSettings) DiskPercent() int32 {
+	if s == nil || s.TargetDiskPercent == nil {
+		return DefaultTargetDiskPercent
+	}
+	return *s.TargetDiskPercent
+}
+
+// SearchQueue returns the target search queue size, or the default one.
+func (s *MetricsRecommenderSettings) SearchQueue() int32 {
+	if s == nil || s.TargetSearchQueue == nil {
+		return DefaultTargetQueueSize
+	}
+	return *s.TargetSearchQueue
+}
+
+// WriteQueue returns the target write queue size, or the default one.
+func (s *MetricsRecommenderSettings) WriteQueue() int32 {
+	if s == nil || s.TargetWriteQueue == nil {
+		return DefaultTargetQueueSize
+	}
+	return *s.TargetWriteQueue
 }
 
 // AutoscalingResources model the limits, submitted by the user, for the supported resources in an autoscaling policy.
@@ -20,6 +20,7 @@ const (
 	EmptyResponse                 AutoscalingEventType = "EmptyResponse"
 	HorizontalScalingLimitReached AutoscalingEventType = "HorizontalScalingLimitReached"
 	MemoryRequired                AutoscalingEventType = "MemoryRequired"
+	MetricsUnavailable            AutoscalingEventType = "MetricsUnavailable"
 	NoNodeSet                     AutoscalingEventType = "NoNodeSet"
 	OverlappingPolicies           AutoscalingEventType = "OverlappingPolicies"
 	StorageRequired               AutoscalingEventType = "StorageRequired"
@@ -61,6 +62,21 @@ type ElasticsearchAutoscalerStatus struct {
 	// AutoscalingPolicyStatuses is used to expose state messages to user or external system.
 	// +kubebuilder:validation:Optional
 	AutoscalingPolicyStatuses []AutoscalingPolicyStatus `json:"policies"`
+	// ActiveSchedules are the schedules currently raising the minimum resources of some autoscaling policies.
+	// +kubebuilder:validation:Optional
+	ActiveSchedules []ActiveSchedule `json:"activeSchedules,omitempty"`
+}
+
+// ActiveSchedule describes the window of a schedule in progress.
+type ActiveSchedule struct {
+	// Name is the name of the schedule.
+	Name string `json:"name"`
+	// Policies are the names of the autoscaling policies to which the schedule applies.
+	Policies []string `json:"policies,omitempty"`
+	// Start is the beginning of the current window.
+	Start metav1.Time `json:"start"`
+	// End is the end of the current window.
+	End metav1.Time `json:"end"`
 }
 
 type AutoscalingPolicyStatus struct {
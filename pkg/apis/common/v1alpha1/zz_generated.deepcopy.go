@@ -12,6 +12,28 @@ import (
 	"k8s.io/api/core/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActiveSchedule) DeepCopyInto(out *ActiveSchedule) {
+	*out = *in
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActiveSchedule.
+func (in *ActiveSchedule) DeepCopy() *ActiveSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ActiveSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutoscalingPolicy) DeepCopyInto(out *AutoscalingPolicy) {
 	*out = *in
@@ -55,6 +77,11 @@ func (in *AutoscalingPolicySpec) DeepCopyInto(out *AutoscalingPolicySpec) {
 	*out = *in
 	in.NamedAutoscalingPolicy.DeepCopyInto(&out.NamedAutoscalingPolicy)
 	in.AutoscalingResources.DeepCopyInto(&out.AutoscalingResources)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsRecommenderSettings)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingPolicySpec.
@@ -222,6 +249,13 @@ func (in *ElasticsearchAutoscalerStatus) DeepCopyInto(out *ElasticsearchAutoscal
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ActiveSchedules != nil {
+		in, out := &in.ActiveSchedules, &out.ActiveSchedules
+		*out = make([]ActiveSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchAutoscalerStatus.
@@ -234,6 +268,46 @@ func (in *ElasticsearchAutoscalerStatus) DeepCopy() *ElasticsearchAutoscalerStat
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsRecommenderSettings) DeepCopyInto(out *MetricsRecommenderSettings) {
+	*out = *in
+	if in.TargetCPUPercent != nil {
+		in, out := &in.TargetCPUPercent, &out.TargetCPUPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetHeapPercent != nil {
+		in, out := &in.TargetHeapPercent, &out.TargetHeapPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetDiskPercent != nil {
+		in, out := &in.TargetDiskPercent, &out.TargetDiskPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetSearchQueue != nil {
+		in, out := &in.TargetSearchQueue, &out.TargetSearchQueue
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetWriteQueue != nil {
+		in, out := &in.TargetWriteQueue, &out.TargetWriteQueue
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsRecommenderSettings.
+func (in *MetricsRecommenderSettings) DeepCopy() *MetricsRecommenderSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsRecommenderSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamedAutoscalingPolicy) DeepCopyInto(out *NamedAutoscalingPolicy) {
 	*out = *in
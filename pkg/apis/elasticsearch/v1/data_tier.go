@@ -0,0 +1,107 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"slices"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+// DataTier is the name of an Elasticsearch data tier.
+// +kubebuilder:validation:Enum=hot;warm;cold;frozen;content
+type DataTier string
+
+const (
+	HotTier     DataTier = "hot"
+	WarmTier    DataTier = "warm"
+	ColdTier    DataTier = "cold"
+	FrozenTier  DataTier = "frozen"
+	ContentTier DataTier = "content"
+
+	// SearchableSnapshotSharedCacheSize is the setting controlling the size of the shared cache used by partially
+	// mounted indices on frozen nodes.
+	SearchableSnapshotSharedCacheSize = "xpack.searchable.snapshot.shared_cache.size"
+
+	// SharedCacheToDataVolumeRatio is the fraction of the data volume claim allocated to the searchable snapshot
+	// shared cache on frozen nodes. It leaves some headroom on the volume for the node's own metadata.
+	SharedCacheToDataVolumeRatio = 0.9
+)
+
+var (
+	// DataTiersMinVersion is the first version supporting the data_hot, data_warm, data_cold and data_content roles.
+	DataTiersMinVersion = version.MinFor(7, 10, 0)
+	// FrozenTierMinVersion is the first version supporting the data_frozen role.
+	FrozenTierMinVersion = version.MinFor(7, 12, 0)
+)
+
+// NodeRoles returns the node roles assigned by default to the nodes of the tier.
+// The hot tier also holds the content tier, which is the default tier for indices not part of a data stream.
+func (t DataTier) NodeRoles() []string {
+	switch t {
+	case HotTier:
+		return []string{string(DataHotRole), string(DataContentRole)}
+	case WarmTier:
+		return []string{string(DataWarmRole)}
+	case ColdTier:
+		return []string{string(DataColdRole)}
+	case FrozenTier:
+		return []string{string(DataFrozenRole)}
+	case ContentTier:
+		return []string{string(DataContentRole)}
+	}
+	return nil
+}
+
+// Role returns the node role that identifies the tier.
+func (t DataTier) Role() NodeRole {
+	switch t {
+	case HotTier:
+		return DataHotRole
+	case WarmTier:
+		return DataWarmRole
+	case ColdTier:
+		return DataColdRole
+	case FrozenTier:
+		return DataFrozenRole
+	case ContentTier:
+		return DataContentRole
+	}
+	return DataRole
+}
+
+// MinVersion returns the minimum Elasticsearch version supporting the tier.
+func (t DataTier) MinVersion() version.Version {
+	if t == FrozenTier {
+		return FrozenTierMinVersion
+	}
+	return DataTiersMinVersion
+}
+
+// SupportsSearchableSnapshots returns true if searchable snapshots can be mounted on the tier.
+func (t DataTier) SupportsSearchableSnapshots() bool {
+	return t == ColdTier || t == FrozenTier
+}
+
+// SearchableSnapshotRepositories returns the names of the snapshot repositories referenced by the NodeSets, sorted and
+// deduplicated.
+func (nsl NodeSetList) SearchableSnapshotRepositories() []string {
+	repositories := make(map[string]struct{})
+	for _, nodeSet := range nsl {
+		if nodeSet.SearchableSnapshotRepository == "" {
+			continue
+		}
+		repositories[nodeSet.SearchableSnapshotRepository] = struct{}{}
+	}
+	if len(repositories) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(repositories))
+	for name := range repositories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -53,6 +53,16 @@ const (
 	// RestartAllocationDelayAnnotation configures the allocation_delay passed to the Elasticsearch node shutdown
 	// API during rolling restarts and upgrades. The value must be a valid Go duration string (e.g. "5m", "1h").
 	RestartAllocationDelayAnnotation = "eck.k8s.elastic.co/restart-allocation-delay"
+	// DiagnosticsTriggerAnnotation allows users to request a diagnostics bundle by setting or changing this annotation
+	// value on the Elasticsearch resource. The bundle is stored in the <cluster>-es-diagnostics Secret.
+	DiagnosticsTriggerAnnotation = "eck.k8s.elastic.co/diagnostics-trigger"
+	// DiagnosticsIntervalAnnotation configures the periodic collection of a diagnostics bundle. The value must be a
+	// valid Go duration string (e.g. "1h", "24h").
+	DiagnosticsIntervalAnnotation = "eck.k8s.elastic.co/diagnostics-interval"
+	// AdoptVolumesFromAnnotation names a deleted Elasticsearch cluster of the same namespace whose Released
+	// PersistentVolumes are adopted by the nodes of this cluster when it is created. The UUID of the deleted cluster
+	// must be set with the elasticsearch.k8s.elastic.co/cluster-uuid annotation.
+	AdoptVolumesFromAnnotation = "eck.k8s.elastic.co/adopt-volumes-from"
 
 	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
 	// we duplicate it as a constant here for practical purposes.
@@ -94,6 +104,26 @@ func GetRestartAllocationDelayAnnotation(annotations map[string]string) (*time.D
 	return nil, nil
 }
 
+// GetDiagnosticsIntervalAnnotation reads the DiagnosticsIntervalAnnotation from the given annotations and returns the
+// parsed duration. Returns 0 if the annotation is absent or empty.
+// Returns an error if the value is not a valid Go duration string or is not positive.
+func GetDiagnosticsIntervalAnnotation(annotations map[string]string) (time.Duration, error) {
+	v, found := annotations[DiagnosticsIntervalAnnotation]
+	if !found || v == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	switch {
+	case err != nil:
+		return 0, fmt.Errorf("error while parsing diagnostics-interval annotation %s: %w", v, err)
+	case d <= 0:
+		return 0, fmt.Errorf("diagnostics-interval annotation must be positive: %s", v)
+	}
+
+	return d, nil
+}
+
 // +kubebuilder:object:root=true
 
 // ElasticsearchList contains a list of Elasticsearch clusters
@@ -376,6 +406,23 @@ type NodeSet struct {
 	// +kubebuilder:validation:Optional
 	ZoneAwareness *ZoneAwareness `json:"zoneAwareness,omitempty"`
 
+	// Tier declares the data tier the nodes of this NodeSet belong to. Unless node.roles is set in Config, the operator
+	// assigns the roles of the tier to the nodes. Nodes of the frozen tier also get their searchable snapshot shared
+	// cache sized from the elasticsearch-data volume claim.
+	// +kubebuilder:validation:Optional
+	Tier DataTier `json:"tier,omitempty"`
+
+	// SearchableSnapshotRepository is the name of the snapshot repository backing the searchable snapshots mounted on
+	// the nodes of this NodeSet, for example a repository declared in a StackConfigPolicy. Only allowed on the cold and
+	// frozen tiers. The operator reports an event if the repository is not registered in the cluster.
+	// +kubebuilder:validation:Optional
+	SearchableSnapshotRepository string `json:"searchableSnapshotRepository,omitempty"`
+
+	// JVMHeap sizes the JVM heap of the Elasticsearch nodes from the memory limit of the Elasticsearch container.
+	// When set, -Xms and -Xmx must not be set in ES_JAVA_OPTS.
+	// +kubebuilder:validation:Optional
+	JVMHeap *JVMHeap `json:"jvmHeap,omitempty"`
+
 	// PodTemplate provides customisation options (labels, annotations, affinity rules, resource requests, and so on) for the Pods belonging to this NodeSet.
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
@@ -393,6 +440,20 @@ const (
 	DefaultZoneAwarenessTopologyKey = corev1.LabelTopologyZone
 )
 
+// ZoneAwarenessMode defines how the operator enforces the distribution of shards across zones.
+// +kubebuilder:validation:Enum=Attributes;Forced
+type ZoneAwarenessMode string
+
+const (
+	// ZoneAwarenessAttributesMode only configures the zone node attribute and the allocation awareness attributes.
+	// Elasticsearch may allocate all copies of a shard to the remaining zones when a zone is lost.
+	ZoneAwarenessAttributesMode ZoneAwarenessMode = "Attributes"
+	// ZoneAwarenessForcedMode additionally manages forced awareness through the cluster settings API, so that replicas
+	// of a zone that is lost are left unassigned instead of overloading the remaining zones. The operator also refuses
+	// to remove the last data node of a tier from a zone.
+	ZoneAwarenessForcedMode ZoneAwarenessMode = "Forced"
+)
+
 // ZoneAwareness configures topology-aware scheduling and shard-awareness defaults for a NodeSet.
 // The operator provides sensible defaults for topology spread constraints (maxSkew=1,
 // whenUnsatisfiable=DoNotSchedule). To customize these, provide a matching topology spread
@@ -409,6 +470,18 @@ type ZoneAwareness struct {
 	// +kubebuilder:validation:MinItems=1
 	// +listType=set
 	Zones []string `json:"zones,omitempty"`
+
+	// Mode defines how shards are balanced across zones. With the Forced mode, the operator sets
+	// cluster.routing.allocation.awareness.force.zone.values to the declared zones through the cluster settings API,
+	// and prevents a scale down from leaving a zone without any data node of a tier. Zones must be set in that case.
+	// Defaults to Attributes.
+	// +kubebuilder:validation:Optional
+	Mode ZoneAwarenessMode `json:"mode,omitempty"`
+}
+
+// IsForced returns true if the forced zone awareness mode is enabled.
+func (za ZoneAwareness) IsForced() bool {
+	return za.Mode == ZoneAwarenessForcedMode
 }
 
 // TopologyKeyOrDefault returns the configured topology key or the default.
@@ -455,6 +528,24 @@ func (nsl NodeSetList) ZoneAwarenessTopologyKey() string {
 	return DefaultZoneAwarenessTopologyKey
 }
 
+// ForcedAwarenessZones returns the sorted list of zones declared by the NodeSets when any of them enables the forced
+// zone awareness mode, or nil otherwise.
+func (nsl NodeSetList) ForcedAwarenessZones() []string {
+	forced := false
+	zones := set.Make()
+	for _, nodeSet := range nsl {
+		if nodeSet.ZoneAwareness == nil {
+			continue
+		}
+		forced = forced || nodeSet.ZoneAwareness.IsForced()
+		zones.MergeWith(set.Make(nodeSet.ZoneAwareness.Zones...))
+	}
+	if !forced || zones.Count() == 0 {
+		return nil
+	}
+	return []string(zones.AsSortedSlice())
+}
+
 // GetESContainerTemplate returns the Elasticsearch container (if set) from the NodeSet's PodTemplate
 func (n NodeSet) GetESContainerTemplate() *corev1.Container {
 	for _, c := range n.PodTemplate.Spec.Containers {
@@ -757,6 +848,10 @@ func (es *Elasticsearch) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return es.Spec.Monitoring.Logs.ElasticsearchRefs
 }
 
+func (es *Elasticsearch) GetMonitoringMode() commonv1.MonitoringMode {
+	return es.Spec.Monitoring.GetMode()
+}
+
 func (es *Elasticsearch) MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association {
 	return &EsMonitoringAssociation{
 		Elasticsearch: es,
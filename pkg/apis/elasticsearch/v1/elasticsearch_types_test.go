@@ -490,6 +490,47 @@ func TestGetRestartAllocationDelayAnnotation(t *testing.T) {
 	}
 }
 
+func TestGetDiagnosticsIntervalAnnotation(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "annotation not present",
+			annotations: map[string]string{},
+			want:        0,
+		},
+		{
+			name:        "valid duration",
+			annotations: map[string]string{DiagnosticsIntervalAnnotation: "24h"},
+			want:        24 * time.Hour,
+		},
+		{
+			name:        "zero duration",
+			annotations: map[string]string{DiagnosticsIntervalAnnotation: "0s"},
+			wantErr:     true,
+		},
+		{
+			name:        "invalid duration",
+			annotations: map[string]string{DiagnosticsIntervalAnnotation: "daily"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetDiagnosticsIntervalAnnotation(tt.annotations)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 // Test_AssociationConfs tests that if the association configuration map in an associated object is cleared, then
 // AssociationConf() is rebuilt from the annotation.
 func Test_AssociationConfs(t *testing.T) {
@@ -572,3 +613,45 @@ func Test_AssociationConfs(t *testing.T) {
 	}
 	assert.Equal(t, 2, len(esMon.AssocConfs))
 }
+
+func TestNodeSetList_ForcedAwarenessZones(t *testing.T) {
+	tests := []struct {
+		name     string
+		nodeSets NodeSetList
+		want     []string
+	}{
+		{
+			name:     "no zone awareness",
+			nodeSets: NodeSetList{{Name: "default"}},
+			want:     nil,
+		},
+		{
+			name: "attributes mode",
+			nodeSets: NodeSetList{
+				{Name: "hot", ZoneAwareness: &ZoneAwareness{Zones: []string{"a", "b"}}},
+			},
+			want: nil,
+		},
+		{
+			name: "forced mode on one NodeSet: union of the zones of all zone-aware NodeSets",
+			nodeSets: NodeSetList{
+				{Name: "master"},
+				{Name: "hot", ZoneAwareness: &ZoneAwareness{Mode: ZoneAwarenessForcedMode, Zones: []string{"c", "a"}}},
+				{Name: "warm", ZoneAwareness: &ZoneAwareness{Zones: []string{"b", "a"}}},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "forced mode without zones",
+			nodeSets: NodeSetList{
+				{Name: "hot", ZoneAwareness: &ZoneAwareness{Mode: ZoneAwarenessForcedMode}},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.nodeSets.ForcedAwarenessZones())
+		})
+	}
+}
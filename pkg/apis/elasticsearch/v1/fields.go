@@ -33,8 +33,9 @@ const (
 	PathData = "path.data"
 	PathLogs = "path.logs"
 
-	ShardAwarenessAttributes = "cluster.routing.allocation.awareness.attributes"
-	NodeAttr                 = "node.attr"
+	ShardAwarenessAttributes      = "cluster.routing.allocation.awareness.attributes"
+	ShardAwarenessForceZoneValues = "cluster.routing.allocation.awareness.force.zone.values"
+	NodeAttr                      = "node.attr"
 
 	XPackSecurityAuthcRealmsFileFile1Order     = "xpack.security.authc.realms.file.file1.order"
 	XPackSecurityAuthcRealmsNativeNative1Order = "xpack.security.authc.realms.native.native1.order"
@@ -0,0 +1,69 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// DefaultJVMHeapMemoryLimitPercent is the share of the container memory limit allocated to the JVM heap when not
+	// specified. The remaining memory is left to the off-heap structures and to the filesystem cache.
+	DefaultJVMHeapMemoryLimitPercent int32 = 50
+)
+
+var (
+	// DefaultJVMHeapMax is the maximum JVM heap size when not specified. It keeps the heap below the threshold over
+	// which the JVM can no longer use compressed ordinary object pointers.
+	DefaultJVMHeapMax = resource.MustParse("31Gi")
+	// MinJVMHeap is the minimum JVM heap size, below which Elasticsearch cannot start.
+	MinJVMHeap = resource.MustParse("128Mi")
+)
+
+// JVMHeap configures the JVM heap size of the Elasticsearch nodes relative to the memory limit of the Elasticsearch
+// container. The operator sets the minimum and maximum heap size (-Xms and -Xmx) to the same value in ES_JAVA_OPTS.
+type JVMHeap struct {
+	// MemoryLimitPercent is the percentage of the Elasticsearch container memory limit allocated to the JVM heap.
+	// Defaults to 50.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=90
+	MemoryLimitPercent *int32 `json:"memoryLimitPercent,omitempty"`
+
+	// Max is the maximum JVM heap size, regardless of the memory limit. Defaults to 31Gi, to stay below the compressed
+	// ordinary object pointers threshold. Must be at least 128Mi.
+	// +kubebuilder:validation:Optional
+	Max *resource.Quantity `json:"max,omitempty"`
+}
+
+// MemoryLimitPercentOrDefault returns the configured percentage of the memory limit or the default.
+func (h JVMHeap) MemoryLimitPercentOrDefault() int32 {
+	if h.MemoryLimitPercent == nil {
+		return DefaultJVMHeapMemoryLimitPercent
+	}
+	return *h.MemoryLimitPercent
+}
+
+// MaxOrDefault returns the configured maximum heap size or the default.
+func (h JVMHeap) MaxOrDefault() resource.Quantity {
+	if h.Max == nil {
+		return DefaultJVMHeapMax
+	}
+	return *h.Max
+}
+
+// HeapSize returns the JVM heap size for the given container memory limit, rounded down to the mebibyte and never
+// below MinJVMHeap.
+func (h JVMHeap) HeapSize(memoryLimit resource.Quantity) resource.Quantity {
+	heap := memoryLimit.Value() * int64(h.MemoryLimitPercentOrDefault()) / 100
+	if maxHeap := h.MaxOrDefault(); heap > maxHeap.Value() {
+		heap = maxHeap.Value()
+	}
+	heap -= heap % (1024 * 1024)
+	if heap < MinJVMHeap.Value() {
+		heap = MinJVMHeap.Value()
+	}
+	return *resource.NewQuantity(heap, resource.BinarySI)
+}
@@ -31,6 +31,7 @@ const (
 	licenseSecretSuffix                          = "license"
 	defaultPodDisruptionBudget                   = "default"
 	scriptsConfigMapSuffix                       = "scripts"
+	diagnosticsSecretSuffix                      = "diagnostics"
 	legacyTransportCertsSecretSuffix             = "transport-certificates"
 	statefulSetTransportCertificatesSecretSuffix = "transport-certs"
 
@@ -62,6 +63,7 @@ var (
 		licenseSecretSuffix,
 		defaultPodDisruptionBudget,
 		scriptsConfigMapSuffix,
+		diagnosticsSecretSuffix,
 		statefulSetTransportCertificatesSecretSuffix,
 		remoteCaNameSuffix,
 		remoteAPIKeysNameSuffix,
@@ -170,6 +172,11 @@ func ScriptsConfigMap(esName string) string {
 	return ESNamer.Suffix(esName, scriptsConfigMapSuffix)
 }
 
+// DiagnosticsSecretName returns the name of the Secret holding the diagnostics bundle of the cluster.
+func DiagnosticsSecretName(esName string) string {
+	return ESNamer.Suffix(esName, diagnosticsSecretSuffix)
+}
+
 func LicenseSecretName(esName string) string {
 	return ESNamer.Suffix(esName, licenseSecretSuffix)
 }
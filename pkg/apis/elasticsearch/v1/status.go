@@ -107,9 +107,11 @@ func (es *Elasticsearch) SetAssociationStatusMap(typ commonv1.AssociationType, s
 
 const (
 	ElasticsearchIsReachable v1alpha1.ConditionType = "ElasticsearchIsReachable"
+	LicenseExpiring          v1alpha1.ConditionType = "LicenseExpiring"
 	ReconciliationComplete   v1alpha1.ConditionType = "ReconciliationComplete"
 	ResourcesAwareManagement v1alpha1.ConditionType = "ResourcesAwareManagement"
 	RunningDesiredVersion    v1alpha1.ConditionType = "RunningDesiredVersion"
+	ZonesBalanced            v1alpha1.ConditionType = "ZonesBalanced"
 )
 
 // NewNodeStatus provides details about the status of nodes which are expected to be created and added to the Elasticsearch cluster.
@@ -414,6 +414,31 @@ func (in *InProgressOperations) DeepCopy() *InProgressOperations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JVMHeap) DeepCopyInto(out *JVMHeap) {
+	*out = *in
+	if in.MemoryLimitPercent != nil {
+		in, out := &in.MemoryLimitPercent, &out.MemoryLimitPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Max != nil {
+		in, out := &in.Max, &out.Max
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JVMHeap.
+func (in *JVMHeap) DeepCopy() *JVMHeap {
+	if in == nil {
+		return nil
+	}
+	out := new(JVMHeap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NewNode) DeepCopyInto(out *NewNode) {
 	*out = *in
@@ -501,6 +526,11 @@ func (in *NodeSet) DeepCopyInto(out *NodeSet) {
 		*out = new(ZoneAwareness)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.JVMHeap != nil {
+		in, out := &in.JVMHeap, &out.JVMHeap
+		*out = new(JVMHeap)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PodTemplate.DeepCopyInto(&out.PodTemplate)
 	if in.VolumeClaimTemplates != nil {
 		in, out := &in.VolumeClaimTemplates, &out.VolumeClaimTemplates
@@ -63,6 +63,13 @@ type EnterpriseSearchSpec struct {
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// MigrationMode opts in to the migration off Enterprise Search, which is not available in version 9.0.0 and later.
+	// Enterprise Search is put in read-only mode, the App Search engines and Workplace Search content sources to move
+	// to Elasticsearch are reported in the status, then the Deployment is removed.
+	// The association to Elasticsearch is kept so that the data stored in Elasticsearch remains accessible.
+	// +kubebuilder:validation:Optional
+	MigrationMode bool `json:"migrationMode,omitempty"`
 }
 
 // EnterpriseSearchStatus defines the observed state of EnterpriseSearch
@@ -75,6 +82,10 @@ type EnterpriseSearchStatus struct {
 	// Association is the status of any auto-linking to Elasticsearch clusters.
 	Association commonv1.AssociationStatus `json:"associationStatus,omitempty"`
 
+	// Migration is the status of the migration off Enterprise Search, reported when MigrationMode is enabled.
+	// +kubebuilder:validation:Optional
+	Migration *MigrationStatus `json:"migration,omitempty"`
+
 	// ObservedGeneration represents the .metadata.generation that the status is based upon.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Enterprise Search
@@ -83,6 +94,32 @@ type EnterpriseSearchStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// MigrationPhase is the step of the migration off Enterprise Search.
+type MigrationPhase string
+
+const (
+	// MigrationPendingPhase means the migration waits for Enterprise Search to be running to enable read-only mode.
+	MigrationPendingPhase MigrationPhase = "Pending"
+	// MigrationReadOnlyPhase means Enterprise Search is in read-only mode and its content has been reported.
+	MigrationReadOnlyPhase MigrationPhase = "ReadOnly"
+	// MigrationCompletedPhase means the Enterprise Search Deployment has been removed.
+	MigrationCompletedPhase MigrationPhase = "Completed"
+)
+
+// MigrationStatus is the status of the migration off Enterprise Search.
+type MigrationStatus struct {
+	// Phase is the current step of the migration.
+	Phase MigrationPhase `json:"phase,omitempty"`
+
+	// Engines lists the App Search engines whose content needs to be moved to Elasticsearch.
+	// +kubebuilder:validation:Optional
+	Engines []string `json:"engines,omitempty"`
+
+	// ContentSources lists the Workplace Search content sources whose content needs to be moved to Elasticsearch.
+	// +kubebuilder:validation:Optional
+	ContentSources []string `json:"contentSources,omitempty"`
+}
+
 // IsMarkedForDeletion returns true if the EnterpriseSearch is going to be deleted
 func (ent *EnterpriseSearch) IsMarkedForDeletion() bool {
 	return !ent.DeletionTimestamp.IsZero()
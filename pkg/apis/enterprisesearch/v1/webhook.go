@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/admission"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
@@ -21,16 +22,23 @@ import (
 const (
 	// webhookPath is the HTTP path for the Enterprise Search validating webhook.
 	webhookPath = "/validate-enterprisesearch-k8s-elastic-co-v1-enterprisesearch"
+
+	unavailableVersionMsg = "Enterprise Search is not available in version 9.0.0 and later: " +
+		"keep a version prior to 9.0.0 and set spec.migrationMode to migrate its content to Elasticsearch"
 )
 
 var (
 	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
 	validationLog = ulog.Log.WithName("enterprisesearch-v1-validation")
 
+	// unavailableVersion is the first version in which Enterprise Search is not available anymore.
+	unavailableVersion = version.MinFor(9, 0, 0)
+
 	defaultChecks = []func(*EnterpriseSearch) field.ErrorList{
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
 		checkAssociation,
 	}
 
@@ -120,9 +128,16 @@ func checkNameLength(ent *EnterpriseSearch) field.ErrorList {
 }
 
 func checkSupportedVersion(ent *EnterpriseSearch) field.ErrorList {
+	if ver, err := version.Parse(ent.Spec.Version); err == nil && ver.GTE(unavailableVersion) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), ent.Spec.Version, unavailableVersionMsg)}
+	}
 	return commonv1.CheckSupportedStackVersion(ent.Spec.Version, version.SupportedEnterpriseSearchVersions)
 }
 
+func checkImageCatalogVersion(ent *EnterpriseSearch) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(ent.Spec.Version, container.EnterpriseSearchImage, ent.Spec.Image)
+}
+
 func checkIfVersionDeprecated(ent *EnterpriseSearch) (string, field.ErrorList) {
 	return commonv1.CheckDeprecatedStackVersion(ent.Spec.Version)
 }
@@ -109,9 +109,34 @@ func TestWebhook(t *testing.T) {
 				return serialize(t, ent)
 			},
 			Check: test.ValidationWebhookFailed(
-				`spec.version: Invalid value: "300.1.2": Unsupported version: version 300.1.2 is higher than the highest supported version`,
+				`spec.version: Invalid value: "300.1.2": Enterprise Search is not available in version 9.0.0 and later`,
 			),
 		},
+		{
+			Name:      "unavailable-version",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				ent := mkEnterpriseSearch(uid)
+				ent.Spec.Version = "9.0.0"
+				return serialize(t, ent)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.version: Invalid value: "9.0.0": Enterprise Search is not available in version 9.0.0 and later: ` +
+					`keep a version prior to 9.0.0 and set spec.migrationMode to migrate its content to Elasticsearch`,
+			),
+		},
+		{
+			Name:      "migration-mode",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				ent := mkEnterpriseSearch(uid)
+				ent.Spec.MigrationMode = true
+				return serialize(t, ent)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
 		{
 			Name:      "update-valid",
 			Operation: admissionv1.Update,
@@ -75,6 +75,11 @@ type KibanaSpec struct {
 	// Count of Kibana instances to deploy.
 	Count int32 `json:"count,omitempty"`
 
+	// Autoscaling defines a HorizontalPodAutoscaler managed by the operator to scale the Kibana Deployment.
+	// When set, Count is ignored and the number of instances is left to the HorizontalPodAutoscaler.
+	// +kubebuilder:validation:Optional
+	Autoscaling *commonv1.HorizontalPodAutoscalerSpec `json:"autoscaling,omitempty"`
+
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running in the same Kubernetes cluster.
 	ElasticsearchRef commonv1.ElasticsearchSelector `json:"elasticsearchRef,omitempty"`
 
@@ -114,6 +119,32 @@ type KibanaSpec struct {
 	// Elasticsearch monitoring clusters running in the same Kubernetes cluster.
 	// +kubebuilder:validation:Optional
 	Monitoring commonv1.Monitoring `json:"monitoring,omitempty"`
+
+	// UpgradeStrategy controls how Kibana instances are replaced when the version changes.
+	// +kubebuilder:validation:Optional
+	UpgradeStrategy UpgradeStrategy `json:"upgradeStrategy,omitempty"`
+}
+
+// UpgradeStrategyType is the type of strategy used to upgrade Kibana to a new version.
+// +kubebuilder:validation:Enum=Recreate;Parallel
+type UpgradeStrategyType string
+
+const (
+	// RecreateUpgradeStrategyType stops all Kibana instances in the prior version before starting the ones in the new version.
+	RecreateUpgradeStrategyType UpgradeStrategyType = "Recreate"
+	// ParallelUpgradeStrategyType starts Kibana instances in the new version next to the ones in the prior version,
+	// and only switches the traffic to them once they are available, after the saved objects migrations.
+	ParallelUpgradeStrategyType UpgradeStrategyType = "Parallel"
+)
+
+// UpgradeStrategy controls how Kibana instances are replaced when the version changes.
+type UpgradeStrategy struct {
+	// Type of upgrade strategy, either Recreate or Parallel. Defaults to Recreate, which causes Kibana to be unavailable
+	// while the saved objects migrations run. Parallel keeps the instances in the prior version serving requests until
+	// the ones in the new version are available, then scales them down. Saved objects are read-only from the start of the
+	// migrations until the instances in the prior version are scaled down. Requires Kibana 7.12.0+.
+	// +kubebuilder:validation:Optional
+	Type UpgradeStrategyType `json:"type,omitempty"`
 }
 
 // KibanaStatus defines the observed state of Kibana
@@ -143,6 +174,12 @@ type KibanaStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// IsParallelUpgrade returns true if Kibana instances in the new version must be started next to the ones in
+// the prior version during version upgrades.
+func (k *Kibana) IsParallelUpgrade() bool {
+	return k.Spec.UpgradeStrategy.Type == ParallelUpgradeStrategyType
+}
+
 // IsMarkedForDeletion returns true if the Kibana is going to be deleted
 func (k *Kibana) IsMarkedForDeletion() bool {
 	return !k.DeletionTimestamp.IsZero()
@@ -514,6 +551,10 @@ func (k *Kibana) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return k.Spec.Monitoring.Logs.ElasticsearchRefs
 }
 
+func (k *Kibana) GetMonitoringMode() commonv1.MonitoringMode {
+	return k.Spec.Monitoring.GetMode()
+}
+
 func (k *Kibana) MonitoringAssociation(esRef commonv1.ObjectSelector) commonv1.Association {
 	return &KbMonitoringAssociation{
 		Kibana: k,
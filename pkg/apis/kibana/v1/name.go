@@ -12,6 +12,7 @@ const (
 	httpServiceSuffix      = "http"
 	scriptsConfigMapSuffix = "scripts"
 	configSecretSuffix     = "config"
+	upgradeSuffix          = "upgrade"
 )
 
 // KBNamer is a KBNamer that is configured with the defaults for resources related to a Kibana resource.
@@ -34,3 +35,13 @@ func ScriptsConfigMap(kbName string) string {
 func ConfigSecret(kbName string) string {
 	return KBNamer.Suffix(kbName, configSecretSuffix)
 }
+
+// UpgradeDeployment returns the name of the Deployment running the new version during a parallel version upgrade.
+func UpgradeDeployment(kbName string) string {
+	return KBNamer.Suffix(kbName, upgradeSuffix)
+}
+
+// UpgradeHTTPService returns the name of the Service targeting the new version during a parallel version upgrade.
+func UpgradeHTTPService(kbName string) string {
+	return KBNamer.Suffix(kbName, upgradeSuffix, httpServiceSuffix)
+}
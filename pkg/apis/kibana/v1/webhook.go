@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
@@ -23,9 +24,15 @@ import (
 const (
 	// webhookPath is the HTTP path for the Kibana validating webhook.
 	webhookPath = "/validate-kibana-k8s-elastic-co-v1-kibana"
+
+	parallelUpgradeVersionMsg = "Parallel upgrade strategy requires version 7.12.0 or later"
 )
 
 var (
+	// parallelUpgradeMinVersion is the first version in which saved objects migrations block writes to the indices of
+	// the prior version, allowing both versions to run at the same time.
+	parallelUpgradeMinVersion = version.MinFor(7, 12, 0)
+
 	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
 	validationLog = ulog.Log.WithName("kibana-v1-validation")
 
@@ -33,8 +40,11 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
 		checkMonitoring,
 		checkAssociations,
+		checkUpgradeStrategy,
+		checkAutoscaling,
 	}
 
 	updateChecks = []func(old, curr *Kibana) field.ErrorList{
@@ -125,6 +135,10 @@ func checkSupportedVersion(k *Kibana) field.ErrorList {
 	return commonv1.CheckSupportedStackVersion(k.Spec.Version, version.SupportedKibanaVersions)
 }
 
+func checkImageCatalogVersion(k *Kibana) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(k.Spec.Version, container.KibanaImage, k.Spec.Image)
+}
+
 func checkIfVersionDeprecated(k *Kibana) (string, field.ErrorList) {
 	return commonv1.CheckDeprecatedStackVersion(k.Spec.Version)
 }
@@ -155,3 +169,23 @@ func checkAssociations(k *Kibana) field.ErrorList {
 	err5 := commonv1.CheckLocalAssociationRefs(field.NewPath("spec").Child("packageRegistryRef"), k.Spec.PackageRegistryRef)
 	return append(err1, append(err2, append(err3, append(err4, err5...)...)...)...)
 }
+
+func checkUpgradeStrategy(k *Kibana) field.ErrorList {
+	if !k.IsParallelUpgrade() {
+		return nil
+	}
+	ver, err := version.Parse(k.Spec.Version)
+	if err != nil {
+		// already reported by checkSupportedVersion
+		return nil
+	}
+	if !ver.GTE(parallelUpgradeMinVersion) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("upgradeStrategy").Child("type"), k.Spec.UpgradeStrategy.Type,
+			parallelUpgradeVersionMsg)}
+	}
+	return nil
+}
+
+func checkAutoscaling(k *Kibana) field.ErrorList {
+	return commonv1.CheckHorizontalPodAutoscaler(field.NewPath("spec").Child("autoscaling"), k.Spec.Autoscaling)
+}
@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
@@ -366,6 +367,55 @@ func TestWebhook(t *testing.T) {
 			},
 			Check: test.ValidationWebhookSucceeded,
 		},
+		{
+			Name:      "parallel-upgrade-strategy",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.UpgradeStrategy.Type = kbv1.ParallelUpgradeStrategyType
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "parallel-upgrade-strategy-unsupported-version",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Version = "7.11.2"
+				k.Spec.UpgradeStrategy.Type = kbv1.ParallelUpgradeStrategyType
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.upgradeStrategy.type: Invalid value: "Parallel": Parallel upgrade strategy requires version 7.12.0 or later`,
+			),
+		},
+		{
+			Name:      "autoscaling",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](2), MaxReplicas: 5}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "autoscaling-max-lower-than-min",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				k := mkKibana(uid)
+				k.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](3), MaxReplicas: 2}
+				return serialize(t, k)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.autoscaling.maxReplicas: Invalid value: 2: maxReplicas cannot be lower than minReplicas`,
+			),
+		},
 	}
 
 	validator := &kbv1.Kibana{}
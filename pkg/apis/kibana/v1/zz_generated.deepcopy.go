@@ -178,6 +178,11 @@ func (in *KibanaList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(commonv1.HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ElasticsearchRef = in.ElasticsearchRef
 	out.PackageRegistryRef = in.PackageRegistryRef
 	out.EnterpriseSearchRef = in.EnterpriseSearchRef
@@ -200,6 +205,7 @@ func (in *KibanaSpec) DeepCopyInto(out *KibanaSpec) {
 		}
 	}
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
+	out.UpgradeStrategy = in.UpgradeStrategy
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KibanaSpec.
@@ -234,3 +240,18 @@ func (in *KibanaStatus) DeepCopy() *KibanaStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStrategy) DeepCopyInto(out *UpgradeStrategy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStrategy.
+func (in *UpgradeStrategy) DeepCopy() *UpgradeStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -411,6 +411,10 @@ func (l *Logstash) GetMonitoringLogsRefs() []commonv1.ObjectSelector {
 	return l.Spec.Monitoring.Logs.ElasticsearchRefs
 }
 
+func (l *Logstash) GetMonitoringMode() commonv1.MonitoringMode {
+	return l.Spec.Monitoring.GetMode()
+}
+
 func (l *Logstash) MonitoringAssociation(esRef commonv1.ObjectSelector) commonv1.Association {
 	return &LogstashMonitoringAssociation{
 		Logstash: l,
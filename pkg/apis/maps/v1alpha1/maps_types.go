@@ -31,6 +31,11 @@ type MapsSpec struct {
 	// Count of Elastic Maps Server instances to deploy.
 	Count int32 `json:"count,omitempty"`
 
+	// Autoscaling defines a HorizontalPodAutoscaler managed by the operator to scale the Elastic Maps Server Deployment.
+	// When set, Count is ignored and the number of instances is left to the HorizontalPodAutoscaler.
+	// +kubebuilder:validation:Optional
+	Autoscaling *commonv1.HorizontalPodAutoscalerSpec `json:"autoscaling,omitempty"`
+
 	// ElasticsearchRef is a reference to an Elasticsearch cluster running in the same Kubernetes cluster.
 	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
 
@@ -58,6 +63,62 @@ type MapsSpec struct {
 	// Can only be used if ECK is enforcing RBAC on references.
 	// +optional
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// BasemapData defines where to get the basemap data served by Elastic Maps Server, for all zoom levels.
+	// Defaults to the basemap data bundled in the image, limited to the lowest zoom levels.
+	// +kubebuilder:validation:Optional
+	BasemapData *BasemapDataSource `json:"basemapData,omitempty"`
+}
+
+// BasemapDataSource is the source of the basemap data file (planet.mbtiles).
+// Exactly one of PersistentVolumeClaim and URL must be set.
+type BasemapDataSource struct {
+	// PersistentVolumeClaim references an existing PersistentVolumeClaim holding the basemap data file, in the same namespace.
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// Path of the basemap data file within the PersistentVolumeClaim. Defaults to `planet.mbtiles`.
+	// +kubebuilder:validation:Optional
+	Path string `json:"path,omitempty"`
+
+	// URL is the HTTP(S) URL from which the basemap data file is downloaded. Files in an object store such as S3 can be
+	// downloaded from a public or pre-signed HTTPS URL.
+	// +kubebuilder:validation:Optional
+	URL string `json:"url,omitempty"`
+
+	// Checksum is the SHA-256 checksum of the basemap data file, in the `sha256:<hex>` format.
+	// Required with URL, optional with PersistentVolumeClaim. The data file is not used if the checksum does not match.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Checksum string `json:"checksum,omitempty"`
+
+	// Cache references an existing PersistentVolumeClaim in which the file downloaded from URL is stored, to be reused
+	// across Pod restarts and by all the instances if the volume can be mounted by several Pods.
+	// Defaults to an emptyDir volume, the file being downloaded again by each new Pod.
+	// +kubebuilder:validation:Optional
+	Cache *corev1.PersistentVolumeClaimVolumeSource `json:"cache,omitempty"`
+}
+
+// BasemapDataPhase is the provisioning phase of the basemap data.
+type BasemapDataPhase string
+
+const (
+	// BasemapDataPendingPhase means the basemap data is being downloaded or verified.
+	BasemapDataPendingPhase BasemapDataPhase = "Pending"
+	// BasemapDataReadyPhase means the basemap data is verified and served.
+	BasemapDataReadyPhase BasemapDataPhase = "Ready"
+	// BasemapDataFailedPhase means the basemap data could not be downloaded or verified.
+	BasemapDataFailedPhase BasemapDataPhase = "Failed"
+)
+
+// BasemapDataStatus is the observed provisioning status of the basemap data.
+type BasemapDataStatus struct {
+	// Phase of the basemap data provisioning in the most recent Elastic Maps Server Pod.
+	Phase BasemapDataPhase `json:"phase,omitempty"`
+
+	// Message is the outcome of the last download or verification attempt.
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
 }
 
 // MapsStatus defines the observed state of Elastic Maps Server
@@ -66,6 +127,10 @@ type MapsStatus struct {
 
 	AssociationStatus commonv1.AssociationStatus `json:"associationStatus,omitempty"`
 
+	// BasemapData is the provisioning status of the basemap data, when a basemap data source is set.
+	// +kubebuilder:validation:Optional
+	BasemapData *BasemapDataStatus `json:"basemapData,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Elastic Maps Server.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Elastic
@@ -5,12 +5,17 @@
 package v1alpha1
 
 import (
+	"net/url"
+	"path"
+	"strings"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/admission"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
@@ -29,7 +34,10 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
 		checkAssociation,
+		checkAutoscaling,
+		checkBasemapData,
 	}
 )
 
@@ -102,6 +110,10 @@ func checkSupportedVersion(ems *ElasticMapsServer) field.ErrorList {
 	return commonv1.CheckSupportedStackVersion(ems.Spec.Version, version.SupportedMapsVersions)
 }
 
+func checkImageCatalogVersion(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(ems.Spec.Version, container.MapsImage, ems.Spec.Image)
+}
+
 func checkIfVersionDeprecated(ems *ElasticMapsServer) (string, field.ErrorList) {
 	return commonv1.CheckDeprecatedStackVersion(ems.Spec.Version)
 }
@@ -109,3 +121,37 @@ func checkIfVersionDeprecated(ems *ElasticMapsServer) (string, field.ErrorList)
 func checkAssociation(ems *ElasticMapsServer) field.ErrorList {
 	return commonv1.CheckAssociationRefs(field.NewPath("spec").Child("elasticsearchRef"), ems.Spec.ElasticsearchRef)
 }
+
+func checkAutoscaling(ems *ElasticMapsServer) field.ErrorList {
+	return commonv1.CheckHorizontalPodAutoscaler(field.NewPath("spec").Child("autoscaling"), ems.Spec.Autoscaling)
+}
+
+func checkBasemapData(ems *ElasticMapsServer) field.ErrorList {
+	source := ems.Spec.BasemapData
+	if source == nil {
+		return nil
+	}
+	var errs field.ErrorList
+	sourcePath := field.NewPath("spec").Child("basemapData")
+	if (source.PersistentVolumeClaim == nil) == (source.URL == "") {
+		errs = append(errs, field.Invalid(sourcePath, source, "exactly one of persistentVolumeClaim and url must be set"))
+	}
+	if source.URL != "" {
+		if u, err := url.Parse(source.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, field.Invalid(sourcePath.Child("url"), source.URL, "url must be a valid HTTP or HTTPS URL"))
+		}
+		if source.Checksum == "" {
+			errs = append(errs, field.Required(sourcePath.Child("checksum"), "checksum is required when downloading from url"))
+		}
+		if source.Path != "" {
+			errs = append(errs, field.Forbidden(sourcePath.Child("path"), "path can only be set with persistentVolumeClaim"))
+		}
+	}
+	if source.Cache != nil && source.URL == "" {
+		errs = append(errs, field.Forbidden(sourcePath.Child("cache"), "cache can only be set with url"))
+	}
+	if path.IsAbs(source.Path) || strings.Contains(source.Path, "..") {
+		errs = append(errs, field.Invalid(sourcePath.Child("path"), source.Path, "path must be a relative path within the volume"))
+	}
+	return errs
+}
@@ -14,6 +14,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/maps/v1alpha1"
@@ -164,6 +165,108 @@ func TestWebhook(t *testing.T) {
 				`spec.elasticsearchRef: Forbidden: Invalid association reference: serviceName or namespace can only be used in combination with name, not with secretName`,
 			),
 		},
+		{
+			Name:      "autoscaling",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](2), MaxReplicas: 5}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "autoscaling-max-lower-than-min",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](3), MaxReplicas: 2}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.autoscaling.maxReplicas: Invalid value: 2: maxReplicas cannot be lower than minReplicas`,
+			),
+		},
+		{
+			Name:      "basemap-data-url",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.BasemapData = &emsv1alpha1.BasemapDataSource{
+					URL:      "https://mirror.local/planet.mbtiles",
+					Checksum: "sha256:" + strings.Repeat("a", 64),
+					Cache:    &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap-cache"},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "basemap-data-pvc",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.BasemapData = &emsv1alpha1.BasemapDataSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap"},
+					Path:                  "data/planet.mbtiles",
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "basemap-data-invalid-url",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.BasemapData = &emsv1alpha1.BasemapDataSource{
+					URL:  "s3://bucket/planet.mbtiles",
+					Path: "planet.mbtiles",
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`url must be a valid HTTP or HTTPS URL`,
+				`checksum is required when downloading from url`,
+				`path can only be set with persistentVolumeClaim`,
+			),
+		},
+		{
+			Name:      "basemap-data-invalid-pvc",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.BasemapData = &emsv1alpha1.BasemapDataSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap"},
+					Path:                  "../planet.mbtiles",
+					Cache:                 &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap-cache"},
+				}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`path must be a relative path within the volume`,
+				`cache can only be set with url`,
+			),
+		},
+		{
+			Name:      "basemap-data-no-source",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				m := mkMaps(uid)
+				m.Spec.BasemapData = &emsv1alpha1.BasemapDataSource{}
+				return serialize(t, m)
+			},
+			Check: test.ValidationWebhookFailed(
+				`exactly one of persistentVolumeClaim and url must be set`,
+			),
+		},
 	}
 
 	validator := &emsv1alpha1.ElasticMapsServer{}
@@ -10,16 +10,57 @@ package v1alpha1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasemapDataSource) DeepCopyInto(out *BasemapDataSource) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasemapDataSource.
+func (in *BasemapDataSource) DeepCopy() *BasemapDataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BasemapDataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BasemapDataStatus) DeepCopyInto(out *BasemapDataStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BasemapDataStatus.
+func (in *BasemapDataStatus) DeepCopy() *BasemapDataStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BasemapDataStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ElasticMapsServer) DeepCopyInto(out *ElasticMapsServer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	if in.assocConf != nil {
 		in, out := &in.assocConf, &out.assocConf
 		*out = new(v1.AssociationConf)
@@ -80,6 +121,11 @@ func (in *ElasticMapsServerList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MapsSpec) DeepCopyInto(out *MapsSpec) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(v1.HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.ElasticsearchRef = in.ElasticsearchRef
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
@@ -97,6 +143,11 @@ func (in *MapsSpec) DeepCopyInto(out *MapsSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.BasemapData != nil {
+		in, out := &in.BasemapData, &out.BasemapData
+		*out = new(BasemapDataSource)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapsSpec.
@@ -113,6 +164,11 @@ func (in *MapsSpec) DeepCopy() *MapsSpec {
 func (in *MapsStatus) DeepCopyInto(out *MapsStatus) {
 	*out = *in
 	out.DeploymentStatus = in.DeploymentStatus
+	if in.BasemapData != nil {
+		in, out := &in.BasemapData, &out.BasemapData
+		*out = new(BasemapDataStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MapsStatus.
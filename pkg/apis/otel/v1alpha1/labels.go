@@ -0,0 +1,25 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+)
+
+const (
+	// TypeLabelValue represents the OpenTelemetry Collector type.
+	TypeLabelValue = "otel-collector"
+
+	// NameLabelName used to represent an OpenTelemetry Collector in k8s resources
+	NameLabelName = "otel.k8s.elastic.co/name"
+)
+
+// GetIdentityLabels will return the common Elastic assigned labels for the OpenTelemetry Collector.
+func (c *OTelCollector) GetIdentityLabels() map[string]string {
+	return map[string]string{
+		commonv1.TypeLabelName: TypeLabelValue,
+		NameLabelName:          c.Name,
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+)
+
+const (
+	// OTelCollectorContainerName is the name of the main container in the OpenTelemetry Collector Pods.
+	OTelCollectorContainerName = "otel-collector"
+	// Kind is inferred from the struct name using reflection in SchemeBuilder.Register()
+	// we duplicate it as a constant here for practical purposes.
+	Kind = "OTelCollector"
+)
+
+// OTelCollectorSpec defines the desired state of an Elastic Distribution of OpenTelemetry (EDOT) Collector.
+type OTelCollectorSpec struct {
+	// Version of the EDOT Collector.
+	Version string `json:"version"`
+
+	// Image is the EDOT Collector Docker image to deploy. Version has to match the EDOT Collector in the image.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// ElasticsearchRef is a reference to an Elasticsearch cluster running in the same Kubernetes cluster.
+	// When set, an `elasticsearch` exporter is configured with the Elasticsearch URL, credentials and CA certificate,
+	// to be referenced in the pipelines of the collector configuration.
+	// +kubebuilder:validation:Optional
+	ElasticsearchRef commonv1.ObjectSelector `json:"elasticsearchRef,omitempty"`
+
+	// ApmServerRef is a reference to an APM Server running in the same Kubernetes cluster.
+	// When set, an `otlphttp/apm` exporter is configured with the APM Server URL, secret token and CA certificate,
+	// to be referenced in the pipelines of the collector configuration.
+	// +kubebuilder:validation:Optional
+	ApmServerRef commonv1.ObjectSelector `json:"apmServerRef,omitempty"`
+
+	// Config holds the EDOT Collector configuration. At most one of [`Config`, `ConfigRef`] can be specified.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Config *commonv1.Config `json:"config,omitempty"`
+
+	// ConfigRef contains a reference to an existing Kubernetes Secret holding the EDOT Collector configuration.
+	// Collector settings must be specified as yaml, under a single "otel.yml" entry. At most one of [`Config`, `ConfigRef`]
+	// can be specified.
+	// +kubebuilder:validation:Optional
+	ConfigRef *commonv1.ConfigSource `json:"configRef,omitempty"`
+
+	// ServiceAccountName is used to check access from the current resource to an Elasticsearch or APM Server resource
+	// in a different namespace.
+	// Can only be used if ECK is enforcing RBAC on references.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// DaemonSet specifies the EDOT Collector should be deployed as a DaemonSet, and allows providing its spec.
+	// Cannot be used along with `deployment` or `statefulSet`.
+	// +kubebuilder:validation:Optional
+	DaemonSet *DaemonSetSpec `json:"daemonSet,omitempty"`
+
+	// Deployment specifies the EDOT Collector should be deployed as a Deployment, and allows providing its spec.
+	// Cannot be used along with `daemonSet` or `statefulSet`.
+	// +kubebuilder:validation:Optional
+	Deployment *DeploymentSpec `json:"deployment,omitempty"`
+
+	// StatefulSet specifies the EDOT Collector should be deployed as a StatefulSet, and allows providing its spec.
+	// Cannot be used along with `daemonSet` or `deployment`.
+	// +kubebuilder:validation:Optional
+	StatefulSet *StatefulSetSpec `json:"statefulSet,omitempty"`
+
+	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying DaemonSet or Deployment or StatefulSet.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+type DaemonSetSpec struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+	// +kubebuilder:validation:Optional
+	UpdateStrategy appsv1.DaemonSetUpdateStrategy `json:"updateStrategy,omitempty"`
+}
+
+type DeploymentSpec struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+	Replicas    *int32                 `json:"replicas,omitempty"`
+	// +kubebuilder:validation:Optional
+	Strategy appsv1.DeploymentStrategy `json:"strategy,omitempty"`
+}
+
+type StatefulSetSpec struct {
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PodTemplate corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+	Replicas    *int32                 `json:"replicas,omitempty"`
+	ServiceName string                 `json:"serviceName,omitempty"`
+
+	// PodManagementPolicy controls how pods are created during initial scale up,
+	// when replacing pods on nodes, or when scaling down. The default policy is
+	// `Parallel`, where pods are created in parallel to match the desired scale
+	// without waiting, and on scale down will delete all pods at once.
+	// The alternative policy is `OrderedReady`, the default for vanilla kubernetes
+	// StatefulSets, where pods are created in increasing order (pod-0, then pod-1, etc.)
+	// and the controller will wait until each pod is ready before continuing.
+	// When scaling down, the pods are removed in the opposite order.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +kubebuilder:default:=Parallel
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+
+	// VolumeClaimTemplates is a list of persistent volume claims to be used by each Pod, for example to persist
+	// the sending queue of the exporters.
+	// Every claim in this list must have a matching volumeMount in one of the containers defined in the PodTemplate.
+	// +kubebuilder:validation:Optional
+	VolumeClaimTemplates []corev1.PersistentVolumeClaim `json:"volumeClaimTemplates,omitempty"`
+}
+
+// OTelCollectorStatus defines the observed state of an EDOT Collector.
+type OTelCollectorStatus struct {
+	// Version of the stack resource currently running. During version upgrades, multiple versions may run
+	// in parallel: this value specifies the lowest version currently running.
+	Version string `json:"version,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ExpectedNodes int32 `json:"expectedNodes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	AvailableNodes int32 `json:"availableNodes,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Health OTelCollectorHealth `json:"health,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ElasticsearchAssociationStatus commonv1.AssociationStatus `json:"elasticsearchAssociationStatus,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ApmServerAssociationStatus commonv1.AssociationStatus `json:"apmServerAssociationStatus,omitempty"`
+
+	// ObservedGeneration is the most recent generation observed for this EDOT Collector.
+	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
+	// If the generation observed in status diverges from the generation in metadata, the EDOT
+	// Collector controller has not yet processed the changes contained in the EDOT Collector specification.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+type OTelCollectorHealth string
+
+const (
+	// OTelCollectorRedHealth means that the health is neither yellow nor green.
+	OTelCollectorRedHealth OTelCollectorHealth = "red"
+
+	// OTelCollectorYellowHealth means that:
+	// 1) at least one Pod is Ready, and
+	// 2) association is not configured, or configured and established
+	OTelCollectorYellowHealth OTelCollectorHealth = "yellow"
+
+	// OTelCollectorGreenHealth means that:
+	// 1) all Pods are Ready, and
+	// 2) association is not configured, or configured and established
+	OTelCollectorGreenHealth OTelCollectorHealth = "green"
+)
+
+// +kubebuilder:object:root=true
+
+// OTelCollector is the Schema for the Elastic Distribution of OpenTelemetry (EDOT) Collectors API.
+// +kubebuilder:resource:categories=elastic,shortName=edot
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="health",type="string",JSONPath=".status.health"
+// +kubebuilder:printcolumn:name="available",type="integer",JSONPath=".status.availableNodes",description="Available nodes"
+// +kubebuilder:printcolumn:name="expected",type="integer",JSONPath=".status.expectedNodes",description="Expected nodes"
+// +kubebuilder:printcolumn:name="version",type="string",JSONPath=".status.version",description="EDOT Collector version"
+// +kubebuilder:printcolumn:name="age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+type OTelCollector struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec         OTelCollectorSpec         `json:"spec,omitempty"`
+	Status       OTelCollectorStatus       `json:"status,omitempty"`
+	esAssocConf  *commonv1.AssociationConf `json:"-"`
+	apmAssocConf *commonv1.AssociationConf `json:"-"`
+}
+
+var _ commonv1.Associated = (*OTelCollector)(nil)
+
+func (c *OTelCollector) AssociationStatusMap(typ commonv1.AssociationType) commonv1.AssociationStatusMap {
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		if c.Spec.ElasticsearchRef.IsSet() {
+			return commonv1.NewSingleAssociationStatusMap(c.Status.ElasticsearchAssociationStatus)
+		}
+	case commonv1.ApmServerAssociationType:
+		if c.Spec.ApmServerRef.IsSet() {
+			return commonv1.NewSingleAssociationStatusMap(c.Status.ApmServerAssociationStatus)
+		}
+	}
+	return commonv1.AssociationStatusMap{}
+}
+
+func (c *OTelCollector) SetAssociationStatusMap(typ commonv1.AssociationType, status commonv1.AssociationStatusMap) error {
+	single, err := status.Single()
+	if err != nil {
+		return err
+	}
+
+	switch typ {
+	case commonv1.ElasticsearchAssociationType:
+		c.Status.ElasticsearchAssociationStatus = single
+		return nil
+	case commonv1.ApmServerAssociationType:
+		c.Status.ApmServerAssociationStatus = single
+		return nil
+	default:
+		return fmt.Errorf("association type %s not known", typ)
+	}
+}
+
+func (c *OTelCollector) ElasticServiceAccount() (commonv1.ServiceAccountName, error) {
+	return "", nil
+}
+
+func (c *OTelCollector) GetAssociations() []commonv1.Association {
+	associations := make([]commonv1.Association, 0)
+	if c.Spec.ElasticsearchRef.IsSet() {
+		associations = append(associations, c.EsAssociation())
+	}
+	if c.Spec.ApmServerRef.IsSet() {
+		associations = append(associations, c.ApmAssociation())
+	}
+	return associations
+}
+
+func (c *OTelCollector) EsAssociation() *OTelCollectorESAssociation {
+	return &OTelCollectorESAssociation{OTelCollector: c}
+}
+
+func (c *OTelCollector) ApmAssociation() *OTelCollectorApmAssociation {
+	return &OTelCollectorApmAssociation{OTelCollector: c}
+}
+
+func (c *OTelCollector) ServiceAccountName() string {
+	return c.Spec.ServiceAccountName
+}
+
+// IsMarkedForDeletion returns true if the OpenTelemetry Collector is going to be deleted
+func (c *OTelCollector) IsMarkedForDeletion() bool {
+	return !c.DeletionTimestamp.IsZero()
+}
+
+// GetObservedGeneration will return the observedGeneration from the OpenTelemetry Collector's status.
+func (c *OTelCollector) GetObservedGeneration() int64 {
+	return c.Status.ObservedGeneration
+}
+
+// OTelCollectorESAssociation helps to manage the OpenTelemetry Collector / Elasticsearch association.
+type OTelCollectorESAssociation struct {
+	*OTelCollector
+}
+
+var _ commonv1.Association = (*OTelCollectorESAssociation)(nil)
+
+func (c *OTelCollectorESAssociation) Associated() commonv1.Associated {
+	if c == nil {
+		return nil
+	}
+	if c.OTelCollector == nil {
+		c.OTelCollector = &OTelCollector{}
+	}
+	return c.OTelCollector
+}
+
+func (c *OTelCollectorESAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.ElasticsearchAssociationType
+}
+
+func (c *OTelCollectorESAssociation) AssociationRef() commonv1.AssociationRef {
+	return c.Spec.ElasticsearchRef.WithDefaultNamespace(c.Namespace)
+}
+
+func (c *OTelCollectorESAssociation) AssociationConfAnnotationName() string {
+	return commonv1.ElasticsearchConfigAnnotationNameBase
+}
+
+func (c *OTelCollectorESAssociation) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(c, c.esAssocConf)
+}
+
+func (c *OTelCollectorESAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	c.esAssocConf = conf
+}
+
+func (c *OTelCollectorESAssociation) SupportsAuthAPIKey() bool {
+	return true
+}
+
+func (c *OTelCollectorESAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+// OTelCollectorApmAssociation helps to manage the OpenTelemetry Collector / APM Server association.
+type OTelCollectorApmAssociation struct {
+	*OTelCollector
+}
+
+var _ commonv1.Association = (*OTelCollectorApmAssociation)(nil)
+
+func (c *OTelCollectorApmAssociation) Associated() commonv1.Associated {
+	if c == nil {
+		return nil
+	}
+	if c.OTelCollector == nil {
+		c.OTelCollector = &OTelCollector{}
+	}
+	return c.OTelCollector
+}
+
+func (c *OTelCollectorApmAssociation) AssociationType() commonv1.AssociationType {
+	return commonv1.ApmServerAssociationType
+}
+
+func (c *OTelCollectorApmAssociation) AssociationRef() commonv1.AssociationRef {
+	return c.Spec.ApmServerRef.WithDefaultNamespace(c.Namespace)
+}
+
+func (c *OTelCollectorApmAssociation) AssociationConfAnnotationName() string {
+	return commonv1.ApmServerConfigAnnotationNameBase
+}
+
+func (c *OTelCollectorApmAssociation) AssociationConf() (*commonv1.AssociationConf, error) {
+	return commonv1.GetAndSetAssociationConf(c, c.apmAssocConf)
+}
+
+func (c *OTelCollectorApmAssociation) SetAssociationConf(conf *commonv1.AssociationConf) {
+	c.apmAssocConf = conf
+}
+
+func (c *OTelCollectorApmAssociation) SupportsAuthAPIKey() bool {
+	return false
+}
+
+func (c *OTelCollectorApmAssociation) AssociationID() string {
+	return commonv1.SingletonAssociationID
+}
+
+// +kubebuilder:object:root=true
+
+// OTelCollectorList contains a list of OTelCollector.
+type OTelCollectorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OTelCollector `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OTelCollector{}, &OTelCollectorList{})
+}
@@ -0,0 +1,126 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+var (
+	defaultChecks = []func(*OTelCollector) field.ErrorList{
+		checkNoUnknownFields,
+		checkNameLength,
+		checkSupportedVersion,
+		checkAtMostOneDeploymentOption,
+		checkSingleConfigSource,
+		checkSpec,
+		checkAssociations,
+	}
+
+	updateChecks = []func(old, curr *OTelCollector) field.ErrorList{
+		checkNoDowngrade,
+		checkPVCchanges,
+	}
+)
+
+const (
+	pvcImmutableMsg = "Volume claim templates cannot be modified"
+)
+
+func checkNoUnknownFields(c *OTelCollector) field.ErrorList {
+	return commonv1.NoUnknownFields(c, c.ObjectMeta)
+}
+
+func checkNameLength(c *OTelCollector) field.ErrorList {
+	return commonv1.CheckNameLength(c)
+}
+
+func checkSupportedVersion(c *OTelCollector) field.ErrorList {
+	return commonv1.CheckSupportedStackVersion(c.Spec.Version, version.SupportedOTelCollectorVersions)
+}
+
+func checkAtMostOneDeploymentOption(c *OTelCollector) field.ErrorList {
+	var enabledSpecsNames []string
+
+	if c.Spec.DaemonSet != nil {
+		enabledSpecsNames = append(enabledSpecsNames, "daemonSet")
+	}
+	if c.Spec.Deployment != nil {
+		enabledSpecsNames = append(enabledSpecsNames, "deployment")
+	}
+	if c.Spec.StatefulSet != nil {
+		enabledSpecsNames = append(enabledSpecsNames, "statefulSet")
+	}
+
+	if enabledSpecsLen := len(enabledSpecsNames); enabledSpecsLen > 1 {
+		msg := fmt.Sprintf("Specify at most one of [%s]", strings.Join(enabledSpecsNames, ", "))
+		errList := make(field.ErrorList, enabledSpecsLen)
+		for index, specName := range enabledSpecsNames {
+			errList[index] = field.Forbidden(field.NewPath("spec").Child(specName), msg)
+		}
+		return errList
+	}
+
+	return nil
+}
+
+func checkSingleConfigSource(c *OTelCollector) field.ErrorList {
+	if c.Spec.Config != nil && c.Spec.ConfigRef != nil {
+		msg := "Specify at most one of [`config`, `configRef`], not both"
+		return field.ErrorList{
+			field.Forbidden(field.NewPath("spec").Child("config"), msg),
+			field.Forbidden(field.NewPath("spec").Child("configRef"), msg),
+		}
+	}
+
+	return nil
+}
+
+func checkSpec(c *OTelCollector) field.ErrorList {
+	if c.Spec.DaemonSet == nil && c.Spec.Deployment == nil && c.Spec.StatefulSet == nil {
+		return field.ErrorList{
+			field.Invalid(field.NewPath("spec"), c.Spec, "either daemonSet or deployment or statefulSet must be specified"),
+		}
+	}
+	return nil
+}
+
+func checkAssociations(c *OTelCollector) field.ErrorList {
+	specPath := field.NewPath("spec")
+	errs := commonv1.CheckAssociationRefs(specPath.Child("elasticsearchRef"), c.Spec.ElasticsearchRef)
+	errs = append(errs, commonv1.CheckAssociationRefs(specPath.Child("apmServerRef"), c.Spec.ApmServerRef)...)
+	if c.Spec.ApmServerRef.IsExternal() {
+		errs = append(errs, field.Forbidden(specPath.Child("apmServerRef"), "references to APM Server running outside the Kubernetes cluster are not supported"))
+	}
+	return errs
+}
+
+func checkNoDowngrade(prev, curr *OTelCollector) field.ErrorList {
+	if commonv1.IsConfiguredToAllowDowngrades(curr) {
+		return nil
+	}
+	return commonv1.CheckNoDowngrade(prev.Spec.Version, curr.Spec.Version)
+}
+
+// checkPVCchanges ensures no PVCs are changed, as volume claim templates are immutable in StatefulSets.
+func checkPVCchanges(current, proposed *OTelCollector) field.ErrorList {
+	if current == nil || proposed == nil || current.Spec.StatefulSet == nil || proposed.Spec.StatefulSet == nil {
+		return nil
+	}
+
+	// checking semantic equality here allows providing PVC storage size with different units (eg. 1Ti vs. 1024Gi).
+	if !apiequality.Semantic.DeepEqual(current.Spec.StatefulSet.VolumeClaimTemplates, proposed.Spec.StatefulSet.VolumeClaimTemplates) {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("statefulSet").Child("volumeClaimTemplates"),
+			proposed.Spec.StatefulSet.VolumeClaimTemplates, pvcImmutableMsg)}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+)
+
+func newCollector(mutate func(*OTelCollector)) *OTelCollector {
+	c := &OTelCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "collector", Namespace: "ns"},
+		Spec: OTelCollectorSpec{
+			Version:    "9.1.0",
+			Deployment: &DeploymentSpec{},
+		},
+	}
+	if mutate != nil {
+		mutate(c)
+	}
+	return c
+}
+
+func TestOTelCollector_ValidateCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		collector  *OTelCollector
+		wantErrors []string
+	}{
+		{
+			name:      "valid deployment",
+			collector: newCollector(nil),
+		},
+		{
+			name: "valid daemonset with an Elasticsearch reference and a config",
+			collector: newCollector(func(c *OTelCollector) {
+				c.Spec.Deployment = nil
+				c.Spec.DaemonSet = &DaemonSetSpec{}
+				c.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es"}
+				c.Spec.Config = &commonv1.Config{Data: map[string]any{"receivers": map[string]any{"otlp": nil}}}
+			}),
+		},
+		{
+			name:       "unsupported version",
+			collector:  newCollector(func(c *OTelCollector) { c.Spec.Version = "8.17.0" }),
+			wantErrors: []string{"spec.version"},
+		},
+		{
+			name:       "no deployment option",
+			collector:  newCollector(func(c *OTelCollector) { c.Spec.Deployment = nil }),
+			wantErrors: []string{"either daemonSet or deployment or statefulSet must be specified"},
+		},
+		{
+			name: "several deployment options",
+			collector: newCollector(func(c *OTelCollector) {
+				c.Spec.DaemonSet = &DaemonSetSpec{}
+			}),
+			wantErrors: []string{"spec.daemonSet", "spec.deployment"},
+		},
+		{
+			name: "config and configRef",
+			collector: newCollector(func(c *OTelCollector) {
+				c.Spec.Config = &commonv1.Config{}
+				c.Spec.ConfigRef = &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "config"}}
+			}),
+			wantErrors: []string{"spec.config", "spec.configRef"},
+		},
+		{
+			name: "invalid Elasticsearch reference",
+			collector: newCollector(func(c *OTelCollector) {
+				c.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es", SecretName: "es-secret"}
+			}),
+			wantErrors: []string{"spec.elasticsearchRef"},
+		},
+		{
+			name: "APM Server reference",
+			collector: newCollector(func(c *OTelCollector) {
+				c.Spec.ApmServerRef = commonv1.ObjectSelector{Name: "apm", Namespace: "apm-ns"}
+			}),
+		},
+		{
+			name: "external APM Server reference",
+			collector: newCollector(func(c *OTelCollector) {
+				c.Spec.ApmServerRef = commonv1.ObjectSelector{SecretName: "apm-secret"}
+			}),
+			wantErrors: []string{"spec.apmServerRef: Forbidden: references to APM Server running outside the Kubernetes cluster are not supported"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.collector.ValidateCreate()
+			if len(tt.wantErrors) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErrors {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestOTelCollector_ValidateUpdate(t *testing.T) {
+	withClaim := func(size string) func(*OTelCollector) {
+		return func(c *OTelCollector) {
+			c.Spec.Deployment = nil
+			c.Spec.StatefulSet = &StatefulSetSpec{
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+					ObjectMeta: metav1.ObjectMeta{Name: "queue"},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.VolumeResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+						},
+					},
+				}},
+			}
+		}
+	}
+	tests := []struct {
+		name      string
+		old, curr *OTelCollector
+		wantError string
+	}{
+		{
+			name: "version upgrade",
+			old:  newCollector(nil),
+			curr: newCollector(func(c *OTelCollector) { c.Spec.Version = "9.2.0" }),
+		},
+		{
+			name:      "version downgrade",
+			old:       newCollector(nil),
+			curr:      newCollector(func(c *OTelCollector) { c.Spec.Version = "9.0.0" }),
+			wantError: "spec.version",
+		},
+		{
+			name: "same volume claim templates with different units",
+			old:  newCollector(withClaim("1Gi")),
+			curr: newCollector(withClaim("1024Mi")),
+		},
+		{
+			name:      "volume claim templates changed",
+			old:       newCollector(withClaim("1Gi")),
+			curr:      newCollector(withClaim("2Gi")),
+			wantError: pvcImmutableMsg,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.curr.ValidateUpdate(tt.old)
+			if tt.wantError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package v1alpha1
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/admission"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// webhookPath is the HTTP path for the OpenTelemetry Collector validating webhook.
+	webhookPath = "/validate-otel-k8s-elastic-co-v1alpha1-otelcollector"
+)
+
+var (
+	groupKind     = schema.GroupKind{Group: GroupVersion.Group, Kind: Kind}
+	validationLog = ulog.Log.WithName("otel-collector-v1alpha1-validation")
+)
+
+// +kubebuilder:webhook:path=/validate-otel-k8s-elastic-co-v1alpha1-otelcollector,mutating=false,failurePolicy=ignore,groups=otel.k8s.elastic.co,resources=otelcollectors,verbs=create;update,versions=v1alpha1,name=elastic-otel-collector-validation-v1alpha1.k8s.elastic.co,sideEffects=None,admissionReviewVersions=v1,matchPolicy=Exact
+
+var _ admission.Validator = (*OTelCollector)(nil)
+
+// ValidateCreate is called by the validating webhook to validate the create operation.
+// Satisfies the webhook.Validator interface.
+func (c *OTelCollector) ValidateCreate() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate create", "name", c.Name)
+	return c.validate(nil)
+}
+
+// ValidateDelete is called by the validating webhook to validate the delete operation.
+// Satisfies the webhook.Validator interface.
+func (c *OTelCollector) ValidateDelete() (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate delete", "name", c.Name)
+	return nil, nil
+}
+
+// ValidateUpdate is called by the validating webhook to validate the update operation.
+// Satisfies the webhook.Validator interface.
+func (c *OTelCollector) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	validationLog.V(1).Info("Validate update", "name", c.Name)
+	oldObj, ok := old.(*OTelCollector)
+	if !ok {
+		return nil, errors.New("cannot cast old object to OTelCollector type")
+	}
+
+	return c.validate(oldObj)
+}
+
+// WebhookPath returns the HTTP path used by the validating webhook.
+func (c *OTelCollector) WebhookPath() string {
+	return webhookPath
+}
+
+func (c *OTelCollector) validate(old *OTelCollector) (admission.Warnings, error) {
+	var errors field.ErrorList
+
+	if old != nil {
+		for _, uc := range updateChecks {
+			if err := uc(old, c); err != nil {
+				errors = append(errors, err...)
+			}
+		}
+
+		if len(errors) > 0 {
+			return nil, apierrors.NewInvalid(groupKind, c.Name, errors)
+		}
+	}
+
+	for _, dc := range defaultChecks {
+		if err := dc(c); err != nil {
+			errors = append(errors, err...)
+		}
+	}
+
+	if len(errors) > 0 {
+		return nil, apierrors.NewInvalid(groupKind, c.Name, errors)
+	}
+	return nil, nil
+}
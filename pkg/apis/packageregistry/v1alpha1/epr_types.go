@@ -33,6 +33,11 @@ type PackageRegistrySpec struct {
 	// Count of Elastic Package Registry instances to deploy.
 	Count int32 `json:"count,omitempty"`
 
+	// Autoscaling defines a HorizontalPodAutoscaler managed by the operator to scale the Elastic Package Registry Deployment.
+	// When set, Count is ignored and the number of instances is left to the HorizontalPodAutoscaler.
+	// +kubebuilder:validation:Optional
+	Autoscaling *commonv1.HorizontalPodAutoscalerSpec `json:"autoscaling,omitempty"`
+
 	// Config holds the PackageRegistry configuration. See: https://github.com/elastic/package-registry/blob/main/config.reference.yml
 	// +kubebuilder:pruning:PreserveUnknownFields
 	Config *commonv1.Config `json:"config,omitempty"`
@@ -52,12 +57,49 @@ type PackageRegistrySpec struct {
 
 	// RevisionHistoryLimit is the number of revisions to retain to allow rollback in the underlying Deployment.
 	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+
+	// PackageSources is a list of volumes holding extra or custom integration packages, served alongside the packages
+	// bundled in the image. Packages are validated by an init container before being added to the package paths of
+	// Elastic Package Registry. Invalid packages are skipped and reported in the status.
+	// Setting `package_paths` in the configuration overrides the package paths, including the one of the package sources.
+	// +kubebuilder:validation:Optional
+	PackageSources []PackageSource `json:"packageSources,omitempty"`
+}
+
+// PackageSource is a volume holding integration packages. Each entry at the root of the volume, or of SubPath,
+// is either a package directory containing a `manifest.yml` file, or a package archive named `<name>-<version>.zip`.
+// Exactly one of PersistentVolumeClaim and Image must be set.
+type PackageSource struct {
+	// Name of the package source, unique among the package sources.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=40
+	Name string `json:"name"`
+
+	// PersistentVolumeClaim references an existing PersistentVolumeClaim holding the packages, in the same namespace.
+	// +kubebuilder:validation:Optional
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// Image references an OCI image or artifact holding the packages. Requires support for image volumes in Kubernetes.
+	// +kubebuilder:validation:Optional
+	Image *corev1.ImageVolumeSource `json:"image,omitempty"`
+
+	// SubPath is the path of the packages within the volume. Defaults to the root of the volume.
+	// +kubebuilder:validation:Optional
+	SubPath string `json:"subPath,omitempty"`
 }
 
 // PackageRegistryStatus defines the observed state of Elastic Package Registry
 type PackageRegistryStatus struct {
 	commonv1.DeploymentStatus `json:",inline"`
 
+	// Packages lists the packages from the package sources served by Elastic Package Registry, as `<name>-<version>`.
+	// +kubebuilder:validation:Optional
+	Packages []string `json:"packages,omitempty"`
+
+	// InvalidPackages lists the entries of the package sources that failed validation and are not served.
+	// +kubebuilder:validation:Optional
+	InvalidPackages []string `json:"invalidPackages,omitempty"`
+
 	// ObservedGeneration is the most recent generation observed for this Elastic Package Registry.
 	// It corresponds to the metadata generation, which is updated on mutation by the API Server.
 	// If the generation observed in status diverges from the generation in metadata, the Elastic Package Registry
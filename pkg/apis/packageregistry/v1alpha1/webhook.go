@@ -5,12 +5,17 @@
 package v1alpha1
 
 import (
+	"path"
+	"slices"
+	"strings"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/admission"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
@@ -29,6 +34,9 @@ var (
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
+		checkAutoscaling,
+		checkPackageSources,
 	}
 )
 
@@ -88,3 +96,30 @@ func checkNameLength(epr *PackageRegistry) field.ErrorList {
 func checkSupportedVersion(epr *PackageRegistry) field.ErrorList {
 	return commonv1.CheckSupportedStackVersion(epr.Spec.Version, version.SupportedPackageRegistryVersions)
 }
+
+func checkImageCatalogVersion(epr *PackageRegistry) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(epr.Spec.Version, container.PackageRegistryImage, epr.Spec.Image)
+}
+
+func checkAutoscaling(epr *PackageRegistry) field.ErrorList {
+	return commonv1.CheckHorizontalPodAutoscaler(field.NewPath("spec").Child("autoscaling"), epr.Spec.Autoscaling)
+}
+
+func checkPackageSources(epr *PackageRegistry) field.ErrorList {
+	var errs field.ErrorList
+	names := make(map[string]struct{}, len(epr.Spec.PackageSources))
+	for i, source := range epr.Spec.PackageSources {
+		sourcePath := field.NewPath("spec").Child("packageSources").Index(i)
+		if _, exists := names[source.Name]; exists {
+			errs = append(errs, field.Duplicate(sourcePath.Child("name"), source.Name))
+		}
+		names[source.Name] = struct{}{}
+		if (source.PersistentVolumeClaim == nil) == (source.Image == nil) {
+			errs = append(errs, field.Invalid(sourcePath, source.Name, "exactly one of persistentVolumeClaim and image must be set"))
+		}
+		if path.IsAbs(source.SubPath) || slices.Contains(strings.Split(source.SubPath, "/"), "..") {
+			errs = append(errs, field.Invalid(sourcePath.Child("subPath"), source.SubPath, "subPath must be a relative path within the volume"))
+		}
+	}
+	return errs
+}
@@ -14,7 +14,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	eprv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/packageregistry/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/test"
 )
@@ -111,6 +113,62 @@ func TestWebhook(t *testing.T) {
 				`spec.version: Invalid value: "300.1.2": Unsupported version: version 300.1.2 is higher than the highest supported version`,
 			),
 		},
+		{
+			Name:      "autoscaling",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				epr := mkEPR(uid)
+				epr.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](2), MaxReplicas: 5}
+				return serialize(t, epr)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "autoscaling-max-lower-than-min",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				epr := mkEPR(uid)
+				epr.Spec.Autoscaling = &commonv1.HorizontalPodAutoscalerSpec{MinReplicas: ptr.To[int32](3), MaxReplicas: 2}
+				return serialize(t, epr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`spec.autoscaling.maxReplicas: Invalid value: 2: maxReplicas cannot be lower than minReplicas`,
+			),
+		},
+		{
+			Name:      "package-sources",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				epr := mkEPR(uid)
+				epr.Spec.PackageSources = []eprv1alpha1.PackageSource{
+					{Name: "internal", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "packages"}, SubPath: "integrations"},
+					{Name: "bundle", Image: &corev1.ImageVolumeSource{Reference: "registry.local/packages:1.0.0"}},
+				}
+				return serialize(t, epr)
+			},
+			Check: test.ValidationWebhookSucceeded,
+		},
+		{
+			Name:      "invalid-package-sources",
+			Operation: admissionv1.Create,
+			Object: func(t *testing.T, uid string) []byte {
+				t.Helper()
+				epr := mkEPR(uid)
+				epr.Spec.PackageSources = []eprv1alpha1.PackageSource{
+					{Name: "internal", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "packages"}, SubPath: "../etc"},
+					{Name: "internal"},
+				}
+				return serialize(t, epr)
+			},
+			Check: test.ValidationWebhookFailed(
+				`subPath must be a relative path within the volume`,
+				`Duplicate value: "internal"`,
+				`exactly one of persistentVolumeClaim and image must be set`,
+			),
+		},
 	}
 
 	validator := &eprv1alpha1.PackageRegistry{}
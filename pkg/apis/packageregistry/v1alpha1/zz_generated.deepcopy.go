@@ -10,6 +10,7 @@ package v1alpha1
 
 import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -19,7 +20,7 @@ func (in *PackageRegistry) DeepCopyInto(out *PackageRegistry) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRegistry.
@@ -75,6 +76,11 @@ func (in *PackageRegistryList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PackageRegistrySpec) DeepCopyInto(out *PackageRegistrySpec) {
 	*out = *in
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(v1.HorizontalPodAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
 		*out = (*in).DeepCopy()
@@ -91,6 +97,13 @@ func (in *PackageRegistrySpec) DeepCopyInto(out *PackageRegistrySpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.PackageSources != nil {
+		in, out := &in.PackageSources, &out.PackageSources
+		*out = make([]PackageSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRegistrySpec.
@@ -107,6 +120,16 @@ func (in *PackageRegistrySpec) DeepCopy() *PackageRegistrySpec {
 func (in *PackageRegistryStatus) DeepCopyInto(out *PackageRegistryStatus) {
 	*out = *in
 	out.DeploymentStatus = in.DeploymentStatus
+	if in.Packages != nil {
+		in, out := &in.Packages, &out.Packages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InvalidPackages != nil {
+		in, out := &in.InvalidPackages, &out.InvalidPackages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageRegistryStatus.
@@ -118,3 +141,28 @@ func (in *PackageRegistryStatus) DeepCopy() *PackageRegistryStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PackageSource) DeepCopyInto(out *PackageSource) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.Image != nil {
+		in, out := &in.Image, &out.Image
+		*out = new(corev1.ImageVolumeSource)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PackageSource.
+func (in *PackageSource) DeepCopy() *PackageSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PackageSource)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -185,7 +185,8 @@ func buildPodTemplate(params Params, fleetCerts *certificates.CertificatesSecret
 	builder = builder.
 		WithLabels(podMeta.Labels).
 		WithAnnotations(podMeta.Annotations).
-		WithDockerImage(spec.Image, container.ImageRepository(container.AgentImageFor(params.AgentVersion), params.AgentVersion)).
+		WithDockerImage(spec.Image, container.ImageRepository(params.Agent.Namespace, container.AgentImageFor(params.AgentVersion), params.AgentVersion)).
+		WithImagePullSecrets(container.ImagePullSecrets(params.Agent.Namespace)...).
 		WithAutomountServiceAccountToken().
 		WithVolumeLikes(vols...).
 		WithEnv(
@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/agent/v1alpha1"
 	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
@@ -87,7 +88,7 @@ var (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, params, &apmv1.ApmServer{})
 	if err != nil {
 		return err
 	}
@@ -119,6 +120,19 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileApmSer
 		return err
 	}
 
+	// Watch Elastic Agents running the APM integration
+	if err := c.Watch(source.Kind(mgr.GetCache(), &agentv1alpha1.Agent{}, handler.TypedEnqueueRequestForOwner[*agentv1alpha1.Agent](
+		mgr.GetScheme(), mgr.GetRESTMapper(),
+		&apmv1.ApmServer{}, handler.OnlyControllerOwner(),
+	))); err != nil {
+		return err
+	}
+
+	// Watch HorizontalPodAutoscalers
+	if err := watches.WatchHorizontalPodAutoscalers(mgr, c, &apmv1.ApmServer{}); err != nil {
+		return err
+	}
+
 	// Watch Pods, to ensure `status.version` and version upgrades are correctly reconciled on any change.
 	// Watching Deployments only may lead to missing some events.
 	if err := watches.WatchPods(mgr, c, ApmServerNameLabelName); err != nil {
@@ -236,7 +250,16 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 	// common metadata to pass on to children
 	meta := metadata.Propagate(as, metadata.Metadata{Labels: as.GetIdentityLabels()})
 
-	svc, err := common.ReconcileService(ctx, r.Client, NewService(*as, meta), as)
+	expectedSvc := NewService(*as, meta)
+	agentSelector, err := r.serviceSelector(ctx, as)
+	if err != nil {
+		return results.WithError(err), state
+	}
+	if agentSelector != nil && as.Spec.HTTP.Service.Spec.Selector == nil {
+		// the Service selects the Elastic Agent running the APM integration
+		expectedSvc.Spec.Selector = agentSelector
+	}
+	svc, err := common.ReconcileService(ctx, r.Client, expectedSvc, as)
 	if err != nil {
 		return results.WithError(err), state
 	}
@@ -273,14 +296,25 @@ func (r *ReconcileApmServer) doReconcile(ctx context.Context, as *apmv1.ApmServe
 		return results, state // will eventually retry
 	}
 
-	state, err = r.reconcileApmServerDeployment(ctx, state, as, asVersion, meta)
-	if err != nil {
-		if apierrors.IsConflict(err) {
-			log.V(1).Info("Conflict while updating status")
-			return results.WithRequeue(), state
+	if as.IsIntegrationMode() {
+		state = r.reconcileIntegration(ctx, state, as, meta, agentSelector != nil, results)
+	} else {
+		state, err = r.reconcileApmServerDeployment(ctx, state, as, asVersion, meta)
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				log.V(1).Info("Conflict while updating status")
+				return results.WithRequeue(), state
+			}
+			k8s.MaybeEmitErrorEventf(r.recorder, err, as, events.EventReconciliationError, events.EventActionDeploymentReconciliation, "Deployment reconciliation error: %v", err)
+			return results.WithError(tracing.CaptureError(ctx, err)), state
+		}
+		if agentSelector == nil {
+			// the Service selects the standalone APM Server: remove any Elastic Agent previously running the APM integration
+			if err := deleteIntegrationAgent(ctx, r.Client, as); err != nil {
+				return results.WithError(err), state
+			}
+			state.ApmServer.Status.Integration = nil
 		}
-		k8s.MaybeEmitErrorEventf(r.recorder, err, as, events.EventReconciliationError, events.EventActionDeploymentReconciliation, "Deployment reconciliation error: %v", err)
-		return results.WithError(tracing.CaptureError(ctx, err)), state
 	}
 
 	state.UpdateApmServerExternalService(*svc)
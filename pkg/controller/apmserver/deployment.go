@@ -76,6 +76,9 @@ func (r *ReconcileApmServer) reconcileApmServerDeployment(
 	if err != nil {
 		return state, err
 	}
+	if err := deployment.ReconcileHorizontalPodAutoscaler(ctx, r.K8sClient(), deploy, as.Spec.Autoscaling, meta, as); err != nil {
+		return state, err
+	}
 
 	pods, err := k8s.PodsMatchingLabels(r.K8sClient(), as.Namespace, map[string]string{ApmServerNameLabelName: as.Name})
 	if err != nil {
@@ -101,6 +104,7 @@ func (r *ReconcileApmServer) deploymentParams(
 		Name:                 Deployment(as.Name),
 		Namespace:            as.Namespace,
 		Replicas:             as.Spec.Count,
+		Autoscaled:           as.Spec.Autoscaling != nil,
 		Selector:             as.GetIdentityLabels(),
 		Metadata:             meta,
 		RevisionHistoryLimit: as.Spec.RevisionHistoryLimit,
@@ -0,0 +1,249 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apmserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"go.elastic.co/apm/module/apmhttp/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
+	commonhttp "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/stringsutil"
+)
+
+const (
+	// apmPackageName is the name of the APM integration package in Fleet.
+	apmPackageName = "apm"
+	// fleetPolicyNamespace is the data stream namespace of the Fleet policies managed by the operator.
+	fleetPolicyNamespace = "default"
+	// fleetRequestTimeout is the duration after which a request to the Fleet API is canceled.
+	fleetRequestTimeout = 60 * time.Second
+)
+
+// AgentPolicy is the representation of an agent policy in the Fleet API.
+type AgentPolicy struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Namespace   string `json:"namespace"`
+	Description string `json:"description,omitempty"`
+}
+
+// PackagePolicy is the representation of a package policy in the Fleet API.
+type PackagePolicy struct {
+	ID        string               `json:"id,omitempty"`
+	Name      string               `json:"name"`
+	Namespace string               `json:"namespace"`
+	PolicyID  string               `json:"policy_id"`
+	Package   PackagePolicyPackage `json:"package"`
+	Inputs    []PackagePolicyInput `json:"inputs"`
+}
+
+// PackagePolicyPackage identifies the package configured by a package policy.
+type PackagePolicyPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// PackagePolicyInput is an input of a package policy.
+type PackagePolicyInput struct {
+	Type           string                      `json:"type"`
+	PolicyTemplate string                      `json:"policy_template,omitempty"`
+	Enabled        bool                        `json:"enabled"`
+	Streams        []any                       `json:"streams"`
+	Vars           map[string]PackagePolicyVar `json:"vars,omitempty"`
+}
+
+// PackagePolicyVar is a variable of a package policy input.
+type PackagePolicyVar struct {
+	Type  string `json:"type,omitempty"`
+	Value any    `json:"value"`
+}
+
+type agentPolicyResult struct {
+	Item AgentPolicy `json:"item"`
+}
+
+type agentPolicyList struct {
+	Items []AgentPolicy `json:"items"`
+}
+
+type packagePolicyResult struct {
+	Item PackagePolicy `json:"item"`
+}
+
+type packagePolicyList struct {
+	Items []PackagePolicy `json:"items"`
+}
+
+// fleetAPI is a minimal client of the Kibana Fleet API, used to manage the policies of the APM integration.
+type fleetAPI struct {
+	client   *http.Client
+	endpoint string
+	username string
+	password string
+	log      logr.Logger
+}
+
+// newFleetAPI returns a Fleet API client using the Kibana association of the given APM Server.
+func newFleetAPI(ctx context.Context, c k8s.Client, dialer net.Dialer, as *apmv1.ApmServer, logger logr.Logger) (fleetAPI, error) {
+	kbAssociation := apmv1.NewApmKibanaAssociation(as)
+	assocConf, err := kbAssociation.AssociationConf()
+	if err != nil {
+		return fleetAPI{}, err
+	}
+	credentials, err := association.ElasticsearchAuthSettings(ctx, c, kbAssociation)
+	if err != nil {
+		return fleetAPI{}, err
+	}
+	var caCerts []*x509.Certificate
+	if assocConf.GetCACertProvided() {
+		var caSecret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Namespace: as.Namespace, Name: assocConf.GetCASecretName()}, &caSecret); err != nil {
+			return fleetAPI{}, err
+		}
+		caBytes, ok := caSecret.Data[certificates.CAFileName]
+		if !ok {
+			return fleetAPI{}, fmt.Errorf("no %s in %s", certificates.CAFileName, k8s.ExtractNamespacedName(&caSecret))
+		}
+		if caCerts, err = certificates.ParsePEMCerts(caBytes); err != nil {
+			return fleetAPI{}, err
+		}
+	}
+	return fleetAPI{
+		client: apmhttp.WrapClient(
+			commonhttp.Client(dialer, caCerts, fleetRequestTimeout),
+			apmhttp.WithClientRequestName(tracing.RequestName),
+			apmhttp.WithClientSpanType("external.kibana"),
+		),
+		endpoint: assocConf.GetURL(),
+		username: credentials.Username,
+		password: credentials.Password,
+		log:      logger,
+	}, nil
+}
+
+func (f fleetAPI) request(ctx context.Context, method string, pathWithQuery string, requestObj, responseObj any) error {
+	var body io.Reader = http.NoBody
+	if requestObj != nil {
+		outData, err := json.Marshal(requestObj)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(outData)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, stringsutil.Concat(f.endpoint, "/api/fleet/", pathWithQuery), body)
+	if err != nil {
+		return err
+	}
+	request.Header.Set(commonhttp.InternalProductRequestHeaderKey, commonhttp.InternalProductRequestHeaderValue)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("kbn-xsrf", "true")
+	request.SetBasicAuth(f.username, f.password)
+
+	f.log.V(1).Info("Fleet API HTTP request", "method", request.Method, "url", request.URL.Redacted())
+
+	resp, err := f.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := commonhttp.MaybeAPIError(resp); err != nil {
+		return err
+	}
+	if responseObj != nil {
+		return json.NewDecoder(resp.Body).Decode(responseObj)
+	}
+	return nil
+}
+
+func (f fleetAPI) setupFleet(ctx context.Context) error {
+	return f.request(ctx, http.MethodPost, "setup", nil, nil)
+}
+
+// getAgentPolicy returns the agent policy with the given ID.
+func (f fleetAPI) getAgentPolicy(ctx context.Context, id string) (AgentPolicy, error) {
+	var response agentPolicyResult
+	err := f.request(ctx, http.MethodGet, "agent_policies/"+id, nil, &response)
+	return response.Item, err
+}
+
+// findAgentPolicy returns the agent policy with the given name, if any.
+func (f fleetAPI) findAgentPolicy(ctx context.Context, name string) (AgentPolicy, bool, error) {
+	for page := 1; ; page++ {
+		var list agentPolicyList
+		if err := f.request(ctx, http.MethodGet, fmt.Sprintf("agent_policies?perPage=20&page=%d", page), nil, &list); err != nil {
+			return AgentPolicy{}, false, err
+		}
+		if len(list.Items) == 0 {
+			return AgentPolicy{}, false, nil
+		}
+		for _, p := range list.Items {
+			if p.Name == name {
+				return p, true, nil
+			}
+		}
+	}
+}
+
+func (f fleetAPI) createAgentPolicy(ctx context.Context, policy AgentPolicy) (AgentPolicy, error) {
+	var response agentPolicyResult
+	err := f.request(ctx, http.MethodPost, "agent_policies", policy, &response)
+	return response.Item, err
+}
+
+// getPackagePolicy returns the package policy with the given ID.
+func (f fleetAPI) getPackagePolicy(ctx context.Context, id string) (PackagePolicy, error) {
+	var response packagePolicyResult
+	err := f.request(ctx, http.MethodGet, "package_policies/"+id, nil, &response)
+	return response.Item, err
+}
+
+// findPackagePolicy returns the package policy with the given name, if any.
+func (f fleetAPI) findPackagePolicy(ctx context.Context, name string) (PackagePolicy, bool, error) {
+	for page := 1; ; page++ {
+		var list packagePolicyList
+		if err := f.request(ctx, http.MethodGet, fmt.Sprintf("package_policies?perPage=20&page=%d", page), nil, &list); err != nil {
+			return PackagePolicy{}, false, err
+		}
+		if len(list.Items) == 0 {
+			return PackagePolicy{}, false, nil
+		}
+		for _, p := range list.Items {
+			if p.Name == name {
+				return p, true, nil
+			}
+		}
+	}
+}
+
+func (f fleetAPI) createPackagePolicy(ctx context.Context, policy PackagePolicy) (PackagePolicy, error) {
+	var response packagePolicyResult
+	err := f.request(ctx, http.MethodPost, "package_policies", policy, &response)
+	return response.Item, err
+}
+
+func (f fleetAPI) updatePackagePolicy(ctx context.Context, id string, policy PackagePolicy) (PackagePolicy, error) {
+	var response packagePolicyResult
+	err := f.request(ctx, http.MethodPut, "package_policies/"+id, policy, &response)
+	return response.Item, err
+}
@@ -0,0 +1,404 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apmserver
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	"go.elastic.co/apm/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/agent"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/hash"
+	commonhttp "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// apmIntegrationInputType is the type of the input of the APM integration.
+	apmIntegrationInputType = "apm"
+	// apmIntegrationPolicyTemplate is the policy template of the APM integration.
+	apmIntegrationPolicyTemplate = "apmserver"
+	// integrationSecretTokenEnvVar is the environment variable of the Elastic Agent holding the secret token. The package
+	// policy references it so that the secret token is neither stored in Fleet nor part of the package policy hash.
+	integrationSecretTokenEnvVar = "APM_SECRET_TOKEN" //nolint:gosec
+)
+
+// fleetPolicyName returns the name of the Fleet agent and package policies of the APM integration.
+func fleetPolicyName(as apmv1.ApmServer) string {
+	return fmt.Sprintf("eck-apm-%s-%s", as.Namespace, as.Name)
+}
+
+// reconcileIntegration runs the APM Server as the APM integration in a Fleet-managed Elastic Agent. The Fleet policies
+// are reconciled through Kibana, then the Elastic Agent is enrolled into them. The Deployment of the standalone APM Server
+// is only removed once the Service selects the Elastic Agent, so that instrumented applications are not interrupted.
+func (r *ReconcileApmServer) reconcileIntegration(
+	ctx context.Context,
+	state State,
+	as *apmv1.ApmServer,
+	meta metadata.Metadata,
+	agentSelected bool,
+	results *reconciler.Results,
+) State {
+	span, ctx := apm.StartSpan(ctx, "reconcile_integration", tracing.SpanTypeApp)
+	defer span.End()
+
+	tokenSecret, err := reconcileApmServerToken(ctx, r.Client, as, meta)
+	if err != nil {
+		results.WithError(err)
+		return state
+	}
+
+	api, err := newFleetAPI(ctx, r.Client, r.Dialer, as, ulog.FromContext(ctx))
+	if err != nil {
+		results.WithError(err)
+		return state
+	}
+	defer api.client.CloseIdleConnections()
+
+	integration, err := reconcileFleetPolicies(ctx, api, as)
+	if err != nil {
+		if commonhttp.IsUnauthorized(err) || commonhttp.IsForbidden(err) {
+			const message = "ECK cannot manage the Fleet policy of the APM integration. Waiting for Kibana credentials. This should be a transient issue."
+			ulog.FromContext(ctx).Info(message, "error", err.Error())
+			k8s.EmitEvent(r.recorder, as, corev1.EventTypeWarning, events.EventReasonDelayed, events.EventActionFleetPolicyReconciliation, message)
+			results.WithRequeue()
+			return state
+		}
+		results.WithError(err)
+		return state
+	}
+
+	reconciledAgent, err := reconcileIntegrationAgent(ctx, r.Client, as, meta, integration.AgentPolicyID, tokenSecret.Name)
+	if err != nil {
+		results.WithError(err)
+		return state
+	}
+
+	if agentSelected {
+		// the Service selects the Elastic Agent: the standalone APM Server can be removed
+		if err := deleteStandaloneDeployment(ctx, r.Client, as); err != nil {
+			results.WithError(err)
+			return state
+		}
+	}
+
+	state.UpdateApmServerIntegrationState(reconciledAgent, tokenSecret, integration)
+	return state
+}
+
+// reconcileFleetPolicies ensures the Fleet agent policy of the APM integration exists, and that its package policy
+// matches the expected APM integration settings. It returns the updated integration status.
+func reconcileFleetPolicies(ctx context.Context, api fleetAPI, as *apmv1.ApmServer) (*apmv1.IntegrationStatus, error) {
+	status := as.Status.Integration.DeepCopy()
+	if status == nil {
+		// first time: setup Fleet to make sure the APM package can be installed
+		if err := api.setupFleet(ctx); err != nil {
+			return nil, err
+		}
+		status = &apmv1.IntegrationStatus{}
+	}
+	status.Agent = IntegrationAgent(as.Name)
+	name := fleetPolicyName(*as)
+
+	agentPolicyID, err := reconcileAgentPolicy(ctx, api, status.AgentPolicyID, name)
+	if err != nil {
+		return nil, err
+	}
+	if agentPolicyID != status.AgentPolicyID {
+		// a new agent policy does not hold the package policy anymore
+		status.PackagePolicyID = ""
+	}
+	status.AgentPolicyID = agentPolicyID
+
+	expected := newPackagePolicy(*as, agentPolicyID)
+	expectedHash := hash.HashObject(expected)
+
+	packagePolicyID := status.PackagePolicyID
+	if packagePolicyID != "" {
+		if _, err := api.getPackagePolicy(ctx, packagePolicyID); err != nil {
+			if !commonhttp.IsNotFound(err) {
+				return nil, err
+			}
+			packagePolicyID = ""
+		}
+	}
+	if packagePolicyID == "" {
+		existing, found, err := api.findPackagePolicy(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			// the package policy exists but its content is not known: update it
+			packagePolicyID = existing.ID
+			status.PackagePolicyHash = ""
+		}
+	}
+
+	switch {
+	case packagePolicyID == "":
+		ulog.FromContext(ctx).Info("Creating Fleet package policy for the APM integration", "namespace", as.Namespace, "as_name", as.Name, "policy_name", name)
+		created, err := api.createPackagePolicy(ctx, expected)
+		if err != nil {
+			return nil, err
+		}
+		packagePolicyID = created.ID
+	case status.PackagePolicyHash != expectedHash:
+		ulog.FromContext(ctx).Info("Updating Fleet package policy for the APM integration", "namespace", as.Namespace, "as_name", as.Name, "policy_id", packagePolicyID)
+		if _, err := api.updatePackagePolicy(ctx, packagePolicyID, expected); err != nil {
+			return nil, err
+		}
+	}
+	status.PackagePolicyID = packagePolicyID
+	status.PackagePolicyHash = expectedHash
+	return status, nil
+}
+
+// reconcileAgentPolicy returns the ID of the agent policy with the given name, creating it if it does not exist.
+func reconcileAgentPolicy(ctx context.Context, api fleetAPI, id string, name string) (string, error) {
+	if id != "" {
+		_, err := api.getAgentPolicy(ctx, id)
+		if err == nil {
+			return id, nil
+		}
+		if !commonhttp.IsNotFound(err) {
+			return "", err
+		}
+	}
+	existing, found, err := api.findAgentPolicy(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return existing.ID, nil
+	}
+	ulog.FromContext(ctx).Info("Creating Fleet agent policy for the APM integration", "policy_name", name)
+	created, err := api.createAgentPolicy(ctx, AgentPolicy{
+		Name:        name,
+		Namespace:   fleetPolicyNamespace,
+		Description: "APM integration managed by ECK",
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.ID, nil
+}
+
+// newPackagePolicy returns the package policy configuring the APM integration to serve the same endpoint, with the
+// same secret token, as the standalone APM Server. The secret token is read by the Elastic Agent from its environment.
+func newPackagePolicy(as apmv1.ApmServer, agentPolicyID string) PackagePolicy {
+	vars := map[string]PackagePolicyVar{
+		"host":         {Type: "text", Value: fmt.Sprintf("0.0.0.0:%d", HTTPPort)},
+		"url":          {Type: "text", Value: fmt.Sprintf("%s://%s.%s.svc:%d", as.Spec.HTTP.Protocol(), HTTPService(as.Name), as.Namespace, HTTPPort)},
+		"secret_token": {Type: "text", Value: fmt.Sprintf("${env.%s}", integrationSecretTokenEnvVar)},
+		"tls_enabled":  {Type: "bool", Value: as.Spec.HTTP.TLS.Enabled()},
+	}
+	if as.Spec.HTTP.TLS.Enabled() {
+		vars["tls_certificate"] = PackagePolicyVar{Type: "text", Value: path.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.CertFileName)}
+		vars["tls_key"] = PackagePolicyVar{Type: "text", Value: path.Join(certificates.HTTPCertificatesSecretVolumeMountPath, certificates.KeyFileName)}
+	}
+	return PackagePolicy{
+		Name:      fleetPolicyName(as),
+		Namespace: fleetPolicyNamespace,
+		PolicyID:  agentPolicyID,
+		Package:   PackagePolicyPackage{Name: apmPackageName, Version: as.EffectiveVersion()},
+		Inputs: []PackagePolicyInput{
+			{
+				Type:           apmIntegrationInputType,
+				PolicyTemplate: apmIntegrationPolicyTemplate,
+				Enabled:        true,
+				Streams:        []any{},
+				Vars:           vars,
+			},
+		},
+	}
+}
+
+// newIntegrationAgent returns the Fleet-managed Elastic Agent running the APM integration, reading the secret token
+// from the Secret of the given name.
+func newIntegrationAgent(as apmv1.ApmServer, meta metadata.Metadata, agentPolicyID string, tokenSecretName string) agentv1alpha1.Agent {
+	podTemplate := *as.Spec.PodTemplate.DeepCopy()
+	idx := slices.IndexFunc(podTemplate.Spec.Containers, func(c corev1.Container) bool { return c.Name == agent.ContainerName })
+	if idx < 0 {
+		podTemplate.Spec.Containers = append(podTemplate.Spec.Containers, corev1.Container{Name: agent.ContainerName})
+		idx = len(podTemplate.Spec.Containers) - 1
+	}
+	agentContainer := &podTemplate.Spec.Containers[idx]
+	agentContainer.Env = append(agentContainer.Env, corev1.EnvVar{
+		Name: integrationSecretTokenEnvVar,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: tokenSecretName},
+				Key:                  SecretTokenKey,
+			},
+		},
+	})
+	if as.Spec.HTTP.TLS.Enabled() {
+		// the APM integration serves the HTTP certificates of the APM Server
+		certsVolume := certificates.HTTPCertSecretVolume(Namer, as.Name)
+		podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, certsVolume.Volume())
+		agentContainer.VolumeMounts = append(agentContainer.VolumeMounts, certsVolume.VolumeMount())
+	}
+
+	return agentv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        IntegrationAgent(as.Name),
+			Namespace:   as.Namespace,
+			Labels:      maps.Clone(meta.Labels),
+			Annotations: meta.Annotations,
+		},
+		Spec: agentv1alpha1.AgentSpec{
+			Version:              as.Spec.Version,
+			Image:                as.Spec.Image,
+			Mode:                 agentv1alpha1.AgentFleetMode,
+			PolicyID:             agentPolicyID,
+			KibanaRef:            as.Spec.KibanaRef,
+			FleetServerRef:       as.Spec.FleetServerRef,
+			ServiceAccountName:   as.Spec.ServiceAccountName,
+			RevisionHistoryLimit: as.Spec.RevisionHistoryLimit,
+			Deployment: &agentv1alpha1.DeploymentSpec{
+				PodTemplate: podTemplate,
+				Replicas:    ptr.To(as.Spec.Count),
+			},
+		},
+	}
+}
+
+// reconcileIntegrationAgent reconciles the Elastic Agent running the APM integration and returns it.
+func reconcileIntegrationAgent(
+	ctx context.Context,
+	c k8s.Client,
+	as *apmv1.ApmServer,
+	meta metadata.Metadata,
+	agentPolicyID string,
+	tokenSecretName string,
+) (agentv1alpha1.Agent, error) {
+	expected := newIntegrationAgent(*as, meta, agentPolicyID, tokenSecretName)
+	// label the Agent with a hash of its content, for comparison purposes
+	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected.Spec)
+
+	reconciled := &agentv1alpha1.Agent{}
+	err := reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     c,
+		Owner:      as,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return hash.GetTemplateHashLabel(expected.Labels) != hash.GetTemplateHashLabel(reconciled.Labels)
+		},
+		UpdateReconciled: func() {
+			reconciled.Labels = expected.Labels
+			reconciled.Annotations = expected.Annotations
+			// overwrite the spec but leave the status intact
+			reconciled.Spec = expected.Spec
+		},
+	})
+	return *reconciled, err
+}
+
+// isAgentAvailable returns true if at least one Pod of the given Elastic Agent is available.
+func isAgentAvailable(a agentv1alpha1.Agent) bool {
+	return a.Status.AvailableNodes > 0
+}
+
+// getIntegrationAgent returns the Elastic Agent running the APM integration, if it exists and is controlled by the APM Server.
+func getIntegrationAgent(ctx context.Context, c k8s.Client, as *apmv1.ApmServer) (agentv1alpha1.Agent, bool, error) {
+	var a agentv1alpha1.Agent
+	err := c.Get(ctx, types.NamespacedName{Namespace: as.Namespace, Name: IntegrationAgent(as.Name)}, &a)
+	if apierrors.IsNotFound(err) {
+		return agentv1alpha1.Agent{}, false, nil
+	}
+	if err != nil {
+		return agentv1alpha1.Agent{}, false, err
+	}
+	if !metav1.IsControlledBy(&a, as) {
+		return agentv1alpha1.Agent{}, false, nil
+	}
+	return a, true, nil
+}
+
+// deleteIntegrationAgent deletes the Elastic Agent running the APM integration, if it is controlled by the APM Server.
+// The Fleet policies are left untouched.
+func deleteIntegrationAgent(ctx context.Context, c k8s.Client, as *apmv1.ApmServer) error {
+	a, exists, err := getIntegrationAgent(ctx, c, as)
+	if err != nil || !exists {
+		return err
+	}
+	ulog.FromContext(ctx).Info("Deleting the Elastic Agent running the APM integration", "namespace", as.Namespace, "as_name", as.Name)
+	return client.IgnoreNotFound(c.Delete(ctx, &a))
+}
+
+// deleteStandaloneDeployment deletes the Deployment of the standalone APM Server, and its HorizontalPodAutoscaler,
+// if they are controlled by the APM Server.
+func deleteStandaloneDeployment(ctx context.Context, c k8s.Client, as *apmv1.ApmServer) error {
+	var d appsv1.Deployment
+	err := c.Get(ctx, types.NamespacedName{Namespace: as.Namespace, Name: Deployment(as.Name)}, &d)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := deployment.ReconcileHorizontalPodAutoscaler(ctx, c, d, nil, metadata.Metadata{}, as); err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(&d, as) {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Deleting the standalone APM Server Deployment", "namespace", as.Namespace, "as_name", as.Name)
+	return client.IgnoreNotFound(c.Delete(ctx, &d))
+}
+
+// serviceSelector returns the selector of the APM Server Service, or nil to select the standalone APM Server Pods.
+// The Service is switched over between the standalone APM Server and the Elastic Agent running the APM integration
+// only once the Pods of the target mode are available, so that instrumented applications are not interrupted.
+func (r *ReconcileApmServer) serviceSelector(ctx context.Context, as *apmv1.ApmServer) (map[string]string, error) {
+	integrationAgent, exists, err := getIntegrationAgent(ctx, r.Client, as)
+	if err != nil || !exists || !isAgentAvailable(integrationAgent) {
+		return nil, err
+	}
+	if as.IsIntegrationMode() {
+		return integrationAgent.GetIdentityLabels(), nil
+	}
+	// back to standalone mode: keep selecting the Elastic Agent until the standalone APM Server is available
+	var d appsv1.Deployment
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: as.Namespace, Name: Deployment(as.Name)}, &d)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	if d.Status.AvailableReplicas > 0 {
+		return nil, nil
+	}
+	return integrationAgent.GetIdentityLabels(), nil
+}
+
+// integrationHealth converts the health of the Elastic Agent running the APM integration into an APM Server health.
+func integrationHealth(a agentv1alpha1.Agent) commonv1.DeploymentHealth {
+	if a.Status.Health == agentv1alpha1.AgentGreenHealth {
+		return commonv1.GreenHealth
+	}
+	return commonv1.RedHealth
+}
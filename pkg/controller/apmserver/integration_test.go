@@ -0,0 +1,315 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package apmserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/agent/v1alpha1"
+	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+// fakeFleetAPI mocks the Fleet API, returning the response registered for each request method, path and query.
+type fakeFleetAPI struct {
+	responses map[string]string
+	requests  *[]string
+}
+
+func (f fakeFleetAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.Method + " " + req.URL.Path
+	if req.URL.RawQuery != "" {
+		key += "?" + req.URL.RawQuery
+	}
+	*f.requests = append(*f.requests, key)
+	body, exists := f.responses[key]
+	statusCode := http.StatusOK
+	if !exists {
+		statusCode = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func integrationApmServer(status *apmv1.IntegrationStatus) apmv1.ApmServer {
+	return apmv1.ApmServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "apm", Namespace: "ns", UID: "apm-uid"},
+		Spec: apmv1.ApmServerSpec{
+			Version:        "8.17.0",
+			Count:          2,
+			Mode:           apmv1.ApmServerIntegrationMode,
+			KibanaRef:      commonv1.ObjectSelector{Name: "kb"},
+			FleetServerRef: commonv1.ObjectSelector{Name: "fleet-server"},
+		},
+		Status: apmv1.ApmServerStatus{Integration: status},
+	}
+}
+
+func Test_reconcileFleetPolicies(t *testing.T) {
+	as := integrationApmServer(nil)
+	expectedHash := hash.HashObject(newPackagePolicy(as, "agent-policy"))
+	existingPackagePolicy := `{"item":{"id":"package-policy","name":"eck-apm-ns-apm"}}`
+
+	tests := []struct {
+		name         string
+		status       *apmv1.IntegrationStatus
+		responses    map[string]string
+		wantStatus   *apmv1.IntegrationStatus
+		wantRequests []string
+	}{
+		{
+			name: "first reconciliation: setup Fleet and create the policies",
+			responses: map[string]string{
+				"POST /api/fleet/setup":                             `{}`,
+				"GET /api/fleet/agent_policies?perPage=20&page=1":   `{"items":[{"id":"other","name":"other"}]}`,
+				"GET /api/fleet/agent_policies?perPage=20&page=2":   `{"items":[]}`,
+				"POST /api/fleet/agent_policies":                    `{"item":{"id":"agent-policy","name":"eck-apm-ns-apm"}}`,
+				"GET /api/fleet/package_policies?perPage=20&page=1": `{"items":[]}`,
+				"POST /api/fleet/package_policies":                  existingPackagePolicy,
+			},
+			wantStatus: &apmv1.IntegrationStatus{
+				Agent:             "apm-apm-agent",
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "package-policy",
+				PackagePolicyHash: expectedHash,
+			},
+			wantRequests: []string{
+				"POST /api/fleet/setup",
+				"GET /api/fleet/agent_policies?perPage=20&page=1",
+				"GET /api/fleet/agent_policies?perPage=20&page=2",
+				"POST /api/fleet/agent_policies",
+				"GET /api/fleet/package_policies?perPage=20&page=1",
+				"POST /api/fleet/package_policies",
+			},
+		},
+		{
+			name: "policies up-to-date: nothing to do",
+			status: &apmv1.IntegrationStatus{
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "package-policy",
+				PackagePolicyHash: expectedHash,
+			},
+			responses: map[string]string{
+				"GET /api/fleet/agent_policies/agent-policy":     `{"item":{"id":"agent-policy","name":"eck-apm-ns-apm"}}`,
+				"GET /api/fleet/package_policies/package-policy": existingPackagePolicy,
+			},
+			wantStatus: &apmv1.IntegrationStatus{
+				Agent:             "apm-apm-agent",
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "package-policy",
+				PackagePolicyHash: expectedHash,
+			},
+			wantRequests: []string{
+				"GET /api/fleet/agent_policies/agent-policy",
+				"GET /api/fleet/package_policies/package-policy",
+			},
+		},
+		{
+			name: "settings changed: update the package policy",
+			status: &apmv1.IntegrationStatus{
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "package-policy",
+				PackagePolicyHash: "outdated",
+			},
+			responses: map[string]string{
+				"GET /api/fleet/agent_policies/agent-policy":     `{"item":{"id":"agent-policy","name":"eck-apm-ns-apm"}}`,
+				"GET /api/fleet/package_policies/package-policy": existingPackagePolicy,
+				"PUT /api/fleet/package_policies/package-policy": existingPackagePolicy,
+			},
+			wantStatus: &apmv1.IntegrationStatus{
+				Agent:             "apm-apm-agent",
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "package-policy",
+				PackagePolicyHash: expectedHash,
+			},
+			wantRequests: []string{
+				"GET /api/fleet/agent_policies/agent-policy",
+				"GET /api/fleet/package_policies/package-policy",
+				"PUT /api/fleet/package_policies/package-policy",
+			},
+		},
+		{
+			name: "package policy deleted in Fleet: update the one found by name",
+			status: &apmv1.IntegrationStatus{
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "deleted",
+				PackagePolicyHash: expectedHash,
+			},
+			responses: map[string]string{
+				"GET /api/fleet/agent_policies/agent-policy":        `{"item":{"id":"agent-policy","name":"eck-apm-ns-apm"}}`,
+				"GET /api/fleet/package_policies?perPage=20&page=1": `{"items":[{"id":"package-policy","name":"eck-apm-ns-apm"}]}`,
+				"PUT /api/fleet/package_policies/package-policy":    existingPackagePolicy,
+			},
+			wantStatus: &apmv1.IntegrationStatus{
+				Agent:             "apm-apm-agent",
+				AgentPolicyID:     "agent-policy",
+				PackagePolicyID:   "package-policy",
+				PackagePolicyHash: expectedHash,
+			},
+			wantRequests: []string{
+				"GET /api/fleet/agent_policies/agent-policy",
+				"GET /api/fleet/package_policies/deleted",
+				"GET /api/fleet/package_policies?perPage=20&page=1",
+				"PUT /api/fleet/package_policies/package-policy",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			api := fleetAPI{
+				client: &http.Client{Transport: fakeFleetAPI{responses: tt.responses, requests: &requests}},
+				log:    ulog.Log,
+			}
+			as := integrationApmServer(tt.status)
+			status, err := reconcileFleetPolicies(context.Background(), api, &as)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_newPackagePolicy(t *testing.T) {
+	as := integrationApmServer(nil)
+	policy := newPackagePolicy(as, "agent-policy")
+	// the policy must serve the same endpoint and secret token as the standalone APM Server
+	bytes, err := json.Marshal(policy)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"name": "eck-apm-ns-apm",
+		"namespace": "default",
+		"policy_id": "agent-policy",
+		"package": {"name": "apm", "version": "8.17.0"},
+		"inputs": [{
+			"type": "apm",
+			"policy_template": "apmserver",
+			"enabled": true,
+			"streams": [],
+			"vars": {
+				"host": {"type": "text", "value": "0.0.0.0:8200"},
+				"url": {"type": "text", "value": "https://apm-apm-http.ns.svc:8200"},
+				"secret_token": {"type": "text", "value": "${env.APM_SECRET_TOKEN}"},
+				"tls_enabled": {"type": "bool", "value": true},
+				"tls_certificate": {"type": "text", "value": "/mnt/elastic-internal/http-certs/tls.crt"},
+				"tls_key": {"type": "text", "value": "/mnt/elastic-internal/http-certs/tls.key"}
+			}
+		}]
+	}`, string(bytes))
+}
+
+func Test_newIntegrationAgent(t *testing.T) {
+	as := integrationApmServer(nil)
+	as.Spec.PodTemplate.Spec.Containers = []corev1.Container{{Name: "agent", Image: "custom"}}
+	meta := metadata.Metadata{Labels: as.GetIdentityLabels()}
+
+	a := newIntegrationAgent(as, meta, "agent-policy", SecretToken(as.Name))
+	require.Equal(t, "apm-apm-agent", a.Name)
+	require.Equal(t, agentv1alpha1.AgentFleetMode, a.Spec.Mode)
+	require.Equal(t, "agent-policy", a.Spec.PolicyID)
+	require.Equal(t, as.Spec.KibanaRef, a.Spec.KibanaRef)
+	require.Equal(t, as.Spec.FleetServerRef, a.Spec.FleetServerRef)
+	require.Equal(t, ptr.To[int32](2), a.Spec.Deployment.Replicas)
+
+	// the HTTP certificates are mounted into the existing agent container
+	certsVolume := certificates.HTTPCertSecretVolume(Namer, as.Name)
+	require.Equal(t, []corev1.Volume{certsVolume.Volume()}, a.Spec.Deployment.PodTemplate.Spec.Volumes)
+	require.Len(t, a.Spec.Deployment.PodTemplate.Spec.Containers, 1)
+	require.Equal(t, []corev1.VolumeMount{certsVolume.VolumeMount()}, a.Spec.Deployment.PodTemplate.Spec.Containers[0].VolumeMounts)
+	// the secret token is read from the APM Server token Secret
+	require.Equal(t, []corev1.EnvVar{{
+		Name: integrationSecretTokenEnvVar,
+		ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "apm-apm-token"},
+			Key:                  SecretTokenKey,
+		}},
+	}}, a.Spec.Deployment.PodTemplate.Spec.Containers[0].Env)
+	// the APM Server spec is left untouched
+	require.Empty(t, as.Spec.PodTemplate.Spec.Volumes)
+	require.Empty(t, as.Spec.PodTemplate.Spec.Containers[0].VolumeMounts)
+	require.Empty(t, as.Spec.PodTemplate.Spec.Containers[0].Env)
+}
+
+func TestReconcileApmServer_serviceSelector(t *testing.T) {
+	standalone := integrationApmServer(nil)
+	standalone.Spec.Mode = apmv1.ApmServerStandaloneMode
+	integration := integrationApmServer(nil)
+
+	agentWithAvailableNodes := func(as apmv1.ApmServer, available int32) *agentv1alpha1.Agent {
+		a := newIntegrationAgent(as, metadata.Metadata{}, "agent-policy", SecretToken(as.Name))
+		a.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(&as, apmv1.GroupVersion.WithKind(apmv1.Kind))}
+		a.Status.AvailableNodes = available
+		return &a
+	}
+	deploymentWithAvailableReplicas := func(available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: Deployment("apm"), Namespace: "ns"},
+			Status:     appsv1.DeploymentStatus{AvailableReplicas: available},
+		}
+	}
+	agentLabels := map[string]string{"common.k8s.elastic.co/type": "agent", "agent.k8s.elastic.co/name": "apm-apm-agent"}
+
+	tests := []struct {
+		name      string
+		as        apmv1.ApmServer
+		resources []client.Object
+		want      map[string]string
+	}{
+		{
+			name: "standalone mode",
+			as:   standalone,
+		},
+		{
+			name:      "integration mode: Elastic Agent not available yet",
+			as:        integration,
+			resources: []client.Object{agentWithAvailableNodes(integration, 0), deploymentWithAvailableReplicas(1)},
+		},
+		{
+			name:      "integration mode: Elastic Agent available",
+			as:        integration,
+			resources: []client.Object{agentWithAvailableNodes(integration, 1), deploymentWithAvailableReplicas(1)},
+			want:      agentLabels,
+		},
+		{
+			name:      "back to standalone mode: standalone APM Server not available yet",
+			as:        standalone,
+			resources: []client.Object{agentWithAvailableNodes(standalone, 1)},
+			want:      agentLabels,
+		},
+		{
+			name:      "back to standalone mode: standalone APM Server available",
+			as:        standalone,
+			resources: []client.Object{agentWithAvailableNodes(standalone, 1), deploymentWithAvailableReplicas(1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ReconcileApmServer{Client: k8s.NewFakeClient(tt.resources...)}
+			got, err := r.serviceSelector(context.Background(), &tt.as)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -13,6 +13,7 @@ const (
 	httpServiceSuffix = "http"
 	configSuffix      = "config"
 	deploymentSuffix  = "server"
+	agentSuffix       = "agent"
 )
 
 // Namer configured with the defaults for resources related to an APM resource.
@@ -33,3 +34,8 @@ func Deployment(apmName string) string {
 func Config(apmName string) string {
 	return Namer.Suffix(apmName, configSuffix)
 }
+
+// IntegrationAgent returns the name of the Elastic Agent running the APM integration.
+func IntegrationAgent(apmName string) string {
+	return Namer.Suffix(apmName, agentSuffix)
+}
@@ -146,7 +146,8 @@ func newPodSpec(c k8s.Client, as *apmv1.ApmServer, p PodSpecParams, meta metadat
 		WithLabels(meta.Labels).
 		WithAnnotations(meta.Annotations).
 		WithResources(DefaultResources).
-		WithDockerImage(p.CustomImageName, container.ImageRepository(container.APMServerImage, v)).
+		WithDockerImage(p.CustomImageName, container.ImageRepository(as.Namespace, container.APMServerImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(as.Namespace)...).
 		WithReadinessProbe(readinessProbe(as.Spec.HTTP.TLS.Enabled())).
 		WithPorts(ports).
 		WithArgs(args...).
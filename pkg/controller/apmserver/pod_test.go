@@ -96,7 +96,7 @@ func TestNewPodSpec(t *testing.T) {
 					Containers: []corev1.Container{
 						{
 							Name:  apmv1.ApmServerContainerName,
-							Image: container.ImageRepository(container.APMServerImage, version.MustParse("7.0.1")),
+							Image: container.ImageRepository("", container.APMServerImage, version.MustParse("7.0.1")),
 							Env: []corev1.EnvVar{
 								{
 									Name: settings.EnvPodIP,
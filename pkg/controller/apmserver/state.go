@@ -9,8 +9,11 @@ import (
 
 	v1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/agent/v1alpha1"
 	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
 )
 
@@ -40,6 +43,19 @@ func (s State) UpdateApmServerState(ctx context.Context, deployment v1.Deploymen
 	return nil
 }
 
+// UpdateApmServerIntegrationState updates the ApmServer status based on the Elastic Agent running the APM integration.
+func (s State) UpdateApmServerIntegrationState(agent agentv1alpha1.Agent, apmServerSecret corev1.Secret, integration *apmv1.IntegrationStatus) {
+	s.ApmServer.Status.DeploymentStatus = commonv1.DeploymentStatus{
+		Selector:       labels.SelectorFromSet(agent.GetIdentityLabels()).String(),
+		Count:          agent.Status.ExpectedNodes,
+		AvailableNodes: agent.Status.AvailableNodes,
+		Version:        agent.Status.Version,
+		Health:         integrationHealth(agent),
+	}
+	s.ApmServer.Status.SecretTokenSecretName = apmServerSecret.Name
+	s.ApmServer.Status.Integration = integration
+}
+
 // UpdateApmServerExternalService updates the ApmServer ExternalService status.
 func (s State) UpdateApmServerExternalService(svc corev1.Service) {
 	s.ApmServer.Status.ExternalService = svc.Name
@@ -45,7 +45,7 @@ func AddAssociationController(
 		Parameters:             params,
 		referencedResourceKind: referencedResourceKind,
 	}
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, params, r.AssociatedObjTemplate())
 	if err != nil {
 		return err
 	}
@@ -6,7 +6,9 @@ package controller
 
 import (
 	"context"
+	"strings"
 
+	pkgerrors "github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,13 +51,29 @@ func AddApmKibana(mgr manager.Manager, accessReviewer rbac.AccessReviewer, param
 		ElasticsearchUserCreation: &association.ElasticsearchUserCreation{
 			ElasticsearchRef: getElasticsearchFromKibana,
 			UserSecretSuffix: "apm-kb-user",
-			ESUserRole: func(_ commonv1.Associated) (string, error) {
-				return user.ApmAgentUserRole, nil
-			},
+			ESUserRole:       getAPMKibanaRoles,
 		},
 	})
 }
 
+func getAPMKibanaRoles(associated commonv1.Associated) (string, error) {
+	apmServer, ok := associated.(*apmv1.ApmServer)
+	if !ok {
+		return "", pkgerrors.Errorf(
+			"ApmServer expected, got %s/%s",
+			associated.GetObjectKind().GroupVersionKind().Group,
+			associated.GetObjectKind().GroupVersionKind().Kind,
+		)
+	}
+	if apmServer.IsIntegrationMode() {
+		return strings.Join([]string{
+			user.ApmAgentUserRole,   // Manage APM agent central configuration
+			user.FleetAdminUserRole, // Manage the Fleet policy of the APM integration
+		}, ","), nil
+	}
+	return user.ApmAgentUserRole, nil
+}
+
 func getKibanaExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
 	kibanaRef := assoc.AssociationRef()
 	if !kibanaRef.IsSet() {
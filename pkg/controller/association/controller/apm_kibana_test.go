@@ -0,0 +1,51 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+)
+
+func Test_getAPMKibanaRoles(t *testing.T) {
+	tests := []struct {
+		name       string
+		associated commonv1.Associated
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "standalone mode",
+			associated: &apmv1.ApmServer{Spec: apmv1.ApmServerSpec{Version: "8.17.0"}},
+			want:       "eck_apm_agent_user_role",
+		},
+		{
+			name:       "integration mode",
+			associated: &apmv1.ApmServer{Spec: apmv1.ApmServerSpec{Version: "8.17.0", Mode: apmv1.ApmServerIntegrationMode}},
+			want:       "eck_apm_agent_user_role,eck_fleet_admin_user_role",
+		},
+		{
+			name:       "not an APM Server",
+			associated: &kbv1.Kibana{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getAPMKibanaRoles(tt.associated)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
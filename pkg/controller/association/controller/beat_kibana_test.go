@@ -10,7 +10,7 @@ import (
 	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/filebeat"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 )
 
 func Test_getBeatKibanaRoles(t *testing.T) {
@@ -38,19 +38,19 @@ func Test_getBeatKibanaRoles(t *testing.T) {
 		},
 		{
 			name:    "<7.3 Beat",
-			args:    &beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Version: "7.0.0", Type: string(filebeat.Type)}},
+			args:    &beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Version: "7.0.0", Type: string(beattype.Filebeat)}},
 			want:    "kibana_user,ingest_admin,beats_admin,eck_beat_kibana_filebeat_role_v70",
 			wantErr: false,
 		},
 		{
 			name:    "<7.7 Beat",
-			args:    &beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Version: "7.6.0", Type: string(filebeat.Type)}},
+			args:    &beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Version: "7.6.0", Type: string(beattype.Filebeat)}},
 			want:    "kibana_user,ingest_admin,beats_admin,eck_beat_kibana_filebeat_role_v73",
 			wantErr: false,
 		},
 		{
 			name:    ">=7.8 Beat",
-			args:    &beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Version: "7.8.0", Type: string(filebeat.Type)}},
+			args:    &beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Version: "7.8.0", Type: string(beattype.Filebeat)}},
 			want:    "kibana_admin,ingest_admin,beats_admin,eck_beat_kibana_filebeat_role_v77",
 			wantErr: false,
 		},
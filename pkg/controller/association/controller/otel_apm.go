@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/apmserver"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/rbac"
+)
+
+func AddOTelCollectorApm(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
+	return association.AddAssociationController(mgr, accessReviewer, params, association.AssociationInfo{
+		AssociationType:           commonv1.ApmServerAssociationType,
+		AssociatedObjTemplate:     func() commonv1.Associated { return &otelv1alpha1.OTelCollector{} },
+		ReferencedObjTemplate:     func() client.Object { return &apmv1.ApmServer{} },
+		ReferencedResourceVersion: referencedApmServerStatusVersion,
+		ExternalServiceURL:        getApmServerExternalURL,
+		ReferencedResourceNamer:   apmserver.Namer,
+		AssociationName:           "otel-apm",
+		AssociatedShortName:       "otel",
+		// the collector authenticates with the secret token of the APM Server
+		AdditionalSecrets: apmServerSecretToken,
+		Labels: func(associated types.NamespacedName) map[string]string {
+			return map[string]string{
+				OTelCollectorAssociationLabelName:      associated.Name,
+				OTelCollectorAssociationLabelNamespace: associated.Namespace,
+				OTelCollectorAssociationLabelType:      commonv1.ApmServerAssociationType,
+			}
+		},
+		AssociationConfAnnotationNameBase:     commonv1.ApmServerConfigAnnotationNameBase,
+		AssociationResourceNameLabelName:      apmserver.ApmServerNameLabelName,
+		AssociationResourceNamespaceLabelName: apmserver.ApmServerNamespaceLabelName,
+
+		ElasticsearchUserCreation: nil,
+	})
+}
+
+// apmServerSecretToken returns the Secret holding the secret token of the referenced APM Server, to be copied into the
+// namespace of the associated resource.
+func apmServerSecretToken(_ context.Context, _ k8s.Client, assoc commonv1.Association) ([]types.NamespacedName, error) {
+	apmRef := assoc.AssociationRef()
+	if !apmRef.IsSet() {
+		return nil, nil
+	}
+	return []types.NamespacedName{{Namespace: apmRef.GetNamespace(), Name: apmserver.SecretToken(apmRef.GetName())}}, nil
+}
+
+func getApmServerExternalURL(c k8s.Client, assoc commonv1.Association) (string, error) {
+	apmRef := assoc.AssociationRef()
+	if !apmRef.IsSet() {
+		return "", nil
+	}
+	var as apmv1.ApmServer
+	if err := c.Get(context.Background(), apmRef.NamespacedName(), &as); err != nil {
+		return "", err
+	}
+	serviceName := apmRef.GetServiceName()
+	if serviceName == "" {
+		serviceName = apmserver.HTTPService(as.Name)
+	}
+	nsn := types.NamespacedName{Namespace: as.Namespace, Name: serviceName}
+	return association.ServiceURL(c, nsn, as.Spec.HTTP.Protocol(), "")
+}
+
+// referencedApmServerStatusVersion returns the currently running version of APM Server
+// reported in its status.
+func referencedApmServerStatusVersion(c k8s.Client, apmAssociation commonv1.Association) (string, bool, error) {
+	var as apmv1.ApmServer
+	if err := c.Get(context.Background(), apmAssociation.AssociationRef().NamespacedName(), &as); err != nil {
+		return "", false, err
+	}
+	return as.Status.Version, false, nil
+}
@@ -29,20 +29,17 @@ const (
 	autoOpsESConfigFileName = "autoops_es.yml"
 )
 
-// autoOpsESConfigTemplate contains the configuration template for the autoops agent
+// autoOpsESConfigTemplate contains the configuration template for the autoops agent.
+// Each Elasticsearch cluster monitored by the agent is configured with its own metrics and templates modules.
 const autoOpsESConfigTemplate = `receivers:
   metricbeatreceiver:
     metricbeat:
       modules:
+{{- range .Clusters}}
         # Metrics
         - module: autoops_es
-          hosts: ${env:AUTOOPS_ES_URL}
-{{- if .SSLEnabled}}
-          ssl.verification_mode: certificate
-          ssl.certificate_authorities: ["{{ .CACertPath }}"]
-{{- else}}
-          ssl.verification_mode: none
-{{- end}}
+          hosts: {{ .Hosts }}
+{{- template "connection" .}}
           period: 10s
           metricsets:
             - cat_shards
@@ -53,18 +50,14 @@ const autoOpsESConfigTemplate = `receivers:
             - tasks_management
         # Templates
         - module: autoops_es
-          hosts: ${env:AUTOOPS_ES_URL}
-{{- if .SSLEnabled}}
-          ssl.verification_mode: certificate
-          ssl.certificate_authorities: ["{{ .CACertPath }}"]
-{{- else}}
-          ssl.verification_mode: none
-{{- end}}
+          hosts: {{ .Hosts }}
+{{- template "connection" .}}
           period: 24h
           metricsets:
             - cat_template
             - component_template
             - index_template
+{{- end}}
     processors:
       - add_fields:
           target: autoops_es
@@ -104,12 +97,49 @@ extensions:
         path: "/health/config"
 `
 
+// connectionTemplate contains the configuration template for the connection to an Elasticsearch cluster
+const connectionTemplate = `{{define "connection"}}
+{{- if .APIKey}}
+          headers:
+            Authorization: "ApiKey {{ .APIKey }}"
+{{- end}}
+{{- if .SSLEnabled}}
+          ssl.verification_mode: certificate
+          ssl.certificate_authorities: ["{{ .CACertPath }}"]
+{{- else}}
+          ssl.verification_mode: none
+{{- end}}
+{{- end}}`
+
 // configTemplateData holds the data for rendering the config template
 type configTemplateData struct {
+	Clusters []clusterTemplateData
+}
+
+// clusterTemplateData holds the data for rendering the configuration of a single Elasticsearch cluster
+type clusterTemplateData struct {
+	// Hosts of the Elasticsearch cluster, as a YAML value.
+	Hosts string
+	// APIKey is the encoded API key used to authenticate, if not provided through the environment.
+	APIKey     string
 	SSLEnabled bool
 	CACertPath string
 }
 
+// renderConfig renders the autoops agent configuration for the given Elasticsearch clusters.
+func renderConfig(clusters []clusterTemplateData) (string, error) {
+	tmpl, err := template.New("autoops-config").Parse(autoOpsESConfigTemplate + connectionTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	var configBuf bytes.Buffer
+	if err := tmpl.Execute(&configBuf, configTemplateData{Clusters: clusters}); err != nil {
+		return "", err
+	}
+	return configBuf.String(), nil
+}
+
 // ReconcileAutoOpsESConfigMap reconciles the ConfigMap containing the autoops configuration
 // specific to each ES instance. This also returns the config hash of the ConfigMap to avoid
 // retrieving it from the cache later and delaying the initial deployment.
@@ -165,17 +195,12 @@ func buildAutoOpsESConfigMap(policy autoopsv1alpha1.AutoOpsAgentPolicy, es esv1.
 		)
 	}
 
-	tmpl, err := template.New("autoops-config").Parse(autoOpsESConfigTemplate)
-	if err != nil {
-		return corev1.ConfigMap{}, err
-	}
-
-	var configBuf bytes.Buffer
-	templateData := configTemplateData{
+	config, err := renderConfig([]clusterTemplateData{{
+		Hosts:      "${env:AUTOOPS_ES_URL}",
 		SSLEnabled: sslEnabled,
 		CACertPath: caCertPath,
-	}
-	if err := tmpl.Execute(&configBuf, templateData); err != nil {
+	}})
+	if err != nil {
 		return corev1.ConfigMap{}, err
 	}
 
@@ -190,7 +215,7 @@ func buildAutoOpsESConfigMap(policy autoopsv1alpha1.AutoOpsAgentPolicy, es esv1.
 			Annotations: meta.Annotations,
 		},
 		Data: map[string]string{
-			autoOpsESConfigFileName: configBuf.String(),
+			autoOpsESConfigFileName: config,
 		},
 	}, nil
 }
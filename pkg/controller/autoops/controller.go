@@ -46,7 +46,7 @@ const (
 // and start it when the manager is started.
 func Add(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
 	r := newReconciler(mgr, accessReviewer, params)
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, params, &autoopsv1alpha1.AutoOpsAgentPolicy{})
 	if err != nil {
 		return err
 	}
@@ -7,6 +7,7 @@ package autoops
 import (
 	"context"
 	"fmt"
+	"hash"
 	"hash/fnv"
 	"path"
 
@@ -67,13 +68,6 @@ func resourceLabelsFor(policy autoopsv1alpha1.AutoOpsAgentPolicy, es esv1.Elasti
 }
 
 func (r *AgentPolicyReconciler) buildDeployment(configHash string, policy autoopsv1alpha1.AutoOpsAgentPolicy, es esv1.Elasticsearch) (appsv1.Deployment, error) {
-	v, err := version.Parse(policy.Spec.Version)
-	if err != nil {
-		return appsv1.Deployment{}, err
-	}
-
-	labels := resourceLabelsFor(policy, es)
-
 	// Create ES-specific ConfigMap volume
 	configMapName := autoopsv1alpha1.Config(policy.GetName(), es)
 	configVolume := volume.NewConfigMapVolume(configMapName, configVolumeName, configVolumePath)
@@ -93,17 +87,55 @@ func (r *AgentPolicyReconciler) buildDeployment(configHash string, policy autoop
 		volumeMounts = append(volumeMounts, caVolume.VolumeMount())
 	}
 
-	annotations := map[string]string{configHashAnnotationName: configHash}
-	meta := metadata.Propagate(&policy, metadata.Metadata{Labels: labels, Annotations: annotations})
+	return r.newAgentDeployment(policy, agentDeploymentParams{
+		name:   autoopsv1alpha1.Deployment(policy.GetName(), es),
+		labels: resourceLabelsFor(policy, es),
+		// the Elasticsearch cluster labels are part of the selector for the Deployments of a policy not to select each
+		// other's Pods
+		selector: map[string]string{
+			PolicyNameLabelKey:                  policy.GetName(),
+			commonapikey.MetadataKeyESName:      es.Name,
+			commonapikey.MetadataKeyESNamespace: es.Namespace,
+		},
+		configHash:   configHash,
+		configFile:   autoOpsESConfigFileName,
+		env:          autoopsEnvVars(policy, es),
+		volumes:      volumes,
+		volumeMounts: volumeMounts,
+	})
+}
+
+// agentDeploymentParams holds what differs between the AutoOps agent Deployments.
+type agentDeploymentParams struct {
+	name         string
+	labels       map[string]string
+	selector     map[string]string
+	configHash   string
+	configFile   string
+	env          []corev1.EnvVar
+	volumes      []corev1.Volume
+	volumeMounts []corev1.VolumeMount
+}
+
+// newAgentDeployment builds an AutoOps agent Deployment running the configuration file mounted in the config volume.
+func (r *AgentPolicyReconciler) newAgentDeployment(policy autoopsv1alpha1.AutoOpsAgentPolicy, params agentDeploymentParams) (appsv1.Deployment, error) {
+	v, err := version.Parse(policy.Spec.Version)
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+
+	annotations := map[string]string{configHashAnnotationName: params.configHash}
+	meta := metadata.Propagate(&policy, metadata.Metadata{Labels: params.labels, Annotations: annotations})
 	builder := defaults.NewPodTemplateBuilder(policy.Spec.PodTemplate, autoOpsAgentType).
-		WithArgs("--config", path.Join(configVolumePath, autoOpsESConfigFileName)).
+		WithArgs("--config", path.Join(configVolumePath, params.configFile)).
 		WithLabels(meta.Labels).
 		WithAnnotations(meta.Annotations).
-		WithDockerImage(policy.Spec.Image, container.ImageRepository(container.AutoOpsAgentImage, v)).
-		WithEnv(autoopsEnvVars(policy, es)...).
+		WithDockerImage(policy.Spec.Image, container.ImageRepository(policy.Namespace, container.AutoOpsAgentImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(policy.Namespace)...).
+		WithEnv(params.env...).
 		WithResources(defaultResources).
-		WithVolumes(volumes...).
-		WithVolumeMounts(volumeMounts...).
+		WithVolumes(params.volumes...).
+		WithVolumeMounts(params.volumeMounts...).
 		WithPorts([]corev1.ContainerPort{{Name: "http", ContainerPort: int32(readinessProbePort), Protocol: corev1.ProtocolTCP}}).
 		WithReadinessProbe(readinessProbe()).
 		WithContainersSecurityContext(corev1.SecurityContext{
@@ -132,11 +164,9 @@ func (r *AgentPolicyReconciler) buildDeployment(configHash string, policy autoop
 	}
 
 	return common_deployment.New(common_deployment.Params{
-		Name:      autoopsv1alpha1.Deployment(policy.GetName(), es),
-		Namespace: policy.GetNamespace(),
-		Selector: map[string]string{
-			PolicyNameLabelKey: policy.GetName(),
-		},
+		Name:                 params.name,
+		Namespace:            policy.GetNamespace(),
+		Selector:             params.selector,
 		Metadata:             meta,
 		PodTemplateSpec:      builder.PodTemplate,
 		Replicas:             1,
@@ -172,11 +202,26 @@ func buildConfigHash(ctx context.Context, configMap corev1.ConfigMap, apiKeySecr
 		_, _ = configHash.Write([]byte(configData))
 	}
 
+	if err := hashAutoOpsSecret(ctx, c, policy, configHash); err != nil {
+		return "", err
+	}
+
+	// This data may not exist on initial reconciliation, so we don't return an error if it's missing.
+	// This should resolve itself on the next reconciliation after the API key is created.
+	if apiKeyData, ok := apiKeySecret.Data[apiKeySecretKey]; ok {
+		_, _ = configHash.Write(apiKeyData)
+	}
+
+	return fmt.Sprint(configHash.Sum32()), nil
+}
+
+// hashAutoOpsSecret writes the values of the autoops-secret referenced by the policy to the given hash.
+func hashAutoOpsSecret(ctx context.Context, c k8s.Client, policy autoopsv1alpha1.AutoOpsAgentPolicy, configHash hash.Hash) error {
 	// Hash secret values from autoops-secret
 	autoopsSecretNSN := types.NamespacedName{Namespace: policy.Namespace, Name: policy.Spec.AutoOpsRef.SecretName}
 	var autoopsSecret corev1.Secret
 	if err := c.Get(ctx, autoopsSecretNSN, &autoopsSecret); err != nil {
-		return "", fmt.Errorf("while getting autoops configuration secret %s: %w", autoopsSecretNSN.String(), err)
+		return fmt.Errorf("while getting autoops configuration secret %s: %w", autoopsSecretNSN.String(), err)
 	}
 
 	// Hash secret keys, including optional keys. There's no code here to handle missing keys as:
@@ -190,13 +235,7 @@ func buildConfigHash(ctx context.Context, configMap corev1.ConfigMap, apiKeySecr
 		}
 	}
 
-	// This data may not exist on initial reconciliation, so we don't return an error if it's missing.
-	// This should resolve itself on the next reconciliation after the API key is created.
-	if apiKeyData, ok := apiKeySecret.Data[apiKeySecretKey]; ok {
-		_, _ = configHash.Write(apiKeyData)
-	}
-
-	return fmt.Sprint(configHash.Sum32()), nil
+	return nil
 }
 
 // autoopsEnvVars returns the environment variables for the AutoOps deployment
@@ -282,6 +282,53 @@ func cleanupOrphanedSecrets(
 	return nil
 }
 
+// cleanupOrphanedShards removes the agent Deployments and the configuration Secrets of the shards that are not active
+// in Shared mode.
+func cleanupOrphanedShards(
+	ctx context.Context,
+	log logr.Logger,
+	c k8s.Client,
+	policy autoopsv1alpha1.AutoOpsAgentPolicy,
+	matchLabels client.MatchingLabels,
+	activeShards sets.Set[string],
+) error {
+	var deployments appsv1.DeploymentList
+	if err := c.List(ctx, &deployments, client.InNamespace(policy.Namespace), matchLabels, client.HasLabels{shardLabelKey}); err != nil {
+		return err
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		shard := deployment.Labels[shardLabelKey]
+		if activeShards.Has(shard) {
+			continue
+		}
+		log.Info("Deleting orphaned shard Deployment", "deployment", deployment.Name, "shard", shard)
+		if err := c.Delete(ctx, deployment); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, client.InNamespace(policy.Namespace), matchLabels, client.MatchingLabels{policySecretTypeLabelKey: sharedConfigSecretType}); err != nil {
+		return err
+	}
+
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		shard := secret.Labels[shardLabelKey]
+		if activeShards.Has(shard) {
+			continue
+		}
+		log.Info("Deleting orphaned shard config Secret", "secret", secret.Name, "shard", shard)
+		if err := c.Delete(ctx, secret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // shouldDeleteResource determines if a resource should be deleted based on whether its ES cluster
 // matches the current selector. Returns the ES cluster namespaced name and whether to delete.
 func shouldDeleteResource(
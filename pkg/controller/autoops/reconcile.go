@@ -7,9 +7,11 @@ package autoops
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
@@ -144,7 +146,33 @@ func (r *AgentPolicyReconciler) internalReconcile(
 		return results
 	}
 
-	for _, es := range accessibleClusters {
+	if policy.Spec.IsShared() {
+		results = r.reconcileSharedAgents(ctx, policy, accessibleClusters, results, state)
+	} else {
+		results = r.reconcilePerClusterAgents(ctx, policy, accessibleClusters, results, state)
+	}
+
+	// Schedule a requeue to periodically re-check RBAC permissions.
+	// Use ReconciliationComplete() to indicate this is a periodic check, not an incomplete reconciliation.
+	if rbacResult := association.RequeueRbacCheck(r.accessReviewer); rbacResult.RequeueAfter > 0 {
+		results = results.WithReconciliationState(
+			reconciler.RequeueAfter(rbacResult.RequeueAfter).ReconciliationComplete(),
+		)
+	}
+
+	return results
+}
+
+// reconcilePerClusterAgents reconciles one agent Deployment per Elasticsearch cluster.
+func (r *AgentPolicyReconciler) reconcilePerClusterAgents(
+	ctx context.Context,
+	policy autoopsv1alpha1.AutoOpsAgentPolicy,
+	clusters []esv1.Elasticsearch,
+	results *reconciler.Results,
+	state *State,
+) *reconciler.Results {
+	log := ulog.FromContext(ctx)
+	for _, es := range clusters {
 		log := log.WithValues("es_namespace", es.Namespace, "es_name", es.Name)
 
 		if es.Status.Phase != esv1.ElasticsearchReadyPhase {
@@ -155,7 +183,7 @@ func (r *AgentPolicyReconciler) internalReconcile(
 		}
 
 		if es.Spec.HTTP.TLS.Enabled() {
-			if err := r.reconcileAutoOpsESCASecret(ctx, policy, es); err != nil {
+			if _, err := r.reconcileAutoOpsESCASecret(ctx, policy, es); err != nil {
 				log.Error(err, "while reconciling AutoOps ES CA secret")
 				state.ResourceError(es, "Failed to reconcile AutoOps ES CA secret", err)
 				results.WithError(err)
@@ -195,7 +223,7 @@ func (r *AgentPolicyReconciler) internalReconcile(
 			continue
 		}
 
-		reconciledDeployment, err := deployment.Reconcile(ctx, r.Client, deploymentParams, &policy)
+		reconciledDeployment, err := reconcileAgentDeployment(ctx, r.Client, deploymentParams, policy)
 		if err != nil {
 			log.Error(err, "while reconciling deployment")
 			state.ResourceError(es, "Failed to reconcile AutoOps agent deployment", err)
@@ -208,14 +236,6 @@ func (r *AgentPolicyReconciler) internalReconcile(
 		}
 	}
 
-	// Schedule a requeue to periodically re-check RBAC permissions.
-	// Use ReconciliationComplete() to indicate this is a periodic check, not an incomplete reconciliation.
-	if rbacResult := association.RequeueRbacCheck(r.accessReviewer); rbacResult.RequeueAfter > 0 {
-		results = results.WithReconciliationState(
-			reconciler.RequeueAfter(rbacResult.RequeueAfter).ReconciliationComplete(),
-		)
-	}
-
 	return results
 }
 
@@ -238,14 +258,29 @@ func (r *AgentPolicyReconciler) cleanupOrphanedResourcesForPolicy(
 		PolicyNameLabelKey: policy.Name,
 	}
 
-	if err := cleanupOrphanedDeployments(ctx, log, r.Client, policy, matchLabels, esSet); err != nil {
+	// In Shared mode, the agents dedicated to a cluster are replaced by the shard agents,
+	// only the CA and API key secrets of the clusters are kept.
+	agentESSet := esSet
+	if policy.Spec.IsShared() {
+		agentESSet = sets.New[types.NamespacedName]()
+	}
+
+	if err := cleanupOrphanedDeployments(ctx, log, r.Client, policy, matchLabels, agentESSet); err != nil {
 		return fmt.Errorf("while cleaning up deployments: %w", err)
 	}
 
-	if err := cleanupOrphanedConfigMaps(ctx, log, r.Client, policy, matchLabels, esSet); err != nil {
+	if err := cleanupOrphanedConfigMaps(ctx, log, r.Client, policy, matchLabels, agentESSet); err != nil {
 		return fmt.Errorf("while cleaning up configmaps: %w", err)
 	}
 
+	// Shard agents are removed as a whole outside of Shared mode, or when there is no cluster to monitor.
+	// Otherwise, they are cleaned up along with their reconciliation.
+	if !policy.Spec.IsShared() || esSet.Len() == 0 {
+		if err := cleanupOrphanedShards(ctx, log, r.Client, policy, matchLabels, sets.New[string]()); err != nil {
+			return fmt.Errorf("while cleaning up shards: %w", err)
+		}
+	}
+
 	// Cleanup both CA secrets and API Key.
 	if err := cleanupOrphanedSecrets(ctx, log, r.Client, r.esClientProvider, r.params.Dialer, policy, matchLabels, esSet); err != nil {
 		return fmt.Errorf("while cleaning up secrets: %w", err)
@@ -254,6 +289,29 @@ func (r *AgentPolicyReconciler) cleanupOrphanedResourcesForPolicy(
 	return nil
 }
 
+// reconcileAgentDeployment reconciles the given agent Deployment. The selector of a Deployment being immutable, an
+// existing Deployment with a different selector, such as one created before the Elasticsearch cluster labels were part
+// of the selector, is deleted to be recreated.
+func reconcileAgentDeployment(
+	ctx context.Context,
+	c k8s.Client,
+	expected appsv1.Deployment,
+	policy autoopsv1alpha1.AutoOpsAgentPolicy,
+) (appsv1.Deployment, error) {
+	var existing appsv1.Deployment
+	err := c.Get(ctx, k8s.ExtractNamespacedName(&expected), &existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return appsv1.Deployment{}, err
+	}
+	if err == nil && !reflect.DeepEqual(existing.Spec.Selector, expected.Spec.Selector) {
+		ulog.FromContext(ctx).Info("Deleting Deployment to update its selector", "deployment", existing.Name)
+		if err := c.Delete(ctx, &existing); err != nil && !apierrors.IsNotFound(err) {
+			return appsv1.Deployment{}, err
+		}
+	}
+	return deployment.Reconcile(ctx, c, expected, &policy)
+}
+
 // isDeploymentReady checks if a deployment is ready by verifying that the DeploymentAvailable condition is true.
 func isDeploymentReady(dep appsv1.Deployment) bool {
 	for _, condition := range dep.Status.Conditions {
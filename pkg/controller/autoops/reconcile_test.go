@@ -185,6 +185,15 @@ func TestAutoOpsAgentPolicyReconciler_internalReconcile(t *testing.T) {
 						Name:      autoopsv1alpha1.Deployment("policy-1", esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es-1", Namespace: "ns-1"}}),
 						Namespace: "ns-1",
 					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								PolicyNameLabelKey:                  "policy-1",
+								commonapikey.MetadataKeyESName:      "es-1",
+								commonapikey.MetadataKeyESNamespace: "ns-1",
+							},
+						},
+					},
 					Status: appsv1.DeploymentStatus{
 						Conditions: []appsv1.DeploymentCondition{
 							{
@@ -233,6 +242,15 @@ func TestAutoOpsAgentPolicyReconciler_internalReconcile(t *testing.T) {
 						Name:      autoopsv1alpha1.Deployment("policy-1", esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es-1", Namespace: "ns-1"}}),
 						Namespace: "ns-1",
 					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								PolicyNameLabelKey:                  "policy-1",
+								commonapikey.MetadataKeyESName:      "es-1",
+								commonapikey.MetadataKeyESNamespace: "ns-1",
+							},
+						},
+					},
 					Status: appsv1.DeploymentStatus{
 						Conditions: []appsv1.DeploymentCondition{
 							{
@@ -321,6 +339,15 @@ func TestAutoOpsAgentPolicyReconciler_internalReconcile(t *testing.T) {
 						Name:      autoopsv1alpha1.Deployment("policy-1", esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es-1", Namespace: "ns-1"}}),
 						Namespace: "ns-1",
 					},
+					Spec: appsv1.DeploymentSpec{
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								PolicyNameLabelKey:                  "policy-1",
+								commonapikey.MetadataKeyESName:      "es-1",
+								commonapikey.MetadataKeyESNamespace: "ns-1",
+							},
+						},
+					},
 					Status: appsv1.DeploymentStatus{
 						Conditions: []appsv1.DeploymentCondition{
 							{
@@ -1119,3 +1146,62 @@ func TestAutoOpsAgentPolicyReconciler_accessRevokedCleanup(t *testing.T) {
 		require.Equal(t, 1, state.status.Resources, "Resources count should be 1 after access revoked")
 	})
 }
+
+func Test_reconcileAgentDeployment(t *testing.T) {
+	policy := autoopsv1alpha1.AutoOpsAgentPolicy{ObjectMeta: metav1.ObjectMeta{Name: "policy-1", Namespace: "ns-1"}}
+	selector := map[string]string{
+		PolicyNameLabelKey:                  "policy-1",
+		commonapikey.MetadataKeyESName:      "es-1",
+		commonapikey.MetadataKeyESNamespace: "ns-1",
+	}
+	expected := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "ns-1", Labels: selector},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: selector},
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: selector}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		existing    *appsv1.Deployment
+		wantDeleted bool
+	}{
+		{
+			name: "no existing Deployment",
+		},
+		{
+			name: "existing Deployment with the same selector",
+			existing: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "ns-1", UID: "existing-uid"},
+				Spec:       appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: selector}},
+			},
+		},
+		{
+			name: "existing Deployment selecting the Pods of all the Deployments of the policy",
+			existing: &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "deploy", Namespace: "ns-1", UID: "existing-uid"},
+				Spec: appsv1.DeploymentSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+					PolicyNameLabelKey: "policy-1",
+				}}},
+			},
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c k8s.Client
+			if tt.existing != nil {
+				c = k8s.NewFakeClient(tt.existing)
+			} else {
+				c = k8s.NewFakeClient()
+			}
+
+			got, err := reconcileAgentDeployment(context.Background(), c, expected, policy)
+			require.NoError(t, err)
+			require.Equal(t, selector, got.Spec.Selector.MatchLabels)
+			// a Deployment recreated by the fake client has no UID
+			require.Equal(t, tt.existing != nil && !tt.wantDeleted, got.UID == "existing-uid")
+		})
+	}
+}
@@ -32,17 +32,18 @@ const (
 
 // reconcileAutoOpsESCASecret reconciles the Secret containing the CA certificate
 // for a specific Elasticsearch cluster, copying it from the ES instance's http-certs-public secret.
+// The CA certificate is returned to the caller, it is nil if not available yet.
 func (r *AgentPolicyReconciler) reconcileAutoOpsESCASecret(
 	ctx context.Context,
 	policy autoopsv1alpha1.AutoOpsAgentPolicy,
 	es esv1.Elasticsearch,
-) error {
+) ([]byte, error) {
 	log := ulog.FromContext(ctx).WithValues("es_namespace", es.Namespace, "es_name", es.Name)
 	log.V(1).Info("Reconciling AutoOps ES CA secret")
 
 	if es.Status.Phase != esv1.ElasticsearchReadyPhase {
 		log.V(1).Info("Skipping ES cluster that is not ready")
-		return nil
+		return nil, nil
 	}
 
 	sourceSecretKey := types.NamespacedName{
@@ -53,15 +54,15 @@ func (r *AgentPolicyReconciler) reconcileAutoOpsESCASecret(
 	if err := r.Client.Get(ctx, sourceSecretKey, &sourceSecret); err != nil {
 		if apierrors.IsNotFound(err) {
 			log.V(1).Info("ES http-certs-public secret not found, skipping")
-			return nil
+			return nil, nil
 		}
-		return fmt.Errorf("while retrieving http-certs-public secret for ES cluster %s/%s: %w", es.Namespace, es.Name, err)
+		return nil, fmt.Errorf("while retrieving http-certs-public secret for ES cluster %s/%s: %w", es.Namespace, es.Name, err)
 	}
 
 	caCert, ok := sourceSecret.Data[certificates.CertFileName]
 	if !ok || len(caCert) == 0 {
 		log.V(1).Info("tls.crt not found in http-certs-public secret, skipping")
-		return nil
+		return nil, nil
 	}
 
 	secretName := autoopsv1alpha1.CASecret(policy.GetName(), es)
@@ -88,18 +89,21 @@ func (r *AgentPolicyReconciler) reconcileAutoOpsESCASecret(
 		},
 	)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	watcher := k8s.ExtractNamespacedName(&policy)
 
 	// Add a watch for the AutoOps CA secret
-	return watches.WatchUserProvidedSecrets(
+	if err := watches.WatchUserProvidedSecrets(
 		watcher,
 		r.dynamicWatches,
 		secretName,
 		[]string{secretName},
-	)
+	); err != nil {
+		return nil, err
+	}
+	return caCert, nil
 }
 
 // buildAutoOpsESCASecret builds the expected Secret for autoops ES CA certificate.
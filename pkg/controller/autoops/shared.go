@@ -0,0 +1,378 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoops
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoopsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoops/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/services"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/maps"
+)
+
+const (
+	// shardLabelKey is the label key for the index of the shard of an AutoOps agent Deployment in Shared mode.
+	shardLabelKey = "autoops.k8s.elastic.co/shard"
+	// sharedConfigSecretType is the type of the Secrets holding the configuration of the shards in Shared mode.
+	sharedConfigSecretType = "shared-config"
+)
+
+// sharedCluster holds what a shared agent needs to monitor an Elasticsearch cluster.
+type sharedCluster struct {
+	es     esv1.Elasticsearch
+	apiKey string
+	caCert []byte
+}
+
+// sharedConfigFileName returns the name of the configuration file of the given shard.
+func sharedConfigFileName(shard int) string {
+	return fmt.Sprintf("shard-%d.yml", shard)
+}
+
+// sharedCAFileName returns the name of the file holding the CA certificate of the given Elasticsearch cluster.
+func sharedCAFileName(es types.NamespacedName) string {
+	return fmt.Sprintf("ca-%s.crt", hash.HashObject(es.Namespace+es.Name))
+}
+
+// sharedLabelsFor returns the labels of the resources shared by all the clusters of a policy in Shared mode.
+func sharedLabelsFor(policy autoopsv1alpha1.AutoOpsAgentPolicy) map[string]string {
+	return map[string]string{
+		commonv1.TypeLabelName:  autoOpsAgentType,
+		PolicyNameLabelKey:      policy.Name,
+		policyNamespaceLabelKey: policy.Namespace,
+	}
+}
+
+// assignShards distributes the given clusters among shardCount shards.
+// Clusters are assigned to the shard with the highest rendezvous hash that is not full, a shard being full when it
+// holds more than its fair share of clusters. Shards are therefore balanced and most clusters keep their shard as
+// clusters come and go.
+func assignShards(clusters []types.NamespacedName, shardCount int) [][]types.NamespacedName {
+	shards := make([][]types.NamespacedName, shardCount)
+	if shardCount == 0 {
+		return shards
+	}
+	capacity := (len(clusters) + shardCount - 1) / shardCount
+
+	sorted := slices.Clone(clusters)
+	slices.SortFunc(sorted, func(a, b types.NamespacedName) int {
+		return cmp.Compare(a.String(), b.String())
+	})
+	for _, cluster := range sorted {
+		preferences := make([]int, shardCount)
+		weights := make([]uint64, shardCount)
+		for shard := range preferences {
+			preferences[shard] = shard
+			weights[shard] = rendezvousWeight(cluster, shard)
+		}
+		slices.SortStableFunc(preferences, func(a, b int) int {
+			return cmp.Compare(weights[b], weights[a])
+		})
+		for _, shard := range preferences {
+			if len(shards[shard]) < capacity {
+				shards[shard] = append(shards[shard], cluster)
+				break
+			}
+		}
+	}
+	return shards
+}
+
+func rendezvousWeight(cluster types.NamespacedName, shard int) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(cluster.String() + "/" + strconv.Itoa(shard)))
+	return h.Sum64()
+}
+
+// reconcileSharedAgents reconciles the agent Deployments of a policy in Shared mode: the given clusters are distributed
+// among a fixed number of Deployments, each of them monitoring its clusters with a single agent.
+func (r *AgentPolicyReconciler) reconcileSharedAgents(
+	ctx context.Context,
+	policy autoopsv1alpha1.AutoOpsAgentPolicy,
+	clusters []esv1.Elasticsearch,
+	results *reconciler.Results,
+	state *State,
+) *reconciler.Results {
+	log := ulog.FromContext(ctx)
+
+	// clusters are assigned to shards whether they are ready or not, for the assignment not to change with their readiness
+	names := make([]types.NamespacedName, 0, len(clusters))
+	for _, es := range clusters {
+		names = append(names, k8s.ExtractNamespacedName(&es))
+	}
+	assignment := assignShards(names, policy.Spec.ShardCount())
+
+	monitored := make(map[types.NamespacedName]sharedCluster, len(clusters))
+	for _, es := range clusters {
+		log := log.WithValues("es_namespace", es.Namespace, "es_name", es.Name)
+
+		if es.Status.Phase != esv1.ElasticsearchReadyPhase {
+			log.V(1).Info("Skipping ES cluster that is not ready")
+			state.UpdateWithPhase(autoopsv1alpha1.MonitoredResourcesNotReadyPhase)
+			results = results.WithRequeue(reconciler.DefaultRequeue)
+			continue
+		}
+
+		var caCert []byte
+		if es.Spec.HTTP.TLS.Enabled() {
+			var err error
+			caCert, err = r.reconcileAutoOpsESCASecret(ctx, policy, es)
+			if err != nil {
+				log.Error(err, "while reconciling AutoOps ES CA secret")
+				state.ResourceError(es, "Failed to reconcile AutoOps ES CA secret", err)
+				results.WithError(err)
+				continue
+			}
+			if len(caCert) == 0 {
+				log.V(1).Info("Skipping ES cluster whose CA certificate is not available yet")
+				state.UpdateWithPhase(autoopsv1alpha1.MonitoredResourcesNotReadyPhase)
+				results = results.WithRequeue(reconciler.DefaultRequeue)
+				continue
+			}
+		}
+
+		apiKeySecret, err := r.reconcileAutoOpsESAPIKey(ctx, policy, es)
+		if err != nil {
+			log.Error(err, "while reconciling AutoOps ES API key")
+			state.ResourceError(es, "Failed to reconcile AutoOps ES API key", err)
+			results.WithError(err)
+			continue
+		}
+
+		monitored[k8s.ExtractNamespacedName(&es)] = sharedCluster{
+			es:     es,
+			apiKey: string(apiKeySecret.Data[apiKeySecretKey]),
+			caCert: caCert,
+		}
+	}
+
+	shards := make([][]sharedCluster, len(assignment))
+	for shard, names := range assignment {
+		for _, name := range names {
+			if cluster, exists := monitored[name]; exists {
+				shards[shard] = append(shards[shard], cluster)
+			}
+		}
+	}
+
+	activeShards := sets.New[string]()
+	for shard, shardClusters := range shards {
+		log := log.WithValues("shard", shard)
+		if len(shardClusters) == 0 {
+			// no cluster to monitor yet, the agent of the shard is not deployed
+			continue
+		}
+		activeShards.Insert(strconv.Itoa(shard))
+
+		configSecret, err := r.reconcileSharedConfigSecret(ctx, policy, shard, shardClusters)
+		if err != nil {
+			log.Error(err, "while reconciling AutoOps shared config secret")
+			for _, cluster := range shardClusters {
+				state.ResourceError(cluster.es, "Failed to reconcile AutoOps shared config secret", err)
+			}
+			results.WithError(err)
+			continue
+		}
+
+		configHash, err := buildSharedConfigHash(ctx, r.Client, policy, configSecret, shard, shardClusters)
+		if err != nil {
+			log.Error(err, "while building config hash")
+			for _, cluster := range shardClusters {
+				state.ResourceError(cluster.es, "Failed to prepare AutoOps agent deployment", err)
+			}
+			results.WithError(err)
+			continue
+		}
+
+		deploymentParams, err := r.buildSharedDeployment(configHash, policy, shard)
+		if err != nil {
+			log.Error(err, "while getting deployment params")
+			for _, cluster := range shardClusters {
+				state.ResourceError(cluster.es, "Failed to build AutoOps agent deployment", err)
+			}
+			results.WithError(err)
+			continue
+		}
+
+		reconciledDeployment, err := reconcileAgentDeployment(ctx, r.Client, deploymentParams, policy)
+		if err != nil {
+			log.Error(err, "while reconciling deployment")
+			for _, cluster := range shardClusters {
+				state.ResourceError(cluster.es, "Failed to reconcile AutoOps agent deployment", err)
+			}
+			results.WithError(err)
+			continue
+		}
+
+		if isDeploymentReady(reconciledDeployment) {
+			for range shardClusters {
+				state.MarkResourceReady()
+			}
+		}
+	}
+
+	matchLabels := client.MatchingLabels{PolicyNameLabelKey: policy.Name}
+	if err := cleanupOrphanedShards(ctx, log, r.Client, policy, matchLabels, activeShards); err != nil {
+		log.Error(err, "while cleaning up orphaned shards")
+		state.UpdateWithPhase(autoopsv1alpha1.ErrorPhase)
+		results.WithError(err)
+	}
+
+	return results
+}
+
+// reconcileSharedConfigSecret reconciles the Secret holding the configuration file of a shard, along with the CA
+// certificates of its clusters. A Secret is used as the configuration contains the API keys of the clusters, and there
+// is one per shard so that the agent of a shard only has access to the API keys of its own clusters.
+func (r *AgentPolicyReconciler) reconcileSharedConfigSecret(
+	ctx context.Context,
+	policy autoopsv1alpha1.AutoOpsAgentPolicy,
+	shard int,
+	clusters []sharedCluster,
+) (*corev1.Secret, error) {
+	expected, err := buildSharedConfigSecret(policy, shard, clusters)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciled := &corev1.Secret{}
+	err = reconciler.ReconcileResource(
+		reconciler.Params{
+			Context:    ctx,
+			Client:     r.Client,
+			Owner:      &policy,
+			Expected:   &expected,
+			Reconciled: reconciled,
+			NeedsUpdate: func() bool {
+				return !maps.IsSubset(expected.Labels, reconciled.Labels) ||
+					!maps.IsSubset(expected.Annotations, reconciled.Annotations) ||
+					!reflect.DeepEqual(expected.Data, reconciled.Data)
+			},
+			UpdateReconciled: func() {
+				reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
+				reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
+				reconciled.Data = expected.Data
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return reconciled, nil
+}
+
+// buildSharedConfigSecret builds the expected Secret holding the configuration of the given shard of a policy.
+func buildSharedConfigSecret(policy autoopsv1alpha1.AutoOpsAgentPolicy, shard int, shardClusters []sharedCluster) (corev1.Secret, error) {
+	labels := sharedLabelsFor(policy)
+	labels[policySecretTypeLabelKey] = sharedConfigSecretType
+	labels[shardLabelKey] = strconv.Itoa(shard)
+	meta := metadata.Propagate(&policy, metadata.Metadata{
+		Labels:      maps.Merge(policy.GetLabels(), labels),
+		Annotations: policy.GetAnnotations(),
+	})
+
+	data := make(map[string][]byte, len(shardClusters)+1)
+	clusters := make([]clusterTemplateData, 0, len(shardClusters))
+	for _, cluster := range shardClusters {
+		clusterData := clusterTemplateData{
+			Hosts:  fmt.Sprintf("[%q]", services.InternalServiceURL(cluster.es)),
+			APIKey: cluster.apiKey,
+		}
+		if cluster.es.Spec.HTTP.TLS.Enabled() {
+			caFileName := sharedCAFileName(k8s.ExtractNamespacedName(&cluster.es))
+			data[caFileName] = cluster.caCert
+			clusterData.SSLEnabled = true
+			clusterData.CACertPath = path.Join(configVolumePath, caFileName)
+		}
+		clusters = append(clusters, clusterData)
+	}
+	config, err := renderConfig(clusters)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	data[sharedConfigFileName(shard)] = []byte(config)
+
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        autoopsv1alpha1.SharedConfig(policy.GetName(), shard),
+			Namespace:   policy.GetNamespace(),
+			Labels:      meta.Labels,
+			Annotations: meta.Annotations,
+		},
+		Data: data,
+	}, nil
+}
+
+// buildSharedConfigHash builds a hash of the configuration of a shard, including the CA certificates of its clusters
+// and the autoops-secret values, to trigger a restart of the shard agent on configuration changes.
+func buildSharedConfigHash(
+	ctx context.Context,
+	c k8s.Client,
+	policy autoopsv1alpha1.AutoOpsAgentPolicy,
+	configSecret *corev1.Secret,
+	shard int,
+	clusters []sharedCluster,
+) (string, error) {
+	configHash := fnv.New32a()
+	_, _ = configHash.Write(configSecret.Data[sharedConfigFileName(shard)])
+	for _, cluster := range clusters {
+		_, _ = configHash.Write(cluster.caCert)
+	}
+	if err := hashAutoOpsSecret(ctx, c, policy, configHash); err != nil {
+		return "", err
+	}
+	return fmt.Sprint(configHash.Sum32()), nil
+}
+
+// buildSharedDeployment builds the agent Deployment of the given shard of a policy in Shared mode.
+func (r *AgentPolicyReconciler) buildSharedDeployment(configHash string, policy autoopsv1alpha1.AutoOpsAgentPolicy, shard int) (appsv1.Deployment, error) {
+	configVolume := volume.NewSecretVolumeWithMountPath(autoopsv1alpha1.SharedConfig(policy.GetName(), shard), configVolumeName, configVolumePath)
+	labels := sharedLabelsFor(policy)
+	labels[shardLabelKey] = strconv.Itoa(shard)
+
+	return r.newAgentDeployment(policy, agentDeploymentParams{
+		name:   autoopsv1alpha1.SharedDeployment(policy.GetName(), shard),
+		labels: labels,
+		selector: map[string]string{
+			PolicyNameLabelKey: policy.GetName(),
+			shardLabelKey:      strconv.Itoa(shard),
+		},
+		configHash:   configHash,
+		configFile:   sharedConfigFileName(shard),
+		env:          sharedEnvVars(policy),
+		volumes:      []corev1.Volume{configVolume.Volume()},
+		volumeMounts: []corev1.VolumeMount{configVolume.VolumeMount()},
+	})
+}
+
+// sharedEnvVars returns the environment variables of the agents in Shared mode. They only differ from the ones of the
+// agents dedicated to a cluster by the connection settings, which are part of the configuration of each cluster.
+func sharedEnvVars(policy autoopsv1alpha1.AutoOpsAgentPolicy) []corev1.EnvVar {
+	return slices.DeleteFunc(autoopsEnvVars(policy, esv1.Elasticsearch{}), func(envVar corev1.EnvVar) bool {
+		return envVar.Name == "AUTOOPS_ES_URL" || envVar.Name == "AUTOOPS_ES_API_KEY"
+	})
+}
@@ -0,0 +1,220 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoops
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	autoopsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoops/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	commonapikey "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/apikey"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func namespacedNames(count int) []types.NamespacedName {
+	names := make([]types.NamespacedName, 0, count)
+	for i := range count {
+		names = append(names, types.NamespacedName{Namespace: fmt.Sprintf("ns-%d", i%3), Name: fmt.Sprintf("es-%d", i)})
+	}
+	return names
+}
+
+func shardOf(assignment [][]types.NamespacedName) map[types.NamespacedName]int {
+	shards := map[types.NamespacedName]int{}
+	for shard, names := range assignment {
+		for _, name := range names {
+			shards[name] = shard
+		}
+	}
+	return shards
+}
+
+func Test_assignShards(t *testing.T) {
+	tests := []struct {
+		name        string
+		clusters    int
+		shards      int
+		maxPerShard int
+	}{
+		{name: "no shard", clusters: 3, shards: 0, maxPerShard: 0},
+		{name: "no cluster", clusters: 0, shards: 3, maxPerShard: 0},
+		{name: "single shard", clusters: 10, shards: 1, maxPerShard: 10},
+		{name: "evenly divisible", clusters: 12, shards: 4, maxPerShard: 3},
+		{name: "not evenly divisible", clusters: 10, shards: 4, maxPerShard: 3},
+		{name: "more shards than clusters", clusters: 2, shards: 5, maxPerShard: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clusters := namespacedNames(tt.clusters)
+			assignment := assignShards(clusters, tt.shards)
+			require.Len(t, assignment, tt.shards)
+			assigned := 0
+			for _, names := range assignment {
+				assert.LessOrEqual(t, len(names), tt.maxPerShard)
+				assigned += len(names)
+			}
+			if tt.shards > 0 {
+				assert.Equal(t, tt.clusters, assigned)
+			}
+			// the assignment does not depend on the order of the clusters
+			reversed := make([]types.NamespacedName, 0, len(clusters))
+			for i := len(clusters) - 1; i >= 0; i-- {
+				reversed = append(reversed, clusters[i])
+			}
+			assert.Equal(t, shardOf(assignment), shardOf(assignShards(reversed, tt.shards)))
+		})
+	}
+}
+
+func Test_assignShards_stability(t *testing.T) {
+	clusters := namespacedNames(40)
+	before := shardOf(assignShards(clusters, 4))
+	after := shardOf(assignShards(append(clusters, types.NamespacedName{Namespace: "ns-new", Name: "es-new"}), 4))
+
+	moved := 0
+	for name, shard := range before {
+		if after[name] != shard {
+			moved++
+		}
+	}
+	// adding a cluster should only move a few clusters to balance the shards
+	assert.Less(t, moved, len(clusters)/4)
+}
+
+func Test_buildSharedConfigSecret(t *testing.T) {
+	policy := newAutoOpsAgentPolicy(func(p *autoopsv1alpha1.AutoOpsAgentPolicy) {
+		p.Spec.Mode = autoopsv1alpha1.SharedMode
+		p.Spec.Shards = ptr.To[int32](2)
+	})
+	withTLS := newElasticsearch()
+	withoutTLS := newElasticsearch(func(e *esv1.Elasticsearch) {
+		e.Name = "es-2"
+		e.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+	})
+
+	secret, err := buildSharedConfigSecret(policy, 1, []sharedCluster{
+		{es: *withTLS, apiKey: "key-1", caCert: []byte("ca-1")},
+		{es: *withoutTLS, apiKey: "key-2"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, autoopsv1alpha1.SharedConfig(policy.Name, 1), secret.Name)
+	assert.Equal(t, policy.Namespace, secret.Namespace)
+	assert.Equal(t, sharedConfigSecretType, secret.Labels[policySecretTypeLabelKey])
+	assert.Equal(t, policy.Name, secret.Labels[PolicyNameLabelKey])
+	assert.Equal(t, "1", secret.Labels[shardLabelKey])
+
+	// the Secret only holds the configuration and CA certificates of its shard
+	caFileName := sharedCAFileName(types.NamespacedName{Namespace: "ns-1", Name: "es-1"})
+	assert.Equal(t, []byte("ca-1"), secret.Data[caFileName])
+	require.Len(t, secret.Data, 2)
+
+	config := string(secret.Data[sharedConfigFileName(1)])
+	assert.Contains(t, config, `hosts: ["https://es-1-es-internal-http.ns-1.svc:9200"]`)
+	assert.Contains(t, config, `Authorization: "ApiKey key-1"`)
+	assert.Contains(t, config, fmt.Sprintf(`ssl.certificate_authorities: ["%s/%s"]`, configVolumePath, caFileName))
+	assert.Contains(t, config, `hosts: ["http://es-2-es-internal-http.ns-1.svc:9200"]`)
+	assert.Contains(t, config, `Authorization: "ApiKey key-2"`)
+	assert.NotContains(t, config, "AUTOOPS_ES_URL")
+}
+
+func TestAutoOpsAgentPolicyReconciler_internalReconcileShared(t *testing.T) {
+	withoutTLS := func(e *esv1.Elasticsearch) {
+		e.Spec.HTTP.TLS.SelfSignedCertificate = &commonv1.SelfSignedCertificate{Disabled: true}
+	}
+	policy := newAutoOpsAgentPolicy(func(p *autoopsv1alpha1.AutoOpsAgentPolicy) {
+		p.Spec.Mode = autoopsv1alpha1.SharedMode
+		p.Spec.Shards = ptr.To[int32](3)
+	})
+	initialObjects := []client.Object{newSecret()}
+	for i := range 4 {
+		initialObjects = append(initialObjects, newElasticsearch(withoutTLS, func(e *esv1.Elasticsearch) {
+			e.Name = fmt.Sprintf("es-%d", i)
+		}))
+	}
+	// a Deployment left over from the per-cluster mode
+	initialObjects = append(initialObjects, &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      autoopsv1alpha1.Deployment(policy.Name, esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "es-0", Namespace: "ns-1"}}),
+			Namespace: "ns-1",
+			Labels: map[string]string{
+				commonv1.TypeLabelName:              autoOpsAgentType,
+				PolicyNameLabelKey:                  policy.Name,
+				policyNamespaceLabelKey:             policy.Namespace,
+				commonapikey.MetadataKeyESName:      "es-0",
+				commonapikey.MetadataKeyESNamespace: "ns-1",
+			},
+		},
+	})
+	k8sClient := k8s.NewFakeClient(initialObjects...)
+
+	r := &AgentPolicyReconciler{
+		Client:           k8sClient,
+		accessReviewer:   &fakeAccessReviewer{allowed: true},
+		esClientProvider: newFakeESClientProvider().Provider,
+		params: operator.Parameters{
+			Dialer: &fakeDialer{},
+		},
+		dynamicWatches: watches.NewDynamicWatches(),
+	}
+
+	ctx := context.Background()
+	state := newState(policy)
+	_, err := r.internalReconcile(ctx, policy, reconciler.NewResult(ctx), state).Aggregate()
+	require.NoError(t, err)
+	assert.Equal(t, 4, state.status.Resources)
+
+	var deployments appsv1.DeploymentList
+	require.NoError(t, k8sClient.List(ctx, &deployments, client.MatchingLabels{PolicyNameLabelKey: policy.Name}))
+	names := make([]string, 0, len(deployments.Items))
+	for _, d := range deployments.Items {
+		names = append(names, d.Name)
+		shard, err := strconv.Atoi(d.Labels[shardLabelKey])
+		require.NoError(t, err)
+		// each shard only mounts its own configuration Secret
+		var secret corev1.Secret
+		require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Namespace: policy.Namespace, Name: autoopsv1alpha1.SharedConfig(policy.Name, shard)}, &secret))
+		assert.Contains(t, string(secret.Data[sharedConfigFileName(shard)]), "module: autoops_es")
+		require.Len(t, d.Spec.Template.Spec.Volumes, 1)
+		assert.Equal(t, secret.Name, d.Spec.Template.Spec.Volumes[0].Secret.SecretName)
+	}
+	// 4 clusters among 3 shards: all the shards are deployed
+	assert.ElementsMatch(t, []string{
+		autoopsv1alpha1.SharedDeployment(policy.Name, 0),
+		autoopsv1alpha1.SharedDeployment(policy.Name, 1),
+		autoopsv1alpha1.SharedDeployment(policy.Name, 2),
+	}, names)
+
+	// switching back to the per-cluster mode removes the shared resources
+	policy.Spec.Mode = autoopsv1alpha1.PerClusterMode
+	policy.Spec.Shards = nil
+	state = newState(policy)
+	_, err = r.internalReconcile(ctx, policy, reconciler.NewResult(ctx), state).Aggregate()
+	require.NoError(t, err)
+	var secrets corev1.SecretList
+	require.NoError(t, k8sClient.List(ctx, &secrets, client.MatchingLabels{policySecretTypeLabelKey: sharedConfigSecretType}))
+	assert.Empty(t, secrets.Items)
+	require.NoError(t, k8sClient.List(ctx, &deployments, client.MatchingLabels{PolicyNameLabelKey: policy.Name}))
+	require.Len(t, deployments.Items, 4)
+	for _, d := range deployments.Items {
+		assert.Empty(t, d.Labels[shardLabelKey])
+	}
+}
@@ -12,6 +12,7 @@ import (
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 
 	autoopsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoops/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
@@ -27,8 +28,10 @@ func validations(ctx context.Context, checker license.Checker) []validation {
 		func(policy *autoopsv1alpha1.AutoOpsAgentPolicy) field.ErrorList {
 			return checkSupportedVersion(ctx, policy, checker)
 		},
+		checkImageCatalogVersion,
 		checkConfigSecretName,
 		checkResourceSelector,
+		checkShards,
 	}
 }
 
@@ -58,6 +61,10 @@ func checkSupportedVersion(ctx context.Context, policy *autoopsv1alpha1.AutoOpsA
 	return commonv1.CheckSupportedStackVersion(policy.Spec.Version, supported)
 }
 
+func checkImageCatalogVersion(policy *autoopsv1alpha1.AutoOpsAgentPolicy) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(policy.Spec.Version, container.AutoOpsAgentImage, policy.Spec.Image)
+}
+
 func checkConfigSecretName(policy *autoopsv1alpha1.AutoOpsAgentPolicy) field.ErrorList {
 	if policy.Spec.AutoOpsRef.SecretName == "" {
 		return field.ErrorList{field.Required(field.NewPath("spec").Child("autoOpsRef").Child("secretName"), "AutoOpsRef secret name must be specified")}
@@ -71,3 +78,10 @@ func checkResourceSelector(policy *autoopsv1alpha1.AutoOpsAgentPolicy) field.Err
 	}
 	return nil
 }
+
+func checkShards(policy *autoopsv1alpha1.AutoOpsAgentPolicy) field.ErrorList {
+	if policy.Spec.Shards != nil && !policy.Spec.IsShared() {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec").Child("shards"), "Shards can only be set in Shared mode")}
+	}
+	return nil
+}
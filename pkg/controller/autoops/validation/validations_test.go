@@ -7,12 +7,15 @@ package validation
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	autoopsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoops/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 )
 
@@ -224,6 +227,27 @@ func TestValidate(t *testing.T) {
 			enterpriseEnabled: false,
 			wantErr:           true,
 		},
+		{
+			name: "shared mode with shards",
+			policy: func() *autoopsv1alpha1.AutoOpsAgentPolicy {
+				p := newPolicy("9.2.4")
+				p.Spec.Mode = autoopsv1alpha1.SharedMode
+				p.Spec.Shards = ptr.To[int32](3)
+				return p
+			}(),
+			enterpriseEnabled: false,
+			wantErr:           false,
+		},
+		{
+			name: "shards without shared mode",
+			policy: func() *autoopsv1alpha1.AutoOpsAgentPolicy {
+				p := newPolicy("9.2.4")
+				p.Spec.Shards = ptr.To[int32](3)
+				return p
+			}(),
+			enterpriseEnabled: false,
+			wantErr:           true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -238,3 +262,20 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckImageCatalogVersion(t *testing.T) {
+	catalog, err := container.ParseImageCatalog([]byte(`
+images:
+- image: elastic-agent/elastic-otel-collector-wolfi
+  version: 9.2.4
+  digest: sha256:` + strings.Repeat("a", 64)))
+	require.NoError(t, err)
+	container.SetImageCatalog(catalog)
+	defer container.SetImageCatalog(nil)
+
+	require.Empty(t, checkImageCatalogVersion(newPolicy("9.2.4")))
+	require.NotEmpty(t, checkImageCatalogVersion(newPolicy("9.3.0")))
+	custom := newPolicy("9.3.0")
+	custom.Spec.Image = "my.registry/autoops:9.3.0"
+	require.Empty(t, checkImageCatalogVersion(custom))
+}
@@ -13,7 +13,15 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
 )
 
-// Context contains the required objects used by the autoscaler functions.
+// PolicyRecommender calculates the resources of the NodeSets managed by an autoscaling policy.
+type PolicyRecommender interface {
+	GetResources() v1alpha1.NodeSetsResources
+}
+
+var _ PolicyRecommender = (*Context)(nil)
+
+// Context contains the required objects used by the autoscaler functions to calculate resources from the
+// Elasticsearch autoscaling capacity API.
 type Context struct {
 	Log logr.Logger
 	// AutoscalingSpec is the autoscaling specification as provided by the user.
@@ -0,0 +1,168 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaler
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+)
+
+const (
+	// maxScaleUpRatio limits the number of nodes added in a single reconciliation to the number of observed nodes.
+	maxScaleUpRatio = 2.0
+	// scaleUpTolerance is the ratio above the target under which no node is added, to avoid flapping.
+	scaleUpTolerance = 0.1
+)
+
+var _ PolicyRecommender = (*MetricsContext)(nil)
+
+// MetricsContext contains the objects used to calculate the resources of an autoscaling policy from the statistics of
+// the nodes it manages.
+// Node resources are not scaled vertically: they are kept within the limits of the autoscaling specification while the
+// number of nodes is adjusted to keep the utilization of the nodes below the targets.
+type MetricsContext struct {
+	Log logr.Logger
+	// AutoscalingSpec is the autoscaling specification as provided by the user.
+	AutoscalingSpec v1alpha1.AutoscalingPolicySpec
+	// NodeSets is the list of the NodeSets managed by the autoscaling specification.
+	NodeSets esv1.NodeSetList
+	// CurrentAutoscalingStatus is the current resources status as stored in the Elasticsearch resource.
+	CurrentAutoscalingStatus v1alpha1.ElasticsearchAutoscalerStatus
+	// NodesStats holds the statistics of the Elasticsearch nodes managed by the autoscaling policy.
+	NodesStats []client.NodeStats
+	// StatusBuilder is used to track any event that should be surfaced to the user.
+	StatusBuilder *v1alpha1.AutoscalingStatusBuilder
+}
+
+// GetResources calculates the resources required by all the NodeSets managed by the autoscaling policy in the context.
+func (ctx *MetricsContext) GetResources() v1alpha1.NodeSetsResources {
+	nodeSets := ctx.NodeSets.Names()
+	var nodeSetsResources v1alpha1.NodeSetsResources
+	var currentNodeCount int32
+	currentNodeSetsResources, hasNodeSetsResources := ctx.CurrentAutoscalingStatus.CurrentResourcesForPolicy(ctx.AutoscalingSpec.Name)
+	if hasNodeSetsResources {
+		nodeSetsResources = nodeSetResourcesFromStatus(currentNodeSetsResources, ctx.AutoscalingSpec, nodeSets)
+		currentNodeCount = currentNodeSetsResources.NodeSetNodeCount.TotalNodeCount()
+	} else {
+		nodeSetsResources = newMinNodeSetResources(ctx.AutoscalingSpec, nodeSets)
+	}
+	nodeSetsResources.NodeResources = nodeSetsResources.UpdateLimits(ctx.AutoscalingSpec.AutoscalingResources)
+
+	nodeCount := ctx.nodeCount(currentNodeCount)
+	if nodeCount > ctx.AutoscalingSpec.NodeCountRange.Max {
+		ctx.StatusBuilder.
+			ForPolicy(ctx.AutoscalingSpec.Name).
+			RecordEvent(
+				v1alpha1.HorizontalScalingLimitReached,
+				fmt.Sprintf("Node utilization requires %d nodes, max number of nodes is %d", nodeCount, ctx.AutoscalingSpec.NodeCountRange.Max),
+			)
+	}
+	distributeFairly(nodeSetsResources.NodeSetNodeCount, ctx.AutoscalingSpec.NodeCountRange.Enforce(nodeCount))
+
+	ctx.Log.Info(
+		"Metrics autoscaler",
+		"state", "online",
+		"policy", ctx.AutoscalingSpec.Name,
+		"nodeset", nodeSetsResources.NodeSetNodeCount,
+		"count", nodeSetsResources.NodeSetNodeCount.TotalNodeCount(),
+		"resources", nodeSetsResources.ToInt64(),
+	)
+	return nodeSetsResources
+}
+
+// nodeCount returns the number of nodes required to keep the utilization of the nodes below the targets.
+func (ctx *MetricsContext) nodeCount(currentNodeCount int32) int32 {
+	observedNodes := int32(len(ctx.NodesStats))
+	if observedNodes == 0 {
+		ctx.StatusBuilder.
+			ForPolicy(ctx.AutoscalingSpec.Name).
+			RecordEvent(v1alpha1.MetricsUnavailable, "No statistics available for the nodes managed by the policy")
+		return currentNodeCount
+	}
+
+	// ratio is the highest ratio between the observed utilization and its target.
+	ratio, metric := ctx.utilizationRatio()
+	ctx.Log.V(1).Info(
+		"Nodes utilization",
+		"policy", ctx.AutoscalingSpec.Name,
+		"metric", metric,
+		"ratio", ratio,
+		"observed.count", observedNodes,
+		"current.count", currentNodeCount,
+	)
+
+	switch {
+	case ratio > 1+scaleUpTolerance:
+		required := int32(math.Ceil(float64(observedNodes) * math.Min(ratio, maxScaleUpRatio)))
+		return max(required, currentNodeCount)
+	case observedNodes < currentNodeCount:
+		// Some nodes are not reporting statistics yet, for example they are still starting, do not scale down.
+		return currentNodeCount
+	case currentNodeCount > 1 && ratio*float64(currentNodeCount)/float64(currentNodeCount-1) < 1:
+		// Utilization remains below the targets without one node, remove it.
+		return currentNodeCount - 1
+	default:
+		return currentNodeCount
+	}
+}
+
+// utilizationRatio returns the highest ratio between a metric, averaged over the nodes, and its target, along with the
+// name of that metric.
+func (ctx *MetricsContext) utilizationRatio() (float64, string) {
+	settings := ctx.AutoscalingSpec.Metrics
+	var cpu, heap, disk, searchQueue, writeQueue float64
+	for _, node := range ctx.NodesStats {
+		cpu += float64(node.OS.CPU.Percent)
+		heap += float64(node.JVM.Mem.HeapUsedPercent)
+		if total := node.FS.Total.TotalInBytes; total > 0 {
+			disk += float64(total-node.FS.Total.AvailableInBytes) * 100 / float64(total)
+		}
+		searchQueue += float64(node.ThreadPool["search"].Queue)
+		writeQueue += float64(node.ThreadPool["write"].Queue)
+	}
+	count := float64(len(ctx.NodesStats))
+	utilizations := []struct {
+		metric           string
+		observed, target float64
+	}{
+		{metric: "cpu", observed: cpu / count, target: float64(settings.CPUPercent())},
+		{metric: "heap", observed: heap / count, target: float64(settings.HeapPercent())},
+		{metric: "disk", observed: disk / count, target: float64(settings.DiskPercent())},
+		{metric: "search_queue", observed: searchQueue / count, target: float64(settings.SearchQueue())},
+		{metric: "write_queue", observed: writeQueue / count, target: float64(settings.WriteQueue())},
+	}
+	var ratio float64
+	var metric string
+	for _, u := range utilizations {
+		if r := u.observed / u.target; r > ratio {
+			ratio, metric = r, u.metric
+		}
+	}
+	return ratio, metric
+}
+
+// NodesStatsForNodeSets returns the statistics of the nodes belonging to the given NodeSets.
+func NodesStatsForNodeSets(esName string, nodeSets esv1.NodeSetList, nodesStats client.NodesStats) []client.NodeStats {
+	var result []client.NodeStats
+	for _, node := range nodesStats.Nodes {
+		for _, nodeSet := range nodeSets {
+			// Elasticsearch nodes are named after their Pod, which is named after the StatefulSet with an ordinal suffix.
+			ordinal, found := strings.CutPrefix(node.Name, esv1.StatefulSet(esName, nodeSet.Name)+"-")
+			if _, err := strconv.Atoi(ordinal); found && err == nil {
+				result = append(result, node)
+				break
+			}
+		}
+	}
+	return result
+}
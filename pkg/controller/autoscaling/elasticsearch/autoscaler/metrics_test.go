@@ -0,0 +1,172 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package autoscaler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+)
+
+func nodeStats(name string, cpu, heap, searchQueue int) client.NodeStats {
+	stats := client.NodeStats{Name: name}
+	stats.OS.CPU.Percent = cpu
+	stats.JVM.Mem.HeapUsedPercent = heap
+	stats.ThreadPool = map[string]client.ThreadPoolStats{"search": {Queue: searchQueue}, "write": {}}
+	stats.FS.Total.TotalInBytes = 100
+	stats.FS.Total.AvailableInBytes = 50
+	return stats
+}
+
+func repeatNodeStats(count int, cpu, heap, searchQueue int) []client.NodeStats {
+	stats := make([]client.NodeStats, count)
+	for i := range stats {
+		stats[i] = nodeStats("node", cpu, heap, searchQueue)
+	}
+	return stats
+}
+
+func TestMetricsContext_GetResources(t *testing.T) {
+	cpuTarget := int32(40)
+	currentStatus := func(nodeCount int32) v1alpha1.ElasticsearchAutoscalerStatus {
+		return v1alpha1.ElasticsearchAutoscalerStatus{AutoscalingPolicyStatuses: []v1alpha1.AutoscalingPolicyStatus{{
+			Name:                   "search",
+			NodeSetNodeCount:       []v1alpha1.NodeSetNodeCount{{Name: "search", NodeCount: nodeCount}},
+			ResourcesSpecification: v1alpha1.NodeResources{Requests: map[corev1.ResourceName]resource.Quantity{corev1.ResourceMemory: q("4Gi")}},
+		}}}
+	}
+	tests := []struct {
+		name          string
+		status        v1alpha1.ElasticsearchAutoscalerStatus
+		settings      *v1alpha1.MetricsRecommenderSettings
+		nodesStats    []client.NodeStats
+		wantCount     int32
+		wantEventType v1alpha1.AutoscalingEventType
+	}{
+		{
+			name:          "no status yet, start with the min number of nodes",
+			wantCount:     2,
+			wantEventType: v1alpha1.MetricsUnavailable,
+		},
+		{
+			name:          "no metrics, keep the current number of nodes",
+			status:        currentStatus(4),
+			wantCount:     4,
+			wantEventType: v1alpha1.MetricsUnavailable,
+		},
+		{
+			name:       "utilization within the tolerance",
+			status:     currentStatus(3),
+			nodesStats: repeatNodeStats(3, 75, 50, 0),
+			wantCount:  3,
+		},
+		{
+			name:       "search queue above the target, scale up",
+			status:     currentStatus(3),
+			nodesStats: repeatNodeStats(3, 50, 50, 15),
+			// 3 nodes * 15 / 10
+			wantCount: 5,
+		},
+		{
+			name:       "scale up is limited to twice the number of observed nodes",
+			status:     currentStatus(2),
+			nodesStats: repeatNodeStats(2, 100, 50, 100),
+			wantCount:  4,
+		},
+		{
+			name:          "scale up is limited by the max number of nodes",
+			status:        currentStatus(5),
+			nodesStats:    repeatNodeStats(5, 100, 50, 0),
+			wantCount:     6,
+			wantEventType: v1alpha1.HorizontalScalingLimitReached,
+		},
+		{
+			name:       "custom CPU target",
+			status:     currentStatus(3),
+			settings:   &v1alpha1.MetricsRecommenderSettings{TargetCPUPercent: &cpuTarget},
+			nodesStats: repeatNodeStats(3, 60, 50, 0),
+			wantCount:  5,
+		},
+		{
+			name:       "low utilization, remove a node",
+			status:     currentStatus(4),
+			nodesStats: repeatNodeStats(4, 20, 30, 0),
+			wantCount:  3,
+		},
+		{
+			name:       "removing a node would exceed a target",
+			status:     currentStatus(4),
+			nodesStats: repeatNodeStats(4, 20, 60, 0),
+			wantCount:  4,
+		},
+		{
+			name:       "some nodes are not observed yet, do not scale down",
+			status:     currentStatus(4),
+			nodesStats: repeatNodeStats(3, 10, 10, 0),
+			wantCount:  4,
+		},
+		{
+			name:       "never scale down below the min number of nodes",
+			status:     currentStatus(2),
+			nodesStats: repeatNodeStats(2, 10, 10, 0),
+			wantCount:  2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := NewAutoscalingSpecBuilder("search").WithNodeCounts(2, 6).WithMemory("2Gi", "8Gi").Build()
+			spec.Recommender = v1alpha1.MetricsRecommender
+			spec.Metrics = tt.settings
+			statusBuilder := v1alpha1.NewAutoscalingStatusBuilder()
+			ctx := MetricsContext{
+				Log:                      logTest,
+				AutoscalingSpec:          spec,
+				NodeSets:                 esv1.NodeSetList{{Name: "search"}},
+				CurrentAutoscalingStatus: tt.status,
+				NodesStats:               tt.nodesStats,
+				StatusBuilder:            statusBuilder,
+			}
+			got := ctx.GetResources()
+			assert.Equal(t, tt.wantCount, got.NodeSetNodeCount.TotalNodeCount())
+			// node resources are not scaled vertically
+			if len(tt.status.AutoscalingPolicyStatuses) > 0 {
+				assert.Equal(t, q("4Gi"), got.GetRequest(corev1.ResourceMemory))
+			} else {
+				assert.Equal(t, q("2Gi"), got.GetRequest(corev1.ResourceMemory))
+			}
+			policyStates := statusBuilder.Build().AutoscalingPolicyStatuses
+			if tt.wantEventType == "" {
+				assert.Empty(t, policyStates)
+				return
+			}
+			require.Len(t, policyStates, 1)
+			require.Len(t, policyStates[0].PolicyStates, 1)
+			assert.Equal(t, tt.wantEventType, policyStates[0].PolicyStates[0].Type)
+		})
+	}
+}
+
+func TestNodesStatsForNodeSets(t *testing.T) {
+	nodesStats := client.NodesStats{Nodes: map[string]client.NodeStats{
+		"a": {Name: "cluster-es-search-0"},
+		"b": {Name: "cluster-es-search-1"},
+		"c": {Name: "cluster-es-search-hot-0"},
+		"d": {Name: "cluster-es-data-0"},
+		"e": {Name: "other-es-search-0"},
+	}}
+	got := NodesStatsForNodeSets("cluster", esv1.NodeSetList{{Name: "search"}}, nodesStats)
+	names := make([]string, 0, len(got))
+	for _, node := range got {
+		names = append(names, node.Name)
+	}
+	assert.ElementsMatch(t, []string{"cluster-es-search-0", "cluster-es-search-1"}, names)
+}
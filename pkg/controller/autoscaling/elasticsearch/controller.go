@@ -214,8 +214,20 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 	statusBuilder := newStatusBuilder(log, esa.Spec.AutoscalingPolicySpecs)
 	results := &reconciler.Results{}
 
+	// Raise the minimum resources of the policies targeted by the schedules in progress.
+	now := time.Now()
+	scheduledEsa, activeSchedules := applySchedules(esa, now)
+	if len(activeSchedules) > 0 {
+		log.V(1).Info(
+			"Autoscaling schedules in progress",
+			"schedules", activeSchedules,
+			"namespace", request.Namespace,
+			"esa_name", request.Name,
+		)
+	}
+
 	// Call the main function
-	reconciledEs, reconcileInternalErr := r.reconcileInternal(ctx, es, statusBuilder, autoscaledNodeSets, &esa)
+	reconciledEs, reconcileInternalErr := r.reconcileInternal(ctx, es, statusBuilder, autoscaledNodeSets, scheduledEsa)
 	if reconcileInternalErr != nil {
 		// we do not return immediately as not all errors prevent to compute a reconciled Elasticsearch resource.
 		results.WithError(reconcileInternalErr)
@@ -226,6 +238,7 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 	esa.Status.ObservedGeneration = ptr.To[int64](esa.Generation)
 	esa.Status.Conditions = esa.Status.Conditions.MergeWith(newStatus.Conditions...)
 	esa.Status.AutoscalingPolicyStatuses = newStatus.AutoscalingPolicyStatuses
+	esa.Status.ActiveSchedules = activeSchedules
 	updateStatus, err := r.updateStatus(ctx, log, esa)
 	if err != nil {
 		return reconcile.Result{}, err
@@ -246,6 +259,10 @@ func (r *ReconcileElasticsearchAutoscaler) Reconcile(ctx context.Context, reques
 		}
 		return results.WithError(err).Aggregate()
 	}
+	if untilNextTransition, ok := nextScheduleTransition(esa, now); ok {
+		// Reconcile as soon as a schedule window starts or ends.
+		results.WithRequeue(untilNextTransition)
+	}
 	return results.WithResults(defaultResult(&esa)).Aggregate()
 }
 
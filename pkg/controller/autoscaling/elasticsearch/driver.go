@@ -7,6 +7,7 @@ package elasticsearch
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/autoscaling/elasticsearch/status"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/services"
 	logconf "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
 )
@@ -124,16 +126,29 @@ func (r *baseReconcileAutoscaling) attemptOnlineReconciliation(
 		return nil, err
 	}
 
-	// Update autoscaling policies in Elasticsearch
-	if err := updatePolicies(ctx, log, autoscalingResource, esClient); err != nil {
-		log.Error(err, "Error while updating the autoscaling policies")
-		return nil, err
+	// The Elasticsearch autoscaling API is only used if at least one policy relies on the capacity recommender.
+	var autoscalingCapacityResult client.AutoscalingCapacityResult
+	if slices.ContainsFunc(autoscalingSpec, usesCapacityRecommender) {
+		// Update autoscaling policies in Elasticsearch
+		if err := updatePolicies(ctx, log, autoscalingResource, esClient); err != nil {
+			log.Error(err, "Error while updating the autoscaling policies")
+			return nil, err
+		}
+
+		// Get capacity requirements from the Elasticsearch autoscaling capacity API
+		autoscalingCapacityResult, err = esClient.GetAutoscalingCapacity(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Get capacity requirements from the Elasticsearch autoscaling capacity API
-	autoscalingCapacityResult, err := esClient.GetAutoscalingCapacity(ctx)
-	if err != nil {
-		return nil, err
+	// Get the node statistics used by the metrics recommender
+	var nodesStats client.NodesStats
+	if slices.ContainsFunc(autoscalingSpec, v1alpha1.AutoscalingPolicySpec.UsesMetricsRecommender) {
+		nodesStats, err = esClient.GetNodesStats(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// nextClusterResources holds the resources computed by the autoscaling algorithm for each nodeSet.
@@ -157,6 +172,19 @@ func (r *baseReconcileAutoscaling) attemptOnlineReconciliation(
 			continue
 		}
 
+		if autoscalingPolicy.UsesMetricsRecommender() {
+			metricsCtx := &autoscaler.MetricsContext{
+				Log:                      log,
+				AutoscalingSpec:          autoscalingPolicy,
+				NodeSets:                 nodeSetList,
+				CurrentAutoscalingStatus: currentAutoscalingStatus,
+				NodesStats:               autoscaler.NodesStatsForNodeSets(es.Name, nodeSetList, nodesStats),
+				StatusBuilder:            statusBuilder,
+			}
+			nextClusterResources = append(nextClusterResources, metricsCtx.GetResources())
+			continue
+		}
+
 		// Get the required capacity for this autoscaling policy from the Elasticsearch API
 		var nodeSetsResources v1alpha1.NodeSetsResources
 		autoscalingPolicyResult, hasCapacity := autoscalingCapacityResult.Policies[autoscalingPolicy.Name]
@@ -33,8 +33,11 @@ func updatePolicies(
 	if err != nil {
 		return err
 	}
-	// Create the expected autoscaling policies
+	// Create the expected autoscaling policies, policies relying on the metrics recommender are not needed in Elasticsearch.
 	for _, rp := range autoscalingPolicySpecs {
+		if !usesCapacityRecommender(rp) {
+			continue
+		}
 		if err := esclient.CreateAutoscalingPolicy(ctx, rp.Name, rp.AutoscalingPolicy); err != nil {
 			log.Error(err, "Error while updating an autoscaling policy", "policy", rp.Name)
 			return err
@@ -42,3 +45,9 @@ func updatePolicies(
 	}
 	return nil
 }
+
+// usesCapacityRecommender returns true if the resources of the policy are calculated from the Elasticsearch autoscaling
+// capacity API.
+func usesCapacityRecommender(policy v1alpha1.AutoscalingPolicySpec) bool {
+	return !policy.UsesMetricsRecommender()
+}
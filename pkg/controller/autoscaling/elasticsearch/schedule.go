@@ -0,0 +1,89 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	autoscalingv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoscaling/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+)
+
+// applySchedules returns a copy of the autoscaler in which the minimum resources of the autoscaling policies are raised
+// according to the schedules active at the given time, along with the active schedules.
+// The copy is only meant to compute the resources, the original autoscaler must be used to update the status.
+func applySchedules(esa autoscalingv1alpha1.ElasticsearchAutoscaler, now time.Time) (*autoscalingv1alpha1.ElasticsearchAutoscaler, []v1alpha1.ActiveSchedule) {
+	scheduled := esa.DeepCopy()
+	var activeSchedules []v1alpha1.ActiveSchedule
+	for _, schedule := range esa.Spec.Schedules {
+		window, active := schedule.ActiveWindow(now)
+		if !active {
+			continue
+		}
+		activeSchedules = append(activeSchedules, v1alpha1.ActiveSchedule{
+			Name:     schedule.Name,
+			Policies: schedule.Policies,
+			Start:    metav1.NewTime(window.Start),
+			End:      metav1.NewTime(window.End),
+		})
+		for i := range scheduled.Spec.AutoscalingPolicySpecs {
+			policy := &scheduled.Spec.AutoscalingPolicySpecs[i]
+			if slices.Contains(schedule.Policies, policy.Name) {
+				raiseMinResources(&policy.AutoscalingResources, schedule.Min)
+			}
+		}
+	}
+	return scheduled, activeSchedules
+}
+
+// raiseMinResources raises the lower limits of the autoscaling resources to the scheduled ones. Upper limits are raised
+// as well if necessary to keep the ranges consistent.
+func raiseMinResources(resources *v1alpha1.AutoscalingResources, scheduled autoscalingv1alpha1.ScheduledResources) {
+	if scheduled.NodeCount != nil && *scheduled.NodeCount > resources.NodeCountRange.Min {
+		resources.NodeCountRange.Min = *scheduled.NodeCount
+		resources.NodeCountRange.Max = max(resources.NodeCountRange.Max, *scheduled.NodeCount)
+	}
+	raiseMinQuantity(resources.CPURange, scheduled.CPU)
+	raiseMinQuantity(resources.MemoryRange, scheduled.Memory)
+	raiseMinQuantity(resources.StorageRange, scheduled.Storage)
+}
+
+func raiseMinQuantity(quantityRange *v1alpha1.QuantityRange, scheduled *resource.Quantity) {
+	// resources which are not managed by the policy are left untouched
+	if quantityRange == nil || scheduled == nil || scheduled.Cmp(quantityRange.Min) <= 0 {
+		return
+	}
+	quantityRange.Min = scheduled.DeepCopy()
+	if quantityRange.Max.Cmp(*scheduled) < 0 {
+		quantityRange.Max = scheduled.DeepCopy()
+	}
+}
+
+// nextScheduleTransition returns the duration until the next start or end of a schedule window, if any.
+func nextScheduleTransition(esa autoscalingv1alpha1.ElasticsearchAutoscaler, now time.Time) (time.Duration, bool) {
+	var next time.Time
+	for _, schedule := range esa.Spec.Schedules {
+		candidates := make([]time.Time, 0, 2)
+		if window, active := schedule.ActiveWindow(now); active {
+			candidates = append(candidates, window.End)
+		}
+		if start, ok := schedule.NextStart(now); ok {
+			candidates = append(candidates, start)
+		}
+		for _, candidate := range candidates {
+			if next.IsZero() || candidate.Before(next) {
+				next = candidate
+			}
+		}
+	}
+	if next.IsZero() {
+		return 0, false
+	}
+	return next.Sub(now), true
+}
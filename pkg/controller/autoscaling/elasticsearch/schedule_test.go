@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package elasticsearch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	autoscalingv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoscaling/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+)
+
+func scheduledAutoscaler() autoscalingv1alpha1.ElasticsearchAutoscaler {
+	blackFridayStart := metav1.NewTime(time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC))
+	blackFridayEnd := metav1.NewTime(time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC))
+	return autoscalingv1alpha1.ElasticsearchAutoscaler{
+		Spec: autoscalingv1alpha1.ElasticsearchAutoscalerSpec{
+			AutoscalingPolicySpecs: v1alpha1.AutoscalingPolicySpecs{
+				{
+					NamedAutoscalingPolicy: v1alpha1.NamedAutoscalingPolicy{Name: "data"},
+					AutoscalingResources: v1alpha1.AutoscalingResources{
+						MemoryRange:    &v1alpha1.QuantityRange{Min: resource.MustParse("2Gi"), Max: resource.MustParse("8Gi")},
+						StorageRange:   &v1alpha1.QuantityRange{Min: resource.MustParse("10Gi"), Max: resource.MustParse("100Gi")},
+						NodeCountRange: v1alpha1.CountRange{Min: 3, Max: 6},
+					},
+				},
+				{
+					NamedAutoscalingPolicy: v1alpha1.NamedAutoscalingPolicy{Name: "ml"},
+					AutoscalingResources: v1alpha1.AutoscalingResources{
+						MemoryRange:    &v1alpha1.QuantityRange{Min: resource.MustParse("0"), Max: resource.MustParse("4Gi")},
+						NodeCountRange: v1alpha1.CountRange{Min: 0, Max: 1},
+					},
+				},
+			},
+			Schedules: []autoscalingv1alpha1.AutoscalingSchedule{
+				{
+					Name:     "black-friday",
+					Policies: []string{"data"},
+					Start:    &blackFridayStart,
+					End:      &blackFridayEnd,
+					Min:      autoscalingv1alpha1.ScheduledResources{NodeCount: ptr.To[int32](8), Memory: ptr.To(resource.MustParse("4Gi"))},
+				},
+				{
+					Name:     "nightly-reindex",
+					Policies: []string{"data", "ml"},
+					Recurrence: &autoscalingv1alpha1.ScheduleRecurrence{
+						Frequency: autoscalingv1alpha1.DailySchedule,
+						StartTime: "02:00",
+						Duration:  metav1.Duration{Duration: 2 * time.Hour},
+					},
+					// storage is not managed by the ml policy, a lower node count has no effect on the data policy
+					Min: autoscalingv1alpha1.ScheduledResources{NodeCount: ptr.To[int32](1), Storage: ptr.To(resource.MustParse("50Gi"))},
+				},
+			},
+		},
+	}
+}
+
+func Test_applySchedules(t *testing.T) {
+	esa := scheduledAutoscaler()
+
+	// no schedule in progress
+	scheduled, active := applySchedules(esa, time.Date(2026, 11, 26, 12, 0, 0, 0, time.UTC))
+	assert.Empty(t, active)
+	assert.Equal(t, esa.Spec.AutoscalingPolicySpecs, scheduled.Spec.AutoscalingPolicySpecs)
+
+	// both schedules in progress
+	scheduled, active = applySchedules(esa, time.Date(2026, 11, 28, 3, 0, 0, 0, time.UTC))
+	require.Len(t, active, 2)
+	assert.Equal(t, "black-friday", active[0].Name)
+	assert.Equal(t, "nightly-reindex", active[1].Name)
+	assert.True(t, active[1].Start.Equal(ptr.To(metav1.NewTime(time.Date(2026, 11, 28, 2, 0, 0, 0, time.UTC)))))
+	assert.True(t, active[1].End.Equal(ptr.To(metav1.NewTime(time.Date(2026, 11, 28, 4, 0, 0, 0, time.UTC)))))
+
+	data := scheduled.Spec.AutoscalingPolicySpecs[0].AutoscalingResources
+	// the upper limit is raised along with the lower one
+	assert.Equal(t, v1alpha1.CountRange{Min: 8, Max: 8}, data.NodeCountRange)
+	assert.Equal(t, "4Gi", data.MemoryRange.Min.String())
+	assert.Equal(t, "8Gi", data.MemoryRange.Max.String())
+	assert.Equal(t, "50Gi", data.StorageRange.Min.String())
+	ml := scheduled.Spec.AutoscalingPolicySpecs[1].AutoscalingResources
+	assert.Equal(t, v1alpha1.CountRange{Min: 1, Max: 1}, ml.NodeCountRange)
+	assert.Nil(t, ml.StorageRange)
+
+	// the original specification is left untouched
+	assert.Equal(t, int32(3), esa.Spec.AutoscalingPolicySpecs[0].NodeCountRange.Min)
+	assert.Equal(t, "10Gi", esa.Spec.AutoscalingPolicySpecs[0].StorageRange.Min.String())
+}
+
+func Test_nextScheduleTransition(t *testing.T) {
+	esa := scheduledAutoscaler()
+	tests := []struct {
+		name   string
+		now    time.Time
+		want   time.Duration
+		wantOk bool
+	}{
+		{
+			name:   "next nightly window",
+			now:    time.Date(2026, 10, 20, 1, 0, 0, 0, time.UTC),
+			want:   time.Hour,
+			wantOk: true,
+		},
+		{
+			name:   "end of the nightly window",
+			now:    time.Date(2026, 10, 20, 3, 30, 0, 0, time.UTC),
+			want:   30 * time.Minute,
+			wantOk: true,
+		},
+		{
+			name:   "end of the one-off window",
+			now:    time.Date(2026, 11, 29, 23, 0, 0, 0, time.UTC),
+			want:   time.Hour,
+			wantOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := nextScheduleTransition(esa, tt.now)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, ok := nextScheduleTransition(autoscalingv1alpha1.ElasticsearchAutoscaler{}, time.Now())
+	assert.False(t, ok)
+}
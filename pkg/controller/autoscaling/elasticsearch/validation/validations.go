@@ -6,6 +6,7 @@ package validation
 
 import (
 	"context"
+	"fmt"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoscaling/v1alpha1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/autoscaling"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
@@ -20,6 +22,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/set"
 )
 
 type validation func(autoscaler v1alpha1.ElasticsearchAutoscaler) (field.ErrorList, error)
@@ -38,6 +41,7 @@ func validations(ctx context.Context, k8sClient k8s.Client, checker license.Chec
 		func(proposed v1alpha1.ElasticsearchAutoscaler) (field.ErrorList, error) {
 			return noAutoscalingAnnotation(ctx, proposed, k8sClient)
 		},
+		validSchedules,
 	}
 }
 
@@ -49,6 +53,13 @@ var (
 			Index(index).
 			Child(child, moreChildren...)
 	}
+	// schedulePath helps to compute the path used in schedule validation error fields.
+	schedulePath = func(index int, child string, moreChildren ...string) *field.Path {
+		return field.NewPath("spec").
+			Child("schedules").
+			Index(index).
+			Child(child, moreChildren...)
+	}
 )
 
 func noAutoscalingAnnotation(ctx context.Context, esa v1alpha1.ElasticsearchAutoscaler, k8sClient k8s.Client) (field.ErrorList, error) {
@@ -130,3 +141,100 @@ func validLicenseLevel(ctx context.Context, esa v1alpha1.ElasticsearchAutoscaler
 	}
 	return errs, nil
 }
+
+// validSchedules checks that the schedules are consistent with the autoscaling policies and that their windows can be
+// computed.
+func validSchedules(esa v1alpha1.ElasticsearchAutoscaler) (field.ErrorList, error) {
+	var errs field.ErrorList
+	policies := make(map[string]commonv1alpha1.AutoscalingPolicySpec, len(esa.Spec.AutoscalingPolicySpecs))
+	for _, policy := range esa.Spec.AutoscalingPolicySpecs {
+		policies[policy.Name] = policy
+	}
+	names := set.Make()
+	for i, schedule := range esa.Spec.Schedules {
+		if names.Has(schedule.Name) {
+			errs = append(errs, field.Duplicate(schedulePath(i, "name"), schedule.Name))
+		}
+		names.Add(schedule.Name)
+		errs = append(errs, validScheduleWindow(i, schedule)...)
+		if schedule.Min.IsEmpty() {
+			errs = append(errs, field.Required(schedulePath(i, "min"), "at least one minimum resource must be specified"))
+		}
+		for j, name := range schedule.Policies {
+			policy, exists := policies[name]
+			if !exists {
+				errs = append(errs, field.NotFound(schedulePath(i, "policies").Index(j), name))
+				continue
+			}
+			if schedule.Min.CPU != nil && !policy.IsCPUDefined() {
+				errs = append(errs, field.Invalid(schedulePath(i, "min", "cpu"), schedule.Min.CPU.String(), fmt.Sprintf("cpu is not autoscaled by policy %s", name)))
+			}
+			if schedule.Min.Memory != nil && !policy.IsMemoryDefined() {
+				errs = append(errs, field.Invalid(schedulePath(i, "min", "memory"), schedule.Min.Memory.String(), fmt.Sprintf("memory is not autoscaled by policy %s", name)))
+			}
+			if schedule.Min.Storage != nil && !policy.IsStorageDefined() {
+				errs = append(errs, field.Invalid(schedulePath(i, "min", "storage"), schedule.Min.Storage.String(), fmt.Sprintf("storage is not autoscaled by policy %s", name)))
+			}
+		}
+		if schedule.Min.NodeCount != nil && *schedule.Min.NodeCount <= 0 {
+			errs = append(errs, field.Invalid(schedulePath(i, "min", "nodeCount"), *schedule.Min.NodeCount, "node count must be greater than 0"))
+		}
+	}
+	return errs, nil
+}
+
+// validScheduleWindow checks that a schedule either defines a one-off window or a valid recurrence.
+func validScheduleWindow(index int, schedule v1alpha1.AutoscalingSchedule) field.ErrorList {
+	var errs field.ErrorList
+	recurrence := schedule.Recurrence
+	if recurrence == nil {
+		switch {
+		case schedule.Start == nil:
+			errs = append(errs, field.Required(schedulePath(index, "start"), "start is required if no recurrence is specified"))
+		case schedule.End == nil:
+			errs = append(errs, field.Required(schedulePath(index, "end"), "end is required if no recurrence is specified"))
+		case !schedule.End.After(schedule.Start.Time):
+			errs = append(errs, field.Invalid(schedulePath(index, "end"), schedule.End.String(), "end must be after start"))
+		}
+		return errs
+	}
+	if schedule.Start != nil || schedule.End != nil {
+		errs = append(errs, field.Forbidden(schedulePath(index, "recurrence"), "recurrence cannot be used with start and end"))
+	}
+	if _, _, err := recurrence.ParseStartTime(); err != nil {
+		errs = append(errs, field.Invalid(schedulePath(index, "recurrence", "startTime"), recurrence.StartTime, err.Error()))
+	}
+	if _, err := recurrence.Location(); err != nil {
+		errs = append(errs, field.Invalid(schedulePath(index, "recurrence", "timeZone"), recurrence.TimeZone, err.Error()))
+	}
+	period := recurrence.Frequency.Period()
+	if recurrence.Duration.Duration <= 0 || recurrence.Duration.Duration > period {
+		errs = append(errs, field.Invalid(
+			schedulePath(index, "recurrence", "duration"),
+			recurrence.Duration.Duration.String(),
+			fmt.Sprintf("duration must be positive and at most %s for a %s recurrence", period, recurrence.Frequency),
+		))
+	}
+	switch recurrence.Frequency {
+	case v1alpha1.WeeklySchedule:
+		if len(recurrence.DaysOfWeek) == 0 {
+			errs = append(errs, field.Required(schedulePath(index, "recurrence", "daysOfWeek"), "days of week are required for a weekly recurrence"))
+		}
+	case v1alpha1.MonthlySchedule:
+		if len(recurrence.DaysOfMonth) == 0 {
+			errs = append(errs, field.Required(schedulePath(index, "recurrence", "daysOfMonth"), "days of month are required for a monthly recurrence"))
+		}
+		for j, day := range recurrence.DaysOfMonth {
+			if day == 0 || day < -31 || day > 31 {
+				errs = append(errs, field.Invalid(schedulePath(index, "recurrence", "daysOfMonth").Index(j), day, "day of month must be between 1 and 31, or between -31 and -1"))
+			}
+		}
+	}
+	if recurrence.Frequency != v1alpha1.WeeklySchedule && len(recurrence.DaysOfWeek) > 0 {
+		errs = append(errs, field.Forbidden(schedulePath(index, "recurrence", "daysOfWeek"), "days of week can only be used with a weekly recurrence"))
+	}
+	if recurrence.Frequency != v1alpha1.MonthlySchedule && len(recurrence.DaysOfMonth) > 0 {
+		errs = append(errs, field.Forbidden(schedulePath(index, "recurrence", "daysOfMonth"), "days of month can only be used with a monthly recurrence"))
+	}
+	return errs
+}
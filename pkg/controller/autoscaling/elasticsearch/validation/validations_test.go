@@ -0,0 +1,151 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoscaling/v1alpha1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+)
+
+func Test_validSchedules(t *testing.T) {
+	start := metav1.NewTime(time.Date(2026, 11, 27, 0, 0, 0, 0, time.UTC))
+	end := metav1.NewTime(start.Add(72 * time.Hour))
+	nightly := &v1alpha1.ScheduleRecurrence{
+		Frequency: v1alpha1.DailySchedule,
+		StartTime: "01:30",
+		Duration:  metav1.Duration{Duration: 3 * time.Hour},
+		TimeZone:  "Europe/Paris",
+	}
+	nodeCount := v1alpha1.ScheduledResources{NodeCount: ptr.To[int32](4)}
+	tests := []struct {
+		name      string
+		schedules []v1alpha1.AutoscalingSchedule
+		wantErrs  []string
+	}{
+		{
+			name: "no schedule",
+		},
+		{
+			name: "valid one-off and recurring schedules",
+			schedules: []v1alpha1.AutoscalingSchedule{
+				{Name: "black-friday", Policies: []string{"data", "ml"}, Start: &start, End: &end, Min: nodeCount},
+				{Name: "reindex", Policies: []string{"data"}, Recurrence: nightly, Min: v1alpha1.ScheduledResources{Memory: ptr.To(resource.MustParse("8Gi"))}},
+				{Name: "month-end", Policies: []string{"data"}, Min: nodeCount, Recurrence: &v1alpha1.ScheduleRecurrence{
+					Frequency:   v1alpha1.MonthlySchedule,
+					StartTime:   "00:00",
+					Duration:    metav1.Duration{Duration: 48 * time.Hour},
+					DaysOfMonth: []int32{-1, 15},
+				}},
+			},
+		},
+		{
+			name: "duplicated name, unknown policy and no minimum",
+			schedules: []v1alpha1.AutoscalingSchedule{
+				{Name: "reindex", Policies: []string{"data"}, Recurrence: nightly, Min: nodeCount},
+				{Name: "reindex", Policies: []string{"hot"}, Recurrence: nightly},
+			},
+			wantErrs: []string{
+				`spec.schedules[1].name: Duplicate value: "reindex"`,
+				"spec.schedules[1].min: Required value",
+				`spec.schedules[1].policies[0]: Not found: "hot"`,
+			},
+		},
+		{
+			name: "resource not managed by the policy",
+			schedules: []v1alpha1.AutoscalingSchedule{
+				{Name: "reindex", Policies: []string{"ml"}, Recurrence: nightly, Min: v1alpha1.ScheduledResources{Storage: ptr.To(resource.MustParse("1Ti"))}},
+			},
+			wantErrs: []string{"spec.schedules[0].min.storage: Invalid value: \"1Ti\": storage is not autoscaled by policy ml"},
+		},
+		{
+			name: "incomplete or inverted one-off window",
+			schedules: []v1alpha1.AutoscalingSchedule{
+				{Name: "no-end", Policies: []string{"data"}, Start: &start, Min: nodeCount},
+				{Name: "inverted", Policies: []string{"data"}, Start: &end, End: &start, Min: nodeCount},
+				{Name: "both", Policies: []string{"data"}, Start: &start, End: &end, Recurrence: nightly, Min: nodeCount},
+			},
+			wantErrs: []string{
+				"spec.schedules[0].end: Required value",
+				"spec.schedules[1].end: Invalid value",
+				"spec.schedules[2].recurrence: Forbidden",
+			},
+		},
+		{
+			name: "invalid recurrence",
+			schedules: []v1alpha1.AutoscalingSchedule{
+				{Name: "weekly", Policies: []string{"data"}, Min: nodeCount, Recurrence: &v1alpha1.ScheduleRecurrence{
+					Frequency:   v1alpha1.WeeklySchedule,
+					StartTime:   "25:00",
+					Duration:    metav1.Duration{Duration: 8 * 24 * time.Hour},
+					DaysOfMonth: []int32{1},
+					TimeZone:    "Mars/Olympus_Mons",
+				}},
+				{Name: "monthly", Policies: []string{"data"}, Min: nodeCount, Recurrence: &v1alpha1.ScheduleRecurrence{
+					Frequency:   v1alpha1.MonthlySchedule,
+					StartTime:   "00:00",
+					Duration:    metav1.Duration{Duration: time.Hour},
+					DaysOfMonth: []int32{0, 32},
+				}},
+			},
+			wantErrs: []string{
+				"spec.schedules[0].recurrence.startTime: Invalid value",
+				"spec.schedules[0].recurrence.timeZone: Invalid value",
+				"spec.schedules[0].recurrence.duration: Invalid value: \"192h0m0s\": duration must be positive and at most 168h0m0s for a Weekly recurrence",
+				"spec.schedules[0].recurrence.daysOfWeek: Required value",
+				"spec.schedules[0].recurrence.daysOfMonth: Forbidden",
+				"spec.schedules[1].recurrence.daysOfMonth[0]: Invalid value",
+				"spec.schedules[1].recurrence.daysOfMonth[1]: Invalid value",
+			},
+		},
+		{
+			name: "node count not greater than 0",
+			schedules: []v1alpha1.AutoscalingSchedule{
+				{Name: "zero", Policies: []string{"data"}, Recurrence: nightly, Min: v1alpha1.ScheduledResources{NodeCount: ptr.To[int32](0)}},
+				{Name: "negative", Policies: []string{"data"}, Recurrence: nightly, Min: v1alpha1.ScheduledResources{NodeCount: ptr.To[int32](-1)}},
+			},
+			wantErrs: []string{
+				"spec.schedules[0].min.nodeCount: Invalid value: 0: node count must be greater than 0",
+				"spec.schedules[1].min.nodeCount: Invalid value: -1: node count must be greater than 0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			esa := v1alpha1.ElasticsearchAutoscaler{
+				Spec: v1alpha1.ElasticsearchAutoscalerSpec{
+					AutoscalingPolicySpecs: commonv1alpha1.AutoscalingPolicySpecs{
+						{
+							NamedAutoscalingPolicy: commonv1alpha1.NamedAutoscalingPolicy{Name: "data"},
+							AutoscalingResources:   defaultResources,
+						},
+						{
+							NamedAutoscalingPolicy: commonv1alpha1.NamedAutoscalingPolicy{Name: "ml"},
+							AutoscalingResources: commonv1alpha1.AutoscalingResources{
+								MemoryRange:    defaultResources.MemoryRange,
+								NodeCountRange: commonv1alpha1.CountRange{Min: 0, Max: 2},
+							},
+						},
+					},
+					Schedules: tt.schedules,
+				},
+			}
+			errs, err := validSchedules(esa)
+			require.NoError(t, err)
+			require.Len(t, errs, len(tt.wantErrs), errs.ToAggregate())
+			for i, want := range tt.wantErrs {
+				assert.Contains(t, errs[i].Error(), want)
+			}
+		})
+	}
+}
@@ -456,6 +456,84 @@ func TestValidateElasticsearchAutoscaler(t *testing.T) {
 			},
 			wantValidationError: ptr.To[string]("spec.policies[0].cpu.max: Invalid value: \"2\": max quantity must be greater or equal than min quantity"),
 		},
+		{
+			name: "Metrics recommender",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"nodeset-1": {"data_content"}}, nil, "8.0.0"),
+				esa: v1alpha1.ElasticsearchAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "esa", Namespace: "ns"},
+					Spec: v1alpha1.ElasticsearchAutoscalerSpec{
+						ElasticsearchRef: v1alpha1.ElasticsearchRef{
+							Name: "es",
+						},
+						AutoscalingPolicySpecs: commonv1alpha1.AutoscalingPolicySpecs{
+							{
+								NamedAutoscalingPolicy: commonv1alpha1.NamedAutoscalingPolicy{
+									Name:              "policy",
+									AutoscalingPolicy: commonv1alpha1.AutoscalingPolicy{Roles: []string{"data_content"}},
+								},
+								AutoscalingResources: defaultResources,
+								Recommender:          commonv1alpha1.MetricsRecommender,
+								Metrics:              &commonv1alpha1.MetricsRecommenderSettings{TargetCPUPercent: ptr.To[int32](60)},
+							},
+						},
+					},
+				},
+				checker: yesCheck,
+			},
+		},
+		{
+			name: "Metrics settings without the metrics recommender",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"nodeset-1": {"data_content"}}, nil, "8.0.0"),
+				esa: v1alpha1.ElasticsearchAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "esa", Namespace: "ns"},
+					Spec: v1alpha1.ElasticsearchAutoscalerSpec{
+						ElasticsearchRef: v1alpha1.ElasticsearchRef{
+							Name: "es",
+						},
+						AutoscalingPolicySpecs: commonv1alpha1.AutoscalingPolicySpecs{
+							{
+								NamedAutoscalingPolicy: commonv1alpha1.NamedAutoscalingPolicy{
+									Name:              "policy",
+									AutoscalingPolicy: commonv1alpha1.AutoscalingPolicy{Roles: []string{"data_content"}},
+								},
+								AutoscalingResources: defaultResources,
+								Metrics:              &commonv1alpha1.MetricsRecommenderSettings{TargetCPUPercent: ptr.To[int32](60)},
+							},
+						},
+					},
+				},
+				checker: yesCheck,
+			},
+			wantValidationError: ptr.To[string]("spec.policies[0].metrics: Forbidden: metrics settings can only be used with the metrics recommender"),
+		},
+		{
+			name: "ML policy with the metrics recommender",
+			args: args{
+				es: es(map[string]string{}, map[string][]string{"nodeset-1": {"ml"}}, nil, "8.0.0"),
+				esa: v1alpha1.ElasticsearchAutoscaler{
+					ObjectMeta: metav1.ObjectMeta{Name: "esa", Namespace: "ns"},
+					Spec: v1alpha1.ElasticsearchAutoscalerSpec{
+						ElasticsearchRef: v1alpha1.ElasticsearchRef{
+							Name: "es",
+						},
+						AutoscalingPolicySpecs: commonv1alpha1.AutoscalingPolicySpecs{
+							{
+								NamedAutoscalingPolicy: commonv1alpha1.NamedAutoscalingPolicy{
+									Name:              "policy",
+									AutoscalingPolicy: commonv1alpha1.AutoscalingPolicy{Roles: []string{"ml"}},
+								},
+								AutoscalingResources: defaultResources,
+								Recommender:          commonv1alpha1.MetricsRecommender,
+							},
+						},
+					},
+				},
+				checker: yesCheck,
+			},
+			wantValidationError: ptr.To[string]("spec.policies[0].recommender: Invalid value: \"metrics\": ML nodes cannot be managed by the metrics recommender"),
+		},
 		// Volumes validations
 		{
 			name: "Not the default volume claim",
@@ -0,0 +1,80 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package beattype holds the registry of the Beat types known to the operator. Each type is declared in a YAML file of
+// the types directory, holding the defaults applied by the Beat controller and the constraints enforced by the
+// validating webhook for that type, so that supporting a new Beat type is a matter of adding a file to the registry.
+package beattype
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+// Type is the type of a Beat, as specified in the Beat resource.
+type Type string
+
+// HostMount is a path of the host mounted into the Beat container.
+type HostMount struct {
+	// Name of the volume.
+	Name string `json:"name"`
+	// HostPath is the path on the host.
+	HostPath string `json:"hostPath"`
+	// MountPath is the path in the Beat container.
+	MountPath string `json:"mountPath"`
+	// ReadOnly is true if the path must be mounted read-only.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	// Type of the host path, unset by default.
+	Type corev1.HostPathType `json:"type,omitempty"`
+}
+
+// Definition declares the defaults and constraints of a Beat type.
+// DefaultConfig, HostMounts, Env, SecurityContext and PolicyRules are only applied to Beats opted into the default
+// configuration with the beatv1beta1.DefaultConfigAnnotation annotation, leaving the other Beats untouched.
+type Definition struct {
+	// Image is the default container image. The image must be specified in the Beat resource if empty.
+	Image container.Image `json:"image,omitempty"`
+	// MinVersion is the minimum version supported for this Beat type.
+	MinVersion version.Version `json:"-"`
+	// KibanaDashboards is true if the Beat can set up its dashboards when associated with Kibana.
+	KibanaDashboards bool `json:"kibanaDashboards,omitempty"`
+	// DefaultConfig is the Beat configuration used when opted into and neither config nor configRef is specified.
+	DefaultConfig map[string]any `json:"defaultConfig,omitempty"`
+	// HostMounts are the host paths read by the default configuration.
+	HostMounts []HostMount `json:"hostMounts,omitempty"`
+	// Env holds the environment variables referenced by the default configuration.
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// SecurityContext is set on the Beat container, unless already specified in the pod template.
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+	// PolicyRules are the Kubernetes API permissions the default configuration requires from the Pod service account.
+	PolicyRules []rbacv1.PolicyRule `json:"policyRules,omitempty"`
+}
+
+// Get returns the definition of the given Beat type and whether the type is known.
+func Get(typ string) (Definition, bool) {
+	def, ok := registry[Type(typ)]
+	return def, ok
+}
+
+// Types returns the known Beat types, sorted by name.
+func Types() []Type {
+	types := make([]Type, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// UsesDefaultConfig returns true if the given Beat is opted into the default configuration of its type, and does not
+// specify its own configuration.
+func UsesDefaultConfig(beat beatv1beta1.Beat) bool {
+	return beat.Annotations[beatv1beta1.DefaultConfigAnnotation] == "true" && beat.Spec.Config == nil && beat.Spec.ConfigRef == nil
+}
@@ -0,0 +1,156 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package beattype
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+func TestTypes(t *testing.T) {
+	assert.Equal(t, []Type{Auditbeat, Filebeat, Heartbeat, Journalbeat, Metricbeat, Osquerybeat, Packetbeat}, Types())
+	// well-known types come with a default image
+	for _, typ := range Types() {
+		def, _ := Get(string(typ))
+		_, known := beatv1beta1.KnownTypes[string(typ)]
+		assert.Equal(t, known, def.Image != "", typ)
+	}
+}
+
+func TestGet(t *testing.T) {
+	def, ok := Get("packetbeat")
+	require.True(t, ok)
+	assert.Equal(t, Definition{
+		Image:            "beats/packetbeat",
+		MinVersion:       version.MinFor(7, 0, 0),
+		KibanaDashboards: true,
+		DefaultConfig:    def.DefaultConfig,
+		SecurityContext: &corev1.SecurityContext{
+			RunAsUser:    ptr.To[int64](0),
+			Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+		},
+	}, def)
+
+	def, ok = Get("osquerybeat")
+	require.True(t, ok)
+	assert.Empty(t, def.Image)
+	assert.Equal(t, version.MinFor(7, 13, 0), def.MinVersion)
+
+	_, ok = Get("apachebeat")
+	assert.False(t, ok)
+}
+
+func TestRegistry(t *testing.T) {
+	for typ, def := range registry {
+		t.Run(string(typ), func(t *testing.T) {
+			_, err := settings.NewCanonicalConfigFrom(def.DefaultConfig)
+			require.NoError(t, err)
+
+			mountPaths := map[string]struct{}{}
+			for _, m := range def.HostMounts {
+				assert.NotContains(t, mountPaths, m.MountPath)
+				mountPaths[m.MountPath] = struct{}{}
+			}
+		})
+	}
+}
+
+func Test_load(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		files   map[string]string
+		want    map[Type]Definition
+		wantErr string
+	}{
+		{
+			name: "valid definition",
+			files: map[string]string{
+				"types/examplebeat.yaml": "type: examplebeat\nminVersion: 8.1.0\ndefaultConfig:\n  examplebeat.inputs: []\n",
+			},
+			want: map[Type]Definition{
+				"examplebeat": {MinVersion: version.MinFor(8, 1, 0), DefaultConfig: map[string]any{"examplebeat.inputs": []any{}}},
+			},
+		},
+		{
+			name:    "unknown field",
+			files:   map[string]string{"types/examplebeat.yaml": "type: examplebeat\nminVersion: 8.1.0\nimages: example\n"},
+			wantErr: `while parsing Beat type definition types/examplebeat.yaml`,
+		},
+		{
+			name:    "missing type",
+			files:   map[string]string{"types/examplebeat.yaml": "minVersion: 8.1.0\n"},
+			wantErr: "missing type in Beat type definition types/examplebeat.yaml",
+		},
+		{
+			name:    "invalid version",
+			files:   map[string]string{"types/examplebeat.yaml": "type: examplebeat\nminVersion: latest\n"},
+			wantErr: "while parsing the minimum version in Beat type definition types/examplebeat.yaml",
+		},
+		{
+			name: "duplicate type",
+			files: map[string]string{
+				"types/a.yaml": "type: examplebeat\nminVersion: 8.1.0\n",
+				"types/b.yaml": "type: examplebeat\nminVersion: 8.1.0\n",
+			},
+			wantErr: "duplicate definition of Beat type examplebeat in types/b.yaml",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, content := range tt.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(content)}
+			}
+			got, err := load(fsys)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUsesDefaultConfig(t *testing.T) {
+	optedIn := metav1.ObjectMeta{Annotations: map[string]string{beatv1beta1.DefaultConfigAnnotation: "true"}}
+	for _, tt := range []struct {
+		name string
+		beat beatv1beta1.Beat
+		want bool
+	}{
+		{
+			name: "not opted in",
+			beat: beatv1beta1.Beat{},
+		},
+		{
+			name: "opted in",
+			beat: beatv1beta1.Beat{ObjectMeta: optedIn},
+			want: true,
+		},
+		{
+			name: "opted in with config",
+			beat: beatv1beta1.Beat{ObjectMeta: optedIn, Spec: beatv1beta1.BeatSpec{Config: &commonv1.Config{}}},
+		},
+		{
+			name: "opted in with config ref",
+			beat: beatv1beta1.Beat{ObjectMeta: optedIn, Spec: beatv1beta1.BeatSpec{ConfigRef: &commonv1.ConfigSource{}}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, UsesDefaultConfig(tt.beat))
+		})
+	}
+}
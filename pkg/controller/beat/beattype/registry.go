@@ -0,0 +1,82 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package beattype
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+const (
+	Auditbeat   Type = "auditbeat"
+	Filebeat    Type = "filebeat"
+	Heartbeat   Type = "heartbeat"
+	Journalbeat Type = "journalbeat"
+	Metricbeat  Type = "metricbeat"
+	Osquerybeat Type = "osquerybeat"
+	Packetbeat  Type = "packetbeat"
+)
+
+var (
+	// types holds one definition file per Beat type.
+	//go:embed types/*.yaml
+	types embed.FS
+
+	registry = mustLoad(types)
+)
+
+// definitionFile is the content of a definition file of the registry.
+type definitionFile struct {
+	Type       Type   `json:"type"`
+	MinVersion string `json:"minVersion"`
+	Definition `json:",inline"`
+}
+
+func mustLoad(fsys fs.FS) map[Type]Definition {
+	definitions, err := load(fsys)
+	if err != nil {
+		panic(err)
+	}
+	return definitions
+}
+
+// load parses the definition files of the registry.
+func load(fsys fs.FS) (map[Type]Definition, error) {
+	files, err := fs.Glob(fsys, path.Join("types", "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	definitions := make(map[Type]Definition, len(files))
+	for _, file := range files {
+		bytes, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var def definitionFile
+		if err := yaml.UnmarshalStrict(bytes, &def); err != nil {
+			return nil, fmt.Errorf("while parsing Beat type definition %s: %w", file, err)
+		}
+		if def.Type == "" {
+			return nil, fmt.Errorf("missing type in Beat type definition %s", file)
+		}
+		if _, exists := definitions[def.Type]; exists {
+			return nil, fmt.Errorf("duplicate definition of Beat type %s in %s", def.Type, file)
+		}
+		v, err := version.Parse(def.MinVersion)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing the minimum version in Beat type definition %s: %w", file, err)
+		}
+		// accept the pre-releases of the minimum version
+		def.Definition.MinVersion = version.MinFor(v.Major, v.Minor, v.Patch)
+		definitions[def.Type] = def.Definition
+	}
+	return definitions, nil
+}
@@ -19,6 +19,7 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/labels"
@@ -100,6 +101,7 @@ func BuildKibanaConfig(ctx context.Context, client k8s.Client, associated beatv1
 func buildBeatConfig(
 	params DriverParams,
 	managedConfig *settings.CanonicalConfig,
+	definition beattype.Definition,
 ) ([]byte, error) {
 	cfg := settings.NewCanonicalConfig()
 
@@ -118,6 +120,12 @@ func buildBeatConfig(
 		return nil, err
 	}
 
+	if beattype.UsesDefaultConfig(params.Beat) && definition.DefaultConfig != nil {
+		if userConfig, err = settings.NewCanonicalConfigFrom(definition.DefaultConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	if userConfig == nil {
 		return cfg.Render()
 	}
@@ -163,10 +171,11 @@ func getUserConfig(params DriverParams) (*settings.CanonicalConfig, error) {
 func reconcileConfig(
 	params DriverParams,
 	managedConfig *settings.CanonicalConfig,
+	definition beattype.Definition,
 	configHash hash.Hash,
 	meta metadata.Metadata,
 ) error {
-	cfgBytes, err := buildBeatConfig(params, managedConfig)
+	cfgBytes, err := buildBeatConfig(params, managedConfig, definition)
 	if err != nil {
 		return err
 	}
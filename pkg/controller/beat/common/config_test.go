@@ -16,6 +16,7 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
@@ -92,11 +93,14 @@ func Test_buildBeatConfig(t *testing.T) {
 	withAssocWithConfig := *withAssoc.DeepCopy()
 	withAssocWithConfig.Spec.Config = userCfg
 
+	defaultConfigOptIn := metav1.ObjectMeta{Annotations: map[string]string{beatv1beta1.DefaultConfigAnnotation: "true"}}
+
 	for _, tt := range []struct {
 		name          string
 		client        k8s.Client
 		beat          beatv1beta1.Beat
 		managedConfig *settings.CanonicalConfig
+		definition    beattype.Definition
 		want          *settings.CanonicalConfig
 		wantErr       bool
 	}{
@@ -112,6 +116,23 @@ func Test_buildBeatConfig(t *testing.T) {
 			},
 			want: userCanonicalCfg,
 		},
+		{
+			name:       "no association, default config",
+			beat:       beatv1beta1.Beat{ObjectMeta: defaultConfigOptIn},
+			definition: beattype.Definition{DefaultConfig: map[string]any{"default": "true"}},
+			want:       settings.MustCanonicalConfig(map[string]any{"default": "true"}),
+		},
+		{
+			name:       "no association, default config not opted into",
+			beat:       beatv1beta1.Beat{},
+			definition: beattype.Definition{DefaultConfig: map[string]any{"default": "true"}},
+		},
+		{
+			name:       "no association, user config takes precedence over default config",
+			beat:       beatv1beta1.Beat{ObjectMeta: defaultConfigOptIn, Spec: beatv1beta1.BeatSpec{Config: userCfg}},
+			definition: beattype.Definition{DefaultConfig: map[string]any{"default": "true"}},
+			want:       userCanonicalCfg,
+		},
 		{
 			name:          "no association, managed config",
 			beat:          beatv1beta1.Beat{},
@@ -227,7 +248,7 @@ func Test_buildBeatConfig(t *testing.T) {
 				Watches:       watches.NewDynamicWatches(),
 				EventRecorder: nil,
 				Beat:          tt.beat,
-			}, tt.managedConfig)
+			}, tt.managedConfig, tt.definition)
 
 			diff := tt.want.Diff(settings.MustParseConfig(gotYaml), nil)
 
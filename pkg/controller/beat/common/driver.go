@@ -14,9 +14,9 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	beat_stackmon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common/stackmon"
 	commonassociation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/association"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
@@ -27,12 +27,34 @@ import (
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
 )
 
-type Type string
-
 type Driver interface {
 	Reconcile() (*reconciler.Results, *beatv1beta1.BeatStatus)
 }
 
+// NewDriver returns the driver of the given Beat, configured from the definition of its type in the beattype registry.
+// Beats of unknown types get no defaults.
+func NewDriver(params DriverParams) Driver {
+	def, _ := beattype.Get(params.Beat.Spec.Type)
+	return &typeDriver{DriverParams: params, definition: def}
+}
+
+type typeDriver struct {
+	DriverParams
+	definition beattype.Definition
+}
+
+func (d *typeDriver) Reconcile() (*reconciler.Results, *beatv1beta1.BeatStatus) {
+	var managedConfig *settings.CanonicalConfig
+	if d.definition.KibanaDashboards {
+		var err error
+		managedConfig, err = BuildKibanaConfig(d.Context, d.Client, beatv1beta1.BeatKibanaAssociation{Beat: &d.Beat})
+		if err != nil {
+			return reconciler.NewResult(d.Context).WithError(err), d.Status
+		}
+	}
+	return Reconcile(d.DriverParams, managedConfig, d.definition)
+}
+
 type DriverParams struct {
 	Context context.Context
 
@@ -73,7 +95,7 @@ var _ driver.Interface = DriverParams{}
 func Reconcile(
 	params DriverParams,
 	managedConfig *settings.CanonicalConfig,
-	defaultImage container.Image,
+	definition beattype.Definition,
 ) (*reconciler.Results, *beatv1beta1.BeatStatus) {
 	results := reconciler.NewResult(params.Context)
 
@@ -93,7 +115,7 @@ func Reconcile(
 	configHash := fnv.New32a()
 	// metadata to propagate to children
 	meta := metadata.Propagate(&params.Beat, metadata.Metadata{Labels: params.Beat.GetIdentityLabels()})
-	if err := reconcileConfig(params, managedConfig, configHash, meta); err != nil {
+	if err := reconcileConfig(params, managedConfig, definition, configHash, meta); err != nil {
 		return results.WithError(err), params.Status
 	}
 
@@ -102,7 +124,7 @@ func Reconcile(
 		return results.WithError(err), params.Status
 	}
 
-	podTemplate, err := buildPodTemplate(params, defaultImage, configHash, meta)
+	podTemplate, err := buildPodTemplate(params, definition, configHash, meta)
 	if err != nil {
 		if errors.Is(err, beat_stackmon.ErrMonitoringClusterUUIDUnavailable) {
 			results.WithReconciliationState(reconciler.RequeueAfter(reconciler.DefaultRequeue).WithReason("ElasticsearchRef UUID unavailable while configuring Beats stack monitoring"))
@@ -16,6 +16,7 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	beat_stackmon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
@@ -76,7 +77,7 @@ func initContainerParameters(typ string) keystore.InitContainerParameters {
 
 func buildPodTemplate(
 	params DriverParams,
-	defaultImage container.Image,
+	definition beattype.Definition,
 	configHash hash.Hash32,
 	meta metadata.Metadata,
 ) (corev1.PodTemplateSpec, error) {
@@ -106,6 +107,15 @@ func buildPodTemplate(
 		dataVolume,
 	}
 
+	// host paths, env vars and security context of the Beat type are only required by its default configuration
+	defaultConfig := beattype.UsesDefaultConfig(params.Beat)
+	if defaultConfig {
+		for _, m := range definition.HostMounts {
+			vols = append(vols, volume.NewHostVolume(m.Name, m.HostPath, m.MountPath, m.ReadOnly, m.Type))
+		}
+	}
+	asRoot := runningAsRoot(params.Beat) || (defaultConfig && definitionRunsAsRoot(definition, podTemplate, spec.Type))
+
 	for _, assoc := range params.Beat.GetAssociations() {
 		assocConf, err := assoc.AssociationConf()
 		if err != nil {
@@ -139,7 +149,31 @@ func buildPodTemplate(
 		initContainers = append(initContainers, keystoreResources.InitContainer)
 	}
 
-	if monitoring.IsLogsDefined(&params.Beat) {
+	collectorMode := params.Beat.Spec.Monitoring.GetMode() == commonv1.CollectorMonitoringMode
+	if collectorMode && monitoring.IsDefined(&params.Beat) {
+		sideCar, err := beat_stackmon.Collector(params.Context, params.Client, &params.Beat, meta)
+		if err != nil {
+			return podTemplate, err
+		}
+		if _, err := reconciler.ReconcileSecret(params.Context, params.Client, sideCar.ConfigSecret, &params.Beat); err != nil {
+			return podTemplate, err
+		}
+		// Add the volumes shared with the collector for logs consumption and for the Unix socket.
+		for _, v := range sideCar.Container.VolumeMounts {
+			if v.Name == beat_stackmon.FilebeatLogsVolumeName || v.Name == beat_stackmon.SharedDataVolumeName {
+				volumeMounts = append(volumeMounts, v)
+			}
+		}
+		volumes = append(volumes, sideCar.Volumes...)
+		if asRoot {
+			sideCar.Container.SecurityContext = &corev1.SecurityContext{
+				RunAsUser: ptr.To[int64](0),
+			}
+		}
+		sideCars = append(sideCars, sideCar.Container)
+	}
+
+	if monitoring.IsLogsDefined(&params.Beat) && !collectorMode {
 		sideCar, err := beat_stackmon.Filebeat(params.Context, params.Client, &params.Beat, params.Beat.Spec.Version, meta)
 		if err != nil {
 			return podTemplate, err
@@ -157,7 +191,7 @@ func buildPodTemplate(
 			MountPath: "/usr/share/filebeat/logs",
 		})
 		volumes = append(volumes, sideCar.Volumes...)
-		if runningAsRoot(params.Beat) {
+		if asRoot {
 			sideCar.Container.SecurityContext = &corev1.SecurityContext{
 				RunAsUser: ptr.To[int64](0),
 			}
@@ -165,7 +199,7 @@ func buildPodTemplate(
 		sideCars = append(sideCars, sideCar.Container)
 	}
 
-	if monitoring.IsMetricsDefined(&params.Beat) {
+	if monitoring.IsMetricsDefined(&params.Beat) && !collectorMode {
 		sideCar, err := beat_stackmon.MetricBeat(params.Context, params.Client, &params.Beat, meta)
 		if err != nil {
 			return podTemplate, err
@@ -183,7 +217,7 @@ func buildPodTemplate(
 			MountPath: "/var/shared",
 		})
 		volumes = append(volumes, sideCar.Volumes...)
-		if runningAsRoot(params.Beat) {
+		if asRoot {
 			sideCar.Container.SecurityContext = &corev1.SecurityContext{
 				RunAsUser: ptr.To[int64](0),
 			}
@@ -205,13 +239,25 @@ func buildPodTemplate(
 		WithLabels(meta.Labels).
 		WithAnnotations(meta.Annotations).
 		WithResources(defaultResources).
-		WithDockerImage(spec.Image, container.ImageRepository(defaultImage, v)).
+		WithDockerImage(spec.Image, container.ImageRepository(params.Beat.Namespace, definition.Image, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(params.Beat.Namespace)...).
 		WithVolumes(volumes...).
 		WithVolumeMounts(volumeMounts...).
 		WithInitContainers(initContainers...).
 		WithInitContainerDefaults().
 		WithContainers(sideCars...)
 
+	if defaultConfig {
+		builder = builder.WithEnv(definition.Env...)
+		if main := builder.MainContainer(); main != nil && main.SecurityContext == nil && definition.SecurityContext != nil {
+			main.SecurityContext = definition.SecurityContext.DeepCopy()
+		}
+		// the default configuration relies on the service account token to access the Kubernetes API
+		if len(definition.PolicyRules) > 0 && podTemplate.Spec.AutomountServiceAccountToken == nil {
+			builder.PodTemplate.Spec.AutomountServiceAccountToken = ptr.To(true)
+		}
+	}
+
 	// If logs monitoring is enabled, remove the "-e" argument from the main container
 	// if it exists, and do not include the "-e" startup option for the Beat so that
 	// it does not log only to stderr, and writes log file for filebeat to consume.
@@ -256,6 +302,20 @@ func runningAsRoot(beat beatv1beta1.Beat) bool {
 	return false
 }
 
+// definitionRunsAsRoot returns true if the security context of the Beat type runs the Beat container as root, and is not
+// overridden in the pod template.
+func definitionRunsAsRoot(definition beattype.Definition, podTemplate corev1.PodTemplateSpec, containerName string) bool {
+	if definition.SecurityContext == nil || definition.SecurityContext.RunAsUser == nil || *definition.SecurityContext.RunAsUser != 0 {
+		return false
+	}
+	for _, c := range podTemplate.Spec.Containers {
+		if c.Name == containerName && c.SecurityContext != nil {
+			return false
+		}
+	}
+	return true
+}
+
 func createDataVolume(dp DriverParams) volume.VolumeLike {
 	dataMountPath := fmt.Sprintf(DataPathTemplate, dp.Beat.Spec.Type)
 	hostDataPath := fmt.Sprintf(DataMountPathTemplate, dp.Beat.Namespace, dp.Beat.Name, dp.Beat.Spec.Type)
@@ -25,6 +25,7 @@ import (
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
@@ -281,7 +282,7 @@ func Test_buildPodTemplate(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.args.params.Context = context.Background()
 			meta := metadata.Propagate(&tt.args.params.Beat, metadata.Metadata{Labels: tt.args.params.Beat.GetIdentityLabels()})
-			podTemplateSpec, err := buildPodTemplate(tt.args.params, tt.args.defaultImage, tt.args.initialHash, meta)
+			podTemplateSpec, err := buildPodTemplate(tt.args.params, beattype.Definition{Image: tt.args.defaultImage}, tt.args.initialHash, meta)
 			if (err != nil) != tt.want.err {
 				t.Errorf("buildPodTemplate() error = %v, wantErr %v", err, tt.want.err)
 				return
@@ -563,3 +564,71 @@ func Test_runningAsRoot(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildPodTemplate_typeDefaults(t *testing.T) {
+	definition, ok := beattype.Get("filebeat")
+	require.True(t, ok)
+	beatWith := func(annotations map[string]string, config *commonv1.Config) beatv1beta1.Beat {
+		return beatv1beta1.Beat{
+			ObjectMeta: metav1.ObjectMeta{Name: "beat-name", Namespace: "ns", Annotations: annotations},
+			Spec: beatv1beta1.BeatSpec{
+				Type:      "filebeat",
+				Version:   "9.1.0",
+				Config:    config,
+				DaemonSet: &beatv1beta1.DaemonSetSpec{},
+			},
+		}
+	}
+	for _, tt := range []struct {
+		name         string
+		beat         beatv1beta1.Beat
+		wantDefaults bool
+	}{
+		{
+			name:         "default config",
+			beat:         beatWith(map[string]string{beatv1beta1.DefaultConfigAnnotation: "true"}, nil),
+			wantDefaults: true,
+		},
+		{
+			name: "default config not opted into",
+			beat: beatWith(nil, nil),
+		},
+		{
+			name: "user config",
+			beat: beatWith(map[string]string{beatv1beta1.DefaultConfigAnnotation: "true"}, &commonv1.Config{Data: map[string]any{"filebeat.inputs": nil}}),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			params := DriverParams{
+				Context: context.Background(),
+				Watches: watches.NewDynamicWatches(),
+				Client:  k8s.NewFakeClient(),
+				Beat:    tt.beat,
+			}
+			meta := metadata.Propagate(&params.Beat, metadata.Metadata{Labels: params.Beat.GetIdentityLabels()})
+			podTemplate, err := buildPodTemplate(params, definition, newHash(""), meta)
+			require.NoError(t, err)
+
+			var volumes []string
+			for _, v := range podTemplate.Spec.Volumes {
+				volumes = append(volumes, v.Name)
+			}
+			main := podTemplate.Spec.Containers[0]
+			var env []string
+			for _, e := range main.Env {
+				env = append(env, e.Name)
+			}
+			if !tt.wantDefaults {
+				assert.NotContains(t, volumes, "varlogcontainers")
+				assert.NotContains(t, env, "NODE_NAME")
+				assert.Nil(t, main.SecurityContext)
+				assert.False(t, *podTemplate.Spec.AutomountServiceAccountToken)
+				return
+			}
+			assert.Subset(t, volumes, []string{"varlogcontainers", "varlogpods", "varlibdockercontainers"})
+			assert.Contains(t, env, "NODE_NAME")
+			assert.Equal(t, definition.SecurityContext, main.SecurityContext)
+			assert.True(t, *podTemplate.Spec.AutomountServiceAccountToken)
+		})
+	}
+}
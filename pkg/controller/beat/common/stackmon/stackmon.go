@@ -28,6 +28,9 @@ const (
 
 	MetricbeatLogsVolumeName      string = "metricbeat-logs"
 	MetricbeatLogsVolumeMountPath string = "/usr/share/metricbeat/logs"
+
+	SharedDataVolumeName      string = "shared-data"
+	SharedDataVolumeMountPath string = "/var/shared"
 )
 
 var (
@@ -58,14 +61,15 @@ func Filebeat(ctx context.Context, client k8s.Client, resource monitoring.HasMon
 	return sidecar, nil
 }
 
-func MetricBeat(ctx context.Context, client k8s.Client, beat *v1beta1.Beat, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+// metricbeatConfig renders the Metricbeat configuration to collect monitoring data from the Beat through its Unix socket.
+func metricbeatConfig(ctx context.Context, client k8s.Client, beat *v1beta1.Beat) (string, error) {
 	if err := beat.ElasticsearchRef().IsValid(); err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", err
 	}
 
 	uuid, err := associatedESUUID(ctx, client, beat)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", err
 	}
 
 	data := struct {
@@ -78,10 +82,18 @@ func MetricBeat(ctx context.Context, client k8s.Client, beat *v1beta1.Beat, meta
 		URL: fmt.Sprintf("http+%s", GetStackMonitoringSocketURL(beat)),
 	}
 	v, err := version.Parse(beat.Spec.Version)
+	if err != nil {
+		return "", err
+	}
+	return stackmon.RenderTemplate(v, metricbeatConfigTemplate, data)
+}
+
+func MetricBeat(ctx context.Context, client k8s.Client, beat *v1beta1.Beat, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	cfg, err := metricbeatConfig(ctx, client, beat)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
-	cfg, err := stackmon.RenderTemplate(v, metricbeatConfigTemplate, data)
+	v, err := version.Parse(beat.Spec.Version)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
@@ -92,7 +104,7 @@ func MetricBeat(ctx context.Context, client k8s.Client, beat *v1beta1.Beat, meta
 	}
 
 	// Add shared volume for Unix socket between containers.
-	sharedDataVolume := volume.NewEmptyDirVolume("shared-data", "/var/shared")
+	sharedDataVolume := volume.NewEmptyDirVolume(SharedDataVolumeName, SharedDataVolumeMountPath)
 	sidecar.Container.VolumeMounts = append(sidecar.Container.VolumeMounts, sharedDataVolume.VolumeMount())
 	sidecar.Volumes = append(sidecar.Volumes, sharedDataVolume.Volume())
 
@@ -104,6 +116,32 @@ func MetricBeat(ctx context.Context, client k8s.Client, beat *v1beta1.Beat, meta
 	return sidecar, nil
 }
 
+// Collector returns an EDOT collector sidecar collecting both the metrics and the logs of the Beat, in place of the
+// Metricbeat and Filebeat sidecars.
+func Collector(ctx context.Context, client k8s.Client, beat *v1beta1.Beat, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	v, err := version.Parse(beat.Spec.Version)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+
+	var metricsCfg, logsCfg string
+	var volumes []volume.VolumeLike
+	if monitoring.IsMetricsDefined(beat) {
+		metricsCfg, err = metricbeatConfig(ctx, client, beat)
+		if err != nil {
+			return stackmon.BeatSidecar{}, err
+		}
+		// shared volume for Unix socket between containers
+		volumes = append(volumes, volume.NewEmptyDirVolume(SharedDataVolumeName, SharedDataVolumeMountPath))
+	}
+	if monitoring.IsLogsDefined(beat) {
+		logsCfg = filebeatConfig
+		// shared volume for logs consumption
+		volumes = append(volumes, volume.NewEmptyDirVolume(FilebeatLogsVolumeName, FilebeatLogsVolumeMountPath))
+	}
+	return stackmon.NewCollectorSidecar(ctx, client, beat, v, metricsCfg, logsCfg, meta, volumes...)
+}
+
 type clusterUUIDResponse struct {
 	ClusterUUID string `json:"cluster_uuid"`
 }
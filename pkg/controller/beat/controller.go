@@ -21,14 +21,7 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/auditbeat"
 	beatcommon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/filebeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/heartbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/journalbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/metricbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/otherbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/packetbeat"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/keystore"
@@ -48,7 +41,7 @@ const (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, r, params)
+	c, err := common.NewController(mgr, controllerName, r, params, &beatv1beta1.Beat{})
 	if err != nil {
 		return err
 	}
@@ -222,22 +215,7 @@ func newDriver(
 		Beat:          beat,
 	}
 
-	switch beat.Spec.Type {
-	case string(filebeat.Type):
-		return filebeat.NewDriver(dp)
-	case string(metricbeat.Type):
-		return metricbeat.NewDriver(dp)
-	case string(heartbeat.Type):
-		return heartbeat.NewDriver(dp)
-	case string(auditbeat.Type):
-		return auditbeat.NewDriver(dp)
-	case string(journalbeat.Type):
-		return journalbeat.NewDriver(dp)
-	case string(packetbeat.Type):
-		return packetbeat.NewDriver(dp)
-	default:
-		return otherbeat.NewDriver(dp)
-	}
+	return beatcommon.NewDriver(dp)
 }
 
 // newStatus will generate a new status, ensuring status.ObservedGeneration
@@ -0,0 +1,134 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/set"
+)
+
+var beatlog = ulog.Log.WithName("beat-validation")
+
+// RegisterWebhook will register the Beat validating webhook. On top of the validations of the Beat API, Beats are
+// validated against the definition of their type in the beattype registry.
+func RegisterWebhook(mgr ctrl.Manager, licenseChecker license.Checker, referenceValidator *commonwebhook.ReferenceValidator, managedNamespaces []string) {
+	beat := &beatv1beta1.Beat{}
+	namespaces := set.Make(managedNamespaces...)
+	wh := &validatingWebhook{
+		decoder:           admission.NewDecoder(mgr.GetScheme()),
+		api:               commonwebhook.ValidatingWebhookFor(mgr.GetScheme(), beat, licenseChecker, referenceValidator, namespaces),
+		managedNamespaces: namespaces,
+	}
+	beatlog.Info("Registering Beat validating webhook", "path", beat.WebhookPath())
+	mgr.GetWebhookServer().Register(beat.WebhookPath(), &webhook.Admission{Handler: wh})
+}
+
+type validatingWebhook struct {
+	decoder admission.Decoder
+	// api validates Beats against the Beat API.
+	api               admission.Handler
+	managedNamespaces set.StringSet
+}
+
+// Handle is called when any request is sent to the webhook, satisfying the admission.Handler interface.
+func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request) admission.Response {
+	resp := wh.api.Handle(ctx, req)
+	if !resp.Allowed || (req.Operation != admissionv1.Create && req.Operation != admissionv1.Update) {
+		return resp
+	}
+
+	beat := &beatv1beta1.Beat{}
+	if err := wh.decoder.DecodeRaw(req.Object, beat); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if wh.managedNamespaces.Count() > 0 && !wh.managedNamespaces.Has(beat.Namespace) {
+		return resp
+	}
+
+	if errs := append(checkTypeVersion(beat), checkImageCatalogVersion(beat)...); len(errs) > 0 {
+		err := apierrors.NewInvalid(schema.GroupKind{Group: beatv1beta1.GroupVersion.Group, Kind: beatv1beta1.Kind}, beat.Name, errs)
+		return commonwebhook.DenyResponseFromStatus(err.Status()).WithWarnings(resp.Warnings...)
+	}
+	if warning := checkDefaultConfigPermissions(beat); warning != "" {
+		resp.Warnings = append(resp.Warnings, warning)
+	}
+	return resp
+}
+
+func checkTypeVersion(b *beatv1beta1.Beat) field.ErrorList {
+	def, ok := beattype.Get(b.Spec.Type)
+	if !ok {
+		return nil
+	}
+	v, err := version.Parse(b.Spec.Version)
+	if err != nil {
+		// already reported by the validation of the Beat API
+		return nil
+	}
+	if v.LT(def.MinVersion) {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec").Child("version"),
+				b.Spec.Version,
+				fmt.Sprintf("%s requires version %s or later", b.Spec.Type, version.WithoutPre(def.MinVersion))),
+		}
+	}
+	return nil
+}
+
+// checkImageCatalogVersion checks the version against the image catalog for the Beat types with a default image.
+func checkImageCatalogVersion(b *beatv1beta1.Beat) field.ErrorList {
+	def, ok := beattype.Get(b.Spec.Type)
+	if !ok || def.Image == "" {
+		return nil
+	}
+	return commonv1.CheckImageCatalogVersion(b.Spec.Version, def.Image, b.Spec.Image)
+}
+
+// checkDefaultConfigPermissions returns a warning if the default configuration of the Beat type requires access to
+// the Kubernetes API while no service account is specified in the pod template.
+func checkDefaultConfigPermissions(b *beatv1beta1.Beat) string {
+	def, ok := beattype.Get(b.Spec.Type)
+	if !ok || len(def.PolicyRules) == 0 || !beattype.UsesDefaultConfig(*b) {
+		return ""
+	}
+	var podTemplate corev1.PodTemplateSpec
+	switch {
+	case b.Spec.DaemonSet != nil:
+		podTemplate = b.Spec.DaemonSet.PodTemplate
+	case b.Spec.Deployment != nil:
+		podTemplate = b.Spec.Deployment.PodTemplate
+	}
+	if podTemplate.Spec.ServiceAccountName != "" {
+		return ""
+	}
+	rules := make([]string, 0, len(def.PolicyRules))
+	for _, rule := range def.PolicyRules {
+		rules = append(rules, fmt.Sprintf("%s on %s", strings.Join(rule.Verbs, "/"), strings.Join(rule.Resources, ", ")))
+	}
+	return fmt.Sprintf(
+		"The default %s configuration requires a service account allowed to %s, but no serviceAccountName is specified in the pod template",
+		b.Spec.Type, strings.Join(rules, "; "))
+}
@@ -0,0 +1,139 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/set"
+)
+
+func asJSON(obj any) []byte {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+func Test_validatingWebhook_Handle(t *testing.T) {
+	catalog, err := container.ParseImageCatalog([]byte(`
+images:
+- image: beats/metricbeat
+  version: 8.15.0
+  digest: sha256:` + strings.Repeat("a", 64)))
+	require.NoError(t, err)
+	container.SetImageCatalog(catalog)
+	defer container.SetImageCatalog(nil)
+
+	optedIn := map[string]string{beatv1beta1.DefaultConfigAnnotation: "true"}
+	for _, tt := range []struct {
+		name         string
+		beat         beatv1beta1.Beat
+		wantAllowed  bool
+		wantMessage  string
+		wantWarnings []string
+	}{
+		{
+			name:        "valid Beat",
+			beat:        beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Type: "filebeat", Version: "8.15.0", DaemonSet: &beatv1beta1.DaemonSetSpec{}}},
+			wantAllowed: true,
+		},
+		{
+			name:        "invalid Beat API",
+			beat:        beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Type: "apachebeat", Version: "8.15.0"}},
+			wantMessage: "Image is required if Beat type is not one of [filebeat, metricbeat, heartbeat, auditbeat, journalbeat, packetbeat]",
+		},
+		{
+			name: "version not supported by the Beat type",
+			beat: beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{
+				Type: "osquerybeat", Image: "osquerybeat:7.12.0", Version: "7.12.0", DaemonSet: &beatv1beta1.DaemonSetSpec{},
+			}},
+			wantMessage:  "osquerybeat requires version 7.13.0 or later",
+			wantWarnings: []string{"Version 7.12.0 is EOL and support for it will be removed in a future release of the ECK operator"},
+		},
+		{
+			name:        "version missing from the image catalog",
+			beat:        beatv1beta1.Beat{Spec: beatv1beta1.BeatSpec{Type: "metricbeat", Version: "8.16.0", DaemonSet: &beatv1beta1.DaemonSetSpec{}}},
+			wantMessage: "version 8.16.0 of image beats/metricbeat is not in the image catalog",
+		},
+		{
+			name: "default config without service account",
+			beat: beatv1beta1.Beat{
+				ObjectMeta: metav1.ObjectMeta{Annotations: optedIn},
+				Spec:       beatv1beta1.BeatSpec{Type: "heartbeat", Version: "8.15.0", Deployment: &beatv1beta1.DeploymentSpec{}},
+			},
+			wantAllowed: true,
+			wantWarnings: []string{
+				"The default heartbeat configuration requires a service account allowed to get/list/watch on namespaces, nodes, pods, services, " +
+					"but no serviceAccountName is specified in the pod template",
+			},
+		},
+		{
+			name: "default config with service account",
+			beat: beatv1beta1.Beat{
+				ObjectMeta: metav1.ObjectMeta{Annotations: optedIn},
+				Spec: beatv1beta1.BeatSpec{Type: "heartbeat", Version: "8.15.0", Deployment: &beatv1beta1.DeploymentSpec{
+					PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{ServiceAccountName: "heartbeat"}},
+				}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "default config not opted into",
+			beat: beatv1beta1.Beat{
+				Spec: beatv1beta1.BeatSpec{Type: "heartbeat", Version: "8.15.0", Deployment: &beatv1beta1.DeploymentSpec{}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "user config",
+			beat: beatv1beta1.Beat{
+				ObjectMeta: metav1.ObjectMeta{Annotations: optedIn},
+				Spec: beatv1beta1.BeatSpec{
+					Type: "heartbeat", Version: "8.15.0", Deployment: &beatv1beta1.DeploymentSpec{},
+					Config: &commonv1.Config{Data: map[string]any{"heartbeat.monitors": nil}},
+				},
+			},
+			wantAllowed: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.beat.Name = "beat"
+			tt.beat.Namespace = "ns"
+			wh := &validatingWebhook{
+				decoder:           admission.NewDecoder(k8s.Scheme()),
+				api:               commonwebhook.ValidatingWebhookFor(k8s.Scheme(), &beatv1beta1.Beat{}, license.MockLicenseChecker{}, nil, set.Make()),
+				managedNamespaces: set.Make(),
+			}
+			resp := wh.Handle(context.Background(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: asJSON(&tt.beat)},
+			}})
+			require.Equal(t, tt.wantAllowed, resp.Allowed, resp.Result)
+			if tt.wantMessage != "" {
+				assert.Contains(t, resp.Result.Message, tt.wantMessage)
+			}
+			assert.Equal(t, tt.wantWarnings, resp.Warnings)
+		})
+	}
+}
@@ -202,11 +202,27 @@ func ValidateAutoscalingPolicies(
 
 		// Validate storage
 		errs = validateQuantities(errs, autoscalingSpecPath, autoscalingSpec.StorageRange, i, "storage", minStorage)
+
+		// Validate the recommender
+		errs = append(errs, validateRecommender(autoscalingSpecPath, autoscalingSpec, i)...)
 	}
 
 	return errs
 }
 
+// validateRecommender ensures that the metrics recommender settings are consistent with the recommender of the policy.
+func validateRecommender(autoscalingSpecPath SpecPathBuilder, autoscalingSpec v1alpha1.AutoscalingPolicySpec, index int) field.ErrorList {
+	var errs field.ErrorList
+	if autoscalingSpec.Metrics != nil && !autoscalingSpec.UsesMetricsRecommender() {
+		errs = append(errs, field.Forbidden(autoscalingSpecPath(index, "metrics"), "metrics settings can only be used with the metrics recommender"))
+	}
+	// ML nodes are sized according to the jobs to be run, which is only known from the Elasticsearch ML decider.
+	if autoscalingSpec.UsesMetricsRecommender() && stringsutil.StringInSlice(string(esv1.MLRole), autoscalingSpec.Roles) {
+		errs = append(errs, field.Invalid(autoscalingSpecPath(index, "recommender"), autoscalingSpec.Recommender, "ML nodes cannot be managed by the metrics recommender"))
+	}
+	return errs
+}
+
 // ignoreRemoteClusterClientRole will ignore the 'remote_cluster_client' role in a given slice of roles.
 func ignoreRemoteClusterClientRole(roles []string) []string {
 	var updatedRoles []string
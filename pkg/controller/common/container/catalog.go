@@ -0,0 +1,177 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package container
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+var (
+	imageCatalog *ImageCatalog
+
+	digestRegexp = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// ImageCatalog pins the Elastic Stack images to digests for each version, and overrides the container registry and
+// the image pull secrets of the Pods per namespace. It is loaded from the file given with the --image-catalog flag.
+type ImageCatalog struct {
+	// Images maps the version of an image to its digest.
+	Images []CatalogImage `json:"images,omitempty"`
+	// Namespaces overrides the container registry and the image pull secrets for the Pods of a namespace.
+	Namespaces []NamespaceOverride `json:"namespaces,omitempty"`
+
+	digests   map[imageVariantKey]map[string]string
+	overrides map[string]NamespaceOverride
+}
+
+// imageVariantKey identifies a variant of an image.
+type imageVariantKey struct {
+	image  Image
+	suffix string
+}
+
+// CatalogImage is the digest of an image for a given version.
+type CatalogImage struct {
+	// Image is the image path without registry, for example elasticsearch/elasticsearch.
+	Image Image `json:"image"`
+	// Suffix is the suffix of the image variant, for example -ubi, empty for the default variant.
+	Suffix string `json:"suffix,omitempty"`
+	// Version is the version of the Elastic Stack application.
+	Version string `json:"version"`
+	// Digest is the digest of the image, for example sha256:5f2d...
+	Digest string `json:"digest"`
+}
+
+// NamespaceOverride overrides the container registry and the image pull secrets for the Pods of a namespace.
+type NamespaceOverride struct {
+	Namespace string `json:"namespace"`
+	// Registry replaces the registry set with --container-registry.
+	Registry string `json:"registry,omitempty"`
+	// ImagePullSecrets are the names of the Secrets added to the image pull secrets of the Pods.
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty"`
+}
+
+// LoadImageCatalog reads and validates the image catalog from the given file.
+func LoadImageCatalog(path string) (*ImageCatalog, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading image catalog %s: %w", path, err)
+	}
+	return ParseImageCatalog(bytes)
+}
+
+// ParseImageCatalog parses and validates the given YAML image catalog.
+func ParseImageCatalog(bytes []byte) (*ImageCatalog, error) {
+	var catalog ImageCatalog
+	if err := yaml.Unmarshal(bytes, &catalog); err != nil {
+		return nil, fmt.Errorf("while parsing image catalog: %w", err)
+	}
+
+	catalog.digests = make(map[imageVariantKey]map[string]string)
+	for _, img := range catalog.Images {
+		if img.Image == "" {
+			return nil, errors.New("image catalog: image is required")
+		}
+		name := string(img.Image) + img.Suffix
+		v, err := version.Parse(img.Version)
+		if err != nil {
+			return nil, fmt.Errorf("image catalog: invalid version %q for image %s: %w", img.Version, name, err)
+		}
+		if !digestRegexp.MatchString(img.Digest) {
+			return nil, fmt.Errorf("image catalog: invalid digest %q for image %s:%s", img.Digest, name, img.Version)
+		}
+		key := imageVariantKey{image: img.Image, suffix: img.Suffix}
+		if _, exists := catalog.digests[key][v.String()]; exists {
+			return nil, fmt.Errorf("image catalog: duplicate entry for image %s:%s", name, img.Version)
+		}
+		if catalog.digests[key] == nil {
+			catalog.digests[key] = make(map[string]string)
+		}
+		catalog.digests[key][v.String()] = img.Digest
+	}
+
+	catalog.overrides = make(map[string]NamespaceOverride, len(catalog.Namespaces))
+	for _, override := range catalog.Namespaces {
+		if override.Namespace == "" {
+			return nil, errors.New("image catalog: namespace is required")
+		}
+		if _, exists := catalog.overrides[override.Namespace]; exists {
+			return nil, fmt.Errorf("image catalog: duplicate entry for namespace %s", override.Namespace)
+		}
+		catalog.overrides[override.Namespace] = override
+	}
+	return &catalog, nil
+}
+
+// SetImageCatalog sets the global image catalog used to resolve the Elastic Stack images.
+func SetImageCatalog(catalog *ImageCatalog) {
+	imageCatalog = catalog
+}
+
+// digest returns the digest of the given image variant and version, if any.
+func (c *ImageCatalog) digest(img Image, suffix string, ver version.Version) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	digest, exists := c.digests[imageVariantKey{image: img, suffix: suffix}][ver.String()]
+	return digest, exists
+}
+
+// CheckCatalogVersion returns an error if the global image catalog pins the variant of the given image used by the
+// operator to digests, but not for the given version. Images absent from the catalog are not restricted.
+func CheckCatalogVersion(img Image, ver version.Version) error {
+	suffix := imageVariant(img, ver)
+	if imageCatalog == nil || len(imageCatalog.digests[imageVariantKey{image: img, suffix: suffix}]) == 0 {
+		return nil
+	}
+	if _, exists := imageCatalog.digest(img, suffix, imageTagVersion(img, ver)); !exists {
+		return fmt.Errorf("version %s of image %s%s is not in the image catalog", ver, img, suffix)
+	}
+	return nil
+}
+
+// ImagePullSecrets returns the image pull secrets the global image catalog adds to the Pods of the given namespace.
+func ImagePullSecrets(namespace string) []corev1.LocalObjectReference {
+	if imageCatalog == nil {
+		return nil
+	}
+	names := imageCatalog.overrides[namespace].ImagePullSecrets
+	if len(names) == 0 {
+		return nil
+	}
+	secrets := make([]corev1.LocalObjectReference, len(names))
+	for i, name := range names {
+		secrets[i] = corev1.LocalObjectReference{Name: name}
+	}
+	return secrets
+}
+
+// applyCatalog replaces the registry of the given image reference with the registry of the namespace, and its tag
+// with the digest of the image variant with the given suffix for the given tag version, according to the global image
+// catalog.
+func applyCatalog(ref string, namespace string, img Image, suffix string, ver version.Version) string {
+	if imageCatalog == nil {
+		return ref
+	}
+	if registry := imageCatalog.overrides[namespace].Registry; registry != "" {
+		ref = registry + strings.TrimPrefix(ref, containerRegistry)
+	}
+	if digest, exists := imageCatalog.digest(img, suffix, ver); exists {
+		if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+			ref = ref[:i]
+		}
+		ref += "@" + digest
+	}
+	return ref
+}
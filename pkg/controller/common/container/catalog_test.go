@@ -0,0 +1,229 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package container
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+var (
+	testDigest      = "sha256:" + strings.Repeat("a", 64)
+	testOtherDigest = "sha256:" + strings.Repeat("b", 64)
+)
+
+func TestParseImageCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		catalog string
+		wantErr string
+	}{
+		{
+			name: "valid catalog",
+			catalog: `
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: ` + testDigest + `
+- image: elasticsearch/elasticsearch
+  version: 8.16.0
+  digest: ` + testOtherDigest + `
+namespaces:
+- namespace: team-a
+  registry: mirror.internal:5000
+  imagePullSecrets: [mirror-credentials]
+`,
+		},
+		{
+			name:    "empty catalog",
+			catalog: ``,
+		},
+		{
+			name: "invalid version",
+			catalog: `
+images:
+- image: elasticsearch/elasticsearch
+  version: latest
+  digest: ` + testDigest,
+			wantErr: `invalid version "latest"`,
+		},
+		{
+			name: "invalid digest",
+			catalog: `
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: sha256:abc`,
+			wantErr: `invalid digest "sha256:abc"`,
+		},
+		{
+			name: "duplicate image version",
+			catalog: `
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: ` + testDigest + `
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: ` + testOtherDigest,
+			wantErr: "duplicate entry for image elasticsearch/elasticsearch:8.15.0",
+		},
+		{
+			name: "duplicate namespace",
+			catalog: `
+namespaces:
+- namespace: team-a
+  registry: mirror.internal
+- namespace: team-a
+  imagePullSecrets: [mirror-credentials]`,
+			wantErr: "duplicate entry for namespace team-a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseImageCatalog([]byte(tt.catalog))
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func setTestImageCatalog(t *testing.T, catalog string) {
+	t.Helper()
+	c, err := ParseImageCatalog([]byte(catalog))
+	require.NoError(t, err)
+	SetImageCatalog(c)
+	t.Cleanup(func() { SetImageCatalog(nil) })
+}
+
+func TestImageRepository_ImageCatalog(t *testing.T) {
+	setTestImageCatalog(t, `
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: `+testDigest+`
+- image: package-registry/distribution
+  version: 9.2.2
+  digest: `+testOtherDigest+`
+namespaces:
+- namespace: team-a
+  registry: mirror.internal:5000
+`)
+
+	tests := []struct {
+		name      string
+		namespace string
+		image     Image
+		version   string
+		want      string
+	}{
+		{
+			name:    "digest pinned image",
+			image:   ElasticsearchImage,
+			version: "8.15.0",
+			want:    "docker.elastic.co/elasticsearch/elasticsearch@" + testDigest,
+		},
+		{
+			name:      "digest pinned image with namespace registry",
+			namespace: "team-a",
+			image:     ElasticsearchImage,
+			version:   "8.15.0",
+			want:      "mirror.internal:5000/elasticsearch/elasticsearch@" + testDigest,
+		},
+		{
+			name:      "version not in the catalog with namespace registry",
+			namespace: "team-a",
+			image:     ElasticsearchImage,
+			version:   "8.16.0",
+			want:      "mirror.internal:5000/elasticsearch/elasticsearch:8.16.0",
+		},
+		{
+			name:    "image not in the catalog",
+			image:   KibanaImage,
+			version: "8.15.0",
+			want:    "docker.elastic.co/kibana/kibana:8.15.0",
+		},
+		{
+			name:    "digest pinned package registry image",
+			image:   PackageRegistryImage,
+			version: "9.2.2",
+			want:    "docker.elastic.co/package-registry/distribution@" + testOtherDigest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ImageRepository(tt.namespace, tt.image, version.MustParse(tt.version)))
+		})
+	}
+}
+
+func TestImageRepository_ImageCatalogVariants(t *testing.T) {
+	setTestImageCatalog(t, `
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: `+testDigest+`
+- image: elasticsearch/elasticsearch
+  suffix: -ubi
+  version: 8.16.0
+  digest: `+testOtherDigest+`
+- image: package-registry/distribution
+  version: 8.15.1
+  digest: `+testDigest+`
+`)
+	SetContainerSuffix(UBISuffix)
+	t.Cleanup(func() { SetContainerSuffix("") })
+
+	// the digest of the default variant is not pinned to the UBI variant
+	assert.Equal(t, "docker.elastic.co/elasticsearch/elasticsearch-ubi:8.15.0", ImageRepository("", ElasticsearchImage, version.MustParse("8.15.0")))
+	assert.Equal(t, "docker.elastic.co/elasticsearch/elasticsearch-ubi@"+testOtherDigest, ImageRepository("", ElasticsearchImage, version.MustParse("8.16.0")))
+	require.NoError(t, CheckCatalogVersion(ElasticsearchImage, version.MustParse("8.16.0")))
+	require.EqualError(t, CheckCatalogVersion(ElasticsearchImage, version.MustParse("8.15.0")),
+		"version 8.15.0 of image elasticsearch/elasticsearch-ubi is not in the image catalog")
+	// the UBI variant of the package registry is not in the catalog
+	require.NoError(t, CheckCatalogVersion(PackageRegistryImage, version.MustParse("8.15.1")))
+
+	// the package registry digest is looked up with the version of the image actually used
+	SetContainerSuffix("")
+	assert.Equal(t, "docker.elastic.co/package-registry/distribution@"+testDigest, ImageRepository("", PackageRegistryImage, version.MustParse("8.14.0")))
+	require.NoError(t, CheckCatalogVersion(PackageRegistryImage, version.MustParse("8.14.0")))
+}
+
+func TestCheckCatalogVersion(t *testing.T) {
+	require.NoError(t, CheckCatalogVersion(ElasticsearchImage, version.MustParse("8.16.0")), "no catalog")
+
+	setTestImageCatalog(t, `
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: `+testDigest)
+
+	require.NoError(t, CheckCatalogVersion(ElasticsearchImage, version.MustParse("8.15.0")))
+	require.EqualError(t, CheckCatalogVersion(ElasticsearchImage, version.MustParse("8.16.0")),
+		"version 8.16.0 of image elasticsearch/elasticsearch is not in the image catalog")
+	require.NoError(t, CheckCatalogVersion(KibanaImage, version.MustParse("8.16.0")), "image not in the catalog")
+}
+
+func TestImagePullSecrets(t *testing.T) {
+	require.Nil(t, ImagePullSecrets("team-a"), "no catalog")
+
+	setTestImageCatalog(t, `
+namespaces:
+- namespace: team-a
+  imagePullSecrets: [mirror-credentials, audit-credentials]
+`)
+
+	require.Equal(t, []corev1.LocalObjectReference{{Name: "mirror-credentials"}, {Name: "audit-credentials"}}, ImagePullSecrets("team-a"))
+	require.Nil(t, ImagePullSecrets("team-b"))
+}
@@ -67,6 +67,7 @@ const (
 	MapsImage             Image = "elastic-maps-service/elastic-maps-server"
 	LogstashImage         Image = "logstash/logstash"
 	AutoOpsAgentImage     Image = "elastic-agent/elastic-otel-collector-wolfi"
+	EDOTCollectorImage    Image = "elastic-agent/elastic-otel-collector-wolfi"
 	PackageRegistryImage  Image = "package-registry/distribution"
 )
 
@@ -82,7 +83,12 @@ func AgentImageFor(version version.Version) Image {
 // ImageRepository returns the full container image name by concatenating the current container registry and the image path with the given version.
 // A UBI suffix (-ubi8 or -ubi suffix depending on the version) is appended to the image name for the maps image,
 // or any image if the operator is configured with --ubi-only.
-func ImageRepository(img Image, ver version.Version) string {
+// The registry is overridden for the given namespace and the tag is replaced with a digest according to the image catalog.
+func ImageRepository(namespace string, img Image, ver version.Version) string {
+	return applyCatalog(imageRepository(img, ver), namespace, img, imageVariant(img, ver), imageTagVersion(img, ver))
+}
+
+func imageRepository(img Image, ver version.Version) string {
 	// replace repository if defined
 	image := img
 
@@ -90,8 +96,26 @@ func ImageRepository(img Image, ver version.Version) string {
 		image = Image(fmt.Sprintf("%s/%s", containerRepository, img.Name()))
 	}
 
-	suffix := ""
+	suffix := imageVariant(img, ver)
+	if img == PackageRegistryImage {
+		return getPackageRegistryImage(containerSuffix == UBISuffix, suffix, ver)
+	}
+
+	return fmt.Sprintf("%s/%s%s:%s", containerRegistry, image, suffix, ver)
+}
+
+// imageVariant returns the suffix of the variant of the given image used for the given version, for example -ubi.
+func imageVariant(img Image, ver version.Version) string {
 	useUBISuffix := containerSuffix == UBISuffix
+	if img == PackageRegistryImage {
+		// the UBI suffix of the package registry goes in the tag
+		if useUBISuffix {
+			return UBISuffix
+		}
+		return containerSuffix
+	}
+
+	suffix := ""
 	// use an UBI suffix for maps server image or any image in UBI mode
 	if useUBISuffix || isOlderMapsServerImg(img, ver) {
 		suffix = getUBISuffix(ver)
@@ -104,12 +128,16 @@ func ImageRepository(img Image, ver version.Version) string {
 	if !useUBISuffix {
 		suffix += containerSuffix
 	}
+	return suffix
+}
 
+// imageTagVersion returns the version in the tag of the given image used for the given version, which differs for the
+// package registry versions without a matching image.
+func imageTagVersion(img Image, ver version.Version) version.Version {
 	if img == PackageRegistryImage {
-		return getPackageRegistryImage(useUBISuffix, suffix, ver)
+		return packageRegistryVersion(containerSuffix == UBISuffix, ver)
 	}
-
-	return fmt.Sprintf("%s/%s%s:%s", containerRegistry, image, suffix, ver)
+	return ver
 }
 
 // isOderMapsServerImg returns true if the given image is a Maps server image and
@@ -134,12 +162,21 @@ func getUBISuffix(ver version.Version) string {
 // Package Registry uses by default the 'lite' image variant. Unlike other stack component
 // images, UBI suffix goes in the tag (lite-X.Y.Z-ubi) and not at the end of the image name.
 func getPackageRegistryImage(useUBI bool, suffix string, v version.Version) string {
+	v = packageRegistryVersion(useUBI, v)
+	if !useUBI {
+		return fmt.Sprintf("%s/%s%s:lite-%s", containerRegistry, PackageRegistryImage, suffix, v)
+	}
+	return fmt.Sprintf("%s/%s:lite-%s%s", containerRegistry, PackageRegistryImage, v, UBISuffix)
+}
+
+// packageRegistryVersion returns the version of the Package Registry image to use for the given version.
+func packageRegistryVersion(useUBI bool, v version.Version) version.Version {
 	if !useUBI {
 		// Before 8.15.1, the package registry image didn't have the 'lite' variant thus fallback always to 8.15.1, this is backwards compatible.
 		if v.LT(version.From(8, 15, 1)) {
-			return fmt.Sprintf("%s/%s%s:lite-8.15.1", containerRegistry, PackageRegistryImage, suffix)
+			return version.From(8, 15, 1)
 		}
-		return fmt.Sprintf("%s/%s%s:lite-%s", containerRegistry, PackageRegistryImage, suffix, v)
+		return v
 	}
 
 	// UBI-based images for package-registry were introduced in https://github.com/elastic/package-registry/pull/1451
@@ -148,15 +185,15 @@ func getPackageRegistryImage(useUBI bool, suffix string, v version.Version) stri
 	switch {
 	case v.LT(version.From(8, 19, 8)):
 		// Fallback to 8.19.8-ubi for all versions below 8.19.8
-		return fmt.Sprintf("%s/%s:lite-8.19.8-ubi", containerRegistry, PackageRegistryImage)
+		return version.From(8, 19, 8)
 	case v.Major == 9 && v.Minor <= 1 && v.LT(version.From(9, 1, 8)):
 		// Fallback to 9.1.8-ubi for 9.0.x and 9.1.x versions below 9.1.8
-		return fmt.Sprintf("%s/%s:lite-9.1.8-ubi", containerRegistry, PackageRegistryImage)
+		return version.From(9, 1, 8)
 	case v.Major == 9 && v.Minor > 1 && v.LT(version.From(9, 2, 2)):
 		// Fallback to 9.2.2-ubi for 9.2.x versions below 9.2.2
-		return fmt.Sprintf("%s/%s:lite-9.2.2-ubi", containerRegistry, PackageRegistryImage)
+		return version.From(9, 2, 2)
 	default:
 		// Use the requested version for all other cases (>= 9.2.2 or >= 8.19.8 non-9.x)
-		return fmt.Sprintf("%s/%s:lite-%s-ubi", containerRegistry, PackageRegistryImage, v)
+		return v
 	}
 }
@@ -218,7 +218,7 @@ func TestImageRepository(t *testing.T) {
 			SetContainerRepository(tc.repository)
 			SetContainerSuffix(tc.suffix)
 
-			have := ImageRepository(tc.image, version.MustParse(tc.version))
+			have := ImageRepository("", tc.image, version.MustParse(tc.version))
 			assert.Equal(t, tc.want, have)
 		})
 	}
@@ -11,6 +11,8 @@ import (
 
 	"go.elastic.co/apm/module/apmzap/v2"
 	"go.elastic.co/apm/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -21,7 +23,31 @@ import (
 )
 
 // NewController creates a new controller with the given name, reconciler and parameters and registers it with the manager.
-func NewController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters) (controller.Controller, error) {
+// If reconciliations are sharded, the controller runs on all the operator replicas, each reconciling the resources of
+// the shards it owns. forType is the kind of the resources reconciled by the controller.
+func NewController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters, forType client.Object) (controller.Controller, error) {
+	if p.Sharding == nil {
+		return NewLeaderController(mgr, name, r, p)
+	}
+	filter := p.Sharding.NewFilter(r, mgr.GetCache(), forType)
+	c, err := controller.New(name, mgr, controller.Options{
+		Reconciler:              filter,
+		MaxConcurrentReconciles: p.MaxConcurrentReconciles,
+		NeedLeaderElection:      ptr.To(false),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// reconcile the resources of the shards handed over by other replicas, including the deletions that happened meanwhile
+	if err := c.Watch(filter.Source()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewLeaderController creates a new controller with the given name, reconciler and parameters and registers it with
+// the manager. The controller only runs on the elected operator replica, even if reconciliations are sharded.
+func NewLeaderController(mgr manager.Manager, name string, r reconcile.Reconciler, p operator.Parameters) (controller.Controller, error) {
 	return controller.New(name, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: p.MaxConcurrentReconciles})
 }
 
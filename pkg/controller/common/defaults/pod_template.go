@@ -117,6 +117,16 @@ func (b *PodTemplateBuilder) WithDockerImage(customImage string, defaultImage st
 	return b
 }
 
+// WithImagePullSecrets appends the given image pull secrets, unless already provided in the template.
+func (b *PodTemplateBuilder) WithImagePullSecrets(secrets ...corev1.LocalObjectReference) *PodTemplateBuilder {
+	for _, secret := range secrets {
+		if !slices.Contains(b.PodTemplate.Spec.ImagePullSecrets, secret) {
+			b.PodTemplate.Spec.ImagePullSecrets = append(b.PodTemplate.Spec.ImagePullSecrets, secret)
+		}
+	}
+	return b
+}
+
 // WithReadinessProbe sets up the given readiness probe, unless already provided in the template.
 func (b *PodTemplateBuilder) WithReadinessProbe(readinessProbe corev1.Probe) *PodTemplateBuilder {
 	b.containerDefaulter.WithReadinessProbe(&readinessProbe)
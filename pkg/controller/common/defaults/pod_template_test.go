@@ -204,6 +204,41 @@ func TestPodTemplateBuilder_WithDockerImage(t *testing.T) {
 	}
 }
 
+func TestPodTemplateBuilder_WithImagePullSecrets(t *testing.T) {
+	tests := []struct {
+		name        string
+		podTemplate corev1.PodTemplateSpec
+		secrets     []corev1.LocalObjectReference
+		want        []corev1.LocalObjectReference
+	}{
+		{
+			name:        "no secrets",
+			podTemplate: corev1.PodTemplateSpec{},
+			want:        nil,
+		},
+		{
+			name:        "append secrets",
+			podTemplate: corev1.PodTemplateSpec{},
+			secrets:     []corev1.LocalObjectReference{{Name: "a"}, {Name: "b"}},
+			want:        []corev1.LocalObjectReference{{Name: "a"}, {Name: "b"}},
+		},
+		{
+			name: "do not duplicate secrets provided in the podTemplate",
+			podTemplate: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{{Name: "user"}, {Name: "a"}}},
+			},
+			secrets: []corev1.LocalObjectReference{{Name: "a"}, {Name: "b"}},
+			want:    []corev1.LocalObjectReference{{Name: "user"}, {Name: "a"}, {Name: "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewPodTemplateBuilder(tt.podTemplate, "mycontainer")
+			require.Equal(t, tt.want, b.WithImagePullSecrets(tt.secrets...).PodTemplate.Spec.ImagePullSecrets)
+		})
+	}
+}
+
 func TestPodTemplateBuilder_WithReadinessProbe(t *testing.T) {
 	containerName := "mycontainer"
 	tests := []struct {
@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package deployment
+
+import (
+	"context"
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/hash"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	eckmaps "github.com/elastic/cloud-on-k8s/v3/pkg/utils/maps"
+)
+
+// ReconcileHorizontalPodAutoscaler ensures a HorizontalPodAutoscaler named after the given Deployment scales it according
+// to the given spec. If the spec is nil, any HorizontalPodAutoscaler previously created for the owner is deleted.
+func ReconcileHorizontalPodAutoscaler(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	d appsv1.Deployment,
+	spec *commonv1.HorizontalPodAutoscalerSpec,
+	meta metadata.Metadata,
+	owner client.Object,
+) error {
+	if spec == nil {
+		return deleteHorizontalPodAutoscaler(ctx, k8sClient, d, owner)
+	}
+
+	expected := NewHorizontalPodAutoscaler(d, *spec, meta)
+	// label the HPA with a hash of its content, for comparison purposes
+	expected.Labels = hash.SetTemplateHashLabel(expected.Labels, expected)
+
+	reconciled := &autoscalingv2.HorizontalPodAutoscaler{}
+	return reconciler.ReconcileResource(reconciler.Params{
+		Context:    ctx,
+		Client:     k8sClient,
+		Owner:      owner,
+		Expected:   &expected,
+		Reconciled: reconciled,
+		NeedsUpdate: func() bool {
+			return !eckmaps.IsSubset(expected.Labels, reconciled.Labels) ||
+				!eckmaps.IsSubset(expected.Annotations, reconciled.Annotations) ||
+				hash.GetTemplateHashLabel(expected.Labels) != hash.GetTemplateHashLabel(reconciled.Labels)
+		},
+		UpdateReconciled: func() {
+			// set expected annotations and labels, but don't remove existing ones
+			// that may have been defaulted or set by a user/admin on the existing resource
+			reconciled.Labels = eckmaps.Merge(reconciled.Labels, expected.Labels)
+			reconciled.Annotations = eckmaps.Merge(reconciled.Annotations, expected.Annotations)
+			// overwrite the spec but leave the status intact
+			reconciled.Spec = expected.Spec
+		},
+	})
+}
+
+// NewHorizontalPodAutoscaler returns the HorizontalPodAutoscaler scaling the given Deployment according to the given spec.
+func NewHorizontalPodAutoscaler(d appsv1.Deployment, spec commonv1.HorizontalPodAutoscalerSpec, meta metadata.Metadata) autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas := spec.MinReplicas
+	if minReplicas == nil {
+		minReplicas = ptr.To[int32](1)
+	}
+	metrics := spec.Metrics
+	if len(metrics) == 0 {
+		metrics = []autoscalingv2.MetricSpec{
+			{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: ptr.To(commonv1.DefaultAutoscalingCPUUtilization),
+					},
+				},
+			},
+		}
+	}
+	return autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        d.Name,
+			Namespace:   d.Namespace,
+			Labels:      maps.Clone(meta.Labels),
+			Annotations: maps.Clone(meta.Annotations),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       "Deployment",
+				Name:       d.Name,
+			},
+			MinReplicas: minReplicas,
+			MaxReplicas: spec.MaxReplicas,
+			Metrics:     metrics,
+			Behavior:    spec.Behavior,
+		},
+	}
+}
+
+// deleteHorizontalPodAutoscaler deletes the HorizontalPodAutoscaler of the given Deployment if it exists and is
+// controlled by the owner, leaving alone any HorizontalPodAutoscaler created by users.
+func deleteHorizontalPodAutoscaler(ctx context.Context, k8sClient k8s.Client, d appsv1.Deployment, owner client.Object) error {
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	err := k8sClient.Get(ctx, k8s.ExtractNamespacedName(&d), &hpa)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(&hpa, owner) {
+		return nil
+	}
+	return client.IgnoreNotFound(k8sClient.Delete(ctx, &hpa))
+}
@@ -0,0 +1,153 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package deployment
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	controllerscheme "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func TestNewHorizontalPodAutoscaler(t *testing.T) {
+	d := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "kb-kb", Namespace: "ns"}}
+	meta := metadata.Metadata{Labels: map[string]string{"a": "b"}}
+
+	tests := []struct {
+		name string
+		spec commonv1.HorizontalPodAutoscalerSpec
+		want autoscalingv2.HorizontalPodAutoscalerSpec
+	}{
+		{
+			name: "defaults",
+			spec: commonv1.HorizontalPodAutoscalerSpec{MaxReplicas: 3},
+			want: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "kb-kb"},
+				MinReplicas:    ptr.To[int32](1),
+				MaxReplicas:    3,
+				Metrics: []autoscalingv2.MetricSpec{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceCPU,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To[int32](80)},
+					},
+				}},
+			},
+		},
+		{
+			name: "user-defined",
+			spec: commonv1.HorizontalPodAutoscalerSpec{
+				MinReplicas: ptr.To[int32](2),
+				MaxReplicas: 5,
+				Metrics: []autoscalingv2.MetricSpec{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceMemory,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To[int32](60)},
+					},
+				}},
+				Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+					ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: ptr.To[int32](600)},
+				},
+			},
+			want: autoscalingv2.HorizontalPodAutoscalerSpec{
+				ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "kb-kb"},
+				MinReplicas:    ptr.To[int32](2),
+				MaxReplicas:    5,
+				Metrics: []autoscalingv2.MetricSpec{{
+					Type: autoscalingv2.ResourceMetricSourceType,
+					Resource: &autoscalingv2.ResourceMetricSource{
+						Name:   corev1.ResourceMemory,
+						Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: ptr.To[int32](60)},
+					},
+				}},
+				Behavior: &autoscalingv2.HorizontalPodAutoscalerBehavior{
+					ScaleDown: &autoscalingv2.HPAScalingRules{StabilizationWindowSeconds: ptr.To[int32](600)},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hpa := NewHorizontalPodAutoscaler(d, tt.spec, meta)
+			require.Equal(t, "kb-kb", hpa.Name)
+			require.Equal(t, "ns", hpa.Namespace)
+			require.Equal(t, "b", hpa.Labels["a"])
+			require.Equal(t, tt.want, hpa.Spec)
+		})
+	}
+}
+
+func TestReconcileHorizontalPodAutoscaler(t *testing.T) {
+	controllerscheme.SetupScheme()
+	ctx := context.Background()
+	owner := &kbv1.Kibana{ObjectMeta: metav1.ObjectMeta{Name: "kb", Namespace: "ns", UID: "kb-uid"}}
+	d := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "kb-kb", Namespace: "ns"}}
+	k8sClient := k8s.NewFakeClient(owner)
+
+	getHPA := func() (autoscalingv2.HorizontalPodAutoscaler, error) {
+		var hpa autoscalingv2.HorizontalPodAutoscaler
+		err := k8sClient.Get(ctx, k8s.ExtractNamespacedName(&d), &hpa)
+		return hpa, err
+	}
+
+	// no spec and no HPA: nothing to do
+	require.NoError(t, ReconcileHorizontalPodAutoscaler(ctx, k8sClient, d, nil, metadata.Metadata{}, owner))
+	_, err := getHPA()
+	require.True(t, apierrors.IsNotFound(err))
+
+	// the HPA should be created
+	spec := &commonv1.HorizontalPodAutoscalerSpec{MaxReplicas: 3}
+	require.NoError(t, ReconcileHorizontalPodAutoscaler(ctx, k8sClient, d, spec, metadata.Metadata{}, owner))
+	hpa, err := getHPA()
+	require.NoError(t, err)
+	require.Equal(t, int32(3), hpa.Spec.MaxReplicas)
+	require.True(t, metav1.IsControlledBy(&hpa, owner))
+
+	// the HPA should be updated
+	spec.MaxReplicas = 5
+	require.NoError(t, ReconcileHorizontalPodAutoscaler(ctx, k8sClient, d, spec, metadata.Metadata{}, owner))
+	hpa, err = getHPA()
+	require.NoError(t, err)
+	require.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+
+	// labels and annotations are merged with the existing ones
+	hpa.Labels["user-label"] = "value"
+	hpa.Annotations = map[string]string{"user-annotation": "value"}
+	require.NoError(t, k8sClient.Update(ctx, &hpa))
+	meta := metadata.Metadata{Labels: map[string]string{"label": "value"}, Annotations: map[string]string{"annotation": "value"}}
+	require.NoError(t, ReconcileHorizontalPodAutoscaler(ctx, k8sClient, d, spec, meta, owner))
+	hpa, err = getHPA()
+	require.NoError(t, err)
+	require.Equal(t, "value", hpa.Labels["user-label"])
+	require.Equal(t, "value", hpa.Labels["label"])
+	require.Equal(t, map[string]string{"user-annotation": "value", "annotation": "value"}, hpa.Annotations)
+	// the metadata of the caller is left untouched
+	require.Equal(t, map[string]string{"label": "value"}, meta.Labels)
+
+	// the HPA should be deleted once autoscaling is disabled
+	require.NoError(t, ReconcileHorizontalPodAutoscaler(ctx, k8sClient, d, nil, metadata.Metadata{}, owner))
+	_, err = getHPA()
+	require.True(t, apierrors.IsNotFound(err))
+
+	// an HPA not created by the operator should be left alone
+	userHPA := NewHorizontalPodAutoscaler(d, commonv1.HorizontalPodAutoscalerSpec{MaxReplicas: 2}, metadata.Metadata{})
+	require.NoError(t, k8sClient.Create(ctx, &userHPA))
+	require.NoError(t, ReconcileHorizontalPodAutoscaler(ctx, k8sClient, d, nil, metadata.Metadata{}, owner))
+	_, err = getHPA()
+	require.NoError(t, err)
+}
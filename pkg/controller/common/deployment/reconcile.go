@@ -29,10 +29,16 @@ type Params struct {
 	Replicas             int32
 	RevisionHistoryLimit *int32
 	Strategy             appsv1.DeploymentStrategy
+	// Autoscaled leaves the number of replicas to a HorizontalPodAutoscaler, Replicas is then ignored.
+	Autoscaled bool
 }
 
 // New creates a Deployment from the given params.
 func New(params Params) appsv1.Deployment {
+	replicas := &params.Replicas
+	if params.Autoscaled {
+		replicas = nil
+	}
 	return appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        params.Name,
@@ -46,13 +52,15 @@ func New(params Params) appsv1.Deployment {
 				MatchLabels: params.Selector,
 			},
 			Template: params.PodTemplateSpec,
-			Replicas: &params.Replicas,
+			Replicas: replicas,
 			Strategy: params.Strategy,
 		},
 	}
 }
 
 // Reconcile creates or updates the given deployment for the specified owner.
+// If the expected deployment does not specify a number of replicas, the number of replicas of the existing deployment
+// is left untouched as it is managed by a HorizontalPodAutoscaler.
 func Reconcile(
 	ctx context.Context,
 	k8sClient k8s.Client,
@@ -81,7 +89,12 @@ func Reconcile(
 			reconciled.Labels = maps.Merge(reconciled.Labels, expected.Labels)
 			reconciled.Annotations = maps.Merge(reconciled.Annotations, expected.Annotations)
 			// overwrite the spec but leave the status intact
+			replicas := reconciled.Spec.Replicas
 			reconciled.Spec = expected.Spec
+			if expected.Spec.Replicas == nil {
+				// replicas are managed by a HorizontalPodAutoscaler
+				reconciled.Spec.Replicas = replicas
+			}
 		},
 	})
 	return *reconciled, err
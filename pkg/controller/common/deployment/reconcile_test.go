@@ -101,3 +101,35 @@ func TestReconcile(t *testing.T) {
 	require.NoError(t, err)
 	comparison.RequireEqual(t, &reconciled, &retrieved)
 }
+
+func TestReconcile_Autoscaled(t *testing.T) {
+	controllerscheme.SetupScheme()
+	k8sClient := k8s.NewFakeClient()
+	params := Params{Name: "dep", Namespace: "ns", Replicas: 2}
+	owner := esv1.Elasticsearch{} // can be any type
+
+	reconciled, err := Reconcile(context.Background(), k8sClient, New(params), &owner)
+	require.NoError(t, err)
+	require.Equal(t, ptr.To[int32](2), reconciled.Spec.Replicas)
+
+	// simulate a scale up by a HorizontalPodAutoscaler
+	reconciled.Spec.Replicas = ptr.To[int32](4)
+	require.NoError(t, k8sClient.Update(context.Background(), &reconciled))
+
+	// replicas should be left to the HorizontalPodAutoscaler
+	params.Autoscaled = true
+	expected := New(params)
+	require.Nil(t, expected.Spec.Replicas)
+	reconciled, err = Reconcile(context.Background(), k8sClient, expected, &owner)
+	require.NoError(t, err)
+	require.Equal(t, ptr.To[int32](4), reconciled.Spec.Replicas)
+	var retrieved appsv1.Deployment
+	require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&expected), &retrieved))
+	require.Equal(t, ptr.To[int32](4), retrieved.Spec.Replicas)
+
+	// replicas should be managed by the operator again once autoscaling is disabled
+	params.Autoscaled = false
+	reconciled, err = Reconcile(context.Background(), k8sClient, New(params), &owner)
+	require.NoError(t, err)
+	require.Equal(t, ptr.To[int32](2), reconciled.Spec.Replicas)
+}
@@ -10,8 +10,14 @@ const (
 	EventReasonDeprecated = "Deprecated"
 	// EventReasonDelayed describes events where a requested change was delayed e.g. to prevent data loss.
 	EventReasonDelayed = "Delayed"
+	// EventReasonMigrated describes events where resources are migrated off a product that is no longer available.
+	EventReasonMigrated = "Migrated"
 	// EventReasonInvalidLicense describes events where a user configured an invalid license for the operator.
 	EventReasonInvalidLicense = "InvalidLicense"
+	// EventReasonLicenseExpiring describes events where the license of a resource is about to expire.
+	EventReasonLicenseExpiring = "LicenseExpiring"
+	// EventReasonLicenseExpired describes events where the license of a resource expired.
+	EventReasonLicenseExpired = "LicenseExpired"
 	// EventReasonStalled describes events where a requested change is stalled and may not make progress without user
 	// intervention. There are transient states e.g. during a nodeSet rename where shards still do not have a place to
 	// move to until the new nodes come up and Elasticsearch will report a stalled shutdown. There are however also
@@ -101,6 +107,14 @@ const (
 	EventActionAutoscalingOffline = "AutoscalingOfflineReconciliation"
 	// EventActionDistributionCheck describes the distribution check step the controller was taking when the event was triggered.
 	EventActionDistributionCheck = "DistributionCheck"
+	// EventActionDiagnosticsCollection describes the diagnostics collection step the controller was taking when the event was triggered.
+	EventActionDiagnosticsCollection = "DiagnosticsCollection"
+	// EventActionVolumeAdoption describes the adoption of released volumes the controller was performing when the event was triggered.
+	EventActionVolumeAdoption = "VolumeAdoption"
+	// EventActionMigration describes the migration step the controller was taking when the event was triggered.
+	EventActionMigration = "Migration"
+	// EventActionFleetPolicyReconciliation describes the Fleet policy reconciliation step the controller was taking when the event was triggered.
+	EventActionFleetPolicyReconciliation = "FleetPolicyReconciliation"
 )
 
 // Event is a k8s event that can be recorded via an event recorder.
@@ -0,0 +1,104 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package initreport reports the outcome of init containers preparing data for the main container, such as the
+// validation of packages or the download of a data file. Init containers write their report to their termination
+// message, which is part of the Pod status, so that the operator does not need to read the logs of the Pods.
+package initreport
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MaxReportBytes is the size above which the kubelet truncates the termination message of a container. Init
+// containers writing more than one line must keep their report below it.
+const MaxReportBytes = 4096
+
+// Phase is the phase of the most recent run of an init container.
+type Phase string
+
+const (
+	// PendingPhase means the init container did not start yet.
+	PendingPhase Phase = "Pending"
+	// RunningPhase means the init container is running.
+	RunningPhase Phase = "Running"
+	// SucceededPhase means the init container completed successfully.
+	SucceededPhase Phase = "Succeeded"
+	// FailedPhase means the init container failed.
+	FailedPhase Phase = "Failed"
+)
+
+// Report is the outcome of the most recent run of an init container.
+type Report struct {
+	Phase Phase
+	// Lines are the lines of the termination message of the init container, once it completed.
+	Lines []string
+}
+
+// LastLine returns the last line of the report, or an empty string if there is none.
+func (r Report) LastLine() string {
+	if len(r.Lines) == 0 {
+		return ""
+	}
+	return r.Lines[len(r.Lines)-1]
+}
+
+// FromPods returns the report of the given init container in the most recently created of the given Pods in which it
+// is declared, or nil if there is no such Pod.
+func FromPods(pods []corev1.Pod, containerName string) *Report {
+	status, found := newestContainerStatus(pods, containerName)
+	if !found {
+		return nil
+	}
+
+	var terminated *corev1.ContainerStateTerminated
+	switch {
+	case status.State.Terminated != nil:
+		terminated = status.State.Terminated
+	case status.State.Running != nil:
+		return &Report{Phase: RunningPhase}
+	case status.LastTerminationState.Terminated != nil && status.LastTerminationState.Terminated.ExitCode != 0:
+		// the init container is waiting to be restarted after a failure
+		terminated = status.LastTerminationState.Terminated
+	default:
+		return &Report{Phase: PendingPhase}
+	}
+
+	phase := SucceededPhase
+	if terminated.ExitCode != 0 {
+		phase = FailedPhase
+	}
+	return &Report{Phase: phase, Lines: lines(terminated.Message)}
+}
+
+// newestContainerStatus returns the status of the given init container in the most recently created Pod declaring it.
+func newestContainerStatus(pods []corev1.Pod, containerName string) (corev1.ContainerStatus, bool) {
+	var newest *corev1.Pod
+	var containerStatus corev1.ContainerStatus
+	for i := range pods {
+		for _, s := range pods[i].Status.InitContainerStatuses {
+			if s.Name != containerName {
+				continue
+			}
+			if newest == nil || newest.CreationTimestamp.Before(&pods[i].CreationTimestamp) {
+				newest = &pods[i]
+				containerStatus = s
+			}
+		}
+	}
+	return containerStatus, newest != nil
+}
+
+// lines returns the non-empty lines of the given message.
+func lines(message string) []string {
+	var result []string
+	for line := range strings.Lines(message) {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package initreport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testContainer = "init"
+
+func testPod(name string, created time.Time, status corev1.ContainerStatus) corev1.Pod {
+	status.Name = testContainer
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", CreationTimestamp: metav1.NewTime(created)},
+		Status:     corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+func terminated(message string, exitCode int32) corev1.ContainerState {
+	return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message, ExitCode: exitCode}}
+}
+
+func TestFromPods(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want *Report
+	}{
+		{
+			name: "no Pod declaring the init container",
+			pods: []corev1.Pod{{Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{{Name: "other"}}}}},
+			want: nil,
+		},
+		{
+			name: "init container not started",
+			pods: []corev1.Pod{testPod("pod", now, corev1.ContainerStatus{})},
+			want: &Report{Phase: PendingPhase},
+		},
+		{
+			name: "init container running",
+			pods: []corev1.Pod{testPod("pod", now, corev1.ContainerStatus{
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			})},
+			want: &Report{Phase: RunningPhase},
+		},
+		{
+			name: "init container succeeded in the newest Pod",
+			pods: []corev1.Pod{
+				testPod("new", now, corev1.ContainerStatus{State: terminated("first\n\nsecond\n", 0)}),
+				testPod("old", now.Add(-time.Hour), corev1.ContainerStatus{State: terminated("old\n", 0)}),
+			},
+			want: &Report{Phase: SucceededPhase, Lines: []string{"first", "second"}},
+		},
+		{
+			name: "init container failed",
+			pods: []corev1.Pod{testPod("pod", now, corev1.ContainerStatus{State: terminated("error\n", 1)})},
+			want: &Report{Phase: FailedPhase, Lines: []string{"error"}},
+		},
+		{
+			name: "init container failed and waits to be restarted",
+			pods: []corev1.Pod{testPod("pod", now, corev1.ContainerStatus{LastTerminationState: terminated("error\n", 1)})},
+			want: &Report{Phase: FailedPhase, Lines: []string{"error"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FromPods(tt.pods, testContainer))
+		})
+	}
+}
@@ -60,13 +60,21 @@ func (lc *checker) publicKeyFor(l EnterpriseLicense) ([]byte, error) {
 	return signatureSec.Data[TrialPubkeyKey], err
 }
 
-// CurrentEnterpriseLicense returns the currently valid Enterprise license if installed.
+// CurrentEnterpriseLicense returns the currently valid Enterprise license if installed. Only licenses of the default
+// license pool are considered, licenses restricted to a subset of the resources do not enable the features of the
+// operator as a whole.
 func (lc *checker) CurrentEnterpriseLicense(ctx context.Context) (*EnterpriseLicense, error) {
 	licenses, err := EnterpriseLicenses(lc.k8sClient)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to list enterprise licenses")
 	}
 
+	return BestValidLicense(ctx, lc, licenses)
+}
+
+// BestValidLicense returns the valid Enterprise license with the most features and the latest expiry among the given
+// licenses, or nil if none is valid.
+func BestValidLicense(ctx context.Context, checker Checker, licenses []EnterpriseLicense) (*EnterpriseLicense, error) {
 	sort.Slice(licenses, func(i, j int) bool {
 		t1, t2 := OperatorLicenseTypeOrder[licenses[i].License.Type], OperatorLicenseTypeOrder[licenses[j].License.Type]
 		if t1 != t2 { // sort by type (first the most features)
@@ -78,7 +86,7 @@ func (lc *checker) CurrentEnterpriseLicense(ctx context.Context) (*EnterpriseLic
 
 	// pick the first valid Enterprise license in the sorted slice
 	for _, l := range licenses {
-		valid, err := lc.Valid(ctx, l)
+		valid, err := checker.Valid(ctx, l)
 		if err != nil {
 			return nil, err
 		}
@@ -146,6 +146,8 @@ func Test_CurrentEnterpriseLicense(t *testing.T) {
 	signatureBytes, err := NewSigner(privKey).Sign(validLicenseFixture)
 	require.NoError(t, err)
 	validLicense := asClientObjects(validLicenseFixture, signatureBytes)
+	validScopedLicense := asClientObject(withSignature(validLicenseFixture, signatureBytes))
+	validScopedLicense.GetLabels()[LicenseNamespaceSelectorLabelPrefix+"business-unit"] = "finance"
 
 	trialState, err := NewTrialState()
 	require.NoError(t, err)
@@ -202,6 +204,16 @@ func Test_CurrentEnterpriseLicense(t *testing.T) {
 			wantType: LicenseTypeEnterprise,
 			wantErr:  false,
 		},
+		{
+			name: "valid enterprise license restricted to a license pool: OK",
+			fields: fields{
+				initialObjects:    []client.Object{validScopedLicense},
+				operatorNamespace: "test-system",
+				publicKey:         publicKeyBytesFixture(t),
+			},
+			want:    false,
+			wantErr: false,
+		},
 		{
 			name: "no license: OK",
 			fields: fields{
@@ -34,29 +34,14 @@ func NewError(src runtime.Object, err error) error {
 	return &Error{Source: src, Err: err}
 }
 
-// EnterpriseLicensesOrErrors lists all Enterprise licenses and all errors encountered during retrieval.
+// EnterpriseLicensesOrErrors lists all Enterprise licenses of the default license pool and all errors encountered during
+// retrieval. Licenses restricted to a subset of the resources do not apply to the operator as a whole.
 func EnterpriseLicensesOrErrors(c k8s.Client) ([]EnterpriseLicense, []error) {
-	licenseList := corev1.SecretList{}
-	matchingLabels := NewLicenseByScopeSelector(LicenseScopeOperator)
-	err := c.List(context.Background(), &licenseList, matchingLabels)
-	if err != nil {
-		return nil, []error{err}
-	}
-	var licenses []EnterpriseLicense
-	var errors []error
-	for _, license := range licenseList.Items {
-		ls := license
-		parsed, err := ParseEnterpriseLicense(ls.Data)
-		if err != nil {
-			errors = append(errors, NewError(&ls, pkgerrors.Wrapf(err, "while parsing license in %v", k8s.ExtractNamespacedName(&ls))))
-		} else {
-			licenses = append(licenses, parsed)
-		}
-	}
-	return licenses, errors
+	scoped, errors := ScopedEnterpriseLicensesOrErrors(c)
+	return InPool(scoped, DefaultLicensePool), errors
 }
 
-// EnterpriseLicenses lists all Enterprise licenses or an aggregate error
+// EnterpriseLicenses lists all Enterprise licenses of the default license pool or an aggregate error
 func EnterpriseLicenses(c k8s.Client) ([]EnterpriseLicense, error) {
 	licenses, errors := EnterpriseLicensesOrErrors(c)
 	return licenses, utilerrors.NewAggregate(errors)
@@ -0,0 +1,222 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sort"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+const (
+	// LicenseNamespaceSelectorLabelPrefix prefixes the labels restricting an operator license to the resources deployed
+	// in the namespaces holding the same labels. For example "namespace-selector.license.k8s.elastic.co/business-unit:
+	// finance" restricts the license to the namespaces labeled with "business-unit: finance". A label key holds at most
+	// one "/", so prefixed labels such as "kubernetes.io/metadata.name" can only be selected through
+	// LicenseNamespaceSelectorAnnotation.
+	LicenseNamespaceSelectorLabelPrefix = "namespace-selector.license.k8s.elastic.co/"
+	// LicenseElasticsearchSelectorLabelPrefix prefixes the labels restricting an operator license to the Elasticsearch
+	// clusters holding the same labels. Prefixed labels can only be selected through
+	// LicenseElasticsearchSelectorAnnotation.
+	LicenseElasticsearchSelectorLabelPrefix = "elasticsearch-selector.license.k8s.elastic.co/"
+	// LicenseNamespaceSelectorAnnotation holds a JSON label selector restricting an operator license to the resources
+	// deployed in the selected namespaces, for example {"matchLabels":{"kubernetes.io/metadata.name":"finance"}}.
+	// It is combined with the LicenseNamespaceSelectorLabelPrefix labels.
+	LicenseNamespaceSelectorAnnotation = "license.k8s.elastic.co/namespace-selector"
+	// LicenseElasticsearchSelectorAnnotation holds a JSON label selector restricting an operator license to the
+	// selected Elasticsearch clusters. It is combined with the LicenseElasticsearchSelectorLabelPrefix labels.
+	LicenseElasticsearchSelectorAnnotation = "license.k8s.elastic.co/elasticsearch-selector"
+	// LicenseLabelPool is the name of the license pool a scoped operator license belongs to. Defaults to the name of the
+	// license secret.
+	LicenseLabelPool = "license.k8s.elastic.co/pool"
+	// DefaultLicensePool is the pool of the operator licenses without scope, which applies to all the resources outside
+	// the scope of any other pool.
+	DefaultLicensePool = "default"
+)
+
+// Scope restricts an operator license to a subset of the resources managed by the operator.
+type Scope struct {
+	// NamespaceSelector selects the namespaces of the resources in scope.
+	NamespaceSelector metav1.LabelSelector
+	// ElasticsearchSelector selects the resources in scope by their labels.
+	ElasticsearchSelector metav1.LabelSelector
+}
+
+// IsEmpty returns true if the scope does not restrict the license to any subset of the resources.
+func (s Scope) IsEmpty() bool {
+	return k8s.IsLabelSelectorEmpty(&s.NamespaceSelector) && k8s.IsLabelSelectorEmpty(&s.ElasticsearchSelector)
+}
+
+// ParseScope returns the scope described by the selector annotations and labels of a license secret.
+func ParseScope(secret metav1.Object) (Scope, error) {
+	var scope Scope
+	var err error
+	if scope.NamespaceSelector, err = parseSelectorAnnotation(secret, LicenseNamespaceSelectorAnnotation); err != nil {
+		return Scope{}, err
+	}
+	if scope.ElasticsearchSelector, err = parseSelectorAnnotation(secret, LicenseElasticsearchSelectorAnnotation); err != nil {
+		return Scope{}, err
+	}
+	for label, value := range secret.GetLabels() {
+		if name, ok := strings.CutPrefix(label, LicenseNamespaceSelectorLabelPrefix); ok {
+			scope.NamespaceSelector = *metav1.AddLabelToSelector(&scope.NamespaceSelector, name, value)
+		}
+		if name, ok := strings.CutPrefix(label, LicenseElasticsearchSelectorLabelPrefix); ok {
+			scope.ElasticsearchSelector = *metav1.AddLabelToSelector(&scope.ElasticsearchSelector, name, value)
+		}
+	}
+	return scope, nil
+}
+
+// parseSelectorAnnotation returns the label selector held by the given annotation, or an empty selector if it is not set.
+func parseSelectorAnnotation(secret metav1.Object, annotation string) (metav1.LabelSelector, error) {
+	var selector metav1.LabelSelector
+	value, exists := secret.GetAnnotations()[annotation]
+	if !exists {
+		return selector, nil
+	}
+	if err := json.Unmarshal([]byte(value), &selector); err != nil {
+		return metav1.LabelSelector{}, pkgerrors.Wrapf(err, "while parsing annotation %s", annotation)
+	}
+	if _, err := metav1.LabelSelectorAsSelector(&selector); err != nil {
+		return metav1.LabelSelector{}, pkgerrors.Wrapf(err, "invalid label selector in annotation %s", annotation)
+	}
+	return selector, nil
+}
+
+// ScopedLicense is an operator license along with the license pool it belongs to.
+type ScopedLicense struct {
+	EnterpriseLicense
+	// Pool is the name of the license pool, DefaultLicensePool for licenses without scope.
+	Pool string
+	// Scope restricts the license to a subset of the resources.
+	Scope Scope
+	// secretName is the name of the secret holding the license, used to order the pools.
+	secretName string
+}
+
+// ScopedEnterpriseLicensesOrErrors lists all Enterprise licenses with their scope and all errors encountered during retrieval.
+func ScopedEnterpriseLicensesOrErrors(c k8s.Client) ([]ScopedLicense, []error) {
+	licenseList := corev1.SecretList{}
+	matchingLabels := NewLicenseByScopeSelector(LicenseScopeOperator)
+	err := c.List(context.Background(), &licenseList, matchingLabels)
+	if err != nil {
+		return nil, []error{err}
+	}
+	var licenses []ScopedLicense
+	var errors []error
+	for _, license := range licenseList.Items {
+		ls := license
+		parsed, err := ParseEnterpriseLicense(ls.Data)
+		if err != nil {
+			errors = append(errors, NewError(&ls, pkgerrors.Wrapf(err, "while parsing license in %v", k8s.ExtractNamespacedName(&ls))))
+			continue
+		}
+		scope, err := ParseScope(&ls)
+		if err != nil {
+			errors = append(errors, NewError(&ls, pkgerrors.Wrapf(err, "while parsing license scope in %v", k8s.ExtractNamespacedName(&ls))))
+			continue
+		}
+		pool := DefaultLicensePool
+		if !scope.IsEmpty() {
+			pool = ls.Name
+			if name := ls.Labels[LicenseLabelPool]; name != "" {
+				pool = name
+			}
+		}
+		licenses = append(licenses, ScopedLicense{EnterpriseLicense: parsed, Pool: pool, Scope: scope, secretName: ls.Name})
+	}
+	return licenses, errors
+}
+
+// InPool returns the licenses of the given pool.
+func InPool(licenses []ScopedLicense, pool string) []EnterpriseLicense {
+	var inPool []EnterpriseLicense
+	for _, l := range licenses {
+		if l.Pool == pool {
+			inPool = append(inPool, l.EnterpriseLicense)
+		}
+	}
+	return inPool
+}
+
+// poolSelector selects the resources of a license pool.
+type poolSelector struct {
+	pool       string
+	namespaces func(namespace string) bool
+	selector   labels.Selector
+}
+
+// PoolMatcher assigns resources to the license pools.
+type PoolMatcher struct {
+	selectors []poolSelector
+}
+
+// NewPoolMatcher returns a PoolMatcher for the pools of the given licenses.
+func NewPoolMatcher(ctx context.Context, c k8s.Client, licenses []ScopedLicense) (PoolMatcher, error) {
+	scoped := make([]ScopedLicense, 0, len(licenses))
+	for _, l := range licenses {
+		if l.Pool != DefaultLicensePool {
+			scoped = append(scoped, l)
+		}
+	}
+	// resources matching several pools are consistently assigned to the first one
+	sort.Slice(scoped, func(i, j int) bool {
+		if scoped[i].Pool != scoped[j].Pool {
+			return scoped[i].Pool < scoped[j].Pool
+		}
+		return scoped[i].secretName < scoped[j].secretName
+	})
+
+	matcher := PoolMatcher{selectors: make([]poolSelector, 0, len(scoped))}
+	for _, l := range scoped {
+		namespaces, err := k8s.NamespaceFilterFunc(ctx, c, l.Scope.NamespaceSelector)
+		if err != nil {
+			return PoolMatcher{}, err
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&l.Scope.ElasticsearchSelector)
+		if err != nil {
+			return PoolMatcher{}, err
+		}
+		matcher.selectors = append(matcher.selectors, poolSelector{pool: l.Pool, namespaces: namespaces, selector: selector})
+	}
+	return matcher, nil
+}
+
+// Pools returns the names of all the license pools, including the default one.
+func (m PoolMatcher) Pools() []string {
+	pools := []string{DefaultLicensePool}
+	for _, s := range m.selectors {
+		if !slices.Contains(pools, s.pool) {
+			pools = append(pools, s.pool)
+		}
+	}
+	return pools
+}
+
+// HasScopedPools returns true if at least one license is restricted to a subset of the resources.
+func (m PoolMatcher) HasScopedPools() bool {
+	return len(m.selectors) > 0
+}
+
+// Pool returns the license pool of the given resource. Resources other than Elasticsearch clusters are selected by the
+// Elasticsearch selector of a pool based on their own labels.
+func (m PoolMatcher) Pool(obj metav1.Object) string {
+	for _, s := range m.selectors {
+		if s.namespaces(obj.GetNamespace()) && s.selector.Matches(labels.Set(obj.GetLabels())) {
+			return s.pool
+		}
+	}
+	return DefaultLicensePool
+}
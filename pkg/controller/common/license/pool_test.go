@@ -0,0 +1,152 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+// scopedLicense returns a license secret with the given name and additional labels.
+func scopedLicense(uid, name string, labels map[string]string) client.Object {
+	l := licenseFixtureV4
+	l.License.UID = uid
+	secret := asClientObject(l)
+	secret.SetName(name)
+	maps.Copy(secret.GetLabels(), labels)
+	return secret
+}
+
+func TestParseScope(t *testing.T) {
+	scope, err := ParseScope(&corev1.Secret{})
+	require.NoError(t, err)
+	assert.True(t, scope.IsEmpty())
+
+	scope, err = ParseScope(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+		LicenseLabelScope: string(LicenseScopeOperator),
+		LicenseNamespaceSelectorLabelPrefix + "business-unit":  "finance",
+		LicenseElasticsearchSelectorLabelPrefix + "tier":       "prod",
+		LicenseElasticsearchSelectorLabelPrefix + "department": "sales",
+	}}})
+	require.NoError(t, err)
+	assert.False(t, scope.IsEmpty())
+	assert.Equal(t, metav1.LabelSelector{MatchLabels: map[string]string{"business-unit": "finance"}}, scope.NamespaceSelector)
+	assert.Equal(t, metav1.LabelSelector{MatchLabels: map[string]string{"tier": "prod", "department": "sales"}}, scope.ElasticsearchSelector)
+
+	// prefixed labels are selected through the annotations, combined with the selector labels
+	scope, err = ParseScope(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Labels: map[string]string{LicenseNamespaceSelectorLabelPrefix + "business-unit": "finance"},
+		Annotations: map[string]string{
+			LicenseNamespaceSelectorAnnotation:     `{"matchLabels":{"kubernetes.io/metadata.name":"finance"}}`,
+			LicenseElasticsearchSelectorAnnotation: `{"matchExpressions":[{"key":"app.kubernetes.io/part-of","operator":"Exists"}]}`,
+		},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, metav1.LabelSelector{MatchLabels: map[string]string{
+		"kubernetes.io/metadata.name": "finance",
+		"business-unit":               "finance",
+	}}, scope.NamespaceSelector)
+	assert.Equal(t, metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "app.kubernetes.io/part-of", Operator: metav1.LabelSelectorOpExists},
+	}}, scope.ElasticsearchSelector)
+
+	for _, invalid := range []string{`not json`, `{"matchExpressions":[{"key":"tier","operator":"Unknown"}]}`} {
+		_, err = ParseScope(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{LicenseNamespaceSelectorAnnotation: invalid},
+		}})
+		require.Error(t, err)
+	}
+}
+
+func TestScopedEnterpriseLicensesOrErrors(t *testing.T) {
+	c := k8s.NewFakeClient(
+		scopedLicense("unscoped", "unscoped-license", nil),
+		scopedLicense("finance", "finance-license", map[string]string{LicenseNamespaceSelectorLabelPrefix + "business-unit": "finance"}),
+		scopedLicense("sales", "sales-license", map[string]string{
+			LicenseElasticsearchSelectorLabelPrefix + "business-unit": "sales",
+			LicenseLabelPool: "sales",
+		}),
+	)
+
+	licenses, errs := ScopedEnterpriseLicensesOrErrors(c)
+	require.Empty(t, errs)
+
+	pools := map[string]string{}
+	for _, l := range licenses {
+		pools[l.License.UID] = l.Pool
+	}
+	assert.Equal(t, map[string]string{
+		"unscoped": DefaultLicensePool,
+		"finance":  "finance-license",
+		"sales":    "sales",
+	}, pools)
+
+	inPool := InPool(licenses, "sales")
+	require.Len(t, inPool, 1)
+	assert.Equal(t, "sales", inPool[0].License.UID)
+
+	// licenses restricted to a pool do not apply to the operator as a whole
+	operatorLicenses, errs := EnterpriseLicensesOrErrors(c)
+	require.Empty(t, errs)
+	require.Len(t, operatorLicenses, 1)
+	assert.Equal(t, "unscoped", operatorLicenses[0].License.UID)
+}
+
+func TestPoolMatcher(t *testing.T) {
+	c := k8s.NewFakeClient(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "finance", Labels: map[string]string{"business-unit": "finance"}}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shared"}},
+		scopedLicense("unscoped", "unscoped-license", nil),
+		scopedLicense("finance", "finance-license", map[string]string{LicenseNamespaceSelectorLabelPrefix + "business-unit": "finance"}),
+		scopedLicense("sales", "sales-license", map[string]string{LicenseElasticsearchSelectorLabelPrefix + "business-unit": "sales"}),
+		// overlaps with the sales pool, whose name comes first
+		scopedLicense("shared", "shared-license", map[string]string{LicenseElasticsearchSelectorLabelPrefix + "shared": "true"}),
+	)
+	licenses, errs := ScopedEnterpriseLicensesOrErrors(c)
+	require.Empty(t, errs)
+	matcher, err := NewPoolMatcher(context.Background(), c, licenses)
+	require.NoError(t, err)
+	assert.True(t, matcher.HasScopedPools())
+	assert.Equal(t, []string{DefaultLicensePool, "finance-license", "sales-license", "shared-license"}, matcher.Pools())
+
+	tests := []struct {
+		name      string
+		namespace string
+		labels    map[string]string
+		want      string
+	}{
+		{name: "namespace selector", namespace: "finance", want: "finance-license"},
+		{name: "label selector", namespace: "shared", labels: map[string]string{"business-unit": "sales"}, want: "sales-license"},
+		{name: "first matching pool", namespace: "shared", labels: map[string]string{"business-unit": "sales", "shared": "true"}, want: "sales-license"},
+		{name: "other label selector", namespace: "shared", labels: map[string]string{"shared": "true"}, want: "shared-license"},
+		{name: "no matching pool", namespace: "unknown", want: DefaultLicensePool},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{Name: "es", Namespace: tt.namespace, Labels: tt.labels}
+			assert.Equal(t, tt.want, matcher.Pool(obj))
+		})
+	}
+
+	var withoutScope []ScopedLicense
+	for _, l := range licenses {
+		if l.Scope.IsEmpty() {
+			withoutScope = append(withoutScope, l)
+		}
+	}
+	unscoped, err := NewPoolMatcher(context.Background(), c, withoutScope)
+	require.NoError(t, err)
+	assert.False(t, unscoped.HasScopedPools())
+	assert.Equal(t, DefaultLicensePool, unscoped.Pool(&metav1.ObjectMeta{Namespace: "finance"}))
+}
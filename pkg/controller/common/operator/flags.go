@@ -21,16 +21,21 @@ const (
 	DistributionChannelFlag              = "distribution-channel"
 	ElasticsearchClientTimeout           = "elasticsearch-client-timeout"
 	ElasticsearchObservationIntervalFlag = "elasticsearch-observation-interval"
+	EnableConversionWebhookFlag          = "enable-conversion-webhook"
 	EnableLeaderElection                 = "enable-leader-election"
+	EnableStorageVersionMigrationFlag    = "enable-storage-version-migration"
 	EnableTracingFlag                    = "enable-tracing"
 	EnableWebhookFlag                    = "enable-webhook"
 	EnforceRBACOnRefsFlag                = "enforce-rbac-on-refs"
 	ExposedNodeLabels                    = "exposed-node-labels"
+	ImageCatalogFlag                     = "image-catalog"
 	PasswordLengthFlag                   = "password-length"
 	PasswordHashCacheSize                = "password-hash-cache-size"
 	IPFamilyFlag                         = "ip-family"
 	KubeClientTimeout                    = "kube-client-timeout"
 	KubeClientQPS                        = "kube-client-qps"
+	LicenseExpiryWarningsFlag            = "license-expiry-warnings"
+	LicensingLedgerRetentionFlag         = "licensing-ledger-retention-months"
 	ManageWebhookCertsFlag               = "manage-webhook-certs"
 	MaxConcurrentReconcilesFlag          = "max-concurrent-reconciles"
 	MetricsPortFlag                      = "metrics-port"
@@ -40,11 +45,15 @@ const (
 	NamespacesFlag                       = "namespaces"
 	OperatorNamespaceFlag                = "operator-namespace"
 	SetDefaultSecurityContextFlag        = "set-default-security-context"
+	ShardCountFlag                       = "shard-count"
+	ShardKeyFlag                         = "shard-key"
 	TelemetryIntervalFlag                = "telemetry-interval"
+	TelemetrySinksFlag                   = "telemetry-sinks"
 	UBIOnlyFlag                          = "ubi-only"
 	ValidateStorageClassFlag             = "validate-storage-class"
 	WebhookCertDirFlag                   = "webhook-cert-dir"
 	WebhookNameFlag                      = "webhook-name"
+	WebhookReferenceValidationFlag       = "webhook-reference-validation"
 	WebhookSecretFlag                    = "webhook-secret"
 	WebhookPortFlag                      = "webhook-port"
 )
@@ -13,6 +13,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/about"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
 	commonpassword "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/password"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/sharding"
 	esvalidation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/validation"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/cryptutil"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/net"
@@ -24,6 +25,9 @@ type Parameters struct {
 	ElasticsearchObservationInterval time.Duration
 	// ExposedNodeLabels holds regular expressions of node labels which are allowed to be automatically set as annotations on Elasticsearch Pods.
 	ExposedNodeLabels esvalidation.NodeLabels
+	// LicenseExpiryWarnings are the durations before the expiry of the license of an Elasticsearch cluster at which
+	// users are warned.
+	LicenseExpiryWarnings []time.Duration
 	// OperatorNamespace is the control plane namespace of the operator.
 	OperatorNamespace string
 	// OperatorInfo is information about the operator
@@ -52,4 +56,7 @@ type Parameters struct {
 	ValidateStorageClass bool
 	// Tracer is a shared APM tracer instance or nil
 	Tracer *apm.Tracer
+	// Sharding assigns the resources to the operator replicas reconciling them, nil if all resources are reconciled by
+	// the elected operator.
+	Sharding *sharding.Manager
 }
@@ -29,6 +29,7 @@ import (
 	kbv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1beta1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
 	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/maps/v1alpha1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
 	packageregistryv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/packageregistry/v1alpha1"
 	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/stackconfigpolicy/v1alpha1"
 )
@@ -68,6 +69,7 @@ func SetupScheme() {
 		policyv1alpha1.AddToScheme,
 		autoopsv1alpha1.AddToScheme,
 		logstashv1alpha1.AddToScheme,
+		otelv1alpha1.AddToScheme,
 	}
 	mustAddSchemeOnce(&addToScheme, schemes)
 }
@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"sync"
+
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// Filter is a reconciler which only reconciles the requests of the shards owned by this operator replica. The requests
+// of the other shards are recorded, to be reconciled as soon as their shard is acquired by this replica. This includes
+// the requests of deleted resources, for the deletion to be handled even if it happens while no replica owns the shard.
+type Filter struct {
+	reconcile.Reconciler
+	manager *Manager
+	// cache holds the resources of the kind reconciled by the filtered reconciler.
+	cache   cache.Cache
+	forType client.Object
+
+	mutex sync.Mutex
+	// pending holds the requests of each shard not owned by this replica.
+	pending map[int]map[reconcile.Request]struct{}
+}
+
+// NewFilter returns a Filter reconciling the requests of the shards owned by this operator replica with r, which
+// reconciles resources of the same kind as forType.
+func (m *Manager) NewFilter(r reconcile.Reconciler, cache cache.Cache, forType client.Object) *Filter {
+	return &Filter{
+		Reconciler: r,
+		manager:    m,
+		cache:      cache,
+		forType:    forType,
+		pending:    map[int]map[reconcile.Request]struct{}{},
+	}
+}
+
+// Reconcile reconciles the given request if its shard is owned by this operator replica.
+func (f *Filter) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	shard := f.manager.ShardOf(request.NamespacedName)
+	// hold the lock until the request is recorded, so that it is either reconciled now or re-queued on acquisition
+	f.mutex.Lock()
+	done, owned := f.manager.begin(shard)
+	if !owned {
+		defer f.mutex.Unlock()
+		f.park(shard, request)
+		return reconcile.Result{}, nil
+	}
+	f.mutex.Unlock()
+	defer done()
+	return f.Reconciler.Reconcile(ctx, request)
+}
+
+// park records the given request of a shard not owned by this operator replica. The request of a resource that does not
+// exist anymore is kept as a delete marker: the shard may be between owners, the replica acquiring it then reconciles
+// the request to run the delete handling of the reconciler.
+func (f *Filter) park(shard int, request reconcile.Request) {
+	if f.pending[shard] == nil {
+		f.pending[shard] = map[reconcile.Request]struct{}{}
+	}
+	f.pending[shard][request] = struct{}{}
+}
+
+// Source returns a source enqueuing the pending requests of the shards acquired by this operator replica, and parking
+// a delete marker for the resources deleted in the shards it does not own.
+func (f *Filter) Source() source.Source {
+	return source.Func(func(ctx context.Context, queue workqueue.TypedRateLimitingInterface[reconcile.Request]) error {
+		informer, err := f.cache.GetInformer(ctx, f.forType)
+		if err != nil {
+			return err
+		}
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{DeleteFunc: f.parkDeleted}); err != nil {
+			return err
+		}
+		f.manager.OnAcquire(func(shard int) {
+			for _, request := range f.takePending(shard) {
+				queue.Add(request)
+			}
+		})
+		return nil
+	})
+}
+
+// parkDeleted parks a delete marker for the given deleted resource, if its shard is not owned by this operator replica.
+func (f *Filter) parkDeleted(obj any) {
+	if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	deleted, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+	request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(deleted)}
+	shard := f.manager.ShardOf(request.NamespacedName)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.manager.Owns(shard) {
+		f.park(shard, request)
+	}
+}
+
+// takePending returns and forgets the pending requests of the given shard.
+func (f *Filter) takePending(shard int) []reconcile.Request {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	requests := make([]reconcile.Request, 0, len(f.pending[shard]))
+	for request := range f.pending[shard] {
+		requests = append(requests, request)
+	}
+	delete(f.pending, shard)
+	return requests
+}
@@ -0,0 +1,458 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/metrics"
+)
+
+const (
+	// LeasePrefix is the prefix of the names of the Leases used to assign shards to the operator replicas.
+	LeasePrefix = "elastic-operator-shard"
+	// LeaseTypeLabel distinguishes the Leases held by each shard owner from the Leases advertising the operator replicas.
+	LeaseTypeLabel  = "sharding.k8s.elastic.co/type"
+	shardLeaseType  = "shard"
+	memberLeaseType = "member"
+
+	// DefaultLeaseDuration is the duration after which a Lease which is not renewed is considered released.
+	DefaultLeaseDuration = 15 * time.Second
+	// DefaultRenewInterval is the interval at which Leases are renewed and shards re-assigned.
+	DefaultRenewInterval = 5 * time.Second
+
+	// drainPollInterval is the interval at which the reconciliations in progress are checked when shutting down.
+	drainPollInterval = 100 * time.Millisecond
+)
+
+var log = ulog.Log.WithName("sharding")
+
+// Key selects how resources are assigned to shards.
+type Key string
+
+const (
+	// NamespaceKey assigns all the resources of a namespace to the same shard.
+	NamespaceKey Key = "namespace"
+	// ResourceKey assigns each resource to a shard independently of its namespace.
+	ResourceKey Key = "resource"
+)
+
+// ParseKey parses the given sharding key.
+func ParseKey(key string) (Key, error) {
+	switch k := Key(strings.ToLower(key)); k {
+	case NamespaceKey, ResourceKey:
+		return k, nil
+	default:
+		return "", fmt.Errorf("invalid sharding key %q, must be one of %s or %s", key, NamespaceKey, ResourceKey)
+	}
+}
+
+// Params are the parameters of the sharding of the reconciliations across operator replicas.
+type Params struct {
+	// Shards is the number of shards the resources are partitioned into.
+	Shards int
+	// Key selects how resources are assigned to shards.
+	Key Key
+	// Namespace is the namespace of the Leases, usually the operator namespace.
+	Namespace string
+	// Identity uniquely identifies this operator replica.
+	Identity string
+	// LeaseDuration is the duration after which a Lease which is not renewed is considered released.
+	LeaseDuration time.Duration
+	// RenewInterval is the interval at which Leases are renewed and shards re-assigned.
+	RenewInterval time.Duration
+}
+
+// Manager assigns shards to the operator replicas through Lease objects. Each replica advertises itself with a member
+// Lease, and the shards are evenly distributed across the live replicas. Each shard is owned by at most one replica at
+// a time, which holds its shard Lease: when replicas come and go, a replica first releases the shards assigned to
+// another replica before that replica acquires them. A shard is only released once its reconciliations in progress
+// are over, no new reconciliation of the shard starts in the meantime.
+type Manager struct {
+	params    Params
+	clientset kubernetes.Interface
+	now       func() time.Time
+
+	mutex sync.RWMutex
+	// owned holds the time of the last renewal of each owned shard.
+	owned map[int]time.Time
+	// releasing holds the owned shards being handed over to another replica.
+	releasing map[int]struct{}
+	// inFlight counts the reconciliations in progress of each shard.
+	inFlight map[int]int
+	// onAcquire are called whenever a shard is acquired.
+	onAcquire []func(shard int)
+	// onRelease are called whenever a shard is released.
+	onRelease []func(shard int)
+}
+
+// NewManager returns a new shard Manager.
+func NewManager(clientset kubernetes.Interface, params Params) *Manager {
+	if params.LeaseDuration == 0 {
+		params.LeaseDuration = DefaultLeaseDuration
+	}
+	if params.RenewInterval == 0 {
+		params.RenewInterval = DefaultRenewInterval
+	}
+	return &Manager{
+		params:    params,
+		clientset: clientset,
+		now:       time.Now,
+		owned:     map[int]time.Time{},
+		releasing: map[int]struct{}{},
+		inFlight:  map[int]int{},
+	}
+}
+
+// ShardOf returns the shard of the resource with the given name.
+func (m *Manager) ShardOf(name types.NamespacedName) int {
+	key := name.Namespace
+	if m.params.Key == ResourceKey {
+		key = name.String()
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(m.params.Shards)) //nolint:gosec
+}
+
+// Owns returns true if the given shard is owned by this operator replica and not being released.
+func (m *Manager) Owns(shard int) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.owns(shard)
+}
+
+func (m *Manager) owns(shard int) bool {
+	renewed, owned := m.owned[shard]
+	_, releasing := m.releasing[shard]
+	// stop reconciling a shard which could not be renewed before another replica may acquire it
+	return owned && !releasing && m.now().Sub(renewed) < m.params.LeaseDuration
+}
+
+// begin records the start of a reconciliation of the given shard if it is owned by this operator replica. The returned
+// function must be called at the end of the reconciliation.
+func (m *Manager) begin(shard int) (func(), bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if !m.owns(shard) {
+		return nil, false
+	}
+	m.inFlight[shard]++
+	return func() {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.inFlight[shard]--
+		if m.inFlight[shard] == 0 {
+			delete(m.inFlight, shard)
+		}
+	}, true
+}
+
+// drain stops starting reconciliations of the given shard and returns the number of its reconciliations in progress.
+func (m *Manager) drain(shard int) int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.releasing[shard] = struct{}{}
+	return m.inFlight[shard]
+}
+
+// OnAcquire registers a function called whenever this operator replica acquires a shard.
+func (m *Manager) OnAcquire(f func(shard int)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.onAcquire = append(m.onAcquire, f)
+}
+
+// OnRelease registers a function called whenever this operator replica releases a shard, once its reconciliations in
+// progress are over.
+func (m *Manager) OnRelease(f func(shard int)) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.onRelease = append(m.onRelease, f)
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, shards are assigned on all the operator replicas.
+func (m *Manager) NeedLeaderElection() bool {
+	return false
+}
+
+// Start assigns shards until the given context is done, then releases the shards owned by this operator replica.
+func (m *Manager) Start(ctx context.Context) error {
+	log.Info("Starting shard manager", "shards", m.params.Shards, "key", m.params.Key, "identity", m.params.Identity)
+	ticker := time.NewTicker(m.params.RenewInterval)
+	defer ticker.Stop()
+	for {
+		if err := m.Sync(ctx); err != nil {
+			log.Error(err, "while assigning shards")
+		}
+		select {
+		case <-ctx.Done():
+			// hand the shards over to the other replicas as soon as possible
+			releaseCtx, cancel := context.WithTimeout(context.Background(), m.params.RenewInterval)
+			defer cancel()
+			m.releaseAll(releaseCtx)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync renews the member Lease of this operator replica, releases the shards assigned to other replicas and acquires
+// or renews the shards assigned to this replica.
+func (m *Manager) Sync(ctx context.Context) error {
+	if err := m.renewMember(ctx); err != nil {
+		return err
+	}
+	leases, err := m.clientset.CoordinationV1().Leases(m.params.Namespace).List(ctx, metav1.ListOptions{LabelSelector: LeaseTypeLabel})
+	if err != nil {
+		return err
+	}
+
+	members := []string{m.params.Identity}
+	shardLeases := map[string]*coordinationv1.Lease{}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		switch lease.Labels[LeaseTypeLabel] {
+		case memberLeaseType:
+			holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+			if holder != "" && !m.expired(lease) && !slices.Contains(members, holder) {
+				members = append(members, holder)
+			}
+		case shardLeaseType:
+			shardLeases[lease.Name] = lease
+		}
+	}
+	slices.Sort(members)
+
+	var errs []error
+	for shard := range m.params.Shards {
+		assignee := members[shard%len(members)]
+		if err := m.syncShard(ctx, shard, shardLeases[shardLeaseName(shard)], assignee == m.params.Identity); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (m *Manager) syncShard(ctx context.Context, shard int, lease *coordinationv1.Lease, assigned bool) error {
+	leases := m.clientset.CoordinationV1().Leases(m.params.Namespace)
+	now := metav1.NewMicroTime(m.now())
+	holder := ""
+	if lease != nil {
+		holder = ptr.Deref(lease.Spec.HolderIdentity, "")
+	}
+
+	switch {
+	case holder == m.params.Identity && !assigned:
+		if inFlight := m.drain(shard); inFlight > 0 {
+			// keep the shard until its reconciliations in progress are over
+			log.V(1).Info("Waiting for reconciliations in progress before releasing shard", "shard", shard, "in_flight", inFlight)
+			lease.Spec.RenewTime = &now
+			_, err := leases.Update(ctx, lease, metav1.UpdateOptions{})
+			return err
+		}
+		// hand the shard over to the replica it is now assigned to
+		m.setReleased(shard)
+		lease.Spec.HolderIdentity = nil
+		_, err := leases.Update(ctx, lease, metav1.UpdateOptions{})
+		log.Info("Released shard", "shard", shard)
+		return err
+	case holder == m.params.Identity:
+		lease.Spec.RenewTime = &now
+		if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		m.setOwned(shard)
+		return nil
+	case !assigned:
+		return nil
+	case lease == nil:
+		if _, err := leases.Create(ctx, m.newLease(shardLeaseName(shard), shardLeaseType, now), metav1.CreateOptions{}); err != nil {
+			return ignoreAlreadyExists(err)
+		}
+	case holder == "" || m.expired(lease):
+		lease.Spec.HolderIdentity = ptr.To(m.params.Identity)
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.params.LeaseDuration.Seconds()))
+		lease.Spec.AcquireTime = &now
+		lease.Spec.RenewTime = &now
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+		if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+			return ignoreConflict(err)
+		}
+	default:
+		// still held by the replica it was previously assigned to
+		return nil
+	}
+	m.setOwned(shard)
+	return nil
+}
+
+func (m *Manager) renewMember(ctx context.Context) error {
+	leases := m.clientset.CoordinationV1().Leases(m.params.Namespace)
+	name := memberLeaseName(m.params.Identity)
+	now := metav1.NewMicroTime(m.now())
+	lease, err := leases.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(ctx, m.newLease(name, memberLeaseType, now), metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = ptr.To(m.params.Identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(m.params.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// releaseAll releases all the shards owned by this operator replica and removes its member Lease. Shards with
+// reconciliations still in progress when the given context is done are not released, their Leases expire instead.
+func (m *Manager) releaseAll(ctx context.Context) {
+	leases := m.clientset.CoordinationV1().Leases(m.params.Namespace)
+	m.mutex.RLock()
+	owned := make([]int, 0, len(m.owned))
+	for shard := range m.owned {
+		owned = append(owned, shard)
+	}
+	m.mutex.RUnlock()
+	for _, shard := range owned {
+		m.drain(shard)
+	}
+	m.waitDrained(ctx)
+	for _, shard := range owned {
+		if inFlight := m.drain(shard); inFlight > 0 {
+			log.Info("Not releasing shard with reconciliations in progress", "shard", shard, "in_flight", inFlight)
+			continue
+		}
+		m.setReleased(shard)
+		lease, err := leases.Get(ctx, shardLeaseName(shard), metav1.GetOptions{})
+		if err != nil || ptr.Deref(lease.Spec.HolderIdentity, "") != m.params.Identity {
+			continue
+		}
+		lease.Spec.HolderIdentity = nil
+		if _, err := leases.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+			log.Error(err, "while releasing shard", "shard", shard)
+		}
+	}
+	if err := leases.Delete(ctx, memberLeaseName(m.params.Identity), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		log.Error(err, "while removing member lease", "identity", m.params.Identity)
+	}
+}
+
+// waitDrained waits until no reconciliation is in progress or the given context is done.
+func (m *Manager) waitDrained(ctx context.Context) {
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for {
+		m.mutex.RLock()
+		drained := len(m.inFlight) == 0
+		m.mutex.RUnlock()
+		if drained {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Manager) newLease(name, leaseType string, now metav1.MicroTime) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: m.params.Namespace,
+			Labels:    map[string]string{LeaseTypeLabel: leaseType},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(m.params.Identity),
+			LeaseDurationSeconds: ptr.To(int32(m.params.LeaseDuration.Seconds())),
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+}
+
+func (m *Manager) expired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil {
+		return true
+	}
+	duration := time.Duration(ptr.Deref(lease.Spec.LeaseDurationSeconds, 0)) * time.Second
+	return m.now().After(lease.Spec.RenewTime.Add(duration))
+}
+
+// setOwned records the renewal of an owned shard. Requests of the shard may have been ignored if it was not owned
+// until then, for example after a restart of the operator, a failure to renew its Lease in time or while it was being
+// released.
+func (m *Manager) setOwned(shard int) {
+	m.mutex.Lock()
+	renewed, owned := m.owned[shard]
+	_, releasing := m.releasing[shard]
+	acquired := !owned || releasing || m.now().Sub(renewed) >= m.params.LeaseDuration
+	m.owned[shard] = m.now()
+	delete(m.releasing, shard)
+	callbacks := slices.Clone(m.onAcquire)
+	m.mutex.Unlock()
+	if !acquired {
+		return
+	}
+	log.Info("Acquired shard", "shard", shard)
+	metrics.ShardOwnedGauge.WithLabelValues(strconv.Itoa(shard)).Set(1)
+	for _, f := range callbacks {
+		f(shard)
+	}
+}
+
+func (m *Manager) setReleased(shard int) {
+	m.mutex.Lock()
+	delete(m.owned, shard)
+	delete(m.releasing, shard)
+	callbacks := slices.Clone(m.onRelease)
+	m.mutex.Unlock()
+	metrics.ShardOwnedGauge.WithLabelValues(strconv.Itoa(shard)).Set(0)
+	for _, f := range callbacks {
+		f(shard)
+	}
+}
+
+func shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-%d", LeasePrefix, shard)
+}
+
+func memberLeaseName(identity string) string {
+	return fmt.Sprintf("%s-member-%s", LeasePrefix, identity)
+}
+
+func ignoreAlreadyExists(err error) error {
+	if apierrors.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+func ignoreConflict(err error) error {
+	if apierrors.IsConflict(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,247 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func newTestManager(clientset kubernetes.Interface, identity string, shards int) *Manager {
+	return NewManager(clientset, Params{Shards: shards, Key: NamespaceKey, Namespace: "elastic-system", Identity: identity})
+}
+
+func ownedShards(m *Manager) []int {
+	var owned []int
+	for shard := range m.params.Shards {
+		if m.Owns(shard) {
+			owned = append(owned, shard)
+		}
+	}
+	return owned
+}
+
+func TestParseKey(t *testing.T) {
+	for _, tt := range []struct {
+		key     string
+		want    Key
+		wantErr bool
+	}{
+		{key: "namespace", want: NamespaceKey},
+		{key: "Resource", want: ResourceKey},
+		{key: "label", wantErr: true},
+		{key: "", wantErr: true},
+	} {
+		got, err := ParseKey(tt.key)
+		if tt.wantErr {
+			require.Error(t, err, tt.key)
+			continue
+		}
+		require.NoError(t, err, tt.key)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestManager_ShardOf(t *testing.T) {
+	byNamespace := NewManager(nil, Params{Shards: 16, Key: NamespaceKey})
+	byResource := NewManager(nil, Params{Shards: 16, Key: ResourceKey})
+
+	namespaceShards := map[int]struct{}{}
+	resourceShards := map[int]struct{}{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		nsn := types.NamespacedName{Namespace: "ns", Name: name}
+		namespaceShards[byNamespace.ShardOf(nsn)] = struct{}{}
+		resourceShards[byResource.ShardOf(nsn)] = struct{}{}
+		// assignment is stable
+		assert.Equal(t, byResource.ShardOf(nsn), byResource.ShardOf(nsn))
+		assert.Less(t, byResource.ShardOf(nsn), 16)
+	}
+	// all the resources of a namespace belong to the same shard with the namespace key
+	assert.Len(t, namespaceShards, 1)
+	assert.Greater(t, len(resourceShards), 1)
+}
+
+func TestManager_Sync(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	a := newTestManager(clientset, "a", 4)
+	b := newTestManager(clientset, "b", 4)
+	var acquiredByA, acquiredByB []int
+	a.OnAcquire(func(shard int) { acquiredByA = append(acquiredByA, shard) })
+	b.OnAcquire(func(shard int) { acquiredByB = append(acquiredByB, shard) })
+
+	// a single replica owns all the shards
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, ownedShards(a))
+	assert.Equal(t, []int{0, 1, 2, 3}, acquiredByA)
+	// renewing the shards does not acquire them again
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, acquiredByA)
+
+	// a new replica does not acquire the shards still held by the other replica
+	require.NoError(t, b.Sync(ctx))
+	assert.Empty(t, ownedShards(b))
+	// which hands them over on its next sync
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{0, 2}, ownedShards(a))
+	require.NoError(t, b.Sync(ctx))
+	assert.Equal(t, []int{1, 3}, ownedShards(b))
+	assert.Equal(t, []int{1, 3}, acquiredByB)
+
+	lease, err := clientset.CoordinationV1().Leases("elastic-system").Get(ctx, "elastic-operator-shard-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "b", ptr.Deref(lease.Spec.HolderIdentity, ""))
+
+	// a replica shutting down releases its shards to the remaining replicas
+	b.releaseAll(ctx)
+	assert.Empty(t, ownedShards(b))
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{0, 1, 2, 3}, ownedShards(a))
+	assert.Equal(t, []int{0, 1, 2, 3, 1, 3}, acquiredByA)
+}
+
+func TestManager_Sync_ExpiredReplica(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	a := newTestManager(clientset, "a", 2)
+	b := newTestManager(clientset, "b", 2)
+	require.NoError(t, a.Sync(ctx))
+	require.NoError(t, b.Sync(ctx))
+	require.NoError(t, a.Sync(ctx))
+	require.NoError(t, b.Sync(ctx))
+	assert.Equal(t, []int{0}, ownedShards(a))
+	assert.Equal(t, []int{1}, ownedShards(b))
+
+	// b stops renewing its leases: a takes its shard over once they expire
+	now := time.Now().Add(DefaultLeaseDuration + time.Second)
+	a.now = func() time.Time { return now }
+	b.now = func() time.Time { return now }
+	assert.Empty(t, ownedShards(b))
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{0, 1}, ownedShards(a))
+}
+
+func TestManager_Sync_InFlight(t *testing.T) {
+	ctx := context.Background()
+	clientset := fake.NewClientset()
+	a := newTestManager(clientset, "a", 2)
+	b := newTestManager(clientset, "b", 2)
+	var releasedByA []int
+	a.OnRelease(func(shard int) { releasedByA = append(releasedByA, shard) })
+
+	require.NoError(t, a.Sync(ctx))
+	done, ok := a.begin(1)
+	require.True(t, ok)
+	require.NoError(t, b.Sync(ctx))
+
+	// shard 1 is assigned to b, but a keeps it until its reconciliation in progress is over
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{0}, ownedShards(a))
+	_, ok = a.begin(1)
+	assert.False(t, ok)
+	assert.Empty(t, releasedByA)
+	require.NoError(t, b.Sync(ctx))
+	assert.Empty(t, ownedShards(b))
+
+	// then hands it over
+	done()
+	require.NoError(t, a.Sync(ctx))
+	assert.Equal(t, []int{1}, releasedByA)
+	require.NoError(t, b.Sync(ctx))
+	assert.Equal(t, []int{1}, ownedShards(b))
+
+	// a replica shutting down does not release a shard with reconciliations in progress
+	done, ok = b.begin(1)
+	require.True(t, ok)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	b.releaseAll(cancelled)
+	done()
+	lease, err := clientset.CoordinationV1().Leases("elastic-system").Get(ctx, "elastic-operator-shard-1", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "b", ptr.Deref(lease.Spec.HolderIdentity, ""))
+}
+
+type countingReconciler struct {
+	requests []reconcile.Request
+}
+
+func (r *countingReconciler) Reconcile(_ context.Context, request reconcile.Request) (reconcile.Result, error) {
+	r.requests = append(r.requests, request)
+	return reconcile.Result{}, nil
+}
+
+// testCache serves objects from a client and events from fake informers.
+type testCache struct {
+	*informertest.FakeInformers
+	reader client.Reader
+}
+
+func (c testCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.reader.Get(ctx, key, obj, opts...)
+}
+
+func TestFilter(t *testing.T) {
+	ctx := context.Background()
+	m := newTestManager(fake.NewClientset(), "a", 1)
+	r := &countingReconciler{}
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "existing"}}
+	deleted := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "deleted"}}
+	informers := &informertest.FakeInformers{}
+	filter := m.NewFilter(r, testCache{FakeInformers: informers, reader: k8s.NewFakeClient(existing)}, &corev1.ConfigMap{})
+	queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer queue.ShutDown()
+	require.NoError(t, filter.Source().Start(ctx, queue))
+
+	// requests of shards not owned are parked, including the ones of resources that do not exist anymore
+	request := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(existing)}
+	missing := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "missing"}}
+	for _, req := range []reconcile.Request{request, request, missing} {
+		_, err := filter.Reconcile(ctx, req)
+		require.NoError(t, err)
+	}
+	assert.Empty(t, r.requests)
+	// resources deleted in shards not owned are parked as delete markers
+	informer, err := informers.FakeInformerFor(ctx, &corev1.ConfigMap{})
+	require.NoError(t, err)
+	informer.Delete(deleted)
+
+	// the pending requests are re-queued once when the shard is acquired, for the deletions to be handled
+	require.NoError(t, m.Sync(ctx))
+	require.Equal(t, 3, queue.Len())
+	var queued []reconcile.Request
+	for range 3 {
+		item, _ := queue.Get()
+		queued = append(queued, item)
+		queue.Done(item)
+	}
+	assert.ElementsMatch(t, []reconcile.Request{request, missing, {NamespacedName: client.ObjectKeyFromObject(deleted)}}, queued)
+
+	// requests of owned shards are reconciled
+	_, err = filter.Reconcile(ctx, request)
+	require.NoError(t, err)
+	assert.Equal(t, []reconcile.Request{request}, r.requests)
+	assert.Empty(t, m.inFlight)
+
+	// deletions in owned shards are left to the reconciler
+	informer.Delete(existing)
+	assert.Empty(t, filter.pending)
+}
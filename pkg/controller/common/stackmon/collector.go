@@ -0,0 +1,280 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"slices"
+
+	"github.com/blang/semver/v4"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+const (
+	// CollectorContainerName is the name of the EDOT collector sidecar container.
+	CollectorContainerName = "otel-collector"
+
+	collectorConfigFilename = "collector.yml"
+	collectorConfigDirPath  = "/etc/otel-collector-config"
+	collectorDataVolumeName = "otel-collector-data"
+	collectorDataPath       = "/var/lib/otel-collector"
+
+	metricsReceiver = "metricbeatreceiver"
+	logsReceiver    = "filebeatreceiver"
+)
+
+// collectorPipeline sends the data collected by a Beats receiver, configured with a Metricbeat or Filebeat
+// configuration, to the monitoring cluster of an association.
+type collectorPipeline struct {
+	// signal is either "metrics" or "logs".
+	signal     string
+	receiver   string
+	baseConfig string
+	assoc      commonv1.Association
+}
+
+// collectorPipelines returns the pipelines to collect metrics and logs from the given resource, an empty configuration
+// disabling the corresponding pipeline.
+func collectorPipelines(resource monitoring.HasMonitoring, metricbeatConfig, filebeatConfig string) ([]collectorPipeline, error) {
+	var pipelines []collectorPipeline
+	for _, p := range []struct {
+		signal       string
+		receiver     string
+		baseConfig   string
+		associations []commonv1.Association
+	}{
+		{signal: "metrics", receiver: metricsReceiver, baseConfig: metricbeatConfig, associations: monitoring.GetMetricsAssociation(resource)},
+		{signal: "logs", receiver: logsReceiver, baseConfig: filebeatConfig, associations: monitoring.GetLogsAssociation(resource)},
+	} {
+		if p.baseConfig == "" {
+			continue
+		}
+		if len(p.associations) != 1 {
+			// should never happen because of the pre-creation validation
+			return nil, errors.New("only one Elasticsearch reference is supported for Stack Monitoring")
+		}
+		pipelines = append(pipelines, collectorPipeline{signal: p.signal, receiver: p.receiver, baseConfig: p.baseConfig, assoc: p.associations[0]})
+	}
+	if len(pipelines) == 0 {
+		return nil, errors.New("no metrics or logs to collect for Stack Monitoring")
+	}
+	return pipelines, nil
+}
+
+// buildCollectorConfig builds an EDOT collector configuration running the given pipelines. The component names are
+// suffixed with the given id, if any, so that the configurations of several resources can be merged in a single collector.
+// caVolume returns the volume holding the CA certificate of the monitoring cluster of an association.
+func buildCollectorConfig(
+	ctx context.Context,
+	client k8s.Client,
+	id string,
+	pipelines []collectorPipeline,
+	caVolume func(assoc commonv1.Association, caSecretName string) volume.VolumeLike,
+) ([]byte, []volume.VolumeLike, error) {
+	componentName := func(kind, signal string) string {
+		if id == "" {
+			return fmt.Sprintf("%s/%s", kind, signal)
+		}
+		return fmt.Sprintf("%s/%s-%s", kind, id, signal)
+	}
+
+	receivers := map[string]any{}
+	exporters := map[string]any{}
+	servicePipelines := map[string]any{}
+	var volumes []volume.VolumeLike
+	for _, p := range pipelines {
+		receiverName := componentName(p.receiver, p.signal)
+		receiverConfig, err := beatReceiverConfig(p.baseConfig, filepath.Join(collectorDataPath, id, p.receiver))
+		if err != nil {
+			return nil, nil, err
+		}
+		receivers[receiverName] = receiverConfig
+
+		exporterName := componentName("elasticsearch", p.signal)
+		exporterConfig, caSecretName, err := buildExporterConfig(ctx, client, p.assoc)
+		if err != nil {
+			return nil, nil, err
+		}
+		if caSecretName != "" {
+			v := caVolume(p.assoc, caSecretName)
+			exporterConfig["tls"] = map[string]any{
+				"ca_file": filepath.Join(v.VolumeMount().MountPath, certificates.CAFileName),
+			}
+			// metrics and logs are usually sent to the same monitoring cluster
+			if !slices.ContainsFunc(volumes, func(other volume.VolumeLike) bool { return other.Name() == v.Name() }) {
+				volumes = append(volumes, v)
+			}
+		}
+		exporters[exporterName] = exporterConfig
+
+		// Beats receivers produce logs whatever the collected data is
+		servicePipelines[componentName("logs", p.signal)] = map[string]any{
+			"receivers": []string{receiverName},
+			"exporters": []string{exporterName},
+		}
+	}
+
+	config, err := yaml.Marshal(map[string]any{
+		"receivers": receivers,
+		"exporters": exporters,
+		"service":   map[string]any{"pipelines": servicePipelines},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, volumes, nil
+}
+
+// beatReceiverConfig converts a Metricbeat or Filebeat configuration into the configuration of a Beats receiver.
+func beatReceiverConfig(baseConfig string, dataPath string) (map[string]any, error) {
+	cfg, err := settings.ParseConfig([]byte(baseConfig))
+	if err != nil {
+		return nil, err
+	}
+	receiverCfg, err := settings.NewCanonicalConfigFrom(map[string]any{
+		"path.data": dataPath,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.MergeWith(receiverCfg); err != nil {
+		return nil, err
+	}
+	var receiverConfig map[string]any
+	if err := cfg.Unpack(&receiverConfig); err != nil {
+		return nil, err
+	}
+	// set after unpacking, empty objects being unpacked as null values
+	receiverConfig["output"] = map[string]any{"otelconsumer": map[string]any{}}
+	return receiverConfig, nil
+}
+
+// buildExporterConfig returns the configuration of the Elasticsearch exporter sending data to the monitoring cluster of
+// the given association, along with the name of the Secret holding the CA certificate of this cluster, if any.
+func buildExporterConfig(ctx context.Context, client k8s.Client, assoc commonv1.Association) (map[string]any, string, error) {
+	credentials, err := association.ElasticsearchAuthSettings(ctx, client, assoc)
+	if err != nil {
+		return nil, "", err
+	}
+	assocConf, err := assoc.AssociationConf()
+	if err != nil {
+		return nil, "", err
+	}
+	exporterConfig := map[string]any{
+		"endpoints": []string{assocConf.GetURL()},
+		"user":      credentials.Username,
+		"password":  credentials.Password,
+		"mapping":   map[string]any{"mode": "bodymap"},
+	}
+	if !assocConf.GetCACertProvided() {
+		return exporterConfig, "", nil
+	}
+	return exporterConfig, assocConf.GetCASecretName(), nil
+}
+
+// associationCAVolume returns the volume holding the CA certificate of the monitoring cluster of an association,
+// mounted at the same path as for the Beats sidecars.
+func associationCAVolume(assoc commonv1.Association, caSecretName string) volume.VolumeLike {
+	caDirPath := fmt.Sprintf(
+		"/mnt/elastic-internal/%s-association/%s/%s/certs",
+		assoc.AssociationType(), assoc.AssociationRef().GetNamespace(), assoc.AssociationRef().NameOrSecretName(),
+	)
+	return volume.NewSecretVolumeWithMountPath(caSecretName, caVolumeName(assoc), caDirPath)
+}
+
+// NewCollectorSidecar builds a single EDOT collector sidecar which runs the given Metricbeat and Filebeat configurations
+// through Beats receivers, in place of the Metricbeat and Filebeat sidecars. An empty configuration disables the
+// collection of the corresponding data.
+func NewCollectorSidecar(
+	ctx context.Context,
+	client k8s.Client,
+	resource monitoring.HasMonitoring,
+	imageVersion semver.Version,
+	metricbeatConfig string,
+	filebeatConfig string,
+	meta metadata.Metadata,
+	additionalVolumes ...volume.VolumeLike,
+) (BeatSidecar, error) {
+	pipelines, err := collectorPipelines(resource, metricbeatConfig, filebeatConfig)
+	if err != nil {
+		return BeatSidecar{}, err
+	}
+	configBytes, caVolumes, err := buildCollectorConfig(ctx, client, "", pipelines, associationCAVolume)
+	if err != nil {
+		return BeatSidecar{}, err
+	}
+
+	configHash := fnv.New32a()
+	if _, err := configHash.Write(configBytes); err != nil {
+		return BeatSidecar{}, err
+	}
+
+	configSecretName := CollectorConfigSecretName(resource.GetName(), pipelines[0].assoc.AssociationType())
+	meta = meta.Merge(metadata.Metadata{Labels: resource.GetIdentityLabels()})
+	configSecret := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        configSecretName,
+			Namespace:   resource.GetNamespace(),
+			Labels:      meta.Labels,
+			Annotations: meta.Annotations,
+		},
+		Data: map[string][]byte{
+			collectorConfigFilename: configBytes,
+		},
+	}
+
+	volumes := []volume.VolumeLike{
+		volume.NewSecretVolumeWithMountPath(configSecretName, configVolumeName(resource.GetName(), CollectorContainerName), collectorConfigDirPath),
+		// EmptyDir volume so that the Beats receivers do not write in the container image
+		volume.NewEmptyDirVolume(collectorDataVolumeName, collectorDataPath),
+	}
+	volumes = append(volumes, caVolumes...)
+	for _, additionalVolume := range additionalVolumes {
+		if additionalVolume != nil {
+			volumes = append(volumes, additionalVolume)
+		}
+	}
+	volumeMounts := make([]corev1.VolumeMount, 0, len(volumes))
+	podVolumes := make([]corev1.Volume, 0, len(volumes))
+	for _, v := range volumes {
+		volumeMounts = append(volumeMounts, v.VolumeMount())
+		podVolumes = append(podVolumes, v.Volume())
+	}
+
+	return BeatSidecar{
+		Container: corev1.Container{
+			Name:         CollectorContainerName,
+			Image:        container.ImageRepository(resource.GetNamespace(), container.EDOTCollectorImage, imageVersion),
+			Args:         []string{"--config", filepath.Join(collectorConfigDirPath, collectorConfigFilename)},
+			Env:          defaults.PodDownwardEnvVars(),
+			VolumeMounts: volumeMounts,
+		},
+		ConfigHash:   configHash,
+		ConfigSecret: configSecret,
+		Volumes:      podVolumes,
+	}, nil
+}
+
+// CollectorConfigSecretName returns the name of the Secret holding the EDOT collector configuration of a resource.
+func CollectorConfigSecretName(resourceName string, associationType commonv1.AssociationType) string {
+	return fmt.Sprintf("%s-%s-collector-config", resourceName, string(associationType))
+}
@@ -0,0 +1,160 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+const collectorTestAssociationConf = `
+{
+	"authSecretName": "monitored-default-monitoring-beat-es-mon-user",
+	"authSecretKey": "default-monitored-default-monitoring-beat-es-mon-user",
+	"isServiceAccount": false,
+	"caCertProvided": true,
+	"caSecretName": "monitored-es-monitoring-default-monitoring-ca",
+	"url": "https://monitoring-es-http.default.svc:9200",
+	"version": "9.1.0"
+}
+`
+
+func collectorTestElasticsearch(mode commonv1.MonitoringMode, withLogs bool) *esv1.Elasticsearch {
+	ref := commonv1.ObjectSelector{Name: "monitoring", Namespace: "default"}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "monitored",
+			Namespace: "default",
+			Annotations: map[string]string{
+				commonv1.ElasticsearchConfigAnnotationName(ref): collectorTestAssociationConf,
+			},
+		},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "9.1.0",
+			Monitoring: commonv1.Monitoring{
+				Mode:    mode,
+				Metrics: commonv1.MetricsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{ref}},
+			},
+		},
+	}
+	if withLogs {
+		es.Spec.Monitoring.Logs = commonv1.LogsMonitoring{ElasticsearchRefs: []commonv1.ObjectSelector{ref}}
+	}
+	return es
+}
+
+func collectorTestAuthSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "monitored-default-monitoring-beat-es-mon-user",
+			Namespace: "default",
+		},
+		Data: map[string][]byte{
+			"default-monitored-default-monitoring-beat-es-mon-user": []byte("password"),
+		},
+	}
+}
+
+func TestNewCollectorSidecar(t *testing.T) {
+	es := collectorTestElasticsearch(commonv1.CollectorMonitoringMode, true)
+	client := k8s.NewFakeClient(collectorTestAuthSecret())
+
+	sidecar, err := NewCollectorSidecar(
+		context.Background(), client, es, version.MustParse("9.1.0"),
+		"metricbeat.modules: []", "filebeat.inputs: []", metadata.Metadata{},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, CollectorContainerName, sidecar.Container.Name)
+	assert.Equal(t, "docker.elastic.co/elastic-agent/elastic-otel-collector-wolfi:9.1.0", sidecar.Container.Image)
+	assert.Equal(t, []string{"--config", "/etc/otel-collector-config/collector.yml"}, sidecar.Container.Args)
+	assert.Equal(t, "monitored-es-monitoring-collector-config", sidecar.ConfigSecret.Name)
+	assert.Equal(t, `exporters:
+  elasticsearch/logs:
+    endpoints:
+    - https://monitoring-es-http.default.svc:9200
+    mapping:
+      mode: bodymap
+    password: password
+    tls:
+      ca_file: /mnt/elastic-internal/es-monitoring-association/default/monitoring/certs/ca.crt
+    user: default-monitored-default-monitoring-beat-es-mon-user
+  elasticsearch/metrics:
+    endpoints:
+    - https://monitoring-es-http.default.svc:9200
+    mapping:
+      mode: bodymap
+    password: password
+    tls:
+      ca_file: /mnt/elastic-internal/es-monitoring-association/default/monitoring/certs/ca.crt
+    user: default-monitored-default-monitoring-beat-es-mon-user
+receivers:
+  filebeatreceiver/logs:
+    filebeat:
+      inputs: []
+    output:
+      otelconsumer: {}
+    path:
+      data: /var/lib/otel-collector/filebeatreceiver
+  metricbeatreceiver/metrics:
+    metricbeat:
+      modules: []
+    output:
+      otelconsumer: {}
+    path:
+      data: /var/lib/otel-collector/metricbeatreceiver
+service:
+  pipelines:
+    logs/logs:
+      exporters:
+      - elasticsearch/logs
+      receivers:
+      - filebeatreceiver/logs
+    logs/metrics:
+      exporters:
+      - elasticsearch/metrics
+      receivers:
+      - metricbeatreceiver/metrics
+`, string(sidecar.ConfigSecret.Data[collectorConfigFilename]))
+
+	// config, data and a single CA volume shared by both pipelines
+	mountPaths := make([]string, 0, len(sidecar.Container.VolumeMounts))
+	for _, m := range sidecar.Container.VolumeMounts {
+		mountPaths = append(mountPaths, m.MountPath)
+	}
+	assert.Equal(t, []string{
+		"/etc/otel-collector-config",
+		"/var/lib/otel-collector",
+		"/mnt/elastic-internal/es-monitoring-association/default/monitoring/certs",
+	}, mountPaths)
+	assert.Len(t, sidecar.Volumes, 3)
+}
+
+func TestNewCollectorSidecar_MetricsOnly(t *testing.T) {
+	es := collectorTestElasticsearch(commonv1.CollectorMonitoringMode, false)
+	client := k8s.NewFakeClient(collectorTestAuthSecret())
+
+	sidecar, err := NewCollectorSidecar(
+		context.Background(), client, es, version.MustParse("9.1.0"),
+		"metricbeat.modules: []", "", metadata.Metadata{},
+	)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sidecar.ConfigSecret.Data[collectorConfigFilename]), logsReceiver)
+
+	// no configuration at all
+	_, err = NewCollectorSidecar(context.Background(), client, es, version.MustParse("9.1.0"), "", "", metadata.Metadata{})
+	require.Error(t, err)
+}
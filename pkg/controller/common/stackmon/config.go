@@ -66,7 +66,7 @@ func newBeatConfig(
 	}
 
 	// name for the config secret and the associated config volume for the es pod
-	configSecretName := fmt.Sprintf("%s-%s-%s-config", resource.GetName(), string(assoc.AssociationType()), beatName)
+	configSecretName := BeatConfigSecretName(resource.GetName(), assoc.AssociationType(), beatName)
 	configName := configVolumeName(resource.GetName(), beatName)
 	configFilename := fmt.Sprintf("%s.yml", beatName)
 	configDirPath := fmt.Sprintf("/etc/%s-config", beatName)
@@ -115,6 +115,11 @@ func newBeatConfig(
 	}, err
 }
 
+// BeatConfigSecretName returns the name of the Secret holding the configuration of a beat sidecar of a resource.
+func BeatConfigSecretName(resourceName string, associationType commonv1.AssociationType, beatName string) string {
+	return fmt.Sprintf("%s-%s-%s-config", resourceName, string(associationType), beatName)
+}
+
 func buildOutputConfig(ctx context.Context, client k8s.Client, assoc commonv1.Association, imageVersion string) (map[string]any, volume.VolumeLike, error) {
 	credentials, err := association.ElasticsearchAuthSettings(ctx, client, assoc)
 	if err != nil {
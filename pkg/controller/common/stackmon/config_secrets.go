@@ -0,0 +1,117 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+// ConfigBuilders build the Stack Monitoring configuration of a resource in each monitoring mode.
+type ConfigBuilders struct {
+	// Metricbeat builds the Metricbeat sidecar of the Sidecars mode.
+	Metricbeat func() (BeatSidecar, error)
+	// Filebeat builds the Filebeat sidecar of the Sidecars mode.
+	Filebeat func() (BeatSidecar, error)
+	// Collector builds the EDOT collector sidecar of the Collector mode.
+	Collector func() (BeatSidecar, error)
+	// SharedCollector builds the shared collector configuration of the Shared mode.
+	SharedCollector func() (corev1.Secret, error)
+}
+
+// ReconcileConfigSecrets reconciles the secrets holding beats or EDOT collector configuration of the given resource,
+// and the shared collector of the namespace in the Shared monitoring mode. The secrets of the other modes are deleted
+// so that none is left behind when the mode changes or monitoring is disabled.
+func ReconcileConfigSecrets(
+	ctx context.Context,
+	c k8s.Client,
+	resource monitoring.HasMonitoring,
+	associationType commonv1.AssociationType,
+	builders ConfigBuilders,
+) error {
+	mode := resource.GetMonitoringMode()
+	metricsDefined := monitoring.IsMetricsDefined(resource)
+	logsDefined := monitoring.IsLogsDefined(resource)
+
+	if err := deleteUnusedConfigSecrets(ctx, c, resource, map[string]bool{
+		BeatConfigSecretName(resource.GetName(), associationType, metricbeatName): mode == commonv1.SidecarsMonitoringMode && metricsDefined,
+		BeatConfigSecretName(resource.GetName(), associationType, filebeatName):   mode == commonv1.SidecarsMonitoringMode && logsDefined,
+		CollectorConfigSecretName(resource.GetName(), associationType):            mode == commonv1.CollectorMonitoringMode && (metricsDefined || logsDefined),
+	}); err != nil {
+		return err
+	}
+	if mode != commonv1.SharedMonitoringMode || !metricsDefined {
+		// remove the shared collector configuration if the resource does not use it (anymore)
+		if err := DeleteSharedCollectorConfig(ctx, c, k8s.ExtractNamespacedName(resource), associationType); err != nil {
+			return err
+		}
+	}
+
+	isMonitoringReconcilable, err := monitoring.IsReconcilable(resource)
+	if err != nil {
+		return err
+	}
+	if !isMonitoringReconcilable {
+		return nil
+	}
+
+	switch mode {
+	case commonv1.SharedMonitoringMode:
+		if !metricsDefined {
+			return nil
+		}
+		secret, err := builders.SharedCollector()
+		if err != nil {
+			return err
+		}
+		return ReconcileSharedCollectorConfig(ctx, c, resource, secret)
+	case commonv1.CollectorMonitoringMode:
+		return reconcileSidecarConfigSecret(ctx, c, resource, builders.Collector)
+	}
+
+	if metricsDefined {
+		if err := reconcileSidecarConfigSecret(ctx, c, resource, builders.Metricbeat); err != nil {
+			return err
+		}
+	}
+	if logsDefined {
+		if err := reconcileSidecarConfigSecret(ctx, c, resource, builders.Filebeat); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcileSidecarConfigSecret reconciles the configuration secret of the sidecar built by the given function.
+func reconcileSidecarConfigSecret(ctx context.Context, c k8s.Client, resource monitoring.HasMonitoring, build func() (BeatSidecar, error)) error {
+	sidecar, err := build()
+	if err != nil {
+		return err
+	}
+	_, err = reconciler.ReconcileSecret(ctx, c, sidecar.ConfigSecret, resource)
+	return err
+}
+
+// deleteUnusedConfigSecrets deletes the given configuration secrets of a resource which are not in use.
+func deleteUnusedConfigSecrets(ctx context.Context, c k8s.Client, resource monitoring.HasMonitoring, inUse map[string]bool) error {
+	for _, secretName := range slices.Sorted(maps.Keys(inUse)) {
+		if inUse[secretName] {
+			continue
+		}
+		if err := k8s.DeleteSecretIfExists(ctx, c, types.NamespacedName{Namespace: resource.GetNamespace(), Name: secretName}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
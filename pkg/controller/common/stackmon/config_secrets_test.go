@@ -0,0 +1,88 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func testConfigBuilders(ctx context.Context, client k8s.Client, es *esv1.Elasticsearch) ConfigBuilders {
+	v := version.MustParse(es.Spec.Version)
+	return ConfigBuilders{
+		Metricbeat: func() (BeatSidecar, error) {
+			return NewMetricBeatSidecar(ctx, client, es, v, nil, "metricbeat.modules: []", metadata.Metadata{})
+		},
+		Filebeat: func() (BeatSidecar, error) {
+			return NewFileBeatSidecar(ctx, client, es, es.Spec.Version, "filebeat.inputs: []", nil, metadata.Metadata{})
+		},
+		Collector: func() (BeatSidecar, error) {
+			return NewCollectorSidecar(ctx, client, es, v, "metricbeat.modules: []", "filebeat.inputs: []", metadata.Metadata{})
+		},
+		SharedCollector: func() (corev1.Secret, error) {
+			return NewSharedCollectorConfig(ctx, client, es, v, "metricbeat.modules: []", nil, metadata.Metadata{})
+		},
+	}
+}
+
+func TestReconcileConfigSecrets(t *testing.T) {
+	ctx := context.Background()
+	client := k8s.NewFakeClient(collectorTestAuthSecret())
+	metricbeatSecret := "monitored-es-monitoring-metricbeat-config"
+	filebeatSecret := "monitored-es-monitoring-filebeat-config"
+	collectorSecret := "monitored-es-monitoring-collector-config"
+	sharedSecret := "monitored-es-monitoring-shared-collector-config"
+
+	assertSecrets := func(t *testing.T, expected ...string) {
+		t.Helper()
+		for _, name := range []string{metricbeatSecret, filebeatSecret, collectorSecret, sharedSecret} {
+			var secret corev1.Secret
+			err := client.Get(ctx, types.NamespacedName{Namespace: "default", Name: name}, &secret)
+			if slices.Contains(expected, name) {
+				assert.NoError(t, err, name)
+			} else {
+				assert.True(t, apierrors.IsNotFound(err), name)
+			}
+		}
+	}
+	reconcileMode := func(t *testing.T, es *esv1.Elasticsearch) {
+		t.Helper()
+		require.NoError(t, ReconcileConfigSecrets(ctx, client, es, commonv1.EsMonitoringAssociationType, testConfigBuilders(ctx, client, es)))
+	}
+
+	reconcileMode(t, collectorTestElasticsearch(commonv1.SidecarsMonitoringMode, true))
+	assertSecrets(t, metricbeatSecret, filebeatSecret)
+
+	// switching modes deletes the configuration of the previous mode
+	reconcileMode(t, collectorTestElasticsearch(commonv1.CollectorMonitoringMode, true))
+	assertSecrets(t, collectorSecret)
+
+	reconcileMode(t, collectorTestElasticsearch(commonv1.SharedMonitoringMode, true))
+	assertSecrets(t, sharedSecret)
+	var d appsv1.Deployment
+	deploymentKey := types.NamespacedName{Namespace: "default", Name: SharedCollectorName}
+	require.NoError(t, client.Get(ctx, deploymentKey, &d))
+
+	// disabling monitoring deletes all the configurations and the shared collector
+	es := collectorTestElasticsearch(commonv1.SharedMonitoringMode, false)
+	es.Spec.Monitoring = commonv1.Monitoring{}
+	reconcileMode(t, es)
+	assertSecrets(t)
+	require.True(t, apierrors.IsNotFound(client.Get(ctx, deploymentKey, &d)))
+}
@@ -16,6 +16,7 @@ type HasMonitoring interface {
 	commonv1.HasIdentityLabels
 	GetMonitoringMetricsRefs() []commonv1.ObjectSelector
 	GetMonitoringLogsRefs() []commonv1.ObjectSelector
+	GetMonitoringMode() commonv1.MonitoringMode
 	MonitoringAssociation(ref commonv1.ObjectSelector) commonv1.Association
 }
 
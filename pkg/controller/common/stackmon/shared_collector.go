@@ -0,0 +1,311 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// SharedCollectorName is the name of the collector Deployment shared by the monitored resources of a namespace.
+	SharedCollectorName = "eck-stack-monitoring-collector"
+	// SharedCollectorLabelName marks the Secrets holding the shared collector configuration of a monitored resource.
+	SharedCollectorLabelName = "stackmon.k8s.elastic.co/shared-collector"
+
+	// sharedCollectorVolumesAnnotation lists the Secrets to mount in the shared collector for a monitored resource.
+	sharedCollectorVolumesAnnotation = "stackmon.k8s.elastic.co/shared-collector-volumes"
+	// sharedCollectorVersionAnnotation holds the version of the collector to run for a monitored resource.
+	sharedCollectorVersionAnnotation = "stackmon.k8s.elastic.co/shared-collector-version"
+	// sharedCollectorConfigHashAnnotation holds a hash of the shared collector configuration to rotate its Pods when changed.
+	sharedCollectorConfigHashAnnotation = "stackmon.k8s.elastic.co/config-hash"
+
+	sharedCollectorType          = "stack-monitoring-collector"
+	sharedCollectorConfigDirPath = "/etc/stack-monitoring-collector"
+)
+
+var (
+	// sharedCollectorResources mirror the default resources of the AutoOps agent, which runs the same collector.
+	sharedCollectorResources = corev1.ResourceRequirements{
+		Limits: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: resource.MustParse("400Mi"),
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+		},
+		Requests: map[corev1.ResourceName]resource.Quantity{
+			corev1.ResourceMemory: resource.MustParse("400Mi"),
+			corev1.ResourceCPU:    resource.MustParse("200m"),
+		},
+	}
+)
+
+// sharedCollectorVolume is a Secret mounted in the shared collector for a monitored resource.
+type sharedCollectorVolume struct {
+	SecretName string `json:"secretName"`
+	MountPath  string `json:"mountPath"`
+}
+
+// SharedCollectorConfigSecretName returns the name of the Secret holding the shared collector configuration of a resource.
+func SharedCollectorConfigSecretName(resourceName string, associationType commonv1.AssociationType) string {
+	return fmt.Sprintf("%s-%s-shared-collector-config", resourceName, string(associationType))
+}
+
+// NewSharedCollectorConfig builds the Secret holding the part of the shared collector configuration which scrapes
+// metrics from the given resource. The Metricbeat configuration must reach the resource through its Service, and
+// caVolume, if any, must be the CA volume of the resource used to render this configuration: it is mounted at the same
+// path in the shared collector.
+func NewSharedCollectorConfig(
+	ctx context.Context,
+	client k8s.Client,
+	resource monitoring.HasMonitoring,
+	imageVersion semver.Version,
+	metricbeatConfig string,
+	caVolume volume.VolumeLike,
+	meta metadata.Metadata,
+) (corev1.Secret, error) {
+	pipelines, err := collectorPipelines(resource, metricbeatConfig, "")
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	associationType := pipelines[0].assoc.AssociationType()
+	id := fmt.Sprintf("%s-%s", associationType, resource.GetName())
+	configBytes, caVolumes, err := buildCollectorConfig(ctx, client, id, pipelines, associationCAVolume)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	if caVolume != nil {
+		caVolumes = append(caVolumes, caVolume)
+	}
+	volumes := make([]sharedCollectorVolume, 0, len(caVolumes))
+	for _, v := range caVolumes {
+		secret := v.Volume().Secret
+		if secret == nil {
+			return corev1.Secret{}, fmt.Errorf("volume %s is not a Secret volume and cannot be mounted in the shared collector", v.Name())
+		}
+		volumes = append(volumes, sharedCollectorVolume{SecretName: secret.SecretName, MountPath: v.VolumeMount().MountPath})
+	}
+	volumesAnnotation, err := json.Marshal(volumes)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+
+	meta = meta.Merge(metadata.Metadata{
+		Labels: resource.GetIdentityLabels(),
+		Annotations: map[string]string{
+			sharedCollectorVolumesAnnotation: string(volumesAnnotation),
+			sharedCollectorVersionAnnotation: imageVersion.String(),
+		},
+	})
+	meta.Labels[SharedCollectorLabelName] = "true"
+	return corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        SharedCollectorConfigSecretName(resource.GetName(), associationType),
+			Namespace:   resource.GetNamespace(),
+			Labels:      meta.Labels,
+			Annotations: meta.Annotations,
+		},
+		Data: map[string][]byte{
+			collectorConfigFilename: configBytes,
+		},
+	}, nil
+}
+
+// ReconcileSharedCollectorConfig reconciles the shared collector configuration of the given resource, then the shared
+// collector of the namespace.
+func ReconcileSharedCollectorConfig(ctx context.Context, c k8s.Client, resource monitoring.HasMonitoring, expected corev1.Secret) error {
+	if _, err := reconciler.ReconcileSecret(ctx, c, expected, resource); err != nil {
+		return err
+	}
+	return ReconcileSharedCollector(ctx, c, resource.GetNamespace())
+}
+
+// DeleteSharedCollectorConfig deletes the shared collector configuration of a resource, if any, and reconciles the
+// shared collector of the namespace accordingly.
+func DeleteSharedCollectorConfig(ctx context.Context, c k8s.Client, resource types.NamespacedName, associationType commonv1.AssociationType) error {
+	secretName := SharedCollectorConfigSecretName(resource.Name, associationType)
+	var secret corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: resource.Namespace, Name: secretName}, &secret)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.Delete(ctx, &secret); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	// the deleted Secret may still be returned by the cache
+	return reconcileSharedCollector(ctx, c, resource.Namespace, secretName)
+}
+
+// ReconcileSharedCollector reconciles the collector Deployment shared by the monitored resources of the given namespace
+// from their shared collector configurations. The Deployment is deleted when no resource uses it anymore.
+func ReconcileSharedCollector(ctx context.Context, c k8s.Client, namespace string) error {
+	return reconcileSharedCollector(ctx, c, namespace)
+}
+
+func reconcileSharedCollector(ctx context.Context, c k8s.Client, namespace string, ignoredSecrets ...string) error {
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, client.InNamespace(namespace), client.MatchingLabels{SharedCollectorLabelName: "true"}); err != nil {
+		return err
+	}
+	configs := make([]corev1.Secret, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		if secret.DeletionTimestamp.IsZero() && !slices.Contains(ignoredSecrets, secret.Name) {
+			configs = append(configs, secret)
+		}
+	}
+
+	if len(configs) == 0 {
+		d := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: SharedCollectorName}}
+		if err := c.Delete(ctx, &d); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	expected, err := buildSharedCollector(namespace, configs)
+	if err != nil {
+		return err
+	}
+	ulog.FromContext(ctx).V(1).Info("Reconciling shared Stack Monitoring collector", "namespace", namespace, "configurations", len(configs))
+	reconciled, err := deployment.Reconcile(ctx, c, expected, nil)
+	if err != nil {
+		return err
+	}
+	// the collector is owned by all the configuration Secrets, themselves owned by the monitored resources, so that it
+	// is garbage collected with the last of them
+	owners := sharedCollectorOwners(configs)
+	if equality.Semantic.DeepEqual(reconciled.OwnerReferences, owners) {
+		return nil
+	}
+	reconciled.OwnerReferences = owners
+	return c.Update(ctx, &reconciled)
+}
+
+// sharedCollectorOwners returns the owner references of the shared collector running the given configurations.
+func sharedCollectorOwners(configs []corev1.Secret) []metav1.OwnerReference {
+	owners := make([]metav1.OwnerReference, 0, len(configs))
+	for _, config := range configs {
+		owners = append(owners, metav1.OwnerReference{
+			APIVersion: "v1",
+			Kind:       "Secret",
+			Name:       config.Name,
+			UID:        config.UID,
+		})
+	}
+	return owners
+}
+
+// buildSharedCollector builds the shared collector Deployment running the given configurations.
+func buildSharedCollector(namespace string, configs []corev1.Secret) (appsv1.Deployment, error) {
+	slices.SortFunc(configs, func(a, b corev1.Secret) int { return strings.Compare(a.Name, b.Name) })
+
+	var collectorVersion *semver.Version
+	configHash := fnv.New32a()
+	args := make([]string, 0, 2*len(configs))
+	volumes := map[string]volume.VolumeLike{}
+	for _, config := range configs {
+		v, err := version.Parse(config.Annotations[sharedCollectorVersionAnnotation])
+		if err != nil {
+			return appsv1.Deployment{}, fmt.Errorf("invalid shared collector version in Secret %s: %w", config.Name, err)
+		}
+		// run the most recent version required by the monitored resources
+		if collectorVersion == nil || v.GT(*collectorVersion) {
+			collectorVersion = &v
+		}
+
+		configDirPath := filepath.Join(sharedCollectorConfigDirPath, config.Name)
+		volumes[configDirPath] = volume.NewSecretVolumeWithMountPath(config.Name, sharedCollectorVolumeName(configDirPath), configDirPath)
+		args = append(args, "--config", filepath.Join(configDirPath, collectorConfigFilename))
+		_, _ = configHash.Write(config.Data[collectorConfigFilename])
+
+		var caVolumes []sharedCollectorVolume
+		if err := json.Unmarshal([]byte(config.Annotations[sharedCollectorVolumesAnnotation]), &caVolumes); err != nil {
+			return appsv1.Deployment{}, fmt.Errorf("invalid shared collector volumes in Secret %s: %w", config.Name, err)
+		}
+		for _, caVolume := range caVolumes {
+			volumes[caVolume.MountPath] = volume.NewSecretVolumeWithMountPath(caVolume.SecretName, sharedCollectorVolumeName(caVolume.MountPath), caVolume.MountPath)
+		}
+	}
+
+	// EmptyDir volume so that the Beats receivers do not write in the container image
+	volumes[collectorDataPath] = volume.NewEmptyDirVolume(collectorDataVolumeName, collectorDataPath)
+
+	podVolumes := make([]corev1.Volume, 0, len(volumes))
+	volumeMounts := make([]corev1.VolumeMount, 0, len(volumes))
+	for _, mountPath := range slices.Sorted(maps.Keys(volumes)) {
+		podVolumes = append(podVolumes, volumes[mountPath].Volume())
+		volumeMounts = append(volumeMounts, volumes[mountPath].VolumeMount())
+	}
+
+	labels := map[string]string{commonv1.TypeLabelName: sharedCollectorType}
+	meta := metadata.Metadata{
+		Labels:      labels,
+		Annotations: map[string]string{sharedCollectorConfigHashAnnotation: fmt.Sprint(configHash.Sum32())},
+	}
+	builder := defaults.NewPodTemplateBuilder(corev1.PodTemplateSpec{}, sharedCollectorType).
+		WithArgs(args...).
+		WithLabels(meta.Labels).
+		WithAnnotations(meta.Annotations).
+		WithDockerImage("", container.ImageRepository(namespace, container.EDOTCollectorImage, *collectorVersion)).
+		WithImagePullSecrets(container.ImagePullSecrets(namespace)...).
+		WithEnv(defaults.PodDownwardEnvVars()...).
+		WithResources(sharedCollectorResources).
+		WithVolumes(podVolumes...).
+		WithVolumeMounts(volumeMounts...).
+		WithContainersSecurityContext(corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			Privileged:             ptr.To(false),
+			ReadOnlyRootFilesystem: ptr.To(true),
+		})
+
+	return deployment.New(deployment.Params{
+		Name:            SharedCollectorName,
+		Namespace:       namespace,
+		Selector:        labels,
+		Metadata:        meta,
+		PodTemplateSpec: builder.PodTemplate,
+		Replicas:        1,
+	}), nil
+}
+
+// sharedCollectorVolumeName returns a volume name unique for the given mount path.
+func sharedCollectorVolumeName(mountPath string) string {
+	return fmt.Sprintf("shared-%x", sha256.Sum256([]byte(mountPath)))[0:20]
+}
@@ -0,0 +1,85 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const sharedCollectorControllerName = "stackmon-shared-collector-controller"
+
+// AddSharedCollector creates a new controller reconciling the shared Stack Monitoring collectors and adds it to the
+// manager. The collector of a namespace is reconciled when the configuration of a monitored resource changes, and
+// repaired when it is modified or deleted.
+func AddSharedCollector(mgr manager.Manager, params operator.Parameters) error {
+	r := &ReconcileSharedCollectorDeployment{
+		Client:     mgr.GetClient(),
+		Parameters: params,
+	}
+	c, err := common.NewLeaderController(mgr, sharedCollectorControllerName, r, params)
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{},
+		handler.TypedEnqueueRequestsFromMapFunc[*corev1.Secret](func(_ context.Context, secret *corev1.Secret) []reconcile.Request {
+			if secret.Labels[SharedCollectorLabelName] != "true" {
+				return nil
+			}
+			return []reconcile.Request{sharedCollectorRequest(secret.Namespace)}
+		}),
+	)); err != nil {
+		return err
+	}
+	return c.Watch(source.Kind(mgr.GetCache(), &appsv1.Deployment{},
+		handler.TypedEnqueueRequestsFromMapFunc[*appsv1.Deployment](func(_ context.Context, d *appsv1.Deployment) []reconcile.Request {
+			if d.Name != SharedCollectorName {
+				return nil
+			}
+			return []reconcile.Request{sharedCollectorRequest(d.Namespace)}
+		}),
+	))
+}
+
+func sharedCollectorRequest(namespace string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: SharedCollectorName}}
+}
+
+var _ reconcile.Reconciler = (*ReconcileSharedCollectorDeployment)(nil)
+
+// ReconcileSharedCollectorDeployment reconciles the shared Stack Monitoring collector of a namespace.
+type ReconcileSharedCollectorDeployment struct {
+	k8s.Client
+	operator.Parameters
+
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reconciles the shared collector Deployment of the namespace of the request from the shared collector
+// configurations of this namespace.
+func (r *ReconcileSharedCollectorDeployment) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, sharedCollectorControllerName, "deployment_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	if err := ReconcileSharedCollector(ctx, r.Client, request.Namespace); err != nil {
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stackmon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func TestNewSharedCollectorConfig(t *testing.T) {
+	es := collectorTestElasticsearch(commonv1.SharedMonitoringMode, false)
+	client := k8s.NewFakeClient(collectorTestAuthSecret())
+
+	esCAVolume := volume.NewSecretVolumeWithMountPath("monitored-es-http-certs-public", "es-ca", "/mnt/elastic-internal/es-monitoring/certs")
+	secret, err := NewSharedCollectorConfig(context.Background(), client, es, version.MustParse("9.1.0"), "metricbeat.modules: []", esCAVolume, metadata.Metadata{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "monitored-es-monitoring-shared-collector-config", secret.Name)
+	assert.Equal(t, "true", secret.Labels[SharedCollectorLabelName])
+	assert.Equal(t, "9.1.0", secret.Annotations[sharedCollectorVersionAnnotation])
+	assert.JSONEq(t, `[
+		{"secretName":"monitored-es-monitoring-default-monitoring-ca","mountPath":"/mnt/elastic-internal/es-monitoring-association/default/monitoring/certs"},
+		{"secretName":"monitored-es-http-certs-public","mountPath":"/mnt/elastic-internal/es-monitoring/certs"}
+	]`, secret.Annotations[sharedCollectorVolumesAnnotation])
+	// component names are prefixed to be merged with the configurations of other resources
+	config := string(secret.Data[collectorConfigFilename])
+	assert.Contains(t, config, "metricbeatreceiver/es-monitoring-monitored-metrics:")
+	assert.Contains(t, config, "elasticsearch/es-monitoring-monitored-metrics:")
+	assert.Contains(t, config, "logs/es-monitoring-monitored-metrics:")
+	assert.Contains(t, config, "data: /var/lib/otel-collector/es-monitoring-monitored/metricbeatreceiver")
+}
+
+func TestReconcileSharedCollector(t *testing.T) {
+	ctx := context.Background()
+	client := k8s.NewFakeClient(collectorTestAuthSecret())
+	deploymentKey := types.NamespacedName{Namespace: "default", Name: SharedCollectorName}
+
+	// no configuration: no collector
+	require.NoError(t, ReconcileSharedCollector(ctx, client, "default"))
+	var d appsv1.Deployment
+	require.True(t, apierrors.IsNotFound(client.Get(ctx, deploymentKey, &d)))
+
+	// one configuration per monitored resource
+	for _, tt := range []struct {
+		name    string
+		version string
+	}{
+		{name: "monitored", version: "9.1.0"},
+		{name: "other", version: "9.2.0"},
+	} {
+		es := collectorTestElasticsearch(commonv1.SharedMonitoringMode, false)
+		es.Name = tt.name
+		secret, err := NewSharedCollectorConfig(ctx, client, es, version.MustParse(tt.version), "metricbeat.modules: []", nil, metadata.Metadata{})
+		require.NoError(t, err)
+		require.NoError(t, ReconcileSharedCollectorConfig(ctx, client, es, secret))
+	}
+
+	require.NoError(t, client.Get(ctx, deploymentKey, &d))
+	require.Len(t, d.Spec.Template.Spec.Containers, 1)
+	collector := d.Spec.Template.Spec.Containers[0]
+	// the most recent version is used
+	assert.Equal(t, "docker.elastic.co/elastic-agent/elastic-otel-collector-wolfi:9.2.0", collector.Image)
+	assert.Equal(t, []string{
+		"--config", "/etc/stack-monitoring-collector/monitored-es-monitoring-shared-collector-config/collector.yml",
+		"--config", "/etc/stack-monitoring-collector/other-es-monitoring-shared-collector-config/collector.yml",
+	}, collector.Args)
+	// both configurations, a single CA volume and the data volume
+	assert.Len(t, d.Spec.Template.Spec.Volumes, 4)
+	configHash := d.Spec.Template.Annotations[sharedCollectorConfigHashAnnotation]
+	assert.NotEmpty(t, configHash)
+	// owned by both configurations to be garbage collected with the last of them
+	assert.Equal(t, []string{"monitored-es-monitoring-shared-collector-config", "other-es-monitoring-shared-collector-config"}, ownerNames(d))
+
+	// the controller recreates a deleted collector
+	require.NoError(t, client.Delete(ctx, &d))
+	r := &ReconcileSharedCollectorDeployment{Client: client}
+	_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: deploymentKey})
+	require.NoError(t, err)
+	require.NoError(t, client.Get(ctx, deploymentKey, &d))
+	assert.Equal(t, configHash, d.Spec.Template.Annotations[sharedCollectorConfigHashAnnotation])
+
+	// removing a configuration updates the collector
+	require.NoError(t, DeleteSharedCollectorConfig(ctx, client, types.NamespacedName{Namespace: "default", Name: "other"}, commonv1.EsMonitoringAssociationType))
+	require.NoError(t, client.Get(ctx, deploymentKey, &d))
+	collector = d.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "docker.elastic.co/elastic-agent/elastic-otel-collector-wolfi:9.1.0", collector.Image)
+	assert.Equal(t, []string{"--config", "/etc/stack-monitoring-collector/monitored-es-monitoring-shared-collector-config/collector.yml"}, collector.Args)
+	assert.NotEqual(t, configHash, d.Spec.Template.Annotations[sharedCollectorConfigHashAnnotation])
+	assert.Equal(t, []string{"monitored-es-monitoring-shared-collector-config"}, ownerNames(d))
+
+	// removing the last configuration deletes the collector
+	require.NoError(t, DeleteSharedCollectorConfig(ctx, client, types.NamespacedName{Namespace: "default", Name: "monitored"}, commonv1.EsMonitoringAssociationType))
+	require.True(t, apierrors.IsNotFound(client.Get(ctx, deploymentKey, &d)))
+
+	// deleting a missing configuration is a no-op
+	require.NoError(t, DeleteSharedCollectorConfig(ctx, client, types.NamespacedName{Namespace: "default", Name: "monitored"}, commonv1.EsMonitoringAssociationType))
+}
+
+func ownerNames(d appsv1.Deployment) []string {
+	names := make([]string, 0, len(d.OwnerReferences))
+	for _, owner := range d.OwnerReferences {
+		names = append(names, owner.Name)
+	}
+	return names
+}
@@ -26,6 +26,11 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 )
 
+const (
+	metricbeatName = "metricbeat"
+	filebeatName   = "filebeat"
+)
+
 func NewMetricBeatSidecar(
 	ctx context.Context,
 	client k8s.Client,
@@ -35,10 +40,10 @@ func NewMetricBeatSidecar(
 	baseConfig string,
 	meta metadata.Metadata,
 ) (BeatSidecar, error) {
-	image := container.ImageRepository(container.MetricbeatImage, imageVersion)
+	image := container.ImageRepository(resource.GetNamespace(), container.MetricbeatImage, imageVersion)
 	// EmptyDir volume so that MetricBeat does not write in the container image, which allows ReadOnlyRootFilesystem: true
 	emptyDir := volume.NewEmptyDirVolume("metricbeat-data", "/usr/share/metricbeat/data")
-	return NewBeatSidecar(ctx, client, "metricbeat", image, imageVersion.String(), resource, monitoring.GetMetricsAssociation(resource), baseConfig, meta, caVolume, emptyDir)
+	return NewBeatSidecar(ctx, client, metricbeatName, image, imageVersion.String(), resource, monitoring.GetMetricsAssociation(resource), baseConfig, meta, caVolume, emptyDir)
 }
 
 func NewFileBeatSidecar(
@@ -54,10 +59,10 @@ func NewFileBeatSidecar(
 	if err != nil {
 		return BeatSidecar{}, err // error unlikely and should have been caught during validation
 	}
-	image := container.ImageRepository(container.FilebeatImage, v)
+	image := container.ImageRepository(resource.GetNamespace(), container.FilebeatImage, v)
 	// EmptyDir volume so that FileBeat does not write in the container image, which allows ReadOnlyRootFilesystem: true
 	emptyDir := volume.NewEmptyDirVolume("filebeat-data", "/usr/share/filebeat/data")
-	return NewBeatSidecar(ctx, client, "filebeat", image, imageVersion, resource, monitoring.GetLogsAssociation(resource), baseConfig, meta, additionalVolume, emptyDir)
+	return NewBeatSidecar(ctx, client, filebeatName, image, imageVersion, resource, monitoring.GetLogsAssociation(resource), baseConfig, meta, additionalVolume, emptyDir)
 }
 
 // BeatSidecar helps with building a beat sidecar container to monitor an Elastic Stack application. It focuses on
@@ -10,13 +10,16 @@ import (
 	"github.com/blang/semver/v4"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 )
 
 const (
 	UnsupportedVersionMsg       = "Unsupported version for Stack Monitoring. Required >= %s."
+	UnsupportedModeVersionMsg   = "Unsupported version for the %s Stack Monitoring mode. Required >= %s."
 	InvalidElasticsearchRefsMsg = "Only one Elasticsearch reference is supported for %s Stack Monitoring"
+	InvalidSharedModeLogsMsg    = "Logs cannot be collected in the Shared Stack Monitoring mode"
 
 	InvalidKibanaElasticsearchRefForStackMonitoringMsg = "Kibana must be associated to an Elasticsearch cluster through elasticsearchRef in order to enable monitoring metrics features"
 	InvalidBeatsElasticsearchRefForStackMonitoringMsg  = "Beats must be associated to an Elasticsearch cluster through elasticsearchRef in order to enable monitoring metrics features"
@@ -27,6 +30,10 @@ var (
 	// This requirement comes from the fact that we configure Elasticsearch to write logs to disk for Filebeat
 	// via the env var ES_LOG_STYLE available from this version.
 	MinStackVersion = version.MustParse("7.14.0-SNAPSHOT")
+
+	// MinCollectorStackVersion is the minimum Stack version to collect monitoring data with the EDOT collector, which
+	// runs the Metricbeat and Filebeat configurations through Beats receivers, in the Collector and Shared modes.
+	MinCollectorStackVersion = version.MustParse("9.1.0-SNAPSHOT")
 )
 
 // Validate validates that the resource version is supported for Stack Monitoring and that there is exactly one
@@ -41,6 +48,17 @@ func Validate(resource monitoring.HasMonitoring, version string, minVersion vers
 				fmt.Sprintf(UnsupportedVersionMsg, finalMinStackVersion)))
 		}
 	}
+	if mode := resource.GetMonitoringMode(); monitoring.IsDefined(resource) && mode != commonv1.SidecarsMonitoringMode {
+		if err := IsSupportedVersion(version, MinCollectorStackVersion); err != nil {
+			finalMinCollectorStackVersion, _ := semver.FinalizeVersion(MinCollectorStackVersion.String()) // discards prerelease suffix
+			errs = append(errs, field.Invalid(field.NewPath("spec").Child("version"), version,
+				fmt.Sprintf(UnsupportedModeVersionMsg, mode, finalMinCollectorStackVersion)))
+		}
+		if mode == commonv1.SharedMonitoringMode && monitoring.IsLogsDefined(resource) {
+			errs = append(errs, field.Forbidden(field.NewPath("spec").Child("monitoring").Child("logs").Child("elasticsearchRefs"),
+				InvalidSharedModeLogsMsg))
+		}
+	}
 	refs := resource.GetMonitoringMetricsRefs()
 	if monitoring.AreEsRefsDefined(refs) && len(refs) != 1 {
 		errs = append(errs, field.Invalid(field.NewPath("spec").Child("monitoring").Child("metrics").Child("elasticsearchRefs"),
@@ -93,6 +93,82 @@ func TestValidate(t *testing.T) {
 			},
 			isErr: true,
 		},
+		{
+			name: "with the collector mode",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "9.1.0",
+					Monitoring: commonv1.Monitoring{
+						Mode: commonv1.CollectorMonitoringMode,
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+						Logs: commonv1.LogsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+					},
+				},
+			},
+			isErr: false,
+		},
+		{
+			name: "with the collector mode and a not supported version",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "8.19.0",
+					Monitoring: commonv1.Monitoring{
+						Mode: commonv1.CollectorMonitoringMode,
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "with the shared mode",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "9.1.0",
+					Monitoring: commonv1.Monitoring{
+						Mode: commonv1.SharedMonitoringMode,
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+					},
+				},
+			},
+			isErr: false,
+		},
+		{
+			name: "with the shared mode and logs",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version: "9.1.0",
+					Monitoring: commonv1.Monitoring{
+						Mode: commonv1.SharedMonitoringMode,
+						Metrics: commonv1.MetricsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+						Logs: commonv1.LogsMonitoring{
+							ElasticsearchRefs: []commonv1.ObjectSelector{{Name: "m1", Namespace: "b"}},
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "with the shared mode but without monitoring",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					Version:    "8.19.0",
+					Monitoring: commonv1.Monitoring{Mode: commonv1.SharedMonitoringMode},
+				},
+			},
+			isErr: false,
+		},
 	}
 
 	for _, tc := range tests {
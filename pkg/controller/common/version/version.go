@@ -38,6 +38,8 @@ var (
 	SupportedMapsVersions            = MinMaxVersion{Min: From(7, 11, 0), Max: From(9, 99, 99)}
 	SupportedPackageRegistryVersions = MinMaxVersion{Min: From(7, 17, 8), Max: From(9, 99, 99)}
 	SupportedLogstashVersions        = MinMaxVersion{Min: From(8, 12, 0), Max: From(9, 99, 99)}
+	// The EDOT Collector image is published from 8.18.0 onwards.
+	SupportedOTelCollectorVersions = MinMaxVersion{Min: From(8, 18, 0), Max: From(9, 99, 99)}
 
 	// AutoOpsAgent was introduced in 9.1.0, but 9.2.1 is now required due to
 	// performance optimizations and the lack of the healthcheckv2 extension in earlier versions.
@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package watches
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// WatchHorizontalPodAutoscalers updates the given controller to enqueue reconciliation requests triggered by spec changes
+// on HorizontalPodAutoscalers controlled by the given owner type. Status updates, which happen at every scaling
+// evaluation, are ignored.
+func WatchHorizontalPodAutoscalers(mgr manager.Manager, c controller.Controller, ownerType client.Object) error {
+	return c.Watch(source.Kind(
+		mgr.GetCache(),
+		&autoscalingv2.HorizontalPodAutoscaler{},
+		handler.TypedEnqueueRequestForOwner[*autoscalingv2.HorizontalPodAutoscaler](
+			mgr.GetScheme(), mgr.GetRESTMapper(), ownerType, handler.OnlyControllerOwner(),
+		),
+		predicate.TypedGenerationChangedPredicate[*autoscalingv2.HorizontalPodAutoscaler]{},
+	))
+}
@@ -0,0 +1,187 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package conversion
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	pkgerrors "github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+)
+
+// HubContentAnnotation holds the fields of an object in the hub version which cannot be represented in the spoke version
+// the object was converted to, so that they can be restored when the object is converted back to the hub version.
+const HubContentAnnotation = "conversion.k8s.elastic.co/hub-content"
+
+// NewJSONSpokeConverter returns a converter between the hub version and a spoke version of a resource whose schemas share
+// the same JSON representation, the spoke version being a subset of the hub version. Converting an object to the spoke
+// version and back to the hub version does not lose the fields only known by the hub version.
+func NewJSONSpokeConverter[H, S client.Object](spoke S) conversion.SpokeConverter[H] {
+	return conversion.NewSpokeConverter(
+		spoke,
+		func(_ context.Context, hub H, spoke S) error {
+			return hubToSpoke(hub, spoke)
+		},
+		func(_ context.Context, spoke S, hub H) error {
+			return spokeToHub(spoke, hub)
+		},
+	)
+}
+
+// hubToSpoke converts hub into spoke, keeping the fields of hub spoke cannot represent in an annotation.
+func hubToSpoke(hub, spoke client.Object) error {
+	hubContent, err := toMap(hub)
+	if err != nil {
+		return err
+	}
+	removeHubContentAnnotation(hubContent)
+	if err := fromMap(hubContent, spoke); err != nil {
+		return err
+	}
+
+	// find out what would be lost when converting back to the hub version
+	roundTripped, err := roundTrip(spoke, hub)
+	if err != nil {
+		return err
+	}
+	lost := lostFields(hubContent, roundTripped)
+	if len(lost) == 0 {
+		return nil
+	}
+	serialized, err := json.Marshal(lost)
+	if err != nil {
+		return err
+	}
+	annotations := spoke.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[HubContentAnnotation] = string(serialized)
+	spoke.SetAnnotations(annotations)
+	return nil
+}
+
+// spokeToHub converts spoke into hub, restoring the fields of the hub version previously kept in an annotation.
+func spokeToHub(spoke, hub client.Object) error {
+	content, err := toMap(spoke)
+	if err != nil {
+		return err
+	}
+	kept, hasKept := hubContent(spoke)
+	removeHubContentAnnotation(content)
+	if hasKept {
+		var lost map[string]any
+		if err := json.Unmarshal([]byte(kept), &lost); err != nil {
+			return pkgerrors.Wrapf(err, "while parsing annotation %s", HubContentAnnotation)
+		}
+		restoreFields(content, lost)
+	}
+	return fromMap(content, hub)
+}
+
+// roundTrip returns the content of spoke once converted into the type of hub.
+func roundTrip(spoke, hub client.Object) (map[string]any, error) {
+	converted, ok := reflect.New(reflect.TypeOf(hub).Elem()).Interface().(client.Object)
+	if !ok {
+		return nil, pkgerrors.Errorf("cannot create an object of type %T", hub)
+	}
+	content, err := toMap(spoke)
+	if err != nil {
+		return nil, err
+	}
+	removeHubContentAnnotation(content)
+	if err := fromMap(content, converted); err != nil {
+		return nil, err
+	}
+	return toMap(converted)
+}
+
+// lostFields returns the fields of original which are missing from roundTripped, ignoring the type metadata.
+// Fields present on both sides with different values are not considered lost: the value of the spoke version prevails.
+func lostFields(original, roundTripped map[string]any) map[string]any {
+	lost := map[string]any{}
+	for key, value := range original {
+		if key == "apiVersion" || key == "kind" {
+			continue
+		}
+		other, exists := roundTripped[key]
+		if !exists {
+			lost[key] = value
+			continue
+		}
+		nested, isMap := value.(map[string]any)
+		otherNested, otherIsMap := other.(map[string]any)
+		if !isMap || !otherIsMap {
+			continue
+		}
+		if nestedLost := lostFields(nested, otherNested); len(nestedLost) > 0 {
+			lost[key] = nestedLost
+		}
+	}
+	return lost
+}
+
+// restoreFields sets in content the lost fields which are not already set.
+func restoreFields(content, lost map[string]any) {
+	for key, value := range lost {
+		existing, exists := content[key]
+		if !exists {
+			content[key] = value
+			continue
+		}
+		nested, isMap := value.(map[string]any)
+		existingNested, existingIsMap := existing.(map[string]any)
+		if isMap && existingIsMap {
+			restoreFields(existingNested, nested)
+		}
+	}
+}
+
+func hubContent(obj client.Object) (string, bool) {
+	value, exists := obj.GetAnnotations()[HubContentAnnotation]
+	return value, exists && value != ""
+}
+
+func removeHubContentAnnotation(content map[string]any) {
+	metadata, ok := content["metadata"].(map[string]any)
+	if !ok {
+		return
+	}
+	annotations, ok := metadata["annotations"].(map[string]any)
+	if !ok {
+		return
+	}
+	delete(annotations, HubContentAnnotation)
+	if len(annotations) == 0 {
+		delete(metadata, "annotations")
+	}
+}
+
+func toMap(obj runtime.Object) (map[string]any, error) {
+	serialized, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]any
+	if err := json.Unmarshal(serialized, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+func fromMap(content map[string]any, obj runtime.Object) error {
+	// the type metadata of the destination is set by the conversion webhook handler
+	delete(content, "apiVersion")
+	delete(content, "kind")
+	serialized, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(serialized, obj)
+}
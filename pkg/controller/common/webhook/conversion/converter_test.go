@@ -0,0 +1,110 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package conversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	esv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1beta1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/scheme"
+)
+
+func hubElasticsearch() *esv1.Elasticsearch {
+	return &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns", Annotations: map[string]string{"foo": "bar"}},
+		Spec: esv1.ElasticsearchSpec{
+			Version:              "8.15.0",
+			NodeSets:             []esv1.NodeSet{{Name: "default", Count: 3}},
+			ServiceAccountName:   "es-sa",
+			RemoteClusters:       []esv1.RemoteCluster{{Name: "remote", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "other"}}},
+			RevisionHistoryLimit: ptr.To[int32](2),
+		},
+	}
+}
+
+func TestNewJSONSpokeConverter(t *testing.T) {
+	converter := NewJSONSpokeConverter[*esv1.Elasticsearch](&esv1beta1.Elasticsearch{})
+
+	// hub to spoke keeps the fields unknown to the spoke in an annotation
+	spoke := &esv1beta1.Elasticsearch{}
+	require.NoError(t, converter.ConvertHubToSpoke(context.Background(), hubElasticsearch(), spoke))
+	assert.Equal(t, "8.15.0", spoke.Spec.Version)
+	assert.Equal(t, []esv1beta1.NodeSet{{Name: "default", Count: 3}}, spoke.Spec.NodeSets)
+	assert.Equal(t, "bar", spoke.Annotations["foo"])
+	assert.JSONEq(t,
+		`{"spec":{"serviceAccountName":"es-sa","remoteClusters":[{"name":"remote","elasticsearchRef":{"name":"other"}}],"revisionHistoryLimit":2}}`,
+		spoke.Annotations[HubContentAnnotation],
+	)
+
+	// spoke to hub restores them, without the annotation
+	hub := &esv1.Elasticsearch{}
+	require.NoError(t, converter.ConvertSpokeToHub(context.Background(), spoke, hub))
+	assert.Equal(t, hubElasticsearch(), hub)
+
+	// fields updated in the spoke version prevail
+	spoke.Spec.Version = "8.16.0"
+	spoke.Spec.NodeSets[0].Count = 5
+	hub = &esv1.Elasticsearch{}
+	require.NoError(t, converter.ConvertSpokeToHub(context.Background(), spoke, hub))
+	expected := hubElasticsearch()
+	expected.Spec.Version = "8.16.0"
+	expected.Spec.NodeSets[0].Count = 5
+	assert.Equal(t, expected, hub)
+
+	// nothing is kept if the spoke can represent the whole object
+	hub = &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.15.0"},
+	}
+	spoke = &esv1beta1.Elasticsearch{}
+	require.NoError(t, converter.ConvertHubToSpoke(context.Background(), hub, spoke))
+	assert.Empty(t, spoke.Annotations)
+
+	// spoke objects never converted from the hub version are converted as is
+	spoke = &esv1beta1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"},
+		Spec:       esv1beta1.ElasticsearchSpec{Version: "7.17.0"},
+	}
+	hub = &esv1.Elasticsearch{}
+	require.NoError(t, converter.ConvertSpokeToHub(context.Background(), spoke, hub))
+	assert.Equal(t, &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"},
+		Spec:       esv1.ElasticsearchSpec{Version: "7.17.0"},
+	}, hub)
+}
+
+func TestNewRegistry(t *testing.T) {
+	scheme.SetupScheme()
+	scheme.SetupV1beta1Scheme()
+	registry, err := NewRegistry(clientgoscheme.Scheme)
+	require.NoError(t, err)
+
+	groupKinds, err := ConvertedGroupKinds(clientgoscheme.Scheme)
+	require.NoError(t, err)
+	require.Len(t, groupKinds, len(hubConverters))
+	for _, gk := range groupKinds {
+		_, exists := registry.GetConverter(gk)
+		assert.True(t, exists, gk.String())
+	}
+
+	converter, exists := registry.GetConverter(esv1.GroupVersion.WithKind(esv1.Kind).GroupKind())
+	require.True(t, exists)
+	src := hubElasticsearch()
+	src.SetGroupVersionKind(esv1.GroupVersion.WithKind(esv1.Kind))
+	dst := &esv1beta1.Elasticsearch{}
+	dst.SetGroupVersionKind(esv1beta1.GroupVersion.WithKind(esv1.Kind))
+	require.NoError(t, converter.ConvertObject(context.Background(), src, dst))
+	assert.Equal(t, "8.15.0", dst.Spec.Version)
+	assert.Contains(t, dst.Annotations, HubContentAnnotation)
+}
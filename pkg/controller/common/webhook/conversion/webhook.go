@@ -0,0 +1,108 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package conversion
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	apmv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1"
+	apmv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/apm/v1beta1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	esv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1beta1"
+	entv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/enterprisesearch/v1"
+	entv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/enterprisesearch/v1beta1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	kbv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1beta1"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+// WebhookPath is the path of the conversion webhook on the operator webhook server.
+const WebhookPath = "/convert"
+
+var log = ulog.Log.WithName("conversion-webhook")
+
+// hubConverter converts the versions of a resource through its hub version, which is the storage version.
+type hubConverter struct {
+	hub       client.Object
+	converter func(*runtime.Scheme) (conversion.Converter, error)
+}
+
+// hubConverters lists the converters of the resources served in several versions. New versions of a resource are
+// served side by side with the existing ones by adding a spoke converter for each version other than the hub version,
+// see docs/design/0012-api-version-promotion.md.
+var hubConverters = []hubConverter{
+	{
+		hub: &esv1.Elasticsearch{},
+		converter: conversion.NewHubSpokeConverter(&esv1.Elasticsearch{},
+			NewJSONSpokeConverter[*esv1.Elasticsearch](&esv1beta1.Elasticsearch{}),
+		),
+	},
+	{
+		hub: &kbv1.Kibana{},
+		converter: conversion.NewHubSpokeConverter(&kbv1.Kibana{},
+			NewJSONSpokeConverter[*kbv1.Kibana](&kbv1beta1.Kibana{}),
+		),
+	},
+	{
+		hub: &apmv1.ApmServer{},
+		converter: conversion.NewHubSpokeConverter(&apmv1.ApmServer{},
+			NewJSONSpokeConverter[*apmv1.ApmServer](&apmv1beta1.ApmServer{}),
+		),
+	},
+	{
+		hub: &entv1.EnterpriseSearch{},
+		converter: conversion.NewHubSpokeConverter(&entv1.EnterpriseSearch{},
+			NewJSONSpokeConverter[*entv1.EnterpriseSearch](&entv1beta1.EnterpriseSearch{}),
+		),
+	},
+}
+
+// NewRegistry returns a registry of the converters of all the resources served in several versions.
+func NewRegistry(scheme *runtime.Scheme) (conversion.Registry, error) {
+	registry := conversion.NewRegistry()
+	for _, c := range hubConverters {
+		gvk, err := apiutil.GVKForObject(c.hub, scheme)
+		if err != nil {
+			return nil, err
+		}
+		converter, err := c.converter(scheme)
+		if err != nil {
+			return nil, err
+		}
+		if err := registry.RegisterConverter(gvk.GroupKind(), converter); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// ConvertedGroupKinds returns the group kinds of the resources converted by the conversion webhook.
+func ConvertedGroupKinds(scheme *runtime.Scheme) ([]schema.GroupKind, error) {
+	groupKinds := make([]schema.GroupKind, 0, len(hubConverters))
+	for _, c := range hubConverters {
+		gvk, err := apiutil.GVKForObject(c.hub, scheme)
+		if err != nil {
+			return nil, err
+		}
+		groupKinds = append(groupKinds, gvk.GroupKind())
+	}
+	return groupKinds, nil
+}
+
+// RegisterWebhook registers the conversion webhook on the webhook server of the manager.
+func RegisterWebhook(mgr manager.Manager) error {
+	registry, err := NewRegistry(mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+	log.Info("Registering conversion webhook", "path", WebhookPath)
+	mgr.GetWebhookServer().Register(WebhookPath, conversion.NewWebhookHandler(mgr.GetScheme(), registry))
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package conversion
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/scheme"
+)
+
+const (
+	crdsDir        = "../../../../../config/crds/v1/resources"
+	helmHelpersTpl = "../../../../../deploy/eck-operator/templates/_helpers.tpl"
+)
+
+// TestConvertedGroupKinds checks that all the resources served in several versions, such as alpha or beta APIs
+// promoted to v1, are converted by the conversion webhook and that the operator can configure it in their CRDs.
+func TestConvertedGroupKinds(t *testing.T) {
+	scheme.SetupScheme()
+	groupKinds, err := ConvertedGroupKinds(clientgoscheme.Scheme)
+	require.NoError(t, err)
+	helpers, err := os.ReadFile(helmHelpersTpl)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(crdsDir, "*_*.yaml"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		var crd apiextensionsv1.CustomResourceDefinition
+		require.NoError(t, yaml.Unmarshal(content, &crd))
+		if len(crd.Spec.Versions) < 2 {
+			continue
+		}
+		gk := schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}
+		assert.True(t, slices.Contains(groupKinds, gk), "%s is served in several versions but has no hub converter", gk)
+		assert.Contains(t, string(helpers), "  - "+crd.Name+"\n", "the operator cannot configure the conversion of %s", crd.Name)
+	}
+}
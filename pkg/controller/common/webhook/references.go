@@ -0,0 +1,307 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	agentv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/agent/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	entv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/enterprisesearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/maps/v1alpha1"
+	eprv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/packageregistry/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/rbac"
+)
+
+// ReferenceValidationMode controls how the webhook reacts to invalid references to other resources.
+type ReferenceValidationMode string
+
+const (
+	// ReferenceValidationDisabled does not validate references at admission.
+	ReferenceValidationDisabled ReferenceValidationMode = "disabled"
+	// ReferenceValidationWarn admits resources with invalid references, with a warning.
+	ReferenceValidationWarn ReferenceValidationMode = "warn"
+	// ReferenceValidationDeny rejects resources with invalid references.
+	ReferenceValidationDeny ReferenceValidationMode = "deny"
+)
+
+// ParseReferenceValidationMode parses the given reference validation mode.
+func ParseReferenceValidationMode(mode string) (ReferenceValidationMode, error) {
+	switch m := ReferenceValidationMode(strings.ToLower(mode)); m {
+	case ReferenceValidationDisabled, ReferenceValidationWarn, ReferenceValidationDeny:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid reference validation mode %q, must be one of %s, %s or %s",
+			mode, ReferenceValidationDisabled, ReferenceValidationWarn, ReferenceValidationDeny)
+	}
+}
+
+// referencedObjTemplates returns an empty object of the kind referenced by each association type.
+var referencedObjTemplates = map[commonv1.AssociationType]func() client.Object{
+	commonv1.ElasticsearchAssociationType:      func() client.Object { return &esv1.Elasticsearch{} },
+	commonv1.EsMonitoringAssociationType:       func() client.Object { return &esv1.Elasticsearch{} },
+	commonv1.KbMonitoringAssociationType:       func() client.Object { return &esv1.Elasticsearch{} },
+	commonv1.BeatMonitoringAssociationType:     func() client.Object { return &esv1.Elasticsearch{} },
+	commonv1.LogstashMonitoringAssociationType: func() client.Object { return &esv1.Elasticsearch{} },
+	commonv1.KibanaAssociationType:             func() client.Object { return &kbv1.Kibana{} },
+	commonv1.EntAssociationType:                func() client.Object { return &entv1.EnterpriseSearch{} },
+	commonv1.FleetServerAssociationType:        func() client.Object { return &agentv1alpha1.Agent{} },
+	commonv1.PackageRegistryAssociationType:    func() client.Object { return &eprv1alpha1.PackageRegistry{} },
+}
+
+// ReferenceValidator validates at admission the references of a resource to other resources managed by the operator,
+// which are otherwise only checked when the associations are reconciled.
+type ReferenceValidator struct {
+	client         k8s.Client
+	accessReviewer rbac.AccessReviewer
+	mode           ReferenceValidationMode
+}
+
+// NewReferenceValidator returns a ReferenceValidator, or nil if references must not be validated.
+func NewReferenceValidator(c k8s.Client, accessReviewer rbac.AccessReviewer, mode ReferenceValidationMode) *ReferenceValidator {
+	if mode == ReferenceValidationDisabled {
+		return nil
+	}
+	return &ReferenceValidator{client: c, accessReviewer: accessReviewer, mode: mode}
+}
+
+// Validate checks that the resources referenced by the associations of the given object exist, run a version compatible
+// with the object and can be accessed by the object service account. Invalid references are returned as warnings, or as
+// an error if references are validated in deny mode.
+// On update, old is the previous version of the object, nil on create: only the associations whose reference or service
+// account changed are validated, unless the version of the object changed. This lets the operator update the metadata and
+// status of a resource whose referenced resources were since deleted or upgraded.
+func (v *ReferenceValidator) Validate(ctx context.Context, obj runtime.Object, old runtime.Object) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	associated, ok := obj.(commonv1.Associated)
+	if !ok {
+		return nil, nil
+	}
+	unchanged := unchangedAssociations(associated, old)
+
+	var problems []string
+	for _, assoc := range associated.GetAssociations() {
+		if _, skip := unchanged[associationKeyOf(assoc)]; skip {
+			continue
+		}
+		problems = append(problems, v.validateAssociation(ctx, associated, assoc)...)
+	}
+	if len(problems) == 0 {
+		return nil, nil
+	}
+	if v.mode == ReferenceValidationDeny {
+		return nil, errors.New(strings.Join(problems, "; "))
+	}
+	return problems, nil
+}
+
+// associationKey identifies the reference of an association and the service account used to access it.
+type associationKey struct {
+	associationType commonv1.AssociationType
+	ref             commonv1.ObjectSelector
+	serviceAccount  string
+}
+
+func associationKeyOf(assoc commonv1.Association) associationKey {
+	ref := assoc.AssociationRef()
+	return associationKey{
+		associationType: assoc.AssociationType(),
+		ref: commonv1.ObjectSelector{
+			Namespace:   ref.GetNamespace(),
+			Name:        ref.GetName(),
+			ServiceName: ref.GetServiceName(),
+			SecretName:  ref.GetSecretName(),
+		},
+		serviceAccount: assoc.ServiceAccountName(),
+	}
+}
+
+// unchangedAssociations returns the associations of the given associated resource already present in its previous
+// version, if its version did not change.
+func unchangedAssociations(associated commonv1.Associated, old runtime.Object) map[associationKey]struct{} {
+	oldAssociated, ok := old.(commonv1.Associated)
+	if !ok {
+		return nil
+	}
+	newVersion, _ := specVersion(associated)
+	oldVersion, _ := specVersion(oldAssociated)
+	if !newVersion.Equals(oldVersion) {
+		return nil
+	}
+	unchanged := map[associationKey]struct{}{}
+	for _, assoc := range oldAssociated.GetAssociations() {
+		unchanged[associationKeyOf(assoc)] = struct{}{}
+	}
+	return unchanged
+}
+
+func (v *ReferenceValidator) validateAssociation(ctx context.Context, associated commonv1.Associated, assoc commonv1.Association) []string {
+	log := ulog.FromContext(ctx).WithName("common-webhook")
+	ref := assoc.AssociationRef()
+	if !ref.IsSet() || ref.IsExternal() {
+		// references to resources not managed by the operator cannot be checked
+		return nil
+	}
+	template, exists := referencedObjTemplates[assoc.AssociationType()]
+	if !exists {
+		return nil
+	}
+
+	referenced := template()
+	gvk, err := apiutil.GVKForObject(referenced, v.client.Scheme())
+	if err != nil {
+		log.Error(err, "while resolving the kind of a referenced resource", "association_type", assoc.AssociationType())
+		return nil
+	}
+	refDesc := fmt.Sprintf("%s %s/%s", gvk.Kind, ref.GetNamespace(), ref.GetName())
+
+	if err := v.client.Get(ctx, ref.NamespacedName(), referenced); err != nil {
+		if apierrors.IsNotFound(err) {
+			return []string{fmt.Sprintf("referenced %s does not exist", refDesc)}
+		}
+		// we do not want to block admission because of it
+		log.Error(err, "while getting a referenced resource", "namespace", ref.GetNamespace(), "name", ref.GetName())
+		return nil
+	}
+	// the kind is required to review the access to the referenced resource
+	referenced.GetObjectKind().SetGroupVersionKind(gvk)
+
+	var problems []string
+	if problem := v.versionProblem(associated, assoc.AssociationType(), referenced, refDesc); problem != "" {
+		problems = append(problems, problem)
+	}
+
+	allowed, err := v.accessReviewer.AccessAllowed(ctx, assoc.ServiceAccountName(), assoc.GetNamespace(), referenced)
+	if err != nil {
+		log.Error(err, "while reviewing the access to a referenced resource", "namespace", ref.GetNamespace(), "name", ref.GetName())
+	} else if !allowed {
+		serviceAccount := assoc.ServiceAccountName()
+		if serviceAccount == "" {
+			serviceAccount = "default"
+		}
+		problems = append(problems, fmt.Sprintf("service account %s/%s is not allowed to access referenced %s",
+			assoc.GetNamespace(), serviceAccount, refDesc))
+	}
+	return problems
+}
+
+// compatibility returns true if a resource running the associated version can use a referenced resource running the
+// referenced version.
+type compatibility func(associated, referenced version.Version) bool
+
+// lastMinors are the last minor versions of past major versions, which remain compatible with the next major version.
+var lastMinors = map[uint64]uint64{6: 8, 7: 17, 8: 19}
+
+var (
+	// sameMinor requires both resources to run the same major and minor versions.
+	sameMinor compatibility = func(associated, referenced version.Version) bool {
+		return associated.Major == referenced.Major && associated.Minor == referenced.Minor
+	}
+	// sameMajor requires both resources to run the same major version, the referenced resource running the same or a
+	// newer minor version.
+	sameMajor compatibility = func(associated, referenced version.Version) bool {
+		return associated.Major == referenced.Major && associated.Minor <= referenced.Minor
+	}
+	// nextMajor is sameMajor, also allowing the referenced resource to run the next major version if the associated
+	// resource runs the last minor version of its major version.
+	nextMajor compatibility = func(associated, referenced version.Version) bool {
+		if sameMajor(associated, referenced) {
+			return true
+		}
+		lastMinor, exists := lastMinors[associated.Major]
+		return exists && associated.Minor == lastMinor && referenced.Major == associated.Major+1
+	}
+	// notOlder requires the referenced resource to run the same or a newer major and minor version.
+	notOlder compatibility = func(associated, referenced version.Version) bool {
+		return referenced.GTE(associated) || (associated.Major == referenced.Major && associated.Minor == referenced.Minor)
+	}
+)
+
+// compatibilityMatrix holds the version compatibility rules per association type and kind of the associated resource.
+// The empty kind applies to the kinds not listed, notOlder applies to the association types not listed.
+var compatibilityMatrix = map[commonv1.AssociationType]map[string]compatibility{
+	commonv1.ElasticsearchAssociationType: {
+		// Beats, Elastic Agent, APM Server and Logstash
+		"":               nextMajor,
+		kbv1.Kind:        sameMajor,
+		entv1.Kind:       sameMinor,
+		emsv1alpha1.Kind: sameMajor,
+	},
+	// monitoring clusters can monitor clusters running the last minor version of the previous major version
+	commonv1.EsMonitoringAssociationType:       {"": nextMajor},
+	commonv1.KbMonitoringAssociationType:       {"": nextMajor},
+	commonv1.BeatMonitoringAssociationType:     {"": nextMajor},
+	commonv1.LogstashMonitoringAssociationType: {"": nextMajor},
+	commonv1.KibanaAssociationType:             {"": sameMajor},
+	commonv1.EntAssociationType:                {"": sameMinor},
+	commonv1.FleetServerAssociationType:        {"": sameMajor},
+}
+
+// versionProblem describes the incompatibility between the versions of an associated resource and the resource it
+// references, if any, according to the compatibility matrix.
+func (v *ReferenceValidator) versionProblem(
+	associated commonv1.Associated,
+	associationType commonv1.AssociationType,
+	referenced client.Object,
+	refDesc string,
+) string {
+	associatedVersion, ok := specVersion(associated)
+	if !ok {
+		return ""
+	}
+	referencedVersion, ok := specVersion(referenced)
+	if !ok {
+		return ""
+	}
+	kind := ""
+	if gvk, err := apiutil.GVKForObject(associated, v.client.Scheme()); err == nil {
+		kind = gvk.Kind
+	}
+	compatible := notOlder
+	if rules, exists := compatibilityMatrix[associationType]; exists {
+		if rule, exists := rules[kind]; exists {
+			compatible = rule
+		} else if rule, exists := rules[""]; exists {
+			compatible = rule
+		}
+	}
+	if compatible(associatedVersion, referencedVersion) {
+		return ""
+	}
+	return fmt.Sprintf("%s version %s is not compatible with the version %s of referenced %s", kind, associatedVersion, referencedVersion, refDesc)
+}
+
+// specVersion returns the version in the spec of the given resource, if any.
+func specVersion(obj runtime.Object) (version.Version, bool) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return version.Version{}, false
+	}
+	raw, found, err := unstructured.NestedString(content, "spec", "version")
+	if err != nil || !found {
+		return version.Version{}, false
+	}
+	v, err := version.Parse(raw)
+	if err != nil {
+		return version.Version{}, false
+	}
+	return v, true
+}
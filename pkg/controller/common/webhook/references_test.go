@@ -0,0 +1,204 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/rbac"
+)
+
+type denyAllAccessReviewer struct{}
+
+func (a denyAllAccessReviewer) AccessAllowed(_ context.Context, _ string, _ string, _ runtime.Object) (bool, error) {
+	return false, nil
+}
+
+func TestParseReferenceValidationMode(t *testing.T) {
+	mode, err := ParseReferenceValidationMode("Deny")
+	require.NoError(t, err)
+	assert.Equal(t, ReferenceValidationDeny, mode)
+
+	_, err = ParseReferenceValidationMode("block")
+	require.ErrorContains(t, err, `invalid reference validation mode "block"`)
+}
+
+func TestReferenceValidator_Validate(t *testing.T) {
+	scheme.SetupScheme()
+	kibana := func(version string, ref commonv1.ObjectSelector) *kbv1.Kibana {
+		return &kbv1.Kibana{
+			ObjectMeta: metav1.ObjectMeta{Name: "kb", Namespace: "ns"},
+			Spec:       kbv1.KibanaSpec{Version: version, ElasticsearchRef: commonv1.ElasticsearchSelector{ObjectSelector: ref}},
+		}
+	}
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns"},
+		Spec:       esv1.ElasticsearchSpec{Version: "8.15.0"},
+	}
+
+	tests := []struct {
+		name           string
+		mode           ReferenceValidationMode
+		accessReviewer rbac.AccessReviewer
+		objs           []client.Object
+		obj            runtime.Object
+		old            runtime.Object
+		wantWarnings   []string
+		wantErr        string
+	}{
+		{
+			name: "not an associated resource",
+			mode: ReferenceValidationDeny,
+			obj:  es,
+		},
+		{
+			name: "no reference",
+			mode: ReferenceValidationDeny,
+			obj:  kibana("8.15.0", commonv1.ObjectSelector{}),
+		},
+		{
+			name: "external reference",
+			mode: ReferenceValidationDeny,
+			obj:  kibana("8.15.0", commonv1.ObjectSelector{SecretName: "external-es"}),
+		},
+		{
+			name: "valid reference",
+			mode: ReferenceValidationDeny,
+			objs: []client.Object{es},
+			obj:  kibana("8.15.2", commonv1.ObjectSelector{Name: "es"}),
+		},
+		{
+			name:         "missing referenced resource",
+			mode:         ReferenceValidationWarn,
+			obj:          kibana("8.15.0", commonv1.ObjectSelector{Name: "missing"}),
+			wantWarnings: []string{"referenced Elasticsearch ns/missing does not exist"},
+		},
+		{
+			name:    "missing referenced resource in deny mode",
+			mode:    ReferenceValidationDeny,
+			obj:     kibana("8.15.0", commonv1.ObjectSelector{Name: "missing", Namespace: "other"}),
+			wantErr: "referenced Elasticsearch other/missing does not exist",
+		},
+		{
+			name:         "version newer than the referenced resource",
+			mode:         ReferenceValidationWarn,
+			objs:         []client.Object{es},
+			obj:          kibana("8.16.0", commonv1.ObjectSelector{Name: "es"}),
+			wantWarnings: []string{"Kibana version 8.16.0 is not compatible with the version 8.15.0 of referenced Elasticsearch ns/es"},
+		},
+		{
+			name:         "major version older than the referenced resource",
+			mode:         ReferenceValidationWarn,
+			objs:         []client.Object{es},
+			obj:          kibana("7.17.0", commonv1.ObjectSelector{Name: "es"}),
+			wantWarnings: []string{"Kibana version 7.17.0 is not compatible with the version 8.15.0 of referenced Elasticsearch ns/es"},
+		},
+		{
+			name: "last minor version of the previous major version",
+			mode: ReferenceValidationDeny,
+			objs: []client.Object{es},
+			obj: &beatv1beta1.Beat{
+				ObjectMeta: metav1.ObjectMeta{Name: "beat", Namespace: "ns"},
+				Spec:       beatv1beta1.BeatSpec{Version: "7.17.3", ElasticsearchRef: commonv1.ObjectSelector{Name: "es"}},
+			},
+		},
+		{
+			name: "unchanged reference on update",
+			mode: ReferenceValidationDeny,
+			obj:  kibana("8.15.0", commonv1.ObjectSelector{Name: "missing"}),
+			old:  kibana("8.15.0", commonv1.ObjectSelector{Name: "missing"}),
+		},
+		{
+			name:    "changed reference on update",
+			mode:    ReferenceValidationDeny,
+			obj:     kibana("8.15.0", commonv1.ObjectSelector{Name: "missing"}),
+			old:     kibana("8.15.0", commonv1.ObjectSelector{Name: "es"}),
+			wantErr: "referenced Elasticsearch ns/missing does not exist",
+		},
+		{
+			name:    "changed version on update",
+			mode:    ReferenceValidationDeny,
+			objs:    []client.Object{es},
+			obj:     kibana("8.16.0", commonv1.ObjectSelector{Name: "es"}),
+			old:     kibana("8.15.0", commonv1.ObjectSelector{Name: "es"}),
+			wantErr: "Kibana version 8.16.0 is not compatible with the version 8.15.0 of referenced Elasticsearch ns/es",
+		},
+		{
+			name:           "access denied",
+			mode:           ReferenceValidationDeny,
+			accessReviewer: denyAllAccessReviewer{},
+			objs:           []client.Object{es},
+			obj:            kibana("8.16.0", commonv1.ObjectSelector{Name: "es"}),
+			wantErr: "Kibana version 8.16.0 is not compatible with the version 8.15.0 of referenced Elasticsearch ns/es; " +
+				"service account ns/default is not allowed to access referenced Elasticsearch ns/es",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accessReviewer := tt.accessReviewer
+			if accessReviewer == nil {
+				accessReviewer = rbac.NewPermissiveAccessReviewer()
+			}
+			v := NewReferenceValidator(k8s.NewFakeClient(tt.objs...), accessReviewer, tt.mode)
+			warnings, err := v.Validate(context.Background(), tt.obj, tt.old)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantWarnings, warnings)
+		})
+	}
+}
+
+func TestReferenceValidator_Disabled(t *testing.T) {
+	v := NewReferenceValidator(k8s.NewFakeClient(), rbac.NewPermissiveAccessReviewer(), ReferenceValidationDisabled)
+	assert.Nil(t, v)
+	warnings, err := v.Validate(context.Background(), &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Name: "kb", Namespace: "ns"},
+		Spec:       kbv1.KibanaSpec{Version: "8.15.0", ElasticsearchRef: commonv1.ElasticsearchSelector{ObjectSelector: commonv1.ObjectSelector{Name: "missing"}}},
+	}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestCompatibilityMatrix(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		rule       compatibility
+		associated string
+		referenced string
+		want       bool
+	}{
+		{name: "same minor", rule: sameMinor, associated: "8.15.0", referenced: "8.15.3", want: true},
+		{name: "newer minor", rule: sameMinor, associated: "8.15.0", referenced: "8.16.0", want: false},
+		{name: "same major, newer minor", rule: sameMajor, associated: "8.15.0", referenced: "8.16.0", want: true},
+		{name: "same major, older minor", rule: sameMajor, associated: "8.15.0", referenced: "8.14.0", want: false},
+		{name: "newer major", rule: sameMajor, associated: "8.19.0", referenced: "9.0.0", want: false},
+		{name: "next major from the last minor", rule: nextMajor, associated: "8.19.0", referenced: "9.1.0", want: true},
+		{name: "next major from another minor", rule: nextMajor, associated: "8.18.0", referenced: "9.1.0", want: false},
+		{name: "two majors ahead", rule: nextMajor, associated: "7.17.0", referenced: "9.0.0", want: false},
+		{name: "newer major", rule: notOlder, associated: "8.18.0", referenced: "9.0.0", want: true},
+		{name: "older minor", rule: notOlder, associated: "8.18.0", referenced: "8.17.0", want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule(version.MustParse(tt.associated), version.MustParse(tt.referenced)))
+		})
+	}
+}
@@ -42,6 +42,7 @@ func SetupValidatingWebhookWithConfig(config *Config) {
 			config.Manager.GetScheme(),
 			config.Validator,
 			config.LicenseChecker,
+			config.ReferenceValidator,
 			set.Make(config.ManagedNamespace...)),
 	)
 }
@@ -50,14 +51,16 @@ func ValidatingWebhookFor(
 	scheme *runtime.Scheme,
 	validator eckadmission.Validator,
 	licenseChecker license.Checker,
+	referenceValidator *ReferenceValidator,
 	managedNamespaces set.StringSet,
 ) *webhook.Admission {
 	return &webhook.Admission{
 		Handler: &validatingWebhook{
-			decoder:           admission.NewDecoder(scheme),
-			validator:         validator,
-			licenseChecker:    licenseChecker,
-			managedNamespaces: managedNamespaces,
+			decoder:            admission.NewDecoder(scheme),
+			validator:          validator,
+			licenseChecker:     licenseChecker,
+			referenceValidator: referenceValidator,
+			managedNamespaces:  managedNamespaces,
 		},
 	}
 }
@@ -69,13 +72,16 @@ type Config struct {
 	ManagedNamespace []string
 	LicenseChecker   license.Checker
 	Validator        eckadmission.Validator
+	// ReferenceValidator validates the references to other resources, nil to skip the validation.
+	ReferenceValidator *ReferenceValidator
 }
 
 type validatingWebhook struct {
-	decoder           admission.Decoder
-	managedNamespaces set.StringSet
-	licenseChecker    license.Checker
-	validator         eckadmission.Validator
+	decoder            admission.Decoder
+	managedNamespaces  set.StringSet
+	licenseChecker     license.Checker
+	referenceValidator *ReferenceValidator
+	validator          eckadmission.Validator
 }
 
 // Handle satisfies the admission.Handler interface
@@ -110,8 +116,9 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 		warnings, err = obj.ValidateCreate()
 	}
 
+	var oldObj runtime.Object
 	if req.Operation == admissionv1.Update {
-		oldObj := v.validator.DeepCopyObject()
+		oldObj = v.validator.DeepCopyObject()
 		err = v.decoder.DecodeRaw(req.OldObject, oldObj)
 		if err != nil {
 			whlog.Error(err, "decoding old object from webhook request into type (%T)", oldObj)
@@ -131,6 +138,14 @@ func (v *validatingWebhook) Handle(ctx context.Context, req admission.Request) a
 		return admission.Denied(err.Error()).WithWarnings(warnings...)
 	}
 
+	if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
+		referenceWarnings, err := v.referenceValidator.Validate(ctx, obj, oldObj)
+		warnings = append(warnings, referenceWarnings...)
+		if err != nil {
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
+		}
+	}
+
 	return admission.Allowed("").WithWarnings(warnings...)
 }
 
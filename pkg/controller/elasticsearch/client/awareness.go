@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+)
+
+// ForcedAwarenessClient captures Elasticsearch API calls around forced shard allocation awareness.
+type ForcedAwarenessClient interface {
+	// GetForcedAwarenessZones returns the zone values of the persistent forced awareness setting.
+	GetForcedAwarenessZones(ctx context.Context) ([]string, error)
+	// UpdateForcedAwarenessZones sets the zone values of the persistent forced awareness setting.
+	// An empty list resets the setting.
+	UpdateForcedAwarenessZones(ctx context.Context, zones []string) error
+}
+
+// ForcedAwarenessSettings is the flat representation of the persistent forced awareness setting.
+type ForcedAwarenessSettings struct {
+	Persistent struct {
+		ZoneValues SettingValues `json:"cluster.routing.allocation.awareness.force.zone.values,omitempty"`
+	} `json:"persistent"`
+}
+
+// SettingValues is a list setting, which Elasticsearch returns either as a JSON array or as a comma-separated string
+// depending on how it was set.
+type SettingValues []string
+
+func (v *SettingValues) UnmarshalJSON(data []byte) error {
+	var values []string
+	if err := json.Unmarshal(data, &values); err == nil {
+		*v = values
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*v = nil
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*v = append(*v, s)
+		}
+	}
+	return nil
+}
+
+func (c *clientV7) GetForcedAwarenessZones(ctx context.Context) ([]string, error) {
+	var settings ForcedAwarenessSettings
+	err := c.get(ctx, "/_cluster/settings?flat_settings=true", &settings)
+	return settings.Persistent.ZoneValues, err
+}
+
+func (c *clientV7) UpdateForcedAwarenessZones(ctx context.Context, zones []string) error {
+	var value *string
+	if len(zones) > 0 {
+		joined := strings.Join(zones, ",")
+		value = &joined
+	}
+	settings := map[string]map[string]*string{
+		"persistent": {esv1.ShardAwarenessForceZoneValues: value},
+	}
+	return c.put(ctx, "/_cluster/settings", settings, nil)
+}
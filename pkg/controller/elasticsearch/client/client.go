@@ -62,10 +62,12 @@ type Client interface {
 	AllocationSetter
 	AutoscalingClient
 	DesiredNodesClient
+	ForcedAwarenessClient
 	ShardLister
 	LicenseClient
 	RemoteClusterClient
 	SecurityClient
+	SnapshotRepositoryClient
 	// Close idle connections in the underlying http client.
 	Close()
 	// Equal returns true if other can be considered as the same client.
@@ -320,7 +320,7 @@ func TestClientGetNodes(t *testing.T) {
 }
 
 func TestClientGetNodesStats(t *testing.T) {
-	expectedPath := "/_nodes/_all/stats/os"
+	expectedPath := "/_nodes/_all/stats/os,jvm,thread_pool,fs"
 	testClient := NewMockClient(version.MustParse("7.17.0"), func(req *http.Request) *http.Response {
 		require.Equal(t, expectedPath, req.URL.Path)
 		return &http.Response{
@@ -334,7 +334,72 @@ func TestClientGetNodesStats(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 1, len(resp.Nodes))
 	require.Contains(t, resp.Nodes, "Rt-o5-ZBQaq-Nkhhy0p7JA")
-	require.Equal(t, "3221225472", resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"].OS.CGroup.Memory.LimitInBytes)
+	node := resp.Nodes["Rt-o5-ZBQaq-Nkhhy0p7JA"]
+	require.Equal(t, "3221225472", node.OS.CGroup.Memory.LimitInBytes)
+	require.Equal(t, 19, node.OS.CPU.Percent)
+	require.Equal(t, 42, node.JVM.Mem.HeapUsedPercent)
+	require.Equal(t, 3, node.ThreadPool["search"].Queue)
+	require.Equal(t, 0, node.ThreadPool["write"].Queue)
+	require.Equal(t, int64(1056858112), node.FS.Total.TotalInBytes)
+	require.Equal(t, int64(720629760), node.FS.Total.AvailableInBytes)
+}
+
+func TestClientGetSnapshotRepositories(t *testing.T) {
+	expectedPath := "/_snapshot"
+	testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		require.Equal(t, expectedPath, req.URL.Path)
+		return NewMockResponse(200, req, `{"s3-repo":{"type":"s3","settings":{"bucket":"snapshots"}},"fs-repo":{"type":"fs"}}`)
+	})
+	resp, err := testClient.GetSnapshotRepositories(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, SnapshotRepositories{"s3-repo": {Type: "s3"}, "fs-repo": {Type: "fs"}}, resp)
+}
+
+func TestClientGetForcedAwarenessZones(t *testing.T) {
+	for _, body := range []string{
+		`{"persistent":{"cluster.routing.allocation.awareness.force.zone.values":["a","b"]},"transient":{}}`,
+		`{"persistent":{"cluster.routing.allocation.awareness.force.zone.values":"a, b"},"transient":{}}`,
+	} {
+		testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+			require.Equal(t, "/_cluster/settings", req.URL.Path)
+			require.Equal(t, "flat_settings=true", req.URL.RawQuery)
+			return NewMockResponse(200, req, body)
+		})
+		zones, err := testClient.GetForcedAwarenessZones(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []string{"a", "b"}, zones)
+	}
+}
+
+func TestClientUpdateForcedAwarenessZones(t *testing.T) {
+	tests := []struct {
+		name         string
+		zones        []string
+		expectedBody string
+	}{
+		{
+			name:         "set zones",
+			zones:        []string{"a", "b"},
+			expectedBody: `{"persistent":{"cluster.routing.allocation.awareness.force.zone.values":"a,b"}}`,
+		},
+		{
+			name:         "reset zones",
+			expectedBody: `{"persistent":{"cluster.routing.allocation.awareness.force.zone.values":null}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testClient := NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+				require.Equal(t, http.MethodPut, req.Method)
+				require.Equal(t, "/_cluster/settings", req.URL.Path)
+				body, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.JSONEq(t, tt.expectedBody, string(body))
+				return NewMockResponse(200, req, `{}`)
+			})
+			require.NoError(t, testClient.UpdateForcedAwarenessZones(context.Background(), tt.zones))
+		})
+	}
 }
 
 func TestGetInfo(t *testing.T) {
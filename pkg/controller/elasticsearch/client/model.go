@@ -112,8 +112,31 @@ type NodesStats struct {
 type NodeStats struct {
 	Name string `json:"name"`
 	OS   struct {
+		CPU struct {
+			Percent int `json:"percent"`
+		} `json:"cpu"`
 		CGroup *CGroup `json:"cgroup"`
 	} `json:"os"`
+	JVM struct {
+		Mem struct {
+			HeapUsedPercent int `json:"heap_used_percent"`
+		} `json:"mem"`
+	} `json:"jvm"`
+	ThreadPool map[string]ThreadPoolStats `json:"thread_pool"`
+	FS         struct {
+		Total struct {
+			TotalInBytes     int64 `json:"total_in_bytes"`
+			AvailableInBytes int64 `json:"available_in_bytes"`
+		} `json:"total"`
+	} `json:"fs"`
+}
+
+// ThreadPoolStats partially models the statistics of a thread pool of an Elasticsearch node.
+type ThreadPoolStats struct {
+	Threads  int `json:"threads"`
+	Queue    int `json:"queue"`
+	Active   int `json:"active"`
+	Rejected int `json:"rejected"`
 }
 
 type CGroup struct {
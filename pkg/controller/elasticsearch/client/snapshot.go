@@ -0,0 +1,29 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package client
+
+import (
+	"context"
+)
+
+// SnapshotRepositoryClient captures Elasticsearch API calls around snapshot repositories.
+type SnapshotRepositoryClient interface {
+	// GetSnapshotRepositories returns the snapshot repositories registered in the cluster, indexed by name.
+	GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error)
+}
+
+// SnapshotRepositories is the response of the _snapshot API, indexed by repository name.
+type SnapshotRepositories map[string]SnapshotRepository
+
+// SnapshotRepository is a snapshot repository registered in Elasticsearch.
+type SnapshotRepository struct {
+	Type string `json:"type"`
+}
+
+func (c *clientV7) GetSnapshotRepositories(ctx context.Context) (SnapshotRepositories, error) {
+	var repositories SnapshotRepositories
+	err := c.get(ctx, "/_snapshot", &repositories)
+	return repositories, err
+}
@@ -73,6 +73,42 @@ const (
             "usage_in_bytes" : "2926161920"
           }
         }
+      },
+      "jvm" : {
+        "timestamp" : 1560016895153,
+        "uptime_in_millis" : 2176432,
+        "mem" : {
+          "heap_used_in_bytes" : 684552192,
+          "heap_used_percent" : 42,
+          "heap_committed_in_bytes" : 1610612736,
+          "heap_max_in_bytes" : 1610612736
+        }
+      },
+      "thread_pool" : {
+        "search" : {
+          "threads" : 4,
+          "queue" : 3,
+          "active" : 4,
+          "rejected" : 0,
+          "largest" : 4,
+          "completed" : 1324
+        },
+        "write" : {
+          "threads" : 2,
+          "queue" : 0,
+          "active" : 1,
+          "rejected" : 0,
+          "largest" : 2,
+          "completed" : 761
+        }
+      },
+      "fs" : {
+        "timestamp" : 1560016895154,
+        "total" : {
+          "total_in_bytes" : 1056858112,
+          "free_in_bytes" : 720629760,
+          "available_in_bytes" : 720629760
+        }
       }
     }
   }
@@ -112,8 +112,8 @@ func (c *clientV7) GetNodes(ctx context.Context) (Nodes, error) {
 
 func (c *clientV7) GetNodesStats(ctx context.Context) (NodesStats, error) {
 	var nodesStats NodesStats
-	// restrict call to basic node info only
-	err := c.get(ctx, "/_nodes/_all/stats/os", &nodesStats)
+	// restrict call to the metrics used by the operator
+	err := c.get(ctx, "/_nodes/_all/stats/os,jvm,thread_pool,fs", &nodesStats)
 	return nodesStats, err
 }
 
@@ -0,0 +1,203 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+)
+
+const (
+	// redacted replaces the values of the sensitive fields.
+	redacted = "REDACTED"
+	// manifestFile lists the files of the bundle, the files omitted because of the bundle size limit and the
+	// collection errors.
+	manifestFile = "manifest.txt"
+)
+
+// sensitiveKeyRegexp matches the keys of the fields whose value must not end up in a diagnostics bundle.
+var sensitiveKeyRegexp = regexp.MustCompile(`(?i)(password|passwd|secret|token|credentials?|api_?key|private_?key)$`)
+
+// bundle is a set of files collected for diagnostics, along with the errors encountered while collecting them.
+type bundle struct {
+	files  map[string][]byte
+	errors []string
+}
+
+func newBundle() *bundle {
+	return &bundle{files: map[string][]byte{}}
+}
+
+// addError records an error encountered while collecting the given file.
+func (b *bundle) addError(file string, err error) {
+	b.errors = append(b.errors, fmt.Sprintf("%s: %s", file, err))
+}
+
+// addYAML adds the given object to the bundle as a YAML file, with its sensitive fields redacted.
+func (b *bundle) addYAML(file string, obj any) {
+	doc, err := toRedactedDocument(obj)
+	if err != nil {
+		b.addError(file, err)
+		return
+	}
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		b.addError(file, err)
+		return
+	}
+	b.files[file] = out
+}
+
+// addFile adds the given content to the bundle as is.
+func (b *bundle) addFile(file string, content []byte) {
+	b.files[file] = content
+}
+
+// addJSON adds the given raw JSON document to the bundle, with its sensitive fields redacted.
+func (b *bundle) addJSON(file string, raw []byte) {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		b.addError(file, err)
+		return
+	}
+	out, err := json.MarshalIndent(redact(doc), "", "  ")
+	if err != nil {
+		b.addError(file, err)
+		return
+	}
+	b.files[file] = out
+}
+
+// toRedactedDocument converts the given object into a generic JSON document with its sensitive fields redacted.
+func toRedactedDocument(obj any) (any, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return redact(doc), nil
+}
+
+// redact replaces the values of the sensitive fields of the given generic JSON document. Environment variables are
+// redacted based on their name.
+func redact(doc any) any {
+	switch v := doc.(type) {
+	case map[string]any:
+		if name, isString := v["name"].(string); isString && sensitiveKeyRegexp.MatchString(name) {
+			if _, hasValue := v["value"]; hasValue {
+				v["value"] = redacted
+			}
+		}
+		for key, value := range v {
+			if sensitiveKeyRegexp.MatchString(key) {
+				if _, isString := value.(string); isString {
+					v[key] = redacted
+					continue
+				}
+			}
+			v[key] = redact(value)
+		}
+		return v
+	case []any:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// archive returns the bundle as a gzipped tarball no larger than maxSize. The largest files are omitted until the
+// archive fits, and listed as such in the manifest.
+func (b *bundle) archive(maxSize int, collectedAt time.Time) ([]byte, error) {
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	// sort by decreasing size so that the largest files are omitted first
+	slices.SortFunc(names, func(a, c string) int {
+		if d := len(b.files[c]) - len(b.files[a]); d != 0 {
+			return d
+		}
+		return strings.Compare(a, c)
+	})
+
+	for omitted := 0; omitted <= len(names); omitted++ {
+		included := slices.Sorted(slices.Values(names[omitted:]))
+		out, err := b.tarball(included, names[:omitted], collectedAt)
+		if err != nil {
+			return nil, err
+		}
+		if len(out) <= maxSize {
+			return out, nil
+		}
+	}
+	return nil, fmt.Errorf("diagnostics bundle exceeds %d bytes", maxSize)
+}
+
+func (b *bundle) tarball(included []string, omitted []string, collectedAt time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	write := func(name string, content []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: collectedAt}); err != nil {
+			return err
+		}
+		_, err := tw.Write(content)
+		return err
+	}
+
+	if err := write(manifestFile, b.manifest(included, omitted, collectedAt)); err != nil {
+		return nil, err
+	}
+	for _, name := range included {
+		if err := write(name, b.files[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *bundle) manifest(included []string, omitted []string, collectedAt time.Time) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "collected at: %s\n", collectedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "files:\n")
+	for _, name := range included {
+		fmt.Fprintf(&sb, "  %s\n", name)
+	}
+	if len(omitted) > 0 {
+		fmt.Fprintf(&sb, "omitted because of the bundle size limit:\n")
+		for _, name := range slices.Sorted(slices.Values(omitted)) {
+			fmt.Fprintf(&sb, "  %s\n", name)
+		}
+	}
+	if len(b.errors) > 0 {
+		fmt.Fprintf(&sb, "errors:\n")
+		for _, err := range b.errors {
+			fmt.Fprintf(&sb, "  %s\n", err)
+		}
+	}
+	return []byte(sb.String())
+}
@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"context"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	toolsevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	commonesclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/esclient"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const name = "elasticsearch-diagnostics-controller"
+
+// Add creates a new diagnostics controller and adds it to the manager. Diagnostics bundles are collected apart from
+// the reconciliation of the clusters, so that a cluster that does not respond does not hold up its reconciliation.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r, err := newReconciler(mgr, params)
+	if err != nil {
+		return err
+	}
+	c, err := common.NewController(mgr, name, r, params, &esv1.Elasticsearch{})
+	if err != nil {
+		return err
+	}
+	return c.Watch(source.Kind(mgr.GetCache(), &esv1.Elasticsearch{}, &handler.TypedEnqueueRequestForObject[*esv1.Elasticsearch]{}))
+}
+
+func newReconciler(mgr manager.Manager, params operator.Parameters) (*ReconcileDiagnostics, error) {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	podName, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return &ReconcileDiagnostics{
+		Client:           mgr.GetClient(),
+		Parameters:       params,
+		recorder:         mgr.GetEventRecorder(name),
+		esClientProvider: commonesclient.NewClient,
+		operatorLogs:     newOperatorLogsProvider(clientset, params.OperatorNamespace, podName),
+	}, nil
+}
+
+var _ reconcile.Reconciler = (*ReconcileDiagnostics)(nil)
+
+// ReconcileDiagnostics collects diagnostics bundles for Elasticsearch clusters.
+type ReconcileDiagnostics struct {
+	k8s.Client
+	operator.Parameters
+	recorder         toolsevents.EventRecorder
+	esClientProvider commonesclient.Provider
+	operatorLogs     operatorLogsProvider
+
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile collects a diagnostics bundle for the Elasticsearch cluster if one is requested, even if the cluster
+// does not respond to requests.
+func (r *ReconcileDiagnostics) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, name, "es_name", request)
+	defer common.LogReconciliationRun(ulog.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	var es esv1.Elasticsearch
+	if err := r.Get(ctx, request.NamespacedName, &es); err != nil {
+		if apierrors.IsNotFound(err) {
+			// the diagnostics Secret is garbage collected with the cluster
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+	if common.IsUnmanaged(ctx, &es) {
+		ulog.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation", "namespace", es.Namespace, "es_name", es.Name)
+		return reconcile.Result{}, nil
+	}
+
+	results := r.reconcileBundle(ctx, es)
+	if results.HasError() {
+		msg := "Could not collect diagnostics bundle"
+		ulog.FromContext(ctx).Info(msg, "namespace", es.Namespace, "es_name", es.Name)
+		k8s.EmitEvent(r.recorder, &es, corev1.EventTypeWarning, events.EventReasonUnexpected, events.EventActionDiagnosticsCollection, msg)
+	}
+	return results.Aggregate()
+}
@@ -0,0 +1,268 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+// Package diagnostics collects diagnostics bundles for Elasticsearch clusters on demand or periodically, and stores
+// them in a Secret.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// BundleKey is the key of the diagnostics bundle in the diagnostics Secret.
+	BundleKey = "diagnostics.tar.gz"
+	// CollectedTriggerAnnotation holds the value of the trigger annotation the bundle was collected for.
+	CollectedTriggerAnnotation = "diagnostics.k8s.elastic.co/trigger"
+	// CollectedAtAnnotation holds the time the bundle was collected at, in RFC3339 format.
+	CollectedAtAnnotation = "diagnostics.k8s.elastic.co/collected-at"
+
+	// kibanaResourcesFile holds the Kibana resources associated with the cluster and their status, as reported by the
+	// operator rather than by the Kibana status API.
+	kibanaResourcesFile = "kibana/resources.yaml"
+	// maxBundleSize keeps the bundle below the size limit of a Secret.
+	maxBundleSize = 1000 * 1024
+	// lastAppliedConfigAnnotation may hold sensitive values of the spec, and is removed from the collected resources.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// elasticsearchAPIs are the Elasticsearch APIs whose response is added to the bundle, indexed by file name.
+var elasticsearchAPIs = map[string]string{
+	"elasticsearch/cluster_health.json":     "/_cluster/health",
+	"elasticsearch/nodes_stats.json":        "/_nodes/stats",
+	"elasticsearch/allocation_explain.json": "/_cluster/allocation/explain",
+	"elasticsearch/shutdown.json":           "/_nodes/shutdown",
+}
+
+// reconcileBundle collects a diagnostics bundle for the given cluster and stores it in the diagnostics Secret, when
+// the value of the trigger annotation changes or when the diagnostics interval has elapsed since the last collection.
+func (r *ReconcileDiagnostics) reconcileBundle(ctx context.Context, es esv1.Elasticsearch) *reconciler.Results {
+	results := reconciler.NewResult(ctx)
+	trigger := es.Annotations[esv1.DiagnosticsTriggerAnnotation]
+	interval, err := esv1.GetDiagnosticsIntervalAnnotation(es.Annotations)
+	if err != nil {
+		// the annotation is ignored, a warning is returned by the validating webhook
+		ulog.FromContext(ctx).V(1).Info("Ignoring invalid diagnostics interval", "error", err.Error())
+		interval = 0
+	}
+	if trigger == "" && interval == 0 {
+		return results
+	}
+
+	var current corev1.Secret
+	err = r.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: esv1.DiagnosticsSecretName(es.Name)}, &current)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return results.WithError(err)
+	}
+
+	now := time.Now()
+	if due, nextCollection := isCollectionDue(current, trigger, interval, now); !due {
+		if nextCollection > 0 {
+			results.WithReconciliationState(reconciler.RequeueAfter(nextCollection).ReconciliationComplete())
+		}
+		return results
+	}
+
+	ulog.FromContext(ctx).Info("Collecting diagnostics bundle", "namespace", es.Namespace, "es_name", es.Name)
+	bundle := r.collect(ctx, es)
+	archive, err := bundle.archive(maxBundleSize, now)
+	if err != nil {
+		return results.WithError(err)
+	}
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: es.Namespace,
+			Name:      esv1.DiagnosticsSecretName(es.Name),
+			Labels:    label.NewLabels(k8s.ExtractNamespacedName(&es)),
+			Annotations: map[string]string{
+				CollectedTriggerAnnotation: trigger,
+				CollectedAtAnnotation:      now.UTC().Format(time.RFC3339),
+			},
+		},
+		Data: map[string][]byte{BundleKey: archive},
+	}
+	if _, err := reconciler.ReconcileSecret(ctx, r.Client, expected, &es); err != nil {
+		return results.WithError(err)
+	}
+	if interval > 0 {
+		results.WithReconciliationState(reconciler.RequeueAfter(interval).ReconciliationComplete())
+	}
+	return results
+}
+
+// isCollectionDue returns whether a bundle must be collected given the current diagnostics Secret, and otherwise the
+// duration until the next periodic collection, if any.
+func isCollectionDue(current corev1.Secret, trigger string, interval time.Duration, now time.Time) (bool, time.Duration) {
+	if _, exists := current.Data[BundleKey]; !exists {
+		return true, 0
+	}
+	if trigger != "" && trigger != current.Annotations[CollectedTriggerAnnotation] {
+		return true, 0
+	}
+	if interval == 0 {
+		return false, 0
+	}
+	collectedAt, err := time.Parse(time.RFC3339, current.Annotations[CollectedAtAnnotation])
+	if err != nil {
+		return true, 0
+	}
+	if elapsed := now.Sub(collectedAt); elapsed < interval {
+		return false, interval - elapsed
+	}
+	return true, 0
+}
+
+// collect gathers the resources related to the cluster, the Elasticsearch APIs responses, the status of the
+// associated Kibana instances and the related operator logs. Errors are recorded in the bundle manifest so that a
+// partial bundle is still available when the cluster does not respond.
+func (r *ReconcileDiagnostics) collect(ctx context.Context, es esv1.Elasticsearch) *bundle {
+	b := newBundle()
+	c := r.Client
+
+	resource := es.DeepCopy()
+	resource.ManagedFields = nil
+	delete(resource.Annotations, lastAppliedConfigAnnotation)
+	b.addYAML("kubernetes/elasticsearch.yaml", resource)
+
+	matchLabels := label.NewLabelSelectorForElasticsearch(es)
+	var ssets appsv1.StatefulSetList
+	if err := c.List(ctx, &ssets, client.InNamespace(es.Namespace), matchLabels); err != nil {
+		b.addError("kubernetes/statefulsets.yaml", err)
+	} else {
+		for i := range ssets.Items {
+			ssets.Items[i].ManagedFields = nil
+		}
+		b.addYAML("kubernetes/statefulsets.yaml", ssets.Items)
+	}
+
+	var pods corev1.PodList
+	if err := c.List(ctx, &pods, client.InNamespace(es.Namespace), matchLabels); err != nil {
+		b.addError("kubernetes/pods.yaml", err)
+	} else {
+		for i := range pods.Items {
+			pods.Items[i].ManagedFields = nil
+		}
+		b.addYAML("kubernetes/pods.yaml", pods.Items)
+	}
+
+	var events corev1.EventList
+	if err := c.List(ctx, &events, client.InNamespace(es.Namespace)); err != nil {
+		b.addError("kubernetes/events.yaml", err)
+	} else {
+		b.addYAML("kubernetes/events.yaml", relatedEvents(es, pods.Items, events.Items))
+	}
+
+	if kibanas, err := associatedKibanas(ctx, c, es); err != nil {
+		b.addError(kibanaResourcesFile, err)
+	} else {
+		b.addYAML(kibanaResourcesFile, kibanas)
+	}
+
+	if logs, err := r.operatorLogs(ctx, es); err != nil {
+		b.addError(operatorLogsFile, err)
+	} else {
+		b.addFile(operatorLogsFile, logs)
+	}
+
+	esClient, err := r.esClientProvider(ctx, c, r.Dialer, es)
+	if err != nil {
+		for _, file := range slices.Sorted(maps.Keys(elasticsearchAPIs)) {
+			b.addError(file, err)
+		}
+		return b
+	}
+	defer esClient.Close()
+	for _, file := range slices.Sorted(maps.Keys(elasticsearchAPIs)) {
+		raw, err := get(ctx, esClient, elasticsearchAPIs[file])
+		if err != nil {
+			b.addError(file, err)
+			continue
+		}
+		b.addJSON(file, raw)
+	}
+	return b
+}
+
+// relatedEvents returns the events involving the cluster or its Pods.
+func relatedEvents(es esv1.Elasticsearch, pods []corev1.Pod, events []corev1.Event) []corev1.Event {
+	involved := map[string]bool{esv1.Kind + "/" + es.Name: true}
+	for _, pod := range pods {
+		involved["Pod/"+pod.Name] = true
+	}
+	var related []corev1.Event
+	for _, event := range events {
+		if involved[event.InvolvedObject.Kind+"/"+event.InvolvedObject.Name] {
+			event.ManagedFields = nil
+			related = append(related, event)
+		}
+	}
+	return related
+}
+
+// kibanaStatus is the status of a Kibana instance associated with the cluster.
+type kibanaStatus struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Status    kbv1.KibanaStatus `json:"status"`
+}
+
+// associatedKibanas returns the status of the Kibana instances referencing the cluster from its namespace.
+func associatedKibanas(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) ([]kibanaStatus, error) {
+	var kibanas kbv1.KibanaList
+	if err := c.List(ctx, &kibanas, client.InNamespace(es.Namespace)); err != nil {
+		return nil, err
+	}
+	var statuses []kibanaStatus
+	for _, kb := range kibanas.Items {
+		ref := kb.Spec.ElasticsearchRef.WithDefaultNamespace(kb.Namespace)
+		if !ref.IsSet() || ref.IsExternal() || ref.NamespacedName() != k8s.ExtractNamespacedName(&es) {
+			continue
+		}
+		statuses = append(statuses, kibanaStatus{Namespace: kb.Namespace, Name: kb.Name, Version: kb.Spec.Version, Status: kb.Status})
+	}
+	return statuses, nil
+}
+
+// get returns the raw response of the given Elasticsearch API.
+func get(ctx context.Context, esClient esclient.Client, path string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, path, nil) //nolint:noctx
+	if err != nil {
+		return nil, err
+	}
+	resp, err := esClient.Request(ctx, req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("while reading response: %w", err)
+	}
+	return raw, nil
+}
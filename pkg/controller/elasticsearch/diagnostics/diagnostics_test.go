@@ -0,0 +1,244 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolsevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/net"
+)
+
+func Test_isCollectionDue(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	collected := func(trigger string, at time.Time) corev1.Secret {
+		return corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+				CollectedTriggerAnnotation: trigger,
+				CollectedAtAnnotation:      at.Format(time.RFC3339),
+			}},
+			Data: map[string][]byte{BundleKey: []byte("bundle")},
+		}
+	}
+	tests := []struct {
+		name     string
+		current  corev1.Secret
+		trigger  string
+		interval time.Duration
+		wantDue  bool
+		wantNext time.Duration
+	}{
+		{
+			name:    "no bundle yet",
+			trigger: "1",
+			wantDue: true,
+		},
+		{
+			name:    "bundle already collected for the trigger",
+			current: collected("1", now.Add(-time.Hour)),
+			trigger: "1",
+			wantDue: false,
+		},
+		{
+			name:    "trigger changed",
+			current: collected("1", now.Add(-time.Minute)),
+			trigger: "2",
+			wantDue: true,
+		},
+		{
+			name:     "interval not elapsed",
+			current:  collected("", now.Add(-time.Hour)),
+			interval: 24 * time.Hour,
+			wantDue:  false,
+			wantNext: 23 * time.Hour,
+		},
+		{
+			name:     "interval elapsed",
+			current:  collected("", now.Add(-25*time.Hour)),
+			interval: 24 * time.Hour,
+			wantDue:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due, next := isCollectionDue(tt.current, tt.trigger, tt.interval, now)
+			assert.Equal(t, tt.wantDue, due)
+			assert.Equal(t, tt.wantNext, next)
+		})
+	}
+}
+
+func Test_redact(t *testing.T) {
+	doc := map[string]any{
+		"spec": map[string]any{
+			"config": map[string]any{
+				"xpack.security.authc.realms.ldap.ldap1.bind_password": "changeme",
+				"xpack.security.authc.token.enabled":                   true,
+			},
+			"env": []any{
+				map[string]any{"name": "ES_API_KEY", "value": "abc"},
+				map[string]any{"name": "ES_JAVA_OPTS", "value": "-Xms1g"},
+				map[string]any{"name": "ES_TOKEN", "valueFrom": map[string]any{"secretKeyRef": map[string]any{"name": "s", "key": "token"}}},
+			},
+			"secretName": "my-secret",
+		},
+	}
+	want := map[string]any{
+		"spec": map[string]any{
+			"config": map[string]any{
+				"xpack.security.authc.realms.ldap.ldap1.bind_password": redacted,
+				"xpack.security.authc.token.enabled":                   true,
+			},
+			"env": []any{
+				map[string]any{"name": "ES_API_KEY", "value": redacted},
+				map[string]any{"name": "ES_JAVA_OPTS", "value": "-Xms1g"},
+				map[string]any{"name": "ES_TOKEN", "valueFrom": map[string]any{"secretKeyRef": map[string]any{"name": "s", "key": "token"}}},
+			},
+			"secretName": "my-secret",
+		},
+	}
+	assert.Equal(t, want, redact(doc))
+}
+
+func Test_bundle_archive(t *testing.T) {
+	b := newBundle()
+	b.files["small.json"] = []byte(`{}`)
+	// random-looking content that does not compress well
+	var large strings.Builder
+	for i := 0; i < 20000; i++ {
+		large.WriteString(time.Duration(i * 7919).String())
+	}
+	b.files["large.json"] = []byte(large.String())
+	b.addError("failed.json", io.EOF)
+
+	archive, err := b.archive(2*1024, time.Now())
+	require.NoError(t, err)
+	files := untar(t, archive)
+	assert.Contains(t, files, "small.json")
+	assert.NotContains(t, files, "large.json")
+	assert.Contains(t, files[manifestFile], "omitted because of the bundle size limit:\n  large.json")
+	assert.Contains(t, files[manifestFile], "errors:\n  failed.json: EOF")
+}
+
+func untar(t *testing.T, archive []byte) map[string]string {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	files := map[string]string{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "es",
+			Annotations: map[string]string{esv1.DiagnosticsTriggerAnnotation: "ticket-1234"},
+		},
+		Spec: esv1.ElasticsearchSpec{Version: "8.15.0"},
+	}
+	pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "ns", Name: "es-es-default-0", Labels: label.NewLabels(k8s.ExtractNamespacedName(&es)),
+	}}
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{Version: "8.15.0", ElasticsearchRef: commonv1.ElasticsearchSelector{ObjectSelector: commonv1.ObjectSelector{Name: "es"}}},
+		Status:     kbv1.KibanaStatus{DeploymentStatus: commonv1.DeploymentStatus{Health: "green"}},
+	}
+	c := k8s.NewFakeClient(&es, &pod, &kb)
+	esClient := esclient.NewMockClient(version.MustParse("8.15.0"), func(req *http.Request) *http.Response {
+		if req.URL.Path == "/_cluster/allocation/explain" {
+			return esclient.NewMockResponse(400, req, `{"error":"no unassigned shards"}`)
+		}
+		return esclient.NewMockResponse(200, req, `{"path":"`+req.URL.Path+`"}`)
+	})
+
+	r := &ReconcileDiagnostics{
+		Client:   c,
+		recorder: toolsevents.NewFakeRecorder(10),
+		esClientProvider: func(context.Context, k8s.Client, net.Dialer, esv1.Elasticsearch) (esclient.Client, error) {
+			return esClient, nil
+		},
+		operatorLogs: func(context.Context, esv1.Elasticsearch) ([]byte, error) {
+			return []byte(`{"message":"Starting reconciliation run","namespace":"ns","es_name":"es"}` + "\n"), nil
+		},
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "es"}}
+	_, err := r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+
+	var secret corev1.Secret
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es-es-diagnostics"}, &secret))
+	assert.Equal(t, "ticket-1234", secret.Annotations[CollectedTriggerAnnotation])
+	files := untar(t, secret.Data[BundleKey])
+	assert.Contains(t, files["kubernetes/elasticsearch.yaml"], "diagnostics-trigger: ticket-1234")
+	assert.Contains(t, files["kubernetes/pods.yaml"], "es-es-default-0")
+	assert.Contains(t, files[kibanaResourcesFile], "health: green")
+	assert.Contains(t, files[operatorLogsFile], "Starting reconciliation run")
+	assert.Contains(t, files["elasticsearch/nodes_stats.json"], `"path": "/_nodes/stats"`)
+	assert.NotContains(t, files, "elasticsearch/allocation_explain.json")
+	assert.Contains(t, files[manifestFile], "elasticsearch/allocation_explain.json: ")
+
+	// the bundle is not collected again for the same trigger
+	collectedAt := secret.Annotations[CollectedAtAnnotation]
+	secret.Annotations[CollectedAtAnnotation] = "2000-01-01T00:00:00Z"
+	require.NoError(t, c.Update(context.Background(), &secret))
+	_, err = r.Reconcile(context.Background(), request)
+	require.NoError(t, err)
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "es-es-diagnostics"}, &secret))
+	assert.Equal(t, "2000-01-01T00:00:00Z", secret.Annotations[CollectedAtAnnotation])
+	assert.NotEmpty(t, collectedAt)
+}
+
+func Test_relatedLogLines(t *testing.T) {
+	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"}}
+	logs := strings.Join([]string{
+		`{"message":"Starting reconciliation run","namespace":"ns","es_name":"es"}`,
+		`{"message":"Starting reconciliation run","namespace":"ns","es_name":"other"}`,
+		`{"message":"Starting reconciliation run","namespace":"other","es_name":"es"}`,
+		`{"message":"Updating settings","namespace":"ns","es_name":"es","password":"changeme"}`,
+		`INFO	Ending reconciliation run	{"namespace": "ns", "es_name": "es", "password": "changeme"}`,
+		`INFO	Starting operator`,
+	}, "\n")
+	related, err := relatedLogLines([]byte(logs), es)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		`{"es_name":"es","message":"Starting reconciliation run","namespace":"ns"}`,
+		`{"es_name":"es","message":"Updating settings","namespace":"ns","password":"REDACTED"}`,
+		``,
+	}, "\n"), string(related))
+}
@@ -0,0 +1,72 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package diagnostics
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+)
+
+const (
+	// operatorLogsFile is the file holding the operator log lines related to the cluster in the bundle.
+	operatorLogsFile = "operator/logs.json"
+	// operatorLogsTailLines is the number of the most recent operator log lines searched for lines related to the cluster.
+	operatorLogsTailLines = 10000
+	// maxLogLineSize is the size of the largest operator log line that can be read.
+	maxLogLineSize = 1024 * 1024
+)
+
+// operatorLogsProvider returns the operator log lines related to the given cluster.
+type operatorLogsProvider func(ctx context.Context, es esv1.Elasticsearch) ([]byte, error)
+
+// newOperatorLogsProvider returns an operatorLogsProvider reading the logs of the given operator Pod. As a cluster is
+// only reconciled by the operator replica that owns it, this Pod is the one whose logs relate to the cluster.
+func newOperatorLogsProvider(clientset kubernetes.Interface, namespace, podName string) operatorLogsProvider {
+	return func(ctx context.Context, es esv1.Elasticsearch) ([]byte, error) {
+		logs, err := clientset.CoreV1().Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+			TailLines: ptr.To[int64](operatorLogsTailLines),
+		}).DoRaw(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return relatedLogLines(logs, es)
+	}
+}
+
+// relatedLogLines returns the log lines referring to the given cluster, with their sensitive fields redacted. Lines
+// that are not JSON documents, as written by the operator in development mode, cannot be redacted and are left out.
+func relatedLogLines(logs []byte, es esv1.Elasticsearch) ([]byte, error) {
+	var related bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	scanner.Buffer(nil, maxLogLineSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if entry["namespace"] != es.Namespace || entry["es_name"] != es.Name {
+			continue
+		}
+		redacted, err := json.Marshal(redact(entry))
+		if err != nil {
+			return nil, err
+		}
+		related.Write(redacted)
+		related.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return related.Bytes(), nil
+}
@@ -46,9 +46,13 @@ func ResolveConfig(ctx context.Context, client k8s.Client, es esv1.Elasticsearch
 		if nodeSpec.Config != nil {
 			userCfg = *nodeSpec.Config
 		}
+		dataTierCfg, err := settings.DataTierConfig(nodeSpec, ver)
+		if err != nil {
+			return nodespec.ResolvedConfig{}, err
+		}
 		clusterHasZoneAwareness := esv1.NodeSetList(es.Spec.NodeSets).HasZoneAwareness()
 		cfg, err := settings.NewMergedESConfig(
-			es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, policyConfig.ElasticsearchConfig,
+			es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, dataTierCfg, policyConfig.ElasticsearchConfig,
 			es.Spec.RemoteClusterServer.Enabled, es.HasRemoteClusterAPIKey(), clusterHasZoneAwareness,
 			clientAuthenticationRequired,
 		)
@@ -107,9 +111,13 @@ func detectClientAuthenticationRequired(
 			)
 		}
 		clusterHasZoneAwareness := esv1.NodeSetList(es.Spec.NodeSets).HasZoneAwareness()
+		dataTierCfg, err := settings.DataTierConfig(nodeSpec, ver)
+		if err != nil {
+			return false, "", err
+		}
 
 		cfg, err := settings.NewMergedESConfig(
-			es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, policyConfig.ElasticsearchConfig,
+			es.Name, ver, ipFamily, es.Spec.HTTP, userCfg, dataTierCfg, policyConfig.ElasticsearchConfig,
 			es.Spec.RemoteClusterServer.Enabled, es.HasRemoteClusterAPIKey(), clusterHasZoneAwareness,
 			false,
 		)
@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stateful
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+)
+
+// checkSearchableSnapshotRepositories records a warning event for each snapshot repository referenced by a data tier
+// which is not registered in Elasticsearch. Repositories are usually registered asynchronously, for example through a
+// StackConfigPolicy, hence a missing repository is reported but does not block the reconciliation.
+func checkSearchableSnapshotRepositories(
+	ctx context.Context,
+	es esv1.Elasticsearch,
+	esReachable bool,
+	snapshotClient esclient.SnapshotRepositoryClient,
+	recorder *events.Recorder,
+) error {
+	expected := esv1.NodeSetList(es.Spec.NodeSets).SearchableSnapshotRepositories()
+	if !esReachable || len(expected) == 0 {
+		return nil
+	}
+
+	registered, err := snapshotClient.GetSnapshotRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range expected {
+		if _, exists := registered[name]; exists {
+			continue
+		}
+		recorder.AddEvent(
+			corev1.EventTypeWarning,
+			events.EventReasonValidation,
+			events.EventActionValidation,
+			fmt.Sprintf("Searchable snapshot repository %s referenced by a data tier is not registered in Elasticsearch", name),
+		)
+	}
+	return nil
+}
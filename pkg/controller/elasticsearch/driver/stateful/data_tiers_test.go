@@ -0,0 +1,105 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stateful
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+)
+
+type fakeSnapshotRepositoryClient struct {
+	repositories esclient.SnapshotRepositories
+	err          error
+	called       bool
+}
+
+var _ esclient.SnapshotRepositoryClient = (*fakeSnapshotRepositoryClient)(nil)
+
+func (f *fakeSnapshotRepositoryClient) GetSnapshotRepositories(_ context.Context) (esclient.SnapshotRepositories, error) {
+	f.called = true
+	return f.repositories, f.err
+}
+
+func Test_checkSearchableSnapshotRepositories(t *testing.T) {
+	tieredES := esv1.Elasticsearch{
+		Spec: esv1.ElasticsearchSpec{
+			NodeSets: []esv1.NodeSet{
+				{Name: "hot", Tier: esv1.HotTier},
+				{Name: "cold", Tier: esv1.ColdTier, SearchableSnapshotRepository: "s3"},
+				{Name: "frozen", Tier: esv1.FrozenTier, SearchableSnapshotRepository: "gcs"},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		es          esv1.Elasticsearch
+		esReachable bool
+		client      *fakeSnapshotRepositoryClient
+		wantCalled  bool
+		wantEvents  int
+		wantErr     bool
+	}{
+		{
+			name:        "no repository referenced",
+			es:          esv1.Elasticsearch{Spec: esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "hot", Tier: esv1.HotTier}}}},
+			esReachable: true,
+			client:      &fakeSnapshotRepositoryClient{},
+		},
+		{
+			name:        "Elasticsearch not reachable",
+			es:          tieredES,
+			esReachable: false,
+			client:      &fakeSnapshotRepositoryClient{},
+		},
+		{
+			name:        "all repositories registered",
+			es:          tieredES,
+			esReachable: true,
+			client: &fakeSnapshotRepositoryClient{repositories: esclient.SnapshotRepositories{
+				"s3": {Type: "s3"}, "gcs": {Type: "gcs"},
+			}},
+			wantCalled: true,
+		},
+		{
+			name:        "one repository missing",
+			es:          tieredES,
+			esReachable: true,
+			client: &fakeSnapshotRepositoryClient{repositories: esclient.SnapshotRepositories{
+				"s3": {Type: "s3"},
+			}},
+			wantCalled: true,
+			wantEvents: 1,
+		},
+		{
+			name:        "error retrieving repositories",
+			es:          tieredES,
+			esReachable: true,
+			client:      &fakeSnapshotRepositoryClient{err: errors.New("boom")},
+			wantCalled:  true,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := events.NewRecorder()
+			err := checkSearchableSnapshotRepositories(context.Background(), tt.es, tt.esReachable, tt.client, recorder)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCalled, tt.client.called)
+			assert.Len(t, recorder.Events(), tt.wantEvents)
+		})
+	}
+}
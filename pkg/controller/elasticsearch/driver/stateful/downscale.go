@@ -53,7 +53,7 @@ func HandleDownscale(
 	downscaleCtx.reconcileState.RecordNodesToBeRemoved(desiredLeavingNodes)
 
 	// Compute the desired downscale, applying a budget filter to make sure we only downscale nodes we're allowed to.
-	downscaleState := newDownscaleState(actualPods, downscaleCtx.es)
+	downscaleState := newDownscaleState(actualPods, downscaleCtx.es, expectedStatefulSets)
 
 	// compute the list of StatefulSet downscales and deletions to perform
 	downscales, deletions := calculateDownscales(downscaleCtx.parentCtx, *downscaleState, expectedStatefulSets, actualStatefulSets, downscaleBudgetFilter)
@@ -100,7 +100,7 @@ func podsToDownscale(
 	actualStatefulSets es_sset.StatefulSetList,
 	downscaleFilter downscaleFilter,
 ) ([]ssetDownscale, es_sset.StatefulSetList) {
-	downscaleState := newDownscaleState(actualPods, es)
+	downscaleState := newDownscaleState(actualPods, es, expectedStatefulSets)
 	// compute the list of StatefulSet downscales and deletions to perform
 	return calculateDownscales(ctx, *downscaleState, expectedStatefulSets, actualStatefulSets, downscaleFilter)
 }
@@ -11,12 +11,14 @@ import (
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
 )
 
 const (
 	OneMasterAtATimeInvariant        = "A master node is already in the process of being removed"
 	AtLeastOneRunningMasterInvariant = "Cannot remove the last running master node"
 	RespectMaxUnavailableInvariant   = "Not removing node to respect maxUnavailable setting"
+	ZoneDataTierInvariant            = "Cannot remove the last data node of a tier from a zone with forced zone awareness"
 )
 
 // checkDownscaleInvariants returns the number of nodes that can be removed if the given state state allows downscaling
@@ -31,6 +33,10 @@ func checkDownscaleInvariants(state downscaleState, statefulSet appsv1.StatefulS
 		}
 		requestedDeletes = 1 // only one removal allowed for masters
 	}
+	requestedDeletes = state.zoneDataNodes.allowedDeletes(statefulSet, requestedDeletes)
+	if requestedDeletes == 0 {
+		return 0, ZoneDataTierInvariant
+	}
 	allowedDeletes := state.getMaxNodesToRemove(requestedDeletes)
 
 	if allowedDeletes == 0 {
@@ -49,10 +55,12 @@ type downscaleState struct {
 	removalsAllowed *int32
 	// masterRemovalInProgress indicates whether a master node is in the process of being removed already.
 	masterRemovalInProgress bool
+	// zoneDataNodes tracks the data nodes per tier and zone when forced zone awareness is enabled, nil otherwise.
+	zoneDataNodes *zoneDataNodes
 }
 
 // newDownscaleState creates a new downscaleState.
-func newDownscaleState(actualPods []corev1.Pod, es esv1.Elasticsearch) *downscaleState {
+func newDownscaleState(actualPods []corev1.Pod, es esv1.Elasticsearch, expectedStatefulSets es_sset.StatefulSetList) *downscaleState {
 	// retrieve the number of masters running ready
 	mastersReady := reconcile.AvailableElasticsearchNodes(label.FilterMasterNodePods(actualPods))
 	nodesReady := reconcile.AvailableElasticsearchNodes(actualPods)
@@ -64,6 +72,7 @@ func newDownscaleState(actualPods []corev1.Pod, es esv1.Elasticsearch) *downscal
 			int32(len(nodesReady)),
 			es.Spec.NodeCount(),
 			es.Spec.UpdateStrategy.ChangeBudget.GetMaxUnavailableOrDefault()),
+		zoneDataNodes: newZoneDataNodes(es, actualPods, expectedStatefulSets),
 	}
 }
 
@@ -100,6 +109,8 @@ func (s *downscaleState) recordNodeRemoval(statefulSet appsv1.StatefulSet, accou
 		s.runningMasters--
 	}
 
+	s.zoneDataNodes.recordRemovals(statefulSet, accountedRemovals)
+
 	if s.removalsAllowed != nil {
 		*s.removalsAllowed -= accountedRemovals
 	}
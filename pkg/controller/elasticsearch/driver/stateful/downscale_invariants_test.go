@@ -125,7 +125,7 @@ func Test_newDownscaleState(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := newDownscaleState(tt.actualPods, es)
+			got := newDownscaleState(tt.actualPods, es, nil)
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewDownscaleInvariants() got = %v, want %v", got, tt.want)
 			}
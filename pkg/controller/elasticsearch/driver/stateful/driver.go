@@ -62,6 +62,14 @@ func (d *Driver) Reconcile(ctx context.Context) *reconciler.Results {
 	results.WithError(d.maybeSetServiceAccountsOrchestrationHint(
 		ctx, sharedState.ESReachable, sharedState.ESClient, sharedState.ResourcesState))
 
+	// Stateful specific: Searchable snapshot repositories referenced by data tiers
+	results.WithError(checkSearchableSnapshotRepositories(
+		ctx, d.ES, sharedState.ESReachable, sharedState.ESClient, d.ReconcileState.Recorder))
+
+	// Stateful specific: Forced zone awareness and zones balance
+	results.WithError(d.reconcileForcedAwareness(ctx, sharedState.ESReachable, sharedState.ESClient))
+	results.WithError(d.reportZonesBalance())
+
 	// Stateful specific: Suspended pods
 	// We want to reconcile suspended Pods before we start reconciling node specs as this is considered a debugging and
 	// troubleshooting tool that does not follow the change budget restrictions
@@ -99,6 +99,12 @@ func (d *Driver) reconcileNodeSpecs(
 		}
 	}
 
+	// Adopt the released volumes of a deleted cluster before the StatefulSets are created.
+	if err := adoptReleasedVolumes(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets()); err != nil {
+		reconcileState.UpdateElasticsearchInvalidWithEvent(events.EventActionVolumeAdoption, fmt.Sprintf("Failed to adopt released volumes: %v", err))
+		return results.WithError(err)
+	}
+
 	// Phase 1: apply expected StatefulSets resources and scale up.
 	upscaleCtx := upscaleCtx{
 		parentCtx:            ctx,
@@ -145,6 +151,10 @@ func (d *Driver) reconcileNodeSpecs(
 		return results.WithError(err)
 	}
 
+	if err := annotateVolumesWithClusterUUID(ctx, d.K8sClient(), d.ES); err != nil {
+		return results.WithError(err)
+	}
+
 	if err := GarbageCollectPVCs(ctx, d.K8sClient(), d.ES, actualStatefulSets, expectedResources.StatefulSets()); err != nil {
 		return results.WithError(err)
 	}
@@ -0,0 +1,248 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stateful
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/bootstrap"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
+	es_sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+// volumeAdoption matches a Released PersistentVolume of a deleted cluster with the claim of a node of the new cluster.
+type volumeAdoption struct {
+	claim  corev1.PersistentVolumeClaim
+	volume corev1.PersistentVolume
+}
+
+// adoptReleasedVolumes pre-creates the PersistentVolumeClaims of a new cluster annotated with the
+// AdoptVolumesFromAnnotation, bound to the Released PersistentVolumes of the deleted cluster, before the StatefulSets
+// are created. The nodeSets of both clusters must have the same names and counts. Volumes are only adopted if all the
+// expected claims can be matched, and if all of them are annotated with the cluster UUID identified by the
+// ClusterUUIDAnnotationName annotation.
+func adoptReleasedVolumes(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	actualStatefulSets es_sset.StatefulSetList,
+	expectedStatefulSets es_sset.StatefulSetList,
+) error {
+	fromCluster, adopt := es.Annotations[esv1.AdoptVolumesFromAnnotation]
+	if !adopt || len(actualStatefulSets) > 0 {
+		// volumes can only be adopted when the cluster is created
+		return nil
+	}
+	clusterUUID := es.Annotations[bootstrap.ClusterUUIDAnnotationName]
+	if fromCluster == "" || clusterUUID == "" {
+		// should not happen because of the pre-creation validation
+		return fmt.Errorf("both %s and %s annotations must be set to adopt released volumes",
+			esv1.AdoptVolumesFromAnnotation, bootstrap.ClusterUUIDAnnotationName)
+	}
+	if fromCluster != es.Name {
+		var previous esv1.Elasticsearch
+		err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: fromCluster}, &previous)
+		if err == nil {
+			return fmt.Errorf("cannot adopt the volumes of Elasticsearch %s/%s which still exists", es.Namespace, fromCluster)
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	var pvs corev1.PersistentVolumeList
+	if err := c.List(ctx, &pvs); err != nil {
+		return fmt.Errorf("while listing persistent volumes to adopt: %w", err)
+	}
+
+	adoptions, err := matchReleasedVolumes(ctx, c, es, fromCluster, clusterUUID, pvs.Items, expectedStatefulSets)
+	if err != nil {
+		return err
+	}
+
+	log := ulog.FromContext(ctx)
+	for _, adoption := range adoptions {
+		// point the volume to the new claim first, so that it cannot be bound to another claim
+		if adoption.volume.Spec.ClaimRef.Name != adoption.claim.Name || adoption.volume.Spec.ClaimRef.UID != "" {
+			log.Info("Adopting released persistent volume", "namespace", es.Namespace, "es_name", es.Name,
+				"pv_name", adoption.volume.Name, "pvc_name", adoption.claim.Name)
+			adoption.volume.Spec.ClaimRef = &corev1.ObjectReference{
+				Kind:       "PersistentVolumeClaim",
+				APIVersion: "v1",
+				Namespace:  adoption.claim.Namespace,
+				Name:       adoption.claim.Name,
+			}
+			if err := c.Update(ctx, &adoption.volume); err != nil {
+				return fmt.Errorf("while updating persistent volume %s to adopt: %w", adoption.volume.Name, err)
+			}
+		}
+		if err := c.Create(ctx, &adoption.claim); err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("while creating persistent volume claim %s: %w", adoption.claim.Name, err)
+		}
+	}
+	return nil
+}
+
+// matchReleasedVolumes returns the claims to create for the expected StatefulSets along with the volumes they adopt.
+// Claims which already exist are ignored.
+func matchReleasedVolumes(
+	ctx context.Context,
+	c k8s.Client,
+	es esv1.Elasticsearch,
+	fromCluster string,
+	clusterUUID string,
+	pvs []corev1.PersistentVolume,
+	expectedStatefulSets es_sset.StatefulSetList,
+) ([]volumeAdoption, error) {
+	// index volumes by the claim they are, or were, bound to
+	volumesByClaim := make(map[string]corev1.PersistentVolume, len(pvs))
+	for _, pv := range pvs {
+		if pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != es.Namespace {
+			continue
+		}
+		volumesByClaim[pv.Spec.ClaimRef.Name] = pv
+	}
+
+	var adoptions []volumeAdoption
+	for _, nodeSet := range es.Spec.NodeSets {
+		statefulSet, exists := expectedStatefulSets.GetByName(esv1.StatefulSet(es.Name, nodeSet.Name))
+		if !exists {
+			continue
+		}
+		for ordinal := int32(0); ordinal < sset.GetReplicas(statefulSet); ordinal++ {
+			for _, claimTemplate := range statefulSet.Spec.VolumeClaimTemplates {
+				claimName := fmt.Sprintf("%s-%s", claimTemplate.Name, sset.PodName(statefulSet.Name, ordinal))
+				err := c.Get(ctx, types.NamespacedName{Namespace: es.Namespace, Name: claimName}, &corev1.PersistentVolumeClaim{})
+				if err == nil {
+					// already created during a previous reconciliation
+					continue
+				}
+				if !apierrors.IsNotFound(err) {
+					return nil, err
+				}
+
+				// the volume is either still released from the claim of the deleted cluster, or already adopted
+				// during a previous reconciliation which did not complete
+				previousClaimName := fmt.Sprintf("%s-%s", claimTemplate.Name, sset.PodName(esv1.StatefulSet(fromCluster, nodeSet.Name), ordinal))
+				pv, found := volumesByClaim[previousClaimName]
+				if !found || pv.Status.Phase != corev1.VolumeReleased {
+					pv, found = volumesByClaim[claimName]
+					found = found && pv.Spec.ClaimRef.UID == ""
+				}
+				if !found {
+					return nil, fmt.Errorf("no released persistent volume found for claim %s of Elasticsearch %s/%s",
+						previousClaimName, es.Namespace, fromCluster)
+				}
+				// a volume that was never annotated cannot be proven to belong to the expected cluster
+				if pvUUID := pv.Annotations[bootstrap.ClusterUUIDAnnotationName]; pvUUID != clusterUUID {
+					return nil, fmt.Errorf("persistent volume %s belongs to cluster %q, not to cluster %s",
+						pv.Name, pvUUID, clusterUUID)
+				}
+				if capacity, requested := pv.Spec.Capacity[corev1.ResourceStorage], claimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]; capacity.Cmp(requested) < 0 {
+					return nil, fmt.Errorf("persistent volume %s capacity %s is lower than the %s requested by claim %s",
+						pv.Name, capacity.String(), requested.String(), claimName)
+				}
+
+				adoptions = append(adoptions, volumeAdoption{
+					claim:  adoptionClaim(claimTemplate, claimName, statefulSet, pv),
+					volume: pv,
+				})
+			}
+		}
+	}
+	return adoptions, nil
+}
+
+// adoptionClaim builds the claim the StatefulSet controller would create from the given template, bound to the given volume.
+func adoptionClaim(
+	claimTemplate corev1.PersistentVolumeClaim,
+	claimName string,
+	statefulSet appsv1.StatefulSet,
+	pv corev1.PersistentVolume,
+) corev1.PersistentVolumeClaim {
+	labels := maps.Clone(claimTemplate.Labels)
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	if statefulSet.Spec.Selector != nil {
+		maps.Copy(labels, statefulSet.Spec.Selector.MatchLabels)
+	}
+	claim := corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        claimName,
+			Namespace:   statefulSet.Namespace,
+			Labels:      labels,
+			Annotations: claimTemplate.Annotations,
+		},
+		Spec: *claimTemplate.Spec.DeepCopy(),
+	}
+	claim.Spec.VolumeName = pv.Name
+	claim.Spec.StorageClassName = &pv.Spec.StorageClassName
+	return claim
+}
+
+// annotateVolumesWithClusterUUID annotates the PersistentVolumes bound to the claims of the given cluster with its UUID,
+// so that a new cluster adopting these volumes once released can check they are the expected ones. Claims are annotated
+// as well once their volume is, so that volumes are only retrieved until they are annotated.
+func annotateVolumesWithClusterUUID(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) error {
+	clusterUUID, bootstrapped := es.Annotations[bootstrap.ClusterUUIDAnnotationName]
+	if !bootstrapped {
+		return nil
+	}
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := c.List(ctx, &pvcs, client.InNamespace(es.Namespace), label.NewLabelSelectorForElasticsearch(es)); err != nil {
+		return fmt.Errorf("while listing pvcs to annotate volumes: %w", err)
+	}
+	for _, pvc := range pvcs.Items {
+		if pvc.Status.Phase != corev1.ClaimBound || pvc.Spec.VolumeName == "" {
+			continue
+		}
+		if _, annotated := pvc.Annotations[bootstrap.ClusterUUIDAnnotationName]; annotated {
+			continue
+		}
+		var pv corev1.PersistentVolume
+		err := c.Get(ctx, types.NamespacedName{Name: pvc.Spec.VolumeName}, &pv)
+		if apierrors.IsForbidden(err) {
+			// the operator may not be allowed to access cluster-scoped resources
+			ulog.FromContext(ctx).V(1).Info("Not allowed to annotate persistent volumes with the cluster UUID",
+				"namespace", es.Namespace, "es_name", es.Name)
+			return nil
+		}
+		if err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		// the annotation of an adopted volume is kept as is
+		if _, annotated := pv.Annotations[bootstrap.ClusterUUIDAnnotationName]; !annotated {
+			if pv.Annotations == nil {
+				pv.Annotations = map[string]string{}
+			}
+			pv.Annotations[bootstrap.ClusterUUIDAnnotationName] = clusterUUID
+			if err := c.Update(ctx, &pv); err != nil {
+				return fmt.Errorf("while annotating persistent volume %s with the cluster UUID: %w", pv.Name, err)
+			}
+		}
+		if pvc.Annotations == nil {
+			pvc.Annotations = map[string]string{}
+		}
+		pvc.Annotations[bootstrap.ClusterUUIDAnnotationName] = pv.Annotations[bootstrap.ClusterUUIDAnnotationName]
+		if err := c.Update(ctx, &pvc); err != nil {
+			return fmt.Errorf("while annotating persistent volume claim %s with the cluster UUID: %w", pvc.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stateful
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/bootstrap"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func Test_adoptReleasedVolumes(t *testing.T) {
+	const clusterUUID = "4rtb2VFmRgmXFXW3Mg9szg"
+
+	esFixture := func(annotations map[string]string) esv1.Elasticsearch {
+		return esv1.Elasticsearch{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "ns", Annotations: annotations},
+			Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Name: "default", Count: 2}}},
+		}
+	}
+	adoptAnnotations := map[string]string{
+		esv1.AdoptVolumesFromAnnotation:     "old",
+		bootstrap.ClusterUUIDAnnotationName: clusterUUID,
+	}
+	expectedStatefulSets := sset.StatefulSetList{{
+		ObjectMeta: metav1.ObjectMeta{Name: "new-es-default", Namespace: "ns"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: ptr.To[int32](2),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				label.ClusterNameLabelName:     "new",
+				label.StatefulSetNameLabelName: "new-es-default",
+			}},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{
+				ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-data"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}},
+		},
+	}}
+	owned := map[string]string{bootstrap.ClusterUUIDAnnotationName: clusterUUID}
+	pvFixture := func(name, claimName string, phase corev1.PersistentVolumePhase, annotations map[string]string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations},
+			Spec: corev1.PersistentVolumeSpec{
+				Capacity:         corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+				StorageClassName: "standard",
+				ClaimRef:         &corev1.ObjectReference{Namespace: "ns", Name: claimName, UID: "old-uid"},
+			},
+			Status: corev1.PersistentVolumeStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		es          esv1.Elasticsearch
+		actual      sset.StatefulSetList
+		objects     []client.Object
+		wantErr     bool
+		wantAdopted map[string]string
+	}{
+		{
+			name:    "no adoption requested",
+			es:      esFixture(nil),
+			objects: []client.Object{pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, nil)},
+		},
+		{
+			name:    "StatefulSets already exist",
+			es:      esFixture(adoptAnnotations),
+			actual:  expectedStatefulSets,
+			objects: []client.Object{pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, nil)},
+		},
+		{
+			name: "adopt released volumes",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, owned),
+				pvFixture("pv-1", "elasticsearch-data-old-es-default-1", corev1.VolumeReleased, owned),
+				pvFixture("pv-other", "elasticsearch-data-other-es-default-0", corev1.VolumeReleased, nil),
+			},
+			wantAdopted: map[string]string{
+				"elasticsearch-data-new-es-default-0": "pv-0",
+				"elasticsearch-data-new-es-default-1": "pv-1",
+			},
+		},
+		{
+			name: "resume an interrupted adoption",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				func() client.Object {
+					pv := pvFixture("pv-0", "elasticsearch-data-new-es-default-0", corev1.VolumeAvailable, owned)
+					pv.Spec.ClaimRef.UID = ""
+					return pv
+				}(),
+				pvFixture("pv-1", "elasticsearch-data-old-es-default-1", corev1.VolumeReleased, owned),
+			},
+			wantAdopted: map[string]string{
+				"elasticsearch-data-new-es-default-0": "pv-0",
+				"elasticsearch-data-new-es-default-1": "pv-1",
+			},
+		},
+		{
+			name: "missing volume",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "volume still bound",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, nil),
+				pvFixture("pv-1", "elasticsearch-data-old-es-default-1", corev1.VolumeBound, nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster UUID mismatch",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, nil),
+				pvFixture("pv-1", "elasticsearch-data-old-es-default-1", corev1.VolumeReleased, map[string]string{bootstrap.ClusterUUIDAnnotationName: "another-uuid"}),
+			},
+			wantErr: true,
+		},
+		{
+			name: "cluster UUID annotation missing",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, owned),
+				pvFixture("pv-1", "elasticsearch-data-old-es-default-1", corev1.VolumeReleased, nil),
+			},
+			wantErr: true,
+		},
+		{
+			name: "previous cluster still exists",
+			es:   esFixture(adoptAnnotations),
+			objects: []client.Object{
+				&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "ns"}},
+				pvFixture("pv-0", "elasticsearch-data-old-es-default-0", corev1.VolumeReleased, nil),
+				pvFixture("pv-1", "elasticsearch-data-old-es-default-1", corev1.VolumeReleased, nil),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := k8s.NewFakeClient(tt.objects...)
+			err := adoptReleasedVolumes(context.Background(), c, tt.es, tt.actual, expectedStatefulSets)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			var pvcs corev1.PersistentVolumeClaimList
+			require.NoError(t, c.List(context.Background(), &pvcs))
+			adopted := map[string]string{}
+			for _, pvc := range pvcs.Items {
+				adopted[pvc.Name] = pvc.Spec.VolumeName
+				assert.Equal(t, "new", pvc.Labels[label.ClusterNameLabelName])
+				assert.Equal(t, "standard", *pvc.Spec.StorageClassName)
+
+				var pv corev1.PersistentVolume
+				require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: pvc.Spec.VolumeName}, &pv))
+				assert.Equal(t, "ns", pv.Spec.ClaimRef.Namespace)
+				assert.Equal(t, pvc.Name, pv.Spec.ClaimRef.Name)
+				assert.Empty(t, pv.Spec.ClaimRef.UID)
+			}
+			if tt.wantAdopted == nil {
+				// no claim is created unless all volumes can be adopted
+				assert.Empty(t, adopted)
+				return
+			}
+			assert.Equal(t, tt.wantAdopted, adopted)
+		})
+	}
+}
+
+func Test_annotateVolumesWithClusterUUID(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "es",
+			Namespace:   "ns",
+			Annotations: map[string]string{bootstrap.ClusterUUIDAnnotationName: "4rtb2VFmRgmXFXW3Mg9szg"},
+		},
+	}
+	pvc := func(name, volumeName string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ns", Labels: map[string]string{label.ClusterNameLabelName: "es"}},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	c := k8s.NewFakeClient(
+		pvc("elasticsearch-data-es-es-default-0", "pv-0", corev1.ClaimBound),
+		pvc("elasticsearch-data-es-es-default-1", "", corev1.ClaimPending),
+		pvc("elasticsearch-data-es-es-default-2", "pv-2", corev1.ClaimBound),
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-0"}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-2", Annotations: map[string]string{bootstrap.ClusterUUIDAnnotationName: "another-uuid"}}},
+		&corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: "pv-unrelated"}},
+	)
+
+	require.NoError(t, annotateVolumesWithClusterUUID(context.Background(), c, es))
+
+	var pv corev1.PersistentVolume
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "pv-0"}, &pv))
+	assert.Equal(t, "4rtb2VFmRgmXFXW3Mg9szg", pv.Annotations[bootstrap.ClusterUUIDAnnotationName])
+	// an existing annotation is not overwritten
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "pv-2"}, &pv))
+	assert.Equal(t, "another-uuid", pv.Annotations[bootstrap.ClusterUUIDAnnotationName])
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "pv-unrelated"}, &pv))
+	assert.Empty(t, pv.Annotations)
+
+	// claims are marked so that their volume is not retrieved again
+	var claim corev1.PersistentVolumeClaim
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "elasticsearch-data-es-es-default-0"}, &claim))
+	assert.Equal(t, "4rtb2VFmRgmXFXW3Mg9szg", claim.Annotations[bootstrap.ClusterUUIDAnnotationName])
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: "elasticsearch-data-es-es-default-1"}, &claim))
+	assert.Empty(t, claim.Annotations)
+}
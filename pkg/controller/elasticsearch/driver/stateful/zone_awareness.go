@@ -0,0 +1,247 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stateful
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/statefulset"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
+	es_sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/set"
+)
+
+// dataRoleLabels are the labels of the node roles holding data, used to group data nodes by tier.
+var dataRoleLabels = []string{
+	string(label.NodeTypesDataLabelName),
+	string(label.NodeTypesDataHotLabelName),
+	string(label.NodeTypesDataWarmLabelName),
+	string(label.NodeTypesDataColdLabelName),
+	string(label.NodeTypesDataFrozenLabelName),
+	string(label.NodeTypesDataContentLabelName),
+}
+
+// reconcileForcedAwareness sets the forced awareness zone values through the cluster settings API when the forced zone
+// awareness mode is enabled, and resets the setting once the mode is disabled. The setting is only reset if it has
+// been previously set by the operator, to not interfere with a user-managed setting.
+func (d *Driver) reconcileForcedAwareness(ctx context.Context, esReachable bool, awarenessClient esclient.ForcedAwarenessClient) error {
+	expected := esv1.NodeSetList(d.ES.Spec.NodeSets).ForcedAwarenessZones()
+	managed := d.ReconcileState.OrchestrationHints().ForcedAwareness.ManagedZones()
+	if !esReachable || (len(expected) == 0 && len(managed) == 0) {
+		return nil
+	}
+
+	current, err := awarenessClient.GetForcedAwarenessZones(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(current)), expected) {
+		if err := awarenessClient.UpdateForcedAwarenessZones(ctx, expected); err != nil {
+			return err
+		}
+	}
+
+	if !slices.Equal(managed, expected) {
+		d.ReconcileState.UpdateOrchestrationHints(
+			d.ReconcileState.OrchestrationHints().Merge(hints.OrchestrationsHints{ForcedAwareness: &hints.ForcedAwarenessHint{Zones: expected}}),
+		)
+	}
+	return nil
+}
+
+// reportZonesBalance reports through the ZonesBalanced condition whether the Pods of the zone-aware NodeSets are evenly
+// spread across zones.
+func (d *Driver) reportZonesBalance() error {
+	nodeSets := esv1.NodeSetList(d.ES.Spec.NodeSets)
+	if !nodeSets.HasZoneAwareness() {
+		return nil
+	}
+	actualPods, err := es_sset.GetActualPodsForCluster(d.Client, d.ES)
+	if err != nil {
+		return err
+	}
+	if imbalances := zoneImbalances(d.ES, actualPods); len(imbalances) > 0 {
+		d.ReconcileState.ReportCondition(esv1.ZonesBalanced, corev1.ConditionFalse, "Unbalanced Pods across zones: "+strings.Join(imbalances, "; "))
+		return nil
+	}
+	d.ReconcileState.ReportCondition(esv1.ZonesBalanced, corev1.ConditionTrue, "Pods are balanced across zones")
+	return nil
+}
+
+// zoneImbalances returns a description of each zone-aware NodeSet whose Pods are not evenly spread across zones, that
+// is when the number of Pods differs by more than one between two zones. Declared zones without any Pod are accounted
+// for, Pods whose zone is not known yet are ignored.
+func zoneImbalances(es esv1.Elasticsearch, actualPods []corev1.Pod) []string {
+	var imbalances []string
+	for _, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.ZoneAwareness == nil {
+			continue
+		}
+		topologyKey := nodeSet.ZoneAwareness.TopologyKeyOrDefault()
+		ssetName := esv1.StatefulSet(es.Name, nodeSet.Name)
+		podsPerZone := map[string]int{}
+		for _, zone := range nodeSet.ZoneAwareness.Zones {
+			podsPerZone[zone] = 0
+		}
+		for _, pod := range actualPods {
+			zone := pod.Annotations[topologyKey]
+			if pod.Labels[label.StatefulSetNameLabelName] != ssetName || zone == "" {
+				continue
+			}
+			podsPerZone[zone]++
+		}
+		if len(podsPerZone) < 2 {
+			continue
+		}
+		minPods, maxPods := int(nodeSet.Count), 0
+		for _, count := range podsPerZone {
+			minPods = min(minPods, count)
+			maxPods = max(maxPods, count)
+		}
+		if maxPods-minPods > 1 {
+			imbalances = append(imbalances, fmt.Sprintf("%s %s", nodeSet.Name, formatPodsPerZone(podsPerZone)))
+		}
+	}
+	return imbalances
+}
+
+func formatPodsPerZone(podsPerZone map[string]int) string {
+	zones := make([]string, 0, len(podsPerZone))
+	for zone := range podsPerZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	counts := make([]string, len(zones))
+	for i, zone := range zones {
+		counts[i] = fmt.Sprintf("%s=%d", zone, podsPerZone[zone])
+	}
+	return "(" + strings.Join(counts, ", ") + ")"
+}
+
+// zoneDataNodes tracks the data nodes per data role and per zone, to prevent a downscale from removing the last data
+// node of a tier from a zone when the forced zone awareness mode is enabled.
+type zoneDataNodes struct {
+	topologyKey string
+	// pods are the actual Pods of the cluster, indexed by name.
+	pods map[string]corev1.Pod
+	// nodes is the number of data nodes per data role label and per zone.
+	nodes map[string]map[string]int
+	// expectedRoles are the data role labels of the expected StatefulSets. Removing all the nodes of a role that is
+	// not expected anymore is allowed.
+	expectedRoles set.StringSet
+}
+
+// newZoneDataNodes returns the data nodes per role and zone, or nil if the forced zone awareness mode is not enabled.
+func newZoneDataNodes(es esv1.Elasticsearch, actualPods []corev1.Pod, expectedStatefulSets es_sset.StatefulSetList) *zoneDataNodes {
+	nodeSets := esv1.NodeSetList(es.Spec.NodeSets)
+	if len(nodeSets.ForcedAwarenessZones()) == 0 {
+		return nil
+	}
+	z := &zoneDataNodes{
+		topologyKey:   nodeSets.ZoneAwarenessTopologyKey(),
+		pods:          make(map[string]corev1.Pod, len(actualPods)),
+		nodes:         map[string]map[string]int{},
+		expectedRoles: set.Make(),
+	}
+	for _, pod := range actualPods {
+		z.pods[pod.Name] = pod
+		zone := pod.Annotations[z.topologyKey]
+		if zone == "" {
+			continue
+		}
+		for _, role := range dataRoles(pod.Labels) {
+			if z.nodes[role] == nil {
+				z.nodes[role] = map[string]int{}
+			}
+			z.nodes[role][zone]++
+		}
+	}
+	for _, expected := range expectedStatefulSets {
+		if sset.GetReplicas(expected) > 0 {
+			z.expectedRoles.MergeWith(set.Make(dataRoles(expected.Spec.Template.Labels)...))
+		}
+	}
+	return z
+}
+
+// dataRoles returns the data role labels set to true in the given labels.
+func dataRoles(labels map[string]string) []string {
+	var roles []string
+	for _, role := range dataRoleLabels {
+		if labels[role] == "true" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// leavingPods returns the Pods removed by a downscale of the given StatefulSet, starting with the highest ordinal.
+// Pods whose zone is not known are ignored.
+func (z *zoneDataNodes) leavingPods(statefulSet appsv1.StatefulSet, deletes int32) []corev1.Pod {
+	replicas := sset.GetReplicas(statefulSet)
+	pods := make([]corev1.Pod, 0, deletes)
+	for i := int32(0); i < deletes; i++ {
+		pod, exists := z.pods[sset.PodName(statefulSet.Name, replicas-1-i)]
+		if exists && pod.Annotations[z.topologyKey] != "" {
+			pods = append(pods, pod)
+		}
+	}
+	return pods
+}
+
+// allowedDeletes returns how many of the requested deletes can be performed on the given StatefulSet without leaving
+// a zone without any data node of a tier that is still expected in the cluster.
+func (z *zoneDataNodes) allowedDeletes(statefulSet appsv1.StatefulSet, requestedDeletes int32) int32 {
+	if z == nil {
+		return requestedDeletes
+	}
+	replicas := sset.GetReplicas(statefulSet)
+	removed := map[string]map[string]int{}
+	for i := int32(0); i < requestedDeletes; i++ {
+		pod, exists := z.pods[sset.PodName(statefulSet.Name, replicas-1-i)]
+		zone := pod.Annotations[z.topologyKey]
+		if !exists || zone == "" {
+			continue
+		}
+		for _, role := range dataRoles(pod.Labels) {
+			if !z.expectedRoles.Has(role) {
+				continue
+			}
+			if z.nodes[role][zone]-removed[role][zone] <= 1 {
+				return i
+			}
+		}
+		for _, role := range dataRoles(pod.Labels) {
+			if removed[role] == nil {
+				removed[role] = map[string]int{}
+			}
+			removed[role][zone]++
+		}
+	}
+	return requestedDeletes
+}
+
+// recordRemovals updates the data nodes to account for the removal of the given number of Pods from the StatefulSet.
+func (z *zoneDataNodes) recordRemovals(statefulSet appsv1.StatefulSet, deletes int32) {
+	if z == nil {
+		return
+	}
+	for _, pod := range z.leavingPods(statefulSet, deletes) {
+		zone := pod.Annotations[z.topologyKey]
+		for _, role := range dataRoles(pod.Labels) {
+			z.nodes[role][zone]--
+		}
+	}
+}
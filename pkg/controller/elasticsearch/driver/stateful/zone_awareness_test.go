@@ -0,0 +1,248 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package stateful
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/statefulset"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/driver"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/hints"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/reconcile"
+	es_sset "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
+)
+
+type fakeForcedAwarenessClient struct {
+	zones   []string
+	updated bool
+}
+
+var _ esclient.ForcedAwarenessClient = (*fakeForcedAwarenessClient)(nil)
+
+func (f *fakeForcedAwarenessClient) GetForcedAwarenessZones(_ context.Context) ([]string, error) {
+	return f.zones, nil
+}
+
+func (f *fakeForcedAwarenessClient) UpdateForcedAwarenessZones(_ context.Context, zones []string) error {
+	f.updated = true
+	f.zones = zones
+	return nil
+}
+
+func zoneAwareES(mode esv1.ZoneAwarenessMode, zones ...string) esv1.Elasticsearch {
+	return esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			NodeSets: []esv1.NodeSet{
+				{Name: "hot", Count: 3, ZoneAwareness: &esv1.ZoneAwareness{Mode: mode, Zones: zones}},
+			},
+		},
+	}
+}
+
+func TestDriver_reconcileForcedAwareness(t *testing.T) {
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		managedZones []string
+		currentZones []string
+		wantUpdated  bool
+		wantZones    []string
+		wantHint     *hints.ForcedAwarenessHint
+	}{
+		{
+			name:        "forced mode disabled and setting not managed",
+			es:          zoneAwareES(esv1.ZoneAwarenessAttributesMode, "a", "b"),
+			wantUpdated: false,
+		},
+		{
+			name:        "forced mode enabled: set the zones",
+			es:          zoneAwareES(esv1.ZoneAwarenessForcedMode, "b", "a"),
+			wantUpdated: true,
+			wantZones:   []string{"a", "b"},
+			wantHint:    &hints.ForcedAwarenessHint{Zones: []string{"a", "b"}},
+		},
+		{
+			name:         "forced mode enabled: zones already set",
+			es:           zoneAwareES(esv1.ZoneAwarenessForcedMode, "a", "b"),
+			managedZones: []string{"a", "b"},
+			currentZones: []string{"b", "a"},
+			wantUpdated:  false,
+			wantZones:    []string{"b", "a"},
+			wantHint:     &hints.ForcedAwarenessHint{Zones: []string{"a", "b"}},
+		},
+		{
+			name:         "forced mode disabled: reset the zones previously set",
+			es:           zoneAwareES(esv1.ZoneAwarenessAttributesMode, "a", "b"),
+			managedZones: []string{"a", "b"},
+			currentZones: []string{"a", "b"},
+			wantUpdated:  true,
+			wantZones:    nil,
+			wantHint:     &hints.ForcedAwarenessHint{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconcileState := reconcile.MustNewState(tt.es)
+			if tt.managedZones != nil {
+				reconcileState.UpdateOrchestrationHints(hints.OrchestrationsHints{ForcedAwareness: &hints.ForcedAwarenessHint{Zones: tt.managedZones}})
+			}
+			d := &Driver{BaseDriver: driver.BaseDriver{Parameters: driver.Parameters{ES: tt.es, ReconcileState: reconcileState}}}
+			client := &fakeForcedAwarenessClient{zones: tt.currentZones}
+
+			require.NoError(t, d.reconcileForcedAwareness(context.Background(), true, client))
+			assert.Equal(t, tt.wantUpdated, client.updated)
+			assert.Equal(t, tt.wantZones, client.zones)
+			assert.Equal(t, tt.wantHint, reconcileState.OrchestrationHints().ForcedAwareness)
+		})
+	}
+}
+
+func zonePod(ssetName string, ordinal int32, zone string, dataRoles ...string) corev1.Pod {
+	labels := map[string]string{label.StatefulSetNameLabelName: ssetName}
+	for _, role := range dataRoles {
+		labels[role] = "true"
+	}
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        sset.PodName(ssetName, ordinal),
+			Labels:      labels,
+			Annotations: map[string]string{esv1.DefaultZoneAwarenessTopologyKey: zone},
+		},
+	}
+}
+
+func Test_zoneImbalances(t *testing.T) {
+	hotSset := esv1.StatefulSet("es", "hot")
+	tests := []struct {
+		name string
+		es   esv1.Elasticsearch
+		pods []corev1.Pod
+		want []string
+	}{
+		{
+			name: "balanced",
+			es:   zoneAwareES(esv1.ZoneAwarenessAttributesMode, "a", "b", "c"),
+			pods: []corev1.Pod{zonePod(hotSset, 0, "a"), zonePod(hotSset, 1, "b"), zonePod(hotSset, 2, "c")},
+		},
+		{
+			name: "declared zone without any Pod",
+			es:   zoneAwareES(esv1.ZoneAwarenessAttributesMode, "a", "b", "c"),
+			pods: []corev1.Pod{zonePod(hotSset, 0, "a"), zonePod(hotSset, 1, "a"), zonePod(hotSset, 2, "b")},
+			want: []string{"hot (a=2, b=1, c=0)"},
+		},
+		{
+			name: "observed zones when no zone is declared",
+			es:   zoneAwareES(esv1.ZoneAwarenessAttributesMode),
+			pods: []corev1.Pod{zonePod(hotSset, 0, "a"), zonePod(hotSset, 1, "a"), zonePod(hotSset, 2, "a"), zonePod(hotSset, 3, "b")},
+			want: []string{"hot (a=3, b=1)"},
+		},
+		{
+			name: "Pods without zone are ignored",
+			es:   zoneAwareES(esv1.ZoneAwarenessAttributesMode, "a", "b"),
+			pods: []corev1.Pod{zonePod(hotSset, 0, "a"), zonePod(hotSset, 1, ""), zonePod(hotSset, 2, "")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, zoneImbalances(tt.es, tt.pods))
+		})
+	}
+}
+
+func Test_zoneDataNodes_allowedDeletes(t *testing.T) {
+	hot := string(label.NodeTypesDataHotLabelName)
+	warm := string(label.NodeTypesDataWarmLabelName)
+	hotSset := esv1.StatefulSet("es", "hot")
+	warmSset := esv1.StatefulSet("es", "warm")
+	statefulSet := func(name string, replicas int32, roles ...string) appsv1.StatefulSet {
+		labels := map[string]string{}
+		for _, role := range roles {
+			labels[role] = "true"
+		}
+		return appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To(replicas),
+				Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+		}
+	}
+	// hot: a, b, c, a - warm: a, b
+	pods := []corev1.Pod{
+		zonePod(hotSset, 0, "a", hot), zonePod(hotSset, 1, "b", hot), zonePod(hotSset, 2, "c", hot), zonePod(hotSset, 3, "a", hot),
+		zonePod(warmSset, 0, "a", warm), zonePod(warmSset, 1, "b", warm),
+	}
+
+	tests := []struct {
+		name             string
+		es               esv1.Elasticsearch
+		expected         es_sset.StatefulSetList
+		statefulSet      appsv1.StatefulSet
+		requestedDeletes int32
+		want             int32
+	}{
+		{
+			name:             "forced mode disabled",
+			es:               zoneAwareES(esv1.ZoneAwarenessAttributesMode, "a", "b", "c"),
+			expected:         es_sset.StatefulSetList{statefulSet(hotSset, 1, hot)},
+			statefulSet:      statefulSet(hotSset, 4, hot),
+			requestedDeletes: 3,
+			want:             3,
+		},
+		{
+			name:             "remove the extra node of a zone, not the last node of the other zones",
+			es:               zoneAwareES(esv1.ZoneAwarenessForcedMode, "a", "b", "c"),
+			expected:         es_sset.StatefulSetList{statefulSet(hotSset, 1, hot)},
+			statefulSet:      statefulSet(hotSset, 4, hot),
+			requestedDeletes: 3,
+			want:             1,
+		},
+		{
+			name:             "remove all the nodes of a tier that is not expected anymore",
+			es:               zoneAwareES(esv1.ZoneAwarenessForcedMode, "a", "b", "c"),
+			expected:         es_sset.StatefulSetList{statefulSet(hotSset, 4, hot), statefulSet(warmSset, 0, warm)},
+			statefulSet:      statefulSet(warmSset, 2, warm),
+			requestedDeletes: 2,
+			want:             2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			z := newZoneDataNodes(tt.es, pods, tt.expected)
+			assert.Equal(t, tt.want, z.allowedDeletes(tt.statefulSet, tt.requestedDeletes))
+		})
+	}
+
+	t.Run("removals are accounted for in the next checks", func(t *testing.T) {
+		es := zoneAwareES(esv1.ZoneAwarenessForcedMode, "a", "b", "c")
+		state := newDownscaleState(pods, es, es_sset.StatefulSetList{statefulSet(hotSset, 1, hot)})
+		// do not restrict removals to the running nodes
+		state.removalsAllowed = nil
+		hotStatefulSet := statefulSet(hotSset, 4, hot)
+
+		allowed, reason := checkDownscaleInvariants(*state, hotStatefulSet, 1)
+		require.Equal(t, int32(1), allowed)
+		require.Empty(t, reason)
+		state.recordNodeRemoval(hotStatefulSet, allowed)
+
+		hotStatefulSet.Spec.Replicas = ptr.To[int32](3)
+		allowed, reason = checkDownscaleInvariants(*state, hotStatefulSet, 1)
+		require.Equal(t, int32(0), allowed)
+		require.Equal(t, ZoneDataTierInvariant, reason)
+	})
+}
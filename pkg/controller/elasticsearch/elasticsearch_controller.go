@@ -24,6 +24,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
@@ -34,6 +35,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
 	commonversion "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
@@ -59,7 +61,17 @@ const name = "elasticsearch-controller"
 // this is also called by cmd/main.go
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, name, reconciler, params)
+	if params.Sharding != nil {
+		// stop observing the clusters handed over to other operator replicas
+		params.Sharding.OnRelease(func(shard int) {
+			for _, es := range reconciler.esObservers.List() {
+				if params.Sharding.ShardOf(es) == shard {
+					reconciler.forget(es)
+				}
+			}
+		})
+	}
+	c, err := common.NewController(mgr, name, reconciler, params, &esv1.Elasticsearch{})
 	if err != nil {
 		return err
 	}
@@ -364,6 +376,16 @@ func (r *ReconcileElasticsearch) annotateResource(
 
 // onDelete garbage collect resources when an Elasticsearch cluster is deleted
 func (r *ReconcileElasticsearch) onDelete(ctx context.Context, es types.NamespacedName) error {
+	r.forget(es)
+	// stop collecting metrics in the shared collector of the namespace
+	if err := stackmon.DeleteSharedCollectorConfig(ctx, r.Client, es, commonv1.EsMonitoringAssociationType); err != nil {
+		return err
+	}
+	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, es, esv1.Kind)
+}
+
+// forget drops the in-memory state of an Elasticsearch cluster which is not reconciled by this operator replica anymore.
+func (r *ReconcileElasticsearch) forget(es types.NamespacedName) {
 	r.expectations.RemoveCluster(es)
 	r.esObservers.StopObserving(es)
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(keystore.SecureSettingsWatchName(es))
@@ -372,5 +394,4 @@ func (r *ReconcileElasticsearch) onDelete(ctx context.Context, es types.Namespac
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedRolesWatchName(es))
 	r.dynamicWatches.Secrets.RemoveHandlerForKey(user.UserProvidedFileRealmWatchName(es))
 	r.dynamicWatches.ConfigMaps.RemoveHandlerForKey(transport.AdditionalCAWatchKey(es))
-	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, es, esv1.Kind)
 }
@@ -26,6 +26,10 @@ type OrchestrationsHints struct {
 	// controllers should then rely on regular users until the value is true.
 	ServiceAccounts *optional.Bool    `json:"service_accounts,omitempty"`
 	DesiredNodes    *DesiredNodesHint `json:"desired_nodes,omitempty"`
+
+	// ForcedAwareness holds the zones last set by the operator in the forced awareness cluster setting. An empty list
+	// means that the setting is not managed by the operator.
+	ForcedAwareness *ForcedAwarenessHint `json:"forced_awareness,omitempty"`
 }
 
 // Merge merges the hints in other into the receiver.
@@ -34,6 +38,7 @@ func (oh OrchestrationsHints) Merge(other OrchestrationsHints) OrchestrationsHin
 		NoTransientSettings: oh.NoTransientSettings || other.NoTransientSettings,
 		ServiceAccounts:     oh.ServiceAccounts.Or(other.ServiceAccounts),
 		DesiredNodes:        oh.DesiredNodes.ReplaceWith(other.DesiredNodes),
+		ForcedAwareness:     oh.ForcedAwareness.ReplaceWith(other.ForcedAwareness),
 	}
 }
 
@@ -97,3 +102,22 @@ func (d *DesiredNodesHint) Equals(v int64, hash string) bool {
 	}
 	return d.Version == v && d.Hash == hash
 }
+
+type ForcedAwarenessHint struct {
+	Zones []string `json:"zones"`
+}
+
+func (f *ForcedAwarenessHint) ReplaceWith(other *ForcedAwarenessHint) *ForcedAwarenessHint {
+	if other == nil {
+		return f
+	}
+	return other
+}
+
+// ManagedZones returns the zones last set by the operator, or nil if the setting is not managed.
+func (f *ForcedAwarenessHint) ManagedZones() []string {
+	if f == nil {
+		return nil
+	}
+	return f.Zones
+}
@@ -0,0 +1,50 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package nodespec
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/settings"
+)
+
+// withJVMHeap appends the JVM heap size derived from the JVMHeap policy of the NodeSet to the environment variable
+// `ES_JAVA_OPTS` of the Elasticsearch container. The heap size is computed from the effective memory limit of the
+// container, once the default resources have been applied. Nothing is done if the NodeSet does not declare a policy
+// or if the container has no memory limit.
+func withJVMHeap(builder *defaults.PodTemplateBuilder, nodeSet esv1.NodeSet) {
+	if nodeSet.JVMHeap == nil {
+		return
+	}
+	for c, esContainer := range builder.PodTemplate.Spec.Containers {
+		if esContainer.Name != esv1.ElasticsearchContainerName {
+			continue
+		}
+		memoryLimit := esContainer.Resources.Limits.Memory()
+		if memoryLimit == nil || memoryLimit.IsZero() {
+			return
+		}
+		heapSize := nodeSet.JVMHeap.HeapSize(*memoryLimit)
+		heapOpts := fmt.Sprintf("-Xms%[1]dm -Xmx%[1]dm", heapSize.Value()/(1024*1024))
+
+		for e, envVar := range esContainer.Env {
+			if envVar.Name != settings.EnvEsJavaOpts {
+				continue
+			}
+			builder.PodTemplate.Spec.Containers[c].Env[e].Value = strings.TrimSpace(envVar.Value + " " + heapOpts)
+			return
+		}
+		builder.PodTemplate.Spec.Containers[c].Env = append(
+			builder.PodTemplate.Spec.Containers[c].Env,
+			corev1.EnvVar{Name: settings.EnvEsJavaOpts, Value: heapOpts},
+		)
+		return
+	}
+}
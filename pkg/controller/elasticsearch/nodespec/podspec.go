@@ -132,7 +132,8 @@ func BuildPodTemplateSpec(
 	builder = builder.
 		WithLabels(meta.Labels).
 		WithAnnotations(meta.Annotations).
-		WithDockerImage(es.Spec.Image, container.ImageRepository(container.ElasticsearchImage, ver)).
+		WithDockerImage(es.Spec.Image, container.ImageRepository(es.Namespace, container.ElasticsearchImage, ver)).
+		WithImagePullSecrets(container.ImagePullSecrets(es.Namespace)...).
 		WithResources(DefaultResources).
 		WithTerminationGracePeriod(DefaultTerminationGracePeriodSeconds).
 		WithPorts(defaultContainerPorts).
@@ -169,6 +170,8 @@ func BuildPodTemplateSpec(
 		enableLog4JFormatMsgNoLookups(builder)
 	}
 
+	withJVMHeap(builder, nodeSet)
+
 	return builder.PodTemplate, nil
 }
 
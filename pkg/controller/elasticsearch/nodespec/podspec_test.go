@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -232,7 +233,7 @@ func TestBuildPodTemplateSpecWithDefaultSecurityContext(t *testing.T) {
 			es.Spec.Version = tt.version.String()
 			es.Spec.NodeSets[0].PodTemplate.Spec.SecurityContext = tt.userSecurityContext
 
-			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil, false, false, false, false)
+			cfg, err := settings.NewMergedESConfig(es.Name, tt.version, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil, nil, false, false, false, false)
 			require.NoError(t, err)
 
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: es.Namespace, Name: esv1.ScriptsConfigMap(es.Name)}})
@@ -315,7 +316,7 @@ func TestBuildPodTemplateSpec(t *testing.T) {
 			ver, err := version.Parse(es.Spec.Version)
 			require.NoError(t, err)
 
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *nodeSet.Config, tt.args.policyConfig.ElasticsearchConfig, false, false, nodeSet.ZoneAwareness != nil, false)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *nodeSet.Config, nil, tt.args.policyConfig.ElasticsearchConfig, false, false, nodeSet.ZoneAwareness != nil, false)
 			require.NoError(t, err)
 
 			actual, err := BuildPodTemplateSpec(context.Background(), tt.args.client, es, es.Spec.NodeSets[0], cfg, tt.args.keystoreResources, tt.args.setDefaultSecurityContext, tt.args.policyConfig, metadata.Metadata{}, "", false)
@@ -527,7 +528,7 @@ func Test_buildAnnotations(t *testing.T) {
 				build()
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil, false, false, es.Spec.NodeSets[0].ZoneAwareness != nil, false)
+			cfg, err := settings.NewMergedESConfig(es.Name, ver, corev1.IPv4Protocol, es.Spec.HTTP, *es.Spec.NodeSets[0].Config, nil, nil, false, false, es.Spec.NodeSets[0].ZoneAwareness != nil, false)
 			require.NoError(t, err)
 			got := buildAnnotations(es, cfg, tt.args.keystoreResources, tt.args.scriptsContent, tt.args.policyAnnotations, tt.args.podsRestartTriggerAnnotation)
 
@@ -771,6 +772,7 @@ func TestBuildPodTemplateSpec_ZoneAwarenessScenarios(t *testing.T) {
 				es.Spec.HTTP,
 				*nodeSet.Config,
 				nil,
+				nil,
 				false,
 				false,
 				esv1.NodeSetList(es.Spec.NodeSets).HasZoneAwareness(),
@@ -1103,7 +1105,7 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 
 			ver, err := version.Parse(sampleES.Spec.Version)
 			require.NoError(t, err)
-			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config, nil, false, false, sampleES.Spec.NodeSets[0].ZoneAwareness != nil, false)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config, nil, nil, false, false, sampleES.Spec.NodeSets[0].ZoneAwareness != nil, false)
 			require.NoError(t, err)
 			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
 			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{}, metadata.Metadata{}, "", false)
@@ -1120,6 +1122,87 @@ func Test_enableLog4JFormatMsgNoLookups(t *testing.T) {
 	}
 }
 
+func TestBuildPodTemplateSpec_JVMHeap(t *testing.T) {
+	tt := []struct {
+		name                       string
+		jvmHeap                    *esv1.JVMHeap
+		resources                  corev1.ResourceRequirements
+		userEnv                    []corev1.EnvVar
+		expectedEsJavaOptsEnvValue string
+	}{
+		{
+			name:                       "no JVM heap policy: ES_JAVA_OPTS is not set",
+			expectedEsJavaOptsEnvValue: "",
+		},
+		{
+			name:                       "default policy with default resources: half of the 2Gi default memory limit",
+			jvmHeap:                    &esv1.JVMHeap{},
+			expectedEsJavaOptsEnvValue: "-Xms1024m -Xmx1024m",
+		},
+		{
+			name:    "custom percentage of the user-provided memory limit",
+			jvmHeap: &esv1.JVMHeap{MemoryLimitPercent: ptr.To[int32](75)},
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			expectedEsJavaOptsEnvValue: "-Xms3072m -Xmx3072m",
+		},
+		{
+			name:    "heap size is capped by the maximum",
+			jvmHeap: &esv1.JVMHeap{},
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("128Gi")},
+			},
+			expectedEsJavaOptsEnvValue: "-Xms31744m -Xmx31744m",
+		},
+		{
+			name:    "heap size is never below the minimum",
+			jvmHeap: &esv1.JVMHeap{},
+			resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("100Mi")},
+			},
+			expectedEsJavaOptsEnvValue: "-Xms128m -Xmx128m",
+		},
+		{
+			name:                       "heap size is appended to user-provided JVM parameters",
+			jvmHeap:                    &esv1.JVMHeap{},
+			userEnv:                    []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-XX:+UseG1GC"}},
+			expectedEsJavaOptsEnvValue: "-XX:+UseG1GC -Xms1024m -Xmx1024m",
+		},
+		{
+			name:    "no memory limit: ES_JAVA_OPTS is not set",
+			jvmHeap: &esv1.JVMHeap{},
+			resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+			expectedEsJavaOptsEnvValue: "",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			sampleES := newEsSampleBuilder().build()
+			sampleES.Spec.NodeSets[0].JVMHeap = tc.jvmHeap
+			sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1].Resources = tc.resources
+			sampleES.Spec.NodeSets[0].PodTemplate.Spec.Containers[1].Env = tc.userEnv
+
+			ver, err := version.Parse(sampleES.Spec.Version)
+			require.NoError(t, err)
+			cfg, err := settings.NewMergedESConfig(sampleES.Name, ver, corev1.IPv4Protocol, sampleES.Spec.HTTP, *sampleES.Spec.NodeSets[0].Config, nil, nil, false, false, false, false)
+			require.NoError(t, err)
+			client := k8s.NewFakeClient(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: sampleES.Namespace, Name: esv1.ScriptsConfigMap(sampleES.Name)}})
+			actual, err := BuildPodTemplateSpec(context.Background(), client, sampleES, sampleES.Spec.NodeSets[0], cfg, nil, false, PolicyConfig{}, metadata.Metadata{}, "", false)
+			require.NoError(t, err)
+
+			envMap := make(map[string]string)
+			for _, e := range actual.Spec.Containers[1].Env {
+				envMap[e.Name] = e.Value
+			}
+			assert.Equal(t, tc.expectedEsJavaOptsEnvValue, envMap[settings.EnvEsJavaOpts])
+		})
+	}
+}
+
 func Test_getScriptsConfigMapContent(t *testing.T) {
 	cm := &corev1.ConfigMap{
 		Data: map[string]string{
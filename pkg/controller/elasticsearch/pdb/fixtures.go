@@ -179,6 +179,7 @@ func (b Builder) BuildResourcesList() (nodespec.ResourcesList, error) {
 			b.Elasticsearch.Spec.HTTP,
 			*config,
 			nil,
+			nil,
 			false,
 			false,
 			false,
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"fmt"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	common "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/settings"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/volume"
+)
+
+// DataTierConfig returns the ES configuration derived from the data tier declared on the given NodeSet:
+//
+// - node.roles is set to the roles of the tier, unless the user already configured the node roles.
+// - on the frozen tier, xpack.searchable.snapshot.shared_cache.size is sized from the elasticsearch-data volume claim,
+// unless the user already configured it.
+//
+// User provided settings are merged afterwards and keep precedence over these defaults. Node roles are however
+// skipped entirely if set by the user, as lists are appended to each other when configurations are merged.
+func DataTierConfig(nodeSet esv1.NodeSet, ver version.Version) (*common.CanonicalConfig, error) {
+	if nodeSet.Tier == "" {
+		return common.NewCanonicalConfig(), nil
+	}
+
+	userSettings := esv1.ElasticsearchSettings{}
+	if err := esv1.UnpackConfig(nodeSet.Config, ver, &userSettings); err != nil {
+		return nil, err
+	}
+
+	cfg := map[string]any{}
+	if !hasUserDefinedRoles(userSettings.Node) {
+		cfg[esv1.NodeRoles] = nodeSet.Tier.NodeRoles()
+	}
+
+	if nodeSet.Tier == esv1.FrozenTier {
+		if cacheSize, ok := sharedCacheSize(nodeSet); ok && !hasUserDefinedSetting(nodeSet, esv1.SearchableSnapshotSharedCacheSize) {
+			cfg[esv1.SearchableSnapshotSharedCacheSize] = cacheSize
+		}
+	}
+
+	return common.NewCanonicalConfigFrom(cfg)
+}
+
+// hasUserDefinedRoles returns true if node.roles or any of the legacy node role attributes is set.
+func hasUserDefinedRoles(node *esv1.Node) bool {
+	if node == nil {
+		return false
+	}
+	return node.Roles != nil || node.Master != nil || node.Data != nil || node.Ingest != nil || node.ML != nil ||
+		node.Transform != nil || node.RemoteClusterClient != nil || node.VotingOnly != nil
+}
+
+// hasUserDefinedSetting returns true if the given setting is part of the NodeSet configuration.
+func hasUserDefinedSetting(nodeSet esv1.NodeSet, setting string) bool {
+	if nodeSet.Config == nil {
+		return false
+	}
+	cfg, err := common.NewCanonicalConfigFrom(nodeSet.Config.Data)
+	if err != nil {
+		return false
+	}
+	return len(cfg.HasKeys([]string{setting})) > 0
+}
+
+// sharedCacheSize returns the searchable snapshot shared cache size for the given NodeSet, computed as a ratio of the
+// storage requested by the elasticsearch-data volume claim. It returns false if no storage request can be found.
+func sharedCacheSize(nodeSet esv1.NodeSet) (string, bool) {
+	claims := nodeSet.VolumeClaimTemplates
+	if len(claims) == 0 {
+		claims = volume.DefaultVolumeClaimTemplates
+	}
+	for _, claim := range claims {
+		if claim.Name != volume.ElasticsearchDataVolumeName {
+			continue
+		}
+		storage := claim.Spec.Resources.Requests.Storage()
+		if storage == nil || storage.IsZero() {
+			return "", false
+		}
+		return fmt.Sprintf("%db", int64(float64(storage.Value())*esv1.SharedCacheToDataVolumeRatio)), true
+	}
+	return "", false
+}
@@ -0,0 +1,121 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/volume"
+)
+
+func dataClaim(size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: volume.ElasticsearchDataVolumeName},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+	}
+}
+
+func TestDataTierConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		nodeSet esv1.NodeSet
+		want    map[string]any
+	}{
+		{
+			name:    "no tier",
+			nodeSet: esv1.NodeSet{Name: "default"},
+			want:    map[string]any{},
+		},
+		{
+			name:    "hot tier",
+			nodeSet: esv1.NodeSet{Name: "hot", Tier: esv1.HotTier},
+			want:    map[string]any{"node": map[string]any{"roles": []any{"data_hot", "data_content"}}},
+		},
+		{
+			name: "warm tier with user defined roles",
+			nodeSet: esv1.NodeSet{
+				Name:   "warm",
+				Tier:   esv1.WarmTier,
+				Config: &commonv1.Config{Data: map[string]any{"node.roles": []string{"data_warm", "ingest"}}},
+			},
+			want: map[string]any{},
+		},
+		{
+			name: "frozen tier with the default volume claim",
+			nodeSet: esv1.NodeSet{
+				Name: "frozen",
+				Tier: esv1.FrozenTier,
+			},
+			want: map[string]any{
+				"node": map[string]any{"roles": []any{"data_frozen"}},
+				"xpack": map[string]any{"searchable": map[string]any{"snapshot": map[string]any{"shared_cache": map[string]any{
+					"size": "966367641b",
+				}}}},
+			},
+		},
+		{
+			name: "frozen tier with a custom volume claim",
+			nodeSet: esv1.NodeSet{
+				Name:                 "frozen",
+				Tier:                 esv1.FrozenTier,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{dataClaim("100Gi")},
+			},
+			want: map[string]any{
+				"node": map[string]any{"roles": []any{"data_frozen"}},
+				"xpack": map[string]any{"searchable": map[string]any{"snapshot": map[string]any{"shared_cache": map[string]any{
+					"size": "96636764160b",
+				}}}},
+			},
+		},
+		{
+			name: "frozen tier with a user defined cache size",
+			nodeSet: esv1.NodeSet{
+				Name: "frozen",
+				Tier: esv1.FrozenTier,
+				Config: &commonv1.Config{Data: map[string]any{
+					esv1.SearchableSnapshotSharedCacheSize: "50%",
+				}},
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{dataClaim("100Gi")},
+			},
+			want: map[string]any{"node": map[string]any{"roles": []any{"data_frozen"}}},
+		},
+		{
+			name: "frozen tier without data volume claim",
+			nodeSet: esv1.NodeSet{
+				Name: "frozen",
+				Tier: esv1.FrozenTier,
+				VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+					{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+				},
+			},
+			want: map[string]any{"node": map[string]any{"roles": []any{"data_frozen"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DataTierConfig(tt.nodeSet, version.MustParse("8.15.0"))
+			require.NoError(t, err)
+			var gotMap map[string]any
+			require.NoError(t, got.Unpack(&gotMap))
+			if len(tt.want) == 0 {
+				require.Empty(t, gotMap)
+				return
+			}
+			require.Equal(t, tt.want, gotMap)
+		})
+	}
+}
@@ -35,13 +35,14 @@ var (
 )
 
 // NewMergedESConfig merges user provided Elasticsearch configuration with configuration derived from the given
-// parameters. The user provided config overrides have precedence over the ECK config.
+// parameters. The user provided config overrides have precedence over the ECK config, including the data tier defaults.
 func NewMergedESConfig(
 	clusterName string,
 	ver version.Version,
 	ipFamily corev1.IPFamily,
 	httpConfig commonv1.HTTPConfigWithClientOptions,
 	userConfig commonv1.Config,
+	dataTierConfig *common.CanonicalConfig,
 	esConfigFromStackConfigPolicy *common.CanonicalConfig,
 	remoteClusterServerEnabled, remoteClusterClientEnabled bool,
 	clusterHasZoneAwareness, clientAuthenticationRequired bool,
@@ -55,6 +56,7 @@ func NewMergedESConfig(
 	err = config.MergeWith(
 		zoneAwarenessConfig(clusterHasZoneAwareness).CanonicalConfig,
 		xpackConfig(ver, httpConfig, remoteClusterServerEnabled, remoteClusterClientEnabled).CanonicalConfig,
+		dataTierConfig,
 		userCfg,
 		esConfigFromStackConfigPolicy,
 	)
@@ -417,7 +417,7 @@ func TestNewMergedESConfig(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			ver, err := version.Parse(tt.version)
 			require.NoError(t, err)
-			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, tt.httpConfig, commonv1.Config{Data: tt.cfgData}, tt.policyCfgData, tt.remoteClusterServerEnabled, tt.remoteClusterClientEnabled, tt.clusterHasZoneAwareness, tt.clientAuthenticationRequired)
+			cfg, err := NewMergedESConfig("clusterName", ver, tt.ipFamily, tt.httpConfig, commonv1.Config{Data: tt.cfgData}, nil, tt.policyCfgData, tt.remoteClusterServerEnabled, tt.remoteClusterClientEnabled, tt.clusterHasZoneAwareness, tt.clientAuthenticationRequired)
 			require.NoError(t, err)
 			tt.assert(cfg)
 		})
@@ -8,11 +8,13 @@ import (
 	"context"
 	_ "embed" // for the beats config files
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 )
 
@@ -26,37 +28,21 @@ var (
 	filebeatConfig string
 )
 
-// ReconcileConfigSecrets reconciles the secrets holding beats configuration
+// ReconcileConfigSecrets reconciles the secrets holding beats or EDOT collector configuration, and the shared
+// collector of the namespace in the Shared monitoring mode.
 func ReconcileConfigSecrets(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, meta metadata.Metadata) error {
-	isMonitoringReconcilable, err := monitoring.IsReconcilable(&es)
-	if err != nil {
-		return err
-	}
-	if !isMonitoringReconcilable {
-		return nil
-	}
-
-	if monitoring.IsMetricsDefined(&es) {
-		b, err := Metricbeat(ctx, client, es, meta)
-		if err != nil {
-			return err
-		}
-
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &es); err != nil {
-			return err
-		}
-	}
-
-	if monitoring.IsLogsDefined(&es) {
-		b, err := Filebeat(ctx, client, es, meta)
-		if err != nil {
-			return err
-		}
-
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &es); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return stackmon.ReconcileConfigSecrets(ctx, client, &es, commonv1.EsMonitoringAssociationType, stackmon.ConfigBuilders{
+		Metricbeat: func() (stackmon.BeatSidecar, error) {
+			return Metricbeat(ctx, client, es, meta)
+		},
+		Filebeat: func() (stackmon.BeatSidecar, error) {
+			return Filebeat(ctx, client, es, meta)
+		},
+		Collector: func() (stackmon.BeatSidecar, error) {
+			return Collector(ctx, client, es, meta)
+		},
+		SharedCollector: func() (corev1.Secret, error) {
+			return SharedCollectorConfig(ctx, client, es, meta)
+		},
+	})
 }
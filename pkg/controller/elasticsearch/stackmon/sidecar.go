@@ -7,6 +7,7 @@ package stackmon
 import (
 	"context"
 	"fmt"
+	"hash"
 	"hash/fnv"
 
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
@@ -23,6 +24,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/network"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/securitycontext"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/services"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/user"
 	esvolume "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/volume"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
@@ -33,45 +35,71 @@ const (
 	cfgHashAnnotation = "elasticsearch.k8s.elastic.co/monitoring-config-hash"
 )
 
-func Metricbeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+// metricbeatConfigParams are the parameters to render the Metricbeat configuration template.
+type metricbeatConfigParams struct {
+	stackmon.TemplateParams
+	// ClusterScope collects the metrics of the whole cluster from any node, rather than the metrics of the local node.
+	ClusterScope bool
+}
+
+// metricbeatConfig renders the Metricbeat configuration to collect monitoring data from Elasticsearch at the given URL,
+// and returns it along with the volume holding the CA certificate of Elasticsearch, if any.
+func metricbeatConfig(client k8s.Client, es esv1.Elasticsearch, url string, clusterScope bool) (string, volume.VolumeLike, error) {
 	username := user.MonitoringUserName
 	password, err := user.GetMonitoringUserPassword(client, k8s.ExtractNamespacedName(&es))
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
 
 	v, err := version.Parse(es.Spec.Version)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
 
 	caVolume, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&es), esv1.ESNamer, commonv1.EsMonitoringAssociationType, es.Spec.HTTP.TLS.Enabled())
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
 
-	input := stackmon.TemplateParams{
-		URL:      fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort),
-		Username: username,
-		Password: password,
-		IsSSL:    es.Spec.HTTP.TLS.Enabled(),
-		CAVolume: caVolume,
+	input := metricbeatConfigParams{
+		TemplateParams: stackmon.TemplateParams{
+			URL:      url,
+			Username: username,
+			Password: password,
+			IsSSL:    es.Spec.HTTP.TLS.Enabled(),
+			CAVolume: caVolume,
+		},
+		ClusterScope: clusterScope,
 	}
 
 	cfg, err := stackmon.RenderTemplate(v, metricbeatConfigTemplate, input)
+	if err != nil {
+		return "", nil, err
+	}
+	return cfg, caVolume, nil
+}
+
+// localURL is the URL used by the sidecars to reach Elasticsearch in the same Pod.
+func localURL(es esv1.Elasticsearch) string {
+	return fmt.Sprintf("%s://localhost:%d", es.Spec.HTTP.Protocol(), network.HTTPPort)
+}
+
+func Metricbeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	cfg, caVolume, err := metricbeatConfig(client, es, localURL(es), false)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
 
-	metricbeat, err := stackmon.NewMetricBeatSidecar(ctx, client, &es, v, caVolume, cfg, meta)
+	v, err := version.Parse(es.Spec.Version)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
-	ver, err := version.Parse(es.Spec.Version)
+
+	metricbeat, err := stackmon.NewMetricBeatSidecar(ctx, client, &es, v, caVolume, cfg, meta)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
-	metricbeat.Container.SecurityContext = securitycontext.DefaultBeatSecurityContext(ver)
+	metricbeat.Container.SecurityContext = securitycontext.DefaultBeatSecurityContext(v)
 	return metricbeat, nil
 }
 
@@ -88,8 +116,50 @@ func Filebeat(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, met
 	return fileBeat, nil
 }
 
-// WithMonitoring updates the Elasticsearch Pod template builder to deploy Metricbeat and Filebeat in sidecar containers
-// in the Elasticsearch pod and injects the volumes for the beat configurations and the ES CA certificates.
+// Collector returns an EDOT collector sidecar collecting both the metrics and the logs of the Elasticsearch Pod.
+func Collector(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+
+	var metricsCfg, logsCfg string
+	var caVolume volume.VolumeLike
+	if monitoring.IsMetricsDefined(&es) {
+		metricsCfg, caVolume, err = metricbeatConfig(client, es, localURL(es), false)
+		if err != nil {
+			return stackmon.BeatSidecar{}, err
+		}
+	}
+	if monitoring.IsLogsDefined(&es) {
+		logsCfg = filebeatConfig
+	}
+
+	collector, err := stackmon.NewCollectorSidecar(ctx, client, &es, v, metricsCfg, logsCfg, meta, caVolume)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+	collector.Container.SecurityContext = securitycontext.DefaultBeatSecurityContext(v)
+	return collector, nil
+}
+
+// SharedCollectorConfig returns the configuration of the shared collector of the namespace to collect the metrics of
+// the whole cluster through its internal Service.
+func SharedCollectorConfig(ctx context.Context, client k8s.Client, es esv1.Elasticsearch, meta metadata.Metadata) (corev1.Secret, error) {
+	cfg, caVolume, err := metricbeatConfig(client, es, services.InternalServiceURL(es), true)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	return stackmon.NewSharedCollectorConfig(ctx, client, &es, v, cfg, caVolume, meta)
+}
+
+// WithMonitoring updates the Elasticsearch Pod template builder to deploy Metricbeat and Filebeat in sidecar containers,
+// or a single EDOT collector sidecar, in the Elasticsearch pod and injects the volumes for their configurations and the
+// ES CA certificates. Nothing is deployed in the Pod in the Shared monitoring mode.
 func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, es esv1.Elasticsearch, meta metadata.Metadata) (*defaults.PodTemplateBuilder, error) {
 	isMonitoringReconcilable, err := monitoring.IsReconcilable(&es)
 	if err != nil {
@@ -100,8 +170,45 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 	}
 
 	configHash := fnv.New32a()
-	volumes := make([]corev1.Volume, 0)
+	var volumes []corev1.Volume
+
+	switch es.Spec.Monitoring.GetMode() {
+	case commonv1.SharedMonitoringMode:
+		// metrics are collected by the shared collector of the namespace
+		return builder, nil
+	case commonv1.CollectorMonitoringMode:
+		b, err := Collector(ctx, client, es, meta)
+		if err != nil {
+			return nil, err
+		}
+		if monitoring.IsLogsDefined(&es) {
+			// enable Stack logging to write Elasticsearch logs to disk
+			builder.WithEnv(fileLogStyleEnvVar())
+			// share the ES logs volume into the collector container
+			b.Container.VolumeMounts = append(b.Container.VolumeMounts, esvolume.DefaultLogsVolumeMount)
+		}
+		volumes = append(volumes, b.Volumes...)
+		builder.WithContainers(b.Container)
+		configHash.Write(b.ConfigHash.Sum(nil))
+	default:
+		volumes, err = withBeatSidecars(ctx, client, builder, es, meta, configHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// add the config hash annotation to ensure pod rotation when an ES password or a CA are rotated
+	builder.WithAnnotations(map[string]string{cfgHashAnnotation: fmt.Sprint(configHash.Sum32())})
+	// inject all volumes
+	builder.WithVolumes(volumes...)
+
+	return builder, nil
+}
 
+// withBeatSidecars adds the Metricbeat and Filebeat sidecar containers to the Pod template builder, and returns the
+// volumes to inject in the Pod.
+func withBeatSidecars(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, es esv1.Elasticsearch, meta metadata.Metadata, configHash hash.Hash) ([]corev1.Volume, error) {
+	volumes := make([]corev1.Volume, 0)
 	if monitoring.IsMetricsDefined(&es) {
 		b, err := Metricbeat(ctx, client, es, meta)
 		if err != nil {
@@ -141,11 +248,5 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 		builder.WithContainers(filebeat)
 		configHash.Write(b.ConfigHash.Sum(nil))
 	}
-
-	// add the config hash annotation to ensure pod rotation when an ES password or a CA are rotated
-	builder.WithAnnotations(map[string]string{cfgHashAnnotation: fmt.Sprint(configHash.Sum32())})
-	// inject all volumes
-	builder.WithVolumes(volumes...)
-
-	return builder, nil
+	return volumes, nil
 }
@@ -145,12 +145,13 @@ func TestMetricbeatConfig(t *testing.T) {
 		"/mount",
 	)
 	type args struct {
-		URL      string
-		Username string
-		Password string
-		IsSSL    bool
-		CAVolume volume.VolumeLike
-		Version  semver.Version
+		URL          string
+		Username     string
+		Password     string
+		IsSSL        bool
+		CAVolume     volume.VolumeLike
+		Version      semver.Version
+		ClusterScope bool
 	}
 	tests := []struct {
 		name string
@@ -209,6 +210,18 @@ func TestMetricbeatConfig(t *testing.T) {
 				CAVolume: volumeFixture,
 			},
 		},
+		{
+			name: "shared collector: cluster scope",
+			args: args{
+				URL:          "https://es-es-internal-http.default.svc:9200",
+				Username:     "elastic",
+				Password:     "secret",
+				IsSSL:        true,
+				Version:      version.From(9, 1, 0),
+				CAVolume:     volumeFixture,
+				ClusterScope: true,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
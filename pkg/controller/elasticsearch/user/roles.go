@@ -56,6 +56,9 @@ const (
 
 	LogstashUserRole = "eck_logstash_user_role"
 
+	// OTelCollectorUserRole is the name of the role used by the Elasticsearch exporter of OpenTelemetry Collectors
+	OTelCollectorUserRole = "eck_otel_collector_user_role"
+
 	// V70 indicates version 7.0
 	V70 = "v70"
 
@@ -253,6 +256,17 @@ var (
 				},
 			},
 		},
+		// OTelCollectorUserRole allows the Elasticsearch exporter to write to the data streams of the OpenTelemetry
+		// signals, relying on the built-in index templates to create them.
+		OTelCollectorUserRole: esclient.Role{
+			Cluster: []string{"monitor"},
+			Indices: []esclient.IndexRole{
+				{
+					Names:      []string{"logs-*", "metrics-*", "traces-*"},
+					Privileges: []string{"auto_configure", "create_doc"},
+				},
+			},
+		},
 	}
 
 	// Additional index permissions for Beats users
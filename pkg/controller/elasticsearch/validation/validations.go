@@ -16,9 +16,12 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 	stackmon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/bootstrap"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
 	esversion "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
@@ -32,6 +35,7 @@ const (
 	invalidNamesErrMsg                       = "Elasticsearch configuration would generate resources with invalid names"
 	invalidSanIPErrMsg                       = "Invalid SAN IP address. Must be a valid IPv4 address"
 	conflictingZoneAwarenessTopologyKeys     = "All zone-aware NodeSets must use the same topologyKey"
+	forcedZoneAwarenessZonesRequiredMsg      = "Zones must be set when the zone awareness mode is Forced"
 	zoneAwarenessAffinityInNoIntersectionMsg = "Required node affinity In values have no intersection with the configured zone-awareness zones; the operator injects an additional In expression for the zones, so pods will be permanently unschedulable"
 	masterRequiredMsg                        = "Elasticsearch needs to have at least one master node"
 	mixedRoleConfigMsg                       = "Detected a combination of node.roles and %s. Use only node.roles"
@@ -47,6 +51,16 @@ const (
 	autoscalingAnnotationUnsupportedErrMsg   = "autoscaling annotation is no longer supported"
 	restartTriggerRemovedWarningMsg          = "Removing the restart-trigger annotation does not cancel an in-progress rolling restart; pods not yet restarted will still be restarted with the previous trigger value."
 	restartTriggerUnchangedWarningMsg        = "Restart-trigger value unchanged; no new rolling restart will be triggered if pods already have this value."
+	dataTierUnsupportedVersionMsg            = "Data tier is not supported in this version of Elasticsearch, minimum required version is %s"
+	dataTierRoleMissingMsg                   = "node.roles must include %s when the NodeSet declares the %s tier"
+	dataTierLegacyRolesMsg                   = "Data tiers can only be combined with node.roles. Remove %s"
+	searchableSnapshotRepositoryTierMsg      = "A searchable snapshot repository can only be declared on the cold or frozen tier"
+	jvmHeapMemoryLimitMissingMsg             = "A memory limit must be set on the Elasticsearch container when jvmHeap is set"
+	jvmHeapConflictingJavaOptsMsg            = "-Xms and -Xmx must not be set in ES_JAVA_OPTS when jvmHeap is set"
+	jvmHeapMaxTooLowMsg                      = "The maximum JVM heap size must be at least %s"
+	jvmHeapMemoryLimitTooLowMsg              = "The memory limit of the Elasticsearch container leaves a JVM heap below %s, increase it or jvmHeap.memoryLimitPercent"
+	adoptVolumesFromClusterRequiredMsg       = "The name of the deleted cluster whose volumes to adopt must be set"
+	adoptVolumesClusterUUIDRequiredMsg       = "The UUID of the deleted cluster whose volumes to adopt must be set"
 )
 
 type validation func(esv1.Elasticsearch) field.ErrorList
@@ -72,10 +86,15 @@ func validations(ctx context.Context, checker license.Checker, exposedNodeLabels
 		},
 		validZoneAwarenessTopologyKeys,
 		validZoneAwarenessAffinityInCompatibility,
+		validForcedZoneAwareness,
 		noUnknownFields,
 		validName,
 		hasCorrectNodeRoles,
+		validDataTiers,
+		validJVMHeap,
+		validAdoptVolumes,
 		supportedVersion,
+		catalogVersion,
 		validSanIP,
 		validAutoscalingConfiguration,
 		validPVCNaming,
@@ -176,6 +195,22 @@ func validZoneAwarenessTopologyKeys(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validForcedZoneAwareness checks that the zones are declared on the NodeSets using the forced zone awareness mode, as
+// they are used as the forced awareness zone values.
+func validForcedZoneAwareness(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, nodeSet := range es.Spec.NodeSets {
+		if nodeSet.ZoneAwareness == nil || !nodeSet.ZoneAwareness.IsForced() || len(nodeSet.ZoneAwareness.Zones) > 0 {
+			continue
+		}
+		errs = append(
+			errs,
+			field.Required(field.NewPath("spec").Child("nodeSets").Index(i).Child("zoneAwareness", "zones"), forcedZoneAwarenessZonesRequiredMsg),
+		)
+	}
+	return errs
+}
+
 // nodeSelectorExpressionRef captures a required node affinity match expression that
 // targets a zone-awareness topology key. It is used by both hard validations (e.g.
 // conflicting In values) and warnings (e.g. DoesNotExist, NotIn) to reference the
@@ -306,6 +341,10 @@ func supportedVersion(es esv1.Elasticsearch) field.ErrorList {
 	return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, unsupportedVersionMsg)}
 }
 
+func catalogVersion(es esv1.Elasticsearch) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(es.Spec.Version, container.ElasticsearchImage, es.Spec.Image)
+}
+
 func supportsRemoteClusterUsingAPIKey(es esv1.Elasticsearch) field.ErrorList {
 	ver, err := version.Parse(es.Spec.Version)
 	if err != nil {
@@ -376,6 +415,14 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 			errs = append(errs, field.Forbidden(confField(i), fmt.Sprintf(mixedRoleConfigMsg, strings.Join(nodeRoleAttrs, ","))))
 		}
 
+		// Nodes of a data tier get the roles of the tier unless roles are explicitly configured.
+		if ns.Tier != "" && len(nodeRoleAttrs) == 0 && (cfg.Node == nil || cfg.Node.Roles == nil) {
+			if cfg.Node == nil {
+				cfg.Node = &esv1.Node{}
+			}
+			cfg.Node.Roles = ns.Tier.NodeRoles()
+		}
+
 		// Check if this nodeSet has the master role.
 		seenMaster = seenMaster || (cfg.Node.IsConfiguredWithRole(esv1.MasterRole) && !cfg.Node.IsConfiguredWithRole(esv1.VotingOnlyRole) && ns.Count > 0)
 	}
@@ -387,6 +434,103 @@ func hasCorrectNodeRoles(es esv1.Elasticsearch) field.ErrorList {
 	return errs
 }
 
+// validDataTiers checks that the data tiers declared on the NodeSets are consistent with the Elasticsearch version
+// and with the node roles configured by the user.
+func validDataTiers(es esv1.Elasticsearch) field.ErrorList {
+	v, err := version.Parse(es.Spec.Version)
+	if err != nil {
+		return field.ErrorList{field.Invalid(field.NewPath("spec").Child("version"), es.Spec.Version, parseVersionErrMsg)}
+	}
+
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		nodeSetPath := field.NewPath("spec").Child("nodeSets").Index(i)
+
+		if ns.SearchableSnapshotRepository != "" && !ns.Tier.SupportsSearchableSnapshots() {
+			errs = append(errs, field.Invalid(nodeSetPath.Child("searchableSnapshotRepository"), ns.SearchableSnapshotRepository, searchableSnapshotRepositoryTierMsg))
+		}
+
+		if ns.Tier == "" {
+			continue
+		}
+
+		if minVersion := ns.Tier.MinVersion(); !v.GTE(minVersion) {
+			errs = append(errs, field.Invalid(nodeSetPath.Child("tier"), ns.Tier, fmt.Sprintf(dataTierUnsupportedVersionMsg, version.WithoutPre(minVersion))))
+			continue
+		}
+
+		cfg := esv1.ElasticsearchSettings{}
+		if err := esv1.UnpackConfig(ns.Config, v, &cfg); err != nil {
+			// reported by hasCorrectNodeRoles
+			continue
+		}
+
+		if nodeRoleAttrs := getNodeRoleAttrs(cfg); len(nodeRoleAttrs) > 0 {
+			errs = append(errs, field.Forbidden(nodeSetPath.Child("config"), fmt.Sprintf(dataTierLegacyRolesMsg, strings.Join(nodeRoleAttrs, ","))))
+			continue
+		}
+
+		if cfg.Node != nil && cfg.Node.Roles != nil && !cfg.Node.HasRole(ns.Tier.Role()) {
+			errs = append(errs, field.Invalid(nodeSetPath.Child("config"), ns.Config, fmt.Sprintf(dataTierRoleMissingMsg, ns.Tier.Role(), ns.Tier)))
+		}
+	}
+	return errs
+}
+
+// validJVMHeap checks that the JVM heap policy declared on the NodeSets can be applied: the Elasticsearch container
+// must have a memory limit, or no resources at all to inherit the default ones, the heap size must not already be
+// set by the user in ES_JAVA_OPTS, and the resulting heap size must not be below the minimum.
+func validJVMHeap(es esv1.Elasticsearch) field.ErrorList {
+	var errs field.ErrorList
+	for i, ns := range es.Spec.NodeSets {
+		if ns.JVMHeap == nil {
+			continue
+		}
+		nodeSetPath := field.NewPath("spec").Child("nodeSets").Index(i)
+		if ns.JVMHeap.Max != nil && ns.JVMHeap.Max.Cmp(esv1.MinJVMHeap) < 0 {
+			errs = append(errs, field.Invalid(nodeSetPath.Child("jvmHeap", "max"), ns.JVMHeap.Max.String(), fmt.Sprintf(jvmHeapMaxTooLowMsg, esv1.MinJVMHeap.String())))
+		}
+		for j, c := range ns.PodTemplate.Spec.Containers {
+			if c.Name != esv1.ElasticsearchContainerName {
+				continue
+			}
+			containerPath := nodeSetPath.Child("podTemplate", "spec", "containers").Index(j)
+			hasResources := len(c.Resources.Requests) > 0 || len(c.Resources.Limits) > 0
+			memoryLimit := c.Resources.Limits.Memory()
+			switch {
+			case hasResources && memoryLimit.IsZero():
+				errs = append(errs, field.Required(containerPath.Child("resources", "limits", "memory"), jvmHeapMemoryLimitMissingMsg))
+			case !memoryLimit.IsZero() && memoryLimit.Value()*int64(ns.JVMHeap.MemoryLimitPercentOrDefault())/100 < esv1.MinJVMHeap.Value():
+				errs = append(errs, field.Invalid(containerPath.Child("resources", "limits", "memory"), memoryLimit.String(), fmt.Sprintf(jvmHeapMemoryLimitTooLowMsg, esv1.MinJVMHeap.String())))
+			}
+			for k, env := range c.Env {
+				if env.Name == settings.EnvEsJavaOpts && (strings.Contains(env.Value, "-Xms") || strings.Contains(env.Value, "-Xmx")) {
+					errs = append(errs, field.Forbidden(containerPath.Child("env").Index(k), jvmHeapConflictingJavaOptsMsg))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// validAdoptVolumes checks that the deleted cluster whose released volumes are adopted is identified by both its name
+// and its UUID, the latter also preventing a new cluster from being bootstrapped over the adopted data.
+func validAdoptVolumes(es esv1.Elasticsearch) field.ErrorList {
+	fromCluster, adopt := es.Annotations[esv1.AdoptVolumesFromAnnotation]
+	if !adopt {
+		return nil
+	}
+	var errs field.ErrorList
+	annotationsPath := field.NewPath("metadata").Child("annotations")
+	if fromCluster == "" {
+		errs = append(errs, field.Required(annotationsPath.Key(esv1.AdoptVolumesFromAnnotation), adoptVolumesFromClusterRequiredMsg))
+	}
+	if es.Annotations[bootstrap.ClusterUUIDAnnotationName] == "" {
+		errs = append(errs, field.Required(annotationsPath.Key(bootstrap.ClusterUUIDAnnotationName), adoptVolumesClusterUUIDRequiredMsg))
+	}
+	return errs
+}
+
 func getNodeRoleAttrs(cfg esv1.ElasticsearchSettings) []string {
 	var nodeRoleAttrs []string
 
@@ -564,6 +708,15 @@ func validateRestartAllocationDelayWarnings(es esv1.Elasticsearch) string {
 	return ""
 }
 
+func validateDiagnosticsIntervalWarnings(es esv1.Elasticsearch) string {
+	_, err := esv1.GetDiagnosticsIntervalAnnotation(es.Annotations)
+	if err != nil {
+		return fmt.Sprintf("diagnostics-interval annotation will be ignored due to error: %s", err.Error())
+	}
+
+	return ""
+}
+
 func validateRestartTriggerWarnings(ctx context.Context, k8sClient k8s.Client, oldCR, newCR esv1.Elasticsearch) string {
 	oldRestartTrigger := oldCR.Annotations[esv1.RestartTriggerAnnotation]
 	newRestartTrigger := newCR.Annotations[esv1.RestartTriggerAnnotation]
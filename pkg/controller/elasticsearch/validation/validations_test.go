@@ -6,18 +6,24 @@ package validation
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/bootstrap"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/label"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 )
@@ -160,6 +166,15 @@ func Test_hasCorrectNodeRoles(t *testing.T) {
 			name: "valid configuration (node attributes)",
 			es:   esWithRoles("7.6.0", 3, m{esv1.NodeMaster: "true", esv1.NodeData: "true"}, m{esv1.NodeData: "true"}),
 		},
+		{
+			name: "data tier nodes are not master eligible",
+			es: func() esv1.Elasticsearch {
+				x := es("8.15.0")
+				x.Spec.NodeSets = []esv1.NodeSet{{Name: "hot", Count: 3, Tier: esv1.HotTier}}
+				return x
+			}(),
+			expectErrors: true,
+		},
 		{
 			name: "valid configuration (node roles)",
 			es:   esWithRoles("7.9.0", 4, m{esv1.NodeRoles: []esv1.NodeRole{esv1.MasterRole, esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.DataRole}}, m{esv1.NodeRoles: []esv1.NodeRole{esv1.RemoteClusterClientRole}}),
@@ -176,6 +191,197 @@ func Test_hasCorrectNodeRoles(t *testing.T) {
 	}
 }
 
+func Test_validDataTiers(t *testing.T) {
+	esWithNodeSets := func(version string, nodeSets ...esv1.NodeSet) esv1.Elasticsearch {
+		x := es(version)
+		x.Spec.NodeSets = nodeSets
+		return x
+	}
+	withRoles := func(roles ...esv1.NodeRole) *commonv1.Config {
+		return &commonv1.Config{Data: map[string]any{esv1.NodeRoles: roles}}
+	}
+
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "no tier",
+			es:   esWithNodeSets("8.15.0", esv1.NodeSet{Name: "default", Count: 3}),
+		},
+		{
+			name: "hot warm cold frozen tiers",
+			es: esWithNodeSets("8.15.0",
+				esv1.NodeSet{Name: "master", Count: 3, Config: withRoles(esv1.MasterRole)},
+				esv1.NodeSet{Name: "hot", Count: 3, Tier: esv1.HotTier},
+				esv1.NodeSet{Name: "warm", Count: 2, Tier: esv1.WarmTier, Config: withRoles(esv1.DataWarmRole, esv1.IngestRole)},
+				esv1.NodeSet{Name: "cold", Count: 1, Tier: esv1.ColdTier, SearchableSnapshotRepository: "s3"},
+				esv1.NodeSet{Name: "frozen", Count: 1, Tier: esv1.FrozenTier, SearchableSnapshotRepository: "s3"},
+			),
+		},
+		{
+			name: "tier roles satisfied by the generic data role",
+			es:   esWithNodeSets("8.15.0", esv1.NodeSet{Name: "hot", Count: 1, Tier: esv1.HotTier, Config: withRoles(esv1.MasterRole, esv1.DataRole)}),
+		},
+		{
+			name:         "node roles missing the tier role",
+			es:           esWithNodeSets("8.15.0", esv1.NodeSet{Name: "warm", Count: 1, Tier: esv1.WarmTier, Config: withRoles(esv1.DataHotRole)}),
+			expectErrors: true,
+		},
+		{
+			name: "tier combined with node attributes",
+			es: esWithNodeSets("7.17.0", esv1.NodeSet{
+				Name: "cold", Count: 1, Tier: esv1.ColdTier, Config: &commonv1.Config{Data: map[string]any{esv1.NodeData: "true"}},
+			}),
+			expectErrors: true,
+		},
+		{
+			name:         "frozen tier before 7.12.0",
+			es:           esWithNodeSets("7.11.2", esv1.NodeSet{Name: "frozen", Count: 1, Tier: esv1.FrozenTier}),
+			expectErrors: true,
+		},
+		{
+			name:         "data tiers before 7.10.0",
+			es:           esWithNodeSets("7.9.3", esv1.NodeSet{Name: "hot", Count: 1, Tier: esv1.HotTier}),
+			expectErrors: true,
+		},
+		{
+			name:         "searchable snapshot repository on the hot tier",
+			es:           esWithNodeSets("8.15.0", esv1.NodeSet{Name: "hot", Count: 1, Tier: esv1.HotTier, SearchableSnapshotRepository: "s3"}),
+			expectErrors: true,
+		},
+		{
+			name:         "searchable snapshot repository without tier",
+			es:           esWithNodeSets("8.15.0", esv1.NodeSet{Name: "default", Count: 1, SearchableSnapshotRepository: "s3"}),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validDataTiers(tt.es)
+			hasErrors := len(result) > 0
+			if tt.expectErrors != hasErrors {
+				t.Errorf("expectedErrors=%t hasErrors=%t result=%+v", tt.expectErrors, hasErrors, result)
+			}
+		})
+	}
+}
+
+func Test_validJVMHeap(t *testing.T) {
+	esWithContainer := func(jvmHeap *esv1.JVMHeap, container corev1.Container) esv1.Elasticsearch {
+		x := es("8.15.0")
+		x.Spec.NodeSets = []esv1.NodeSet{{
+			Name:        "default",
+			Count:       3,
+			JVMHeap:     jvmHeap,
+			PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{container}}},
+		}}
+		return x
+	}
+	memory := func(requests, limits string) corev1.ResourceRequirements {
+		r := corev1.ResourceRequirements{}
+		if requests != "" {
+			r.Requests = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(requests)}
+		}
+		if limits != "" {
+			r.Limits = corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(limits)}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "no JVM heap policy",
+			es: esWithContainer(nil, corev1.Container{
+				Name: esv1.ElasticsearchContainerName,
+				Env:  []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xms1g -Xmx1g"}},
+			}),
+		},
+		{
+			name: "default resources",
+			es:   esWithContainer(&esv1.JVMHeap{}, corev1.Container{Name: esv1.ElasticsearchContainerName}),
+		},
+		{
+			name: "memory limit",
+			es: esWithContainer(&esv1.JVMHeap{}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("4Gi", "4Gi"),
+				Env:       []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-XX:+UseG1GC"}},
+			}),
+		},
+		{
+			name: "memory request without limit",
+			es: esWithContainer(&esv1.JVMHeap{}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("4Gi", ""),
+			}),
+			expectErrors: true,
+		},
+		{
+			name: "heap size already set in ES_JAVA_OPTS",
+			es: esWithContainer(&esv1.JVMHeap{}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("", "4Gi"),
+				Env:       []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xmx2g"}},
+			}),
+			expectErrors: true,
+		},
+		{
+			name: "maximum heap size",
+			es: esWithContainer(&esv1.JVMHeap{Max: ptr.To(resource.MustParse("1Gi"))}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("", "4Gi"),
+			}),
+		},
+		{
+			name: "zero maximum heap size",
+			es: esWithContainer(&esv1.JVMHeap{Max: ptr.To(resource.MustParse("0"))}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("", "4Gi"),
+			}),
+			expectErrors: true,
+		},
+		{
+			name: "negative maximum heap size",
+			es: esWithContainer(&esv1.JVMHeap{Max: ptr.To(resource.MustParse("-1Gi"))}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("", "4Gi"),
+			}),
+			expectErrors: true,
+		},
+		{
+			name: "maximum heap size below the minimum",
+			es: esWithContainer(&esv1.JVMHeap{Max: ptr.To(resource.MustParse("64Mi"))}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("", "4Gi"),
+			}),
+			expectErrors: true,
+		},
+		{
+			name: "memory limit leaving a heap below the minimum",
+			es: esWithContainer(&esv1.JVMHeap{}, corev1.Container{
+				Name:      esv1.ElasticsearchContainerName,
+				Resources: memory("", "200Mi"),
+			}),
+			expectErrors: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := validJVMHeap(tt.es)
+			hasErrors := len(result) > 0
+			if tt.expectErrors != hasErrors {
+				t.Errorf("expectedErrors=%t hasErrors=%t result=%+v", tt.expectErrors, hasErrors, result)
+			}
+		})
+	}
+}
+
 func Test_supportedVersion(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -210,6 +416,50 @@ func Test_supportedVersion(t *testing.T) {
 	}
 }
 
+func Test_catalogVersion(t *testing.T) {
+	catalog, err := container.ParseImageCatalog([]byte(`
+images:
+- image: elasticsearch/elasticsearch
+  version: 8.15.0
+  digest: sha256:` + strings.Repeat("a", 64)))
+	require.NoError(t, err)
+	container.SetImageCatalog(catalog)
+	defer container.SetImageCatalog(nil)
+
+	customImage := es("8.16.0")
+	customImage.Spec.Image = "my.registry/elasticsearch:8.16.0"
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name:         "version in the catalog",
+			es:           es("8.15.0"),
+			expectErrors: false,
+		},
+		{
+			name:         "version missing from the catalog should fail",
+			es:           es("8.16.0"),
+			expectErrors: true,
+		},
+		{
+			name:         "custom image is not restricted",
+			es:           customImage,
+			expectErrors: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := catalogVersion(tt.es)
+			actualErrors := len(actual) > 0
+			if tt.expectErrors != actualErrors {
+				t.Errorf("failed catalogVersion(). Name: %v, actual %v, wanted: %v, value: %v", tt.name, actual, tt.expectErrors, tt.es.Spec.Version)
+			}
+		})
+	}
+}
+
 func Test_supportsRemoteClusterUsingAPIKey(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -808,6 +1058,97 @@ func Test_validZoneAwarenessTopologyKeys(t *testing.T) {
 	}
 }
 
+func Test_validForcedZoneAwareness(t *testing.T) {
+	tests := []struct {
+		name         string
+		es           esv1.Elasticsearch
+		expectErrors bool
+	}{
+		{
+			name: "attributes mode without zones",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					NodeSets: []esv1.NodeSet{
+						{Name: "a", ZoneAwareness: &esv1.ZoneAwareness{}},
+						{Name: "b"},
+					},
+				},
+			},
+		},
+		{
+			name: "forced mode with zones",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					NodeSets: []esv1.NodeSet{
+						{Name: "a", ZoneAwareness: &esv1.ZoneAwareness{Mode: esv1.ZoneAwarenessForcedMode, Zones: []string{"z1", "z2"}}},
+					},
+				},
+			},
+		},
+		{
+			name: "forced mode without zones",
+			es: esv1.Elasticsearch{
+				Spec: esv1.ElasticsearchSpec{
+					NodeSets: []esv1.NodeSet{
+						{Name: "a", ZoneAwareness: &esv1.ZoneAwareness{Mode: esv1.ZoneAwarenessForcedMode}},
+					},
+				},
+			},
+			expectErrors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validForcedZoneAwareness(tt.es)
+			hasErrors := len(errs) > 0
+			assert.Equal(t, tt.expectErrors, hasErrors)
+		})
+	}
+}
+
+func Test_validAdoptVolumes(t *testing.T) {
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		expectErrors bool
+	}{
+		{
+			name: "no adoption",
+		},
+		{
+			name: "adoption with the cluster UUID",
+			annotations: map[string]string{
+				esv1.AdoptVolumesFromAnnotation:     "deleted",
+				bootstrap.ClusterUUIDAnnotationName: "4rtb2VFmRgmXFXW3Mg9szg",
+			},
+		},
+		{
+			name: "adoption without the cluster UUID",
+			annotations: map[string]string{
+				esv1.AdoptVolumesFromAnnotation: "deleted",
+			},
+			expectErrors: true,
+		},
+		{
+			name: "adoption without the cluster name",
+			annotations: map[string]string{
+				esv1.AdoptVolumesFromAnnotation:     "",
+				bootstrap.ClusterUUIDAnnotationName: "4rtb2VFmRgmXFXW3Mg9szg",
+			},
+			expectErrors: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validAdoptVolumes(esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}})
+			hasErrors := len(errs) > 0
+			assert.Equal(t, tt.expectErrors, hasErrors)
+		})
+	}
+}
+
 func Test_validZoneAwarenessAffinityInCompatibility(t *testing.T) {
 	requiredAffinityWithInExpression := func(key string, values []string) *corev1.Affinity {
 		return &corev1.Affinity{
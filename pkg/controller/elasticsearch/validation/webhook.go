@@ -18,6 +18,7 @@ import (
 
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/set"
@@ -32,13 +33,21 @@ const (
 var eslog = ulog.Log.WithName("es-validation")
 
 // RegisterWebhook will register the Elasticsearch validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, exposedNodeLabels NodeLabels, licenseChecker license.Checker, managedNamespaces []string) {
+func RegisterWebhook(
+	mgr ctrl.Manager,
+	validateStorageClass bool,
+	exposedNodeLabels NodeLabels,
+	licenseChecker license.Checker,
+	referenceValidator *commonwebhook.ReferenceValidator,
+	managedNamespaces []string,
+) {
 	wh := &validatingWebhook{
 		client:               mgr.GetClient(),
 		decoder:              admission.NewDecoder(mgr.GetScheme()),
 		validateStorageClass: validateStorageClass,
 		exposedNodeLabels:    exposedNodeLabels,
 		licenseChecker:       licenseChecker,
+		referenceValidator:   referenceValidator,
 		managedNamespaces:    set.Make(managedNamespaces...),
 	}
 	eslog.Info("Registering Elasticsearch validating webhook", "path", webhookPath)
@@ -51,6 +60,7 @@ type validatingWebhook struct {
 	validateStorageClass bool
 	exposedNodeLabels    NodeLabels
 	licenseChecker       license.Checker
+	referenceValidator   *commonwebhook.ReferenceValidator
 	managedNamespaces    set.StringSet
 }
 
@@ -67,6 +77,10 @@ func (wh *validatingWebhook) validateCreate(ctx context.Context, es esv1.Elastic
 		warnings = append(warnings, allocationDelayWarning)
 	}
 
+	if diagnosticsIntervalWarning := validateDiagnosticsIntervalWarnings(es); diagnosticsIntervalWarning != "" {
+		warnings = append(warnings, diagnosticsIntervalWarning)
+	}
+
 	return warnings, err
 }
 
@@ -102,6 +116,10 @@ func (wh *validatingWebhook) validateUpdate(ctx context.Context, prev esv1.Elast
 		warnings = append(warnings, allocationDelayWarning)
 	}
 
+	if diagnosticsIntervalWarning := validateDiagnosticsIntervalWarnings(curr); diagnosticsIntervalWarning != "" {
+		warnings = append(warnings, diagnosticsIntervalWarning)
+	}
+
 	return warnings, nil
 }
 
@@ -126,6 +144,12 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 			return admission.Denied(err.Error())
 		}
 
+		referenceWarnings, err := wh.referenceValidator.Validate(ctx, es, nil)
+		warnings = append(warnings, referenceWarnings...)
+		if err != nil {
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
+		}
+
 		if len(warnings) > 0 {
 			return admission.Allowed("").WithWarnings(warnings...)
 		}
@@ -145,6 +169,12 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 			return admission.Denied(err.Error())
 		}
 
+		referenceWarnings, err := wh.referenceValidator.Validate(ctx, es, oldObj)
+		warnings = append(warnings, referenceWarnings...)
+		if err != nil {
+			return admission.Denied(err.Error()).WithWarnings(warnings...)
+		}
+
 		if len(warnings) > 0 {
 			return admission.Allowed("").WithWarnings(warnings...)
 		}
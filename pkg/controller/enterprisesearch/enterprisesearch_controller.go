@@ -50,7 +50,7 @@ const (
 // The Manager will set fields on the Controller and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, params, &entv1.EnterpriseSearch{})
 	if err != nil {
 		return err
 	}
@@ -197,6 +197,11 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return results.WithError(err), status
 	}
 
+	if isMigrated(ent) {
+		// the Service is removed along with the Deployment
+		return r.reconcileMigration(ctx, ent, "", results)
+	}
+
 	// extract the metadata that should be propagated to children
 	meta := metadata.Propagate(&ent, metadata.Metadata{Labels: ent.GetIdentityLabels()})
 
@@ -241,6 +246,10 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 		return results.WithError(err), status
 	}
 
+	if ent.Spec.MigrationMode {
+		return r.reconcileMigration(ctx, ent, svc.Name, results)
+	}
+
 	// toggle read-only mode for Enterprise Search version upgrades
 	upgrade := VersionUpgrade{k8sClient: r.K8sClient(), recorder: r.Recorder(), ent: ent, dialer: r.Dialer}
 	if err := upgrade.Handle(ctx); err != nil {
@@ -266,6 +275,36 @@ func (r *ReconcileEnterpriseSearch) doReconcile(ctx context.Context, ent entv1.E
 	return results, status
 }
 
+// reconcileMigration moves the migration off Enterprise Search forward, instead of reconciling the Deployment.
+func (r *ReconcileEnterpriseSearch) reconcileMigration(
+	ctx context.Context,
+	ent entv1.EnterpriseSearch,
+	svcName string,
+	results *reconciler.Results,
+) (*reconciler.Results, entv1.EnterpriseSearchStatus) {
+	status := newStatus(ent)
+	migration := Migration{VersionUpgrade{k8sClient: r.K8sClient(), recorder: r.Recorder(), ent: ent, dialer: r.Dialer}}
+	migrationStatus, err := migration.Handle(ctx)
+	if err != nil {
+		return results.WithError(fmt.Errorf("migration: %w", err)), status
+	}
+	if migrationStatus.Phase != entv1.MigrationCompletedPhase {
+		results.WithReconciliationState(reconciler.RequeueAfter(migrationRequeue).WithReason("Enterprise Search migration in progress"))
+	}
+
+	var deploy appsv1.Deployment
+	err = r.K8sClient().Get(ctx, types.NamespacedName{Namespace: ent.Namespace, Name: DeploymentName(ent.Name)}, &deploy)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return results.WithError(err), status
+	}
+	status, err = r.generateStatus(ctx, ent, deploy, svcName)
+	if err != nil {
+		return results.WithError(fmt.Errorf("updating status: %w", err)), status
+	}
+	status.Migration = migrationStatus
+	return results, status
+}
+
 // newStatus will generate a new status, ensuring status.ObservedGeneration
 // follows the generation of the Enterprise Search object.
 func newStatus(ent entv1.EnterpriseSearch) entv1.EnterpriseSearchStatus {
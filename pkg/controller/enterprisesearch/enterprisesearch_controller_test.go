@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	toolsevents "k8s.io/client-go/tools/events"
@@ -213,6 +214,39 @@ func TestReconcileEnterpriseSearch_Reconcile_Create_Update_Resources(t *testing.
 	checkResources()
 }
 
+func TestReconcileEnterpriseSearch_Reconcile_Migrated(t *testing.T) {
+	sample := entv1.EnterpriseSearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "sample"},
+		Spec: entv1.EnterpriseSearchSpec{
+			Version:       "8.17.0",
+			Count:         1,
+			MigrationMode: true,
+		},
+		Status: entv1.EnterpriseSearchStatus{
+			Migration: &entv1.MigrationStatus{Phase: entv1.MigrationCompletedPhase},
+		},
+	}
+	service := corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: HTTPServiceName(sample.Name)}}
+	r := &ReconcileEnterpriseSearch{
+		Client:         k8s.NewFakeClient(&sample, &service),
+		dynamicWatches: watches.NewDynamicWatches(),
+		recorder:       toolsevents.NewFakeRecorder(10),
+		Parameters: operator.Parameters{
+			OperatorInfo: about.OperatorInfo{BuildInfo: about.BuildInfo{Version: "1.0.0"}},
+		},
+	}
+
+	_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: k8s.ExtractNamespacedName(&sample)})
+	require.NoError(t, err)
+
+	// the Service is deleted and not recreated
+	err = r.Client.Get(context.Background(), k8s.ExtractNamespacedName(&service), &corev1.Service{})
+	require.True(t, apierrors.IsNotFound(err))
+	var ent entv1.EnterpriseSearch
+	require.NoError(t, r.Client.Get(context.Background(), k8s.ExtractNamespacedName(&sample), &ent))
+	require.Empty(t, ent.Status.ExternalService)
+	require.Equal(t, entv1.MigrationCompletedPhase, ent.Status.Migration.Phase)
+}
+
 func TestReconcileEnterpriseSearch_doReconcile_AssociationDelaysVersionUpgrade(t *testing.T) {
 	// associate Enterprise Search 7.7.0 to Elasticsearch 7.7.0
 	es := esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "some-es"}}
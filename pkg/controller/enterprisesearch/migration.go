@@ -0,0 +1,207 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package enterprisesearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	entv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// EnginesAPIPath is the HTTP path of the App Search engines API.
+	EnginesAPIPath = "/api/as/v1/engines"
+	// ContentSourcesAPIPath is the HTTP path of the Workplace Search content sources API.
+	ContentSourcesAPIPath = "/api/ws/v1/sources"
+	// MigrationReqTimeout is the duration after which a request to list the Enterprise Search content should be canceled.
+	MigrationReqTimeout = 1 * time.Minute
+
+	// migrationPageSize is the number of items requested per page when listing the Enterprise Search content.
+	migrationPageSize = 100
+	// migrationRequeue is the delay after which an in-progress migration is reconciled again.
+	migrationRequeue = 10 * time.Second
+)
+
+// Migration moves users off Enterprise Search, which is not available in version 9.0.0 and later.
+// It relies on the same Enterprise Search API client as version upgrades, and goes through the following steps:
+//   - Pending: wait for Enterprise Search to be running, then enable read-only mode and report the App Search engines
+//     and Workplace Search content sources whose content needs to be moved to Elasticsearch.
+//   - ReadOnly: once the report is persisted in the status, remove the Enterprise Search Deployment and its Service.
+//   - Completed: the Deployment and the Service are removed. The association to Elasticsearch is left untouched.
+type Migration struct {
+	VersionUpgrade
+}
+
+// Handle moves the migration forward and returns its updated status.
+func (m *Migration) Handle(ctx context.Context) (*entv1.MigrationStatus, error) {
+	status := m.ent.Status.Migration.DeepCopy()
+	if status == nil {
+		status = &entv1.MigrationStatus{Phase: entv1.MigrationPendingPhase}
+	}
+
+	switch status.Phase {
+	case entv1.MigrationCompletedPhase:
+		// make sure the Deployment and the Service do not come back
+		return status, m.deleteDeploymentAndService(ctx)
+	case entv1.MigrationReadOnlyPhase:
+		// the report is persisted in the status, the Deployment and the Service can be removed
+		if err := m.deleteDeploymentAndService(ctx); err != nil {
+			return status, err
+		}
+		const msg = "Enterprise Search migration completed: the Deployment and the Service have been removed"
+		ulog.FromContext(ctx).Info(msg, "namespace", m.ent.Namespace, "ent_name", m.ent.Name)
+		k8s.EmitEvent(m.recorder, &m.ent, corev1.EventTypeNormal, events.EventReasonMigrated, events.EventActionMigration, msg)
+		status.Phase = entv1.MigrationCompletedPhase
+		return status, nil
+	}
+
+	var deployment appsv1.Deployment
+	err := m.k8sClient.Get(ctx, types.NamespacedName{Namespace: m.ent.Namespace, Name: DeploymentName(m.ent.Name)}, &deployment)
+	if apierrors.IsNotFound(err) {
+		// Enterprise Search never ran: nothing to migrate
+		status.Phase = entv1.MigrationCompletedPhase
+		return status, nil
+	}
+	if err != nil {
+		return status, err
+	}
+
+	esAssocConf, err := m.ent.AssociationConf()
+	if err != nil {
+		return status, err
+	}
+	if !esAssocConf.AuthIsConfigured() {
+		// no Elasticsearch user available to reach the Enterprise Search API
+		const msg = "Enterprise Search migration requires an association to Elasticsearch, " +
+			"please enable read-only mode and remove the Deployment manually"
+		k8s.EmitEvent(m.recorder, &m.ent, corev1.EventTypeWarning, events.EventReasonDelayed, events.EventActionMigration, msg)
+		return status, nil
+	}
+
+	pods, err := m.getActualPods()
+	if err != nil {
+		return status, err
+	}
+	if len(pods) == 0 {
+		ulog.FromContext(ctx).Info("Waiting for Enterprise Search to be running to enable read-only mode",
+			"namespace", m.ent.Namespace, "ent_name", m.ent.Name)
+		return status, nil
+	}
+
+	if err := m.enableReadOnlyMode(ctx); err != nil {
+		return status, err
+	}
+
+	httpClient, err := m.apiClient()
+	if err != nil {
+		return status, err
+	}
+	defer httpClient.CloseIdleConnections()
+
+	engines, err := m.listNames(ctx, httpClient, EnginesAPIPath)
+	if err != nil {
+		return status, fmt.Errorf("while listing App Search engines: %w", err)
+	}
+	sources, err := m.listNames(ctx, httpClient, ContentSourcesAPIPath)
+	if err != nil {
+		return status, fmt.Errorf("while listing Workplace Search content sources: %w", err)
+	}
+
+	msg := fmt.Sprintf("Enterprise Search is in read-only mode: %d App Search engines and %d Workplace Search content sources to migrate",
+		len(engines), len(sources))
+	ulog.FromContext(ctx).Info(msg, "namespace", m.ent.Namespace, "ent_name", m.ent.Name)
+	k8s.EmitEvent(m.recorder, &m.ent, corev1.EventTypeNormal, events.EventReasonMigrated, events.EventActionMigration, msg)
+
+	status.Phase = entv1.MigrationReadOnlyPhase
+	status.Engines = engines
+	status.ContentSources = sources
+	return status, nil
+}
+
+// deleteDeploymentAndService deletes the Enterprise Search Deployment and its HTTP Service, if they exist.
+func (m *Migration) deleteDeploymentAndService(ctx context.Context) error {
+	deployment := appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: m.ent.Namespace, Name: DeploymentName(m.ent.Name)}}
+	if err := m.k8sClient.Delete(ctx, &deployment); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	service := corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: m.ent.Namespace, Name: HTTPServiceName(m.ent.Name)}}
+	if err := m.k8sClient.Delete(ctx, &service); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// isMigrated returns true if the migration off the given Enterprise Search completed: its Deployment and its Service
+// must not be reconciled anymore.
+func isMigrated(ent entv1.EnterpriseSearch) bool {
+	return ent.Spec.MigrationMode && ent.Status.Migration != nil && ent.Status.Migration.Phase == entv1.MigrationCompletedPhase
+}
+
+// pagedNames is the subset of a paginated Enterprise Search API response used to list the names of its results.
+type pagedNames struct {
+	Meta struct {
+		Page struct {
+			Current    int `json:"current"`
+			TotalPages int `json:"total_pages"`
+		} `json:"page"`
+	} `json:"meta"`
+	Results []struct {
+		Name string `json:"name"`
+	} `json:"results"`
+}
+
+// listNames returns the names of all the results of the paginated Enterprise Search API at the given path.
+// A product that is not available returns no names.
+func (m *Migration) listNames(ctx context.Context, httpClient *http.Client, path string) ([]string, error) {
+	var names []string
+	for page := 1; ; page++ {
+		req, err := m.apiRequest(ctx, http.MethodGet, fmt.Sprintf("%s?page[current]=%d&page[size]=%d", path, page, migrationPageSize), nil)
+		if err != nil {
+			return nil, err
+		}
+		timeoutCtx, cancel := context.WithTimeout(ctx, MigrationReqTimeout)
+		resp, err := httpClient.Do(req.WithContext(timeoutCtx))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusNotFound {
+			return names, nil
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("invalid API response (status code %d): %s", resp.StatusCode, body)
+		}
+		var result pagedNames
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, err
+		}
+		for _, r := range result.Results {
+			names = append(names, r.Name)
+		}
+		if len(result.Results) == 0 || page >= result.Meta.Page.TotalPages {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package enterprisesearch
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolsevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	entv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/enterprisesearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+// fakeAPI mocks the Enterprise Search API, returning the response registered for each request URL.
+type fakeAPI struct {
+	responses map[string]string
+	requests  *[]string
+}
+
+func (f fakeAPI) RoundTrip(req *http.Request) (*http.Response, error) {
+	*f.requests = append(*f.requests, req.Method+" "+req.URL.Path+"?"+req.URL.RawQuery)
+	body, exists := f.responses[req.URL.Path+"?"+req.URL.RawQuery]
+	if req.Method == http.MethodPut && req.URL.Path == ReadOnlyModeAPIPath {
+		body, exists = "{}", true
+	}
+	statusCode := http.StatusOK
+	if !exists {
+		statusCode = http.StatusNotFound
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil
+}
+
+func entWithMigration(status *entv1.MigrationStatus, annotations map[string]string) entv1.EnterpriseSearch {
+	ent := entWithVersion("8.17.0", annotations)
+	ent.Spec.MigrationMode = true
+	ent.Status.Migration = status
+	return ent
+}
+
+func TestMigration_Handle(t *testing.T) {
+	const (
+		enginesPage1 = EnginesAPIPath + "?page[current]=1&page[size]=100"
+		enginesPage2 = EnginesAPIPath + "?page[current]=2&page[size]=100"
+		sourcesPage1 = ContentSourcesAPIPath + "?page[current]=1&page[size]=100"
+	)
+	tests := []struct {
+		name            string
+		ent             entv1.EnterpriseSearch
+		runtimeObjs     []client.Object
+		responses       map[string]string
+		wantStatus      *entv1.MigrationStatus
+		wantRequests    []string
+		wantDeployment  bool
+		wantService     bool
+		wantReadOnlyAnn bool
+	}{
+		{
+			name:        "no Deployment: nothing to migrate",
+			ent:         entWithMigration(nil, nil),
+			wantStatus:  &entv1.MigrationStatus{Phase: entv1.MigrationCompletedPhase},
+			wantService: true,
+		},
+		{
+			name:           "no Pod running: wait",
+			ent:            entWithMigration(nil, nil),
+			runtimeObjs:    []client.Object{deploymentWithVersion("8.17.0")},
+			wantStatus:     &entv1.MigrationStatus{Phase: entv1.MigrationPendingPhase},
+			wantDeployment: true,
+			wantService:    true,
+		},
+		{
+			name: "Enterprise Search running: enable read-only mode and report its content",
+			ent:  entWithMigration(nil, nil),
+			runtimeObjs: []client.Object{
+				deploymentWithVersion("8.17.0"),
+				podWithVersion("pod1", "8.17.0"),
+			},
+			responses: map[string]string{
+				enginesPage1: `{"meta":{"page":{"current":1,"total_pages":2}},"results":[{"name":"engine-a"},{"name":"engine-b"}]}`,
+				enginesPage2: `{"meta":{"page":{"current":2,"total_pages":2}},"results":[{"name":"engine-c"}]}`,
+				sourcesPage1: `{"meta":{"page":{"current":1,"total_pages":1}},"results":[{"id":"1","name":"drive"}]}`,
+			},
+			wantStatus: &entv1.MigrationStatus{
+				Phase:          entv1.MigrationReadOnlyPhase,
+				Engines:        []string{"engine-a", "engine-b", "engine-c"},
+				ContentSources: []string{"drive"},
+			},
+			wantRequests: []string{
+				"PUT " + ReadOnlyModeAPIPath + "?",
+				"GET " + enginesPage1,
+				"GET " + enginesPage2,
+				"GET " + sourcesPage1,
+			},
+			wantDeployment:  true,
+			wantService:     true,
+			wantReadOnlyAnn: true,
+		},
+		{
+			name: "Workplace Search not available: report App Search engines only",
+			ent:  entWithMigration(nil, map[string]string{ReadOnlyModeAnnotationName: "true"}),
+			runtimeObjs: []client.Object{
+				deploymentWithVersion("8.17.0"),
+				podWithVersion("pod1", "8.17.0"),
+			},
+			responses: map[string]string{
+				enginesPage1: `{"meta":{"page":{"current":1,"total_pages":1}},"results":[{"name":"engine-a"}]}`,
+			},
+			wantStatus: &entv1.MigrationStatus{
+				Phase:   entv1.MigrationReadOnlyPhase,
+				Engines: []string{"engine-a"},
+			},
+			wantRequests: []string{
+				"GET " + enginesPage1,
+				"GET " + sourcesPage1,
+			},
+			wantDeployment:  true,
+			wantService:     true,
+			wantReadOnlyAnn: true,
+		},
+		{
+			name: "content reported: remove the Deployment and the Service",
+			ent: entWithMigration(
+				&entv1.MigrationStatus{Phase: entv1.MigrationReadOnlyPhase, Engines: []string{"engine-a"}},
+				map[string]string{ReadOnlyModeAnnotationName: "true"},
+			),
+			runtimeObjs: []client.Object{
+				deploymentWithVersion("8.17.0"),
+				podWithVersion("pod1", "8.17.0"),
+			},
+			wantStatus:      &entv1.MigrationStatus{Phase: entv1.MigrationCompletedPhase, Engines: []string{"engine-a"}},
+			wantReadOnlyAnn: true,
+		},
+		{
+			name: "migration completed: keep the Deployment and the Service removed",
+			ent: entWithMigration(
+				&entv1.MigrationStatus{Phase: entv1.MigrationCompletedPhase, Engines: []string{"engine-a"}},
+				map[string]string{ReadOnlyModeAnnotationName: "true"},
+			),
+			runtimeObjs:     []client.Object{deploymentWithVersion("8.17.0")},
+			wantStatus:      &entv1.MigrationStatus{Phase: entv1.MigrationCompletedPhase, Engines: []string{"engine-a"}},
+			wantReadOnlyAnn: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			httpClient := &http.Client{Transport: fakeAPI{responses: tt.responses, requests: &requests}}
+			service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: HTTPServiceName("ent")}}
+			k8sClient := k8s.NewFakeClient(append(append(tt.runtimeObjs, &esUserSecret, service), &tt.ent)...)
+			m := Migration{VersionUpgrade{
+				k8sClient:  k8sClient,
+				ent:        tt.ent,
+				httpClient: httpClient,
+				recorder:   toolsevents.NewFakeRecorder(10),
+			}}
+			status, err := m.Handle(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, status)
+			require.Equal(t, tt.wantRequests, requests)
+
+			var deployment appsv1.Deployment
+			err = k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "ns", Name: DeploymentName("ent")}, &deployment)
+			if tt.wantDeployment {
+				require.NoError(t, err)
+			} else {
+				require.True(t, apierrors.IsNotFound(err))
+			}
+
+			err = k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(service), &corev1.Service{})
+			if tt.wantService {
+				require.NoError(t, err)
+			} else {
+				require.True(t, apierrors.IsNotFound(err))
+			}
+
+			var ent entv1.EnterpriseSearch
+			require.NoError(t, k8sClient.Get(context.Background(), k8s.ExtractNamespacedName(&tt.ent), &ent))
+			require.Equal(t, tt.wantReadOnlyAnn, hasReadOnlyAnnotationTrue(ent))
+		})
+	}
+}
@@ -73,7 +73,8 @@ func newPodSpec(ent entv1.EnterpriseSearch, configHash string) (corev1.PodTempla
 	builder := defaults.NewPodTemplateBuilder(ent.Spec.PodTemplate, entv1.EnterpriseSearchContainerName).
 		WithAnnotations(annotations).
 		WithResources(DefaultResources).
-		WithDockerImage(ent.Spec.Image, container.ImageRepository(container.EnterpriseSearchImage, v)).
+		WithDockerImage(ent.Spec.Image, container.ImageRepository(ent.Namespace, container.EnterpriseSearchImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(ent.Namespace)...).
 		WithPorts(defaultContainerPorts).
 		WithReadinessProbe(ReadinessProbe).
 		WithEnv(DefaultEnv...).
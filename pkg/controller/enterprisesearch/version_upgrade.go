@@ -106,6 +106,12 @@ func (r *VersionUpgrade) Handle(ctx context.Context) error {
 		return err
 	}
 
+	if len(actualPods) == 0 {
+		// no Pod to reach yet, for example when the Deployment is recreated after a migration was canceled:
+		// we'll retry eventually once Pods are running
+		return nil
+	}
+
 	return r.disableReadOnlyMode(ctx)
 }
 
@@ -165,24 +171,11 @@ func hasReadOnlyAnnotationTrue(ent entv1.EnterpriseSearch) bool {
 
 // setReadOnlyMode performs an API call to Enterprise Search to set the read-only mode setting to the given value.
 func (r *VersionUpgrade) setReadOnlyMode(ctx context.Context, enabled bool) error {
-	httpClient := r.httpClient
-	if httpClient == nil {
-		// build an HTTP client to reach the Enterprise Search service
-		var tlsCerts []*x509.Certificate
-		if r.ent.Spec.HTTP.TLS.Enabled() {
-			var err error
-			tlsCerts, err = r.retrieveTLSCerts()
-			if err != nil {
-				return err
-			}
-		}
-		httpClient = apmhttp.WrapClient(
-			commonhttp.Client(r.dialer, tlsCerts, 0),
-			apmhttp.WithClientRequestName(tracing.RequestName),
-			apmhttp.WithClientSpanType("external.enterprisesearch"),
-		)
-		defer httpClient.CloseIdleConnections()
+	httpClient, err := r.apiClient()
+	if err != nil {
+		return err
 	}
+	defer httpClient.CloseIdleConnections()
 
 	request, err := r.readOnlyModeRequest(ctx, enabled)
 	if err != nil {
@@ -210,6 +203,27 @@ func (r *VersionUpgrade) setReadOnlyMode(ctx context.Context, enabled bool) erro
 	return nil
 }
 
+// apiClient returns the HTTP client to reach the Enterprise Search API.
+func (r *VersionUpgrade) apiClient() (*http.Client, error) {
+	if r.httpClient != nil {
+		return r.httpClient, nil
+	}
+	// build an HTTP client to reach the Enterprise Search service
+	var tlsCerts []*x509.Certificate
+	if r.ent.Spec.HTTP.TLS.Enabled() {
+		var err error
+		tlsCerts, err = r.retrieveTLSCerts()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return apmhttp.WrapClient(
+		commonhttp.Client(r.dialer, tlsCerts, 0),
+		apmhttp.WithClientRequestName(tracing.RequestName),
+		apmhttp.WithClientSpanType("external.enterprisesearch"),
+	), nil
+}
+
 // serviceURL builds the URL of the Enterprise Search service.
 func (r *VersionUpgrade) serviceURL() string {
 	return fmt.Sprintf("%s://%s.%s.svc:%d",
@@ -218,21 +232,33 @@ func (r *VersionUpgrade) serviceURL() string {
 
 // readOnlyModeRequest builds the HTTP request to toggle the read-only mode on Enterprise Search.
 func (r *VersionUpgrade) readOnlyModeRequest(ctx context.Context, enabled bool) (*http.Request, error) {
-	credentials, err := association.ElasticsearchAuthSettings(ctx, r.k8sClient, &r.ent)
+	body := bytes.NewBuffer(fmt.Appendf(nil, "{\"enabled\": %t}", enabled))
+
+	req, err := r.apiRequest(ctx, http.MethodPut, ReadOnlyModeAPIPath, body)
 	if err != nil {
 		return nil, err
 	}
 
-	url := stringsutil.Concat(r.serviceURL(), ReadOnlyModeAPIPath)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	body := bytes.NewBuffer(fmt.Appendf(nil, "{\"enabled\": %t}", enabled))
+	return req, nil
+}
 
-	req, err := http.NewRequest(http.MethodPut, url, body) //nolint:noctx
+// apiRequest builds an HTTP request to the given path of the Enterprise Search API, authenticated with the
+// Elasticsearch user of the association.
+func (r *VersionUpgrade) apiRequest(ctx context.Context, method string, path string, body io.Reader) (*http.Request, error) {
+	credentials, err := association.ElasticsearchAuthSettings(ctx, r.k8sClient, &r.ent)
+	if err != nil {
+		return nil, err
+	}
+
+	url := stringsutil.Concat(r.serviceURL(), path)
+
+	req, err := http.NewRequest(method, url, body) //nolint:noctx
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	req.SetBasicAuth(credentials.Username, credentials.Password)
 
 	return req, nil
@@ -180,6 +180,18 @@ func TestVersionUpgrade_Handle(t *testing.T) {
 			},
 			wantUpdatedEnt: entWithVersion("7.7.0", map[string]string{}),
 		},
+		{
+			name: "read-only mode enabled, but no Pod running: wait for Pods to disable it",
+			ent: entWithVersion("7.7.1", map[string]string{
+				ReadOnlyModeAnnotationName: "true",
+			}),
+			httpChecks: roundTripChecks{
+				called: false,
+			},
+			wantUpdatedEnt: entWithVersion("7.7.1", map[string]string{
+				ReadOnlyModeAnnotationName: "true",
+			}),
+		},
 		{
 			name: "version upgrade requested, but no association configured : do nothing",
 			ent: entv1.EnterpriseSearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ent"},
@@ -125,9 +125,20 @@ func (d *driver) Reconcile(
 		return results
 	}
 
+	// detect parallel version upgrades before the HTTP Service selector is reconciled
+	upgrade, err := newParallelUpgrade(ctx, d.client, d.recorder, *kb, params.Dialer)
+	if err != nil {
+		return results.WithError(err)
+	}
+
 	// metadata to propagate to children
 	meta := metadata.Propagate(kb, metadata.Metadata{Labels: kb.GetIdentityLabels()})
-	svc, err := common.ReconcileService(ctx, d.client, NewService(*kb, meta), kb)
+	expectedSvc := NewService(*kb, meta)
+	if selector := upgrade.ServiceSelector(); selector != nil && kb.Spec.HTTP.Service.Spec.Selector == nil {
+		// route the traffic to a single version while both run in parallel
+		expectedSvc.Spec.Selector = selector
+	}
+	svc, err := common.ReconcileService(ctx, d.client, expectedSvc, kb)
 	if err != nil {
 		// TODO: consider updating some status here?
 		return results.WithError(err)
@@ -197,10 +208,17 @@ func (d *driver) Reconcile(
 	}
 
 	expectedDp := deployment.New(deploymentParams)
-	reconciledDp, err := deployment.Reconcile(ctx, d.client, expectedDp, kb)
+	reconciledDp, upgradeInProgress, err := upgrade.ReconcileDeployments(ctx, expectedDp, meta)
 	if err != nil {
 		return results.WithError(err)
 	}
+	if upgradeInProgress {
+		results.WithReconciliationState(reconciler.RequeueAfter(parallelUpgradeRequeue).WithReason("Kibana version upgrade in progress"))
+	}
+
+	if err := deployment.ReconcileHorizontalPodAutoscaler(ctx, d.client, expectedDp, kb.Spec.Autoscaling, meta, kb); err != nil {
+		return results.WithError(err)
+	}
 
 	existingPods, err := k8s.PodsMatchingLabels(d.K8sClient(), kb.Namespace, map[string]string{kblabel.KibanaNameLabelName: kb.Name})
 	if err != nil {
@@ -218,6 +236,7 @@ func (d *driver) Reconcile(
 // getStrategyType decides which deployment strategy (RollingUpdate or Recreate) to use based on whether the version
 // upgrade is in progress. Kibana does not support a smooth rolling upgrade from one version to another:
 // running multiple versions simultaneously may lead to concurrency bugs and data corruption.
+// The Parallel upgrade strategy avoids the downtime by serving the traffic from a separate Deployment, see ParallelUpgrade.
 func (d *driver) getStrategyType(kb *kbv1.Kibana) (appsv1.DeploymentStrategyType, error) {
 	var pods corev1.PodList
 	var labels client.MatchingLabels = map[string]string{kblabel.KibanaNameLabelName: kb.Name}
@@ -318,6 +337,7 @@ func (d *driver) deploymentParams(ctx context.Context, kb *kbv1.Kibana, policyAn
 		Name:                 kbv1.KBNamer.Suffix(kb.Name),
 		Namespace:            kb.Namespace,
 		Replicas:             kb.Spec.Count,
+		Autoscaled:           kb.Spec.Autoscaling != nil,
 		Selector:             kb.GetIdentityLabels(),
 		Metadata:             meta,
 		PodTemplateSpec:      kibanaPodSpec,
@@ -20,6 +20,10 @@ const (
 	// KibanaVersionLabelName used to propagate Kibana version from the spec to the pods
 	KibanaVersionLabelName = "kibana.k8s.elastic.co/version"
 
+	// KibanaUpgradeLabelName holds the name of Kibana on the Pods running the new version next to the prior one during
+	// a parallel upgrade, in place of KibanaNameLabelName so that they are not matched by the Kibana Deployment selector
+	KibanaUpgradeLabelName = "kibana.k8s.elastic.co/upgrade"
+
 	// Type represents the Kibana type
 	Type = "kibana"
 )
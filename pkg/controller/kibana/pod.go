@@ -136,7 +136,8 @@ func NewPodTemplateSpec(
 		WithResources(DefaultResources).
 		WithLabels(meta.Labels).
 		WithAnnotations(meta.Annotations).
-		WithDockerImage(kb.Spec.Image, container.ImageRepository(container.KibanaImage, v)).
+		WithDockerImage(kb.Spec.Image, container.ImageRepository(kb.Namespace, container.KibanaImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(kb.Namespace)...).
 		WithReadinessProbe(readinessProbe(kb.Spec.HTTP.TLS.Enabled(), basePath)).
 		WithVolumes(scriptsConfigMapVolume.Volume()).WithVolumeMounts(scriptsConfigMapVolume.VolumeMount()).
 		WithVolumes(PluginsVolume.Volume()).WithVolumeMounts(PluginsVolume.VolumeMount()).
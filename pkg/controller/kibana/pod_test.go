@@ -51,7 +51,7 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				kibanaContainer := GetKibanaContainer(pod.Spec)
 				require.NotNil(t, kibanaContainer)
 				assert.Equal(t, 2, len(kibanaContainer.VolumeMounts))
-				assert.Equal(t, container.ImageRepository(container.KibanaImage, version.MustParse("7.1.0")), kibanaContainer.Image)
+				assert.Equal(t, container.ImageRepository("", container.KibanaImage, version.MustParse("7.1.0")), kibanaContainer.Image)
 				assert.NotNil(t, kibanaContainer.ReadinessProbe)
 				assert.NotEmpty(t, kibanaContainer.Ports)
 			},
@@ -8,10 +8,12 @@ import (
 	"context"
 	_ "embed" // for the beats config files
 
+	corev1 "k8s.io/api/core/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 )
 
@@ -25,37 +27,21 @@ var (
 	filebeatConfig string
 )
 
-// ReconcileConfigSecrets reconciles the secrets holding beats configuration
+// ReconcileConfigSecrets reconciles the secrets holding beats or EDOT collector configuration, and the shared
+// collector of the namespace in the Shared monitoring mode.
 func ReconcileConfigSecrets(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath string, meta metadata.Metadata) error {
-	isMonitoringReconcilable, err := monitoring.IsReconcilable(&kb)
-	if err != nil {
-		return err
-	}
-	if !isMonitoringReconcilable {
-		return nil
-	}
-
-	if monitoring.IsMetricsDefined(&kb) {
-		b, err := Metricbeat(ctx, client, kb, basePath, meta)
-		if err != nil {
-			return err
-		}
-
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &kb); err != nil {
-			return err
-		}
-	}
-
-	if monitoring.IsLogsDefined(&kb) {
-		b, err := Filebeat(ctx, client, kb, meta)
-		if err != nil {
-			return err
-		}
-
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &kb); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return stackmon.ReconcileConfigSecrets(ctx, client, &kb, commonv1.KbMonitoringAssociationType, stackmon.ConfigBuilders{
+		Metricbeat: func() (stackmon.BeatSidecar, error) {
+			return Metricbeat(ctx, client, kb, basePath, meta)
+		},
+		Filebeat: func() (stackmon.BeatSidecar, error) {
+			return Filebeat(ctx, client, kb, meta)
+		},
+		Collector: func() (stackmon.BeatSidecar, error) {
+			return Collector(ctx, client, kb, basePath, meta)
+		},
+		SharedCollector: func() (corev1.Secret, error) {
+			return SharedCollectorConfig(ctx, client, kb, basePath, meta)
+		},
+	})
 }
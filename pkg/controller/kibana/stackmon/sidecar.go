@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash"
 	"hash/fnv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -36,10 +37,12 @@ const (
 	kibanaLogsMountPath  = "/usr/share/kibana/logs"
 )
 
-func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath string, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+// metricbeatConfig renders the Metricbeat configuration to collect monitoring data from Kibana at the given URL, and
+// returns it along with the volume holding the CA certificate of Kibana, if any.
+func metricbeatConfig(client k8s.Client, kb kbv1.Kibana, basePath string, url string) (string, volume.VolumeLike, error) {
 	if !kb.Spec.ElasticsearchRef.IsSet() {
 		// should never happen because of the pre-creation validation
-		return stackmon.BeatSidecar{}, errors.New(validations.InvalidKibanaElasticsearchRefForStackMonitoringMsg) //nolint:staticcheck
+		return "", nil, errors.New(validations.InvalidKibanaElasticsearchRefForStackMonitoringMsg) //nolint:staticcheck
 	}
 	associatedEsNsn := kb.Spec.ElasticsearchRef.NamespacedName()
 	if associatedEsNsn.Namespace == "" {
@@ -51,7 +54,7 @@ func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath
 	if esAssoc := kb.EsAssociation(); esAssoc.AssociationRef().IsExternal() {
 		info, err := association.GetUnmanagedAssociationConnectionInfoFromSecret(client, esAssoc)
 		if err != nil {
-			return stackmon.BeatSidecar{}, err
+			return "", nil, err
 		}
 		username, password = info.Username, info.Password
 	} else {
@@ -59,17 +62,17 @@ func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath
 		username = user.MonitoringUserName
 		password, err = user.GetMonitoringUserPassword(client, associatedEsNsn)
 		if err != nil {
-			return stackmon.BeatSidecar{}, err
+			return "", nil, err
 		}
 	}
 
 	v, err := version.Parse(kb.Spec.Version)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err // error unlikely and should have been caught during validation
+		return "", nil, err // error unlikely and should have been caught during validation
 	}
 	caVol, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&kb), kbv1.KBNamer, commonv1.KbMonitoringAssociationType, kb.Spec.HTTP.TLS.Enabled())
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
 
 	type inputConfigData struct {
@@ -81,8 +84,8 @@ func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath
 		TemplateParams: stackmon.TemplateParams{
 			Username: username,
 			Password: password,
-			URL:      fmt.Sprintf("%s://localhost:%d", kb.Spec.HTTP.Protocol(), network.HTTPPort), // Metricbeat in the sidecar connects to the monitored resource using `localhost`
-			IsSSL:    kb.Spec.HTTP.TLS.Enabled(),                                                  // enable SSL configuration based on whether the monitored resource has TLS enabled
+			URL:      url,
+			IsSSL:    kb.Spec.HTTP.TLS.Enabled(), // enable SSL configuration based on whether the monitored resource has TLS enabled
 			CAVolume: caVol,
 		},
 		BasePath: basePath,
@@ -90,22 +93,72 @@ func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath
 
 	cfg, err := stackmon.RenderTemplate(v, metricbeatConfigTemplate, configData)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
+	return cfg, caVol, nil
+}
+
+// localURL is the URL used by the sidecars to reach Kibana in the same Pod.
+func localURL(kb kbv1.Kibana) string {
+	return fmt.Sprintf("%s://localhost:%d", kb.Spec.HTTP.Protocol(), network.HTTPPort)
+}
 
-	metricbeat, err := stackmon.NewMetricBeatSidecar(ctx, client, &kb, v, caVol, cfg, meta)
+func Metricbeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath string, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	// Metricbeat in the sidecar connects to the monitored resource using `localhost`
+	cfg, caVol, err := metricbeatConfig(client, kb, basePath, localURL(kb))
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
-	return metricbeat, nil
+	v, err := version.Parse(kb.Spec.Version)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err // error unlikely and should have been caught during validation
+	}
+	return stackmon.NewMetricBeatSidecar(ctx, client, &kb, v, caVol, cfg, meta)
 }
 
 func Filebeat(ctx context.Context, client k8s.Client, kb kbv1.Kibana, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
 	return stackmon.NewFileBeatSidecar(ctx, client, &kb, kb.Spec.Version, filebeatConfig, nil, meta)
 }
 
-// WithMonitoring updates the Kibana Pod template builder to deploy Metricbeat and Filebeat in sidecar containers
-// in the Kibana pod and injects the volumes for the beat configurations and the ES CA certificates.
+// Collector returns an EDOT collector sidecar collecting both the metrics and the logs of the Kibana Pod.
+func Collector(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath string, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	v, err := version.Parse(kb.Spec.Version)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err // error unlikely and should have been caught during validation
+	}
+
+	var metricsCfg, logsCfg string
+	var caVol volume.VolumeLike
+	if monitoring.IsMetricsDefined(&kb) {
+		metricsCfg, caVol, err = metricbeatConfig(client, kb, basePath, localURL(kb))
+		if err != nil {
+			return stackmon.BeatSidecar{}, err
+		}
+	}
+	if monitoring.IsLogsDefined(&kb) {
+		logsCfg = filebeatConfig
+	}
+	return stackmon.NewCollectorSidecar(ctx, client, &kb, v, metricsCfg, logsCfg, meta, caVol)
+}
+
+// SharedCollectorConfig returns the configuration of the shared collector of the namespace to collect the metrics of
+// Kibana through its HTTP Service.
+func SharedCollectorConfig(ctx context.Context, client k8s.Client, kb kbv1.Kibana, basePath string, meta metadata.Metadata) (corev1.Secret, error) {
+	url := fmt.Sprintf("%s://%s.%s.svc:%d", kb.Spec.HTTP.Protocol(), kbv1.HTTPService(kb.Name), kb.Namespace, network.HTTPPort)
+	cfg, caVol, err := metricbeatConfig(client, kb, basePath, url)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	v, err := version.Parse(kb.Spec.Version)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	return stackmon.NewSharedCollectorConfig(ctx, client, &kb, v, cfg, caVol, meta)
+}
+
+// WithMonitoring updates the Kibana Pod template builder to deploy Metricbeat and Filebeat in sidecar containers, or a
+// single EDOT collector sidecar, in the Kibana pod and injects the volumes for their configurations and the ES CA
+// certificates. Nothing is deployed in the Pod in the Shared monitoring mode.
 func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, kb kbv1.Kibana, basePath string, meta metadata.Metadata) (*defaults.PodTemplateBuilder, error) {
 	isMonitoringReconcilable, err := monitoring.IsReconcilable(&kb)
 	if err != nil {
@@ -116,8 +169,46 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 	}
 
 	configHash := fnv.New32a()
-	volumes := make([]corev1.Volume, 0)
+	var volumes []corev1.Volume
+
+	switch kb.Spec.Monitoring.GetMode() {
+	case commonv1.SharedMonitoringMode:
+		// metrics are collected by the shared collector of the namespace
+		return builder, nil
+	case commonv1.CollectorMonitoringMode:
+		b, err := Collector(ctx, client, kb, basePath, meta)
+		if err != nil {
+			return nil, err
+		}
+		if monitoring.IsLogsDefined(&kb) {
+			// create a logs volume shared between Kibana and the collector
+			logsVolume := volume.NewEmptyDirVolume(kibanaLogsVolumeName, kibanaLogsMountPath)
+			volumes = append(volumes, logsVolume.Volume())
+			b.Container.VolumeMounts = append(b.Container.VolumeMounts, logsVolume.VolumeMount())
+			builder.WithVolumeMounts(logsVolume.VolumeMount())
+		}
+		volumes = append(volumes, b.Volumes...)
+		builder.WithContainers(b.Container)
+		configHash.Write(b.ConfigHash.Sum(nil))
+	default:
+		volumes, err = withBeatSidecars(ctx, client, builder, kb, basePath, meta, configHash)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	// add the config hash annotation to ensure pod rotation when an ES password or a CA are rotated
+	builder.WithAnnotations(map[string]string{cfgHashAnnotation: fmt.Sprint(configHash.Sum32())})
+	// inject all volumes
+	builder.WithVolumes(volumes...)
+
+	return builder, nil
+}
+
+// withBeatSidecars adds the Metricbeat and Filebeat sidecar containers to the Pod template builder, and returns the
+// volumes to inject in the Pod.
+func withBeatSidecars(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, kb kbv1.Kibana, basePath string, meta metadata.Metadata, configHash hash.Hash) ([]corev1.Volume, error) {
+	volumes := make([]corev1.Volume, 0)
 	if monitoring.IsMetricsDefined(&kb) {
 		b, err := Metricbeat(ctx, client, kb, basePath, meta)
 		if err != nil {
@@ -156,11 +247,5 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 		builder.WithContainers(filebeat)
 		configHash.Write(b.ConfigHash.Sum(nil))
 	}
-
-	// add the config hash annotation to ensure pod rotation when an ES password or a CA are rotated
-	builder.WithAnnotations(map[string]string{cfgHashAnnotation: fmt.Sprint(configHash.Sum32())})
-	// inject all volumes
-	builder.WithVolumes(volumes...)
-
-	return builder, nil
+	return volumes, nil
 }
@@ -0,0 +1,599 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.elastic.co/apm/module/apmhttp/v2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	toolsevents "k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	commonhttp "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	kblabel "github.com/elastic/cloud-on-k8s/v3/pkg/controller/kibana/label"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/kibana/network"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/maps"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/net"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/stringsutil"
+)
+
+const (
+	// StatusAPIPath is the HTTP path of the Kibana status API.
+	StatusAPIPath = "/api/status"
+	// StatusReqTimeout is the duration after which a request to the status API should be canceled.
+	StatusReqTimeout = 10 * time.Second
+	// parallelUpgradeRequeue is the interval at which the progress of a parallel upgrade is checked.
+	parallelUpgradeRequeue = 10 * time.Second
+)
+
+// parallelUpgradePhase is the current step of a parallel version upgrade.
+type parallelUpgradePhase int
+
+const (
+	// noParallelUpgrade means the Kibana Deployment is reconciled as usual.
+	noParallelUpgrade parallelUpgradePhase = iota
+	// startingParallelUpgrade means the new version is starting in the upgrade Deployment, while the prior version
+	// still serves the traffic.
+	startingParallelUpgrade
+	// switchingParallelUpgrade means the new version in the upgrade Deployment serves the traffic, while the Kibana
+	// Deployment is scaled down then upgraded.
+	switchingParallelUpgrade
+	// completingParallelUpgrade means the upgraded Kibana Deployment serves the traffic again, and the upgrade
+	// Deployment can be deleted.
+	completingParallelUpgrade
+)
+
+// ParallelUpgrade runs Kibana in the new version in a separate Deployment during version upgrades, and only switches
+// the traffic of the HTTP Service to it once the saved objects migrations are over. Before the switch, writes to the
+// saved objects indices of the prior version are blocked so that it remains read-only until it is scaled down.
+type ParallelUpgrade struct {
+	k8sClient  k8s.Client
+	recorder   toolsevents.EventRecorder
+	kb         kbv1.Kibana
+	dialer     net.Dialer   // optional custom dialer for the http client
+	httpClient *http.Client // custom http client, will be created if nil
+
+	phase        parallelUpgradePhase
+	priorVersion string
+	// current is the Kibana Deployment, running the prior version until the new version serves the traffic.
+	current appsv1.Deployment
+	// upgrade is the Deployment running the new version during the upgrade.
+	upgrade appsv1.Deployment
+}
+
+// newParallelUpgrade returns a ParallelUpgrade set to the current step of the upgrade, if any.
+func newParallelUpgrade(
+	ctx context.Context,
+	k8sClient k8s.Client,
+	recorder toolsevents.EventRecorder,
+	kb kbv1.Kibana,
+	dialer net.Dialer,
+) (*ParallelUpgrade, error) {
+	u := &ParallelUpgrade{k8sClient: k8sClient, recorder: recorder, kb: kb, dialer: dialer}
+	return u, u.detectPhase(ctx)
+}
+
+// detectPhase sets the current step of the upgrade from the existing Deployments.
+func (u *ParallelUpgrade) detectPhase(ctx context.Context) error {
+	err := u.k8sClient.Get(ctx, types.NamespacedName{Namespace: u.kb.Namespace, Name: kbv1.UpgradeDeployment(u.kb.Name)}, &u.upgrade)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	upgradeExists := err == nil
+
+	if !u.kb.IsParallelUpgrade() {
+		if upgradeExists {
+			// the strategy was changed during an upgrade
+			return u.deleteUpgradeResources(ctx)
+		}
+		return nil
+	}
+
+	err = u.k8sClient.Get(ctx, types.NamespacedName{Namespace: u.kb.Namespace, Name: kbv1.Deployment(u.kb.Name)}, &u.current)
+	if apierrors.IsNotFound(err) {
+		// first deployment, not an upgrade
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	priorVersion, exists := u.current.Spec.Template.Labels[kblabel.KibanaVersionLabelName]
+	if !exists || priorVersion == u.kb.Spec.Version {
+		if upgradeExists && u.upgrade.Spec.Template.Labels[kblabel.KibanaVersionLabelName] != u.kb.Spec.Version {
+			// the spec went back to the prior version during the upgrade, which may have already blocked its writes
+			if err := u.allowWrites(ctx); err != nil {
+				return err
+			}
+		}
+		switch {
+		case !upgradeExists:
+		case isDeploymentAvailable(u.current, u.kb.Spec.Version):
+			u.phase = completingParallelUpgrade
+		default:
+			u.phase = switchingParallelUpgrade
+		}
+		return nil
+	}
+
+	u.priorVersion = priorVersion
+	u.phase = startingParallelUpgrade
+	if !upgradeExists {
+		return nil
+	}
+	if isScaledDown(u.current) {
+		// the traffic was already switched to the new version
+		u.phase = switchingParallelUpgrade
+		return nil
+	}
+	if !isDeploymentAvailable(u.upgrade, u.kb.Spec.Version) {
+		return nil
+	}
+	available, err := u.isNewVersionAvailable(ctx)
+	if err != nil {
+		// the new version may still be running migrations, retry later
+		ulog.FromContext(ctx).V(1).Info("Kibana status API not available yet", "namespace", u.kb.Namespace, "kibana_name", u.kb.Name, "error", err.Error())
+		return nil
+	}
+	if available {
+		if err := u.blockPriorVersionWrites(ctx); err != nil {
+			return err
+		}
+		msg := fmt.Sprintf("Kibana %s is available, switching traffic from %s", u.kb.Spec.Version, u.priorVersion)
+		ulog.FromContext(ctx).Info(msg, "namespace", u.kb.Namespace, "kibana_name", u.kb.Name)
+		k8s.EmitEvent(u.recorder, &u.kb, corev1.EventTypeNormal, events.EventReasonUpgraded, events.EventActionVersionUpgrade, msg)
+		u.phase = switchingParallelUpgrade
+	}
+	return nil
+}
+
+// ServiceSelector returns the selector pinning the HTTP Service to a single version during the upgrade, or nil
+// if the default selector applies.
+func (u *ParallelUpgrade) ServiceSelector() map[string]string {
+	switch u.phase {
+	case startingParallelUpgrade:
+		return maps.Merge(u.kb.GetIdentityLabels(), map[string]string{kblabel.KibanaVersionLabelName: u.priorVersion})
+	case switchingParallelUpgrade:
+		return u.upgradeSelector()
+	default:
+		return nil
+	}
+}
+
+// ReconcileDeployments reconciles the expected Kibana Deployment according to the current step of the upgrade.
+// It returns the Kibana Deployment to compute the status from, and whether the upgrade is still in progress.
+func (u *ParallelUpgrade) ReconcileDeployments(
+	ctx context.Context,
+	expected appsv1.Deployment,
+	meta metadata.Metadata,
+) (appsv1.Deployment, bool, error) {
+	switch u.phase {
+	case startingParallelUpgrade:
+		// leave the prior version untouched until the new one is available
+		if _, err := deployment.Reconcile(ctx, u.k8sClient, u.expectedUpgradeDeployment(expected), &u.kb); err != nil {
+			return appsv1.Deployment{}, false, err
+		}
+		if _, err := common.ReconcileService(ctx, u.k8sClient, u.expectedUpgradeService(meta), &u.kb); err != nil {
+			return appsv1.Deployment{}, false, err
+		}
+		return u.current, true, nil
+	case switchingParallelUpgrade:
+		if u.priorVersion != "" {
+			// the prior version does not serve the traffic anymore, stop it before upgrading the Kibana Deployment
+			if err := u.scaleDownPriorVersion(ctx); err != nil {
+				return appsv1.Deployment{}, false, err
+			}
+			if u.current.Status.Replicas > 0 {
+				return u.upgrade, true, nil
+			}
+		}
+		if expected.Spec.Replicas == nil {
+			// replicas are managed by a HorizontalPodAutoscaler, which does not scale up a Deployment with no replicas
+			expected.Spec.Replicas = u.upgrade.Spec.Replicas
+		}
+		reconciled, err := deployment.Reconcile(ctx, u.k8sClient, expected, &u.kb)
+		return reconciled, true, err
+	case completingParallelUpgrade:
+		// the HTTP Service targets the Kibana Deployment again, the new version can be removed from the upgrade Deployment
+		reconciled, err := deployment.Reconcile(ctx, u.k8sClient, expected, &u.kb)
+		if err != nil {
+			return appsv1.Deployment{}, false, err
+		}
+		msg := fmt.Sprintf("Kibana upgraded to version %s", u.kb.Spec.Version)
+		ulog.FromContext(ctx).Info(msg, "namespace", u.kb.Namespace, "kibana_name", u.kb.Name)
+		k8s.EmitEvent(u.recorder, &u.kb, corev1.EventTypeNormal, events.EventReasonUpgraded, events.EventActionVersionUpgrade, msg)
+		return reconciled, false, u.deleteUpgradeResources(ctx)
+	default:
+		reconciled, err := deployment.Reconcile(ctx, u.k8sClient, expected, &u.kb)
+		return reconciled, false, err
+	}
+}
+
+// expectedUpgradeDeployment derives the Deployment running the new version from the expected Kibana Deployment.
+// Its Pods are not labeled with the name of Kibana, so that they are neither matched by the selector of the Kibana
+// Deployment nor counted in the status of Kibana, and are labeled with the upgrade label instead.
+func (u *ParallelUpgrade) expectedUpgradeDeployment(expected appsv1.Deployment) appsv1.Deployment {
+	upgrade := *expected.DeepCopy()
+	upgrade.Name = kbv1.UpgradeDeployment(u.kb.Name)
+	upgrade.Spec.Selector = &metav1.LabelSelector{MatchLabels: u.upgradeSelector()}
+	delete(upgrade.Spec.Template.Labels, kblabel.KibanaNameLabelName)
+	upgrade.Spec.Template.Labels = maps.Merge(upgrade.Spec.Template.Labels, u.upgradeSelector())
+	upgrade.Spec.Strategy = appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	if upgrade.Spec.Replicas == nil {
+		// replicas are managed by a HorizontalPodAutoscaler on the prior version, start with as many replicas
+		upgrade.Spec.Replicas = u.current.Spec.Replicas
+	}
+	return upgrade
+}
+
+// expectedUpgradeService returns the Service targeting the Pods of the upgrade Deployment.
+func (u *ParallelUpgrade) expectedUpgradeService(meta metadata.Metadata) *corev1.Service {
+	svc := corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kbv1.UpgradeHTTPService(u.kb.Name),
+			Namespace: u.kb.Namespace,
+		},
+	}
+	ports := []corev1.ServicePort{
+		{
+			Name:     u.kb.Spec.HTTP.Protocol(),
+			Protocol: corev1.ProtocolTCP,
+			Port:     network.HTTPPort,
+		},
+	}
+	return defaults.SetServiceDefaults(&svc, meta, u.upgradeSelector(), ports)
+}
+
+// upgradeSelector returns the selector of the Pods of the upgrade Deployment, which does not overlap with the
+// selector of the Kibana Deployment.
+func (u *ParallelUpgrade) upgradeSelector() map[string]string {
+	return map[string]string{
+		commonv1.TypeLabelName:         kblabel.Type,
+		kblabel.KibanaUpgradeLabelName: u.kb.Name,
+	}
+}
+
+// scaleDownPriorVersion scales the Kibana Deployment running the prior version down to zero replicas, leaving its
+// spec untouched otherwise.
+func (u *ParallelUpgrade) scaleDownPriorVersion(ctx context.Context) error {
+	if isScaledDown(u.current) {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Scaling down Kibana prior version", "namespace", u.kb.Namespace, "kibana_name", u.kb.Name, "version", u.priorVersion)
+	u.current.Spec.Replicas = ptr.To[int32](0)
+	return u.k8sClient.Update(ctx, &u.current)
+}
+
+// isScaledDown returns true if the given Deployment is expected to have no replicas.
+func isScaledDown(d appsv1.Deployment) bool {
+	return d.Spec.Replicas != nil && *d.Spec.Replicas == 0
+}
+
+// deleteUpgradeResources deletes the Deployment and the Service of the new version.
+func (u *ParallelUpgrade) deleteUpgradeResources(ctx context.Context) error {
+	for _, obj := range []client.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: u.kb.Namespace, Name: kbv1.UpgradeDeployment(u.kb.Name)}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: u.kb.Namespace, Name: kbv1.UpgradeHTTPService(u.kb.Name)}},
+	} {
+		if err := u.k8sClient.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDeploymentAvailable returns true if all the Pods of the given Deployment run the given version and are available.
+func isDeploymentAvailable(d appsv1.Deployment, version string) bool {
+	replicas := int32(1)
+	if d.Spec.Replicas != nil {
+		replicas = *d.Spec.Replicas
+	}
+	return d.Spec.Template.Labels[kblabel.KibanaVersionLabelName] == version &&
+		d.Status.ObservedGeneration >= d.Generation &&
+		d.Status.Replicas == replicas &&
+		d.Status.UpdatedReplicas == replicas &&
+		d.Status.AvailableReplicas == replicas
+}
+
+// kibanaStatus is the subset of the status API response used to check the availability of Kibana.
+type kibanaStatus struct {
+	Version struct {
+		Number string `json:"number"`
+	} `json:"version"`
+	Status struct {
+		Overall struct {
+			// Level is reported by Kibana 8.0.0+.
+			Level string `json:"level"`
+			// State is reported by prior versions.
+			State string `json:"state"`
+		} `json:"overall"`
+	} `json:"status"`
+}
+
+// isAvailable returns true if Kibana runs the given version and reports itself available.
+func (s kibanaStatus) isAvailable(expected version.Version) bool {
+	actual, err := version.Parse(s.Version.Number)
+	if err != nil {
+		return false
+	}
+	return actual.Major == expected.Major && actual.Minor == expected.Minor && actual.Patch == expected.Patch &&
+		(s.Status.Overall.Level == "available" || s.Status.Overall.State == "green")
+}
+
+// isNewVersionAvailable returns true if the status API of the new version reports Kibana available, which happens
+// once the saved objects migrations are over. Without Elasticsearch credentials, the availability of the
+// Deployment is enough.
+func (u *ParallelUpgrade) isNewVersionAvailable(ctx context.Context) (bool, error) {
+	esAssocConf, err := u.kb.EsAssociation().AssociationConf()
+	if err != nil {
+		return false, err
+	}
+	if !esAssocConf.AuthIsConfigured() {
+		return true, nil
+	}
+	expectedVersion, err := version.Parse(u.kb.Spec.Version)
+	if err != nil {
+		return false, err
+	}
+
+	httpClient := u.httpClient
+	if httpClient == nil {
+		// build an HTTP client to reach the Kibana upgrade service
+		var tlsCerts []*x509.Certificate
+		if u.kb.Spec.HTTP.TLS.Enabled() {
+			tlsCerts, err = u.retrieveTLSCerts(ctx)
+			if err != nil {
+				return false, err
+			}
+		}
+		httpClient = u.newHTTPClient(tlsCerts, "external.kibana")
+		defer httpClient.CloseIdleConnections()
+	}
+
+	request, err := u.statusRequest(ctx)
+	if err != nil {
+		return false, err
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, StatusReqTimeout)
+	defer cancel()
+	resp, err := httpClient.Do(request.WithContext(timeoutCtx))
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusServiceUnavailable {
+		return false, fmt.Errorf("invalid status API response (status code %d): %s", resp.StatusCode, body)
+	}
+	var status kibanaStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return false, err
+	}
+	return status.isAvailable(expectedVersion), nil
+}
+
+// blockPriorVersionWrites puts the prior version in read-only mode before the traffic is switched to the new version,
+// by blocking writes to the saved objects indices behind its version aliases. Indices the new version still uses,
+// because its migrations did not need to reindex them, are left writable.
+func (u *ParallelUpgrade) blockPriorVersionWrites(ctx context.Context) error {
+	esAssocConf, err := u.kb.EsAssociation().AssociationConf()
+	if err != nil {
+		return err
+	}
+	if !esAssocConf.AuthIsConfigured() {
+		return nil
+	}
+	priorIndices, err := u.savedObjectsIndices(ctx, u.priorVersion)
+	if err != nil {
+		return err
+	}
+	newIndices, err := u.savedObjectsIndices(ctx, u.kb.Spec.Version)
+	if err != nil {
+		return err
+	}
+	indices := sets.List(priorIndices.Difference(newIndices))
+	if len(indices) == 0 {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Blocking writes to the saved objects indices of Kibana prior version",
+		"namespace", u.kb.Namespace, "kibana_name", u.kb.Name, "version", u.priorVersion, "indices", indices)
+	_, err = u.esRequest(ctx, http.MethodPut, "/"+strings.Join(indices, ",")+"/_block/write", nil)
+	return err
+}
+
+// allowWrites removes the write block from the saved objects indices of the version in the spec, set by
+// blockPriorVersionWrites if the spec goes back to that version after the traffic was switched to the new one.
+func (u *ParallelUpgrade) allowWrites(ctx context.Context) error {
+	esAssocConf, err := u.kb.EsAssociation().AssociationConf()
+	if err != nil {
+		return err
+	}
+	if !esAssocConf.AuthIsConfigured() {
+		return nil
+	}
+	indices, err := u.savedObjectsIndices(ctx, u.kb.Spec.Version)
+	if err != nil {
+		return err
+	}
+	if indices.Len() == 0 {
+		return nil
+	}
+	ulog.FromContext(ctx).Info("Allowing writes to the saved objects indices of Kibana after a rollback of the upgrade",
+		"namespace", u.kb.Namespace, "kibana_name", u.kb.Name, "version", u.kb.Spec.Version, "indices", sets.List(indices))
+	_, err = u.esRequest(ctx, http.MethodPut, "/"+strings.Join(sets.List(indices), ",")+"/_settings", []byte(`{"index.blocks.write":false}`))
+	return err
+}
+
+// savedObjectsIndices returns the indices behind the aliases Kibana creates for the saved objects of the given version.
+func (u *ParallelUpgrade) savedObjectsIndices(ctx context.Context, ver string) (sets.Set[string], error) {
+	body, err := u.esRequest(ctx, http.MethodGet, "/_alias/.kibana*_"+ver, nil)
+	if err != nil {
+		return nil, err
+	}
+	var aliases map[string]any
+	if err := json.Unmarshal(body, &aliases); err != nil {
+		return nil, err
+	}
+	return sets.KeySet(aliases), nil
+}
+
+// esRequest sends a request to Elasticsearch with an optional JSON body, authenticated with the Elasticsearch
+// credentials of Kibana, and returns the response body.
+func (u *ParallelUpgrade) esRequest(ctx context.Context, method, path string, reqBody []byte) ([]byte, error) {
+	esAssocConf, err := u.kb.EsAssociation().AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	credentials, err := association.ElasticsearchAuthSettings(ctx, u.k8sClient, u.kb.EsAssociation())
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := u.httpClient
+	if httpClient == nil {
+		var caCerts []*x509.Certificate
+		if esAssocConf.GetCACertProvided() {
+			caCerts, err = u.retrieveCACerts(ctx, esAssocConf.GetCASecretName())
+			if err != nil {
+				return nil, err
+			}
+		}
+		httpClient = u.newHTTPClient(caCerts, "external.elasticsearch")
+		defer httpClient.CloseIdleConnections()
+	}
+
+	req, err := http.NewRequest(method, stringsutil.Concat(esAssocConf.GetURL(), path), bytes.NewReader(reqBody)) //nolint:noctx
+	if err != nil {
+		return nil, err
+	}
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if credentials.HasServiceAccountToken() {
+		req.Header.Set("Authorization", "Bearer "+credentials.ServiceAccountToken)
+	} else {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, StatusReqTimeout)
+	defer cancel()
+	resp, err := httpClient.Do(req.WithContext(timeoutCtx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("invalid response to %s %s (status code %d): %s", method, path, resp.StatusCode, body)
+	}
+	return body, nil
+}
+
+// newHTTPClient returns an HTTP client trusting the given certificates.
+func (u *ParallelUpgrade) newHTTPClient(caCerts []*x509.Certificate, spanType string) *http.Client {
+	return apmhttp.WrapClient(
+		commonhttp.Client(u.dialer, caCerts, 0),
+		apmhttp.WithClientRequestName(tracing.RequestName),
+		apmhttp.WithClientSpanType(spanType),
+	)
+}
+
+// serviceURL builds the URL of the Kibana upgrade service.
+func (u *ParallelUpgrade) serviceURL() (string, error) {
+	basePath, err := GetKibanaBasePath(u.kb)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s://%s.%s.svc:%d%s",
+		u.kb.Spec.HTTP.Protocol(), kbv1.UpgradeHTTPService(u.kb.Name), u.kb.Namespace, network.HTTPPort, basePath), nil
+}
+
+// statusRequest builds the HTTP request to retrieve the status of the new version, authenticated with the
+// Elasticsearch credentials of Kibana.
+func (u *ParallelUpgrade) statusRequest(ctx context.Context) (*http.Request, error) {
+	credentials, err := association.ElasticsearchAuthSettings(ctx, u.k8sClient, u.kb.EsAssociation())
+	if err != nil {
+		return nil, err
+	}
+	serviceURL, err := u.serviceURL()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, stringsutil.Concat(serviceURL, StatusAPIPath), nil) //nolint:noctx
+	if err != nil {
+		return nil, err
+	}
+	if credentials.HasServiceAccountToken() {
+		req.Header.Set("Authorization", "Bearer "+credentials.ServiceAccountToken)
+	} else {
+		req.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	return req, nil
+}
+
+// retrieveCACerts returns the certificates of the certificate authority stored in the given association Secret.
+func (u *ParallelUpgrade) retrieveCACerts(ctx context.Context, secretName string) ([]*x509.Certificate, error) {
+	var caSecret corev1.Secret
+	if err := u.k8sClient.Get(ctx, types.NamespacedName{Namespace: u.kb.Namespace, Name: secretName}, &caSecret); err != nil {
+		return nil, err
+	}
+	caData, exists := caSecret.Data[certificates.CAFileName]
+	if !exists {
+		return nil, fmt.Errorf("no %s found in secret %s", certificates.CAFileName, caSecret.Name)
+	}
+	return certificates.ParsePEMCerts(caData)
+}
+
+// retrieveTLSCerts returns the TLS certs used by Kibana.
+func (u *ParallelUpgrade) retrieveTLSCerts(ctx context.Context) ([]*x509.Certificate, error) {
+	var certsSecret corev1.Secret
+	nsn := types.NamespacedName{
+		Namespace: u.kb.Namespace,
+		Name:      certificates.InternalCertsSecretName(kbv1.KBNamer, u.kb.Name),
+	}
+	if err := u.k8sClient.Get(ctx, nsn, &certsSecret); err != nil {
+		return nil, err
+	}
+	certData, exists := certsSecret.Data[certificates.CertFileName]
+	if !exists {
+		return nil, fmt.Errorf("no %s found in secret %s", certificates.CertFileName, certsSecret.Name)
+	}
+	return certificates.ParsePEMCerts(certData)
+}
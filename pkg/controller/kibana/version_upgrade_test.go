@@ -0,0 +1,381 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package kibana
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolsevents "k8s.io/client-go/tools/events"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	kblabel "github.com/elastic/cloud-on-k8s/v3/pkg/controller/kibana/label"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+// fakeUpgradeRoundTrip mocks HTTP calls to the Kibana status API and to the Elasticsearch alias and index block APIs
+type fakeUpgradeRoundTrip struct {
+	statusBody string
+	// aliasBodies are the responses of the alias API indexed by Kibana version
+	aliasBodies map[string]string
+	requests    *[]string
+}
+
+func (f fakeUpgradeRoundTrip) RoundTrip(req *http.Request) (*http.Response, error) {
+	*f.requests = append(*f.requests, req.Method+" "+req.URL.Path)
+	body := `{"acknowledged":true}`
+	switch {
+	case strings.HasSuffix(req.URL.Path, StatusAPIPath):
+		body = f.statusBody
+	case strings.HasPrefix(req.URL.Path, "/_alias/.kibana*_"):
+		body = f.aliasBodies[strings.TrimPrefix(req.URL.Path, "/_alias/.kibana*_")]
+		if body == "" {
+			body = "{}"
+		}
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil
+}
+
+func upgradeTestKibana(strategy kbv1.UpgradeStrategyType) kbv1.Kibana {
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb"},
+		Spec: kbv1.KibanaSpec{
+			Version:         "8.1.0",
+			Count:           2,
+			UpgradeStrategy: kbv1.UpgradeStrategy{Type: strategy},
+		},
+	}
+	kb.EsAssociation().SetAssociationConf(&commonv1.AssociationConf{
+		AuthSecretName: "kb-user",
+		AuthSecretKey:  "ns-kb-kibana-user",
+		URL:            "https://es-es-http.ns.svc:9200",
+		Version:        "8.1.0",
+	})
+	return kb
+}
+
+var upgradeTestSelector = map[string]string{
+	commonv1.TypeLabelName:         "kibana",
+	kblabel.KibanaUpgradeLabelName: "kb",
+}
+
+func upgradeTestDeployment(name, ver string, available bool) *appsv1.Deployment {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](2),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{commonv1.TypeLabelName: "kibana", kblabel.KibanaNameLabelName: "kb"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{
+					commonv1.TypeLabelName:         "kibana",
+					kblabel.KibanaNameLabelName:    "kb",
+					kblabel.KibanaVersionLabelName: ver,
+				}},
+			},
+		},
+	}
+	if name == "kb-kb-upgrade" {
+		d.Spec.Selector.MatchLabels = upgradeTestSelector
+		delete(d.Spec.Template.Labels, kblabel.KibanaNameLabelName)
+		d.Spec.Template.Labels[kblabel.KibanaUpgradeLabelName] = "kb"
+		d.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+	}
+	if available {
+		d.Status = appsv1.DeploymentStatus{Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	}
+	return d
+}
+
+func scaledDown(d *appsv1.Deployment) *appsv1.Deployment {
+	d.Spec.Replicas = ptr.To[int32](0)
+	d.Status = appsv1.DeploymentStatus{}
+	return d
+}
+
+func TestParallelUpgrade(t *testing.T) {
+	userSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-user"},
+		Data:       map[string][]byte{"ns-kb-kibana-user": []byte("password")},
+	}
+	upgradeService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-kb-upgrade-http"},
+		Spec:       corev1.ServiceSpec{Selector: upgradeTestSelector},
+	}
+	availableStatus := `{"version":{"number":"8.1.0"},"status":{"overall":{"level":"available"}}}`
+	aliasBodies := map[string]string{
+		"8.0.0": `{".kibana_8.0.0_001":{"aliases":{".kibana_8.0.0":{}}},".kibana_task_manager_8.0.0_001":{"aliases":{".kibana_task_manager_8.0.0":{}}}}`,
+		// the task manager index did not need to be reindexed
+		"8.1.0": `{".kibana_8.1.0_001":{"aliases":{".kibana_8.1.0":{}}},".kibana_task_manager_8.0.0_001":{"aliases":{".kibana_task_manager_8.1.0":{}}}}`,
+	}
+
+	tests := []struct {
+		name              string
+		strategy          kbv1.UpgradeStrategyType
+		objects           []client.Object
+		statusBody        string
+		autoscaled        bool
+		wantSelector      map[string]string
+		wantInProgress    bool
+		wantRequests      []string
+		wantCurrentVer    string
+		wantCurrentCount  int32
+		wantUpgradeExists bool
+	}{
+		{
+			name:     "leftovers are deleted with the Recreate strategy",
+			strategy: kbv1.RecreateUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.0.0", true),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+				upgradeService,
+			},
+			wantCurrentVer:   "8.1.0",
+			wantCurrentCount: 2,
+		},
+		{
+			name:             "first deployment",
+			strategy:         kbv1.ParallelUpgradeStrategyType,
+			wantCurrentVer:   "8.1.0",
+			wantCurrentCount: 2,
+		},
+		{
+			name:     "no version change",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.1.0", true),
+			},
+			wantCurrentVer:   "8.1.0",
+			wantCurrentCount: 2,
+		},
+		{
+			name:     "start the new version next to the prior one",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.0.0", true),
+			},
+			wantSelector:      map[string]string{kblabel.KibanaNameLabelName: "kb", commonv1.TypeLabelName: "kibana", kblabel.KibanaVersionLabelName: "8.0.0"},
+			wantInProgress:    true,
+			wantCurrentVer:    "8.0.0",
+			wantCurrentCount:  2,
+			wantUpgradeExists: true,
+		},
+		{
+			name:     "wait for the migrations of the new version",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.0.0", true),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+			},
+			statusBody:        `{"version":{"number":"8.1.0"},"status":{"overall":{"level":"unavailable"}}}`,
+			wantSelector:      map[string]string{kblabel.KibanaNameLabelName: "kb", commonv1.TypeLabelName: "kibana", kblabel.KibanaVersionLabelName: "8.0.0"},
+			wantInProgress:    true,
+			wantRequests:      []string{"GET /api/status"},
+			wantCurrentVer:    "8.0.0",
+			wantCurrentCount:  2,
+			wantUpgradeExists: true,
+		},
+		{
+			name:     "block writes of the prior version, switch the traffic to the new version and scale down the prior one",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.0.0", true),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+				upgradeService,
+			},
+			statusBody:     availableStatus,
+			wantSelector:   upgradeTestSelector,
+			wantInProgress: true,
+			wantRequests: []string{
+				"GET /api/status",
+				"GET /_alias/.kibana*_8.0.0",
+				"GET /_alias/.kibana*_8.1.0",
+				"PUT /.kibana_8.0.0_001/_block/write",
+			},
+			wantCurrentVer:    "8.0.0",
+			wantCurrentCount:  0,
+			wantUpgradeExists: true,
+		},
+		{
+			name:     "upgrade the Kibana Deployment once the prior version is scaled down",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				scaledDown(upgradeTestDeployment("kb-kb", "8.0.0", false)),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+				upgradeService,
+			},
+			wantSelector:      upgradeTestSelector,
+			wantInProgress:    true,
+			wantCurrentVer:    "8.1.0",
+			wantCurrentCount:  2,
+			wantUpgradeExists: true,
+		},
+		{
+			name:     "scale up the autoscaled Kibana Deployment once the prior version is scaled down",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				scaledDown(upgradeTestDeployment("kb-kb", "8.0.0", false)),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+				upgradeService,
+			},
+			autoscaled:        true,
+			wantSelector:      upgradeTestSelector,
+			wantInProgress:    true,
+			wantCurrentVer:    "8.1.0",
+			wantCurrentCount:  2,
+			wantUpgradeExists: true,
+		},
+		{
+			name:     "wait for the Kibana Deployment to be upgraded",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.1.0", false),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+				upgradeService,
+			},
+			wantSelector:      upgradeTestSelector,
+			wantInProgress:    true,
+			wantCurrentVer:    "8.1.0",
+			wantCurrentCount:  2,
+			wantUpgradeExists: true,
+		},
+		{
+			name:     "allow writes to the indices of the prior version when the spec goes back to it",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				scaledDown(upgradeTestDeployment("kb-kb", "8.1.0", false)),
+				upgradeTestDeployment("kb-kb-upgrade", "8.2.0", true),
+				upgradeService,
+			},
+			wantRequests: []string{
+				"GET /_alias/.kibana*_8.1.0",
+				"PUT /.kibana_8.1.0_001,.kibana_task_manager_8.0.0_001/_settings",
+			},
+			wantCurrentVer:   "8.1.0",
+			wantCurrentCount: 2,
+		},
+		{
+			name:     "restore the default selector and delete the upgrade resources once the Kibana Deployment is upgraded",
+			strategy: kbv1.ParallelUpgradeStrategyType,
+			objects: []client.Object{
+				upgradeTestDeployment("kb-kb", "8.1.0", true),
+				upgradeTestDeployment("kb-kb-upgrade", "8.1.0", true),
+				upgradeService,
+			},
+			wantCurrentVer:   "8.1.0",
+			wantCurrentCount: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := k8s.NewFakeClient(append(tt.objects, userSecret)...)
+			var requests []string
+			u := &ParallelUpgrade{
+				k8sClient:  c,
+				recorder:   toolsevents.NewFakeRecorder(10),
+				kb:         upgradeTestKibana(tt.strategy),
+				httpClient: &http.Client{Transport: fakeUpgradeRoundTrip{statusBody: tt.statusBody, aliasBodies: aliasBodies, requests: &requests}},
+			}
+			require.NoError(t, u.detectPhase(ctx))
+			assert.Equal(t, tt.wantSelector, u.ServiceSelector())
+			assert.Equal(t, tt.wantRequests, requests)
+
+			expected := *upgradeTestDeployment("kb-kb", "8.1.0", false)
+			if tt.autoscaled {
+				expected.Spec.Replicas = nil
+			}
+			_, inProgress, err := u.ReconcileDeployments(ctx, expected, metadata.Metadata{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantInProgress, inProgress)
+
+			var current appsv1.Deployment
+			require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "kb-kb"}, &current))
+			assert.Equal(t, tt.wantCurrentVer, current.Spec.Template.Labels[kblabel.KibanaVersionLabelName])
+			assert.Equal(t, tt.wantCurrentCount, *current.Spec.Replicas)
+
+			var upgrade appsv1.Deployment
+			err = c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "kb-kb-upgrade"}, &upgrade)
+			var svc corev1.Service
+			svcErr := c.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "kb-kb-upgrade-http"}, &svc)
+			if !tt.wantUpgradeExists {
+				assert.True(t, apierrors.IsNotFound(err))
+				assert.True(t, apierrors.IsNotFound(svcErr))
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, svcErr)
+			assert.Equal(t, "8.1.0", upgrade.Spec.Template.Labels[kblabel.KibanaVersionLabelName])
+			assert.Equal(t, "kb", upgrade.Spec.Template.Labels[kblabel.KibanaUpgradeLabelName])
+			assert.NotContains(t, upgrade.Spec.Template.Labels, kblabel.KibanaNameLabelName)
+			assert.Equal(t, appsv1.RecreateDeploymentStrategyType, upgrade.Spec.Strategy.Type)
+			assert.Equal(t, upgrade.Spec.Selector.MatchLabels, svc.Spec.Selector)
+		})
+	}
+}
+
+func TestParallelUpgrade_statusRequest(t *testing.T) {
+	kb := upgradeTestKibana(kbv1.ParallelUpgradeStrategyType)
+	kb.Spec.Config = &commonv1.Config{Data: map[string]any{"server.basePath": "/kibana", "server.rewriteBasePath": true}}
+	c := k8s.NewFakeClient(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-user"},
+		Data:       map[string][]byte{"ns-kb-kibana-user": []byte("password")},
+	})
+	u := &ParallelUpgrade{k8sClient: c, kb: kb}
+
+	req, err := u.statusRequest(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "https://kb-kb-upgrade-http.ns.svc:5601/kibana/api/status", req.URL.String())
+	username, password, ok := req.BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "ns-kb-kibana-user", username)
+	assert.Equal(t, "password", password)
+}
+
+func Test_kibanaStatus_isAvailable(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "8.x available", body: `{"version":{"number":"8.1.0"},"status":{"overall":{"level":"available"}}}`, want: true},
+		{name: "8.x degraded", body: `{"version":{"number":"8.1.0"},"status":{"overall":{"level":"degraded"}}}`, want: false},
+		{name: "7.x green", body: `{"version":{"number":"8.1.0"},"status":{"overall":{"state":"green"}}}`, want: true},
+		{name: "7.x yellow", body: `{"version":{"number":"8.1.0"},"status":{"overall":{"state":"yellow"}}}`, want: false},
+		{name: "prior version", body: `{"version":{"number":"8.0.0"},"status":{"overall":{"level":"available"}}}`, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			u := &ParallelUpgrade{
+				k8sClient: k8s.NewFakeClient(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "kb-user"},
+					Data:       map[string][]byte{"ns-kb-kibana-user": []byte("password")},
+				}),
+				kb:         upgradeTestKibana(kbv1.ParallelUpgradeStrategyType),
+				httpClient: &http.Client{Transport: fakeUpgradeRoundTrip{statusBody: tt.body, requests: &requests}},
+			}
+			available, err := u.isNewVersionAvailable(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, available)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esav1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoscaling/v1alpha1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/stackconfigpolicy/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	esclient "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/stackconfigpolicy"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/metrics"
+)
+
+// ParseExpiryWarnings parses the given durations before license expiry at which users must be warned.
+func ParseExpiryWarnings(values []string) ([]time.Duration, error) {
+	warnings := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		warning, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid license expiry warning %q: %w", value, err)
+		}
+		if warning <= 0 {
+			return nil, fmt.Errorf("license expiry warning %q must be positive", value)
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings, nil
+}
+
+// appliedLicense describes the license applied to an Elasticsearch cluster.
+type appliedLicense struct {
+	// parent is the UID of the enterprise license the cluster license comes from.
+	parent string
+	// licenseType is the type of the cluster license.
+	licenseType string
+	// expiry is the time at which the cluster license expires.
+	expiry time.Time
+}
+
+// isTrial returns true if the applied license is a trial license.
+func (l appliedLicense) isTrial() bool {
+	return l.licenseType == string(esclient.ElasticsearchLicenseTypeTrial)
+}
+
+// expiryWarning returns the smallest warning threshold that is greater than the time left before the given expiry,
+// or false if the expiry is not within any of the thresholds.
+func expiryWarning(now, expiry time.Time, warnings []time.Duration) (time.Duration, bool) {
+	remaining := expiry.Sub(now)
+	var threshold time.Duration
+	found := false
+	for _, warning := range warnings {
+		if remaining <= warning && (!found || warning < threshold) {
+			threshold = warning
+			found = true
+		}
+	}
+	return threshold, found
+}
+
+// nextExpiryWarning returns the duration until the next warning threshold is crossed, or false if all the thresholds
+// have already been crossed.
+func nextExpiryWarning(now, expiry time.Time, warnings []time.Duration) (time.Duration, bool) {
+	var next time.Duration
+	found := false
+	for _, warning := range warnings {
+		untilWarning := expiry.Add(-warning).Sub(now)
+		if untilWarning > 0 && (!found || untilWarning < next) {
+			next = untilWarning
+			found = true
+		}
+	}
+	return next, found
+}
+
+// degradedFeatures returns a description of the features and resources relying on a license which are degraded if
+// the given Elasticsearch cluster falls back to a basic license.
+func degradedFeatures(ctx context.Context, c k8s.Client, es esv1.Elasticsearch, operatorNamespace string) ([]string, error) {
+	var features []string
+
+	if v, err := version.Parse(es.Spec.Version); err == nil {
+		var mlNodes int32
+		for _, nodeSet := range es.Spec.NodeSets {
+			var cfg esv1.ElasticsearchSettings
+			if err := esv1.UnpackConfig(nodeSet.Config, v, &cfg); err != nil {
+				continue
+			}
+			// all nodes have the ml role by default, only report the ones explicitly dedicated to machine learning
+			if cfg.Node != nil && cfg.Node.Roles != nil && cfg.Node.HasRole(esv1.MLRole) {
+				mlNodes += nodeSet.Count
+			}
+		}
+		if mlNodes > 0 {
+			features = append(features, fmt.Sprintf("machine learning (%d nodes)", mlNodes))
+		}
+	}
+
+	if _, withAPIKeys := es.RemoteClustersCount(); withAPIKeys > 0 {
+		features = append(features, fmt.Sprintf("remote clusters with API keys (%d)", withAPIKeys))
+	}
+
+	var autoscalers esav1alpha1.ElasticsearchAutoscalerList
+	if err := c.List(ctx, &autoscalers, client.InNamespace(es.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, autoscaler := range autoscalers.Items {
+		if autoscaler.Spec.ElasticsearchRef.Name == es.Name {
+			features = append(features, fmt.Sprintf("autoscaling (ElasticsearchAutoscaler %s)", autoscaler.Name))
+		}
+	}
+
+	var policies policyv1alpha1.StackConfigPolicyList
+	if err := c.List(ctx, &policies); err != nil {
+		return nil, err
+	}
+	for _, policy := range policies.Items {
+		matches, err := stackconfigpolicy.DoesPolicyMatchObject(&policy, &es, operatorNamespace)
+		if err != nil || !matches {
+			continue
+		}
+		features = append(features, fmt.Sprintf("StackConfigPolicy %s/%s", policy.Namespace, policy.Name))
+	}
+
+	return features, nil
+}
+
+// expiryMessage describes the expiry of the given license and the features degraded on expiry.
+func expiryMessage(applied appliedLicense, threshold time.Duration, features []string) string {
+	kind := "Enterprise license"
+	if applied.isTrial() {
+		kind = "Trial license"
+	}
+	msg := fmt.Sprintf("%s %s (%s) expires on %s, in less than %s",
+		kind, applied.parent, applied.licenseType, applied.expiry.UTC().Format(time.RFC3339), threshold)
+	if len(features) == 0 {
+		return msg + ", the cluster will then fall back to a basic license"
+	}
+	return fmt.Sprintf("%s, the cluster will then fall back to a basic license, degrading: %s", msg, strings.Join(features, ", "))
+}
+
+// reportLicenseExpiry reports the expiry of the license applied to the given cluster through the LicenseExpiring
+// condition of the cluster, a warning event each time a warning threshold is crossed, and the license expiry metric.
+// It returns the duration until the next warning threshold is crossed, if any.
+func (r *ReconcileLicenses) reportLicenseExpiry(ctx context.Context, es esv1.Elasticsearch, applied *appliedLicense) (time.Duration, bool, error) {
+	log := ulog.FromContext(ctx)
+	now := time.Now()
+
+	metrics.LicenseExpiryGauge.DeletePartialMatch(map[string]string{
+		metrics.NamespaceLabel: es.Namespace,
+		metrics.NameLabel:      es.Name,
+	})
+	if applied == nil {
+		// only report the absence of license on clusters which used to have one
+		if es.Status.Conditions.Index(esv1.LicenseExpiring) < 0 {
+			return 0, false, nil
+		}
+		return 0, false, r.updateLicenseExpiringCondition(ctx, es, corev1.ConditionFalse, "No enterprise license applied, the cluster runs with a basic license")
+	}
+	if applied.expiry.IsZero() {
+		msg := fmt.Sprintf("License %s (%s) applied", applied.parent, applied.licenseType)
+		return 0, false, r.updateLicenseExpiringCondition(ctx, es, corev1.ConditionFalse, msg)
+	}
+	metrics.LicenseExpiryGauge.WithLabelValues(es.Namespace, es.Name, applied.licenseType).Set(float64(applied.expiry.Unix()))
+
+	next, hasNext := nextExpiryWarning(now, applied.expiry, r.LicenseExpiryWarnings)
+	threshold, expiring := expiryWarning(now, applied.expiry, r.LicenseExpiryWarnings)
+	if !expiring {
+		msg := fmt.Sprintf("License %s (%s) expires on %s", applied.parent, applied.licenseType, applied.expiry.UTC().Format(time.RFC3339))
+		return next, hasNext, r.updateLicenseExpiringCondition(ctx, es, corev1.ConditionFalse, msg)
+	}
+
+	features, err := degradedFeatures(ctx, r.Client, es, r.OperatorNamespace)
+	if err != nil {
+		return 0, false, err
+	}
+	msg := expiryMessage(*applied, threshold, features)
+	if current := es.Status.Conditions.Index(esv1.LicenseExpiring); current < 0 || es.Status.Conditions[current].Message != msg {
+		// the message only changes when a new threshold is crossed or the degraded features change
+		log.Info("License expiring", "namespace", es.Namespace, "es_name", es.Name, "eck_license", applied.parent, "expiry", applied.expiry)
+		k8s.EmitEvent(r.recorder, &es, corev1.EventTypeWarning, events.EventReasonLicenseExpiring, events.EventActionLicenseCheck, msg)
+	}
+	return next, hasNext, r.updateLicenseExpiringCondition(ctx, es, corev1.ConditionTrue, msg)
+}
+
+// reportLicenseExpired warns that the license previously applied to the given cluster is not available anymore.
+func (r *ReconcileLicenses) reportLicenseExpired(ctx context.Context, es esv1.Elasticsearch, previous esclient.License) error {
+	if previous.ExpiryTime().IsZero() || previous.ExpiryTime().After(time.Now()) {
+		// the license was removed, not expired
+		return nil
+	}
+	features, err := degradedFeatures(ctx, r.Client, es, r.OperatorNamespace)
+	if err != nil {
+		return err
+	}
+	msg := fmt.Sprintf("License %s (%s) expired on %s, the cluster falls back to a basic license",
+		previous.UID, previous.Type, previous.ExpiryTime().UTC().Format(time.RFC3339))
+	if len(features) > 0 {
+		msg = fmt.Sprintf("%s, degrading: %s", msg, strings.Join(features, ", "))
+	}
+	k8s.EmitEvent(r.recorder, &es, corev1.EventTypeWarning, events.EventReasonLicenseExpired, events.EventActionLicenseCheck, msg)
+	return nil
+}
+
+// updateLicenseExpiringCondition sets the LicenseExpiring condition of the given cluster, if it changed.
+func (r *ReconcileLicenses) updateLicenseExpiringCondition(ctx context.Context, es esv1.Elasticsearch, status corev1.ConditionStatus, msg string) error {
+	conditions := es.Status.Conditions.MergeWith(commonv1alpha1.Condition{
+		Type:               esv1.LicenseExpiring,
+		Status:             status,
+		LastTransitionTime: metav1.Now(),
+		Message:            msg,
+	})
+	if slices.EqualFunc(conditions, es.Status.Conditions, func(a, b commonv1alpha1.Condition) bool {
+		return a.Type == b.Type && a.Status == b.Status && a.Message == b.Message
+	}) {
+		return nil
+	}
+	es.Status.Conditions = conditions
+	return common.UpdateStatus(ctx, r.Client, &es)
+}
+
+// currentClusterLicense returns the license currently applied to the given cluster, if any.
+func currentClusterLicense(ctx context.Context, c k8s.Client, es esv1.Elasticsearch) (esclient.License, bool) {
+	var secret corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: es.Namespace, Name: esv1.LicenseSecretName(es.Name)}, &secret); err != nil {
+		return esclient.License{}, false
+	}
+	var current esclient.License
+	if err := json.Unmarshal(secret.Data[license.FileName], &current); err != nil {
+		return esclient.License{}, false
+	}
+	return current, true
+}
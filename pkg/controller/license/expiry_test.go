@@ -0,0 +1,246 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolsevents "k8s.io/client-go/tools/events"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	esav1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/autoscaling/v1alpha1"
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	commonv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1alpha1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	policyv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/stackconfigpolicy/v1alpha1"
+	commonlicense "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/chrono"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+var testExpiryWarnings = []time.Duration{30 * 24 * time.Hour, 7 * 24 * time.Hour, 24 * time.Hour}
+
+func TestParseExpiryWarnings(t *testing.T) {
+	warnings, err := ParseExpiryWarnings([]string{"720h", " 24h", ""})
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{720 * time.Hour, 24 * time.Hour}, warnings)
+
+	warnings, err = ParseExpiryWarnings([]string{""})
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+
+	_, err = ParseExpiryWarnings([]string{"7d"})
+	require.ErrorContains(t, err, `invalid license expiry warning "7d"`)
+	_, err = ParseExpiryWarnings([]string{"-1h"})
+	require.ErrorContains(t, err, `license expiry warning "-1h" must be positive`)
+}
+
+func Test_expiryWarning(t *testing.T) {
+	now := chrono.MustParseTime("2026-10-01")
+	tests := []struct {
+		name          string
+		expiry        time.Time
+		wantThreshold time.Duration
+		wantExpiring  bool
+		wantNext      time.Duration
+		wantHasNext   bool
+	}{
+		{
+			name:        "expiry beyond all thresholds",
+			expiry:      chrono.MustParseTime("2026-12-01"),
+			wantNext:    31 * 24 * time.Hour,
+			wantHasNext: true,
+		},
+		{
+			name:          "within the largest threshold",
+			expiry:        chrono.MustParseTime("2026-10-20"),
+			wantThreshold: 30 * 24 * time.Hour,
+			wantExpiring:  true,
+			wantNext:      12 * 24 * time.Hour,
+			wantHasNext:   true,
+		},
+		{
+			name:          "within the smallest threshold",
+			expiry:        now.Add(12 * time.Hour),
+			wantThreshold: 24 * time.Hour,
+			wantExpiring:  true,
+		},
+		{
+			name:          "already expired",
+			expiry:        now.Add(-time.Hour),
+			wantThreshold: 24 * time.Hour,
+			wantExpiring:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			threshold, expiring := expiryWarning(now, tt.expiry, testExpiryWarnings)
+			assert.Equal(t, tt.wantThreshold, threshold)
+			assert.Equal(t, tt.wantExpiring, expiring)
+			next, hasNext := nextExpiryWarning(now, tt.expiry, testExpiryWarnings)
+			assert.Equal(t, tt.wantNext, next)
+			assert.Equal(t, tt.wantHasNext, hasNext)
+		})
+	}
+}
+
+func Test_degradedFeatures(t *testing.T) {
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Name: "es", Namespace: "ns", Labels: map[string]string{"env": "prod"}},
+		Spec: esv1.ElasticsearchSpec{
+			Version: "8.15.0",
+			NodeSets: []esv1.NodeSet{
+				{Name: "default", Count: 3},
+				{Name: "ml", Count: 2, Config: &commonv1.Config{Data: map[string]any{"node.roles": []any{"ml", "remote_cluster_client"}}}},
+			},
+			RemoteClusters: []esv1.RemoteCluster{
+				{Name: "remote", ElasticsearchRef: commonv1.LocalObjectSelector{Name: "remote"}, APIKey: &esv1.RemoteClusterAPIKey{}},
+			},
+		},
+	}
+	c := k8s.NewFakeClient(
+		&esav1alpha1.ElasticsearchAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "autoscaler", Namespace: "ns"},
+			Spec:       esav1alpha1.ElasticsearchAutoscalerSpec{ElasticsearchRef: esav1alpha1.ElasticsearchRef{Name: "es"}},
+		},
+		&esav1alpha1.ElasticsearchAutoscaler{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-autoscaler", Namespace: "ns"},
+			Spec:       esav1alpha1.ElasticsearchAutoscalerSpec{ElasticsearchRef: esav1alpha1.ElasticsearchRef{Name: "other"}},
+		},
+		&policyv1alpha1.StackConfigPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "global", Namespace: "elastic-system"},
+			Spec:       policyv1alpha1.StackConfigPolicySpec{ResourceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+		},
+		&policyv1alpha1.StackConfigPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "other"},
+			Spec:       policyv1alpha1.StackConfigPolicySpec{ResourceSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+		},
+	)
+
+	features, err := degradedFeatures(context.Background(), c, es, "elastic-system")
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"machine learning (2 nodes)",
+		"remote clusters with API keys (1)",
+		"autoscaling (ElasticsearchAutoscaler autoscaler)",
+		"StackConfigPolicy elastic-system/global",
+	}, features)
+}
+
+func TestReconcileLicenses_reportLicenseExpiry(t *testing.T) {
+	tests := []struct {
+		name             string
+		expiry           time.Duration
+		wantRequeueAfter time.Duration
+		wantStatus       corev1.ConditionStatus
+		wantMessage      string
+		wantEvent        bool
+	}{
+		{
+			name:   "license not expiring soon: requeue when the first threshold is crossed",
+			expiry: 40 * 24 * time.Hour,
+			// 30 days before expiry
+			wantRequeueAfter: 10 * 24 * time.Hour,
+			wantStatus:       corev1.ConditionFalse,
+			wantMessage:      "License enterprise-license (platinum) expires on",
+		},
+		{
+			name:   "license expiring within a threshold",
+			expiry: 20 * 24 * time.Hour,
+			// expiry minus half of the safety margin, before the next threshold 7 days before expiry
+			wantRequeueAfter: 5 * 24 * time.Hour,
+			wantStatus:       corev1.ConditionTrue,
+			wantMessage:      "Enterprise license enterprise-license (platinum) expires on",
+			wantEvent:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := cluster.DeepCopy()
+			recorder := toolsevents.NewFakeRecorder(10)
+			c := k8s.NewFakeClient(enterpriseLicenseExpiringAt(t, client.ElasticsearchLicenseTypePlatinum, 1, time.Now().Add(tt.expiry)), es)
+			r := &ReconcileLicenses{
+				Client:     c,
+				Parameters: operator.Parameters{LicenseExpiryWarnings: testExpiryWarnings},
+				checker:    commonlicense.MockLicenseChecker{EnterpriseEnabled: true},
+				recorder:   recorder,
+			}
+			nsn := k8s.ExtractNamespacedName(es)
+
+			res, err := r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
+			require.NoError(t, err)
+			assert.InDelta(t, tt.wantRequeueAfter, res.RequeueAfter, float64(time.Minute))
+
+			require.NoError(t, c.Get(context.Background(), nsn, es))
+			index := es.Status.Conditions.Index(esv1.LicenseExpiring)
+			require.GreaterOrEqual(t, index, 0)
+			assert.Equal(t, tt.wantStatus, es.Status.Conditions[index].Status)
+			assert.Contains(t, es.Status.Conditions[index].Message, tt.wantMessage)
+			if !tt.wantEvent {
+				assert.Empty(t, recorder.Events)
+				return
+			}
+			require.Len(t, recorder.Events, 1)
+			assert.Contains(t, <-recorder.Events, "LicenseExpiring")
+
+			// no new event as long as no other threshold is crossed
+			_, err = r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
+			require.NoError(t, err)
+			assert.Empty(t, recorder.Events)
+		})
+	}
+}
+
+func TestReconcileLicenses_reportLicenseExpired(t *testing.T) {
+	expired := client.License{
+		UID:                "expired-license",
+		Type:               string(client.ElasticsearchLicenseTypePlatinum),
+		ExpiryDateInMillis: time.Now().Add(-time.Hour).UnixMilli(),
+	}
+	licenseBytes, err := json.Marshal(expired)
+	require.NoError(t, err)
+	licensedCluster := cluster.DeepCopy()
+	licensedCluster.Status.Conditions = licensedCluster.Status.Conditions.MergeWith(commonv1alpha1.Condition{
+		Type:   esv1.LicenseExpiring,
+		Status: corev1.ConditionTrue,
+	})
+
+	recorder := toolsevents.NewFakeRecorder(10)
+	c := k8s.NewFakeClient(licensedCluster, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: esv1.LicenseSecretName("cluster"), Namespace: "namespace"},
+		Data:       map[string][]byte{commonlicense.FileName: licenseBytes},
+	})
+	r := &ReconcileLicenses{
+		Client:     c,
+		Parameters: operator.Parameters{LicenseExpiryWarnings: testExpiryWarnings},
+		checker:    commonlicense.MockLicenseChecker{EnterpriseEnabled: true},
+		recorder:   recorder,
+	}
+	nsn := k8s.ExtractNamespacedName(licensedCluster)
+	_, err = r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
+	require.NoError(t, err)
+
+	require.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "LicenseExpired")
+	assert.Contains(t, event, "License expired-license (platinum) expired on")
+
+	var es esv1.Elasticsearch
+	require.NoError(t, c.Get(context.Background(), nsn, &es))
+	condition := es.Status.Conditions[es.Status.Conditions.Index(esv1.LicenseExpiring)]
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, "No enterprise license applied, the cluster runs with a basic license", condition.Message)
+	require.Error(t, c.Get(context.Background(), crclient.ObjectKey{Namespace: "namespace", Name: esv1.LicenseSecretName("cluster")}, &corev1.Secret{}))
+}
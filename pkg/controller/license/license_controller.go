@@ -16,9 +16,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	toolsevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -36,6 +38,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/sset"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/metrics"
 )
 
 const (
@@ -66,7 +69,8 @@ func (r *ReconcileLicenses) Reconcile(ctx context.Context, request reconcile.Req
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, p operator.Parameters) error {
 	r := newReconciler(mgr, p)
-	c, err := common.NewController(mgr, name, r, p)
+	// licenses are managed by the elected operator, which sees all the license pools and clusters
+	c, err := common.NewLeaderController(mgr, name, r, p)
 	if err != nil {
 		return err
 	}
@@ -129,7 +133,19 @@ func addWatches(mgr manager.Manager, c controller.Controller, k8sClient k8s.Clie
 	)); err != nil {
 		return err
 	}
-	return nil
+
+	// Watch for label changes of namespaces, which can move their clusters to another license pool.
+	return c.Watch(source.Kind(mgr.GetCache(), &corev1.Namespace{},
+		handler.TypedEnqueueRequestsFromMapFunc[*corev1.Namespace](func(ctx context.Context, ns *corev1.Namespace) []reconcile.Request {
+			rs, err := reconcileRequestsForAllClusters(k8sClient, log, client.InNamespace(ns.Name))
+			if err != nil {
+				log.Error(err, "failed to list affected clusters in namespace watch", "namespace", ns.Name)
+				return nil
+			}
+			return rs
+		}),
+		predicate.TypedLabelChangedPredicate[*corev1.Namespace]{},
+	))
 }
 
 var _ reconcile.Reconciler = (*ReconcileLicenses)(nil)
@@ -144,17 +160,31 @@ type ReconcileLicenses struct {
 	recorder  toolsevents.EventRecorder
 }
 
-// findLicense tries to find the best Elastic stack license available.
-func (r *ReconcileLicenses) findLicense(ctx context.Context, c k8s.Client, checker license.Checker, minVersion *version.Version) (esclient.License, string, bool) {
-	licenseList, errs := license.EnterpriseLicensesOrErrors(c)
+// findLicense tries to find the best Elastic stack license available in the license pool of the given cluster.
+// Clusters in the scope of a license pool only get licenses from that pool, other clusters get licenses without scope.
+func (r *ReconcileLicenses) findLicense(
+	ctx context.Context,
+	c k8s.Client,
+	checker license.Checker,
+	cluster esv1.Elasticsearch,
+	minVersion *version.Version,
+) (esclient.License, string, bool, error) {
+	licenseList, errs := license.ScopedEnterpriseLicensesOrErrors(c)
 	if len(errs) > 0 {
 		ulog.FromContext(ctx).Error(utilerrors.NewAggregate(errs), "Ignoring invalid license objects")
 		recordInvalidLicenseEvents(errs, r.recorder)
 	}
+	pools, err := license.NewPoolMatcher(ctx, c, licenseList)
+	if err != nil {
+		return esclient.License{}, "", false, err
+	}
+	pool := pools.Pool(&cluster)
+	ulog.FromContext(ctx).V(1).Info("Resolved license pool", "namespace", cluster.Namespace, "es_name", cluster.Name, "license_pool", pool)
 	valid := func(l license.EnterpriseLicense) (bool, error) {
 		return checker.Valid(ctx, l)
 	}
-	return license.BestMatch(ctx, minVersion, licenseList, valid)
+	matchingSpec, parent, found := license.BestMatch(ctx, minVersion, license.InPool(licenseList, pool), valid)
+	return matchingSpec, parent, found, nil
 }
 
 func recordInvalidLicenseEvents(errs []error, recorder toolsevents.EventRecorder) {
@@ -202,29 +232,55 @@ func reconcileSecret(
 }
 
 // reconcileClusterLicense upserts a cluster license in the namespace of the given Elasticsearch cluster.
-// Returns time to next reconciliation, bool whether a license is configured at all and optional error.
-func (r *ReconcileLicenses) reconcileClusterLicense(ctx context.Context, cluster esv1.Elasticsearch) (time.Time, bool, error) {
+// Returns the license applied to the cluster if any, bool whether a license is configured at all and optional error.
+func (r *ReconcileLicenses) reconcileClusterLicense(ctx context.Context, cluster esv1.Elasticsearch) (*appliedLicense, bool, error) {
 	log := ulog.FromContext(ctx)
 
-	var noResult time.Time
 	minVersion, err := r.minVersion(cluster)
 	if err != nil {
-		return noResult, true, err
+		return nil, true, err
+	}
+	matchingSpec, parent, found, err := r.findLicense(ctx, r, r.checker, cluster, minVersion)
+	if err != nil {
+		return nil, true, err
 	}
-	matchingSpec, parent, found := r.findLicense(ctx, r, r.checker, minVersion)
 	if !found {
 		// no matching license found, delete cluster level license if it exists to revert to basic
+		if previous, exists := currentClusterLicense(ctx, r.Client, cluster); exists {
+			if err := r.reportLicenseExpired(ctx, cluster, previous); err != nil {
+				return nil, false, err
+			}
+		}
 		clusterLicenseNSN := types.NamespacedName{Namespace: cluster.Namespace, Name: esv1.LicenseSecretName(cluster.Name)}
 		log.V(1).Info("No enterprise license found. Attempting to remove cluster license secret", "namespace", cluster.Namespace, "es_name", cluster.Name)
 		err := k8s.DeleteSecretIfExists(ctx, r.Client, clusterLicenseNSN)
-		return noResult, false, err
+		return nil, false, err
 	}
 	log.V(1).Info("Found license for cluster", "eck_license", parent, "es_license", matchingSpec.UID, "license_type", matchingSpec.Type, "namespace", cluster.Namespace, "es_name", cluster.Name)
 	// make sure the signature secret is created in the cluster's namespace
 	if err := reconcileSecret(ctx, r, cluster, parent, matchingSpec); err != nil {
-		return noResult, false, err
+		return nil, false, err
+	}
+	return &appliedLicense{
+		parent:      parent,
+		licenseType: matchingSpec.Type,
+		expiry:      r.licenseExpiry(matchingSpec, parent),
+	}, false, nil
+}
+
+// licenseExpiry returns the expiry of the given cluster license. ECK managed trial licenses are generated by
+// Elasticsearch, their expiry is the one of the parent enterprise license.
+func (r *ReconcileLicenses) licenseExpiry(clusterLicense esclient.License, parent string) time.Time {
+	if expiry := clusterLicense.ExpiryTime(); !expiry.IsZero() {
+		return expiry
+	}
+	licenses, _ := license.ScopedEnterpriseLicensesOrErrors(r)
+	for _, l := range licenses {
+		if l.License.UID == parent {
+			return l.ExpiryTime()
+		}
 	}
-	return matchingSpec.ExpiryTime(), false, nil
+	return time.Time{}
 }
 
 func (r *ReconcileLicenses) minVersion(cluster esv1.Elasticsearch) (*version.Version, error) {
@@ -255,6 +311,10 @@ func (r *ReconcileLicenses) reconcileInternal(ctx context.Context, request recon
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// nothing to do no cluster
+			metrics.LicenseExpiryGauge.DeletePartialMatch(map[string]string{
+				metrics.NamespaceLabel: request.Namespace,
+				metrics.NameLabel:      request.Name,
+			})
 			return res
 		}
 		return res.WithError(err)
@@ -265,14 +325,28 @@ func (r *ReconcileLicenses) reconcileInternal(ctx context.Context, request recon
 		return res
 	}
 
-	newExpiry, noLicense, err := r.reconcileClusterLicense(ctx, cluster)
+	applied, noLicense, err := r.reconcileClusterLicense(ctx, cluster)
 	if err != nil {
 		return res.WithError(err)
 	}
+	var newExpiry time.Time
+	if applied != nil {
+		newExpiry = applied.expiry
+	}
 	margin := defaultSafetyMargin
 	if noLicense {
 		// don't apply safety margin if we don't have a license but use requested requeue time as specified in newExpiry
 		margin = 0
 	}
-	return res.WithRequeue(nextReconcile(newExpiry, margin))
+	requeueAfter := nextReconcile(newExpiry, margin)
+
+	nextWarning, hasNextWarning, err := r.reportLicenseExpiry(ctx, cluster, applied)
+	if err != nil {
+		return res.WithError(err)
+	}
+	if hasNextWarning && nextWarning < requeueAfter {
+		// make sure users are warned as soon as a warning threshold is crossed
+		requeueAfter = nextWarning
+	}
+	return res.WithRequeue(requeueAfter)
 }
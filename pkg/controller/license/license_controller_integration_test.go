@@ -46,7 +46,7 @@ func TestReconcile(t *testing.T) {
 			Client:  mgr.GetClient(),
 			checker: license.MockLicenseChecker{EnterpriseEnabled: true},
 		}
-		c, err := common.NewController(mgr, name, r, p)
+		c, err := common.NewController(mgr, name, r, p, &esv1.Elasticsearch{})
 		if err != nil {
 			return err
 		}
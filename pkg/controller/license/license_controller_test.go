@@ -10,10 +10,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -86,8 +88,14 @@ func enterpriseLicense(t *testing.T, licenseType client.ElasticsearchLicenseType
 	if expired {
 		expiry = time.Now().Add(-24 * time.Hour)
 	}
+	return enterpriseLicenseExpiringAt(t, licenseType, maxNodes, expiry)
+}
+
+func enterpriseLicenseExpiringAt(t *testing.T, licenseType client.ElasticsearchLicenseType, maxNodes int, expiry time.Time) *corev1.Secret {
+	t.Helper()
 	license := commonlicense.EnterpriseLicense{
 		License: commonlicense.LicenseSpec{
+			UID:                "enterprise-license",
 			ExpiryDateInMillis: expiry.Unix() * 1000,
 			StartDateInMillis:  time.Now().Add(-1*time.Minute).Unix() * 1000,
 			Type:               "enterprise",
@@ -211,3 +219,31 @@ func TestReconcileLicenses_reconcileInternal(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileLicenses_reconcileInternal_licensePools(t *testing.T) {
+	financeLicense := enterpriseLicense(t, client.ElasticsearchLicenseTypePlatinum, 1, false)
+	financeLicense.Name = "finance-license"
+	financeLicense.Labels[commonlicense.LicenseNamespaceSelectorLabelPrefix+"business-unit"] = "finance"
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace"}}
+	c := k8s.NewFakeClient(financeLicense, namespace, cluster.DeepCopy())
+	r := &ReconcileLicenses{
+		Client:  c,
+		checker: commonlicense.MockLicenseChecker{EnterpriseEnabled: true},
+	}
+	nsn := k8s.ExtractNamespacedName(cluster)
+	licenseNsn := types.NamespacedName{Namespace: nsn.Namespace, Name: esv1.LicenseSecretName(nsn.Name)}
+
+	// the cluster is outside the scope of the only license
+	_, err := r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
+	require.NoError(t, err)
+	require.True(t, apierrors.IsNotFound(c.Get(context.Background(), licenseNsn, &corev1.Secret{})))
+
+	// moving the namespace to the finance pool gives access to the license
+	namespace.Labels = map[string]string{"business-unit": "finance"}
+	require.NoError(t, c.Update(context.Background(), namespace))
+	_, err = r.reconcileInternal(context.Background(), reconcile.Request{NamespacedName: nsn}).Aggregate()
+	require.NoError(t, err)
+	var clusterLicense corev1.Secret
+	require.NoError(t, c.Get(context.Background(), licenseNsn, &clusterLicense))
+	assert.Equal(t, "enterprise-license", clusterLicense.Labels[commonlicense.LicenseLabelName])
+}
@@ -9,16 +9,17 @@ import (
 
 	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 )
 
-func reconcileRequestsForAllClusters(c k8s.Client, log logr.Logger) ([]reconcile.Request, error) {
+func reconcileRequestsForAllClusters(c k8s.Client, log logr.Logger, opts ...client.ListOption) ([]reconcile.Request, error) {
 	var clusters esv1.ElasticsearchList
-	// list all clusters
-	err := c.List(context.Background(), &clusters)
+	// list all clusters, or those matching the given options
+	err := c.List(context.Background(), &clusters, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -24,6 +24,7 @@ func Test_listAffectedLicenses(t *testing.T) {
 
 	type args struct {
 		initialObjects []client.Object
+		opts           []client.ListOption
 	}
 	tests := []struct {
 		name          string
@@ -68,6 +69,19 @@ func Test_listAffectedLicenses(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "clusters of a namespace",
+			args: args{
+				initialObjects: []client.Object{
+					&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "foo-cluster", Namespace: "default"}},
+					&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Name: "bar-cluster", Namespace: "finance"}},
+				},
+				opts: []client.ListOption{client.InNamespace("finance")},
+			},
+			want: []reconcile.Request{
+				{NamespacedName: types.NamespacedName{Namespace: "finance", Name: "bar-cluster"}},
+			},
+		},
 		{
 			name:          "list error",
 			args:          args{},
@@ -82,7 +96,7 @@ func Test_listAffectedLicenses(t *testing.T) {
 				client = k8s.NewFailingClient(tt.injectedError)
 			}
 
-			got, err := reconcileRequestsForAllClusters(client, logr.Discard())
+			got, err := reconcileRequestsForAllClusters(client, logr.Discard(), tt.args.opts...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("reconcileRequestsForAllClusters() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -267,7 +267,8 @@ func addWatches(mgr manager.Manager, c controller.Controller) error {
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	r := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, name, r, params)
+	// the trial is managed by the elected operator
+	c, err := common.NewLeaderController(mgr, name, r, params)
 	if err != nil {
 		return err
 	}
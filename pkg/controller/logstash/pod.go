@@ -115,7 +115,8 @@ func buildPodTemplate(params Params, configHash hash.Hash32) (corev1.PodTemplate
 		WithResources(DefaultResources).
 		WithLabels(podMetadata.Labels).
 		WithAnnotations(podMetadata.Annotations).
-		WithDockerImage(spec.Image, container.ImageRepository(container.LogstashImage, v)).
+		WithDockerImage(spec.Image, container.ImageRepository(params.Logstash.Namespace, container.LogstashImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(params.Logstash.Namespace)...).
 		WithAutomountServiceAccountToken().
 		WithPorts(ports).
 		WithReadinessProbe(readinessProbe(params)).
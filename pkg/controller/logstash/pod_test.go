@@ -65,7 +65,7 @@ func TestNewPodTemplateSpec(t *testing.T) {
 				logstashContainer := GetLogstashContainer(pod.Spec)
 				require.NotNil(t, logstashContainer)
 				assert.Equal(t, 5, len(logstashContainer.VolumeMounts))
-				assert.Equal(t, container.ImageRepository(container.LogstashImage, version.MustParse("8.6.1")), logstashContainer.Image)
+				assert.Equal(t, container.ImageRepository("", container.LogstashImage, version.MustParse("8.6.1")), logstashContainer.Image)
 				assert.NotNil(t, logstashContainer.ReadinessProbe)
 				assert.NotEmpty(t, logstashContainer.Ports)
 			},
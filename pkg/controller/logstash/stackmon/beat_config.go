@@ -8,11 +8,13 @@ import (
 	"context"
 	_ "embed" // for the beats config files
 
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
 
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/monitoring"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/logstash/configs"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 )
@@ -27,37 +29,21 @@ var (
 	filebeatConfig string
 )
 
-// ReconcileConfigSecrets reconciles the secrets holding beats configuration
+// ReconcileConfigSecrets reconciles the secrets holding beats or EDOT collector configuration, and the shared
+// collector of the namespace in the Shared monitoring mode.
 func ReconcileConfigSecrets(ctx context.Context, client k8s.Client, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata) error {
-	isMonitoringReconcilable, err := monitoring.IsReconcilable(&logstash)
-	if err != nil {
-		return err
-	}
-	if !isMonitoringReconcilable {
-		return nil
-	}
-
-	if monitoring.IsMetricsDefined(&logstash) {
-		b, err := Metricbeat(ctx, client, logstash, apiServer, meta)
-		if err != nil {
-			return err
-		}
-
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &logstash); err != nil {
-			return err
-		}
-	}
-
-	if monitoring.IsLogsDefined(&logstash) {
-		b, err := Filebeat(ctx, client, logstash, meta)
-		if err != nil {
-			return err
-		}
-
-		if _, err := reconciler.ReconcileSecret(ctx, client, b.ConfigSecret, &logstash); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return stackmon.ReconcileConfigSecrets(ctx, client, &logstash, commonv1.LogstashMonitoringAssociationType, stackmon.ConfigBuilders{
+		Metricbeat: func() (stackmon.BeatSidecar, error) {
+			return Metricbeat(ctx, client, logstash, apiServer, meta)
+		},
+		Filebeat: func() (stackmon.BeatSidecar, error) {
+			return Filebeat(ctx, client, logstash, meta)
+		},
+		Collector: func() (stackmon.BeatSidecar, error) {
+			return Collector(ctx, client, logstash, apiServer, meta)
+		},
+		SharedCollector: func() (corev1.Secret, error) {
+			return SharedCollectorConfig(ctx, client, logstash, apiServer, meta)
+		},
+	})
 }
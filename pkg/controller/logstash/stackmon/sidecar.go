@@ -7,6 +7,7 @@ package stackmon
 import (
 	"context"
 	"fmt"
+	"hash"
 	"hash/fnv"
 
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
@@ -32,7 +33,9 @@ const (
 	cfgHashAnnotation = "logstash.k8s.elastic.co/monitoring-config-hash"
 )
 
-func Metricbeat(ctx context.Context, client k8s.Client, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+// metricbeatConfig renders the Metricbeat configuration to collect monitoring data from the Logstash API at the given
+// host, and returns it along with the volume holding the CA certificate of the API, if any.
+func metricbeatConfig(client k8s.Client, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, host string) (string, commonvolume.VolumeLike, error) {
 	useTLS := apiServer.UseTLS()
 
 	var protocol = "http"
@@ -42,16 +45,16 @@ func Metricbeat(ctx context.Context, client k8s.Client, logstash logstashv1alpha
 
 	v, err := version.Parse(logstash.Spec.Version)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
 
 	caVol, err := stackmon.CAVolume(client, k8s.ExtractNamespacedName(&logstash), logstashv1alpha1.Namer, commonv1.LogstashMonitoringAssociationType, useTLS)
 	if err != nil {
-		return stackmon.BeatSidecar{}, err
+		return "", nil, err
 	}
 
 	input := stackmon.TemplateParams{
-		URL:      fmt.Sprintf("%s://localhost:%d", protocol, network.HTTPPort),
+		URL:      fmt.Sprintf("%s://%s:%d", protocol, host, network.HTTPPort),
 		Username: apiServer.Username,
 		Password: apiServer.Password,
 		IsSSL:    useTLS,
@@ -59,6 +62,19 @@ func Metricbeat(ctx context.Context, client k8s.Client, logstash logstashv1alpha
 	}
 
 	cfg, err := stackmon.RenderTemplate(v, metricbeatConfigTemplate, input)
+	if err != nil {
+		return "", nil, err
+	}
+	return cfg, caVol, nil
+}
+
+func Metricbeat(ctx context.Context, client k8s.Client, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	cfg, caVol, err := metricbeatConfig(client, logstash, apiServer, "localhost")
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+
+	v, err := version.Parse(logstash.Spec.Version)
 	if err != nil {
 		return stackmon.BeatSidecar{}, err
 	}
@@ -74,8 +90,45 @@ func Filebeat(ctx context.Context, client k8s.Client, logstash logstashv1alpha1.
 	return stackmon.NewFileBeatSidecar(ctx, client, &logstash, logstash.Spec.Version, filebeatConfig, nil, meta)
 }
 
-// WithMonitoring updates the Logstash Pod template builder to deploy Metricbeat and Filebeat in sidecar containers
-// in the Logstash pod and injects the volumes for the beat configurations and the ES CA certificates.
+// Collector returns an EDOT collector sidecar collecting both the metrics and the logs of the Logstash Pod.
+func Collector(ctx context.Context, client k8s.Client, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata) (stackmon.BeatSidecar, error) {
+	v, err := version.Parse(logstash.Spec.Version)
+	if err != nil {
+		return stackmon.BeatSidecar{}, err
+	}
+
+	var metricsCfg, logsCfg string
+	var caVol commonvolume.VolumeLike
+	if monitoring.IsMetricsDefined(&logstash) {
+		metricsCfg, caVol, err = metricbeatConfig(client, logstash, apiServer, "localhost")
+		if err != nil {
+			return stackmon.BeatSidecar{}, err
+		}
+	}
+	if monitoring.IsLogsDefined(&logstash) {
+		logsCfg = filebeatConfig
+	}
+	return stackmon.NewCollectorSidecar(ctx, client, &logstash, v, metricsCfg, logsCfg, meta, caVol)
+}
+
+// SharedCollectorConfig returns the configuration of the shared collector of the namespace to collect the metrics of
+// Logstash through its API Service.
+func SharedCollectorConfig(ctx context.Context, client k8s.Client, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata) (corev1.Secret, error) {
+	host := fmt.Sprintf("%s.%s.svc", logstashv1alpha1.APIServiceName(logstash.Name), logstash.Namespace)
+	cfg, caVol, err := metricbeatConfig(client, logstash, apiServer, host)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	v, err := version.Parse(logstash.Spec.Version)
+	if err != nil {
+		return corev1.Secret{}, err
+	}
+	return stackmon.NewSharedCollectorConfig(ctx, client, &logstash, v, cfg, caVol, meta)
+}
+
+// WithMonitoring updates the Logstash Pod template builder to deploy Metricbeat and Filebeat in sidecar containers, or
+// a single EDOT collector sidecar, in the Logstash pod and injects the volumes for their configurations and the ES CA
+// certificates. Nothing is deployed in the Pod in the Shared monitoring mode.
 func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata) (*defaults.PodTemplateBuilder, error) {
 	isMonitoringReconcilable, err := monitoring.IsReconcilable(&logstash)
 	if err != nil {
@@ -88,6 +141,43 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 	configHash := fnv.New32a()
 	var volumes []corev1.Volume
 
+	switch logstash.Spec.Monitoring.GetMode() {
+	case commonv1.SharedMonitoringMode:
+		// metrics are collected by the shared collector of the namespace
+		return builder, nil
+	case commonv1.CollectorMonitoringMode:
+		b, err := Collector(ctx, client, logstash, apiServer, meta)
+		if err != nil {
+			return nil, err
+		}
+		if monitoring.IsLogsDefined(&logstash) {
+			// Set environment variable to tell Logstash container to write logs to disk
+			builder.WithEnv(fileLogStyleEnvVar())
+			// Add the logs volume mount from the logstash container
+			b.Container.VolumeMounts = append(b.Container.VolumeMounts, volume.DefaultLogsVolume.VolumeMount())
+		}
+		volumes = append(volumes, b.Volumes...)
+		builder.WithContainers(b.Container)
+		configHash.Write(b.ConfigHash.Sum(nil))
+	default:
+		volumes, err = withBeatSidecars(ctx, client, builder, logstash, apiServer, meta, configHash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// add the config hash annotation to ensure pod rotation when an ES password or a CA are rotated
+	builder.WithAnnotations(map[string]string{cfgHashAnnotation: fmt.Sprint(configHash.Sum32())})
+	// inject all volumes
+	builder.WithVolumes(volumes...)
+
+	return builder, nil
+}
+
+// withBeatSidecars adds the Metricbeat and Filebeat sidecar containers to the Pod template builder, and returns the
+// volumes to inject in the Pod.
+func withBeatSidecars(ctx context.Context, client k8s.Client, builder *defaults.PodTemplateBuilder, logstash logstashv1alpha1.Logstash, apiServer configs.APIServer, meta metadata.Metadata, configHash hash.Hash) ([]corev1.Volume, error) {
+	var volumes []corev1.Volume
 	if monitoring.IsMetricsDefined(&logstash) {
 		b, err := Metricbeat(ctx, client, logstash, apiServer, meta)
 		if err != nil {
@@ -125,11 +215,5 @@ func WithMonitoring(ctx context.Context, client k8s.Client, builder *defaults.Po
 		builder.WithContainers(filebeat)
 		configHash.Write(b.ConfigHash.Sum(nil))
 	}
-
-	// add the config hash annotation to ensure pod rotation when an ES password or a CA are rotated
-	builder.WithAnnotations(map[string]string{cfgHashAnnotation: fmt.Sprint(configHash.Sum32())})
-	// inject all volumes
-	builder.WithVolumes(volumes...)
-
-	return builder, nil
+	return volumes, nil
 }
@@ -15,6 +15,7 @@ import (
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
 	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	volumevalidations "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume/validations"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
@@ -44,6 +45,7 @@ func validations() []validation {
 		checkNoUnknownFields,
 		checkNameLength,
 		checkSupportedVersion,
+		checkImageCatalogVersion,
 		checkSingleConfigSource,
 		checkESRefsNamed,
 		checkAssociations,
@@ -63,6 +65,10 @@ func checkSupportedVersion(l *lsv1alpha1.Logstash) field.ErrorList {
 	return commonv1.CheckSupportedStackVersion(l.Spec.Version, version.SupportedLogstashVersions)
 }
 
+func checkImageCatalogVersion(l *lsv1alpha1.Logstash) field.ErrorList {
+	return commonv1.CheckImageCatalogVersion(l.Spec.Version, container.LogstashImage, l.Spec.Image)
+}
+
 func checkNoDowngrade(prev, curr *lsv1alpha1.Logstash) field.ErrorList {
 	if commonv1.IsConfiguredToAllowDowngrades(curr) {
 		return nil
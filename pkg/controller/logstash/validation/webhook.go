@@ -10,6 +10,7 @@ import (
 
 	admissionv1 "k8s.io/api/admission/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -17,6 +18,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
+	commonwebhook "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/set"
@@ -31,11 +33,12 @@ const (
 var lslog = ulog.Log.WithName("ls-validation")
 
 // RegisterWebhook will register the Logstash validating webhook.
-func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, managedNamespaces []string) {
+func RegisterWebhook(mgr ctrl.Manager, validateStorageClass bool, referenceValidator *commonwebhook.ReferenceValidator, managedNamespaces []string) {
 	wh := &validatingWebhook{
 		client:               mgr.GetClient(),
 		decoder:              admission.NewDecoder(mgr.GetScheme()),
 		validateStorageClass: validateStorageClass,
+		referenceValidator:   referenceValidator,
 		managedNamespaces:    set.Make(managedNamespaces...),
 	}
 	lslog.Info("Registering Logstash validating webhook", "path", webhookPath)
@@ -46,6 +49,7 @@ type validatingWebhook struct {
 	client               k8s.Client
 	decoder              admission.Decoder
 	validateStorageClass bool
+	referenceValidator   *commonwebhook.ReferenceValidator
 	managedNamespaces    set.StringSet
 }
 
@@ -92,17 +96,27 @@ func (wh *validatingWebhook) Handle(ctx context.Context, req admission.Request)
 		}
 	}
 
+	var oldObj runtime.Object
 	if req.Operation == admissionv1.Update {
-		oldObj := &lsv1alpha1.Logstash{}
-		err = wh.decoder.DecodeRaw(req.OldObject, oldObj)
+		oldLs := &lsv1alpha1.Logstash{}
+		err = wh.decoder.DecodeRaw(req.OldObject, oldLs)
 		if err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
 
-		err = wh.ValidateUpdate(ctx, oldObj, ls)
+		err = wh.ValidateUpdate(ctx, oldLs, ls)
 		if err != nil {
 			return admission.Denied(err.Error())
 		}
+		oldObj = oldLs
+	}
+
+	if req.Operation == admissionv1.Create || req.Operation == admissionv1.Update {
+		warnings, err := wh.referenceValidator.Validate(ctx, ls, oldObj)
+		if err != nil {
+			return admission.Denied(err.Error())
+		}
+		return admission.Allowed("").WithWarnings(warnings...)
 	}
 
 	return admission.Allowed("")
@@ -0,0 +1,286 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package maps
+
+import (
+	"path"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/initreport"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/settings"
+)
+
+const (
+	// BasemapDataContainerName is the name of the init container downloading and verifying the basemap data.
+	BasemapDataContainerName = "basemap-data"
+	// BasemapDataMountPath is the directory in which the basemap data volume is mounted.
+	BasemapDataMountPath = "/mnt/elastic-internal/basemap-data"
+
+	basemapDataVolumeName      = "basemap-data"
+	defaultBasemapDataFilename = "planet.mbtiles"
+
+	basemapURLEnvName      = "BASEMAP_URL"
+	basemapChecksumEnvName = "BASEMAP_CHECKSUM"
+	basemapFileEnvName     = "BASEMAP_FILE"
+
+	// basemapDataPendingRequeue is the interval at which the provisioning status is checked while pending.
+	basemapDataPendingRequeue = 30 * time.Second
+)
+
+// basemapDataScript downloads the basemap data file, if a URL is set, and verifies its checksum, if set.
+// Downloads are resumed from the partial file left by a previous attempt, and a downloaded file is only moved to its
+// final location once verified, so that it can be reused as is by later Pods sharing the same cache volume.
+// It runs with Node.js, which is part of the Elastic Maps Server image. The download progress is written to the
+// standard output of the container, and the outcome to its termination message, to be reported in the status.
+const basemapDataScript = `
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+const stream = require('stream');
+
+const url = process.env.` + basemapURLEnvName + ` || '';
+const expected = (process.env.` + basemapChecksumEnvName + ` || '').replace(/^sha256:/, '');
+const file = process.env.` + basemapFileEnvName + `;
+const partial = file + '.partial';
+
+const maxAttempts = 5;
+const maxRedirects = 10;
+const progressIntervalMs = 10000;
+const timeoutMs = 60000;
+
+function report(message) {
+	console.log(message);
+	fs.writeFileSync('/dev/termination-log', message + '\n');
+}
+
+function fail(message) {
+	report(message);
+	process.exit(1);
+}
+
+function mebibytes(bytes) {
+	return Math.floor(bytes / 1048576) + 'MiB';
+}
+
+function progress(done, total) {
+	if (!total) {
+		return 'downloading basemap data: ' + mebibytes(done);
+	}
+	return 'downloading basemap data: ' + mebibytes(done) + ' of ' + mebibytes(total) + ' (' + Math.floor(done * 100 / total) + '%)';
+}
+
+function verify(p) {
+	return new Promise((resolve, reject) => {
+		if (!expected) {
+			resolve();
+			return;
+		}
+		console.log('verifying basemap data file');
+		const hash = crypto.createHash('sha256');
+		fs.createReadStream(p)
+			.on('error', reject)
+			.on('data', (chunk) => hash.update(chunk))
+			.on('end', () => {
+				const actual = hash.digest('hex');
+				if (actual !== expected) {
+					reject(new Error('checksum mismatch: expected sha256:' + expected + ', got sha256:' + actual));
+					return;
+				}
+				resolve();
+			});
+	});
+}
+
+function download(location, redirects) {
+	return new Promise((resolve, reject) => {
+		const offset = fs.existsSync(partial) ? fs.statSync(partial).size : 0;
+		const client = location.startsWith('https:') ? require('https') : require('http');
+		const headers = offset > 0 ? { Range: 'bytes=' + offset + '-' } : {};
+		const req = client.get(location, { headers: headers }, (res) => {
+			if (res.statusCode >= 300 && res.statusCode < 400 && res.headers.location) {
+				res.resume();
+				if (redirects >= maxRedirects) {
+					reject(new Error('too many redirects'));
+					return;
+				}
+				download(new URL(res.headers.location, location).toString(), redirects + 1).then(resolve, reject);
+				return;
+			}
+			if (res.statusCode === 416 && offset > 0) {
+				// the partial file is already complete
+				res.resume();
+				resolve();
+				return;
+			}
+			if (res.statusCode !== 200 && res.statusCode !== 206) {
+				res.resume();
+				reject(new Error('unexpected HTTP status ' + res.statusCode));
+				return;
+			}
+			const resumed = res.statusCode === 206;
+			let done = resumed ? offset : 0;
+			let total = Number(res.headers['content-length']) + done;
+			const range = /\/(\d+)$/.exec(res.headers['content-range'] || '');
+			if (range) {
+				total = Number(range[1]);
+			}
+			let reported = Date.now();
+			res.on('data', (chunk) => {
+				done += chunk.length;
+				if (Date.now() - reported >= progressIntervalMs) {
+					reported = Date.now();
+					console.log(progress(done, total));
+				}
+			});
+			stream.pipeline(res, fs.createWriteStream(partial, { flags: resumed ? 'a' : 'w' }), (err) => {
+				if (err) {
+					reject(err);
+					return;
+				}
+				if (total && done !== total) {
+					reject(new Error('incomplete download: ' + progress(done, total)));
+					return;
+				}
+				resolve();
+			});
+		});
+		req.on('error', reject);
+		req.setTimeout(timeoutMs, () => req.destroy(new Error('no response within ' + timeoutMs / 1000 + 's')));
+	});
+}
+
+async function main() {
+	if (!url) {
+		if (!fs.existsSync(file)) {
+			fail('basemap data file not found');
+		}
+		await verify(file).catch((err) => fail(err.message));
+		report('basemap data file verified');
+		return;
+	}
+
+	if (fs.existsSync(file)) {
+		report('reusing verified basemap data file from cache');
+		return;
+	}
+
+	// remove data files of previous sources to reclaim space
+	const dir = path.dirname(file);
+	for (const entry of fs.readdirSync(dir)) {
+		if (/^planet-.*\.mbtiles/.test(entry) && !entry.startsWith(path.basename(file))) {
+			fs.rmSync(path.join(dir, entry), { force: true });
+		}
+	}
+
+	for (let attempt = 1; ; attempt++) {
+		try {
+			await download(url, 0);
+			break;
+		} catch (err) {
+			if (attempt >= maxAttempts) {
+				fail('download failed: ' + err.message);
+			}
+			console.log('download attempt ' + attempt + ' failed, retrying: ' + err.message);
+			await new Promise((resolve) => setTimeout(resolve, attempt * 5000));
+		}
+	}
+	await verify(partial).catch((err) => {
+		fs.rmSync(partial, { force: true });
+		fail(err.message);
+	});
+	fs.renameSync(partial, file);
+	report('basemap data file downloaded and verified');
+}
+
+main().catch((err) => fail(err.message));
+`
+
+// basemapDataFile returns the path of the basemap data file in the Elastic Maps Server containers.
+func basemapDataFile(source emsv1alpha1.BasemapDataSource) string {
+	if source.URL != "" {
+		// the file is named after its checksum, to not reuse a cached file of a previous source
+		return path.Join(BasemapDataMountPath, "planet-"+strings.TrimPrefix(source.Checksum, "sha256:")+".mbtiles")
+	}
+	if source.Path != "" {
+		return path.Join(BasemapDataMountPath, source.Path)
+	}
+	return path.Join(BasemapDataMountPath, defaultBasemapDataFilename)
+}
+
+// basemapDataConfig returns the configuration pointing Elastic Maps Server to the basemap data file, if any.
+func basemapDataConfig(ems emsv1alpha1.ElasticMapsServer) *settings.CanonicalConfig {
+	if ems.Spec.BasemapData == nil {
+		return settings.NewCanonicalConfig()
+	}
+	return settings.MustCanonicalConfig(map[string]any{
+		"path.planet": basemapDataFile(*ems.Spec.BasemapData),
+	})
+}
+
+// basemapDataVolume returns the volume holding the basemap data file.
+func basemapDataVolume(source emsv1alpha1.BasemapDataSource) corev1.Volume {
+	vol := corev1.Volume{Name: basemapDataVolumeName}
+	switch {
+	case source.PersistentVolumeClaim != nil:
+		pvc := source.PersistentVolumeClaim.DeepCopy()
+		// the data file is only ever read from the source
+		pvc.ReadOnly = true
+		vol.PersistentVolumeClaim = pvc
+	case source.Cache != nil:
+		vol.PersistentVolumeClaim = source.Cache.DeepCopy()
+	default:
+		vol.EmptyDir = &corev1.EmptyDirVolumeSource{}
+	}
+	return vol
+}
+
+// withBasemapData mounts the basemap data volume, and adds an init container downloading and verifying the basemap
+// data file before Elastic Maps Server starts. The main container mounts the volume read-only.
+func withBasemapData(builder *defaults.PodTemplateBuilder, ems emsv1alpha1.ElasticMapsServer) *defaults.PodTemplateBuilder {
+	source := ems.Spec.BasemapData
+	if source == nil {
+		return builder
+	}
+
+	return builder.
+		WithVolumes(basemapDataVolume(*source)).
+		WithVolumeMounts(corev1.VolumeMount{Name: basemapDataVolumeName, MountPath: BasemapDataMountPath, ReadOnly: true}).
+		WithInitContainers(corev1.Container{
+			Name:    BasemapDataContainerName,
+			Command: []string{"node", "-e", basemapDataScript},
+			Env: []corev1.EnvVar{
+				{Name: basemapURLEnvName, Value: source.URL},
+				{Name: basemapChecksumEnvName, Value: source.Checksum},
+				{Name: basemapFileEnvName, Value: basemapDataFile(*source)},
+			},
+			VolumeMounts: []corev1.VolumeMount{
+				// the init container writes the downloaded file, unless read from a source volume
+				{Name: basemapDataVolumeName, MountPath: BasemapDataMountPath, ReadOnly: source.PersistentVolumeClaim != nil},
+			},
+		})
+}
+
+// basemapDataStatus returns the provisioning status of the basemap data from the report of the init container of the
+// most recent Pod, or nil if the init container did not run yet in any Pod.
+func basemapDataStatus(report *initreport.Report) *emsv1alpha1.BasemapDataStatus {
+	if report == nil {
+		return nil
+	}
+	switch report.Phase {
+	case initreport.SucceededPhase:
+		return &emsv1alpha1.BasemapDataStatus{Phase: emsv1alpha1.BasemapDataReadyPhase, Message: report.LastLine()}
+	case initreport.FailedPhase:
+		return &emsv1alpha1.BasemapDataStatus{Phase: emsv1alpha1.BasemapDataFailedPhase, Message: report.LastLine()}
+	}
+	return &emsv1alpha1.BasemapDataStatus{
+		Phase:   emsv1alpha1.BasemapDataPendingPhase,
+		Message: "downloading or verifying basemap data",
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package maps
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	emsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/maps/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/initreport"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+)
+
+func TestNewPodSpec_BasemapData(t *testing.T) {
+	checksum := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name            string
+		source          emsv1alpha1.BasemapDataSource
+		wantVolume      corev1.VolumeSource
+		wantInitMount   corev1.VolumeMount
+		wantBasemapFile string
+	}{
+		{
+			name:   "download to emptyDir",
+			source: emsv1alpha1.BasemapDataSource{URL: "https://mirror.local/planet.mbtiles", Checksum: checksum},
+			wantVolume: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+			wantInitMount:   corev1.VolumeMount{Name: "basemap-data", MountPath: BasemapDataMountPath},
+			wantBasemapFile: "/mnt/elastic-internal/basemap-data/planet-" + strings.Repeat("a", 64) + ".mbtiles",
+		},
+		{
+			name: "download to cache",
+			source: emsv1alpha1.BasemapDataSource{
+				URL:      "https://mirror.local/planet.mbtiles",
+				Checksum: checksum,
+				Cache:    &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap-cache"},
+			},
+			wantVolume: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap-cache"},
+			},
+			wantInitMount:   corev1.VolumeMount{Name: "basemap-data", MountPath: BasemapDataMountPath},
+			wantBasemapFile: "/mnt/elastic-internal/basemap-data/planet-" + strings.Repeat("a", 64) + ".mbtiles",
+		},
+		{
+			name: "read from PersistentVolumeClaim",
+			source: emsv1alpha1.BasemapDataSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap"},
+			},
+			wantVolume: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap", ReadOnly: true},
+			},
+			wantInitMount:   corev1.VolumeMount{Name: "basemap-data", MountPath: BasemapDataMountPath, ReadOnly: true},
+			wantBasemapFile: "/mnt/elastic-internal/basemap-data/planet.mbtiles",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ems := emsv1alpha1.ElasticMapsServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ems", Namespace: "default"},
+				Spec: emsv1alpha1.MapsSpec{
+					Version:     "9.1.0",
+					HTTP:        commonv1.HTTPConfig{TLS: commonv1.TLSOptions{SelfSignedCertificate: &commonv1.SelfSignedCertificate{Disabled: true}}},
+					BasemapData: &tt.source,
+				},
+			}
+			ems.SetAssociationConf(&commonv1.AssociationConf{})
+
+			podSpec, err := newPodSpec(ems, "hash", metadata.Metadata{}, true)
+			require.NoError(t, err)
+
+			assert.Contains(t, podSpec.Spec.Volumes, corev1.Volume{Name: "basemap-data", VolumeSource: tt.wantVolume})
+			require.Len(t, podSpec.Spec.Containers, 1)
+			assert.Contains(t, podSpec.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "basemap-data", MountPath: BasemapDataMountPath, ReadOnly: true})
+
+			require.Len(t, podSpec.Spec.InitContainers, 1)
+			initContainer := podSpec.Spec.InitContainers[0]
+			assert.Equal(t, BasemapDataContainerName, initContainer.Name)
+			assert.Equal(t, podSpec.Spec.Containers[0].Image, initContainer.Image)
+			assert.Contains(t, initContainer.VolumeMounts, tt.wantInitMount)
+			assert.Contains(t, initContainer.Env, corev1.EnvVar{Name: basemapFileEnvName, Value: tt.wantBasemapFile})
+			assert.Contains(t, initContainer.Env, corev1.EnvVar{Name: basemapURLEnvName, Value: tt.source.URL})
+			// the script runs with Node.js, which is part of the Elastic Maps Server image
+			assert.Equal(t, []string{"node", "-e", basemapDataScript}, initContainer.Command)
+		})
+	}
+}
+
+func Test_basemapDataStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		report *initreport.Report
+		want   *emsv1alpha1.BasemapDataStatus
+	}{
+		{
+			name: "no Pods",
+			want: nil,
+		},
+		{
+			name:   "init container not started",
+			report: &initreport.Report{Phase: initreport.PendingPhase},
+			want:   &emsv1alpha1.BasemapDataStatus{Phase: emsv1alpha1.BasemapDataPendingPhase, Message: "downloading or verifying basemap data"},
+		},
+		{
+			name:   "downloading",
+			report: &initreport.Report{Phase: initreport.RunningPhase},
+			want:   &emsv1alpha1.BasemapDataStatus{Phase: emsv1alpha1.BasemapDataPendingPhase, Message: "downloading or verifying basemap data"},
+		},
+		{
+			name:   "ready",
+			report: &initreport.Report{Phase: initreport.SucceededPhase, Lines: []string{"basemap data file downloaded and verified"}},
+			want:   &emsv1alpha1.BasemapDataStatus{Phase: emsv1alpha1.BasemapDataReadyPhase, Message: "basemap data file downloaded and verified"},
+		},
+		{
+			name:   "failed",
+			report: &initreport.Report{Phase: initreport.FailedPhase, Lines: []string{"checksum mismatch"}},
+			want:   &emsv1alpha1.BasemapDataStatus{Phase: emsv1alpha1.BasemapDataFailedPhase, Message: "checksum mismatch"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, basemapDataStatus(tt.report))
+		})
+	}
+}
@@ -84,7 +84,7 @@ func newConfig(ctx context.Context, d driver.Interface, ems emsv1alpha1.ElasticM
 	if err != nil {
 		return cfg, err
 	}
-	err = cfg.MergeWith(inlineUserCfg, refUserCfg, defaults, tls, assocCfg)
+	err = cfg.MergeWith(inlineUserCfg, refUserCfg, defaults, tls, assocCfg, basemapDataConfig(ems))
 	return cfg, err
 }
 
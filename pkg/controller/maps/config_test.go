@@ -44,6 +44,28 @@ ssl:
     certificate: /mnt/elastic-internal/http-certs/tls.crt
     enabled: true
     key: /mnt/elastic-internal/http-certs/tls.key
+`,
+			wantErr: false,
+		},
+		{
+			name: "basemap data",
+			args: args{
+				runtimeObjs: nil,
+				ems: v1alpha1.ElasticMapsServer{
+					Spec: v1alpha1.MapsSpec{BasemapData: &v1alpha1.BasemapDataSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "basemap"},
+						Path:                  "data/planet.mbtiles",
+					}},
+				},
+				ipFamily: corev1.IPv4Protocol,
+			},
+			want: `host: 0.0.0.0
+path:
+    planet: /mnt/elastic-internal/basemap-data/data/planet.mbtiles
+ssl:
+    certificate: /mnt/elastic-internal/http-certs/tls.crt
+    enabled: true
+    key: /mnt/elastic-internal/http-certs/tls.key
 `,
 			wantErr: false,
 		},
@@ -33,6 +33,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/deployment"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/initreport"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
@@ -53,7 +54,7 @@ const (
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, params, &emsv1alpha1.ElasticMapsServer{})
 	if err != nil {
 		return err
 	}
@@ -86,6 +87,11 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileMapsSe
 		return err
 	}
 
+	// Watch HorizontalPodAutoscalers
+	if err := watches.WatchHorizontalPodAutoscalers(mgr, c, &emsv1alpha1.ElasticMapsServer{}); err != nil {
+		return err
+	}
+
 	// Watch Pods, to ensure `status.version` and version upgrades are correctly reconciled on any change.
 	// Watching Deployments only may lead to missing some events.
 	if err := watches.WatchPods(mgr, c, NameLabelName); err != nil {
@@ -275,6 +281,10 @@ func (r *ReconcileMapsServer) doReconcile(ctx context.Context, ems emsv1alpha1.E
 	if err != nil {
 		return results.WithError(fmt.Errorf("calculating status: %w", err)), status
 	}
+	if status.BasemapData != nil && status.BasemapData.Phase == emsv1alpha1.BasemapDataPendingPhase {
+		// Pods are not updated while the basemap data is downloaded, requeue to report its outcome
+		results.WithRequeue(basemapDataPendingRequeue)
+	}
 
 	return results, status
 }
@@ -360,7 +370,14 @@ func (r *ReconcileMapsServer) reconcileDeployment(
 		return appsv1.Deployment{}, err
 	}
 	deploy := deployment.New(deployParams)
-	return deployment.Reconcile(ctx, r.K8sClient(), deploy, &ems)
+	reconciled, err := deployment.Reconcile(ctx, r.K8sClient(), deploy, &ems)
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+	if err := deployment.ReconcileHorizontalPodAutoscaler(ctx, r.K8sClient(), deploy, ems.Spec.Autoscaling, meta, &ems); err != nil {
+		return appsv1.Deployment{}, err
+	}
+	return reconciled, nil
 }
 
 func (r *ReconcileMapsServer) deploymentParams(ems emsv1alpha1.ElasticMapsServer, configHash string, meta metadata.Metadata) (deployment.Params, error) {
@@ -379,6 +396,7 @@ func (r *ReconcileMapsServer) deploymentParams(ems emsv1alpha1.ElasticMapsServer
 		Name:            Deployment(ems.Name),
 		Namespace:       ems.Namespace,
 		Replicas:        ems.Spec.Count,
+		Autoscaled:      ems.Spec.Autoscaling != nil,
 		Selector:        deploymentLabels,
 		Metadata:        meta,
 		PodTemplateSpec: podSpec,
@@ -398,6 +416,11 @@ func (r *ReconcileMapsServer) getStatus(ctx context.Context, ems emsv1alpha1.Ela
 	}
 	status.DeploymentStatus = deploymentStatus
 	status.AssociationStatus = ems.Status.AssociationStatus
+	if ems.Spec.BasemapData == nil {
+		status.BasemapData = nil
+	} else if basemapStatus := basemapDataStatus(initreport.FromPods(pods, BasemapDataContainerName)); basemapStatus != nil {
+		status.BasemapData = basemapStatus
+	}
 
 	return status, nil
 }
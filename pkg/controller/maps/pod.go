@@ -78,11 +78,15 @@ func newPodSpec(ems emsv1alpha1.ElasticMapsServer, configHash string, meta metad
 		WithAnnotations(podMeta.Annotations).
 		WithLabels(podMeta.Labels).
 		WithResources(DefaultResources).
-		WithDockerImage(ems.Spec.Image, container.ImageRepository(container.MapsImage, v)).
+		WithDockerImage(ems.Spec.Image, container.ImageRepository(ems.Namespace, container.MapsImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(ems.Namespace)...).
 		WithReadinessProbe(readinessProbe(ems.Spec.HTTP.TLS.Enabled())).
 		WithPorts(defaultContainerPorts).
 		WithVolumes(cfgVolume.Volume(), logsVolume.Volume()).
-		WithVolumeMounts(cfgVolume.VolumeMount(), logsVolume.VolumeMount()).
+		WithVolumeMounts(cfgVolume.VolumeMount(), logsVolume.VolumeMount())
+
+	// Add the basemap data before applying the init containers defaults
+	builder = withBasemapData(builder, ems).
 		WithInitContainerDefaults()
 
 	if setDefaultSecurityContext {
@@ -0,0 +1,211 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"fmt"
+	"hash"
+	"maps"
+	"path"
+
+	"github.com/elastic/go-ucfg"
+	"github.com/ghodss/yaml"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/apmserver"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/labels"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+)
+
+const (
+	// ElasticsearchExporterName is the name of the exporter configured by the operator to send data to the
+	// referenced Elasticsearch cluster.
+	ElasticsearchExporterName = "elasticsearch"
+	// ApmServerExporterName is the name of the exporter configured by the operator to send data to the referenced
+	// APM Server through its OTLP/HTTP intake.
+	ApmServerExporterName = "otlphttp/apm"
+)
+
+// configOptions are the options used to parse and merge collector configurations. Unlike the settings of the Elastic
+// Stack applications, dots in keys are not path separators: collector configurations commonly use dotted attribute
+// names as keys. Lists provided by the user replace the ones set by the operator.
+var configOptions = []ucfg.Option{ucfg.ReplaceArrValues}
+
+func reconcileConfig(params Params, configHash hash.Hash) *reconciler.Results {
+	defer tracing.Span(&params.Context)()
+	results := reconciler.NewResult(params.Context)
+
+	cfgBytes, err := buildConfig(params)
+	if err != nil {
+		return results.WithError(err)
+	}
+
+	expected := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   params.Collector.Namespace,
+			Name:        ConfigSecretName(params.Collector.Name),
+			Labels:      labels.AddCredentialsLabel(maps.Clone(params.Meta.Labels)),
+			Annotations: params.Meta.Annotations,
+		},
+		Data: map[string][]byte{
+			ConfigFileName: cfgBytes,
+		},
+	}
+
+	if _, err = reconciler.ReconcileSecret(params.Context, params.Client, expected, &params.Collector); err != nil {
+		return results.WithError(err)
+	}
+
+	_, _ = configHash.Write(cfgBytes)
+
+	return results
+}
+
+// buildConfig merges the user provided configuration on top of the exporters configured by the operator, so that
+// users can reference the operator managed exporters in their pipelines and override any of their settings.
+func buildConfig(params Params) ([]byte, error) {
+	exporters, err := buildExportersConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ucfg.NewFrom(exporters, configOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	userConfig, err := getUserConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	if userConfig != nil {
+		if err := cfg.Merge(userConfig, configOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	var rendered map[string]any
+	if err := cfg.Unpack(&rendered, configOptions...); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(rendered)
+}
+
+// buildExportersConfig returns the configuration of the exporters sending data to the referenced Elasticsearch cluster
+// and APM Server, if any.
+func buildExportersConfig(params Params) (map[string]any, error) {
+	exporters := map[string]any{}
+	esExporter, err := buildElasticsearchExporterConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	if esExporter != nil {
+		exporters[ElasticsearchExporterName] = esExporter
+	}
+	apmExporter, err := buildApmServerExporterConfig(params)
+	if err != nil {
+		return nil, err
+	}
+	if apmExporter != nil {
+		exporters[ApmServerExporterName] = apmExporter
+	}
+	if len(exporters) == 0 {
+		return map[string]any{}, nil
+	}
+	return map[string]any{"exporters": exporters}, nil
+}
+
+// buildElasticsearchExporterConfig returns the configuration of the Elasticsearch exporter, or nil if there is no
+// configured association with an Elasticsearch cluster.
+func buildElasticsearchExporterConfig(params Params) (map[string]any, error) {
+	if !params.Collector.Spec.ElasticsearchRef.IsSet() {
+		return nil, nil
+	}
+	assoc := params.Collector.EsAssociation()
+	assocConf, err := assoc.AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if !assocConf.IsConfigured() {
+		return nil, nil
+	}
+
+	credentials, err := association.ElasticsearchAuthSettings(params.Context, params.Client, assoc)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := map[string]any{
+		"endpoints": []string{assocConf.GetURL()},
+	}
+	if credentials.APIKey != "" {
+		// the exporter expects the base64 encoded id:api_key pair, as stored in the association Secret
+		exporter["api_key"] = credentials.APIKey
+	} else {
+		exporter["user"] = credentials.Username
+		exporter["password"] = credentials.Password
+	}
+	if assocConf.GetCACertProvided() {
+		exporter["tls"] = map[string]any{
+			"ca_file": path.Join(certificatesDir(assoc), CAFileName),
+		}
+	}
+	return exporter, nil
+}
+
+// buildApmServerExporterConfig returns the configuration of the OTLP/HTTP exporter authenticating with the secret token
+// of the APM Server, or nil if there is no configured association with an APM Server.
+func buildApmServerExporterConfig(params Params) (map[string]any, error) {
+	if !params.Collector.Spec.ApmServerRef.IsSet() {
+		return nil, nil
+	}
+	assoc := params.Collector.ApmAssociation()
+	assocConf, err := assoc.AssociationConf()
+	if err != nil {
+		return nil, err
+	}
+	if !assocConf.IsConfigured() {
+		return nil, nil
+	}
+
+	// the Secret holding the token is copied into the namespace of the collector by the association controller
+	var tokenSecret corev1.Secret
+	tokenKey := types.NamespacedName{Namespace: params.Collector.Namespace, Name: apmserver.SecretToken(assoc.AssociationRef().GetName())}
+	if err := params.Client.Get(params.Context, tokenKey, &tokenSecret); err != nil {
+		return nil, err
+	}
+	token, exists := tokenSecret.Data[apmserver.SecretTokenKey]
+	if !exists {
+		return nil, fmt.Errorf("secret token not found in Secret %s", tokenKey)
+	}
+
+	exporter := map[string]any{
+		"endpoint": assocConf.GetURL(),
+		"headers": map[string]any{
+			"Authorization": "Bearer " + string(token),
+		},
+	}
+	if assocConf.GetCACertProvided() {
+		exporter["tls"] = map[string]any{
+			"ca_file": path.Join(certificatesDir(assoc), CAFileName),
+		}
+	}
+	return exporter, nil
+}
+
+// getUserConfig extracts the config either from the spec `config` field or from the Secret referenced by spec
+// `configRef` field, or returns nil if none is set.
+func getUserConfig(params Params) (*ucfg.Config, error) {
+	if params.Collector.Spec.Config != nil {
+		return ucfg.NewFrom(params.Collector.Spec.Config.Data, configOptions...)
+	}
+	return common.ParseConfigRefToConfig(
+		params, &params.Collector, params.Collector.Spec.ConfigRef, ConfigFileName, common.ConfigRefWatchName, configOptions,
+	)
+}
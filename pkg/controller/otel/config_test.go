@@ -0,0 +1,260 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+const userConfig = `
+processors:
+  resourcedetection:
+    detectors: [system]
+    system:
+      resource_attributes:
+        host.name:
+          enabled: true
+exporters:
+  elasticsearch:
+    mapping:
+      mode: otel
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [resourcedetection]
+      exporters: [elasticsearch]
+`
+
+func collectorFixture(mutate func(*otelv1alpha1.OTelCollector)) otelv1alpha1.OTelCollector {
+	c := otelv1alpha1.OTelCollector{
+		ObjectMeta: metav1.ObjectMeta{Name: "collector", Namespace: "ns"},
+		Spec: otelv1alpha1.OTelCollectorSpec{
+			Version:    "9.1.0",
+			Deployment: &otelv1alpha1.DeploymentSpec{},
+		},
+	}
+	if mutate != nil {
+		mutate(&c)
+	}
+	return c
+}
+
+func withESRef(conf commonv1.AssociationConf) func(*otelv1alpha1.OTelCollector) {
+	return func(c *otelv1alpha1.OTelCollector) {
+		c.Spec.ElasticsearchRef = commonv1.ObjectSelector{Name: "es", Namespace: "ns"}
+		c.EsAssociation().SetAssociationConf(&conf)
+	}
+}
+
+func withApmRef(conf commonv1.AssociationConf) func(*otelv1alpha1.OTelCollector) {
+	return func(c *otelv1alpha1.OTelCollector) {
+		c.Spec.ApmServerRef = commonv1.ObjectSelector{Name: "apm", Namespace: "apm-ns"}
+		c.ApmAssociation().SetAssociationConf(&conf)
+	}
+}
+
+func withUserConfig(c *otelv1alpha1.OTelCollector) {
+	var data map[string]any
+	if err := yaml.Unmarshal([]byte(userConfig), &data); err != nil {
+		panic(err)
+	}
+	c.Spec.Config = &commonv1.Config{Data: data}
+}
+
+func Test_buildConfig(t *testing.T) {
+	authSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "collector-otel-es-user", Namespace: "ns"},
+		Data:       map[string][]byte{"ns-collector-otel-user": []byte("secret")},
+	}
+	apiKeySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "es-api-key", Namespace: "ns"},
+		Data:       map[string][]byte{"api-key": []byte("aWQ6a2V5")},
+	}
+	configRefSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "otel-config", Namespace: "ns"},
+		Data:       map[string][]byte{ConfigFileName: []byte(userConfig)},
+	}
+	apmTokenSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "apm-apm-token", Namespace: "ns"},
+		Data:       map[string][]byte{"secret-token": []byte("token")},
+	}
+	apmConf := commonv1.AssociationConf{
+		AuthSecretName: commonv1.NoAuthRequiredValue,
+		CACertProvided: true,
+		CASecretName:   "collector-otel-apm-ca",
+		URL:            "https://apm-apm-http.apm-ns.svc:8200",
+		Version:        "9.1.0",
+	}
+	userAuthConf := commonv1.AssociationConf{
+		AuthSecretName: "collector-otel-es-user",
+		AuthSecretKey:  "ns-collector-otel-user",
+		CACertProvided: true,
+		CASecretName:   "collector-otel-es-ca",
+		URL:            "https://es-es-http.ns.svc:9200",
+		Version:        "9.1.0",
+	}
+
+	tests := []struct {
+		name      string
+		collector otelv1alpha1.OTelCollector
+		client    k8s.Client
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "no Elasticsearch reference and no config",
+			collector: collectorFixture(nil),
+			client:    k8s.NewFakeClient(),
+			want:      `{}`,
+		},
+		{
+			name:      "user config only, keeping dotted keys",
+			collector: collectorFixture(withUserConfig),
+			client:    k8s.NewFakeClient(),
+			want:      userConfig,
+		},
+		{
+			name: "Elasticsearch reference with basic auth and CA, merged with the user config",
+			collector: collectorFixture(func(c *otelv1alpha1.OTelCollector) {
+				withESRef(userAuthConf)(c)
+				withUserConfig(c)
+			}),
+			client: k8s.NewFakeClient(authSecret),
+			want: `
+processors:
+  resourcedetection:
+    detectors: [system]
+    system:
+      resource_attributes:
+        host.name:
+          enabled: true
+exporters:
+  elasticsearch:
+    endpoints: ["https://es-es-http.ns.svc:9200"]
+    user: ns-collector-otel-user
+    password: secret
+    tls:
+      ca_file: /mnt/elastic-internal/elasticsearch-association/ns/es/certs/ca.crt
+    mapping:
+      mode: otel
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [resourcedetection]
+      exporters: [elasticsearch]
+`,
+		},
+		{
+			name: "Elasticsearch reference with an API key and a config from a Secret",
+			collector: collectorFixture(func(c *otelv1alpha1.OTelCollector) {
+				withESRef(commonv1.AssociationConf{
+					AuthSecretName: "es-api-key",
+					AuthSecretKey:  "api-key",
+					IsAPIKey:       true,
+					URL:            "https://es.example.com:443",
+					Version:        "9.1.0",
+				})(c)
+				c.Spec.ConfigRef = &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "otel-config"}}
+			}),
+			client: k8s.NewFakeClient(apiKeySecret, configRefSecret),
+			want: `
+processors:
+  resourcedetection:
+    detectors: [system]
+    system:
+      resource_attributes:
+        host.name:
+          enabled: true
+exporters:
+  elasticsearch:
+    endpoints: ["https://es.example.com:443"]
+    api_key: aWQ6a2V5
+    mapping:
+      mode: otel
+service:
+  pipelines:
+    logs:
+      receivers: [otlp]
+      processors: [resourcedetection]
+      exporters: [elasticsearch]
+`,
+		},
+		{
+			name: "Elasticsearch and APM Server references",
+			collector: collectorFixture(func(c *otelv1alpha1.OTelCollector) {
+				withESRef(userAuthConf)(c)
+				withApmRef(apmConf)(c)
+			}),
+			client: k8s.NewFakeClient(authSecret, apmTokenSecret),
+			want: `
+exporters:
+  elasticsearch:
+    endpoints: ["https://es-es-http.ns.svc:9200"]
+    user: ns-collector-otel-user
+    password: secret
+    tls:
+      ca_file: /mnt/elastic-internal/elasticsearch-association/ns/es/certs/ca.crt
+  otlphttp/apm:
+    endpoint: https://apm-apm-http.apm-ns.svc:8200
+    headers:
+      Authorization: Bearer token
+    tls:
+      ca_file: /mnt/elastic-internal/apm-association/apm-ns/apm/certs/ca.crt
+`,
+		},
+		{
+			name:      "APM Server reference without secret token",
+			collector: collectorFixture(withApmRef(apmConf)),
+			client:    k8s.NewFakeClient(),
+			wantErr:   true,
+		},
+		{
+			name:      "Elasticsearch reference without auth Secret",
+			collector: collectorFixture(withESRef(userAuthConf)),
+			client:    k8s.NewFakeClient(),
+			wantErr:   true,
+		},
+		{
+			name: "missing config Secret",
+			collector: collectorFixture(func(c *otelv1alpha1.OTelCollector) {
+				c.Spec.ConfigRef = &commonv1.ConfigSource{SecretRef: commonv1.SecretRef{SecretName: "otel-config"}}
+			}),
+			client:  k8s.NewFakeClient(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildConfig(Params{
+				Context:   context.Background(),
+				Client:    tt.client,
+				Watches:   watches.NewDynamicWatches(),
+				Collector: tt.collector,
+			})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			var gotConfig, wantConfig map[string]any
+			require.NoError(t, yaml.Unmarshal(got, &gotConfig))
+			require.NoError(t, yaml.Unmarshal([]byte(tt.want), &wantConfig))
+			require.Equal(t, wantConfig, gotConfig)
+		})
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	toolsevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	logconf "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	controllerName = "otel-collector-controller"
+)
+
+// Add creates a new OpenTelemetry Collector Controller and adds it to the Manager with default RBAC. The Manager will
+// set fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, params operator.Parameters) error {
+	r := newReconciler(mgr, params)
+	c, err := common.NewController(mgr, controllerName, r, params, &otelv1alpha1.OTelCollector{})
+	if err != nil {
+		return err
+	}
+	return addWatches(mgr, c, r)
+}
+
+// newReconciler returns a new reconcile.Reconciler.
+func newReconciler(mgr manager.Manager, params operator.Parameters) *ReconcileOTelCollector {
+	client := mgr.GetClient()
+	return &ReconcileOTelCollector{
+		Client:         client,
+		recorder:       mgr.GetEventRecorder(controllerName),
+		dynamicWatches: watches.NewDynamicWatches(),
+		Parameters:     params,
+	}
+}
+
+// addWatches adds watches for all resources this controller cares about
+func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcileOTelCollector) error {
+	// Watch for changes to OTelCollector
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &otelv1alpha1.OTelCollector{}, &handler.TypedEnqueueRequestForObject[*otelv1alpha1.OTelCollector]{})); err != nil {
+		return err
+	}
+
+	// Watch DaemonSets
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &appsv1.DaemonSet{},
+			handler.TypedEnqueueRequestForOwner[*appsv1.DaemonSet](mgr.GetScheme(), mgr.GetRESTMapper(),
+				&otelv1alpha1.OTelCollector{}, handler.OnlyControllerOwner()),
+		)); err != nil {
+		return err
+	}
+
+	// Watch Deployments
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &appsv1.Deployment{},
+			handler.TypedEnqueueRequestForOwner[*appsv1.Deployment](mgr.GetScheme(), mgr.GetRESTMapper(),
+				&otelv1alpha1.OTelCollector{}, handler.OnlyControllerOwner()),
+		)); err != nil {
+		return err
+	}
+
+	// Watch StatefulSets
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &appsv1.StatefulSet{},
+			handler.TypedEnqueueRequestForOwner[*appsv1.StatefulSet](mgr.GetScheme(), mgr.GetRESTMapper(),
+				&otelv1alpha1.OTelCollector{}, handler.OnlyControllerOwner()),
+		)); err != nil {
+		return err
+	}
+
+	// Watch Pods, to ensure `status.version` is correctly reconciled on any change.
+	// Watching DaemonSets, Deployments or StatefulSets only may lead to missing some events.
+	if err := watches.WatchPods(mgr, c, otelv1alpha1.NameLabelName); err != nil {
+		return err
+	}
+
+	// Watch Secrets
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &corev1.Secret{},
+			handler.TypedEnqueueRequestForOwner[*corev1.Secret](mgr.GetScheme(), mgr.GetRESTMapper(),
+				&otelv1alpha1.OTelCollector{}, handler.OnlyControllerOwner()),
+		)); err != nil {
+		return err
+	}
+
+	// Watch dynamically referenced Secrets
+	return c.Watch(
+		source.Kind(mgr.GetCache(), &corev1.Secret{},
+			r.dynamicWatches.Secrets,
+		))
+}
+
+var _ reconcile.Reconciler = (*ReconcileOTelCollector)(nil)
+
+// ReconcileOTelCollector reconciles an OTelCollector object
+type ReconcileOTelCollector struct {
+	k8s.Client
+	recorder       toolsevents.EventRecorder
+	dynamicWatches watches.DynamicWatches
+	operator.Parameters
+	// iteration is the number of times this controller has run its Reconcile method
+	iteration uint64
+}
+
+// Reconcile reads that state of the cluster for an OTelCollector object and makes changes based on the state read
+// and what is in the OTelCollector.Spec
+func (r *ReconcileOTelCollector) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	ctx = common.NewReconciliationContext(ctx, &r.iteration, r.Tracer, controllerName, "otel_collector_name", request)
+	defer common.LogReconciliationRun(logconf.FromContext(ctx))()
+	defer tracing.EndContextTransaction(ctx)
+
+	collector := &otelv1alpha1.OTelCollector{}
+	if err := r.Client.Get(ctx, request.NamespacedName, collector); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, r.onDelete(ctx, request.NamespacedName)
+		}
+		return reconcile.Result{}, tracing.CaptureError(ctx, err)
+	}
+
+	if common.IsUnmanaged(ctx, collector) {
+		logconf.FromContext(ctx).Info("Object is currently not managed by this controller. Skipping reconciliation")
+		return reconcile.Result{}, nil
+	}
+
+	if collector.IsMarkedForDeletion() {
+		return reconcile.Result{}, r.onDelete(ctx, request.NamespacedName)
+	}
+
+	results, status := r.doReconcile(ctx, *collector)
+
+	if err := updateStatus(ctx, *collector, r.Client, status); err != nil {
+		if apierrors.IsConflict(err) {
+			return results.WithRequeue().Aggregate()
+		}
+		results = results.WithError(err)
+	}
+
+	result, err := results.Aggregate()
+	k8s.MaybeEmitErrorEventf(r.recorder, err, collector, events.EventReconciliationError, events.EventActionReconciliation, "Reconciliation error: %v", err)
+
+	return result, err
+}
+
+func (r *ReconcileOTelCollector) doReconcile(ctx context.Context, collector otelv1alpha1.OTelCollector) (*reconciler.Results, otelv1alpha1.OTelCollectorStatus) {
+	defer tracing.Span(&ctx)()
+	results := reconciler.NewResult(ctx)
+	status := newStatus(collector)
+
+	areAssocsConfigured, err := association.AreConfiguredIfSet(ctx, collector.GetAssociations(), r.recorder)
+	if err != nil {
+		return results.WithError(err), status
+	}
+	if !areAssocsConfigured {
+		return results, status
+	}
+
+	// Run basic validations as a fallback in case webhook is disabled.
+	if err := r.validate(ctx, collector); err != nil {
+		results = results.WithError(err)
+		return results, status
+	}
+	collectorVersion, err := version.Parse(collector.Spec.Version)
+	if err != nil {
+		return results.WithError(err), status
+	}
+
+	// extract the metadata that should be propagated to children
+	meta := metadata.Propagate(&collector, metadata.Metadata{Labels: collector.GetIdentityLabels()})
+
+	return internalReconcile(Params{
+		Context:          ctx,
+		Meta:             meta,
+		Client:           r.Client,
+		EventRecorder:    r.recorder,
+		Watches:          r.dynamicWatches,
+		Collector:        collector,
+		CollectorVersion: collectorVersion,
+		Status:           status,
+		OperatorParams:   r.Parameters,
+	})
+}
+
+func (r *ReconcileOTelCollector) validate(ctx context.Context, collector otelv1alpha1.OTelCollector) error {
+	defer tracing.Span(&ctx)()
+
+	// Run create validations only as update validations require old object which we don't have here.
+	if _, err := collector.ValidateCreate(); err != nil {
+		logconf.FromContext(ctx).Error(err, "Validation failed")
+		k8s.MaybeEmitErrorEvent(r.recorder, err, &collector, events.EventReasonValidation, events.EventActionValidation, err.Error())
+		return tracing.CaptureError(ctx, err)
+	}
+	return nil
+}
+
+func (r *ReconcileOTelCollector) onDelete(ctx context.Context, obj types.NamespacedName) error {
+	r.dynamicWatches.Secrets.RemoveHandlerForKey(common.ConfigRefWatchName(obj))
+	return reconciler.GarbageCollectSoftOwnedSecrets(ctx, r.Client, obj, otelv1alpha1.Kind)
+}
@@ -0,0 +1,87 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	toolsevents "k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func newReconcileOTelCollector(objs ...client.Object) *ReconcileOTelCollector {
+	return &ReconcileOTelCollector{
+		Client:         k8s.NewFakeClient(objs...),
+		recorder:       toolsevents.NewFakeRecorder(100),
+		dynamicWatches: watches.NewDynamicWatches(),
+	}
+}
+
+func TestReconcileOTelCollector_Reconcile(t *testing.T) {
+	collector := collectorFixture(withUserConfig)
+	collector.Generation = 2
+	r := newReconcileOTelCollector(&collector)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "collector"}}
+	ctx := context.Background()
+
+	_, err := r.Reconcile(ctx, request)
+	require.NoError(t, err)
+
+	// the configuration is rendered into a Secret mounted in the Pods of a Deployment
+	var configSecret corev1.Secret
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "collector-otel-config"}, &configSecret))
+	require.Contains(t, string(configSecret.Data[ConfigFileName]), "host.name")
+
+	var deploy appsv1.Deployment
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "collector-otel"}, &deploy))
+	podSpec := deploy.Spec.Template.Spec
+	require.Len(t, podSpec.Containers, 1)
+	require.Equal(t, otelv1alpha1.OTelCollectorContainerName, podSpec.Containers[0].Name)
+	require.Equal(t, container.ImageRepository("ns", container.EDOTCollectorImage, version.MustParse("9.1.0")), podSpec.Containers[0].Image)
+	require.Equal(t, []string{"--config", "/etc/otel-collector/otel.yml"}, podSpec.Containers[0].Args)
+	require.Contains(t, deploy.Spec.Template.Annotations, ConfigHashAnnotationName)
+	require.Equal(t, "9.1.0", deploy.Spec.Template.Labels[VersionLabelName])
+
+	var updated otelv1alpha1.OTelCollector
+	require.NoError(t, r.Client.Get(ctx, request.NamespacedName, &updated))
+	require.Equal(t, int64(2), updated.Status.ObservedGeneration)
+	require.Equal(t, otelv1alpha1.OTelCollectorRedHealth, updated.Status.Health)
+
+	// switching to a DaemonSet removes the Deployment
+	updated.Spec.Deployment = nil
+	updated.Spec.DaemonSet = &otelv1alpha1.DaemonSetSpec{}
+	require.NoError(t, r.Client.Update(ctx, &updated))
+	_, err = r.Reconcile(ctx, request)
+	require.NoError(t, err)
+
+	var ds appsv1.DaemonSet
+	require.NoError(t, r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "collector-otel"}, &ds))
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: "ns", Name: "collector-otel"}, &deploy)
+	require.True(t, apierrors.IsNotFound(err))
+}
+
+func TestReconcileOTelCollector_ReconcileInvalid(t *testing.T) {
+	collector := collectorFixture(func(c *otelv1alpha1.OTelCollector) {
+		c.Spec.Deployment = nil
+	})
+	r := newReconcileOTelCollector(&collector)
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "ns", Name: "collector"}}
+
+	_, err := r.Reconcile(context.Background(), request)
+	require.Error(t, err)
+}
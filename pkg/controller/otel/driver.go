@@ -0,0 +1,112 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"hash/fnv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	toolsevents "k8s.io/client-go/tools/events"
+
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/association"
+	commonassociation "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/association"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/watches"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+// Params are a set of parameters used during internal reconciliation of OpenTelemetry Collectors.
+type Params struct {
+	Context context.Context
+
+	Meta metadata.Metadata
+
+	Client        k8s.Client
+	EventRecorder toolsevents.EventRecorder
+	Watches       watches.DynamicWatches
+
+	Collector otelv1alpha1.OTelCollector
+	// CollectorVersion is a convenience field to avoid parsing the version string multiple times.
+	CollectorVersion version.Version
+	Status           otelv1alpha1.OTelCollectorStatus
+
+	OperatorParams operator.Parameters
+}
+
+// K8sClient returns the Kubernetes client.
+func (p Params) K8sClient() k8s.Client {
+	return p.Client
+}
+
+// Recorder returns the Kubernetes event recorder.
+func (p Params) Recorder() toolsevents.EventRecorder {
+	return p.EventRecorder
+}
+
+// DynamicWatches returns the set of stateful dynamic watches used during reconciliation.
+func (p Params) DynamicWatches() watches.DynamicWatches {
+	return p.Watches
+}
+
+// GetPodTemplate returns the configured pod template for the associated OpenTelemetry Collector.
+func (p *Params) GetPodTemplate() corev1.PodTemplateSpec {
+	switch {
+	case p.Collector.Spec.DaemonSet != nil:
+		return p.Collector.Spec.DaemonSet.PodTemplate
+	case p.Collector.Spec.StatefulSet != nil:
+		return p.Collector.Spec.StatefulSet.PodTemplate
+	default:
+		return p.Collector.Spec.Deployment.PodTemplate
+	}
+}
+
+// Logger returns the configured logger for use during reconciliation.
+func (p *Params) Logger() logr.Logger {
+	return log.FromContext(p.Context)
+}
+
+func newStatus(collector otelv1alpha1.OTelCollector) otelv1alpha1.OTelCollectorStatus {
+	status := collector.Status
+	status.ObservedGeneration = collector.Generation
+	return status
+}
+
+func internalReconcile(params Params) (*reconciler.Results, otelv1alpha1.OTelCollectorStatus) {
+	defer tracing.Span(&params.Context)()
+	results := reconciler.NewResult(params.Context)
+
+	assocAllowed, err := association.AllowVersion(params.CollectorVersion, &params.Collector, params.Logger(), params.EventRecorder)
+	if err != nil {
+		return results.WithError(err), params.Status
+	}
+	if !assocAllowed {
+		return results, params.Status // will eventually retry
+	}
+
+	configHash := fnv.New32a()
+	if res := reconcileConfig(params, configHash); res.HasError() {
+		return results.WithResults(res), params.Status
+	}
+
+	// we need to deref the secret here (if any) to include it in the configHash otherwise the collector will not be
+	// rolled on content changes
+	if err := commonassociation.WriteAssocsToConfigHash(params.Client, params.Collector.GetAssociations(), configHash); err != nil {
+		return results.WithError(err), params.Status
+	}
+
+	podTemplate, err := buildPodTemplate(params, configHash)
+	if err != nil {
+		return results.WithError(err), params.Status
+	}
+	return reconcilePodVehicle(params, podTemplate)
+}
@@ -0,0 +1,37 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+)
+
+// CalculateHealth returns health of the OpenTelemetry Collector based on association status, desired count and ready count.
+func CalculateHealth(associations []commonv1.Association, ready, desired int32) (otelv1alpha1.OTelCollectorHealth, error) {
+	for _, assoc := range associations {
+		assocConf, err := assoc.AssociationConf()
+		if err != nil {
+			return "", err
+		}
+		if assocConf.IsConfigured() {
+			statusMap := assoc.AssociationStatusMap(assoc.AssociationType())
+			if !statusMap.AllEstablished() {
+				return otelv1alpha1.OTelCollectorRedHealth, nil
+			}
+		}
+	}
+
+	switch {
+	case ready == 0:
+		return otelv1alpha1.OTelCollectorRedHealth, nil
+	case ready == desired:
+		return otelv1alpha1.OTelCollectorGreenHealth, nil
+	case ready > 0:
+		return otelv1alpha1.OTelCollectorYellowHealth, nil
+	default:
+		return otelv1alpha1.OTelCollectorRedHealth, nil
+	}
+}
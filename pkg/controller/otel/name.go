@@ -0,0 +1,20 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import common_name "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/name"
+
+// Namer is a Namer that is configured with the defaults for resources related to an OpenTelemetry Collector resource.
+var Namer = common_name.NewNamer("otel")
+
+// ConfigSecretName returns the name of a secret used to store the OpenTelemetry Collector configuration.
+func ConfigSecretName(name string) string {
+	return Namer.Suffix(name, "config")
+}
+
+// Name returns the name of the DaemonSet, Deployment or StatefulSet of an OpenTelemetry Collector.
+func Name(name string) string {
+	return Namer.Suffix(name)
+}
@@ -0,0 +1,125 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"fmt"
+	"hash"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/container"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/volume"
+)
+
+const (
+	CAFileName = "ca.crt"
+
+	ConfigVolumeName = "config"
+	ConfigMountPath  = "/etc/otel-collector"
+	// ConfigFileName is the name of the config file projected into the collector Pods, and the name of the entry
+	// users must use in the Secret referenced by `configRef`.
+	ConfigFileName = "otel.yml"
+
+	// ConfigHashAnnotationName is an annotation used to store the OpenTelemetry Collector config hash.
+	ConfigHashAnnotationName = "otel.k8s.elastic.co/config-hash"
+
+	// VersionLabelName is a label used to track the version of an OpenTelemetry Collector Pod.
+	VersionLabelName = "otel.k8s.elastic.co/version"
+)
+
+var defaultResources = corev1.ResourceRequirements{
+	Limits: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("400Mi"),
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+	},
+	Requests: map[corev1.ResourceName]resource.Quantity{
+		corev1.ResourceMemory: resource.MustParse("400Mi"),
+		corev1.ResourceCPU:    resource.MustParse("200m"),
+	},
+}
+
+func buildPodTemplate(params Params, configHash hash.Hash32) (corev1.PodTemplateSpec, error) {
+	defer tracing.Span(&params.Context)()
+	spec := params.Collector.Spec
+	vols := []volume.VolumeLike{
+		volume.NewSecretVolume(
+			ConfigSecretName(params.Collector.Name),
+			ConfigVolumeName,
+			path.Join(ConfigMountPath, ConfigFileName),
+			ConfigFileName,
+			0440),
+	}
+
+	// all volumes with CAs of direct associations
+	caAssocVols, err := getVolumesFromAssociations(params.Collector.GetAssociations())
+	if err != nil {
+		return corev1.PodTemplateSpec{}, err
+	}
+	vols = append(vols, caAssocVols...)
+
+	podMeta := params.Meta.Merge(metadata.Metadata{
+		Labels:      map[string]string{VersionLabelName: spec.Version},
+		Annotations: map[string]string{ConfigHashAnnotationName: fmt.Sprint(configHash.Sum32())},
+	})
+	builder := defaults.NewPodTemplateBuilder(params.GetPodTemplate(), otelv1alpha1.OTelCollectorContainerName).
+		WithLabels(podMeta.Labels).
+		WithAnnotations(podMeta.Annotations).
+		WithDockerImage(spec.Image, container.ImageRepository(params.Collector.Namespace, container.EDOTCollectorImage, params.CollectorVersion)).
+		WithImagePullSecrets(container.ImagePullSecrets(params.Collector.Namespace)...).
+		WithArgs("--config", path.Join(ConfigMountPath, ConfigFileName)).
+		WithEnv(defaults.PodDownwardEnvVars()...).
+		WithResources(defaultResources).
+		WithAutomountServiceAccountToken().
+		WithVolumeLikes(vols...).
+		WithContainersSecurityContext(corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			Capabilities: &corev1.Capabilities{
+				Drop: []corev1.Capability{"ALL"},
+			},
+			Privileged:             ptr.To(false),
+			ReadOnlyRootFilesystem: ptr.To(true),
+		})
+
+	return builder.PodTemplate, nil
+}
+
+func getVolumesFromAssociations(associations []commonv1.Association) ([]volume.VolumeLike, error) {
+	var vols []volume.VolumeLike
+	for i, assoc := range associations {
+		assocConf, err := assoc.AssociationConf()
+		if err != nil {
+			return nil, err
+		}
+		if !assocConf.CAIsConfigured() {
+			// skip as there is no volume to mount if association has no CA configured
+			continue
+		}
+		vols = append(vols, volume.NewSecretVolumeWithMountPath(
+			assocConf.GetCASecretName(),
+			fmt.Sprintf("%s-certs-%d", assoc.AssociationType(), i),
+			certificatesDir(assoc),
+		))
+	}
+	return vols, nil
+}
+
+func certificatesDir(association commonv1.Association) string {
+	ref := association.AssociationRef()
+	return fmt.Sprintf(
+		"/mnt/elastic-internal/%s-association/%s/%s/certs",
+		association.AssociationType(),
+		ref.GetNamespace(),
+		ref.NameOrSecretName(),
+	)
+}
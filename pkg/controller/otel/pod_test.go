@@ -0,0 +1,76 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"hash/fnv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
+)
+
+func Test_buildPodTemplate(t *testing.T) {
+	collector := collectorFixture(func(c *otelv1alpha1.OTelCollector) {
+		withESRef(commonv1.AssociationConf{
+			AuthSecretName: "collector-otel-es-user",
+			AuthSecretKey:  "ns-collector-otel-user",
+			CACertProvided: true,
+			CASecretName:   "collector-otel-es-ca",
+			URL:            "https://es-es-http.ns.svc:9200",
+			Version:        "9.1.0",
+		})(c)
+		c.Spec.Image = "my-registry/edot:9.1.0"
+		c.Spec.Deployment.PodTemplate = corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: otelv1alpha1.OTelCollectorContainerName,
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+					},
+				}},
+			},
+		}
+	})
+
+	podTemplate, err := buildPodTemplate(Params{
+		Context:          context.Background(),
+		Collector:        collector,
+		CollectorVersion: version.MustParse(collector.Spec.Version),
+	}, fnv.New32a())
+	require.NoError(t, err)
+
+	require.Len(t, podTemplate.Spec.Containers, 1)
+	c := podTemplate.Spec.Containers[0]
+	require.Equal(t, "my-registry/edot:9.1.0", c.Image)
+	// user provided resources are kept
+	require.Equal(t, corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}}, c.Resources)
+	require.NotNil(t, c.SecurityContext)
+	require.True(t, *c.SecurityContext.ReadOnlyRootFilesystem)
+
+	mountPaths := make(map[string]string, len(c.VolumeMounts))
+	for _, m := range c.VolumeMounts {
+		mountPaths[m.Name] = m.MountPath
+	}
+	require.Equal(t, map[string]string{
+		ConfigVolumeName:        "/etc/otel-collector/otel.yml",
+		"elasticsearch-certs-0": "/mnt/elastic-internal/elasticsearch-association/ns/es/certs",
+	}, mountPaths)
+
+	secretNames := make(map[string]string, len(podTemplate.Spec.Volumes))
+	for _, v := range podTemplate.Spec.Volumes {
+		secretNames[v.Name] = v.Secret.SecretName
+	}
+	require.Equal(t, map[string]string{
+		ConfigVolumeName:        "collector-otel-config",
+		"elasticsearch-certs-0": "collector-otel-es-ca",
+	}, secretNames)
+}
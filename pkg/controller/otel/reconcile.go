@@ -0,0 +1,194 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package otel
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	otelv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/otel/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/daemonset"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/deployment"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/statefulset"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/pointer"
+)
+
+func reconcilePodVehicle(params Params, podTemplate corev1.PodTemplateSpec) (*reconciler.Results, otelv1alpha1.OTelCollectorStatus) {
+	defer tracing.Span(&params.Context)()
+	results := reconciler.NewResult(params.Context)
+
+	spec := params.Collector.Spec
+	objMeta := metav1.ObjectMeta{Name: Name(params.Collector.Name), Namespace: params.Collector.Namespace}
+
+	var toDelete []client.Object
+	var reconciliationFunc func(params ReconciliationParams) (int32, int32, error)
+	switch {
+	case spec.DaemonSet != nil:
+		reconciliationFunc = reconcileDaemonSet
+		toDelete = append(toDelete, &appsv1.Deployment{ObjectMeta: objMeta}, &appsv1.StatefulSet{ObjectMeta: objMeta})
+	case spec.Deployment != nil:
+		reconciliationFunc = reconcileDeployment
+		toDelete = append(toDelete, &appsv1.DaemonSet{ObjectMeta: objMeta}, &appsv1.StatefulSet{ObjectMeta: objMeta})
+	case spec.StatefulSet != nil:
+		reconciliationFunc = reconcileStatefulSet
+		toDelete = append(toDelete, &appsv1.DaemonSet{ObjectMeta: objMeta}, &appsv1.Deployment{ObjectMeta: objMeta})
+	}
+
+	ready, desired, err := reconciliationFunc(ReconciliationParams{
+		ctx:         params.Context,
+		meta:        params.Meta,
+		client:      params.Client,
+		collector:   params.Collector,
+		podTemplate: podTemplate,
+	})
+	if err != nil {
+		return results.WithError(err), params.Status
+	}
+
+	for _, obj := range toDelete {
+		// clean up the other ones
+		if err := params.Client.Get(params.Context, types.NamespacedName{
+			Namespace: objMeta.Namespace,
+			Name:      objMeta.Name,
+		}, obj); err == nil {
+			results.WithError(params.Client.Delete(params.Context, obj))
+		} else if !apierrors.IsNotFound(err) {
+			results.WithError(err)
+		}
+	}
+
+	var status otelv1alpha1.OTelCollectorStatus
+	if status, err = calculateStatus(&params, ready, desired); err != nil {
+		err = errors.Wrap(err, "while calculating status")
+	}
+
+	return results.WithError(err), status
+}
+
+func reconcileDeployment(rp ReconciliationParams) (int32, int32, error) {
+	d := deployment.New(deployment.Params{
+		Name:                 Name(rp.collector.Name),
+		Namespace:            rp.collector.Namespace,
+		Selector:             rp.collector.GetIdentityLabels(),
+		Metadata:             rp.meta,
+		PodTemplateSpec:      rp.podTemplate,
+		Replicas:             pointer.Int32OrDefault(rp.collector.Spec.Deployment.Replicas, int32(1)),
+		RevisionHistoryLimit: rp.collector.Spec.RevisionHistoryLimit,
+		Strategy:             rp.collector.Spec.Deployment.Strategy,
+	})
+	if err := controllerutil.SetControllerReference(&rp.collector, &d, scheme.Scheme); err != nil {
+		return 0, 0, err
+	}
+
+	reconciled, err := deployment.Reconcile(rp.ctx, rp.client, d, &rp.collector)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return reconciled.Status.ReadyReplicas, reconciled.Status.Replicas, nil
+}
+
+func reconcileStatefulSet(rp ReconciliationParams) (int32, int32, error) {
+	d := statefulset.New(statefulset.Params{
+		Name:                 Name(rp.collector.Name),
+		Namespace:            rp.collector.Namespace,
+		ServiceName:          rp.collector.Spec.StatefulSet.ServiceName,
+		Selector:             rp.collector.GetIdentityLabels(),
+		Metadata:             rp.meta,
+		PodTemplateSpec:      rp.podTemplate,
+		VolumeClaimTemplates: rp.collector.Spec.StatefulSet.VolumeClaimTemplates,
+		Replicas:             pointer.Int32OrDefault(rp.collector.Spec.StatefulSet.Replicas, int32(1)),
+		PodManagementPolicy:  rp.collector.Spec.StatefulSet.PodManagementPolicy,
+		RevisionHistoryLimit: rp.collector.Spec.RevisionHistoryLimit,
+	})
+	if err := controllerutil.SetControllerReference(&rp.collector, &d, scheme.Scheme); err != nil {
+		return 0, 0, err
+	}
+
+	reconciled, err := statefulset.Reconcile(rp.ctx, rp.client, d, &rp.collector)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return reconciled.Status.ReadyReplicas, reconciled.Status.Replicas, nil
+}
+
+func reconcileDaemonSet(rp ReconciliationParams) (int32, int32, error) {
+	ds := daemonset.New(daemonset.Params{
+		PodTemplate:          rp.podTemplate,
+		Name:                 Name(rp.collector.Name),
+		Owner:                &rp.collector,
+		Metadata:             rp.meta,
+		Selectors:            rp.collector.GetIdentityLabels(),
+		RevisionHistoryLimit: rp.collector.Spec.RevisionHistoryLimit,
+		Strategy:             rp.collector.Spec.DaemonSet.UpdateStrategy,
+	})
+
+	if err := controllerutil.SetControllerReference(&rp.collector, &ds, scheme.Scheme); err != nil {
+		return 0, 0, err
+	}
+
+	reconciled, err := daemonset.Reconcile(rp.ctx, rp.client, ds, &rp.collector)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return reconciled.Status.NumberReady, reconciled.Status.DesiredNumberScheduled, nil
+}
+
+// ReconciliationParams are the parameters used during an OpenTelemetry Collector's reconciliation.
+type ReconciliationParams struct {
+	ctx         context.Context
+	meta        metadata.Metadata
+	client      k8s.Client
+	collector   otelv1alpha1.OTelCollector
+	podTemplate corev1.PodTemplateSpec
+}
+
+// calculateStatus will calculate a new status from the state of the pods within the k8s cluster
+// and will return any error encountered.
+func calculateStatus(params *Params, ready, desired int32) (otelv1alpha1.OTelCollectorStatus, error) {
+	collector := params.Collector
+	status := params.Status
+
+	pods, err := k8s.PodsMatchingLabels(params.Client, collector.Namespace, map[string]string{otelv1alpha1.NameLabelName: collector.Name})
+	if err != nil {
+		return status, err
+	}
+
+	status.Version = common.LowestVersionFromPods(params.Context, status.Version, pods, VersionLabelName)
+	status.AvailableNodes = ready
+	status.ExpectedNodes = desired
+	health, err := CalculateHealth(collector.GetAssociations(), ready, desired)
+	if err != nil {
+		return status, err
+	}
+	status.Health = health
+	return status, nil
+}
+
+// updateStatus will update the OpenTelemetry Collector's status within the k8s cluster, using the given collector and status.
+func updateStatus(ctx context.Context, collector otelv1alpha1.OTelCollector, client client.Client, status otelv1alpha1.OTelCollectorStatus) error {
+	if reflect.DeepEqual(collector.Status, status) {
+		return nil
+	}
+	collector.Status = status
+	return common.UpdateStatus(ctx, client, &collector)
+}
@@ -69,7 +69,7 @@ func newConfig(d driver.Interface, epr eprv1alpha1.PackageRegistry) (*settings.C
 	if err != nil {
 		return cfg, err
 	}
-	defaults := defaultConfig()
+	defaults := defaultConfig(epr)
 
 	err = cfg.MergeWith(defaults, inlineUserCfg, refUserCfg)
 	return cfg, err
@@ -82,9 +82,15 @@ func inlineUserConfig(cfg *commonv1.Config) (*settings.CanonicalConfig, error) {
 	return settings.NewCanonicalConfigFrom(cfg.Data)
 }
 
-func defaultConfig() *settings.CanonicalConfig {
+// defaultConfig returns the default configuration of Elastic Package Registry. The package paths include the custom
+// package path when package sources are set, unless overridden by the user configuration.
+func defaultConfig(epr eprv1alpha1.PackageRegistry) *settings.CanonicalConfig {
+	packagePaths := []string{"/packages/package-registry", "/packages/package-storage"}
+	if len(epr.Spec.PackageSources) > 0 {
+		packagePaths = append(packagePaths, CustomPackagesPath)
+	}
 	return settings.MustCanonicalConfig(map[string]any{
-		"package_paths": []string{"/packages/package-registry", "/packages/package-storage"},
+		"package_paths": packagePaths,
 		"cache_time": map[string]any{
 			"index":      "10s",
 			"search":     "10m",
@@ -44,6 +44,28 @@ func Test_newConfig(t *testing.T) {
 package_paths:
     - /packages/package-registry
     - /packages/package-storage
+`,
+			wantErr: false,
+		},
+		{
+			name: "package sources",
+			args: args{
+				runtimeObjs: nil,
+				epr: v1alpha1.PackageRegistry{
+					Spec: v1alpha1.PackageRegistrySpec{PackageSources: []v1alpha1.PackageSource{
+						{Name: "internal", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "packages"}},
+					}},
+				},
+			},
+			want: `cache_time:
+    catch_all: 10m
+    categories: 10m
+    index: 10s
+    search: 10m
+package_paths:
+    - /packages/package-registry
+    - /packages/package-storage
+    - /packages/custom
 `,
 			wantErr: false,
 		},
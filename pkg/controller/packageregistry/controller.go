@@ -30,6 +30,7 @@ import (
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/deployment"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/driver"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/events"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/initreport"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/operator"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/reconciler"
@@ -49,7 +50,7 @@ const (
 // and start it when the manager is started.
 func Add(mgr manager.Manager, params operator.Parameters) error {
 	reconciler := newReconciler(mgr, params)
-	c, err := common.NewController(mgr, controllerName, reconciler, params)
+	c, err := common.NewController(mgr, controllerName, reconciler, params, &eprv1alpha1.PackageRegistry{})
 	if err != nil {
 		return err
 	}
@@ -80,6 +81,11 @@ func addWatches(mgr manager.Manager, c controller.Controller, r *ReconcilePackag
 		return err
 	}
 
+	// Watch HorizontalPodAutoscalers
+	if err := watches.WatchHorizontalPodAutoscalers(mgr, c, &eprv1alpha1.PackageRegistry{}); err != nil {
+		return err
+	}
+
 	// Watch Pods, to ensure `status.version` and version upgrades are correctly reconciled on any change.
 	// Watching Deployments only may lead to missing some events.
 	if err := watches.WatchPods(mgr, c, label.NameLabelName); err != nil {
@@ -233,6 +239,9 @@ func (r *ReconcilePackageRegistry) doReconcile(ctx context.Context, epr eprv1alp
 	if err != nil {
 		return results.WithError(err), status
 	}
+	if err := deployment.ReconcileHorizontalPodAutoscaler(ctx, r.K8sClient(), expectedDp, epr.Spec.Autoscaling, meta, &epr); err != nil {
+		return results.WithError(err), status
+	}
 
 	pods, err := k8s.PodsMatchingLabels(r.K8sClient(), epr.Namespace, map[string]string{label.NameLabelName: epr.Name})
 	if err != nil {
@@ -244,6 +253,12 @@ func (r *ReconcilePackageRegistry) doReconcile(ctx context.Context, epr eprv1alp
 	}
 	status.DeploymentStatus = deploymentStatus
 
+	if len(epr.Spec.PackageSources) == 0 {
+		status.Packages, status.InvalidPackages = nil, nil
+	} else if packages, invalidPackages, ok := packagesFromReport(initreport.FromPods(pods, ValidatePackagesContainerName)); ok {
+		status.Packages, status.InvalidPackages = packages, invalidPackages
+	}
+
 	return results, status
 }
 
@@ -320,6 +335,7 @@ func (r *ReconcilePackageRegistry) deploymentParams(epr eprv1alpha1.PackageRegis
 		Name:                 DeploymentName(epr.Name),
 		Namespace:            epr.Namespace,
 		Replicas:             epr.Spec.Count,
+		Autoscaled:           epr.Spec.Autoscaling != nil,
 		Selector:             deploymentLabels,
 		Metadata:             meta,
 		PodTemplateSpec:      podSpec,
@@ -0,0 +1,210 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package packageregistry
+
+import (
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	eprv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/packageregistry/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/defaults"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/initreport"
+)
+
+const (
+	// ValidatePackagesContainerName is the name of the init container validating the packages of the package sources.
+	ValidatePackagesContainerName = "validate-packages"
+	// CustomPackagesPath is the package path from which the validated packages are served.
+	CustomPackagesPath = "/packages/custom"
+	// PackageSourcesMountPath is the directory in which the package sources are mounted in the init container.
+	PackageSourcesMountPath = "/mnt/elastic-internal/package-sources"
+
+	// customPackagesVolumeName and packageSourceVolumePrefix must not collide, whatever the name of a package source.
+	customPackagesVolumeName  = "packages-custom"
+	packageSourceVolumePrefix = "package-source-"
+
+	// packageValidOutcome and packageInvalidOutcome prefix the lines of the report of the init container.
+	packageValidOutcome   = "ok "
+	packageInvalidOutcome = "invalid "
+)
+
+// validatePackagesScript validates each entry of the package sources and copies the valid ones into the custom
+// package path. An entry is either a package directory with a manifest.yml file, or a <name>-<version>.zip archive.
+// The outcome for each entry is written to the standard output of the container, and to its termination message to be
+// reported in the status. The termination message is size-limited: invalid entries are written first, and the entries
+// that do not fit are left out. Invalid entries are skipped rather than failing the Pod, so that a single broken package
+// does not make the whole registry unavailable.
+var validatePackagesScript = `#!/usr/bin/env bash
+
+set -u
+export LC_ALL=C
+
+invalid_entries=()
+valid_entries=()
+
+invalid() {
+	echo "invalid $1"
+	invalid_entries+=("invalid $1")
+}
+
+valid() {
+	echo "ok $1"
+	valid_entries+=("ok $1")
+}
+
+manifest_field() {
+	sed -n -e "s/^$1:[[:space:]]*//p" "$2" | head -n 1 | tr -d "\"' \r"
+}
+
+for source in ` + PackageSourcesMountPath + `/*; do
+	[ -d "$source" ] || continue
+	for entry in "$source"/*; do
+		[ -e "$entry" ] || continue
+		base=$(basename "$entry")
+		if [ -d "$entry" ]; then
+			manifest="$entry/manifest.yml"
+			if [ ! -f "$manifest" ]; then
+				invalid "$base: missing manifest.yml"
+				continue
+			fi
+			name=$(manifest_field name "$manifest")
+			version=$(manifest_field version "$manifest")
+			format_version=$(manifest_field format_version "$manifest")
+			if [[ ! "$name" =~ ^[a-z0-9_]+$ ]]; then
+				invalid "$base: invalid or missing name in manifest.yml"
+				continue
+			fi
+			if [ -z "$version" ] || [ -z "$format_version" ]; then
+				invalid "$base: missing version or format_version in manifest.yml"
+				continue
+			fi
+			target="` + CustomPackagesPath + `/$name/$version"
+			if [ -e "$target" ] || [ -e "` + CustomPackagesPath + `/$name-$version.zip" ]; then
+				invalid "$base: duplicate package $name-$version"
+				continue
+			fi
+			mkdir -p "` + CustomPackagesPath + `/$name" && cp -R "$entry" "$target" || {
+				invalid "$base: failed to copy package"
+				continue
+			}
+			valid "$name-$version"
+		elif [[ "$base" =~ ^([a-z0-9_]+)-([0-9][^/]*)\.zip$ ]]; then
+			name="${BASH_REMATCH[1]}"
+			version="${BASH_REMATCH[2]}"
+			if [ -e "` + CustomPackagesPath + `/$base" ] || [ -e "` + CustomPackagesPath + `/$name/$version" ]; then
+				invalid "$base: duplicate package $name-$version"
+				continue
+			fi
+			cp "$entry" "` + CustomPackagesPath + `/$base" || {
+				invalid "$base: failed to copy package"
+				continue
+			}
+			valid "$name-$version"
+		else
+			invalid "$base: not a package directory or a <name>-<version>.zip archive"
+		fi
+	done
+done
+
+report=/dev/termination-log
+: > "$report"
+size=0
+omitted=0
+for line in ${invalid_entries[@]+"${invalid_entries[@]}"} ${valid_entries[@]+"${valid_entries[@]}"}; do
+	# leave room for the line counting the omitted entries
+	if (( size + ${#line} + 1 > ` + strconv.Itoa(initreport.MaxReportBytes-64) + ` )); then
+		omitted=$((omitted + 1))
+		continue
+	fi
+	echo "$line" >> "$report"
+	size=$((size + ${#line} + 1))
+done
+if (( omitted > 0 )); then
+	echo "omitted $omitted entries" >> "$report"
+fi
+`
+
+// packageSourceVolumeName returns the name of the volume of the given package source.
+func packageSourceVolumeName(source eprv1alpha1.PackageSource) string {
+	return packageSourceVolumePrefix + source.Name
+}
+
+// packageSourceVolume returns the volume holding the packages of the given package source.
+func packageSourceVolume(source eprv1alpha1.PackageSource) corev1.Volume {
+	vol := corev1.Volume{Name: packageSourceVolumeName(source)}
+	switch {
+	case source.PersistentVolumeClaim != nil:
+		pvc := source.PersistentVolumeClaim.DeepCopy()
+		// packages are only ever read from the source
+		pvc.ReadOnly = true
+		vol.PersistentVolumeClaim = pvc
+	case source.Image != nil:
+		vol.Image = source.Image.DeepCopy()
+	}
+	return vol
+}
+
+// withPackageSources mounts the package sources into an init container validating the packages they hold, and
+// copying the valid ones into a volume shared with the main container. It must be called before the init containers
+// defaults are applied, for the init container to inherit the shared volume mount.
+func withPackageSources(builder *defaults.PodTemplateBuilder, epr eprv1alpha1.PackageRegistry) *defaults.PodTemplateBuilder {
+	if len(epr.Spec.PackageSources) == 0 {
+		return builder
+	}
+
+	volumes := []corev1.Volume{
+		{Name: customPackagesVolumeName, VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}
+	sourceMounts := make([]corev1.VolumeMount, 0, len(epr.Spec.PackageSources))
+	for _, source := range epr.Spec.PackageSources {
+		volumes = append(volumes, packageSourceVolume(source))
+		sourceMounts = append(sourceMounts, corev1.VolumeMount{
+			Name:      packageSourceVolumeName(source),
+			MountPath: path.Join(PackageSourcesMountPath, source.Name),
+			SubPath:   source.SubPath,
+			ReadOnly:  true,
+		})
+	}
+
+	return builder.
+		WithVolumes(volumes...).
+		WithVolumeMounts(corev1.VolumeMount{Name: customPackagesVolumeName, MountPath: CustomPackagesPath}).
+		WithInitContainers(corev1.Container{
+			Name:         ValidatePackagesContainerName,
+			Command:      []string{"/usr/bin/env", "bash", "-c", validatePackagesScript},
+			VolumeMounts: sourceMounts,
+		})
+}
+
+// packagesFromReport returns the valid and invalid packages of the given report of the validation init container,
+// and false if the init container has not completed successfully.
+func packagesFromReport(report *initreport.Report) ([]string, []string, bool) {
+	if report == nil || report.Phase != initreport.SucceededPhase {
+		return nil, nil, false
+	}
+	valid, invalid := parsePackagesReport(report.Lines)
+	return valid, invalid, true
+}
+
+// parsePackagesReport parses the lines of the report of the validation init container into sorted lists of valid
+// and invalid packages.
+func parsePackagesReport(lines []string) ([]string, []string) {
+	var valid, invalid []string
+	for _, line := range lines {
+		switch {
+		case strings.HasPrefix(line, packageValidOutcome):
+			valid = append(valid, strings.TrimPrefix(line, packageValidOutcome))
+		case strings.HasPrefix(line, packageInvalidOutcome):
+			invalid = append(invalid, strings.TrimPrefix(line, packageInvalidOutcome))
+		}
+	}
+	slices.Sort(valid)
+	slices.Sort(invalid)
+	return valid, invalid
+}
@@ -0,0 +1,144 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package packageregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eprv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/packageregistry/v1alpha1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/initreport"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/metadata"
+)
+
+func TestNewPodSpec_PackageSources(t *testing.T) {
+	epr := eprv1alpha1.PackageRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-epr", Namespace: "default"},
+		Spec: eprv1alpha1.PackageRegistrySpec{
+			Version: "9.3.0",
+			PackageSources: []eprv1alpha1.PackageSource{
+				{Name: "internal", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "packages"}, SubPath: "integrations"},
+				{Name: "bundle", Image: &corev1.ImageVolumeSource{Reference: "registry.local/packages:1.0.0"}},
+			},
+		},
+	}
+
+	podSpec, err := newPodSpec(epr, "test-config-hash", metadata.Metadata{}, true)
+	require.NoError(t, err)
+
+	volumes := map[string]corev1.Volume{}
+	for _, v := range podSpec.Spec.Volumes {
+		volumes[v.Name] = v
+	}
+	require.NotNil(t, volumes["packages-custom"].EmptyDir)
+	require.NotNil(t, volumes["package-source-internal"].PersistentVolumeClaim)
+	assert.True(t, volumes["package-source-internal"].PersistentVolumeClaim.ReadOnly)
+	require.NotNil(t, volumes["package-source-bundle"].Image)
+	assert.Equal(t, "registry.local/packages:1.0.0", volumes["package-source-bundle"].Image.Reference)
+
+	require.Len(t, podSpec.Spec.Containers, 1)
+	assert.Contains(t, podSpec.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "packages-custom", MountPath: CustomPackagesPath})
+	for _, m := range podSpec.Spec.Containers[0].VolumeMounts {
+		assert.NotContains(t, []string{"package-source-internal", "package-source-bundle"}, m.Name, "package sources must only be mounted in the init container")
+	}
+
+	require.Len(t, podSpec.Spec.InitContainers, 1)
+	initContainer := podSpec.Spec.InitContainers[0]
+	assert.Equal(t, ValidatePackagesContainerName, initContainer.Name)
+	assert.Equal(t, podSpec.Spec.Containers[0].Image, initContainer.Image)
+	assert.Contains(t, initContainer.VolumeMounts, corev1.VolumeMount{Name: "packages-custom", MountPath: CustomPackagesPath})
+	assert.Contains(t, initContainer.VolumeMounts, corev1.VolumeMount{
+		Name: "package-source-internal", MountPath: "/mnt/elastic-internal/package-sources/internal", SubPath: "integrations", ReadOnly: true,
+	})
+	assert.Contains(t, initContainer.VolumeMounts, corev1.VolumeMount{
+		Name: "package-source-bundle", MountPath: "/mnt/elastic-internal/package-sources/bundle", ReadOnly: true,
+	})
+
+	// no init container nor volume without package sources
+	epr.Spec.PackageSources = nil
+	podSpec, err = newPodSpec(epr, "test-config-hash", metadata.Metadata{}, true)
+	require.NoError(t, err)
+	assert.Empty(t, podSpec.Spec.InitContainers)
+	for _, v := range podSpec.Spec.Volumes {
+		assert.NotEqual(t, "packages-custom", v.Name)
+	}
+}
+
+func TestNewPodSpec_PackageSourceNamedCustom(t *testing.T) {
+	epr := eprv1alpha1.PackageRegistry{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-epr", Namespace: "default"},
+		Spec: eprv1alpha1.PackageRegistrySpec{
+			Version: "9.3.0",
+			PackageSources: []eprv1alpha1.PackageSource{
+				{Name: "custom", PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "packages"}},
+			},
+		},
+	}
+
+	podSpec, err := newPodSpec(epr, "test-config-hash", metadata.Metadata{}, true)
+	require.NoError(t, err)
+
+	// the volume of the package source must not collide with the volume of the validated packages
+	names := map[string]int{}
+	for _, v := range podSpec.Spec.Volumes {
+		names[v.Name]++
+	}
+	for name, count := range names {
+		assert.Equal(t, 1, count, "duplicate volume %s", name)
+	}
+	assert.Contains(t, names, "packages-custom")
+	assert.Contains(t, names, "package-source-custom")
+}
+
+func Test_packagesFromReport(t *testing.T) {
+	tests := []struct {
+		name        string
+		report      *initreport.Report
+		wantValid   []string
+		wantInvalid []string
+		wantOK      bool
+	}{
+		{
+			name:   "no report",
+			wantOK: false,
+		},
+		{
+			name:   "init container running",
+			report: &initreport.Report{Phase: initreport.RunningPhase, Lines: []string{"ok a-1.0.0"}},
+			wantOK: false,
+		},
+		{
+			name:   "init container failed",
+			report: &initreport.Report{Phase: initreport.FailedPhase, Lines: []string{"ok a-1.0.0"}},
+			wantOK: false,
+		},
+		{
+			name: "init container succeeded",
+			report: &initreport.Report{Phase: initreport.SucceededPhase, Lines: []string{
+				"ok zeta-2.0.0", "invalid broken: missing manifest.yml", "cp: cannot stat", "ok alpha-1.0.0",
+			}},
+			wantValid:   []string{"alpha-1.0.0", "zeta-2.0.0"},
+			wantInvalid: []string{"broken: missing manifest.yml"},
+			wantOK:      true,
+		},
+		{
+			name:   "empty report",
+			report: &initreport.Report{Phase: initreport.SucceededPhase},
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, invalid, ok := packagesFromReport(tt.report)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantValid, valid)
+			assert.Equal(t, tt.wantInvalid, invalid)
+		})
+	}
+}
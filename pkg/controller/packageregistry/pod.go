@@ -100,9 +100,15 @@ func newPodSpec(epr eprv1alpha1.PackageRegistry, configHash string, meta metadat
 		WithAnnotations(podMeta.Annotations).
 		WithLabels(podMeta.Labels).
 		WithResources(DefaultResources).
-		WithDockerImage(epr.Spec.Image, container.ImageRepository(container.PackageRegistryImage, v)).
+		WithDockerImage(epr.Spec.Image, container.ImageRepository(epr.Namespace, container.PackageRegistryImage, v)).
+		WithImagePullSecrets(container.ImagePullSecrets(epr.Namespace)...).
 		WithReadinessProbe(readinessProbe(epr.Spec.HTTP.TLS.Enabled())).
-		WithPorts(defaultContainerPorts).
+		WithPorts(defaultContainerPorts)
+
+	// Add the package sources before applying the init containers defaults
+	builder = withPackageSources(builder, epr)
+
+	builder = builder.
 		WithInitContainerDefaults().
 		WithEnv(eprVars...).
 		WithContainersSecurityContext(corev1.SecurityContext{
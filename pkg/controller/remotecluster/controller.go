@@ -48,7 +48,7 @@ const (
 // Add creates a new ReconcileRemoteClusters Controller and adds it to the manager with default RBAC.
 func Add(mgr manager.Manager, accessReviewer rbac.AccessReviewer, params operator.Parameters) error {
 	r := NewReconciler(mgr, accessReviewer, params)
-	c, err := common.NewController(mgr, name, r, params)
+	c, err := common.NewController(mgr, name, r, params, &esv1.Elasticsearch{})
 	if err != nil {
 		return err
 	}
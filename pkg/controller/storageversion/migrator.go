@@ -0,0 +1,135 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package storageversion
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	pkgerrors "github.com/pkg/errors"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// groupSuffix is the suffix of the API groups of the resources managed by the operator.
+	groupSuffix = ".k8s.elastic.co"
+	// listPageSize is the number of objects retrieved at once when migrating the objects of a resource.
+	listPageSize = 100
+)
+
+// Migrator rewrites the stored objects of the operator resources in the storage version of their CRD, so that the
+// previous versions can be pruned from the status of the CRD and later removed from the CRD itself.
+type Migrator struct {
+	client       client.Client
+	crdClientset apiextensionsclient.Interface
+}
+
+// NewMigrator returns a new storage version Migrator.
+func NewMigrator(c client.Client, crdClientset apiextensionsclient.Interface) *Migrator {
+	return &Migrator{client: c, crdClientset: crdClientset}
+}
+
+// Migrate migrates the stored objects of all the operator CRDs still storing objects in versions other than their
+// storage version, then prunes these versions from the status of the CRDs.
+func (m *Migrator) Migrate(ctx context.Context) error {
+	crds, err := m.crdClientset.ApiextensionsV1().CustomResourceDefinitions().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for i := range crds.Items {
+		crd := &crds.Items[i]
+		if !strings.HasSuffix(crd.Spec.Group, groupSuffix) {
+			continue
+		}
+		if err := m.migrateCRD(ctx, crd); err != nil {
+			errs = append(errs, pkgerrors.Wrapf(err, "while migrating the storage version of %s", crd.Name))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (m *Migrator) migrateCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition) error {
+	storageVersion, found := storageVersionOf(crd)
+	if !found {
+		return nil
+	}
+	if !needsMigration(crd, storageVersion) {
+		return nil
+	}
+	log := ulog.FromContext(ctx).WithValues("crd", crd.Name, "storage_version", storageVersion, "stored_versions", crd.Status.StoredVersions)
+	log.Info("Migrating stored objects to the storage version")
+
+	gvk := schema.GroupVersionKind{Group: crd.Spec.Group, Version: storageVersion, Kind: crd.Spec.Names.ListKind}
+	migrated := 0
+	continueToken := ""
+	for {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+		if err := m.client.List(ctx, list, client.Limit(listPageSize), client.Continue(continueToken)); err != nil {
+			return err
+		}
+		for i := range list.Items {
+			if err := m.migrateObject(ctx, &list.Items[i]); err != nil {
+				return err
+			}
+			migrated++
+		}
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			break
+		}
+	}
+
+	// all objects are now stored in the storage version
+	crd.Status.StoredVersions = []string{storageVersion}
+	if _, err := m.crdClientset.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(ctx, crd, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	log.Info("Storage version migration completed", "migrated_objects", migrated)
+	return nil
+}
+
+// migrateObject rewrites an object unchanged, which stores it in the storage version.
+func (m *Migrator) migrateObject(ctx context.Context, obj *unstructured.Unstructured) error {
+	err := m.client.Update(ctx, obj)
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsNotFound(err):
+		// deleted in the meantime
+		return nil
+	case apierrors.IsConflict(err):
+		// updated in the meantime, hence already stored in the storage version
+		return nil
+	default:
+		return pkgerrors.Wrapf(err, "while migrating %s/%s", obj.GetNamespace(), obj.GetName())
+	}
+}
+
+// storageVersionOf returns the version in which the objects of the given CRD are stored.
+func storageVersionOf(crd *apiextensionsv1.CustomResourceDefinition) (string, bool) {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name, true
+		}
+	}
+	return "", false
+}
+
+// needsMigration returns true if objects of the given CRD may be stored in a version other than the storage version.
+func needsMigration(crd *apiextensionsv1.CustomResourceDefinition, storageVersion string) bool {
+	return slices.ContainsFunc(crd.Status.StoredVersions, func(v string) bool { return v != storageVersion })
+}
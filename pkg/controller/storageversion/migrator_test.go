@@ -0,0 +1,70 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package storageversion
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/scheme"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func crd(name, group, kind string, storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: kind, ListKind: kind + "List"},
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true, Storage: true},
+				{Name: "v1beta1", Served: true},
+			},
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{StoredVersions: storedVersions},
+	}
+}
+
+func TestMigrator_Migrate(t *testing.T) {
+	scheme.SetupScheme()
+	// NewClientset does not know the schema of CustomResourceDefinitions to track their updates
+	crdClientset := apiextensionsfake.NewSimpleClientset( //nolint:staticcheck
+		crd("elasticsearches.elasticsearch.k8s.elastic.co", "elasticsearch.k8s.elastic.co", "Elasticsearch", "v1beta1", "v1"),
+		crd("kibanas.kibana.k8s.elastic.co", "kibana.k8s.elastic.co", "Kibana", "v1"),
+		crd("widgets.example.com", "example.com", "Widget", "v1beta1", "v1"),
+	)
+	c := k8s.NewFakeClient(
+		&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "es1", ResourceVersion: "1"}},
+		&esv1.Elasticsearch{ObjectMeta: metav1.ObjectMeta{Namespace: "ns2", Name: "es2", ResourceVersion: "1"}},
+	)
+
+	require.NoError(t, NewMigrator(c, crdClientset).Migrate(context.Background()))
+
+	// objects are rewritten
+	for _, key := range []client.ObjectKey{{Namespace: "ns1", Name: "es1"}, {Namespace: "ns2", Name: "es2"}} {
+		var es esv1.Elasticsearch
+		require.NoError(t, c.Get(context.Background(), key, &es))
+		assert.Equal(t, "2", es.ResourceVersion, key.String())
+	}
+
+	// previous versions are pruned from the status of the operator CRDs only
+	for name, expected := range map[string][]string{
+		"elasticsearches.elasticsearch.k8s.elastic.co": {"v1"},
+		"kibanas.kibana.k8s.elastic.co":                {"v1"},
+		"widgets.example.com":                          {"v1beta1", "v1"},
+	} {
+		actual, err := crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual.Status.StoredVersions, name)
+	}
+}
@@ -28,6 +28,8 @@ type AdmissionControllerInterface interface {
 	services() Services
 	// webhooks returns the list of webhooks in the configuration
 	webhooks() []webhook
+	// service returns the service used by the first Webhook, if any
+	service() *v1.ServiceReference
 	// updateCABundle updates CABundle with the provided CA in all the Webhooks
 	updateCABundle(caCert []byte) error
 }
@@ -82,6 +84,15 @@ func (v1w *v1webhookHandler) services() Services {
 	return services
 }
 
+func (v1w *v1webhookHandler) service() *v1.ServiceReference {
+	for _, wh := range v1w.webhookConfiguration.Webhooks {
+		if wh.ClientConfig.Service != nil {
+			return wh.ClientConfig.Service
+		}
+	}
+	return nil
+}
+
 func (v1w *v1webhookHandler) updateCABundle(caCert []byte) error {
 	for i := range v1w.webhookConfiguration.Webhooks {
 		v1w.webhookConfiguration.Webhooks[i].ClientConfig.CABundle = caCert
@@ -0,0 +1,114 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package webhook
+
+import (
+	"context"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.elastic.co/apm/v2"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/webhook/conversion"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+// ReconcileCRDConversion configures the CustomResourceDefinitions listed in the params to convert their versions with
+// the conversion webhook of the operator, reached through the same service and trusting the same CA as the validating
+// webhooks. If the conversion webhook is disabled, the CRDs pointing to it are reset to the None strategy, provided the
+// operator is still allowed to access them, which the Helm chart does not grant in that case.
+func (w *Params) ReconcileCRDConversion(
+	ctx context.Context,
+	crdClientset apiextensionsclient.Interface,
+	webhookConfiguration AdmissionControllerInterface,
+) error {
+	if len(w.ConversionCRDs) == 0 {
+		return nil
+	}
+	span, ctx := apm.StartSpan(ctx, "reconcile_crd_conversion", tracing.SpanTypeApp)
+	defer span.End()
+
+	webhookConversion, err := expectedConversion(webhookConfiguration)
+	if err != nil {
+		return err
+	}
+	for _, name := range w.ConversionCRDs {
+		crd, err := crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if !w.ConversionWebhook && apierrors.IsForbidden(err) {
+				// the CRDs must be reset manually if the conversion webhook was enabled in a previous run of the operator
+				ulog.FromContext(ctx).V(1).Info("Not allowed to read CRD, skipping conversion reset", "crd", name)
+				continue
+			}
+			return err
+		}
+		expected := webhookConversion
+		if !w.ConversionWebhook {
+			if !isOperatorConversion(crd.Spec.Conversion, webhookConversion) {
+				// leave the conversion configured by someone else alone
+				continue
+			}
+			expected = &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}
+		}
+		if equality.Semantic.DeepEqual(crd.Spec.Conversion, expected) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Configuring CRD conversion", "crd", name, "strategy", expected.Strategy)
+		crd.Spec.Conversion = expected.DeepCopy()
+		if _, err := crdClientset.ApiextensionsV1().CustomResourceDefinitions().Update(ctx, crd, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isOperatorConversion returns true if the given conversion uses the conversion webhook of the operator.
+func isOperatorConversion(actual, operatorConversion *apiextensionsv1.CustomResourceConversion) bool {
+	if actual == nil || actual.Strategy != apiextensionsv1.WebhookConverter ||
+		actual.Webhook == nil || actual.Webhook.ClientConfig == nil || actual.Webhook.ClientConfig.Service == nil {
+		return false
+	}
+	service := actual.Webhook.ClientConfig.Service
+	operatorService := operatorConversion.Webhook.ClientConfig.Service
+	return service.Namespace == operatorService.Namespace &&
+		service.Name == operatorService.Name &&
+		ptr.Deref(service.Path, "") == conversion.WebhookPath
+}
+
+// expectedConversion returns the conversion configuration of the CRDs converted by the operator conversion webhook.
+func expectedConversion(webhookConfiguration AdmissionControllerInterface) (*apiextensionsv1.CustomResourceConversion, error) {
+	service := webhookConfiguration.service()
+	if service == nil {
+		return nil, pkgerrors.New("cannot find the webhook service in the validating webhook configuration")
+	}
+	var caBundle []byte
+	for _, wh := range webhookConfiguration.webhooks() {
+		if len(wh.caBundle) > 0 {
+			caBundle = wh.caBundle
+			break
+		}
+	}
+	return &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Namespace: service.Namespace,
+					Name:      service.Name,
+					Path:      ptr.To(conversion.WebhookPath),
+					Port:      ptr.To(ptr.Deref(service.Port, 443)),
+				},
+				CABundle: caBundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}, nil
+}
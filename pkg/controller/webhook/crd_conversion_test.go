@@ -0,0 +1,137 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestParams_ReconcileCRDConversion(t *testing.T) {
+	crdName := "elasticsearches.elasticsearch.k8s.elastic.co"
+	// NewClientset does not know the schema of CustomResourceDefinitions to track their updates
+	crdClientset := apiextensionsfake.NewSimpleClientset( //nolint:staticcheck
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: crdName},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter},
+			},
+		},
+		&apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "beats.beat.k8s.elastic.co"},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Conversion: &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter},
+			},
+		},
+	)
+	wh := &v1webhookHandler{webhookConfiguration: &v1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "elastic-webhook.k8s.elastic.co"},
+		Webhooks: []v1.ValidatingWebhook{
+			{
+				Name: "elastic-es-validation-v1.k8s.elastic.co",
+				ClientConfig: v1.WebhookClientConfig{
+					Service:  &v1.ServiceReference{Name: "elastic-webhook-server", Namespace: "elastic-system"},
+					CABundle: []byte("ca"),
+				},
+			},
+		},
+	}}
+
+	// conversion webhook disabled
+	w := Params{Name: "elastic-webhook.k8s.elastic.co", ConversionCRDs: []string{crdName}}
+	require.NoError(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+	crd, err := crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, apiextensionsv1.NoneConverter, crd.Spec.Conversion.Strategy)
+
+	// conversion webhook enabled
+	w.ConversionWebhook = true
+	require.NoError(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+	crd, err = crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{
+					Namespace: "elastic-system",
+					Name:      "elastic-webhook-server",
+					Path:      ptr.To("/convert"),
+					Port:      ptr.To[int32](443),
+				},
+				CABundle: []byte("ca"),
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}, crd.Spec.Conversion)
+
+	// other CRDs are left untouched
+	other, err := crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), "beats.beat.k8s.elastic.co", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, apiextensionsv1.NoneConverter, other.Spec.Conversion.Strategy)
+
+	// the CA bundle follows the rotation of the webhook certificates
+	wh.webhookConfiguration.Webhooks[0].ClientConfig.CABundle = []byte("new-ca")
+	require.NoError(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+	crd, err = crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new-ca"), crd.Spec.Conversion.Webhook.ClientConfig.CABundle)
+
+	// disabling the conversion webhook resets the CRD
+	w.ConversionWebhook = false
+	require.NoError(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+	crd, err = crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &apiextensionsv1.CustomResourceConversion{Strategy: apiextensionsv1.NoneConverter}, crd.Spec.Conversion)
+
+	// a conversion webhook configured by someone else is left untouched
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig: &apiextensionsv1.WebhookClientConfig{
+				Service: &apiextensionsv1.ServiceReference{Namespace: "other", Name: "other-webhook", Path: ptr.To("/convert")},
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	_, err = crdClientset.ApiextensionsV1().CustomResourceDefinitions().Update(context.Background(), crd, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+	crd, err = crdClientset.ApiextensionsV1().CustomResourceDefinitions().Get(context.Background(), crdName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "other-webhook", crd.Spec.Conversion.Webhook.ClientConfig.Service.Name)
+}
+
+func TestParams_ReconcileCRDConversion_Forbidden(t *testing.T) {
+	crdClientset := apiextensionsfake.NewSimpleClientset() //nolint:staticcheck
+	crdClientset.PrependReactor("get", "customresourcedefinitions", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Group: "apiextensions.k8s.io", Resource: "customresourcedefinitions"}, "", errors.New("forbidden"))
+	})
+	wh := &v1webhookHandler{webhookConfiguration: &v1.ValidatingWebhookConfiguration{
+		Webhooks: []v1.ValidatingWebhook{
+			{ClientConfig: v1.WebhookClientConfig{Service: &v1.ServiceReference{Name: "elastic-webhook-server", Namespace: "elastic-system"}}},
+		},
+	}}
+	w := Params{ConversionCRDs: []string{"elasticsearches.elasticsearch.k8s.elastic.co"}}
+
+	// without the permission to read CRDs, there is nothing to reset
+	require.NoError(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+	// but the conversion webhook cannot be configured
+	w.ConversionWebhook = true
+	require.Error(t, w.ReconcileCRDConversion(context.Background(), crdClientset, wh))
+}
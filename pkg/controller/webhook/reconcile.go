@@ -28,6 +28,12 @@ type Params struct {
 
 	// Certificate options
 	Rotation certificates.RotationParams
+
+	// ConversionCRDs are the names of the CustomResourceDefinitions of the resources served in several versions.
+	ConversionCRDs []string
+	// ConversionWebhook is true if ConversionCRDs must be converted by the conversion webhook. Otherwise, the
+	// conversion webhook configured in their CRDs by a previous run of the operator is removed.
+	ConversionWebhook bool
 }
 
 // ReconcileResources reconciles the certificates used by the webhook client and the webhook server.
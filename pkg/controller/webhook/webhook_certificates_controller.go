@@ -11,6 +11,7 @@ import (
 	pkgerrors "github.com/pkg/errors"
 	"go.elastic.co/apm/v2"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -46,6 +47,8 @@ type ReconcileWebhookResources struct {
 	webhookParams Params
 	// resources are updated with a native Kubernetes client
 	clientset kubernetes.Interface
+	// crdClientset is used to configure the conversion webhook of the CRDs
+	crdClientset apiextensionsclient.Interface
 
 	// APM tracer
 	tracer *apm.Tracer
@@ -69,6 +72,9 @@ func (r *ReconcileWebhookResources) reconcileInternal(ctx context.Context) *reco
 	if err := r.webhookParams.ReconcileResources(ctx, r.clientset, wh); err != nil {
 		return res.WithError(err)
 	}
+	if err := r.webhookParams.ReconcileCRDConversion(ctx, r.crdClientset, wh); err != nil {
+		return res.WithError(err)
+	}
 
 	// Get the latest content of the webhook CA
 	webhookServerSecret, err := r.clientset.CoreV1().Secrets(r.webhookParams.Namespace).Get(ctx, r.webhookParams.SecretName, metav1.GetOptions{})
@@ -87,19 +93,33 @@ func (r *ReconcileWebhookResources) reconcileInternal(ctx context.Context) *reco
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, webhookParams Params, clientset kubernetes.Interface, tracer *apm.Tracer) *ReconcileWebhookResources {
+func newReconciler(
+	mgr manager.Manager,
+	webhookParams Params,
+	clientset kubernetes.Interface,
+	crdClientset apiextensionsclient.Interface,
+	tracer *apm.Tracer,
+) *ReconcileWebhookResources {
 	c := mgr.GetClient()
 	return &ReconcileWebhookResources{
 		Client:        c,
 		webhookParams: webhookParams,
 		clientset:     clientset,
+		crdClientset:  crdClientset,
 		tracer:        tracer,
 	}
 }
 
 // Add adds a new Controller to mgr with r as the reconcile.Reconciler
-func Add(mgr manager.Manager, webhookParams Params, clientset kubernetes.Interface, webhook AdmissionControllerInterface, tracer *apm.Tracer) error {
-	r := newReconciler(mgr, webhookParams, clientset, tracer)
+func Add(
+	mgr manager.Manager,
+	webhookParams Params,
+	clientset kubernetes.Interface,
+	crdClientset apiextensionsclient.Interface,
+	webhook AdmissionControllerInterface,
+	tracer *apm.Tracer,
+) error {
+	r := newReconciler(mgr, webhookParams, clientset, crdClientset, tracer)
 	// Create a new controller
 	c, err := controller.New(ControllerName, mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
 	lsv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/apmserver"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/nodespec"
 	essettings "github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/settings"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/enterprisesearch"
@@ -33,6 +34,8 @@ import (
 // aggregator aggregates the total of resources of all Elastic managed components
 type aggregator struct {
 	client k8s.Client
+	// pools assigns the managed resources to the license pools
+	pools license.PoolMatcher
 }
 
 type aggregate func(ctx context.Context) (managedMemory, error)
@@ -41,6 +44,20 @@ type aggregate func(ctx context.Context) (managedMemory, error)
 func (a aggregator) aggregateMemory(ctx context.Context) (memoryUsage, error) {
 	usage := newMemoryUsage()
 
+	licenses, _ := license.ScopedEnterpriseLicensesOrErrors(a.client)
+	pools, err := license.NewPoolMatcher(ctx, a.client, licenses)
+	if err != nil {
+		return memoryUsage{}, errors.Wrap(err, "failed to resolve license pools")
+	}
+	a.pools = pools
+	if pools.HasScopedPools() {
+		// report all the pools, even the ones without any resource
+		usage.pools = map[string]resource.Quantity{}
+		for _, pool := range pools.Pools() {
+			usage.pools[pool] = resource.Quantity{}
+		}
+	}
+
 	for _, f := range []aggregate{
 		a.aggregateElasticsearchMemory,
 		a.aggregateKibanaMemory,
@@ -65,26 +82,31 @@ func (a aggregator) aggregateElasticsearchMemory(ctx context.Context) (managedMe
 		return managedMemory{}, errors.Wrap(err, "failed to aggregate Elasticsearch memory")
 	}
 
-	var total resource.Quantity
+	memory := managedMemory{label: elasticsearchKey}
 	for _, es := range esList.Items {
 		for _, nodeSet := range es.Spec.NodeSets {
+			envLookup := memFromJavaOpts
+			if nodeSet.JVMHeap != nil {
+				// the heap is derived from the memory limit, which is the only source of truth
+				envLookup = nil
+			}
 			mem, err := containerMemLimits(
 				nodeSet.PodTemplate.Spec.Containers,
 				esv1.ElasticsearchContainerName,
-				essettings.EnvEsJavaOpts, memFromJavaOpts,
+				essettings.EnvEsJavaOpts, envLookup,
 				nodespec.DefaultMemoryLimits,
 			)
 			if err != nil {
 				return managedMemory{}, errors.Wrap(err, "failed to aggregate Elasticsearch memory")
 			}
 
-			total.Add(multiply(mem, nodeSet.Count))
+			memory.addResource(es.Namespace, a.pools.Pool(&es), multiply(mem, nodeSet.Count))
 			ulog.FromContext(ctx).V(1).Info("Collecting", "namespace", es.Namespace, "es_name", es.Name,
 				"memory", mem.String(), "count", nodeSet.Count)
 		}
 	}
 
-	return managedMemory{total, elasticsearchKey}, nil
+	return memory, nil
 }
 
 func (a aggregator) aggregateEnterpriseSearchMemory(ctx context.Context) (managedMemory, error) {
@@ -94,7 +116,7 @@ func (a aggregator) aggregateEnterpriseSearchMemory(ctx context.Context) (manage
 		return managedMemory{}, errors.Wrap(err, "failed to aggregate Enterprise Search memory")
 	}
 
-	var total resource.Quantity
+	memory := managedMemory{label: entSearchKey}
 	for _, ent := range entList.Items {
 		mem, err := containerMemLimits(
 			ent.Spec.PodTemplate.Spec.Containers,
@@ -106,12 +128,12 @@ func (a aggregator) aggregateEnterpriseSearchMemory(ctx context.Context) (manage
 			return managedMemory{}, errors.Wrap(err, "failed to aggregate Enterprise Search memory")
 		}
 
-		total.Add(multiply(mem, ent.Spec.Count))
+		memory.addResource(ent.Namespace, a.pools.Pool(&ent), multiply(mem, ent.Spec.Count))
 		ulog.FromContext(ctx).V(1).Info("Collecting", "namespace", ent.Namespace, "ent_name", ent.Name,
 			"memory", mem.String(), "count", ent.Spec.Count)
 	}
 
-	return managedMemory{total, entSearchKey}, nil
+	return memory, nil
 }
 
 func (a aggregator) aggregateKibanaMemory(ctx context.Context) (managedMemory, error) {
@@ -121,7 +143,7 @@ func (a aggregator) aggregateKibanaMemory(ctx context.Context) (managedMemory, e
 		return managedMemory{}, errors.Wrap(err, "failed to aggregate Kibana memory")
 	}
 
-	var total resource.Quantity
+	memory := managedMemory{label: kibanaKey}
 	for _, kb := range kbList.Items {
 		mem, err := containerMemLimits(
 			kb.Spec.PodTemplate.Spec.Containers,
@@ -133,12 +155,12 @@ func (a aggregator) aggregateKibanaMemory(ctx context.Context) (managedMemory, e
 			return managedMemory{}, errors.Wrap(err, "failed to aggregate Kibana memory")
 		}
 
-		total.Add(multiply(mem, kb.Spec.Count))
+		memory.addResource(kb.Namespace, a.pools.Pool(&kb), multiply(mem, kb.Spec.Count))
 		ulog.FromContext(ctx).V(1).Info("Collecting", "namespace", kb.Namespace, "kibana_name", kb.Name,
 			"memory", mem.String(), "count", kb.Spec.Count)
 	}
 
-	return managedMemory{total, kibanaKey}, nil
+	return memory, nil
 }
 
 func (a aggregator) aggregateLogstashMemory(ctx context.Context) (managedMemory, error) {
@@ -148,7 +170,7 @@ func (a aggregator) aggregateLogstashMemory(ctx context.Context) (managedMemory,
 		return managedMemory{}, errors.Wrap(err, "failed to aggregate Logstash memory")
 	}
 
-	var total resource.Quantity
+	memory := managedMemory{label: logstashKey}
 	for _, ls := range lsList.Items {
 		mem, err := containerMemLimits(
 			ls.Spec.PodTemplate.Spec.Containers,
@@ -160,12 +182,12 @@ func (a aggregator) aggregateLogstashMemory(ctx context.Context) (managedMemory,
 			return managedMemory{}, errors.Wrap(err, "failed to aggregate Logstash memory")
 		}
 
-		total.Add(multiply(mem, ls.Spec.Count))
+		memory.addResource(ls.Namespace, a.pools.Pool(&ls), multiply(mem, ls.Spec.Count))
 		ulog.FromContext(ctx).V(1).Info("Collecting", "namespace", ls.Namespace, "logstash_name", ls.Name,
 			"memory", mem.String(), "count", ls.Spec.Count)
 	}
 
-	return managedMemory{total, logstashKey}, nil
+	return memory, nil
 }
 
 func (a aggregator) aggregateApmServerMemory(ctx context.Context) (managedMemory, error) {
@@ -175,7 +197,7 @@ func (a aggregator) aggregateApmServerMemory(ctx context.Context) (managedMemory
 		return managedMemory{}, errors.Wrap(err, "failed to aggregate APM Server memory")
 	}
 
-	var total resource.Quantity
+	memory := managedMemory{label: apmKey}
 	for _, as := range asList.Items {
 		mem, err := containerMemLimits(
 			as.Spec.PodTemplate.Spec.Containers,
@@ -187,12 +209,12 @@ func (a aggregator) aggregateApmServerMemory(ctx context.Context) (managedMemory
 			return managedMemory{}, errors.Wrap(err, "failed to aggregate APM Server memory")
 		}
 
-		total.Add(multiply(mem, as.Spec.Count))
+		memory.addResource(as.Namespace, a.pools.Pool(&as), multiply(mem, as.Spec.Count))
 		ulog.FromContext(ctx).V(1).Info("Collecting", "namespace", as.Namespace, "as_name", as.Name,
 			"memory", mem.String(), "count", as.Spec.Count)
 	}
 
-	return managedMemory{total, apmKey}, nil
+	return memory, nil
 }
 
 // containerMemLimits reads the container memory limits from the resource specification with fallback
@@ -14,7 +14,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -154,6 +156,42 @@ func TestAggregator(t *testing.T) {
 	require.Equal(t, 329.9073486328125, val.totalMemory.inGiB(), "total")
 }
 
+func TestAggregator_ElasticsearchJVMHeap(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "es"},
+		Spec: esv1.ElasticsearchSpec{
+			NodeSets: []esv1.NodeSet{
+				{
+					Name:    "heap-policy",
+					Count:   2,
+					JVMHeap: &esv1.JVMHeap{},
+					PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+						Name: esv1.ElasticsearchContainerName,
+						// ignored in favor of the default memory limit, as the heap size is derived from the limit
+						Env: []corev1.EnvVar{{Name: "ES_JAVA_OPTS", Value: "-Xmx8g"}},
+					}}}},
+				},
+				{
+					Name:    "heap-policy-with-limit",
+					Count:   1,
+					JVMHeap: &esv1.JVMHeap{},
+					PodTemplate: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+						Name: esv1.ElasticsearchContainerName,
+						Resources: corev1.ResourceRequirements{
+							Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("8Gi")},
+						},
+					}}}},
+				},
+			},
+		},
+	}
+	aggregator := aggregator{client: k8s.NewFakeClient(es)}
+
+	val, err := aggregator.aggregateElasticsearchMemory(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 12.0, val.inGiB())
+}
+
 func readObjects(t *testing.T, filePath string) []client.Object {
 	t.Helper()
 
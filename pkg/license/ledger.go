@@ -0,0 +1,408 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	ulog "github.com/elastic/cloud-on-k8s/v3/pkg/utils/log"
+)
+
+const (
+	// LedgerCfgMapPrefix is the prefix of the name of the config maps used to store the monthly licensing ledgers,
+	// the name is suffixed with the month in the YYYY-MM format.
+	LedgerCfgMapPrefix = "elastic-licensing-ledger-"
+	// LedgerType represents the Elastic usage ledger type used to mark the config maps that store licensing ledgers
+	LedgerType = "elastic-usage-ledger"
+
+	ledgerKey         = "ledger.json"
+	ledgerMonthFormat = "2006-01"
+	ledgerDayFormat   = "2006-01-02"
+	// maxLedgerSize is the maximum size of a serialized ledger, to remain below the 1MiB limit of a config map.
+	maxLedgerSize = 900 * 1024
+	// maxSampleGap is the maximum duration between two reports for a sample to be considered as continuous.
+	// Beyond it, the reporter is considered as interrupted and the period in between is not accounted for.
+	maxSampleGap = 3 * ResourceReporterFrequency
+)
+
+// Ledger records the consumption of the Elastic managed components over a calendar month, in UTC.
+type Ledger struct {
+	// Month of the ledger in the YYYY-MM format.
+	Month string `json:"month"`
+	// Samples are added each time the consumption changes or the reporting resumes after an interruption.
+	Samples []LedgerSample `json:"samples"`
+	// DailyPeaks holds the highest consumption reported each day, indexed by date in the YYYY-MM-DD format.
+	DailyPeaks map[string]LedgerPeak `json:"daily_peaks"`
+	// Compacted indicates that the oldest samples were merged to keep the ledger within the size limit of a config map.
+	// Only contiguous samples are merged, retaining the highest consumption: time-weighted values are then an upper
+	// bound. When no samples are contiguous, the oldest one is dropped and its period is no longer accounted for.
+	Compacted bool `json:"compacted,omitempty"`
+}
+
+// LedgerSample is the consumption reported continuously between Timestamp and LastSeen.
+type LedgerSample struct {
+	Timestamp               time.Time `json:"timestamp"`
+	LastSeen                time.Time `json:"last_seen"`
+	EnterpriseResourceUnits int64     `json:"enterprise_resource_units"`
+	TotalMemoryBytes        int64     `json:"total_memory_bytes"`
+	// Usage is the managed memory in bytes per namespace and per product.
+	Usage map[string]map[string]int64 `json:"usage,omitempty"`
+}
+
+// LedgerPeak is the highest consumption reported over a period of time.
+type LedgerPeak struct {
+	Timestamp               time.Time `json:"timestamp"`
+	EnterpriseResourceUnits int64     `json:"enterprise_resource_units"`
+	TotalMemoryBytes        int64     `json:"total_memory_bytes"`
+}
+
+// LedgerCfgMapName returns the name of the config map that stores the ledger of the month of the given time.
+func LedgerCfgMapName(t time.Time) string {
+	return LedgerCfgMapPrefix + t.UTC().Format(ledgerMonthFormat)
+}
+
+func newLedgerSample(info LicensingInfo, t time.Time) LedgerSample {
+	return LedgerSample{
+		Timestamp:               t,
+		LastSeen:                t,
+		EnterpriseResourceUnits: info.EnterpriseResourceUnits,
+		TotalMemoryBytes:        info.totalMemory.Value(),
+		Usage:                   info.namespacedUsage(),
+	}
+}
+
+// sameUsage returns true if both samples report the same consumption.
+func (s LedgerSample) sameUsage(other LedgerSample) bool {
+	return s.EnterpriseResourceUnits == other.EnterpriseResourceUnits &&
+		s.TotalMemoryBytes == other.TotalMemoryBytes &&
+		maps.EqualFunc(s.Usage, other.Usage, maps.Equal)
+}
+
+// record adds a sample to the ledger and updates the peak of the day.
+func (l *Ledger) record(sample LedgerSample) {
+	sample.Timestamp, sample.LastSeen = sample.Timestamp.UTC(), sample.LastSeen.UTC()
+	if n := len(l.Samples); n > 0 && l.Samples[n-1].sameUsage(sample) && sample.Timestamp.Sub(l.Samples[n-1].LastSeen) <= maxSampleGap {
+		l.Samples[n-1].LastSeen = sample.LastSeen
+	} else {
+		l.Samples = append(l.Samples, sample)
+	}
+
+	if l.DailyPeaks == nil {
+		l.DailyPeaks = map[string]LedgerPeak{}
+	}
+	day := sample.Timestamp.Format(ledgerDayFormat)
+	if peak, exists := l.DailyPeaks[day]; !exists || sample.TotalMemoryBytes > peak.TotalMemoryBytes {
+		l.DailyPeaks[day] = LedgerPeak{
+			Timestamp:               sample.Timestamp,
+			EnterpriseResourceUnits: sample.EnterpriseResourceUnits,
+			TotalMemoryBytes:        sample.TotalMemoryBytes,
+		}
+	}
+}
+
+// compact merges the oldest contiguous samples until the serialized ledger fits in the given size.
+func (l *Ledger) compact(maxSize int) ([]byte, error) {
+	for {
+		bytes, err := json.Marshal(l)
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes) <= maxSize || len(l.Samples) < 2 {
+			return bytes, nil
+		}
+		l.Compacted = true
+		i := firstContiguousSamples(l.Samples)
+		if i < 0 {
+			// merging samples that are not contiguous would account for the period in between, drop the oldest instead
+			l.Samples = l.Samples[1:]
+			continue
+		}
+		l.Samples[i+1] = mergeSamples(l.Samples[i], l.Samples[i+1])
+		l.Samples = slices.Delete(l.Samples, i, i+1)
+	}
+}
+
+// firstContiguousSamples returns the index of the oldest sample followed by a contiguous one, or -1 if there is none.
+func firstContiguousSamples(samples []LedgerSample) int {
+	for i := 0; i+1 < len(samples); i++ {
+		if samples[i+1].Timestamp.Sub(samples[i].LastSeen) <= maxSampleGap {
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeSamples merges two contiguous samples into one that retains the highest consumption of both.
+func mergeSamples(first, second LedgerSample) LedgerSample {
+	merged := LedgerSample{
+		Timestamp:               first.Timestamp,
+		LastSeen:                second.LastSeen,
+		EnterpriseResourceUnits: max(first.EnterpriseResourceUnits, second.EnterpriseResourceUnits),
+		TotalMemoryBytes:        max(first.TotalMemoryBytes, second.TotalMemoryBytes),
+		Usage:                   map[string]map[string]int64{},
+	}
+	for _, sample := range []LedgerSample{first, second} {
+		for namespace, products := range sample.Usage {
+			if merged.Usage[namespace] == nil {
+				merged.Usage[namespace] = map[string]int64{}
+			}
+			for product, bytes := range products {
+				merged.Usage[namespace][product] = max(merged.Usage[namespace][product], bytes)
+			}
+		}
+	}
+	return merged
+}
+
+// RecordInLedger adds the licensing information to the ledger of the month of its timestamp.
+func (r LicensingResolver) RecordInLedger(ctx context.Context, info LicensingInfo) error {
+	span, ctx := apm.StartSpan(ctx, "record_license_ledger", tracing.SpanTypeApp)
+	defer span.End()
+
+	timestamp, err := time.Parse(time.RFC3339, info.Timestamp)
+	if err != nil {
+		return errors.Wrap(err, "invalid licensing information timestamp")
+	}
+	nsn := types.NamespacedName{Namespace: r.operatorNs, Name: LedgerCfgMapName(timestamp)}
+	ulog.FromContext(ctx).V(1).Info("Recording", "namespace", nsn.Namespace, "configmap_name", nsn.Name)
+
+	var cfgMap corev1.ConfigMap
+	err = r.client.Get(ctx, nsn, &cfgMap)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	ledger, err := parseLedger(cfgMap)
+	if err != nil {
+		return err
+	}
+	ledger.Month = timestamp.UTC().Format(ledgerMonthFormat)
+	ledger.record(newLedgerSample(info, timestamp))
+	bytes, err := ledger.compact(maxLedgerSize)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		cfgMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: nsn.Namespace,
+				Name:      nsn.Name,
+				Labels: map[string]string{
+					commonv1.TypeLabelName: LedgerType,
+				},
+			},
+			Data: map[string]string{ledgerKey: string(bytes)},
+		}
+		return r.client.Create(ctx, &cfgMap)
+	}
+	if cfgMap.Data == nil {
+		cfgMap.Data = map[string]string{}
+	}
+	cfgMap.Data[ledgerKey] = string(bytes)
+	return r.client.Update(ctx, &cfgMap)
+}
+
+// DeleteExpiredLedgers deletes the ledgers of the months before the given number of months, including the month of
+// now. A non-positive retention keeps all the ledgers.
+func (r LicensingResolver) DeleteExpiredLedgers(ctx context.Context, now time.Time, retentionMonths int) error {
+	if retentionMonths <= 0 {
+		return nil
+	}
+	span, ctx := apm.StartSpan(ctx, "delete_expired_license_ledgers", tracing.SpanTypeApp)
+	defer span.End()
+
+	now = now.UTC()
+	oldest := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1-retentionMonths, 0)
+	var cfgMaps corev1.ConfigMapList
+	if err := r.client.List(ctx, &cfgMaps, client.InNamespace(r.operatorNs), client.MatchingLabels{commonv1.TypeLabelName: LedgerType}); err != nil {
+		return err
+	}
+	for i := range cfgMaps.Items {
+		cfgMap := &cfgMaps.Items[i]
+		month, err := time.Parse(ledgerMonthFormat, strings.TrimPrefix(cfgMap.Name, LedgerCfgMapPrefix))
+		if err != nil || !month.Before(oldest) {
+			continue
+		}
+		ulog.FromContext(ctx).Info("Deleting expired licensing ledger", "namespace", cfgMap.Namespace, "configmap_name", cfgMap.Name)
+		if err := r.client.Delete(ctx, cfgMap); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLedger returns the ledger stored in the given config map, or an empty ledger if there is none.
+func parseLedger(cfgMap corev1.ConfigMap) (Ledger, error) {
+	var ledger Ledger
+	data, exists := cfgMap.Data[ledgerKey]
+	if !exists {
+		return ledger, nil
+	}
+	if err := json.Unmarshal([]byte(data), &ledger); err != nil {
+		return Ledger{}, errors.Wrapf(err, "failed to parse licensing ledger %s/%s", cfgMap.Namespace, cfgMap.Name)
+	}
+	return ledger, nil
+}
+
+// loadLedgers returns the ledgers of all the months between from and to, in chronological order.
+// Months without a ledger are ignored.
+func loadLedgers(ctx context.Context, c k8s.Client, namespace string, from, to time.Time) ([]Ledger, error) {
+	var ledgers []Ledger
+	from, to = from.UTC(), to.UTC()
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for ; !month.After(to); month = month.AddDate(0, 1, 0) {
+		var cfgMap corev1.ConfigMap
+		err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: LedgerCfgMapName(month)}, &cfgMap)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ledger, err := parseLedger(cfgMap)
+		if err != nil {
+			return nil, err
+		}
+		ledgers = append(ledgers, ledger)
+	}
+	return ledgers, nil
+}
+
+// UsageReport summarizes the consumption of the Elastic managed components over a period of time.
+type UsageReport struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// CoveredDuration is the part of the period for which consumption was reported.
+	CoveredDuration string `json:"covered_duration"`
+	// Peak is the highest consumption reported over the period, if any.
+	Peak *LedgerPeak `json:"peak,omitempty"`
+	// TimeWeightedEnterpriseResourceUnits is the average of the enterprise resource units over the covered duration.
+	TimeWeightedEnterpriseResourceUnits float64 `json:"time_weighted_enterprise_resource_units"`
+	// DailyPeaks holds the highest consumption reported each day of the period.
+	DailyPeaks map[string]LedgerPeak `json:"daily_peaks"`
+	// Usage breaks down the consumption per namespace and per product.
+	Usage map[string]map[string]ProductUsage `json:"usage"`
+	// Compacted indicates that the report is based on compacted samples, time-weighted values are then an upper bound.
+	Compacted bool `json:"compacted,omitempty"`
+}
+
+// ProductUsage is the memory consumption of a product in a namespace over a period of time.
+type ProductUsage struct {
+	PeakMemoryBytes         int64 `json:"peak_memory_bytes"`
+	TimeWeightedMemoryBytes int64 `json:"time_weighted_memory_bytes"`
+	// TimeWeightedEnterpriseResourceUnits is the fractional equivalent of the time-weighted memory, totals are
+	// rounded up in the overall consumption only.
+	TimeWeightedEnterpriseResourceUnits float64 `json:"time_weighted_enterprise_resource_units"`
+}
+
+// newUsageReport computes the consumption reported in the ledgers between from and to.
+func newUsageReport(ledgers []Ledger, from, to time.Time) UsageReport {
+	from, to = from.UTC(), to.UTC()
+	report := UsageReport{
+		From:       from,
+		To:         to,
+		DailyPeaks: map[string]LedgerPeak{},
+		Usage:      map[string]map[string]ProductUsage{},
+	}
+
+	var samples []LedgerSample
+	for _, ledger := range ledgers {
+		samples = append(samples, ledger.Samples...)
+		report.Compacted = report.Compacted || ledger.Compacted
+		for day, peak := range ledger.DailyPeaks {
+			date, err := time.Parse(ledgerDayFormat, day)
+			if err != nil || date.Before(from.Truncate(24*time.Hour)) || !date.Before(to) {
+				continue
+			}
+			report.DailyPeaks[day] = peak
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Timestamp.Before(samples[j].Timestamp) })
+
+	var covered time.Duration
+	var eruSeconds float64
+	memorySeconds := map[string]map[string]float64{}
+	for i, sample := range samples {
+		// a sample applies until the next one, unless reporting was interrupted in between
+		end := sample.LastSeen
+		if i+1 < len(samples) && samples[i+1].Timestamp.Sub(sample.LastSeen) <= maxSampleGap {
+			end = samples[i+1].Timestamp
+		}
+		start := sample.Timestamp
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		inPeriod := !sample.Timestamp.After(to) && !sample.LastSeen.Before(from)
+		if !inPeriod {
+			continue
+		}
+
+		if report.Peak == nil || sample.TotalMemoryBytes > report.Peak.TotalMemoryBytes {
+			report.Peak = &LedgerPeak{
+				Timestamp:               start,
+				EnterpriseResourceUnits: sample.EnterpriseResourceUnits,
+				TotalMemoryBytes:        sample.TotalMemoryBytes,
+			}
+		}
+
+		duration := max(end.Sub(start), 0)
+		covered += duration
+		eruSeconds += float64(sample.EnterpriseResourceUnits) * duration.Seconds()
+		for namespace, products := range sample.Usage {
+			if report.Usage[namespace] == nil {
+				report.Usage[namespace] = map[string]ProductUsage{}
+				memorySeconds[namespace] = map[string]float64{}
+			}
+			for product, bytes := range products {
+				usage := report.Usage[namespace][product]
+				usage.PeakMemoryBytes = max(usage.PeakMemoryBytes, bytes)
+				report.Usage[namespace][product] = usage
+				memorySeconds[namespace][product] += float64(bytes) * duration.Seconds()
+			}
+		}
+	}
+
+	report.CoveredDuration = covered.String()
+	if covered == 0 {
+		return report
+	}
+	report.TimeWeightedEnterpriseResourceUnits = roundTo2Decimals(eruSeconds / covered.Seconds())
+	for namespace, products := range memorySeconds {
+		for product, value := range products {
+			usage := report.Usage[namespace][product]
+			usage.TimeWeightedMemoryBytes = int64(math.Round(value / covered.Seconds()))
+			usage.TimeWeightedEnterpriseResourceUnits = roundTo2Decimals(float64(usage.TimeWeightedMemoryBytes) / (64 * GiB))
+			report.Usage[namespace][product] = usage
+		}
+	}
+	return report
+}
+
+func roundTo2Decimals(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,238 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package license
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
+	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+func ledgerTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return parsed
+}
+
+func ledgerSample(t *testing.T, timestamp string, erus int64, esBytes int64) LedgerSample {
+	t.Helper()
+	ts := ledgerTime(t, timestamp)
+	return LedgerSample{
+		Timestamp:               ts,
+		LastSeen:                ts,
+		EnterpriseResourceUnits: erus,
+		TotalMemoryBytes:        esBytes,
+		Usage:                   map[string]map[string]int64{"ns": {elasticsearchKey: esBytes}},
+	}
+}
+
+func TestLedger_record(t *testing.T) {
+	var ledger Ledger
+	ledger.record(ledgerSample(t, "2026-10-19T10:00:00Z", 1, 8*GiB))
+	// same usage, the sample is extended
+	ledger.record(ledgerSample(t, "2026-10-19T10:02:00Z", 1, 8*GiB))
+	require.Len(t, ledger.Samples, 1)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T10:02:00Z"), ledger.Samples[0].LastSeen)
+
+	// the usage changes
+	ledger.record(ledgerSample(t, "2026-10-19T10:04:00Z", 2, 80*GiB))
+	// the usage is back to its previous value
+	ledger.record(ledgerSample(t, "2026-10-19T10:06:00Z", 1, 8*GiB))
+	require.Len(t, ledger.Samples, 3)
+
+	// reporting resumes after an interruption with the same usage
+	ledger.record(ledgerSample(t, "2026-10-20T08:00:00Z", 1, 8*GiB))
+	require.Len(t, ledger.Samples, 4)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T10:06:00Z"), ledger.Samples[2].LastSeen)
+
+	assert.Equal(t, map[string]LedgerPeak{
+		"2026-10-19": {Timestamp: ledgerTime(t, "2026-10-19T10:04:00Z"), EnterpriseResourceUnits: 2, TotalMemoryBytes: 80 * GiB},
+		"2026-10-20": {Timestamp: ledgerTime(t, "2026-10-20T08:00:00Z"), EnterpriseResourceUnits: 1, TotalMemoryBytes: 8 * GiB},
+	}, ledger.DailyPeaks)
+}
+
+func TestLedger_compact(t *testing.T) {
+	ledger := Ledger{Month: "2026-10", Samples: []LedgerSample{
+		ledgerSample(t, "2026-10-19T10:00:00Z", 2, 80*GiB),
+		ledgerSample(t, "2026-10-19T11:00:00Z", 1, 8*GiB),
+		ledgerSample(t, "2026-10-19T12:00:00Z", 1, 16*GiB),
+	}}
+	ledger.Samples[0].LastSeen = ledgerTime(t, "2026-10-19T10:58:00Z")
+	ledger.Samples[1].LastSeen = ledgerTime(t, "2026-10-19T11:58:00Z")
+	uncompacted, err := ledger.compact(maxLedgerSize)
+	require.NoError(t, err)
+	require.Len(t, ledger.Samples, 3)
+	assert.False(t, ledger.Compacted)
+
+	_, err = ledger.compact(len(uncompacted) - 1)
+	require.NoError(t, err)
+	require.Len(t, ledger.Samples, 2)
+	assert.True(t, ledger.Compacted)
+	// the merged sample covers both periods with the highest usage
+	assert.Equal(t, ledgerTime(t, "2026-10-19T10:00:00Z"), ledger.Samples[0].Timestamp)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T11:58:00Z"), ledger.Samples[0].LastSeen)
+	assert.Equal(t, int64(2), ledger.Samples[0].EnterpriseResourceUnits)
+	assert.Equal(t, int64(80*GiB), ledger.Samples[0].Usage["ns"][elasticsearchKey])
+}
+
+func TestLedger_compact_NotContiguous(t *testing.T) {
+	ledger := Ledger{Month: "2026-10", Samples: []LedgerSample{
+		ledgerSample(t, "2026-10-19T10:00:00Z", 2, 80*GiB),
+		// reporting was interrupted between 10:00 and 11:00
+		ledgerSample(t, "2026-10-19T11:00:00Z", 1, 8*GiB),
+		ledgerSample(t, "2026-10-19T12:00:00Z", 1, 16*GiB),
+	}}
+	ledger.Samples[1].LastSeen = ledgerTime(t, "2026-10-19T11:58:00Z")
+	uncompacted, err := ledger.compact(maxLedgerSize)
+	require.NoError(t, err)
+
+	// the oldest contiguous samples are merged, the interruption is not accounted for
+	_, err = ledger.compact(len(uncompacted) - 1)
+	require.NoError(t, err)
+	require.Len(t, ledger.Samples, 2)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T10:00:00Z"), ledger.Samples[0].LastSeen)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T11:00:00Z"), ledger.Samples[1].Timestamp)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T12:00:00Z"), ledger.Samples[1].LastSeen)
+	assert.Equal(t, int64(16*GiB), ledger.Samples[1].TotalMemoryBytes)
+
+	// no contiguous samples left, the oldest one is dropped
+	compacted, err := ledger.compact(maxLedgerSize)
+	require.NoError(t, err)
+	_, err = ledger.compact(len(compacted) - 1)
+	require.NoError(t, err)
+	require.Len(t, ledger.Samples, 1)
+	assert.Equal(t, ledgerTime(t, "2026-10-19T11:00:00Z"), ledger.Samples[0].Timestamp)
+	assert.True(t, ledger.Compacted)
+}
+
+func Test_newUsageReport(t *testing.T) {
+	september := Ledger{
+		Month: "2026-09",
+		Samples: []LedgerSample{
+			ledgerSample(t, "2026-09-30T12:00:00Z", 1, 32*GiB),
+		},
+		DailyPeaks: map[string]LedgerPeak{
+			"2026-09-30": {Timestamp: ledgerTime(t, "2026-09-30T12:00:00Z"), EnterpriseResourceUnits: 1, TotalMemoryBytes: 32 * GiB},
+		},
+	}
+	september.Samples[0].LastSeen = ledgerTime(t, "2026-09-30T23:59:00Z")
+
+	october := Ledger{
+		Month: "2026-10",
+		Samples: []LedgerSample{
+			ledgerSample(t, "2026-10-01T00:00:00Z", 1, 30*GiB),
+			ledgerSample(t, "2026-10-01T06:00:00Z", 3, 150*GiB),
+			// reporting was interrupted between 12:00 and 18:00
+			ledgerSample(t, "2026-10-01T18:00:00Z", 1, 30*GiB),
+		},
+		DailyPeaks: map[string]LedgerPeak{
+			"2026-10-01": {Timestamp: ledgerTime(t, "2026-10-01T06:00:00Z"), EnterpriseResourceUnits: 3, TotalMemoryBytes: 150 * GiB},
+		},
+	}
+	october.Samples[0].LastSeen = ledgerTime(t, "2026-10-01T05:58:00Z")
+	october.Samples[1].LastSeen = ledgerTime(t, "2026-10-01T12:00:00Z")
+	october.Samples[2].LastSeen = ledgerTime(t, "2026-10-02T00:00:00Z")
+
+	report := newUsageReport([]Ledger{september, october}, ledgerTime(t, "2026-10-01T00:00:00Z"), ledgerTime(t, "2026-11-01T00:00:00Z"))
+	assert.Equal(t, "18h0m0s", report.CoveredDuration)
+	assert.Equal(t, &LedgerPeak{Timestamp: ledgerTime(t, "2026-10-01T06:00:00Z"), EnterpriseResourceUnits: 3, TotalMemoryBytes: 150 * GiB}, report.Peak)
+	// (6h * 1 + 6h * 3 + 6h * 1) / 18h
+	assert.Equal(t, 1.67, report.TimeWeightedEnterpriseResourceUnits)
+	assert.Equal(t, map[string]LedgerPeak{"2026-10-01": october.DailyPeaks["2026-10-01"]}, report.DailyPeaks)
+	assert.Equal(t, map[string]map[string]ProductUsage{"ns": {elasticsearchKey: {
+		PeakMemoryBytes: 150 * GiB,
+		// (6h * 30GiB + 6h * 150GiB + 6h * 30GiB) / 18h
+		TimeWeightedMemoryBytes:             70 * GiB,
+		TimeWeightedEnterpriseResourceUnits: 1.09,
+	}}}, report.Usage)
+
+	// no recorded consumption
+	report = newUsageReport(nil, ledgerTime(t, "2026-10-01T00:00:00Z"), ledgerTime(t, "2026-11-01T00:00:00Z"))
+	assert.Equal(t, "0s", report.CoveredDuration)
+	assert.Nil(t, report.Peak)
+	assert.Empty(t, report.Usage)
+}
+
+func TestResourceReporter_GetUsageReport(t *testing.T) {
+	es := &esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Count: 3}}},
+	}
+	kb := &kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "team-b", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{Count: 1},
+	}
+	k8sClient := k8s.NewFakeClient(es, kb)
+	reporter := NewResourceReporter(k8sClient, operatorNs, 0, nil)
+
+	require.NoError(t, reporter.Report(context.Background()))
+	require.NoError(t, reporter.Report(context.Background()))
+
+	now := time.Now()
+	var cfgMap corev1.ConfigMap
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Namespace: operatorNs, Name: LedgerCfgMapName(now)}, &cfgMap))
+	assert.Equal(t, LedgerType, cfgMap.Labels[commonv1.TypeLabelName])
+	ledger, err := parseLedger(cfgMap)
+	require.NoError(t, err)
+	require.Len(t, ledger.Samples, 1)
+
+	report, err := reporter.GetUsageReport(context.Background(), now.Add(-time.Hour), now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, report.Peak)
+	assert.Equal(t, int64(1), report.Peak.EnterpriseResourceUnits)
+	assert.Equal(t, int64(6*GiB), report.Usage["team-a"][elasticsearchKey].PeakMemoryBytes)
+	assert.Equal(t, int64(1*GiB), report.Usage["team-b"][kibanaKey].PeakMemoryBytes)
+	assert.Len(t, report.DailyPeaks, 1)
+}
+
+func TestLicensingResolver_DeleteExpiredLedgers(t *testing.T) {
+	ledgerCfgMap := func(name string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Namespace: operatorNs,
+			Name:      name,
+			Labels:    map[string]string{commonv1.TypeLabelName: LedgerType},
+		}}
+	}
+	k8sClient := k8s.NewFakeClient(
+		ledgerCfgMap(LedgerCfgMapPrefix+"2025-09"),
+		ledgerCfgMap(LedgerCfgMapPrefix+"2025-10"),
+		ledgerCfgMap(LedgerCfgMapPrefix+"2026-10"),
+		// not a ledger
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: operatorNs, Name: LedgerCfgMapPrefix + "2020-01"}},
+	)
+	r := LicensingResolver{client: k8sClient, operatorNs: operatorNs}
+	now := ledgerTime(t, "2026-10-19T10:00:00Z")
+
+	// no retention
+	require.NoError(t, r.DeleteExpiredLedgers(context.Background(), now, 0))
+	var cfgMaps corev1.ConfigMapList
+	require.NoError(t, k8sClient.List(context.Background(), &cfgMaps))
+	require.Len(t, cfgMaps.Items, 4)
+
+	// the current month and the 12 previous ones are kept
+	require.NoError(t, r.DeleteExpiredLedgers(context.Background(), now, 13))
+	require.NoError(t, k8sClient.List(context.Background(), &cfgMaps))
+	names := make([]string, 0, len(cfgMaps.Items))
+	for _, cfgMap := range cfgMaps.Items {
+		names = append(names, cfgMap.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		LedgerCfgMapPrefix + "2020-01",
+		LedgerCfgMapPrefix + "2025-10",
+		LedgerCfgMapPrefix + "2026-10",
+	}, names)
+}
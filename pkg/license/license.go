@@ -50,6 +50,10 @@ const (
 type managedMemory struct {
 	resource.Quantity
 	label string
+	// namespaces breaks down the managed memory per namespace
+	namespaces map[string]resource.Quantity
+	// pools breaks down the managed memory per license pool
+	pools map[string]resource.Quantity
 }
 
 func newManagedMemory(binarySI int64, label string) managedMemory {
@@ -59,6 +63,23 @@ func newManagedMemory(binarySI int64, label string) managedMemory {
 	}
 }
 
+// addResource adds the memory of a resource deployed in the given namespace and assigned to the given license pool.
+func (mm *managedMemory) addResource(namespace, pool string, q resource.Quantity) {
+	mm.Add(q)
+	if mm.namespaces == nil {
+		mm.namespaces = map[string]resource.Quantity{}
+	}
+	nsMemory := mm.namespaces[namespace]
+	nsMemory.Add(q)
+	mm.namespaces[namespace] = nsMemory
+	if mm.pools == nil {
+		mm.pools = map[string]resource.Quantity{}
+	}
+	poolMemory := mm.pools[pool]
+	poolMemory.Add(q)
+	mm.pools[pool] = poolMemory
+}
+
 func (mm managedMemory) inGiB() float64 {
 	return inGiB(mm.Quantity)
 }
@@ -71,6 +92,8 @@ func (mm managedMemory) intoMap(m map[string]string) {
 type memoryUsage struct {
 	appUsage    map[string]managedMemory
 	totalMemory managedMemory
+	// pools is the total memory per license pool, only set if some licenses are restricted to a subset of the resources
+	pools map[string]resource.Quantity
 }
 
 func newMemoryUsage() memoryUsage {
@@ -83,6 +106,31 @@ func newMemoryUsage() memoryUsage {
 func (mu *memoryUsage) add(memory managedMemory) {
 	mu.appUsage[memory.label] = memory
 	mu.totalMemory.Add(memory.Quantity)
+	if mu.pools == nil {
+		return
+	}
+	for pool, q := range memory.pools {
+		poolMemory := mu.pools[pool]
+		poolMemory.Add(q)
+		mu.pools[pool] = poolMemory
+	}
+}
+
+// namespacedUsage returns the managed memory in bytes per namespace and per product.
+func (mu memoryUsage) namespacedUsage() map[string]map[string]int64 {
+	usage := map[string]map[string]int64{}
+	for label, memory := range mu.appUsage {
+		for namespace, q := range memory.namespaces {
+			if q.IsZero() {
+				continue
+			}
+			if usage[namespace] == nil {
+				usage[namespace] = map[string]int64{}
+			}
+			usage[namespace][label] = q.Value()
+		}
+	}
+	return usage
 }
 
 // LicensingInfo represents information about the operator license including the total memory of all Elastic managed
@@ -94,6 +142,26 @@ type LicensingInfo struct {
 	EckLicenseExpiryDate       *time.Time
 	MaxEnterpriseResourceUnits int64
 	EnterpriseResourceUnits    int64
+	// LicensePools is the licensing information per license pool, only set if some licenses are restricted to a subset
+	// of the resources.
+	LicensePools map[string]LicensePoolInfo
+}
+
+// LicensePoolInfo represents the consumption of the resources assigned to a license pool.
+type LicensePoolInfo struct {
+	TotalMemory                resource.Quantity
+	MaxEnterpriseResourceUnits int64
+	EnterpriseResourceUnits    int64
+}
+
+// intoMap adds the licensing information of the given pool to the given map.
+func (pi LicensePoolInfo) intoMap(pool string, m map[string]string) {
+	prefix := "license_pool." + pool + "."
+	m[prefix+"enterprise_resource_units"] = strconv.FormatInt(pi.EnterpriseResourceUnits, 10)
+	m[prefix+totalKey+"_memory_bytes"] = strconv.FormatInt(pi.TotalMemory.Value(), 10)
+	if pi.MaxEnterpriseResourceUnits > 0 {
+		m[prefix+"max_enterprise_resource_units"] = strconv.FormatInt(pi.MaxEnterpriseResourceUnits, 10)
+	}
 }
 
 // toMap transforms a LicensingInfo to a map of string, in order to fill in the data of a config map
@@ -116,6 +184,10 @@ func (li LicensingInfo) toMap() map[string]string {
 		m["eck_license_expiry_date"] = li.EckLicenseExpiryDate.Format(time.RFC3339)
 	}
 
+	for pool, info := range li.LicensePools {
+		info.intoMap(pool, m)
+	}
+
 	return m
 }
 
@@ -132,6 +204,19 @@ func (li LicensingInfo) ReportAsMetrics() {
 	if li.MaxEnterpriseResourceUnits > 0 {
 		metrics.LicensingMaxERUGauge.With(labels).Set(float64(li.MaxEnterpriseResourceUnits))
 	}
+
+	// reset the pool metrics to not report deleted pools anymore
+	metrics.LicensingPoolTotalERUGauge.Reset()
+	metrics.LicensingPoolMaxERUGauge.Reset()
+	metrics.LicensingPoolTotalMemoryGauge.Reset()
+	for pool, info := range li.LicensePools {
+		poolLabels := prometheus.Labels{metrics.LicensePoolLabel: pool}
+		metrics.LicensingPoolTotalERUGauge.With(poolLabels).Set(float64(info.EnterpriseResourceUnits))
+		metrics.LicensingPoolTotalMemoryGauge.With(poolLabels).Set(inGiB(info.TotalMemory))
+		if info.MaxEnterpriseResourceUnits > 0 {
+			metrics.LicensingPoolMaxERUGauge.With(poolLabels).Set(float64(info.MaxEnterpriseResourceUnits))
+		}
+	}
 }
 
 // LicensingResolver resolves the licensing information of the operator
@@ -160,9 +245,37 @@ func (r LicensingResolver) ToInfo(ctx context.Context, memoryUsage memoryUsage)
 		licensingInfo.MaxEnterpriseResourceUnits = maxERUs
 	}
 
+	if memoryUsage.pools != nil {
+		licensingInfo.LicensePools, err = r.toPoolsInfo(ctx, memoryUsage.pools)
+		if err != nil {
+			return LicensingInfo{}, err
+		}
+	}
+
 	return licensingInfo, nil
 }
 
+// toPoolsInfo returns the licensing information of each license pool given the total memory of the resources assigned
+// to the pool.
+func (r LicensingResolver) toPoolsInfo(ctx context.Context, poolsMemory map[string]resource.Quantity) (map[string]LicensePoolInfo, error) {
+	// invalid licenses are reported by the license controller
+	licenses, _ := license.ScopedEnterpriseLicensesOrErrors(r.client)
+	checker := license.NewLicenseChecker(r.client, r.operatorNs)
+	pools := make(map[string]LicensePoolInfo, len(poolsMemory))
+	for pool, memory := range poolsMemory {
+		poolLicense, err := license.BestValidLicense(ctx, checker, license.InPool(licenses, pool))
+		if err != nil {
+			return nil, err
+		}
+		pools[pool] = LicensePoolInfo{
+			TotalMemory:                memory,
+			MaxEnterpriseResourceUnits: r.getMaxEnterpriseResourceUnits(poolLicense),
+			EnterpriseResourceUnits:    inEnterpriseResourceUnits(memory),
+		}
+	}
+	return pools, nil
+}
+
 // Save updates or creates licensing information in a config map
 // This relies on UnconditionalUpdates being supported configmaps and may change in k8s v2: https://github.com/kubernetes/kubernetes/issues/21330
 func (r LicensingResolver) Save(ctx context.Context, info LicensingInfo) error {
@@ -205,7 +318,7 @@ func (r LicensingResolver) Save(ctx context.Context, info LicensingInfo) error {
 	})
 }
 
-// getOperatorLicense gets the operator license.
+// getOperatorLicense gets the operator license, from the default license pool.
 func (r LicensingResolver) getOperatorLicense(ctx context.Context) (*license.EnterpriseLicense, error) {
 	checker := license.NewLicenseChecker(r.client, r.operatorNs)
 	return checker.CurrentEnterpriseLicense(ctx)
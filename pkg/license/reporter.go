@@ -23,11 +23,13 @@ const ResourceReporterFrequency = 2 * time.Minute
 type ResourceReporter struct {
 	aggregator        aggregator
 	licensingResolver LicensingResolver
-	tracer            *apm.Tracer
+	// ledgerRetention is the number of months for which the monthly ledgers are kept, non-positive to keep them all
+	ledgerRetention int
+	tracer          *apm.Tracer
 }
 
 // NewResourceReporter returns a new ResourceReporter
-func NewResourceReporter(c client.Client, operatorNs string, tracer *apm.Tracer) ResourceReporter {
+func NewResourceReporter(c client.Client, operatorNs string, ledgerRetention int, tracer *apm.Tracer) ResourceReporter {
 	return ResourceReporter{
 		aggregator: aggregator{
 			client: c,
@@ -36,7 +38,8 @@ func NewResourceReporter(c client.Client, operatorNs string, tracer *apm.Tracer)
 			client:     c,
 			operatorNs: operatorNs,
 		},
-		tracer: tracer,
+		ledgerRetention: ledgerRetention,
+		tracer:          tracer,
 	}
 }
 
@@ -59,7 +62,8 @@ func (r ResourceReporter) Start(ctx context.Context, refreshPeriod time.Duration
 	}
 }
 
-// Report licensing information by publishing metrics and updating the config map.
+// Report licensing information by publishing metrics, updating the config map and recording it in the monthly ledger,
+// deleting the ledgers past the retention period.
 func (r ResourceReporter) Report(ctx context.Context) error {
 	ctx = tracing.NewContextTransaction(ctx, r.tracer, tracing.PeriodicTxType, "resource-reporter", nil)
 	defer tracing.EndContextTransaction(ctx)
@@ -70,7 +74,13 @@ func (r ResourceReporter) Report(ctx context.Context) error {
 	}
 
 	licensingInfo.ReportAsMetrics()
-	return r.licensingResolver.Save(ctx, licensingInfo)
+	if err := r.licensingResolver.Save(ctx, licensingInfo); err != nil {
+		return err
+	}
+	if err := r.licensingResolver.RecordInLedger(ctx, licensingInfo); err != nil {
+		return err
+	}
+	return r.licensingResolver.DeleteExpiredLedgers(ctx, time.Now(), r.ledgerRetention)
 }
 
 // Get aggregates managed resources and returns the licensing information
@@ -84,3 +94,15 @@ func (r ResourceReporter) Get(ctx context.Context) (LicensingInfo, error) {
 
 	return r.licensingResolver.ToInfo(ctx, usage)
 }
+
+// GetUsageReport returns the consumption recorded in the monthly ledgers between from and to.
+func (r ResourceReporter) GetUsageReport(ctx context.Context, from, to time.Time) (UsageReport, error) {
+	span, ctx := apm.StartSpan(ctx, "get_license_usage_report", tracing.SpanTypeApp)
+	defer span.End()
+	ledgers, err := loadLedgers(ctx, r.licensingResolver.client, r.licensingResolver.operatorNs, from, to)
+	if err != nil {
+		return UsageReport{}, err
+	}
+
+	return newUsageReport(ledgers, from, to), nil
+}
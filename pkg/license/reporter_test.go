@@ -6,6 +6,7 @@ package license
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -24,6 +25,7 @@ import (
 	commonlicense "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/license"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/kibana"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/maps"
 )
 
 const operatorNs = "test-system"
@@ -37,7 +39,7 @@ func TestGet(t *testing.T) {
 				}},
 			},
 		}
-		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, 0, nil).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -80,7 +82,7 @@ func TestGet(t *testing.T) {
 				}},
 			},
 		}
-		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, 0, nil).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -122,7 +124,7 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&es), operatorNs, 0, nil).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -150,7 +152,7 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, 0, nil).Get(context.Background())
 		require.NoError(t, err)
 
 		want := LicensingInfo{
@@ -192,7 +194,7 @@ func TestGet(t *testing.T) {
 			},
 		}
 
-		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, 0, nil).Get(context.Background())
 		require.NoError(t, err)
 		want := LicensingInfo{
 			memoryUsage: memoryUsage{
@@ -230,7 +232,7 @@ func TestGet(t *testing.T) {
 				},
 			},
 		}
-		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, nil).Get(context.Background())
+		have, err := NewResourceReporter(k8s.NewFakeClient(&kb), operatorNs, 0, nil).Get(context.Background())
 		require.NoError(t, err)
 		want := LicensingInfo{
 			memoryUsage: memoryUsage{
@@ -277,7 +279,7 @@ func Test_Start(t *testing.T) {
 	tick := refreshPeriod / 2
 
 	// start the resource reporter
-	go NewResourceReporter(k8sClient, operatorNs, nil).Start(context.Background(), refreshPeriod)
+	go NewResourceReporter(k8sClient, operatorNs, 0, nil).Start(context.Background(), refreshPeriod)
 
 	// check that the licensing config map exists
 	assert.Eventually(t, func() bool {
@@ -368,3 +370,49 @@ func startTrial(t *testing.T, k8sClient client.Client) {
 	// persist updated license
 	require.NoError(t, commonlicense.UpdateEnterpriseLicense(context.Background(), wrappedClient, licenseSecret, license))
 }
+
+func TestGet_licensePools(t *testing.T) {
+	licenseBytes, err := json.Marshal(commonlicense.EnterpriseLicense{License: commonlicense.LicenseSpec{
+		UID:  "finance-license",
+		Type: commonlicense.LicenseTypeEnterprise,
+	}})
+	require.NoError(t, err)
+	financeLicense := corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: operatorNs,
+			Name:      "finance-license",
+			Labels: maps.Merge(
+				commonlicense.LabelsForOperatorScope(commonlicense.LicenseTypeEnterprise),
+				map[string]string{commonlicense.LicenseNamespaceSelectorLabelPrefix + "business-unit": "finance"},
+			),
+		},
+		Data: map[string][]byte{commonlicense.FileName: licenseBytes},
+	}
+	financeNs := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "finance", Labels: map[string]string{"business-unit": "finance"}}}
+	// 10 * 2Gi in the finance pool
+	es := esv1.Elasticsearch{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "finance", Name: "es"},
+		Spec:       esv1.ElasticsearchSpec{NodeSets: []esv1.NodeSet{{Count: 10}}},
+	}
+	// 100 * 1Gi in the default pool
+	kb := kbv1.Kibana{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "kb"},
+		Spec:       kbv1.KibanaSpec{Count: 100},
+	}
+
+	have, err := NewResourceReporter(k8s.NewFakeClient(&financeLicense, &financeNs, &es, &kb), operatorNs, 0, nil).Get(context.Background())
+	require.NoError(t, err)
+	require.Len(t, have.LicensePools, 2)
+	assert.Equal(t, int64(2), have.LicensePools[commonlicense.DefaultLicensePool].EnterpriseResourceUnits)
+	assert.Equal(t, int64(1), have.LicensePools["finance-license"].EnterpriseResourceUnits)
+	// the ERUs of each pool are rounded up separately
+	assert.Equal(t, int64(2), have.EnterpriseResourceUnits)
+
+	m := have.toMap()
+	assert.Equal(t, "1", m["license_pool.finance-license.enterprise_resource_units"])
+	assert.Equal(t, "21474836480", m["license_pool.finance-license.total_managed_memory_bytes"])
+	assert.Equal(t, "2", m["license_pool.default.enterprise_resource_units"])
+	assert.Equal(t, "107374182400", m["license_pool.default.total_managed_memory_bytes"])
+	// no max ERUs without valid license
+	assert.NotContains(t, m, "license_pool.finance-license.max_enterprise_resource_units")
+}
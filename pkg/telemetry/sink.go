@@ -0,0 +1,420 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"go.elastic.co/apm/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/certificates"
+	commonhttp "github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/http"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/tracing"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+const (
+	// DefaultDataStream is the data stream telemetry documents are indexed into when none is configured.
+	DefaultDataStream = "logs-eck.telemetry-default"
+	// otlpLogsPath is the path of the OTLP/HTTP logs endpoint.
+	otlpLogsPath = "/v1/logs"
+	// otlpEventName is the name of the OTLP log events holding the telemetry data.
+	otlpEventName = "eck.telemetry"
+	// otlpSeverityInfo is the OTLP severity number of INFO log records.
+	otlpSeverityInfo = 9
+	// sinkTimeout is the timeout of the requests sending telemetry data to a sink.
+	sinkTimeout = 30 * time.Second
+)
+
+// Sink exports the telemetry data gathered by the Reporter to a destination owned by the user.
+type Sink interface {
+	// Name identifies the sink in logs.
+	Name() string
+	// Send exports the given telemetry data, collected at the given time.
+	Send(ctx context.Context, timestamp time.Time, telemetry ECKTelemetry) error
+}
+
+// SinksConfig is the configuration of the sinks the telemetry data is exported to, in addition to the Kibana telemetry
+// Secrets. Credentials are not part of the configuration but read from Secrets in the operator namespace, each time
+// the telemetry data is exported so that they can be rotated.
+type SinksConfig struct {
+	// Elasticsearch indexes the telemetry data into a data stream of an Elasticsearch cluster.
+	Elasticsearch *ElasticsearchSinkConfig `json:"elasticsearch,omitempty"`
+	// OTLP exports the telemetry data as log records to an OTLP/HTTP endpoint.
+	OTLP *OTLPSinkConfig `json:"otlp,omitempty"`
+}
+
+// ElasticsearchSinkConfig is the configuration of a sink indexing the telemetry data into an Elasticsearch data stream.
+type ElasticsearchSinkConfig struct {
+	// URL of the Elasticsearch cluster.
+	URL string `json:"url"`
+	// DataStream is the name of the data stream to index the telemetry data into. Defaults to logs-eck.telemetry-default.
+	DataStream string `json:"dataStream,omitempty"`
+	// APIKeySecretRef references the key of a Secret holding the encoded API key used to authenticate against
+	// Elasticsearch.
+	APIKeySecretRef *corev1.SecretKeySelector `json:"apiKeySecretRef,omitempty"`
+	// Username and PasswordSecretRef, referencing the key of a Secret holding the password, are the credentials used to
+	// authenticate against Elasticsearch if no API key is set.
+	Username          string                    `json:"username,omitempty"`
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+	// CAFile is the path to a PEM file holding the certificate authorities trusted to connect to Elasticsearch.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// OTLPSinkConfig is the configuration of a sink exporting the telemetry data as log records to an OTLP/HTTP endpoint.
+type OTLPSinkConfig struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver, log records are sent to its /v1/logs path.
+	Endpoint string `json:"endpoint"`
+	// Headers are added to each request.
+	Headers map[string]string `json:"headers,omitempty"`
+	// HeadersSecretRef references a Secret whose entries are added as headers to each request, for example to
+	// authenticate against the receiver. They take precedence over Headers.
+	HeadersSecretRef *commonv1.SecretRef `json:"headersSecretRef,omitempty"`
+	// CAFile is the path to a PEM file holding the certificate authorities trusted to connect to the endpoint.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// LoadSinks reads the sinks configuration from the given file and builds the corresponding sinks, reading the Secrets
+// they reference in the given namespace.
+func LoadSinks(c k8s.Client, namespace, path string) ([]Sink, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading telemetry sinks configuration %s: %w", path, err)
+	}
+	var config SinksConfig
+	if err := yaml.Unmarshal(bytes, &config); err != nil {
+		return nil, fmt.Errorf("while parsing telemetry sinks configuration: %w", err)
+	}
+	return NewSinks(c, namespace, config)
+}
+
+// NewSinks builds the sinks described by the given configuration, reading the Secrets they reference in the given
+// namespace.
+func NewSinks(c k8s.Client, namespace string, config SinksConfig) ([]Sink, error) {
+	secrets := sinkSecrets{client: c, namespace: namespace}
+	var sinks []Sink
+	if config.Elasticsearch != nil {
+		sink, err := newElasticsearchSink(secrets, *config.Elasticsearch)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if config.OTLP != nil {
+		sink, err := newOTLPSink(secrets, *config.OTLP)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// newSinkHTTPClient returns an HTTP client trusting the certificate authorities of the given file, if any.
+func newSinkHTTPClient(caFile string) (*http.Client, error) {
+	if caFile == "" {
+		return commonhttp.Client(nil, nil, sinkTimeout), nil
+	}
+	pemData, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("while reading telemetry sink CA file %s: %w", caFile, err)
+	}
+	caCerts, err := certificates.ParsePEMCerts(pemData)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing telemetry sink CA file %s: %w", caFile, err)
+	}
+	return commonhttp.Client(nil, caCerts, sinkTimeout), nil
+}
+
+// parseSinkURL parses the URL of a sink, which must be an absolute HTTP(S) URL.
+func parseSinkURL(sink, rawURL string) (*url.URL, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("telemetry %s sink: URL is required", sink)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("telemetry %s sink: invalid URL: %w", sink, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("telemetry %s sink: URL scheme must be http or https, got %q", sink, u.Scheme)
+	}
+	return u, nil
+}
+
+// sinkSecrets reads the Secrets referenced by the sinks configuration.
+type sinkSecrets struct {
+	client    k8s.Client
+	namespace string
+}
+
+// get returns the Secret of the given name.
+func (s sinkSecrets) get(ctx context.Context, name string) (corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := s.client.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, &secret); err != nil {
+		return corev1.Secret{}, fmt.Errorf("while reading telemetry sink secret %s/%s: %w", s.namespace, name, err)
+	}
+	return secret, nil
+}
+
+// value returns the value of the referenced key of a Secret.
+func (s sinkSecrets) value(ctx context.Context, ref corev1.SecretKeySelector) (string, error) {
+	secret, err := s.get(ctx, ref.Name)
+	if err != nil {
+		return "", err
+	}
+	value, exists := secret.Data[ref.Key]
+	if !exists {
+		return "", fmt.Errorf("key %s not found in telemetry sink secret %s/%s", ref.Key, s.namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// post sends the given JSON document to the given URL.
+func post(ctx context.Context, httpClient *http.Client, url string, headers http.Header, document any) error {
+	body, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = headers.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if apiErr := commonhttp.MaybeAPIError(resp); apiErr != nil {
+		return apiErr
+	}
+	return nil
+}
+
+type elasticsearchSink struct {
+	httpClient *http.Client
+	url        string
+	secrets    sinkSecrets
+	config     ElasticsearchSinkConfig
+}
+
+var _ Sink = &elasticsearchSink{}
+
+func newElasticsearchSink(secrets sinkSecrets, config ElasticsearchSinkConfig) (*elasticsearchSink, error) {
+	esURL, err := parseSinkURL("elasticsearch", config.URL)
+	if err != nil {
+		return nil, err
+	}
+	dataStream := config.DataStream
+	if dataStream == "" {
+		dataStream = DefaultDataStream
+	}
+
+	if config.APIKeySecretRef == nil && (config.Username == "" || config.PasswordSecretRef == nil) {
+		return nil, errors.New("telemetry elasticsearch sink: either an API key secret or a username and a password secret are required")
+	}
+
+	httpClient, err := newSinkHTTPClient(config.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	return &elasticsearchSink{
+		httpClient: httpClient,
+		url:        esURL.JoinPath(dataStream, "_doc").String(),
+		secrets:    secrets,
+		config:     config,
+	}, nil
+}
+
+func (s *elasticsearchSink) Name() string {
+	return "elasticsearch"
+}
+
+// headers returns the headers authenticating the requests against Elasticsearch.
+func (s *elasticsearchSink) headers(ctx context.Context) (http.Header, error) {
+	headers := http.Header{}
+	if s.config.APIKeySecretRef != nil {
+		apiKey, err := s.secrets.value(ctx, *s.config.APIKeySecretRef)
+		if err != nil {
+			return nil, err
+		}
+		headers.Set("Authorization", "ApiKey "+apiKey)
+		return headers, nil
+	}
+	password, err := s.secrets.value(ctx, *s.config.PasswordSecretRef)
+	if err != nil {
+		return nil, err
+	}
+	credentials := base64.StdEncoding.EncodeToString([]byte(s.config.Username + ":" + password))
+	headers.Set("Authorization", "Basic "+credentials)
+	return headers, nil
+}
+
+// Send indexes the telemetry data as a single document of the data stream.
+func (s *elasticsearchSink) Send(ctx context.Context, timestamp time.Time, telemetry ECKTelemetry) error {
+	span, ctx := apm.StartSpan(ctx, "send_telemetry_elasticsearch", tracing.SpanTypeApp)
+	defer span.End()
+
+	headers, err := s.headers(ctx)
+	if err != nil {
+		return err
+	}
+
+	document := struct {
+		Timestamp string `json:"@timestamp"`
+		ECKTelemetry
+	}{
+		Timestamp:    timestamp.UTC().Format(time.RFC3339Nano),
+		ECKTelemetry: telemetry,
+	}
+	return post(ctx, s.httpClient, s.url, headers, document)
+}
+
+type otlpSink struct {
+	httpClient       *http.Client
+	url              string
+	headers          http.Header
+	secrets          sinkSecrets
+	headersSecretRef *commonv1.SecretRef
+}
+
+var _ Sink = &otlpSink{}
+
+func newOTLPSink(secrets sinkSecrets, config OTLPSinkConfig) (*otlpSink, error) {
+	endpoint, err := parseSinkURL("otlp", config.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(endpoint.Path, otlpLogsPath) {
+		endpoint = endpoint.JoinPath(otlpLogsPath)
+	}
+
+	headers := http.Header{}
+	for name, value := range config.Headers {
+		headers.Set(name, value)
+	}
+
+	httpClient, err := newSinkHTTPClient(config.CAFile)
+	if err != nil {
+		return nil, err
+	}
+	return &otlpSink{
+		httpClient:       httpClient,
+		url:              endpoint.String(),
+		headers:          headers,
+		secrets:          secrets,
+		headersSecretRef: config.HeadersSecretRef,
+	}, nil
+}
+
+func (s *otlpSink) Name() string {
+	return "otlp"
+}
+
+// Send exports the telemetry data as a single log record whose body is the JSON telemetry document, using the JSON
+// encoding of the OTLP/HTTP protocol.
+func (s *otlpSink) Send(ctx context.Context, timestamp time.Time, telemetry ECKTelemetry) error {
+	span, ctx := apm.StartSpan(ctx, "send_telemetry_otlp", tracing.SpanTypeApp)
+	defer span.End()
+
+	headers := s.headers.Clone()
+	if s.headersSecretRef != nil {
+		secret, err := s.secrets.get(ctx, s.headersSecretRef.SecretName)
+		if err != nil {
+			return err
+		}
+		for name, value := range secret.Data {
+			headers.Set(name, string(value))
+		}
+	}
+
+	body, err := json.Marshal(telemetry)
+	if err != nil {
+		return err
+	}
+	info := telemetry.ECK.OperatorInfo
+	request := otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{{
+			Resource: otlpResource{Attributes: []otlpAttribute{
+				newOTLPAttribute("service.name", "elastic-operator"),
+				newOTLPAttribute("service.version", info.BuildInfo.Version),
+				newOTLPAttribute("service.instance.id", string(info.OperatorUUID)),
+			}},
+			ScopeLogs: []otlpScopeLogs{{
+				Scope: otlpScope{Name: "github.com/elastic/cloud-on-k8s/v3/pkg/telemetry"},
+				LogRecords: []otlpLogRecord{{
+					TimeUnixNano:   strconv.FormatInt(timestamp.UnixNano(), 10),
+					SeverityNumber: otlpSeverityInfo,
+					SeverityText:   "INFO",
+					EventName:      otlpEventName,
+					Body:           otlpValue{StringValue: string(body)},
+				}},
+			}},
+		}},
+	}
+	return post(ctx, s.httpClient, s.url, headers, request)
+}
+
+// The following types are the subset of the JSON encoding of the OTLP logs export request used by the OTLP sink.
+
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	Resource  otlpResource    `json:"resource"`
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string    `json:"timeUnixNano"`
+	SeverityNumber int       `json:"severityNumber"`
+	SeverityText   string    `json:"severityText"`
+	EventName      string    `json:"eventName"`
+	Body           otlpValue `json:"body"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+func newOTLPAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
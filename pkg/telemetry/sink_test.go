@@ -0,0 +1,267 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License 2.0;
+// you may not use this file except in compliance with the Elastic License 2.0.
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
+)
+
+// recordedRequest is a request received by a test sink server.
+type recordedRequest struct {
+	path    string
+	headers http.Header
+	body    map[string]any
+}
+
+func newSinkServer(t *testing.T, statusCode int) (*httptest.Server, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bytes, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(bytes, &body))
+		requests = append(requests, recordedRequest{path: r.URL.Path, headers: r.Header, body: body})
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// sinkSecret is a Secret of the operator namespace holding the credentials of the sinks.
+var sinkSecret = &corev1.Secret{
+	ObjectMeta: metav1.ObjectMeta{Name: "telemetry-credentials", Namespace: "elastic-system"},
+	Data: map[string][]byte{
+		"api-key":       []byte("encoded-key"),
+		"password":      []byte("changeme"),
+		"Authorization": []byte("Bearer secret-token"),
+	},
+}
+
+func secretKeyRef(key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: sinkSecret.Name}, Key: key}
+}
+
+func testTelemetry() ECKTelemetry {
+	return newECKTelemetry(testOperatorInfo, map[string]any{"elasticsearches": map[string]any{"resource_count": 1}}, map[string]string{"eck_license_level": "basic"})
+}
+
+func TestNewSinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    SinksConfig
+		wantSinks []string
+		wantErr   string
+	}{
+		{
+			name: "no sink",
+		},
+		{
+			name: "both sinks",
+			config: SinksConfig{
+				Elasticsearch: &ElasticsearchSinkConfig{URL: "https://es.example.com:9200", APIKeySecretRef: secretKeyRef("api-key")},
+				OTLP:          &OTLPSinkConfig{Endpoint: "https://otel.example.com:4318"},
+			},
+			wantSinks: []string{"elasticsearch", "otlp"},
+		},
+		{
+			name:    "elasticsearch sink without URL",
+			config:  SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{APIKeySecretRef: secretKeyRef("api-key")}},
+			wantErr: "telemetry elasticsearch sink: URL is required",
+		},
+		{
+			name:    "elasticsearch sink without credentials",
+			config:  SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{URL: "https://es.example.com:9200"}},
+			wantErr: "telemetry elasticsearch sink: either an API key secret or a username and a password secret are required",
+		},
+		{
+			name:    "elasticsearch sink with a username but no password secret",
+			config:  SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{URL: "https://es.example.com:9200", Username: "telemetry"}},
+			wantErr: "telemetry elasticsearch sink: either an API key secret or a username and a password secret are required",
+		},
+		{
+			name:    "otlp sink with invalid scheme",
+			config:  SinksConfig{OTLP: &OTLPSinkConfig{Endpoint: "grpc://otel.example.com:4317"}},
+			wantErr: `telemetry otlp sink: URL scheme must be http or https, got "grpc"`,
+		},
+		{
+			name:    "missing CA file",
+			config:  SinksConfig{OTLP: &OTLPSinkConfig{Endpoint: "https://otel.example.com:4318", CAFile: "/does/not/exist"}},
+			wantErr: "while reading telemetry sink CA file /does/not/exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sinks, err := NewSinks(k8s.NewFakeClient(), "elastic-system", tt.config)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			names := make([]string, 0, len(sinks))
+			for _, sink := range sinks {
+				names = append(names, sink.Name())
+			}
+			assert.ElementsMatch(t, tt.wantSinks, names)
+		})
+	}
+}
+
+func TestLoadSinks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sinks.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+elasticsearch:
+  url: https://es.example.com:9200
+  dataStream: logs-eck.inventory-default
+  username: telemetry
+  passwordSecretRef:
+    name: telemetry-credentials
+    key: password
+`), 0o600))
+	c := k8s.NewFakeClient(sinkSecret)
+	sinks, err := LoadSinks(c, "elastic-system", path)
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+	sink, ok := sinks[0].(*elasticsearchSink)
+	require.True(t, ok)
+	assert.Equal(t, "https://es.example.com:9200/logs-eck.inventory-default/_doc", sink.url)
+	headers, err := sink.headers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Basic dGVsZW1ldHJ5OmNoYW5nZW1l", headers.Get("Authorization"))
+
+	_, err = LoadSinks(c, "elastic-system", filepath.Join(t.TempDir(), "missing.yml"))
+	require.ErrorContains(t, err, "while reading telemetry sinks configuration")
+}
+
+func Test_elasticsearchSink_Send(t *testing.T) {
+	server, requests := newSinkServer(t, http.StatusCreated)
+	sinks, err := NewSinks(k8s.NewFakeClient(sinkSecret), "elastic-system", SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{URL: server.URL, APIKeySecretRef: secretKeyRef("api-key")}})
+	require.NoError(t, err)
+
+	timestamp := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, sinks[0].Send(context.Background(), timestamp, testTelemetry()))
+
+	require.Len(t, *requests, 1)
+	request := (*requests)[0]
+	assert.Equal(t, "/"+DefaultDataStream+"/_doc", request.path)
+	assert.Equal(t, "ApiKey encoded-key", request.headers.Get("Authorization"))
+	assert.Equal(t, "application/json", request.headers.Get("Content-Type"))
+	assert.Equal(t, "2026-10-19T10:00:00Z", request.body["@timestamp"])
+	eck, ok := request.body["eck"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, string(testOperatorInfo.OperatorUUID), eck["operator_uuid"])
+	assert.Equal(t, map[string]any{"elasticsearches": map[string]any{"resource_count": float64(1)}}, eck["stats"])
+	assert.Equal(t, map[string]any{"eck_license_level": "basic"}, eck["license"])
+}
+
+func Test_elasticsearchSink_SendError(t *testing.T) {
+	server, _ := newSinkServer(t, http.StatusForbidden)
+	sinks, err := NewSinks(k8s.NewFakeClient(sinkSecret), "elastic-system", SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{URL: server.URL, APIKeySecretRef: secretKeyRef("api-key")}})
+	require.NoError(t, err)
+	err = sinks[0].Send(context.Background(), time.Now(), testTelemetry())
+	require.ErrorContains(t, err, "status is 403")
+}
+
+func Test_elasticsearchSink_SendMissingSecret(t *testing.T) {
+	server, requests := newSinkServer(t, http.StatusCreated)
+	c := k8s.NewFakeClient(sinkSecret)
+
+	// the Secret does not exist
+	sinks, err := NewSinks(k8s.NewFakeClient(), "elastic-system", SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{URL: server.URL, APIKeySecretRef: secretKeyRef("api-key")}})
+	require.NoError(t, err)
+	err = sinks[0].Send(context.Background(), time.Now(), testTelemetry())
+	require.ErrorContains(t, err, "while reading telemetry sink secret elastic-system/telemetry-credentials")
+
+	// the key does not exist in the Secret
+	sinks, err = NewSinks(c, "elastic-system", SinksConfig{Elasticsearch: &ElasticsearchSinkConfig{URL: server.URL, APIKeySecretRef: secretKeyRef("missing")}})
+	require.NoError(t, err)
+	err = sinks[0].Send(context.Background(), time.Now(), testTelemetry())
+	require.ErrorContains(t, err, "key missing not found in telemetry sink secret elastic-system/telemetry-credentials")
+	require.Empty(t, *requests)
+}
+
+func Test_otlpSink_Send(t *testing.T) {
+	server, requests := newSinkServer(t, http.StatusOK)
+	sinks, err := NewSinks(k8s.NewFakeClient(sinkSecret), "elastic-system", SinksConfig{OTLP: &OTLPSinkConfig{
+		Endpoint:         server.URL,
+		Headers:          map[string]string{"Authorization": "Bearer token", "X-Scope": "eck"},
+		HeadersSecretRef: &commonv1.SecretRef{SecretName: sinkSecret.Name},
+	}})
+	require.NoError(t, err)
+
+	timestamp := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, sinks[0].Send(context.Background(), timestamp, testTelemetry()))
+
+	require.Len(t, *requests, 1)
+	request := (*requests)[0]
+	assert.Equal(t, otlpLogsPath, request.path)
+	assert.Equal(t, "Bearer secret-token", request.headers.Get("Authorization"))
+	assert.Equal(t, "eck", request.headers.Get("X-Scope"))
+
+	bytes, err := json.Marshal(request.body)
+	require.NoError(t, err)
+	var logs otlpLogsRequest
+	require.NoError(t, json.Unmarshal(bytes, &logs))
+	require.Len(t, logs.ResourceLogs, 1)
+	assert.Contains(t, logs.ResourceLogs[0].Resource.Attributes, newOTLPAttribute("service.instance.id", string(testOperatorInfo.OperatorUUID)))
+	require.Len(t, logs.ResourceLogs[0].ScopeLogs, 1)
+	require.Len(t, logs.ResourceLogs[0].ScopeLogs[0].LogRecords, 1)
+	record := logs.ResourceLogs[0].ScopeLogs[0].LogRecords[0]
+	assert.Equal(t, "1792404000000000000", record.TimeUnixNano)
+	assert.Equal(t, otlpEventName, record.EventName)
+
+	var telemetry ECKTelemetry
+	require.NoError(t, json.Unmarshal([]byte(record.Body.StringValue), &telemetry))
+	assert.Equal(t, testOperatorInfo, telemetry.ECK.OperatorInfo)
+	assert.Equal(t, map[string]string{"eck_license_level": "basic"}, telemetry.ECK.License)
+}
+
+// fakeSink records the telemetry data it receives.
+type fakeSink struct {
+	sent []ECKTelemetry
+}
+
+func (s *fakeSink) Name() string {
+	return "fake"
+}
+
+func (s *fakeSink) Send(_ context.Context, _ time.Time, telemetry ECKTelemetry) error {
+	s.sent = append(s.sent, telemetry)
+	return nil
+}
+
+func TestReporter_reportToSinks(t *testing.T) {
+	kb, secret := createKbAndSecret("kb1", "ns1", 1)
+	client := k8s.NewFakeClient(&kb, &secret, licenceConfigMap)
+	sink := &fakeSink{}
+
+	// Kibana telemetry is disabled but the data is still exported to the sinks
+	r := NewReporter(testOperatorInfo, client, "elastic-system", []string{"ns1"}, 1*time.Hour, nil, false, []Sink{sink})
+	r.report(context.Background())
+
+	require.Len(t, sink.sent, 1)
+	assert.Equal(t, testOperatorInfo, sink.sent[0].ECK.OperatorInfo)
+	assert.Contains(t, sink.sent[0].ECK.Stats, "kibanas")
+	assert.Equal(t, "basic", sink.sent[0].ECK.License["eck_license_level"])
+
+	require.NoError(t, client.Get(context.Background(), k8s.ExtractNamespacedName(&secret), &secret))
+	assert.NotContains(t, secret.Data, "telemetry.yml")
+}
@@ -67,6 +67,8 @@ func NewReporter(
 	managedNamespaces []string,
 	telemetryInterval time.Duration,
 	tracer *apm.Tracer,
+	reportToKibana bool,
+	sinks []Sink,
 ) Reporter {
 	if len(managedNamespaces) == 0 {
 		// treat no managed namespaces as managing all namespaces, ie. set empty string for namespace filtering
@@ -80,6 +82,8 @@ func NewReporter(
 		managedNamespaces: managedNamespaces,
 		telemetryInterval: telemetryInterval,
 		tracer:            tracer,
+		kibanaDisabled:    !reportToKibana,
+		sinks:             sinks,
 	}
 }
 
@@ -90,6 +94,10 @@ type Reporter struct {
 	managedNamespaces []string
 	telemetryInterval time.Duration
 	tracer            *apm.Tracer
+	// kibanaDisabled prevents the telemetry data from being written to the Kibana telemetry Secrets.
+	kibanaDisabled bool
+	// sinks are the user-owned destinations the telemetry data is exported to.
+	sinks []Sink
 }
 
 func (r *Reporter) Start(ctx context.Context) {
@@ -104,13 +112,17 @@ func marshalTelemetry(ctx context.Context, info about.OperatorInfo, stats map[st
 	span, _ := apm.StartSpan(ctx, "marshal_telemetry", tracing.SpanTypeApp)
 	defer span.End()
 
-	return yaml.Marshal(ECKTelemetry{
+	return yaml.Marshal(newECKTelemetry(info, stats, license))
+}
+
+func newECKTelemetry(info about.OperatorInfo, stats map[string]any, license map[string]string) ECKTelemetry {
+	return ECKTelemetry{
 		ECK: ECK{
 			OperatorInfo: info,
 			Stats:        stats,
 			License:      license,
 		},
-	})
+	}
 }
 
 func (r *Reporter) getResourceStats(ctx context.Context) (map[string]any, error) {
@@ -158,6 +170,17 @@ func (r *Reporter) report(ctx context.Context) {
 		// it's ok to go on
 	}
 
+	now := time.Now()
+	for _, sink := range r.sinks {
+		if err := sink.Send(ctx, now, newECKTelemetry(r.operatorInfo, stats, licenseInfo)); err != nil {
+			log.Error(err, "failed to send telemetry data", "sink", sink.Name())
+		}
+	}
+
+	if r.kibanaDisabled {
+		return
+	}
+
 	telemetryBytes, err := marshalTelemetry(ctx, r.operatorInfo, stats, licenseInfo)
 	if err != nil {
 		log.Error(err, "failed to marshal telemetry data")
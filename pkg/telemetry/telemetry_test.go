@@ -446,7 +446,7 @@ func TestNewReporter(t *testing.T) {
 	)
 
 	// We only want the reporter to handle the managed namespaces, in this test only ns1 and ns2 are managed.
-	r := NewReporter(testOperatorInfo, client, "elastic-system", []string{kb1.Namespace, kb2.Namespace}, 1*time.Hour, nil)
+	r := NewReporter(testOperatorInfo, client, "elastic-system", []string{kb1.Namespace, kb2.Namespace}, 1*time.Hour, nil, true, nil)
 	r.report(context.Background())
 
 	wantData := map[string][]byte{
@@ -18,7 +18,12 @@ const (
 	licensingSubsystem = "licensing"
 
 	LicenseLevelLabel      = "license_level"
+	LicensePoolLabel       = "license_pool"
+	LicenseTypeLabel       = "license_type"
+	NameLabel              = "name"
+	NamespaceLabel         = "namespace"
 	OperatorNamespaceLabel = "operator_namespace"
+	ShardLabel             = "shard"
 	UUIDLabel              = "uuid"
 )
 
@@ -29,6 +34,14 @@ var (
 		Help:      "Gauge used to evaluate if an instance is elected",
 	}, []string{UUIDLabel, OperatorNamespaceLabel}))
 
+	// ShardOwnedGauge reports whether each shard of the resources is owned by this operator replica.
+	ShardOwnedGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "sharding",
+		Name:      "shard_owned",
+		Help:      "Whether the shard is owned by this operator replica",
+	}, []string{ShardLabel}))
+
 	// LicensingMaxERUGauge reports the maximum allowed enterprise resource units for licensing purposes.
 	LicensingMaxERUGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
@@ -92,6 +105,40 @@ var (
 		Name:      "memory_gibibytes_logstash",
 		Help:      "Memory used by Logstash in GiB",
 	}, []string{LicenseLevelLabel}))
+
+	// LicensingPoolMaxERUGauge reports the maximum allowed enterprise resource units of each license pool.
+	LicensingPoolMaxERUGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "pool_enterprise_resource_units_max",
+		Help:      "Maximum number of enterprise resource units available in the license pool",
+	}, []string{LicensePoolLabel}))
+
+	// LicensingPoolTotalERUGauge reports the enterprise resource units usage of each license pool.
+	LicensingPoolTotalERUGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "pool_enterprise_resource_units_total",
+		Help:      "Total enterprise resource units used by the resources of the license pool",
+	}, []string{LicensePoolLabel}))
+
+	// LicensingPoolTotalMemoryGauge reports the memory usage of each license pool.
+	LicensingPoolTotalMemoryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "pool_memory_gibibytes_total",
+		Help:      "Total memory used by the resources of the license pool in GiB",
+	}, []string{LicensePoolLabel}))
+)
+
+var (
+	// LicenseExpiryGauge reports the expiry time of the license applied to each Elasticsearch cluster.
+	LicenseExpiryGauge = registerGauge(prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: licensingSubsystem,
+		Name:      "elasticsearch_license_expiry_timestamp_seconds",
+		Help:      "Expiry time of the license applied to the Elasticsearch cluster, in seconds since the Unix epoch",
+	}, []string{NamespaceLabel, NameLabel, LicenseTypeLabel}))
 )
 
 func registerGauge(gauge *prometheus.GaugeVec) *prometheus.GaugeVec {
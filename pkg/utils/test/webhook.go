@@ -101,7 +101,7 @@ func RunValidationWebhookTests(t *testing.T, gvk metav1.GroupVersionKind, valida
 	controllerscheme.SetupScheme()
 	decoder := serializer.NewCodecFactory(clientgoscheme.Scheme).UniversalDeserializer()
 
-	webhook := webhook.ValidatingWebhookFor(clientgoscheme.Scheme, validator, license.MockLicenseChecker{}, nil)
+	webhook := webhook.ValidatingWebhookFor(clientgoscheme.Scheme, validator, license.MockLicenseChecker{}, nil, nil)
 
 	server := httptest.NewServer(webhook)
 	defer server.Close()
@@ -14,12 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/auditbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/filebeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/heartbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/journalbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/metricbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/packetbeat"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/stackmon/validations"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/test/e2e/test"
@@ -39,10 +34,10 @@ func TestFilebeatDefaultConfig(t *testing.T) {
 	fbBuilder := beat.NewBuilder(name).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithESValidations(
-			beat.HasEventFromBeat(filebeat.Type),
+			beat.HasEventFromBeat(beattype.Filebeat),
 			beat.HasEventFromPod(testPodBuilder.Pod.Name),
 			beat.HasMessageContaining(testPodBuilder.Logged))
 
@@ -88,12 +83,12 @@ func TestMetricbeatDefaultConfig(t *testing.T) {
 			testPodBuilder := beat.NewPodBuilder(name)
 
 			mbBuilder := beat.NewBuilder(name).
-				WithType(metricbeat.Type).
+				WithType(beattype.Metricbeat).
 				WithRoles(beat.MetricbeatClusterRoleName, beat.AutodiscoverClusterRoleName).
 				WithOpenShiftRoles(test.UseSCCRole).
 				WithElasticsearchRef(esBuilder.Ref()).
 				WithESValidations(
-					beat.HasEventFromBeat(metricbeat.Type),
+					beat.HasEventFromBeat(beattype.Metricbeat),
 					beat.HasEvent("event.dataset:system.cpu"),
 					beat.HasEvent("event.dataset:system.load"),
 					beat.HasEvent("event.dataset:system.memory"),
@@ -122,12 +117,12 @@ func TestHeartbeatConfig(t *testing.T) {
 		WithESMasterDataNodes(3, elasticsearch.DefaultResources)
 
 	hbBuilder := beat.NewBuilder(name).
-		WithType(heartbeat.Type).
+		WithType(beattype.Heartbeat).
 		WithDeployment().
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithESValidations(
-			beat.HasEventFromBeat(heartbeat.Type),
+			beat.HasEventFromBeat(beattype.Heartbeat),
 			beat.HasEvent("monitor.status:up"))
 
 	configYaml := fmt.Sprintf(e2eHeartBeatConfigTpl, v1.HTTPService(esBuilder.Elasticsearch.Name), esBuilder.Elasticsearch.Namespace)
@@ -158,14 +153,14 @@ func TestBeatSecureSettings(t *testing.T) {
 	}
 
 	fbBuilder := beat.NewBuilder(name).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithSecureSettings(secretName).
 		WithObjects(secret).
 		WithESValidations(
-			beat.HasEventFromBeat(filebeat.Type),
+			beat.HasEventFromBeat(beattype.Filebeat),
 			beat.HasEventFromPod(testPodBuilder.Pod.Name),
 			beat.HasMessageContaining(testPodBuilder.Logged),
 			beat.HasEvent("agent.name:"+agentName),
@@ -293,14 +288,14 @@ processors:
 	}
 
 	fbBuilder := beat.NewBuilder(name).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithConfigRef(secretName).
 		WithObjects(secret).
 		WithESValidations(
-			beat.HasEventFromBeat(filebeat.Type),
+			beat.HasEventFromBeat(beattype.Filebeat),
 			beat.HasEvent("agent.name:"+agentName),
 		)
 
@@ -326,13 +321,13 @@ func TestAuditbeatConfig(t *testing.T) {
 		WithNodeCount(1)
 
 	abBuilder := beat.NewBuilder(name).
-		WithType(auditbeat.Type).
+		WithType(beattype.Auditbeat).
 		WithKibanaRef(kbBuilder.Ref()).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithESValidations(
-			beat.HasEventFromBeat(auditbeat.Type),
+			beat.HasEventFromBeat(beattype.Auditbeat),
 			beat.HasEvent("event.dataset:file"),
 			beat.HasEvent("event.module:file_integrity"),
 		)
@@ -353,13 +348,13 @@ func TestPacketbeatConfig(t *testing.T) {
 		WithNodeCount(1)
 
 	pbBuilder := beat.NewBuilder(name).
-		WithType(packetbeat.Type).
+		WithType(beattype.Packetbeat).
 		WithKibanaRef(kbBuilder.Ref()).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithESValidations(
-			beat.HasEventFromBeat(packetbeat.Type),
+			beat.HasEventFromBeat(beattype.Packetbeat),
 			beat.HasEvent("event.dataset:flow"),
 			beat.HasEvent("event.dataset:dns"),
 		)
@@ -386,12 +381,12 @@ func TestJournalbeatConfig(t *testing.T) {
 		WithESMasterDataNodes(3, elasticsearch.DefaultResources)
 
 	jbBuilder := beat.NewBuilder(name).
-		WithType(journalbeat.Type).
+		WithType(beattype.Journalbeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
 		WithESValidations(
-			beat.HasEventFromBeat(journalbeat.Type),
+			beat.HasEventFromBeat(beattype.Journalbeat),
 		)
 
 	jbBuilder = beat.ApplyYamls(t, jbBuilder, e2eJournalbeatConfig, e2eJournalbeatPodTemplate)
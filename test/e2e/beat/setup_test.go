@@ -13,9 +13,7 @@ import (
 	"testing"
 
 	esv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/elasticsearch/v1"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/filebeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/heartbeat"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/metricbeat"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/test/e2e/test"
 	"github.com/elastic/cloud-on-k8s/v3/test/e2e/test/beat"
@@ -45,7 +43,7 @@ func TestBeatKibanaRefWithTLSDisabled(t *testing.T) {
 		WithTLSDisabled(true)
 
 	fbBuilder := beat.NewBuilder(name).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
@@ -83,7 +81,7 @@ func TestBeatKibanaRef(t *testing.T) {
 		WithElasticsearchRef(esBuilder.Ref())
 
 	fbBuilder := beat.NewBuilder(name).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
@@ -101,7 +99,7 @@ func TestBeatKibanaRef(t *testing.T) {
 	fbBuilder = beat.ApplyYamls(t, fbBuilder, fileBeatConfig, E2EFilebeatPodTemplate)
 
 	mbBuilder := beat.NewBuilder(name).
-		WithType(metricbeat.Type).
+		WithType(beattype.Metricbeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithElasticsearchRef(esBuilder.Ref()).
@@ -111,7 +109,7 @@ func TestBeatKibanaRef(t *testing.T) {
 	mbBuilder = beat.ApplyYamls(t, mbBuilder, e2eMetricbeatConfig, e2eMetricbeatPodTemplate)
 
 	hbBuilder := beat.NewBuilder(name).
-		WithType(heartbeat.Type).
+		WithType(beattype.Heartbeat).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithDeployment().
 		WithElasticsearchRef(esBuilder.Ref())
@@ -13,8 +13,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	beatcommon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/filebeat"
 	"github.com/elastic/cloud-on-k8s/v3/test/e2e/test"
 	"github.com/elastic/cloud-on-k8s/v3/test/e2e/test/beat"
 	"github.com/elastic/cloud-on-k8s/v3/test/e2e/test/elasticsearch"
@@ -38,7 +38,7 @@ func TestBeatVersionUpgradeToLatest7x(t *testing.T) {
 	fbBuilder := beat.NewBuilder(name).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithDeploymentStrategy(appsv1.DeploymentStrategy{
 			Type: appsv1.RecreateDeploymentStrategyType,
 		}).
@@ -78,7 +78,7 @@ func TestVersionUpgradeToLatest8x(t *testing.T) {
 	fbBuilder := beat.NewBuilder(name).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithDeploymentStrategy(appsv1.DeploymentStrategy{
 			Type: appsv1.RecreateDeploymentStrategyType,
 		}).
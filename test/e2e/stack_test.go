@@ -20,7 +20,7 @@ import (
 	entv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/enterprisesearch/v1"
 	kbv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/kibana/v1"
 	logstashv1alpha1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/logstash/v1alpha1"
-	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/filebeat"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/utils/k8s"
 	beattests "github.com/elastic/cloud-on-k8s/v3/test/e2e/beat"
@@ -64,7 +64,7 @@ func initialBuildersToUpgrade(t *testing.T, initialVersion string) ([]test.Build
 		WithElasticsearchRef(esRef).
 		WithRestrictedSecurityContext()
 	fb := beat.NewBuilder("fb").
-		WithType(filebeat.Type).
+		WithType(beattype.Filebeat).
 		WithRoles(beat.AutodiscoverClusterRoleName).
 		WithOpenShiftRoles(test.UseSCCRole).
 		WithVersion(initialVersion).
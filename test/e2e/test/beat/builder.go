@@ -20,7 +20,7 @@ import (
 
 	beatv1beta1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/beat/v1beta1"
 	commonv1 "github.com/elastic/cloud-on-k8s/v3/pkg/apis/common/v1"
-	beatcommon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/settings"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/common/version"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
@@ -96,7 +96,7 @@ func newBuilder(name string, suffix string) Builder {
 
 type ValidationFunc func(client.Client) error
 
-func (b Builder) WithType(typ beatcommon.Type) Builder {
+func (b Builder) WithType(typ beattype.Type) Builder {
 	typeStr := string(typ)
 	b.Beat.Spec.Type = typeStr
 	b.Beat.Spec.Version = test.Ctx().ElasticStackVersion
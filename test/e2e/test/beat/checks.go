@@ -11,11 +11,11 @@ import (
 	"io"
 	"net/http"
 
-	beatcommon "github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/common"
+	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/beat/beattype"
 	"github.com/elastic/cloud-on-k8s/v3/pkg/controller/elasticsearch/client"
 )
 
-func HasEventFromBeat(name beatcommon.Type) ValidationFunc {
+func HasEventFromBeat(name beattype.Type) ValidationFunc {
 	return HasEvent(fmt.Sprintf("agent.type:%s", name))
 }
 